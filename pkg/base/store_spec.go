@@ -185,6 +185,10 @@ type StoreSpec struct {
 	// ExtraOptions is a serialized protobuf set by Go CCL code and passed through
 	// to C CCL code.
 	ExtraOptions []byte
+	// RaftLogPath, if set, is the directory of a dedicated storage engine that
+	// holds the store's Raft log and HardState, keeping log writes and
+	// truncations out of the engine that holds the state machine.
+	RaftLogPath string
 }
 
 // String returns a fully parsable version of the store spec.
@@ -212,6 +216,9 @@ func (ss StoreSpec) String() string {
 		}
 		fmt.Fprintf(&buffer, ",")
 	}
+	if len(ss.RaftLogPath) != 0 {
+		fmt.Fprintf(&buffer, "raft-log-path=%s,", ss.RaftLogPath)
+	}
 	if len(ss.PebbleOptions) > 0 {
 		optsStr := strings.Replace(ss.PebbleOptions, "\n", " ", -1)
 		fmt.Fprint(&buffer, "pebble=")
@@ -255,6 +262,8 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 //   - 20%             -> 20% of the available space
 //   - 0.2             -> 20% of the available space
 // - attrs=xxx:yyy:zzz A colon separated list of optional attributes.
+// - raft-log-path=xxx The optional directory of a separate storage engine
+//   holding the store's Raft log, possibly on a different device.
 // Note that commas are forbidden within any field name or value.
 func NewStoreSpec(value string) (StoreSpec, error) {
 	const pathField = "path"
@@ -328,6 +337,12 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 			} else {
 				return StoreSpec{}, fmt.Errorf("%s is not a valid store type", value)
 			}
+		case "raft-log-path":
+			var err error
+			ss.RaftLogPath, err = GetAbsoluteStorePath("raft-log-path", value)
+			if err != nil {
+				return StoreSpec{}, err
+			}
 		case "rocksdb":
 			ss.RocksDBOptions = value
 		case "pebble":
@@ -375,11 +390,16 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 		if ss.Path != "" {
 			return StoreSpec{}, fmt.Errorf("path specified for in memory store")
 		}
+		if ss.RaftLogPath != "" {
+			return StoreSpec{}, fmt.Errorf("raft-log-path specified for in memory store")
+		}
 		if ss.Size.Percent == 0 && ss.Size.InBytes == 0 {
 			return StoreSpec{}, fmt.Errorf("size must be specified for an in memory store")
 		}
 	} else if ss.Path == "" {
 		return StoreSpec{}, fmt.Errorf("no path specified")
+	} else if ss.RaftLogPath == ss.Path {
+		return StoreSpec{}, fmt.Errorf("raft-log-path must differ from the store path")
 	}
 	return ss, nil
 }
@@ -144,6 +144,12 @@ target_file_size=2097152`
 		{"path=/mnt/hda1,type=other", "other is not a valid store type", StoreSpec{}},
 		{"path=/mnt/hda1,type=mem,size=20GiB", "path specified for in memory store", StoreSpec{}},
 
+		// Raft log
+		{"path=/mnt/hda1,raft-log-path=/mnt/nvme1", "", StoreSpec{Path: "/mnt/hda1", RaftLogPath: "/mnt/nvme1"}},
+		{"path=/mnt/hda1,raft-log-path=/mnt/hda1", "raft-log-path must differ from the store path", StoreSpec{}},
+		{"path=/mnt/hda1,raft-log-path=", "no value specified for raft-log-path", StoreSpec{}},
+		{"type=mem,size=20GiB,raft-log-path=/mnt/nvme1", "raft-log-path specified for in memory store", StoreSpec{}},
+
 		// RocksDB
 		{"path=/,rocksdb=key1=val1;key2=val2", "", StoreSpec{Path: "/", RocksDBOptions: "key1=val1;key2=val2"}},
 
@@ -35,4 +35,5 @@ type TestingKnobs struct {
 	TenantTestingKnobs   ModuleTestingKnobs
 	JobsTestingKnobs     ModuleTestingKnobs
 	BackupRestore        ModuleTestingKnobs
+	TTL                  ModuleTestingKnobs
 }
@@ -76,12 +76,19 @@ type avroLogicalType struct {
 	Scale       int            `json:"scale,omitempty"`
 }
 
+type avroArrayType struct {
+	SchemaType string         `json:"type"`
+	Items      avroSchemaType `json:"items"`
+}
+
 func avroUnionKey(t avroSchemaType) string {
 	switch s := t.(type) {
 	case string:
 		return s
 	case avroLogicalType:
 		return avroUnionKey(s.SchemaType) + `.` + s.LogicalType
+	case avroArrayType:
+		return s.SchemaType
 	case *avroRecord:
 		return s.Name
 	default:
@@ -347,6 +354,53 @@ func columnDescToAvroSchema(colDesc *descpb.ColumnDescriptor) (*avroSchemaField,
 		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
 			return tree.ParseDJSON(x.(string))
 		}
+	case types.EnumFamily:
+		// Enums are encoded as strings rather than avro enums, so that adding
+		// a value to the type doesn't require a new schema.
+		avroType = avroSchemaString
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DEnum).LogicalRep, nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			return tree.MakeDEnumFromLogicalRepresentation(colDesc.Type, x.(string))
+		}
+	case types.ArrayFamily:
+		// The elements of the array are optional, like the fields of a record,
+		// because SQL arrays may contain NULLs.
+		elemColDesc := *colDesc
+		elemColDesc.Type = colDesc.Type.ArrayContents()
+		elemSchema, err := columnDescToAvroSchema(&elemColDesc)
+		if err != nil {
+			return nil, err
+		}
+		avroType = avroArrayType{
+			SchemaType: `array`,
+			Items:      elemSchema.SchemaType,
+		}
+		schema.encodeFn = func(d tree.Datum) (interface{}, error) {
+			elems := d.(*tree.DArray).Array
+			native := make([]interface{}, len(elems))
+			for i, elem := range elems {
+				var err error
+				if native[i], err = elemSchema.encodeFn(elem); err != nil {
+					return nil, err
+				}
+			}
+			return native, nil
+		}
+		schema.decodeFn = func(x interface{}) (tree.Datum, error) {
+			arr := tree.NewDArray(elemColDesc.Type)
+			for _, native := range x.([]interface{}) {
+				elem, err := elemSchema.decodeFn(native)
+				if err != nil {
+					return nil, err
+				}
+				if err := arr.Append(elem); err != nil {
+					return nil, err
+				}
+			}
+			return arr, nil
+		}
 	default:
 		return nil, errors.Errorf(`column %s: type %s not yet supported with avro`,
 			colDesc.Name, colDesc.Type.SQLString())
@@ -62,6 +62,7 @@ func kvsToRows(
 	leaseMgr *lease.Manager,
 	hydratedTables *hydratedtables.Cache,
 	details jobspb.ChangefeedDetails,
+	filter *rowFilter,
 	inputFn func(context.Context) (kvfeed.Event, error),
 ) func(context.Context) ([]emitEntry, error) {
 	_, withDiff := details.Opts[changefeedbase.OptDiff]
@@ -178,6 +179,14 @@ func kvsToRows(
 			}
 		}
 
+		if filter != nil {
+			if emit, err := filter.apply(ctx, &r.row); err != nil {
+				return nil, err
+			} else if !emit {
+				return output, nil
+			}
+		}
+
 		output = append(output, r)
 		return output, nil
 	}
@@ -159,6 +159,12 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 		return ctx
 	}
 
+	filter, err := makeRowFilter(ca.spec.Feed, ca.flowCtx.NewEvalCtx())
+	if err != nil {
+		ca.MoveToDraining(err)
+		return ctx
+	}
+
 	if ca.sink, err = getSink(
 		ctx, ca.spec.Feed.SinkURI, nodeID, ca.spec.Feed.Opts, ca.spec.Feed.Targets,
 		ca.flowCtx.Cfg.Settings, timestampOracle, ca.flowCtx.Cfg.ExternalStorageFromURI, ca.spec.User,
@@ -206,7 +212,8 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 	kvfeedCfg := makeKVFeedCfg(ca.flowCtx.Cfg, leaseMgr, ca.kvFeedMemMon, ca.spec,
 		spans, withDiff, buf, metrics)
 	cfg := ca.flowCtx.Cfg
-	rowsFn := kvsToRows(ctx, cfg.Codec, cfg.Settings, cfg.DB, leaseMgr, cfg.HydratedTables, ca.spec.Feed, buf.Get)
+	rowsFn := kvsToRows(ctx, cfg.Codec, cfg.Settings, cfg.DB, leaseMgr, cfg.HydratedTables, ca.spec.Feed,
+		filter, buf.Get)
 	ca.tickFn = emitEntries(ca.flowCtx.Cfg.Settings, ca.spec.Feed,
 		kvfeedCfg.InitialHighWater, sf, ca.encoder, ca.sink, rowsFn, knobs, metrics)
 	ca.startKVFeed(ctx, kvfeedCfg)
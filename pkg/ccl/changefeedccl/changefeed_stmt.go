@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -40,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
@@ -197,6 +199,18 @@ func changefeedPlanHook(
 			SinkURI:       sinkURI,
 			StatementTime: statementTime,
 		}
+		if len(changefeedStmt.Columns) > 0 || changefeedStmt.Where != nil {
+			if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionChangefeedProjections) {
+				return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+					`CHANGEFEEDs with a column list or a WHERE clause require all nodes to be upgraded to %s`,
+					clusterversion.VersionByKey(clusterversion.VersionChangefeedProjections))
+			}
+			if err := resolveChangefeedColumnsAndFilter(
+				ctx, p, changefeedStmt, targetDescs, &details,
+			); err != nil {
+				return err
+			}
+		}
 		progress := jobspb.Progress{
 			Progress: &jobspb.Progress_HighWater{},
 			Details: &jobspb.Progress_Changefeed{
@@ -212,7 +226,7 @@ func changefeedPlanHook(
 		//   and `format` if the user didn't specify them.
 		// - Then `getEncoder` is run to return any configuration errors.
 		// - Then the changefeed is opted in to `OptKeyInValue` for any cloud
-		//   storage sink. Kafka etc have a key and value field in each message but
+		//   storage sink, except with the csv format. Kafka etc have a key and value field in each message but
 		//   cloud storage sinks don't have anywhere to put the key. So if the key
 		//   is not in the value, then for DELETEs there is no way to recover which
 		//   key was deleted. We could make the user explicitly pass this option for
@@ -233,6 +247,13 @@ func changefeedPlanHook(
 		if err != nil {
 			return err
 		}
+		if parsedSink.Scheme == changefeedbase.SinkSchemeWebhookHTTPS &&
+			!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionChangefeedWebhookSink) {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				`%s sinks require all nodes to be upgraded to %s`,
+				changefeedbase.SinkSchemeWebhookHTTPS,
+				clusterversion.VersionByKey(clusterversion.VersionChangefeedWebhookSink))
+		}
 		if details, err = validateDetails(details); err != nil {
 			return err
 		}
@@ -240,7 +261,15 @@ func changefeedPlanHook(
 		if _, err := getEncoder(details.Opts); err != nil {
 			return err
 		}
-		if isCloudStorageSink(parsedSink) {
+		if err := checkChangefeedFormatVersion(
+			ctx, p.ExecCfg().Settings, details.Opts, targetDescs,
+		); err != nil {
+			return err
+		}
+		// CSV records contain the whole row, and so its key, but deletions have
+		// no record at all in that format.
+		if isCloudStorageSink(parsedSink) &&
+			changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) != changefeedbase.OptFormatCSV {
 			details.Opts[changefeedbase.OptKeyInValue] = ``
 		}
 
@@ -252,6 +281,12 @@ func changefeedPlanHook(
 		telemetry.Count(`changefeed.create.sink.` + telemetrySink)
 		telemetry.Count(`changefeed.create.format.` + details.Opts[changefeedbase.OptFormat])
 		telemetry.CountBucketed(`changefeed.create.num_tables`, int64(len(targets)))
+		if len(details.ProjectionColumns) > 0 {
+			telemetry.Count(`changefeed.create.projection`)
+		}
+		if details.Filter != `` {
+			telemetry.Count(`changefeed.create.filter`)
+		}
 
 		if details.SinkURI == `` {
 			telemetry.Count(`changefeed.create.core`)
@@ -400,6 +435,8 @@ func changefeedJobDescription(
 	}
 	c := &tree.CreateChangefeed{
 		Targets: changefeed.Targets,
+		Columns: changefeed.Columns,
+		Where:   changefeed.Where,
 		SinkURI: tree.NewDString(cleanedSinkURI),
 	}
 	for k, v := range opts {
@@ -474,7 +511,15 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeRow)
 		case changefeedbase.OptEnvelopeKeyOnly:
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeKeyOnly)
-		case ``, changefeedbase.OptEnvelopeWrapped:
+		case ``:
+			// CSV records cannot nest the before and after values of a row, so
+			// that format defaults to the row envelope instead.
+			if changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) == changefeedbase.OptFormatCSV {
+				details.Opts[opt] = string(changefeedbase.OptEnvelopeRow)
+			} else {
+				details.Opts[opt] = string(changefeedbase.OptEnvelopeWrapped)
+			}
+		case changefeedbase.OptEnvelopeWrapped:
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeWrapped)
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
@@ -486,7 +531,9 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		switch v := changefeedbase.FormatType(details.Opts[opt]); v {
 		case ``, changefeedbase.OptFormatJSON:
 			details.Opts[opt] = string(changefeedbase.OptFormatJSON)
-		case changefeedbase.OptFormatAvro:
+		case changefeedbase.OptFormatAvro, changefeedbase.OptFormatDeprecatedAvro:
+			details.Opts[opt] = string(changefeedbase.OptFormatAvro)
+		case changefeedbase.OptFormatCSV, changefeedbase.OptFormatProtobuf:
 			// No-op.
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
@@ -496,6 +543,50 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 	return details, nil
 }
 
+// checkChangefeedFormatVersion makes sure that the change aggregators of nodes
+// which are not yet at VersionChangefeedFormats can encode the changefeed.
+// Those nodes only know the avro format by its experimental name, so the
+// format is persisted under that name, and they fail on the other formats and
+// on the avro features added with them, which are rejected.
+func checkChangefeedFormatVersion(
+	ctx context.Context, st *cluster.Settings, opts map[string]string, targetDescs []catalog.Descriptor,
+) error {
+	if st.Version.IsActive(ctx, clusterversion.VersionChangefeedFormats) {
+		return nil
+	}
+	notSupportedErr := func(feature string) error {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			`%s requires all nodes to be upgraded to %s`,
+			feature, clusterversion.VersionByKey(clusterversion.VersionChangefeedFormats))
+	}
+
+	format := changefeedbase.FormatType(opts[changefeedbase.OptFormat])
+	switch format {
+	case changefeedbase.OptFormatAvro:
+		if changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeRow {
+			return notSupportedErr(fmt.Sprintf(`%s=%s with %s=%s`,
+				changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeRow, changefeedbase.OptFormat, format))
+		}
+		for _, desc := range targetDescs {
+			table, isTable := desc.(catalog.TableDescriptor)
+			if !isTable {
+				continue
+			}
+			for _, col := range table.GetPublicColumns() {
+				switch col.Type.Family() {
+				case types.EnumFamily, types.ArrayFamily:
+					return notSupportedErr(fmt.Sprintf(`%s=%s with column %s of type %s`,
+						changefeedbase.OptFormat, format, col.Name, col.Type.SQLString()))
+				}
+			}
+		}
+		opts[changefeedbase.OptFormat] = string(changefeedbase.OptFormatDeprecatedAvro)
+	case changefeedbase.OptFormatCSV, changefeedbase.OptFormatProtobuf:
+		return notSupportedErr(fmt.Sprintf(`%s=%s`, changefeedbase.OptFormat, format))
+	}
+	return nil
+}
+
 func validateChangefeedTable(
 	targets jobspb.ChangefeedTargets, tableDesc catalog.TableDescriptor,
 ) error {
@@ -542,6 +633,104 @@ func validateChangefeedTable(
 	return nil
 }
 
+// resolveChangefeedColumnsAndFilter resolves the column list and the WHERE
+// clause of a changefeed against the table it watches. The columns are recorded
+// by ID, so that they are unaffected by later renames.
+func resolveChangefeedColumnsAndFilter(
+	ctx context.Context,
+	p sql.PlanHookState,
+	changefeedStmt *tree.CreateChangefeed,
+	targetDescs []catalog.Descriptor,
+	details *jobspb.ChangefeedDetails,
+) error {
+	var table catalog.TableDescriptor
+	for _, desc := range targetDescs {
+		if t, isTable := desc.(catalog.TableDescriptor); isTable {
+			if table != nil {
+				table = nil
+				break
+			}
+			table = t
+		}
+	}
+	if table == nil {
+		return errors.Errorf(
+			`CHANGEFEEDs with a column list or a WHERE clause must watch exactly one table`)
+	}
+	publicColIdxs := table.ColumnIdxMap()
+
+	if len(changefeedStmt.Columns) > 0 {
+		details.ProjectionColumns = make(map[string]descpb.ColumnID, len(changefeedStmt.Columns))
+		for _, name := range changefeedStmt.Columns {
+			col, _, err := table.FindColumnByName(name)
+			if err != nil {
+				return err
+			}
+			if _, ok := publicColIdxs[col.ID]; !ok {
+				return colinfo.NewUndefinedColumnError(string(name))
+			}
+			if _, ok := details.ProjectionColumns[col.Name]; ok {
+				return pgerror.Newf(pgcode.DuplicateColumn,
+					"column %q specified more than once", tree.ErrString(&name))
+			}
+			details.ProjectionColumns[col.Name] = col.ID
+		}
+	}
+
+	if changefeedStmt.Where != nil {
+		expr := changefeedStmt.Where.Expr
+		// The WHERE clause is evaluated by the change aggregators, which can't
+		// resolve user defined type names.
+		if _, err := tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+			var typ tree.ResolvableTypeReference
+			switch t := expr.(type) {
+			case *tree.CastExpr:
+				typ = t.Type
+			case *tree.AnnotateTypeExpr:
+				typ = t.Type
+			default:
+				return true, expr, nil
+			}
+			if _, ok := typ.(*types.T); !ok {
+				return false, nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					`user defined types are not supported in the WHERE clause of a CHANGEFEED: %s`,
+					tree.ErrString(expr))
+			}
+			return true, expr, nil
+		}); err != nil {
+			return err
+		}
+
+		// The expression must be deterministic, since it's evaluated once per
+		// change on whichever node happens to be watching the row.
+		tn := tree.MakeUnqualifiedTableName(tree.Name(table.GetName()))
+		_, colIDs, err := schemaexpr.DequalifyAndValidateExpr(
+			ctx, table, expr, types.Bool, `CHANGEFEED WHERE`, p.SemaCtx(), tree.VolatilityImmutable, &tn,
+		)
+		if err != nil {
+			return err
+		}
+		sourceInfo := colinfo.NewSourceInfoForSingleTable(
+			tn, colinfo.ResultColumnsFromColDescs(table.GetID(), table.GetPublicColumns()),
+		)
+		if details.Filter, err = schemaexpr.DequalifyColumnRefs(ctx, sourceInfo, expr); err != nil {
+			return err
+		}
+		details.FilterColumns = make(map[string]descpb.ColumnID, colIDs.Len())
+		for _, id := range colIDs.Ordered() {
+			col, err := table.FindColumnByID(id)
+			if err != nil {
+				return err
+			}
+			if _, ok := publicColIdxs[id]; !ok {
+				return colinfo.NewUndefinedColumnError(col.Name)
+			}
+			details.FilterColumns[col.Name] = id
+		}
+	}
+	return nil
+}
+
 type changefeedResumer struct {
 	job *jobs.Job
 }
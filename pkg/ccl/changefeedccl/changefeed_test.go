@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -156,6 +157,140 @@ func TestChangefeedDiff(t *testing.T) {
 	t.Run(`cloudstorage`, cloudStorageTest(testFn))
 }
 
+func TestChangefeedColumnsAndWhere(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a', 10), (2, 'b', 20)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo (b) WHERE c > 15`)
+		defer closeFeed(t, foo)
+		fooDiff := feed(t, f, `CREATE CHANGEFEED FOR foo (b) WHERE c > 15 WITH diff`)
+		defer closeFeed(t, fooDiff)
+
+		// The primary key is always emitted, and the WHERE clause may reference
+		// columns that aren't.
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+		})
+		assertPayloads(t, fooDiff, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "b"}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `UPDATE foo SET c = 30 WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+		})
+		assertPayloads(t, fooDiff, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "before": {"a": 1, "b": "a"}}`,
+		})
+
+		// A row which stops matching is only known to have matched before with
+		// the diff option, in which case it's emitted as a deletion.
+		sqlDB.Exec(t, `UPDATE foo SET c = 5 WHERE a = 2`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": null}`,
+			`foo: [2]->{"after": null}`,
+		})
+		assertPayloads(t, fooDiff, []string{
+			`foo: [1]->{"after": null, "before": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": null, "before": {"a": 2, "b": "b"}}`,
+		})
+
+		// Referenced columns are tracked by ID, so renames are fine.
+		sqlDB.Exec(t, `ALTER TABLE foo RENAME COLUMN c TO d`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'c', 30), (4, 'd', 4)`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+		})
+		assertPayloads(t, fooDiff, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "c"}, "before": null}`,
+		})
+
+		sqlDB.Exec(t, `ALTER TABLE foo DROP COLUMN d`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (5, 'e')`)
+		const dropped = `column "c" in the WHERE clause of the changefeed was dropped from foo`
+		if _, err := foo.Next(); !testutils.IsError(err, dropped) {
+			t.Errorf(`expected %q error got: %+v`, dropped, err)
+		}
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedColumnsAndWhereVersionGate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				DisableAutomaticVersionUpgrade: 1,
+				BinaryVersionOverride: clusterversion.VersionByKey(
+					clusterversion.VersionChangefeedProjections - 1),
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+
+	// Older change aggregators would ignore the column list and the WHERE
+	// clause and emit every column of every row.
+	sqlDB.ExpectErr(
+		t, `CHANGEFEEDs with a column list or a WHERE clause require all nodes to be upgraded`,
+		`EXPERIMENTAL CHANGEFEED FOR foo (b)`,
+	)
+	sqlDB.ExpectErr(
+		t, `CHANGEFEEDs with a column list or a WHERE clause require all nodes to be upgraded`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WHERE b = 'a'`,
+	)
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING version = crdb_internal.node_executable_version()`)
+	defer utilccl.TestingEnableEnterprise()()
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	var jobID int64
+	sqlDB.QueryRow(t,
+		`CREATE CHANGEFEED FOR foo (b) WHERE b = 'a' INTO 'experimental-sql://d/'`,
+	).Scan(&jobID)
+	sqlDB.Exec(t, `CANCEL JOB $1`, jobID)
+}
+
+func TestChangefeedWebhookSinkVersionGate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				DisableAutomaticVersionUpgrade: 1,
+				BinaryVersionOverride: clusterversion.VersionByKey(
+					clusterversion.VersionChangefeedWebhookSink - 1),
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	defer utilccl.TestingEnableEnterprise()()
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+
+	// Older change aggregators would fail to create the sink.
+	sqlDB.ExpectErr(
+		t, `webhook-https sinks require all nodes to be upgraded`,
+		`CREATE CHANGEFEED FOR foo INTO 'webhook-https://localhost:1234/'`,
+	)
+}
+
 func TestChangefeedEnvelope(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1900,6 +2035,41 @@ func TestChangefeedErrors(t *testing.T) {
 		`EXPERIMENTAL CHANGEFEED FOR foo WITH cursor=$1`, timeutil.Now().Add(time.Hour),
 	)
 
+	sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+	sqlDB.ExpectErr(
+		t, `must watch exactly one table`,
+		`EXPERIMENTAL CHANGEFEED FOR foo, bar (a)`,
+	)
+	sqlDB.ExpectErr(
+		t, `column "nope" does not exist`,
+		`EXPERIMENTAL CHANGEFEED FOR foo (nope)`,
+	)
+	sqlDB.ExpectErr(
+		t, `column "b" specified more than once`,
+		`EXPERIMENTAL CHANGEFEED FOR foo (b, b)`,
+	)
+	sqlDB.ExpectErr(
+		t, `column "nope" does not exist`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WHERE nope > 1`,
+	)
+	sqlDB.ExpectErr(
+		t, `expected CHANGEFEED WHERE expression to have type bool, but 'a' has type int`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WHERE a`,
+	)
+	sqlDB.ExpectErr(
+		t, `context-dependent operators are not allowed in CHANGEFEED WHERE`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WHERE now() > '2000-01-01'`,
+	)
+	sqlDB.ExpectErr(
+		t, `volatile functions are not allowed in CHANGEFEED WHERE`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WHERE random() < 0.5`,
+	)
+	sqlDB.Exec(t, `CREATE TYPE status AS ENUM ('open', 'closed')`)
+	sqlDB.ExpectErr(
+		t, `user defined types are not supported in the WHERE clause of a CHANGEFEED`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WHERE b::status = 'open'`,
+	)
+
 	sqlDB.ExpectErr(
 		t, `omit the SINK clause`,
 		`CREATE CHANGEFEED FOR foo INTO ''`,
@@ -2052,17 +2222,30 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1`, `kafka://nope/?sasl_password=a`,
 	)
 
-	// The avro format doesn't support key_in_value yet.
+	// The avro format doesn't support key_in_value yet. The deprecated
+	// experimental_avro name of the format is still accepted.
 	sqlDB.ExpectErr(
-		t, `key_in_value is not supported with format=experimental_avro`,
+		t, `key_in_value is not supported with format=avro`,
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH key_in_value, format='experimental_avro'`,
 		`kafka://nope`,
 	)
 
+	// The csv format has no room for metadata.
+	sqlDB.ExpectErr(
+		t, `updated is not supported with format=csv`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH updated, format='csv'`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `envelope=wrapped is not supported with format=csv`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH envelope='wrapped', format='csv'`,
+		`kafka://nope`,
+	)
+
 	// The cloudStorageSink is particular about the options it will work with.
 	sqlDB.ExpectErr(
-		t, `this sink is incompatible with format=experimental_avro`,
-		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='experimental_avro', confluent_schema_registry=$2`,
+		t, `this sink is incompatible with format=avro`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='avro', confluent_schema_registry=$2`,
 		`experimental-nodelocal://0/bar`, `schemareg-nope`,
 	)
 	sqlDB.ExpectErr(
@@ -73,19 +73,27 @@ const (
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
 
-	OptFormatJSON FormatType = `json`
-	OptFormatAvro FormatType = `experimental_avro`
+	OptFormatJSON           FormatType = `json`
+	OptFormatAvro           FormatType = `avro`
+	OptFormatDeprecatedAvro FormatType = `experimental_avro`
+	OptFormatCSV            FormatType = `csv`
+	OptFormatProtobuf       FormatType = `protobuf`
 
+	SinkParamBatchSize        = `batch_size`
 	SinkParamCACert           = `ca_cert`
 	SinkParamClientCert       = `client_cert`
 	SinkParamClientKey        = `client_key`
 	SinkParamFileSize         = `file_size`
+	SinkParamFlushInterval    = `flush_interval`
+	SinkParamMaxRetries       = `max_retries`
 	SinkParamSchemaTopic      = `schema_topic`
+	SinkParamSkipTLSVerify    = `insecure_tls_skip_verify`
 	SinkParamTLSEnabled       = `tls_enabled`
 	SinkParamTopicPrefix      = `topic_prefix`
 	SinkSchemeBuffer          = ``
 	SinkSchemeExperimentalSQL = `experimental-sql`
 	SinkSchemeKafka           = `kafka`
+	SinkSchemeWebhookHTTPS    = `webhook-https`
 	SinkParamSASLEnabled      = `sasl_enabled`
 	SinkParamSASLHandshake    = `sasl_handshake`
 	SinkParamSASLUser         = `sasl_user`
@@ -24,12 +24,16 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 const (
@@ -37,6 +41,8 @@ const (
 	confluentSubjectSuffixKey    = `-key`
 	confluentSubjectSuffixValue  = `-value`
 	confluentAvroWireFormatMagic = byte(0)
+	confluentSchemaTypeAvro      = `AVRO`
+	confluentSchemaTypeProtobuf  = `PROTOBUF`
 )
 
 // encodeRow holds all the pieces necessary to encode a row change into a key or
@@ -86,8 +92,12 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
 	case ``, changefeedbase.OptFormatJSON:
 		return makeJSONEncoder(opts)
-	case changefeedbase.OptFormatAvro:
+	case changefeedbase.OptFormatAvro, changefeedbase.OptFormatDeprecatedAvro:
 		return newConfluentAvroEncoder(opts)
+	case changefeedbase.OptFormatCSV:
+		return makeCSVEncoder(opts)
+	case changefeedbase.OptFormatProtobuf:
+		return newConfluentProtobufEncoder(opts)
 	default:
 		return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
@@ -267,16 +277,144 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 	return gojson.Marshal(jsonEntries)
 }
 
+// csvEncoder encodes changefeed entries as CSV records. Keys are the primary
+// key columns and values are all columns, in the order of the table. NULLs are
+// encoded as empty fields. A CSV record cannot nest the before and after values
+// of a row, so only the `row` and `key_only` envelopes are supported and
+// deletions have no value.
+type csvEncoder struct {
+	keyOnly bool
+
+	alloc  rowenc.DatumAlloc
+	fmtCtx *tree.FmtCtx
+	record []string
+	buf    bytes.Buffer
+	writer *csv.Writer
+}
+
+var _ Encoder = &csvEncoder{}
+
+func makeCSVEncoder(opts map[string]string) (*csvEncoder, error) {
+	e := &csvEncoder{fmtCtx: tree.NewFmtCtx(tree.FmtExport)}
+	e.writer = csv.NewWriter(&e.buf)
+
+	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
+	case changefeedbase.OptEnvelopeKeyOnly:
+		e.keyOnly = true
+	case changefeedbase.OptEnvelopeRow:
+	default:
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatCSV)
+	}
+	for _, opt := range []string{
+		changefeedbase.OptUpdatedTimestamps, changefeedbase.OptDiff, changefeedbase.OptKeyInValue,
+	} {
+		if _, ok := opts[opt]; ok {
+			return nil, errors.Errorf(`%s is not supported with %s=%s`,
+				opt, changefeedbase.OptFormat, changefeedbase.OptFormatCSV)
+		}
+	}
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *csvEncoder) EncodeKey(_ context.Context, row encodeRow) ([]byte, error) {
+	colIdxByID := row.tableDesc.ColumnIdxMap()
+	e.record = e.record[:0]
+	for _, colID := range row.tableDesc.GetPrimaryIndex().ColumnIDs {
+		idx, ok := colIdxByID[colID]
+		if !ok {
+			return nil, errors.Errorf(`unknown column id: %d`, colID)
+		}
+		if err := e.appendField(row.datums[idx], row.tableDesc.GetColumnAtIdx(idx).Type); err != nil {
+			return nil, err
+		}
+	}
+	return e.encodeRecord()
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *csvEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, error) {
+	if e.keyOnly || row.deleted {
+		return nil, nil
+	}
+	columns := row.tableDesc.GetPublicColumns()
+	e.record = e.record[:0]
+	for i := range columns {
+		if err := e.appendField(row.datums[i], columns[i].Type); err != nil {
+			return nil, err
+		}
+	}
+	return e.encodeRecord()
+}
+
+func (e *csvEncoder) appendField(datum rowenc.EncDatum, typ *types.T) error {
+	if err := datum.EnsureDecoded(typ, &e.alloc); err != nil {
+		return err
+	}
+	if datum.Datum == tree.DNull {
+		e.record = append(e.record, ``)
+		return nil
+	}
+	e.fmtCtx.Reset()
+	datum.Datum.Format(e.fmtCtx)
+	e.record = append(e.record, e.fmtCtx.String())
+	return nil
+}
+
+// encodeRecord returns the pending fields as a CSV record. The record
+// delimiter is left to the sink.
+func (e *csvEncoder) encodeRecord() ([]byte, error) {
+	e.buf.Reset()
+	if err := e.writer.Write(e.record); err != nil {
+		return nil, err
+	}
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(e.buf.Bytes(), []byte{'\n'}), nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface. Resolved timestamps
+// aren't rows, so they are encoded as JSON like with the wrapped envelope of the
+// json format.
+func (e *csvEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return gojson.Marshal(map[string]interface{}{
+		`resolved`: tree.TimestampToDecimalDatum(resolved).Decimal.String(),
+	})
+}
+
 // confluentAvroEncoder encodes changefeed entries as Avro's binary or textual
 // JSON format. Keys are the primary key columns in a record. Values are all
-// columns in a record.
+// columns in a record, wrapped in an envelope record unless envelope=row.
 type confluentAvroEncoder struct {
-	registryURL                        string
-	updatedField, beforeField, keyOnly bool
+	registryURL                                 string
+	updatedField, beforeField, keyOnly, wrapped bool
 
-	keyCache      map[tableIDAndVersion]confluentRegisteredKeySchema
-	valueCache    map[tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
-	resolvedCache map[string]confluentRegisteredEnvelopeSchema
+	keyCache      *cache.UnorderedCache
+	rowCache      *cache.UnorderedCache
+	valueCache    *cache.UnorderedCache
+	resolvedCache *cache.UnorderedCache
+}
+
+// encoderCacheSize is the number of registered schemas kept by each cache of
+// the encoders using a schema registry. An evicted schema is registered again
+// when it is next needed, and the registry returns the ID it already assigned.
+const encoderCacheSize = 1024
+
+// newEncoderCache returns an LRU cache of the schemas registered by an
+// encoder, so that changefeeds over many tables or schema changes don't
+// accumulate them without bound.
+func newEncoderCache() *cache.UnorderedCache {
+	return cache.NewUnorderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(size int, _, _ interface{}) bool {
+			return size > encoderCacheSize
+		},
+	})
 }
 
 type tableIDAndVersion uint64
@@ -286,7 +424,7 @@ func makeTableIDAndVersion(id descpb.ID, version descpb.DescriptorVersion) table
 	return tableIDAndVersion(id)<<32 + tableIDAndVersion(version)
 }
 
-type confluentRegisteredKeySchema struct {
+type confluentRegisteredDataSchema struct {
 	schema     *avroDataRecord
 	registryID int32
 }
@@ -304,18 +442,20 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 	switch opts[changefeedbase.OptEnvelope] {
 	case string(changefeedbase.OptEnvelopeKeyOnly):
 		e.keyOnly = true
+	case string(changefeedbase.OptEnvelopeRow):
 	case string(changefeedbase.OptEnvelopeWrapped):
+		e.wrapped = true
 	default:
 		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
 			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
 	}
 	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
-	if e.updatedField && e.keyOnly {
+	if e.updatedField && !e.wrapped {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptUpdatedTimestamps, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
 	_, e.beforeField = opts[changefeedbase.OptDiff]
-	if e.beforeField && e.keyOnly {
+	if e.beforeField && !e.wrapped {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
@@ -330,16 +470,18 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
 	}
 
-	e.keyCache = make(map[tableIDAndVersion]confluentRegisteredKeySchema)
-	e.valueCache = make(map[tableIDAndVersionPair]confluentRegisteredEnvelopeSchema)
-	e.resolvedCache = make(map[string]confluentRegisteredEnvelopeSchema)
+	e.keyCache = newEncoderCache()
+	e.rowCache = newEncoderCache()
+	e.valueCache = newEncoderCache()
+	e.resolvedCache = newEncoderCache()
 	return e, nil
 }
 
 // EncodeKey implements the Encoder interface.
 func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]byte, error) {
 	cacheKey := makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
-	registered, ok := e.keyCache[cacheKey]
+	cached, ok := e.keyCache.Get(cacheKey)
+	registered, _ := cached.(confluentRegisteredDataSchema)
 	if !ok {
 		var err error
 		registered.schema, err = indexToAvroSchema(row.tableDesc, row.tableDesc.GetPrimaryIndex())
@@ -354,8 +496,7 @@ func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]
 		if err != nil {
 			return nil, err
 		}
-		// TODO(dan): Bound the size of this cache.
-		e.keyCache[cacheKey] = registered
+		e.keyCache.Add(cacheKey, registered)
 	}
 
 	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
@@ -369,16 +510,20 @@ func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]
 
 // EncodeValue implements the Encoder interface.
 func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) ([]byte, error) {
-	if e.keyOnly {
+	if e.keyOnly || (!e.wrapped && row.deleted) {
 		return nil, nil
 	}
+	if !e.wrapped {
+		return e.encodeRowValue(ctx, row)
+	}
 
 	var cacheKey tableIDAndVersionPair
 	if e.beforeField && row.prevTableDesc != nil {
 		cacheKey[0] = makeTableIDAndVersion(row.prevTableDesc.GetID(), row.prevTableDesc.GetVersion())
 	}
 	cacheKey[1] = makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
-	registered, ok := e.valueCache[cacheKey]
+	cached, ok := e.valueCache.Get(cacheKey)
+	registered, _ := cached.(confluentRegisteredEnvelopeSchema)
 	if !ok {
 		var beforeDataSchema *avroDataRecord
 		if e.beforeField && row.prevTableDesc != nil {
@@ -407,8 +552,7 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 		if err != nil {
 			return nil, err
 		}
-		// TODO(dan): Bound the size of this cache.
-		e.valueCache[cacheKey] = registered
+		e.valueCache.Add(cacheKey, registered)
 	}
 	var meta avroMetadata
 	if registered.schema.opts.updatedField {
@@ -432,11 +576,44 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 	return registered.schema.BinaryFromRow(header, meta, beforeDatums, afterDatums)
 }
 
+// encodeRowValue encodes the value of a row for envelope=row, which is the
+// record of the columns without any metadata.
+func (e *confluentAvroEncoder) encodeRowValue(ctx context.Context, row encodeRow) ([]byte, error) {
+	cacheKey := makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
+	cached, ok := e.rowCache.Get(cacheKey)
+	registered, _ := cached.(confluentRegisteredDataSchema)
+	if !ok {
+		var err error
+		registered.schema, err = tableToAvroSchema(row.tableDesc, avroSchemaNoSuffix)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(row.tableDesc.GetName()) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.rowCache.Add(cacheKey, registered)
+	}
+
+	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
+	header := []byte{
+		confluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registered.registryID))
+	return registered.schema.BinaryFromRow(header, row.datums)
+}
+
 // EncodeResolvedTimestamp implements the Encoder interface.
 func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 	ctx context.Context, topic string, resolved hlc.Timestamp,
 ) ([]byte, error) {
-	registered, ok := e.resolvedCache[topic]
+	cached, ok := e.resolvedCache.Get(topic)
+	registered, _ := cached.(confluentRegisteredEnvelopeSchema)
 	if !ok {
 		opts := avroEnvelopeOpts{resolvedField: true}
 		var err error
@@ -452,8 +629,7 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 		if err != nil {
 			return nil, err
 		}
-		// TODO(dan): Bound the size of this cache.
-		e.resolvedCache[topic] = registered
+		e.resolvedCache.Add(topic, registered)
 	}
 	var meta avroMetadata
 	if registered.schema.opts.resolvedField {
@@ -472,26 +648,269 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avroRecord, subject string,
+) (int32, error) {
+	return registerConfluentSchema(ctx, e.registryURL, subject, confluentSchemaTypeAvro, schema.codec.Schema())
+}
+
+// confluentProtobufEncoder encodes changefeed entries as protobuf messages in
+// the Confluent wire format, after registering the generated schemas in a
+// Confluent schema registry. Keys are the primary key columns in a message.
+// Values are all columns in a message, wrapped in an envelope message unless
+// envelope=row.
+type confluentProtobufEncoder struct {
+	registryURL                                 string
+	updatedField, beforeField, keyOnly, wrapped bool
+
+	keyCache      *cache.UnorderedCache
+	rowCache      *cache.UnorderedCache
+	valueCache    *cache.UnorderedCache
+	resolvedCache *cache.UnorderedCache
+}
+
+type confluentRegisteredProtobufData struct {
+	message    *protobufDataMessage
+	registryID int32
+}
+
+type confluentRegisteredProtobufEnvelope struct {
+	message    *protobufEnvelopeMessage
+	registryID int32
+}
+
+var _ Encoder = &confluentProtobufEncoder{}
+
+func newConfluentProtobufEncoder(opts map[string]string) (*confluentProtobufEncoder, error) {
+	e := &confluentProtobufEncoder{registryURL: opts[changefeedbase.OptConfluentSchemaRegistry]}
+
+	switch opts[changefeedbase.OptEnvelope] {
+	case string(changefeedbase.OptEnvelopeKeyOnly):
+		e.keyOnly = true
+	case string(changefeedbase.OptEnvelopeRow):
+	case string(changefeedbase.OptEnvelopeWrapped):
+		e.wrapped = true
+	default:
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	if e.updatedField && !e.wrapped {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptUpdatedTimestamps, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+	_, e.beforeField = opts[changefeedbase.OptDiff]
+	if e.beforeField && !e.wrapped {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+
+	if _, ok := opts[changefeedbase.OptKeyInValue]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	if len(e.registryURL) == 0 {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	e.keyCache = newEncoderCache()
+	e.rowCache = newEncoderCache()
+	e.valueCache = newEncoderCache()
+	e.resolvedCache = newEncoderCache()
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]byte, error) {
+	cacheKey := makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
+	cached, ok := e.keyCache.Get(cacheKey)
+	registered, _ := cached.(confluentRegisteredProtobufData)
+	if !ok {
+		var err error
+		registered.message, err = indexToProtobufMessage(row.tableDesc, row.tableDesc.GetPrimaryIndex())
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(row.tableDesc.GetName()) + confluentSubjectSuffixKey
+		registered.registryID, err = e.register(ctx, registered.message.file, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.keyCache.Add(cacheKey, registered)
+	}
+	return registered.message.BinaryFromRow(confluentProtobufHeader(registered.registryID), row.datums)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeValue(
+	ctx context.Context, row encodeRow,
+) ([]byte, error) {
+	if e.keyOnly || (!e.wrapped && row.deleted) {
+		return nil, nil
+	}
+	if !e.wrapped {
+		return e.encodeRowValue(ctx, row)
+	}
+
+	var cacheKey tableIDAndVersionPair
+	if e.beforeField && row.prevTableDesc != nil {
+		cacheKey[0] = makeTableIDAndVersion(row.prevTableDesc.GetID(), row.prevTableDesc.GetVersion())
+	}
+	cacheKey[1] = makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
+	cached, ok := e.valueCache.Get(cacheKey)
+	registered, _ := cached.(confluentRegisteredProtobufEnvelope)
+	if !ok {
+		var before *protobufDataMessage
+		if e.beforeField && row.prevTableDesc != nil {
+			var err error
+			before, err = tableToProtobufMessage(row.prevTableDesc, `before`)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		after, err := tableToProtobufMessage(row.tableDesc, avroSchemaNoSuffix)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := avroEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField}
+		registered.message, err = envelopeToProtobufMessage(row.tableDesc.GetName(), opts, before, after)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(row.tableDesc.GetName()) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(ctx, registered.message.file, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.valueCache.Add(cacheKey, registered)
+	}
+	var beforeDatums, afterDatums rowenc.EncDatumRow
+	if row.prevDatums != nil && !row.prevDeleted {
+		beforeDatums = row.prevDatums
+	}
+	if !row.deleted {
+		afterDatums = row.datums
+	}
+	return registered.message.BinaryFromRow(
+		confluentProtobufHeader(registered.registryID), row.updated, beforeDatums, afterDatums)
+}
+
+// encodeRowValue encodes the value of a row for envelope=row, which is the
+// message of the columns without any metadata.
+func (e *confluentProtobufEncoder) encodeRowValue(
+	ctx context.Context, row encodeRow,
+) ([]byte, error) {
+	cacheKey := makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
+	cached, ok := e.rowCache.Get(cacheKey)
+	registered, _ := cached.(confluentRegisteredProtobufData)
+	if !ok {
+		var err error
+		registered.message, err = tableToProtobufMessage(row.tableDesc, avroSchemaNoSuffix)
+		if err != nil {
+			return nil, err
+		}
+		if err := registered.message.build(); err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(row.tableDesc.GetName()) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(ctx, registered.message.file, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.rowCache.Add(cacheKey, registered)
+	}
+	return registered.message.BinaryFromRow(confluentProtobufHeader(registered.registryID), row.datums)
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	cached, ok := e.resolvedCache.Get(topic)
+	registered, _ := cached.(confluentRegisteredProtobufEnvelope)
+	if !ok {
+		opts := avroEnvelopeOpts{resolvedField: true}
+		var err error
+		registered.message, err = envelopeToProtobufMessage(topic, opts, nil /* before */, nil /* after */)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(ctx, registered.message.file, subject)
+		if err != nil {
+			return nil, err
+		}
+		e.resolvedCache.Add(topic, registered)
+	}
+	return registered.message.BinaryFromRow(
+		confluentProtobufHeader(registered.registryID), resolved, nil /* beforeRow */, nil /* afterRow */)
+}
+
+func (e *confluentProtobufEncoder) register(
+	ctx context.Context, file *descriptorpb.FileDescriptorProto, subject string,
+) (int32, error) {
+	return registerConfluentSchema(
+		ctx, e.registryURL, subject, confluentSchemaTypeProtobuf, protobufSchemaText(file))
+}
+
+// confluentProtobufHeader returns the header of a message with the given
+// registered schema. The wire format is the same as for avro, followed by the
+// indexes of the message in the schema, which is always the first one here.
+//
+// https://docs.confluent.io/current/schema-registry/serdes-develop/index.html#wire-format
+func confluentProtobufHeader(registryID int32) []byte {
+	header := []byte{
+		confluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+		0, // The message indexes [0] are encoded as a single 0.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registryID))
+	return header
+}
+
+// registerConfluentSchema registers the given schema under the subject in the
+// Confluent schema registry and returns its ID.
+func registerConfluentSchema(
+	ctx context.Context, registryURL, subject, schemaType, schemaStr string,
 ) (int32, error) {
 	type confluentSchemaVersionRequest struct {
-		Schema string `json:"schema"`
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType,omitempty"`
 	}
 	type confluentSchemaVersionResponse struct {
 		ID int32 `json:"id"`
 	}
 
-	url, err := url.Parse(e.registryURL)
+	url, err := url.Parse(registryURL)
 	if err != nil {
 		return 0, err
 	}
 	url.Path = filepath.Join(url.EscapedPath(), `subjects`, subject, `versions`)
 
-	schemaStr := schema.codec.Schema()
 	if log.V(1) {
-		log.Infof(ctx, "registering avro schema %s %s", url, schemaStr)
+		log.Infof(ctx, "registering %s schema %s %s", schemaType, url, schemaStr)
 	}
 
 	req := confluentSchemaVersionRequest{Schema: schemaStr}
+	// The registry assumes avro when the schema type is unset, and versions
+	// before 5.5 reject the field altogether.
+	if schemaType != confluentSchemaTypeAvro {
+		req.SchemaType = schemaType
+	}
 	var buf bytes.Buffer
 	if err := gojson.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
@@ -21,6 +21,9 @@ import (
 	"github.com/cockroachdb/cockroach-go/crdb"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -49,7 +52,10 @@ func TestEncoders(t *testing.T) {
 	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
 
 	var opts []map[string]string
-	for _, f := range []string{string(changefeedbase.OptFormatJSON), string(changefeedbase.OptFormatAvro)} {
+	for _, f := range []string{
+		string(changefeedbase.OptFormatJSON), string(changefeedbase.OptFormatAvro),
+		string(changefeedbase.OptFormatCSV), string(changefeedbase.OptFormatProtobuf),
+	} {
 		for _, e := range []string{
 			string(changefeedbase.OptEnvelopeKeyOnly), string(changefeedbase.OptEnvelopeRow), string(changefeedbase.OptEnvelopeWrapped),
 		} {
@@ -121,46 +127,48 @@ func TestEncoders(t *testing.T) {
 			delete:   `[1]->{"after": null, "before": {"a": 1, "b": "bar"}, "updated": "1.0000000002"}`,
 			resolved: `{"resolved":"1.0000000002"}`,
 		},
-		`format=experimental_avro,envelope=key_only`: {
+		`format=avro,envelope=key_only`: {
 			insert:   `{"a":{"long":1}}->`,
 			delete:   `{"a":{"long":1}}->`,
 			resolved: `{"resolved":{"string":"1.0000000002"}}`,
 		},
-		`format=experimental_avro,envelope=key_only,updated`: {
+		`format=avro,envelope=key_only,updated`: {
 			err: `updated is only usable with envelope=wrapped`,
 		},
-		`format=experimental_avro,envelope=key_only,diff`: {
+		`format=avro,envelope=key_only,diff`: {
 			err: `diff is only usable with envelope=wrapped`,
 		},
-		`format=experimental_avro,envelope=key_only,updated,diff`: {
+		`format=avro,envelope=key_only,updated,diff`: {
 			err: `updated is only usable with envelope=wrapped`,
 		},
-		`format=experimental_avro,envelope=row`: {
-			err: `envelope=row is not supported with format=experimental_avro`,
+		`format=avro,envelope=row`: {
+			insert:   `{"a":{"long":1}}->{"a":{"long":1},"b":{"string":"bar"}}`,
+			delete:   `{"a":{"long":1}}->`,
+			resolved: `{"resolved":{"string":"1.0000000002"}}`,
 		},
-		`format=experimental_avro,envelope=row,updated`: {
-			err: `envelope=row is not supported with format=experimental_avro`,
+		`format=avro,envelope=row,updated`: {
+			err: `updated is only usable with envelope=wrapped`,
 		},
-		`format=experimental_avro,envelope=row,diff`: {
-			err: `envelope=row is not supported with format=experimental_avro`,
+		`format=avro,envelope=row,diff`: {
+			err: `diff is only usable with envelope=wrapped`,
 		},
-		`format=experimental_avro,envelope=row,updated,diff`: {
-			err: `envelope=row is not supported with format=experimental_avro`,
+		`format=avro,envelope=row,updated,diff`: {
+			err: `updated is only usable with envelope=wrapped`,
 		},
-		`format=experimental_avro,envelope=wrapped`: {
+		`format=avro,envelope=wrapped`: {
 			insert: `{"a":{"long":1}}->` +
 				`{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}}}`,
 			delete:   `{"a":{"long":1}}->{"after":null}`,
 			resolved: `{"resolved":{"string":"1.0000000002"}}`,
 		},
-		`format=experimental_avro,envelope=wrapped,updated`: {
+		`format=avro,envelope=wrapped,updated`: {
 			insert: `{"a":{"long":1}}->` +
 				`{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},` +
 				`"updated":{"string":"1.0000000002"}}`,
 			delete:   `{"a":{"long":1}}->{"after":null,"updated":{"string":"1.0000000002"}}`,
 			resolved: `{"resolved":{"string":"1.0000000002"}}`,
 		},
-		`format=experimental_avro,envelope=wrapped,diff`: {
+		`format=avro,envelope=wrapped,diff`: {
 			insert: `{"a":{"long":1}}->` +
 				`{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},` +
 				`"before":null}`,
@@ -169,7 +177,7 @@ func TestEncoders(t *testing.T) {
 				`"before":{"foo_before":{"a":{"long":1},"b":{"string":"bar"}}}}`,
 			resolved: `{"resolved":{"string":"1.0000000002"}}`,
 		},
-		`format=experimental_avro,envelope=wrapped,updated,diff`: {
+		`format=avro,envelope=wrapped,updated,diff`: {
 			insert: `{"a":{"long":1}}->` +
 				`{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},` +
 				`"before":null,` +
@@ -180,6 +188,94 @@ func TestEncoders(t *testing.T) {
 				`"updated":{"string":"1.0000000002"}}`,
 			resolved: `{"resolved":{"string":"1.0000000002"}}`,
 		},
+		`format=csv,envelope=key_only`: {
+			insert:   `1->`,
+			delete:   `1->`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`format=csv,envelope=key_only,updated`: {
+			err: `updated is not supported with format=csv`,
+		},
+		`format=csv,envelope=key_only,diff`: {
+			err: `diff is not supported with format=csv`,
+		},
+		`format=csv,envelope=key_only,updated,diff`: {
+			err: `updated is not supported with format=csv`,
+		},
+		`format=csv,envelope=row`: {
+			insert:   `1->1,bar`,
+			delete:   `1->`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`format=csv,envelope=row,updated`: {
+			err: `updated is not supported with format=csv`,
+		},
+		`format=csv,envelope=row,diff`: {
+			err: `diff is not supported with format=csv`,
+		},
+		`format=csv,envelope=row,updated,diff`: {
+			err: `updated is not supported with format=csv`,
+		},
+		`format=csv,envelope=wrapped`: {
+			err: `envelope=wrapped is not supported with format=csv`,
+		},
+		`format=csv,envelope=wrapped,updated`: {
+			err: `envelope=wrapped is not supported with format=csv`,
+		},
+		`format=csv,envelope=wrapped,diff`: {
+			err: `envelope=wrapped is not supported with format=csv`,
+		},
+		`format=csv,envelope=wrapped,updated,diff`: {
+			err: `envelope=wrapped is not supported with format=csv`,
+		},
+		`format=protobuf,envelope=key_only`: {
+			insert:   `{"a":1}->`,
+			delete:   `{"a":1}->`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`format=protobuf,envelope=key_only,updated`: {
+			err: `updated is only usable with envelope=wrapped`,
+		},
+		`format=protobuf,envelope=key_only,diff`: {
+			err: `diff is only usable with envelope=wrapped`,
+		},
+		`format=protobuf,envelope=key_only,updated,diff`: {
+			err: `updated is only usable with envelope=wrapped`,
+		},
+		`format=protobuf,envelope=row`: {
+			insert:   `{"a":1}->{"a":1,"b":"bar"}`,
+			delete:   `{"a":1}->`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`format=protobuf,envelope=row,updated`: {
+			err: `updated is only usable with envelope=wrapped`,
+		},
+		`format=protobuf,envelope=row,diff`: {
+			err: `diff is only usable with envelope=wrapped`,
+		},
+		`format=protobuf,envelope=row,updated,diff`: {
+			err: `updated is only usable with envelope=wrapped`,
+		},
+		`format=protobuf,envelope=wrapped`: {
+			insert:   `{"a":1}->{"after":{"a":1,"b":"bar"}}`,
+			delete:   `{"a":1}->{}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`format=protobuf,envelope=wrapped,updated`: {
+			insert:   `{"a":1}->{"after":{"a":1,"b":"bar"},"updated":"1.0000000002"}`,
+			delete:   `{"a":1}->{"updated":"1.0000000002"}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`format=protobuf,envelope=wrapped,diff`: {
+			insert:   `{"a":1}->{"after":{"a":1,"b":"bar"}}`,
+			delete:   `{"a":1}->{"before":{"a":1,"b":"bar"}}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		`format=protobuf,envelope=wrapped,updated,diff`: {
+			insert:   `{"a":1}->{"after":{"a":1,"b":"bar"},"updated":"1.0000000002"}`,
+			delete:   `{"a":1}->{"before":{"a":1,"b":"bar"},"updated":"1.0000000002"}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
 	}
 
 	for _, o := range opts {
@@ -193,10 +289,11 @@ func TestEncoders(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			expected := expecteds[name]
 
+			var e Encoder
 			var rowStringFn func([]byte, []byte) string
 			var resolvedStringFn func([]byte) string
 			switch o[changefeedbase.OptFormat] {
-			case string(changefeedbase.OptFormatJSON):
+			case string(changefeedbase.OptFormatJSON), string(changefeedbase.OptFormatCSV):
 				rowStringFn = func(k, v []byte) string { return fmt.Sprintf(`%s->%s`, k, v) }
 				resolvedStringFn = func(r []byte) string { return string(r) }
 			case string(changefeedbase.OptFormatAvro):
@@ -210,11 +307,24 @@ func TestEncoders(t *testing.T) {
 				resolvedStringFn = func(r []byte) string {
 					return string(avroToJSON(t, reg, r))
 				}
+			case string(changefeedbase.OptFormatProtobuf):
+				reg := makeTestSchemaRegistry()
+				defer reg.Close()
+				o[changefeedbase.OptConfluentSchemaRegistry] = reg.server.URL
+				rowStringFn = func(k, v []byte) string {
+					pe := e.(*confluentProtobufEncoder)
+					return fmt.Sprintf(`%s->%s`,
+						protobufToJSON(t, pe, tableDesc, k), protobufToJSON(t, pe, tableDesc, v))
+				}
+				resolvedStringFn = func(r []byte) string {
+					return string(protobufToJSON(t, e.(*confluentProtobufEncoder), tableDesc, r))
+				}
 			default:
 				t.Fatalf(`unknown format: %s`, o[changefeedbase.OptFormat])
 			}
 
-			e, err := getEncoder(o)
+			var err error
+			e, err = getEncoder(o)
 			if len(expected.err) > 0 {
 				require.EqualError(t, err, expected.err)
 				return
@@ -388,6 +498,49 @@ func TestAvroEncoder(t *testing.T) {
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
 
+func TestAvroSchemaEvolution(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		reg := makeTestSchemaRegistry()
+		defer reg.Close()
+
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TYPE status AS ENUM ('open', 'closed')`)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b status, c STRING[])`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'open', ARRAY['x', NULL])`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo `+
+			`WITH format=$1, confluent_schema_registry=$2`,
+			changefeedbase.OptFormatAvro, reg.server.URL)
+		defer closeFeed(t, foo)
+		assertPayloadsAvro(t, reg, foo, []string{
+			`foo: {"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"open"},` +
+				`"c":{"array":[{"string":"x"},null]}}}}`,
+		})
+
+		// Enum values are encoded as strings, so adding one doesn't change the
+		// schema.
+		sqlDB.Exec(t, `ALTER TYPE status ADD VALUE 'pending'`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'pending', ARRAY[])`)
+		assertPayloadsAvro(t, reg, foo, []string{
+			`foo: {"a":{"long":2}}->{"after":{"foo":{"a":{"long":2},"b":{"string":"pending"},` +
+				`"c":{"array":[]}}}}`,
+		})
+
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN d INT[]`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, NULL, NULL, ARRAY[1])`)
+		assertPayloadsAvro(t, reg, foo, []string{
+			`foo: {"a":{"long":3}}->{"after":{"foo":{"a":{"long":3},"b":null,"c":null,` +
+				`"d":{"array":[{"long":1}]}}}}`,
+		})
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
 func TestAvroMigrateToUnsupportedColumn(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -457,3 +610,82 @@ func TestAvroLedger(t *testing.T) {
 	t.Run(`sinkless`, sinklessTest(testFn))
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
+
+func TestChangefeedFormatVersionGate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	arrayTableDesc, err := parseTableDesc(`CREATE TABLE bar (a INT PRIMARY KEY, b INT[])`)
+	require.NoError(t, err)
+
+	oldVersion := clusterversion.VersionByKey(clusterversion.VersionChangefeedFormats - 1)
+	oldSettings := cluster.MakeTestingClusterSettingsWithVersions(
+		clusterversion.TestingBinaryVersion, oldVersion, true /* initializeVersion */)
+	newSettings := cluster.MakeTestingClusterSettings()
+
+	tests := []struct {
+		opts      map[string]string
+		table     catalog.TableDescriptor
+		oldFormat string
+		oldErr    string
+	}{
+		{
+			opts:      map[string]string{`format`: `json`},
+			table:     tableDesc,
+			oldFormat: `json`,
+		},
+		{
+			opts:      map[string]string{`format`: `experimental_avro`},
+			table:     tableDesc,
+			oldFormat: `experimental_avro`,
+		},
+		{
+			opts:      map[string]string{`format`: `avro`},
+			table:     tableDesc,
+			oldFormat: `experimental_avro`,
+		},
+		{
+			opts:   map[string]string{`format`: `avro`, `envelope`: `row`},
+			table:  tableDesc,
+			oldErr: `envelope=row with format=avro requires all nodes to be upgraded`,
+		},
+		{
+			opts:   map[string]string{`format`: `avro`},
+			table:  arrayTableDesc,
+			oldErr: `format=avro with column b of type INT8\[\] requires all nodes to be upgraded`,
+		},
+		{
+			opts:   map[string]string{`format`: `csv`},
+			table:  tableDesc,
+			oldErr: `format=csv requires all nodes to be upgraded`,
+		},
+		{
+			opts:   map[string]string{`format`: `protobuf`},
+			table:  tableDesc,
+			oldErr: `format=protobuf requires all nodes to be upgraded`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.opts, test.table.GetName()), func(t *testing.T) {
+			descs := []catalog.Descriptor{test.table}
+
+			opts := make(map[string]string)
+			for k, v := range test.opts {
+				opts[k] = v
+			}
+			require.NoError(t, checkChangefeedFormatVersion(ctx, newSettings, opts, descs))
+			require.Equal(t, test.opts, opts)
+
+			err := checkChangefeedFormatVersion(ctx, oldSettings, opts, descs)
+			if test.oldErr != `` {
+				require.Regexp(t, test.oldErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.oldFormat, opts[`format`])
+		})
+	}
+}
@@ -0,0 +1,421 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// The file maps SQL schemas to protobuf messages, like avro.go does for avro
+// records. It's not intended to be a general purpose protobuf utility.
+//
+// The message descriptors are generated from the table descriptors at runtime.
+// They use the proto2 syntax so that every field has presence: a NULL column
+// is an unset field. The field number of a column is its column ID, which is
+// never reused within a table, so the messages generated for all the versions
+// of a table are compatible with each other. A dropped column is an unknown
+// field for readers of newer schemas and an added column is an unset field for
+// readers of older ones, and renaming a column doesn't change the encoding.
+//
+// Booleans, integers, floats and bytes (including the EWKB of spatial types)
+// map to the corresponding protobuf scalar types, and arrays to repeated
+// fields. Every other type is encoded as a string, in the same representation
+// as EXPORT. This keeps decimals and timestamps lossless without depending on
+// the well-known types.
+
+// Field numbers of the envelope messages.
+const (
+	protobufEnvelopeBeforeField   = 1
+	protobufEnvelopeAfterField    = 2
+	protobufEnvelopeUpdatedField  = 3
+	protobufEnvelopeResolvedField = 4
+)
+
+var protobufMarshalOptions = proto.MarshalOptions{Deterministic: true}
+
+// protobufField is a field of a protobufDataMessage, corresponding to a column.
+type protobufField struct {
+	proto *descriptorpb.FieldDescriptorProto
+	desc  protoreflect.FieldDescriptor
+
+	colName string
+	typ     *types.T
+
+	// encodeFn encodes a non-NULL datum or, for repeated fields, a non-NULL
+	// element of the array.
+	encodeFn func(tree.Datum) (protoreflect.Value, error)
+}
+
+// protobufDataMessage is a message representing a SQL table or index.
+type protobufDataMessage struct {
+	proto *descriptorpb.DescriptorProto
+	desc  protoreflect.MessageDescriptor
+	// file is set when the message was built into a file of its own.
+	file *descriptorpb.FileDescriptorProto
+
+	fields           []*protobufField
+	colIdxByFieldIdx []int
+	alloc            rowenc.DatumAlloc
+}
+
+// protobufEnvelopeMessage is a message that wraps a changed SQL row and some
+// metadata. The before and after messages are part of the same file.
+type protobufEnvelopeMessage struct {
+	proto *descriptorpb.DescriptorProto
+	desc  protoreflect.MessageDescriptor
+	file  *descriptorpb.FileDescriptorProto
+
+	opts          avroEnvelopeOpts
+	before, after *protobufDataMessage
+}
+
+// columnDescToProtobufField converts a column descriptor into its
+// corresponding protobuf field.
+func columnDescToProtobufField(colDesc *descpb.ColumnDescriptor) (*protobufField, error) {
+	if !protowire.Number(colDesc.ID).IsValid() {
+		return nil, errors.Errorf(
+			`column %s: column ID %d is not a valid protobuf field number`, colDesc.Name, colDesc.ID)
+	}
+	field := &protobufField{
+		proto: &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(SQLNameToAvroName(colDesc.Name)),
+			Number: proto.Int32(int32(colDesc.ID)),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		},
+		colName: colDesc.Name,
+		typ:     colDesc.Type,
+	}
+
+	typ := colDesc.Type
+	if typ.Family() == types.ArrayFamily {
+		field.proto.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		typ = typ.ArrayContents()
+	}
+	var protoType descriptorpb.FieldDescriptorProto_Type
+	switch typ.Family() {
+	case types.BoolFamily:
+		protoType = descriptorpb.FieldDescriptorProto_TYPE_BOOL
+		field.encodeFn = func(d tree.Datum) (protoreflect.Value, error) {
+			return protoreflect.ValueOfBool(bool(*d.(*tree.DBool))), nil
+		}
+	case types.IntFamily:
+		protoType = descriptorpb.FieldDescriptorProto_TYPE_INT64
+		field.encodeFn = func(d tree.Datum) (protoreflect.Value, error) {
+			return protoreflect.ValueOfInt64(int64(*d.(*tree.DInt))), nil
+		}
+	case types.FloatFamily:
+		protoType = descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+		field.encodeFn = func(d tree.Datum) (protoreflect.Value, error) {
+			return protoreflect.ValueOfFloat64(float64(*d.(*tree.DFloat))), nil
+		}
+	case types.BytesFamily:
+		protoType = descriptorpb.FieldDescriptorProto_TYPE_BYTES
+		field.encodeFn = func(d tree.Datum) (protoreflect.Value, error) {
+			return protoreflect.ValueOfBytes([]byte(*d.(*tree.DBytes))), nil
+		}
+	case types.GeographyFamily:
+		protoType = descriptorpb.FieldDescriptorProto_TYPE_BYTES
+		field.encodeFn = func(d tree.Datum) (protoreflect.Value, error) {
+			return protoreflect.ValueOfBytes([]byte(d.(*tree.DGeography).EWKB())), nil
+		}
+	case types.GeometryFamily:
+		protoType = descriptorpb.FieldDescriptorProto_TYPE_BYTES
+		field.encodeFn = func(d tree.Datum) (protoreflect.Value, error) {
+			return protoreflect.ValueOfBytes([]byte(d.(*tree.DGeometry).EWKB())), nil
+		}
+	case types.AnyFamily, types.TupleFamily, types.UnknownFamily, types.ArrayFamily:
+		return nil, errors.Errorf(`column %s: type %s not supported with protobuf`,
+			colDesc.Name, colDesc.Type.SQLString())
+	default:
+		protoType = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		field.encodeFn = func(d tree.Datum) (protoreflect.Value, error) {
+			return protoreflect.ValueOfString(tree.AsStringWithFlags(d, tree.FmtExport)), nil
+		}
+	}
+	field.proto.Type = protoType.Enum()
+	return field, nil
+}
+
+func makeProtobufDataMessage(
+	name string, tableDesc catalog.TableDescriptor, colIdxs []int,
+) (*protobufDataMessage, error) {
+	m := &protobufDataMessage{
+		proto: &descriptorpb.DescriptorProto{Name: proto.String(name)},
+	}
+	for _, colIdx := range colIdxs {
+		field, err := columnDescToProtobufField(tableDesc.GetColumnAtIdx(colIdx))
+		if err != nil {
+			return nil, err
+		}
+		m.proto.Field = append(m.proto.Field, field.proto)
+		m.fields = append(m.fields, field)
+		m.colIdxByFieldIdx = append(m.colIdxByFieldIdx, colIdx)
+	}
+	return m, nil
+}
+
+// indexToProtobufMessage converts an index descriptor into its corresponding
+// protobuf message, built into a file of its own. The fields are kept in the
+// same order as the columns of the index.
+func indexToProtobufMessage(
+	tableDesc catalog.TableDescriptor, indexDesc *descpb.IndexDescriptor,
+) (*protobufDataMessage, error) {
+	colIdxByID := tableDesc.ColumnIdxMap()
+	colIdxs := make([]int, len(indexDesc.ColumnIDs))
+	for i, colID := range indexDesc.ColumnIDs {
+		colIdx, ok := colIdxByID[colID]
+		if !ok {
+			return nil, errors.Errorf(`unknown column id: %d`, colID)
+		}
+		colIdxs[i] = colIdx
+	}
+	m, err := makeProtobufDataMessage(SQLNameToAvroName(tableDesc.GetName()), tableDesc, colIdxs)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.build(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// tableToProtobufMessage converts a table descriptor into its corresponding
+// protobuf message. The fields are kept in the same order as the public
+// columns of the table. If a name suffix is provided (as opposed to
+// avroSchemaNoSuffix), it is appended to the name of the message.
+//
+// The returned message must either be built into a file of its own with
+// build, or be part of an envelope.
+func tableToProtobufMessage(
+	tableDesc catalog.TableDescriptor, nameSuffix string,
+) (*protobufDataMessage, error) {
+	name := SQLNameToAvroName(tableDesc.GetName())
+	if nameSuffix != avroSchemaNoSuffix {
+		name = name + `_` + nameSuffix
+	}
+	colIdxs := make([]int, len(tableDesc.GetPublicColumns()))
+	for i := range colIdxs {
+		colIdxs[i] = i
+	}
+	return makeProtobufDataMessage(name, tableDesc, colIdxs)
+}
+
+// build generates a file containing only this message.
+func (m *protobufDataMessage) build() error {
+	file, fileDesc, err := makeProtobufFile(m.proto)
+	if err != nil {
+		return err
+	}
+	m.file = file
+	m.resolve(fileDesc)
+	return nil
+}
+
+// resolve sets the descriptors of the message and its fields from the file it
+// was built into.
+func (m *protobufDataMessage) resolve(fileDesc protoreflect.FileDescriptor) {
+	m.desc = fileDesc.Messages().ByName(protoreflect.Name(m.proto.GetName()))
+	for _, field := range m.fields {
+		field.desc = m.desc.Fields().ByNumber(protoreflect.FieldNumber(field.proto.GetNumber()))
+	}
+}
+
+// setFromRow sets the fields of msg, which must be of this message's type,
+// from the given row.
+func (m *protobufDataMessage) setFromRow(msg protoreflect.Message, row rowenc.EncDatumRow) error {
+	for fieldIdx, field := range m.fields {
+		d := row[m.colIdxByFieldIdx[fieldIdx]]
+		if err := d.EnsureDecoded(field.typ, &m.alloc); err != nil {
+			return err
+		}
+		if d.Datum == tree.DNull {
+			continue
+		}
+		if !field.desc.IsList() {
+			v, err := field.encodeFn(d.Datum)
+			if err != nil {
+				return err
+			}
+			msg.Set(field.desc, v)
+			continue
+		}
+		list := msg.Mutable(field.desc).List()
+		for _, elem := range d.Datum.(*tree.DArray).Array {
+			if elem == tree.DNull {
+				return errors.Errorf(
+					`column %s: arrays with NULL elements are not supported with protobuf`, field.colName)
+			}
+			v, err := field.encodeFn(elem)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+	}
+	return nil
+}
+
+// BinaryFromRow appends the given row data in protobuf's binary format to buf.
+func (m *protobufDataMessage) BinaryFromRow(buf []byte, row rowenc.EncDatumRow) ([]byte, error) {
+	msg := dynamicpb.NewMessage(m.desc)
+	if err := m.setFromRow(msg, row); err != nil {
+		return nil, err
+	}
+	return protobufMarshalOptions.MarshalAppend(buf, msg)
+}
+
+// envelopeToProtobufMessage creates a protobuf message for an envelope
+// containing before and after versions of a row change and metadata about that
+// row change. The envelope is the first message of the generated file.
+func envelopeToProtobufMessage(
+	topic string, opts avroEnvelopeOpts, before, after *protobufDataMessage,
+) (*protobufEnvelopeMessage, error) {
+	m := &protobufEnvelopeMessage{
+		proto: &descriptorpb.DescriptorProto{
+			Name: proto.String(SQLNameToAvroName(topic) + `_envelope`),
+		},
+		opts: opts,
+	}
+	messageField := func(name string, number int32, data *protobufDataMessage) {
+		m.proto.Field = append(m.proto.Field, &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(`.` + data.proto.GetName()),
+		})
+	}
+	stringField := func(name string, number int32) {
+		m.proto.Field = append(m.proto.Field, &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		})
+	}
+
+	messages := []*descriptorpb.DescriptorProto{m.proto}
+	if opts.beforeField && before != nil {
+		m.before = before
+		messageField(`before`, protobufEnvelopeBeforeField, before)
+		messages = append(messages, before.proto)
+	}
+	if opts.afterField {
+		m.after = after
+		messageField(`after`, protobufEnvelopeAfterField, after)
+		messages = append(messages, after.proto)
+	}
+	if opts.updatedField {
+		stringField(`updated`, protobufEnvelopeUpdatedField)
+	}
+	if opts.resolvedField {
+		stringField(`resolved`, protobufEnvelopeResolvedField)
+	}
+
+	file, fileDesc, err := makeProtobufFile(messages...)
+	if err != nil {
+		return nil, err
+	}
+	m.file = file
+	m.desc = fileDesc.Messages().ByName(protoreflect.Name(m.proto.GetName()))
+	if m.before != nil {
+		m.before.resolve(fileDesc)
+	}
+	if m.after != nil {
+		m.after.resolve(fileDesc)
+	}
+	return m, nil
+}
+
+// BinaryFromRow appends the given metadata and row data in protobuf's binary
+// format to buf. The timestamp is the updated or resolved timestamp, depending
+// on which of these fields the envelope has.
+func (m *protobufEnvelopeMessage) BinaryFromRow(
+	buf []byte, ts hlc.Timestamp, beforeRow, afterRow rowenc.EncDatumRow,
+) ([]byte, error) {
+	msg := dynamicpb.NewMessage(m.desc)
+	fields := m.desc.Fields()
+	if m.before != nil && beforeRow != nil {
+		before := msg.Mutable(fields.ByNumber(protobufEnvelopeBeforeField)).Message()
+		if err := m.before.setFromRow(before, beforeRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.after != nil && afterRow != nil {
+		after := msg.Mutable(fields.ByNumber(protobufEnvelopeAfterField)).Message()
+		if err := m.after.setFromRow(after, afterRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.updatedField {
+		msg.Set(fields.ByNumber(protobufEnvelopeUpdatedField),
+			protoreflect.ValueOfString(ts.AsOfSystemTime()))
+	}
+	if m.opts.resolvedField {
+		msg.Set(fields.ByNumber(protobufEnvelopeResolvedField),
+			protoreflect.ValueOfString(ts.AsOfSystemTime()))
+	}
+	return protobufMarshalOptions.MarshalAppend(buf, msg)
+}
+
+// makeProtobufFile generates a proto2 file containing the given messages, which
+// may only refer to each other.
+func makeProtobufFile(
+	messages ...*descriptorpb.DescriptorProto,
+) (*descriptorpb.FileDescriptorProto, protoreflect.FileDescriptor, error) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String(messages[0].GetName() + `.proto`),
+		Syntax:      proto.String(`proto2`),
+		MessageType: messages,
+	}
+	fileDesc, err := protodesc.NewFile(file, nil /* resolver */)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, `generating protobuf descriptor for %s`, file.GetName())
+	}
+	return file, fileDesc, nil
+}
+
+// protobufSchemaText renders the given file in the protobuf language, which is
+// how schemas are registered in the Confluent schema registry.
+func protobufSchemaText(file *descriptorpb.FileDescriptorProto) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "syntax = %q;\n", file.GetSyntax())
+	for _, message := range file.MessageType {
+		fmt.Fprintf(&buf, "\nmessage %s {\n", message.GetName())
+		for _, field := range message.Field {
+			label := `optional`
+			if field.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+				label = `repeated`
+			}
+			typ := strings.TrimPrefix(field.GetTypeName(), `.`)
+			if typ == `` {
+				// TYPE_INT64 is int64 in the protobuf language, etc.
+				typ = strings.ToLower(strings.TrimPrefix(field.GetType().String(), `TYPE_`))
+			}
+			fmt.Fprintf(&buf, "  %s %s %s = %d;\n", label, typ, field.GetName(), field.GetNumber())
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
@@ -0,0 +1,262 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"encoding/binary"
+	gojson "encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// protobufToJSON decodes a message encoded by the given encoder for the given
+// table and returns it as JSON. The test schema registry only knows the text of
+// the schemas, so the descriptors are looked up in the caches of the encoder
+// instead.
+func protobufToJSON(
+	t testing.TB, e *confluentProtobufEncoder, tableDesc catalog.TableDescriptor, b []byte,
+) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	require.True(t, len(b) >= 6, `missing header`)
+	require.Equal(t, confluentAvroWireFormatMagic, b[0], `bad magic byte`)
+	require.Equal(t, byte(0), b[5], `bad message indexes`)
+	id := int32(binary.BigEndian.Uint32(b[1:5]))
+
+	var desc protoreflect.MessageDescriptor
+	lookup := func(c *cache.UnorderedCache, key interface{}) {
+		v, ok := c.StealthyGet(key)
+		if !ok {
+			return
+		}
+		switch registered := v.(type) {
+		case confluentRegisteredProtobufData:
+			if registered.registryID == id {
+				desc = registered.message.desc
+			}
+		case confluentRegisteredProtobufEnvelope:
+			if registered.registryID == id {
+				desc = registered.message.desc
+			}
+		}
+	}
+	tableKey := makeTableIDAndVersion(tableDesc.GetID(), tableDesc.GetVersion())
+	lookup(e.keyCache, tableKey)
+	lookup(e.rowCache, tableKey)
+	lookup(e.valueCache, tableIDAndVersionPair{0, tableKey})
+	lookup(e.valueCache, tableIDAndVersionPair{tableKey, tableKey})
+	lookup(e.resolvedCache, tableDesc.GetName())
+	require.NotNil(t, desc, `unknown registry id: %d`, id)
+	return protobufMessageToJSON(t, desc, b[6:])
+}
+
+// protobufMessageToJSON decodes a message of the given type and returns it as
+// JSON. Like with avroToJSON, gojson.Marshal is used because it sorts its object
+// keys.
+func protobufMessageToJSON(t testing.TB, desc protoreflect.MessageDescriptor, b []byte) []byte {
+	msg := dynamicpb.NewMessage(desc)
+	require.NoError(t, proto.Unmarshal(b, msg))
+	json, err := gojson.Marshal(protobufToNative(msg))
+	require.NoError(t, err)
+	return json
+}
+
+func protobufToNative(msg protoreflect.Message) map[string]interface{} {
+	native := make(map[string]interface{})
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			elems := make([]interface{}, list.Len())
+			for i := range elems {
+				elems[i] = list.Get(i).Interface()
+			}
+			native[string(fd.Name())] = elems
+		case fd.Message() != nil:
+			native[string(fd.Name())] = protobufToNative(v.Message())
+		default:
+			native[string(fd.Name())] = v.Interface()
+		}
+		return true
+	})
+	return native
+}
+
+func TestProtobufSchema(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	t.Run(`schema`, func(t *testing.T) {
+		tableDesc, err := parseTableDesc(`CREATE TABLE "☃" (
+			a INT PRIMARY KEY, b STRING, c BYTES, d FLOAT, e BOOL, f DECIMAL, g TIMESTAMPTZ,
+			h INT[], "🍦" STRING[]
+		)`)
+		require.NoError(t, err)
+
+		keyMessage, err := indexToProtobufMessage(tableDesc, tableDesc.GetPrimaryIndex())
+		require.NoError(t, err)
+		require.Equal(t, `syntax = "proto2";
+
+message _u2603_ {
+  optional int64 a = 1;
+}
+`, protobufSchemaText(keyMessage.file))
+
+		rowMessage, err := tableToProtobufMessage(tableDesc, avroSchemaNoSuffix)
+		require.NoError(t, err)
+		require.NoError(t, rowMessage.build())
+		require.Equal(t, `syntax = "proto2";
+
+message _u2603_ {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional bytes c = 3;
+  optional double d = 4;
+  optional bool e = 5;
+  optional string f = 6;
+  optional string g = 7;
+  repeated int64 h = 8;
+  repeated string _u0001f366_ = 9;
+}
+`, protobufSchemaText(rowMessage.file))
+	})
+
+	t.Run(`envelope`, func(t *testing.T) {
+		tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		require.NoError(t, err)
+		before, err := tableToProtobufMessage(tableDesc, `before`)
+		require.NoError(t, err)
+		after, err := tableToProtobufMessage(tableDesc, avroSchemaNoSuffix)
+		require.NoError(t, err)
+		opts := avroEnvelopeOpts{beforeField: true, afterField: true, updatedField: true}
+		envelope, err := envelopeToProtobufMessage(`foo`, opts, before, after)
+		require.NoError(t, err)
+		require.Equal(t, `syntax = "proto2";
+
+message foo_envelope {
+  optional foo_before before = 1;
+  optional foo after = 2;
+  optional string updated = 3;
+}
+
+message foo_before {
+  optional int64 a = 1;
+  optional string b = 2;
+}
+
+message foo {
+  optional int64 a = 1;
+  optional string b = 2;
+}
+`, protobufSchemaText(envelope.file))
+
+		rows, err := parseValues(tableDesc, `VALUES (1, 'a'), (1, NULL)`)
+		require.NoError(t, err)
+		encoded, err := envelope.BinaryFromRow(nil, hlc.Timestamp{WallTime: 1, Logical: 2}, rows[0], rows[1])
+		require.NoError(t, err)
+		require.Equal(t,
+			`{"after":{"a":1},"before":{"a":1,"b":"a"},"updated":"1.0000000002"}`,
+			string(protobufMessageToJSON(t, envelope.desc, encoded)))
+	})
+
+	// This test shows what protobuf value some sql datums map to, for easy
+	// reference.
+	t.Run(`value_goldens`, func(t *testing.T) {
+		goldens := []struct {
+			sqlType string
+			sql     string
+			value   string
+			err     string
+		}{
+			{sqlType: `INT`, sql: `NULL`, value: `{}`},
+			{sqlType: `INT`, sql: `-1`, value: `{"a":-1}`},
+			{sqlType: `BOOL`, sql: `true`, value: `{"a":true}`},
+			{sqlType: `FLOAT`, sql: `1.2`, value: `{"a":1.2}`},
+			{sqlType: `STRING`, sql: `'foo'`, value: `{"a":"foo"}`},
+			{sqlType: `STRING`, sql: `''`, value: `{"a":""}`},
+			{sqlType: `BYTES`, sql: `'foo'`, value: `{"a":"Zm9v"}`},
+			{sqlType: `DECIMAL`, sql: `1.20`, value: `{"a":"1.20"}`},
+			{sqlType: `DATE`, sql: `'2019-01-02'`, value: `{"a":"2019-01-02"}`},
+			{sqlType: `TIMESTAMP`, sql: `'2019-01-02 03:04:05.06'`, value: `{"a":"2019-01-02 03:04:05.06"}`},
+			{sqlType: `TIMESTAMPTZ`, sql: `'2019-01-02 03:04:05'`, value: `{"a":"2019-01-02 03:04:05+00:00"}`},
+			{sqlType: `INTERVAL`, sql: `'1h'`, value: `{"a":"01:00:00"}`},
+			{sqlType: `UUID`,
+				sql:   `'27f4f4c9-e35a-45dd-9b79-5ff0f9b5fbb0'`,
+				value: `{"a":"27f4f4c9-e35a-45dd-9b79-5ff0f9b5fbb0"}`},
+			{sqlType: `JSONB`, sql: `'{"b": 1}'`, value: `{"a":"{\"b\": 1}"}`},
+			{sqlType: `INT[]`, sql: `ARRAY[1, 2]`, value: `{"a":[1,2]}`},
+			{sqlType: `INT[]`, sql: `ARRAY[]`, value: `{}`},
+			{sqlType: `STRING[]`, sql: `ARRAY['b', NULL]`,
+				err: `column a: arrays with NULL elements are not supported with protobuf`},
+		}
+
+		for _, test := range goldens {
+			tableDesc, err := parseTableDesc(
+				`CREATE TABLE foo (pk INT PRIMARY KEY, a ` + test.sqlType + `)`)
+			require.NoError(t, err)
+			// Leave the pk out of the message to keep the goldens short.
+			message, err := makeProtobufDataMessage(`foo`, tableDesc, []int{1})
+			require.NoError(t, err)
+			require.NoError(t, message.build())
+			rows, err := parseValues(tableDesc, `VALUES (1, `+test.sql+`)`)
+			require.NoError(t, err)
+
+			encoded, err := message.BinaryFromRow(nil, rows[0])
+			if test.err != `` {
+				require.EqualError(t, err, test.err)
+				continue
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.value, string(protobufMessageToJSON(t, message.desc, encoded)),
+				`%s %s`, test.sqlType, test.sql)
+		}
+	})
+
+	// The field numbers are the column IDs, so the messages of different
+	// versions of a table can read each other.
+	t.Run(`evolution`, func(t *testing.T) {
+		writerDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		require.NoError(t, err)
+		readerTable := *writerDesc.TableDesc()
+		readerTable.Columns = []descpb.ColumnDescriptor{readerTable.Columns[0], readerTable.Columns[2]}
+		readerTable.Columns[1].Name = `c_renamed`
+		readerDesc := tabledesc.NewImmutable(readerTable)
+
+		writer, err := tableToProtobufMessage(writerDesc, avroSchemaNoSuffix)
+		require.NoError(t, err)
+		require.NoError(t, writer.build())
+		reader, err := tableToProtobufMessage(readerDesc, avroSchemaNoSuffix)
+		require.NoError(t, err)
+		require.NoError(t, reader.build())
+
+		writerRows, err := parseValues(writerDesc, `VALUES (1, 'dropped', 3)`)
+		require.NoError(t, err)
+		encoded, err := writer.BinaryFromRow(nil, writerRows[0])
+		require.NoError(t, err)
+		require.Equal(t, `{"a":1,"c_renamed":3}`, string(protobufMessageToJSON(t, reader.desc, encoded)))
+
+		readerRows, err := parseValues(readerDesc, `VALUES (1, 3)`)
+		require.NoError(t, err)
+		encoded, err = reader.BinaryFromRow(nil, readerRows[0])
+		require.NoError(t, err)
+		require.Equal(t, `{"a":1,"c":3}`, string(protobufMessageToJSON(t, writer.desc, encoded)))
+	})
+}
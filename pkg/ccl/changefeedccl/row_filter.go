@@ -0,0 +1,272 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// rowFilter applies the column list and the WHERE clause of a changefeed to the
+// rows decoded by the rowFetcherCache. The WHERE clause is evaluated against the
+// full row, so it may reference columns that are not emitted.
+//
+// Both are resolved using the column IDs recorded when the changefeed was
+// created, so renaming a referenced column doesn't affect the changefeed, but
+// dropping one is an error.
+type rowFilter struct {
+	details jobspb.ChangefeedDetails
+	evalCtx *tree.EvalContext
+	// filter is the parsed WHERE clause of the changefeed, or nil if it doesn't
+	// have one.
+	filter tree.Expr
+
+	alloc  rowenc.DatumAlloc
+	tables map[idVersion]*filteredTable
+}
+
+// filteredTable is the projection and the WHERE clause of a changefeed,
+// resolved against one version of the watched table.
+type filteredTable struct {
+	desc *tabledesc.Immutable
+	// projected is desc with only the emitted columns, and colIdxs are their
+	// indexes in desc. Both are unset if the changefeed doesn't have a column
+	// list.
+	projected *tabledesc.Immutable
+	colIdxs   []int
+	// filter is unset if the changefeed doesn't have a WHERE clause.
+	filter tree.TypedExpr
+	ivars  filterIVarContainer
+}
+
+// makeRowFilter returns a rowFilter for the column list and the WHERE clause of
+// the given changefeed, or nil if it has neither.
+func makeRowFilter(details jobspb.ChangefeedDetails, evalCtx *tree.EvalContext) (*rowFilter, error) {
+	if len(details.ProjectionColumns) == 0 && details.Filter == `` {
+		return nil, nil
+	}
+	f := &rowFilter{
+		details: details,
+		evalCtx: evalCtx,
+		tables:  make(map[idVersion]*filteredTable),
+	}
+	if details.Filter != `` {
+		var err error
+		if f.filter, err = parser.ParseExpr(details.Filter); err != nil {
+			return nil, errors.Wrapf(err, `parsing the WHERE clause of the changefeed`)
+		}
+	}
+	return f, nil
+}
+
+// apply filters and projects the given row in place. It returns false if the
+// row should not be emitted.
+//
+// Deletions only have their primary key set, so the WHERE clause can't be
+// evaluated against them. They are emitted unless the previous value of the
+// row is known (the `diff` option) and didn't match. Likewise, an update that
+// makes a row stop matching is emitted as a deletion if the previous value of
+// the row is known to have matched, so that consumers don't keep a stale copy
+// of it.
+func (f *rowFilter) apply(ctx context.Context, row *encodeRow) (bool, error) {
+	t, err := f.forTable(ctx, row.tableDesc)
+	if err != nil {
+		return false, err
+	}
+	var prevT *filteredTable
+	if row.prevDatums != nil {
+		if prevT, err = f.forTable(ctx, row.prevTableDesc); err != nil {
+			return false, err
+		}
+	}
+
+	if f.filter != nil {
+		prevMatches := false
+		if prevT != nil && !row.prevDeleted {
+			if prevMatches, err = prevT.matches(f.evalCtx, row.prevDatums); err != nil {
+				return false, err
+			}
+		}
+		if row.deleted {
+			if prevT != nil && !prevMatches {
+				return false, nil
+			}
+		} else {
+			matches, err := t.matches(f.evalCtx, row.datums)
+			if err != nil {
+				return false, err
+			}
+			if !matches {
+				if !prevMatches {
+					return false, nil
+				}
+				row.deleted = true
+			}
+		}
+	}
+
+	if t.projected != nil {
+		row.tableDesc, row.datums = t.project(row.datums)
+		if prevT != nil {
+			row.prevTableDesc, row.prevDatums = prevT.project(row.prevDatums)
+		}
+	}
+	return true, nil
+}
+
+// forTable returns the projection and the WHERE clause resolved against the
+// given version of the watched table.
+func (f *rowFilter) forTable(
+	ctx context.Context, desc catalog.TableDescriptor,
+) (*filteredTable, error) {
+	tableDesc, ok := desc.(*tabledesc.Immutable)
+	if !ok {
+		return nil, errors.AssertionFailedf(`unexpected table descriptor type %T`, desc)
+	}
+	key := idVersion{id: tableDesc.ID, version: tableDesc.Version}
+	// As in the rowFetcherCache, the version of the table doesn't change when
+	// one of its user defined types does, so check them too.
+	if t, ok := f.tables[key]; ok && tableDesc.UserDefinedTypeColsHaveSameVersion(t.desc) {
+		return t, nil
+	}
+
+	t := &filteredTable{desc: tableDesc}
+	t.ivars.cols = tableDesc.Columns
+	t.ivars.alloc = &f.alloc
+	colIdxByID := tableDesc.ColumnIdxMap()
+
+	if len(f.details.ProjectionColumns) > 0 {
+		var emitted util.FastIntSet
+		for _, id := range tableDesc.PrimaryIndex.ColumnIDs {
+			emitted.Add(int(id))
+		}
+		for name, id := range f.details.ProjectionColumns {
+			if _, ok := colIdxByID[id]; !ok {
+				return nil, errors.Errorf(
+					`column %q in the column list of the changefeed was dropped from %s`, name, tableDesc.Name)
+			}
+			emitted.Add(int(id))
+		}
+		projected := *tableDesc.TableDesc()
+		projected.Columns = make([]descpb.ColumnDescriptor, 0, emitted.Len())
+		for colIdx := range tableDesc.Columns {
+			if col := &tableDesc.Columns[colIdx]; emitted.Contains(int(col.ID)) {
+				projected.Columns = append(projected.Columns, *col)
+				t.colIdxs = append(t.colIdxs, colIdx)
+			}
+		}
+		t.projected = tabledesc.NewImmutable(projected)
+	}
+
+	if f.filter != nil {
+		h := tree.MakeIndexedVarHelper(&t.ivars, len(tableDesc.Columns))
+		expr, err := tree.SimpleVisit(f.filter, func(expr tree.Expr) (bool, tree.Expr, error) {
+			vBase, ok := expr.(tree.VarName)
+			if !ok {
+				return true, expr, nil
+			}
+			v, err := vBase.NormalizeVarName()
+			if err != nil {
+				return false, nil, err
+			}
+			c, ok := v.(*tree.ColumnItem)
+			if !ok {
+				return false, nil, errors.AssertionFailedf(
+					`unexpected %T in the WHERE clause of the changefeed`, v)
+			}
+			name := string(c.ColumnName)
+			id, ok := f.details.FilterColumns[name]
+			if !ok {
+				return false, nil, errors.AssertionFailedf(
+					`unknown column %q in the WHERE clause of the changefeed`, name)
+			}
+			colIdx, ok := colIdxByID[id]
+			if !ok {
+				return false, nil, errors.Errorf(
+					`column %q in the WHERE clause of the changefeed was dropped from %s`, name, tableDesc.Name)
+			}
+			return false, h.IndexedVar(colIdx), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		semaCtx := tree.MakeSemaContext()
+		semaCtx.IVarContainer = &t.ivars
+		if t.filter, err = tree.TypeCheck(ctx, expr, &semaCtx, types.Bool); err != nil {
+			return nil, errors.Wrapf(err, `type checking the WHERE clause of the changefeed for %s`,
+				tableDesc.Name)
+		}
+	}
+
+	f.tables[key] = t
+	return t, nil
+}
+
+// matches returns whether the given row satisfies the WHERE clause.
+func (t *filteredTable) matches(evalCtx *tree.EvalContext, row rowenc.EncDatumRow) (bool, error) {
+	if t.filter == nil {
+		return true, nil
+	}
+	t.ivars.row = row
+	evalCtx.PushIVarContainer(&t.ivars)
+	defer evalCtx.PopIVarContainer()
+	return schemaexpr.RunFilter(t.filter, evalCtx)
+}
+
+// project returns the descriptor and the datums of the emitted columns of the
+// given row.
+func (t *filteredTable) project(
+	row rowenc.EncDatumRow,
+) (catalog.TableDescriptor, rowenc.EncDatumRow) {
+	projected := make(rowenc.EncDatumRow, len(t.colIdxs))
+	for i, colIdx := range t.colIdxs {
+		projected[i] = row[colIdx]
+	}
+	return t.projected, projected
+}
+
+// filterIVarContainer resolves the columns referenced by the WHERE clause of a
+// changefeed to the datums of the row being filtered.
+type filterIVarContainer struct {
+	cols  []descpb.ColumnDescriptor
+	row   rowenc.EncDatumRow
+	alloc *rowenc.DatumAlloc
+}
+
+var _ tree.IndexedVarContainer = &filterIVarContainer{}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *filterIVarContainer) IndexedVarEval(idx int, _ *tree.EvalContext) (tree.Datum, error) {
+	if err := c.row[idx].EnsureDecoded(c.cols[idx].Type, c.alloc); err != nil {
+		return nil, err
+	}
+	return c.row[idx].Datum, nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *filterIVarContainer) IndexedVarResolvedType(idx int) *types.T {
+	return c.cols[idx].Type
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *filterIVarContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(c.cols[idx].Name)
+	return &n
+}
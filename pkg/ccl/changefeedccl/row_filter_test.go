@@ -0,0 +1,190 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(ctx)
+
+	parsed, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+	require.NoError(t, err)
+	tableDesc := tabledesc.NewImmutable(*parsed.TableDesc())
+	// CREATE CHANGEFEED FOR foo (b) WHERE c > 15
+	details := jobspb.ChangefeedDetails{
+		ProjectionColumns: map[string]descpb.ColumnID{`b`: 2},
+		Filter:            `c > 15`,
+		FilterColumns:     map[string]descpb.ColumnID{`c`: 3},
+	}
+
+	// rowString renders the emitted columns of a row, or `skipped`.
+	rowString := func(t *testing.T, f *rowFilter, row encodeRow) string {
+		emit, err := f.apply(ctx, &row)
+		require.NoError(t, err)
+		if !emit {
+			return `skipped`
+		}
+		var s string
+		if row.deleted {
+			s = `deleted `
+		}
+		s += datumsString(row.datums)
+		if row.prevDatums != nil {
+			s += ` prev ` + datumsString(row.prevDatums)
+		}
+		return s
+	}
+	values := func(t *testing.T, desc *tabledesc.Immutable, sql string) rowenc.EncDatumRow {
+		rows, err := parseValues(desc, `VALUES `+sql)
+		require.NoError(t, err)
+		return rows[0]
+	}
+
+	t.Run(`no_diff`, func(t *testing.T) {
+		f, err := makeRowFilter(details, evalCtx)
+		require.NoError(t, err)
+		tests := []struct {
+			row      string
+			deleted  bool
+			expected string
+		}{
+			{row: `(1, 'a', 20)`, expected: `(1, 'a')`},
+			{row: `(1, 'a', 10)`, expected: `skipped`},
+			{row: `(1, 'a', NULL)`, expected: `skipped`},
+			// Deletions only have their primary key, so they are always emitted.
+			{row: `(1, NULL, NULL)`, deleted: true, expected: `deleted (1, NULL)`},
+		}
+		for _, test := range tests {
+			row := encodeRow{
+				datums:    values(t, tableDesc, test.row),
+				deleted:   test.deleted,
+				tableDesc: tableDesc,
+			}
+			require.Equal(t, test.expected, rowString(t, f, row), test.row)
+		}
+	})
+
+	t.Run(`diff`, func(t *testing.T) {
+		f, err := makeRowFilter(details, evalCtx)
+		require.NoError(t, err)
+		tests := []struct {
+			row         string
+			deleted     bool
+			prevRow     string
+			prevDeleted bool
+			expected    string
+		}{
+			{row: `(1, 'a', 20)`, prevRow: `(1, NULL, NULL)`, prevDeleted: true,
+				expected: `(1, 'a') prev (1, NULL)`},
+			{row: `(1, 'b', 30)`, prevRow: `(1, 'a', 20)`,
+				expected: `(1, 'b') prev (1, 'a')`},
+			{row: `(1, 'a', 30)`, prevRow: `(1, 'a', 10)`,
+				expected: `(1, 'a') prev (1, 'a')`},
+			{row: `(1, 'a', 10)`, prevRow: `(1, 'a', 5)`, expected: `skipped`},
+			// A row which stops matching is emitted as a deletion.
+			{row: `(1, 'a', 10)`, prevRow: `(1, 'a', 20)`,
+				expected: `deleted (1, 'a') prev (1, 'a')`},
+			{row: `(1, NULL, NULL)`, deleted: true, prevRow: `(1, 'a', 20)`,
+				expected: `deleted (1, NULL) prev (1, 'a')`},
+			{row: `(1, NULL, NULL)`, deleted: true, prevRow: `(1, 'a', 10)`,
+				expected: `skipped`},
+			{row: `(1, NULL, NULL)`, deleted: true, prevRow: `(1, NULL, NULL)`, prevDeleted: true,
+				expected: `skipped`},
+		}
+		for _, test := range tests {
+			row := encodeRow{
+				datums:        values(t, tableDesc, test.row),
+				deleted:       test.deleted,
+				tableDesc:     tableDesc,
+				prevDatums:    values(t, tableDesc, test.prevRow),
+				prevDeleted:   test.prevDeleted,
+				prevTableDesc: tableDesc,
+			}
+			require.Equal(t, test.expected, rowString(t, f, row), test.row)
+		}
+	})
+
+	t.Run(`schema_changes`, func(t *testing.T) {
+		f, err := makeRowFilter(details, evalCtx)
+		require.NoError(t, err)
+
+		// Columns are resolved by ID, so renames don't matter.
+		renamedTable := *tableDesc.TableDesc()
+		renamedTable.Version++
+		renamedTable.Columns = append([]descpb.ColumnDescriptor(nil), renamedTable.Columns...)
+		renamedTable.Columns[1].Name = `b_renamed`
+		renamedTable.Columns[2].Name = `c_renamed`
+		renamedDesc := tabledesc.NewImmutable(renamedTable)
+		row := encodeRow{datums: values(t, renamedDesc, `(1, 'a', 20)`), tableDesc: renamedDesc}
+		require.Equal(t, `(1, 'a')`, rowString(t, f, row))
+		renamed, err := f.forTable(ctx, renamedDesc)
+		require.NoError(t, err)
+		require.Equal(t, `b_renamed`, renamed.projected.GetPublicColumns()[1].Name)
+
+		// New columns aren't emitted.
+		addedTable := *tableDesc.TableDesc()
+		addedTable.Version += 2
+		addedTable.Columns = append(addedTable.Columns[:3:3], descpb.ColumnDescriptor{
+			ID: 4, Name: `d`, Type: tableDesc.Columns[0].Type, Nullable: true,
+		})
+		addedDesc := tabledesc.NewImmutable(addedTable)
+		row = encodeRow{datums: values(t, addedDesc, `(1, 'a', 20, 4)`), tableDesc: addedDesc}
+		require.Equal(t, `(1, 'a')`, rowString(t, f, row))
+
+		droppedTable := *tableDesc.TableDesc()
+		droppedTable.Version += 3
+		droppedTable.Columns = droppedTable.Columns[:2:2]
+		droppedDesc := tabledesc.NewImmutable(droppedTable)
+		row = encodeRow{datums: values(t, droppedDesc, `(1, 'a')`), tableDesc: droppedDesc}
+		_, err = f.apply(ctx, &row)
+		require.EqualError(t, err,
+			`column "c" in the WHERE clause of the changefeed was dropped from foo`)
+
+		f, err = makeRowFilter(jobspb.ChangefeedDetails{
+			ProjectionColumns: map[string]descpb.ColumnID{`c`: 3},
+		}, evalCtx)
+		require.NoError(t, err)
+		row = encodeRow{datums: values(t, droppedDesc, `(1, 'a')`), tableDesc: droppedDesc}
+		_, err = f.apply(ctx, &row)
+		require.EqualError(t, err,
+			`column "c" in the column list of the changefeed was dropped from foo`)
+	})
+}
+
+func datumsString(row rowenc.EncDatumRow) string {
+	var buf bytes.Buffer
+	buf.WriteString(`(`)
+	for i := range row {
+		if i > 0 {
+			buf.WriteString(`, `)
+		}
+		buf.WriteString(tree.AsString(row[i].Datum))
+	}
+	buf.WriteString(`)`)
+	return buf.String()
+}
@@ -172,6 +172,22 @@ func getSink(
 		makeSink = func() (Sink, error) {
 			return makeKafkaSink(cfg, u.Host, targets)
 		}
+	case u.Scheme == changefeedbase.SinkSchemeWebhookHTTPS:
+		// The rows are embedded in the JSON body of the requests.
+		if format := changefeedbase.FormatType(opts[changefeedbase.OptFormat]); format != `` &&
+			format != changefeedbase.OptFormatJSON {
+			return nil, errors.Errorf(`this sink is only usable with %s=%s`,
+				changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+		}
+		cfg, err := makeWebhookSinkConfig(q)
+		if err != nil {
+			return nil, err
+		}
+		u.Scheme = `https`
+		u.RawQuery = ``
+		makeSink = func() (Sink, error) {
+			return makeWebhookSink(ctx, u.String(), cfg, targets)
+		}
 	case isCloudStorageSink(u):
 		fileSizeParam := q.Get(changefeedbase.SinkParamFileSize)
 		q.Del(changefeedbase.SinkParamFileSize)
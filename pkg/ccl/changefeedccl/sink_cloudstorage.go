@@ -161,9 +161,8 @@ func (f *cloudStorageSinkFile) Write(p []byte) (int, error) {
 // by a given `<sink_id>` and <session_id> is a unique identifying string for the job
 // session running the `changeAggregator` that owns this sink.
 //
-// `<ext>` implies the format of the file: currently the only option is
-// `ndjson`, which means a text file conforming to the "Newline Delimited JSON"
-// spec.
+// `<ext>` implies the format of the file: `ndjson`, which means a text file
+// conforming to the "Newline Delimited JSON" spec, or `csv` for the csv format.
 //
 // This naming convention of data files is carefully chosen in order to preserve
 // the external ordering guarantees of CDC. Naming output files in this fashion
@@ -329,7 +328,8 @@ func makeCloudStorageSink(
 		s.dataFilePartition = timestampOracle.inclusiveLowerBoundTS().GoTime().Format(s.partitionFormat)
 	}
 
-	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	format := changefeedbase.FormatType(opts[changefeedbase.OptFormat])
+	switch format {
 	case changefeedbase.OptFormatJSON:
 		// TODO(dan): It seems like these should be on the encoder, but that
 		// would require a bit of refactoring.
@@ -338,19 +338,29 @@ func makeCloudStorageSink(
 			_, err := w.Write([]byte{'\n'})
 			return err
 		}
+	case changefeedbase.OptFormatCSV:
+		s.ext = `.csv`
+		s.recordDelimFn = func(w io.Writer) error {
+			_, err := w.Write([]byte{'\n'})
+			return err
+		}
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
 
-	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
-	case changefeedbase.OptEnvelopeWrapped:
+	// CSV records are the rows themselves, which contain their key. The other
+	// formats need the key in a wrapped value to be able to represent
+	// deletions.
+	switch envelope := changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]); {
+	case envelope == changefeedbase.OptEnvelopeWrapped && format != changefeedbase.OptFormatCSV:
+	case envelope == changefeedbase.OptEnvelopeRow && format == changefeedbase.OptFormatCSV:
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
 	}
 
-	if _, ok := opts[changefeedbase.OptKeyInValue]; !ok {
+	if _, ok := opts[changefeedbase.OptKeyInValue]; !ok && format != changefeedbase.OptFormatCSV {
 		return nil, errors.Errorf(`this sink requires the WITH %s option`, changefeedbase.OptKeyInValue)
 	}
 
@@ -397,6 +407,12 @@ func (s *cloudStorageSink) EmitRow(
 		return errors.New(`cannot EmitRow on a closed sink`)
 	}
 
+	if value == nil {
+		// Deletions have no value with the csv format, which has no way to
+		// represent them.
+		return nil
+	}
+
 	file := s.getOrCreateFile(table.GetName(), table.GetVersion())
 
 	// TODO(dan): Memory monitoring for this
@@ -136,6 +136,50 @@ func TestCloudStorageSink(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, `{"resolved":"5.0000000000"}`, string(resolvedFile))
 	})
+	t.Run(`csv`, func(t *testing.T) {
+		t1 := tabledesc.NewImmutable(descpb.TableDescriptor{Name: `t1`})
+		testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+		sf := span.MakeFrontier(testSpan)
+		timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+		csvOpts := map[string]string{
+			changefeedbase.OptFormat:   string(changefeedbase.OptFormatCSV),
+			changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeRow),
+		}
+		sinkDir := `csv`
+		s, err := makeCloudStorageSink(
+			ctx, `nodelocal://0/`+sinkDir, 1, unlimitedFileSize,
+			settings, csvOpts, timestampOracle, externalStorageFromURI, user,
+		)
+		require.NoError(t, err)
+
+		// Deletions have no value and are skipped.
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`1,a`), ts(1)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, nil, ts(2)))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`2,b`), ts(3)))
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, []string{
+			"1,a\n2,b\n",
+		}, slurpDir(t, sinkDir))
+		files, err := filepath.Glob(filepath.Join(dir, sinkDir, `1970-01-01`, `*.csv`))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		require.NoError(t, s.Close())
+
+		// The other envelopes and formats need the key in the value.
+		csvOpts[changefeedbase.OptEnvelope] = string(changefeedbase.OptEnvelopeWrapped)
+		_, err = makeCloudStorageSink(
+			ctx, `nodelocal://0/`+sinkDir, 1, unlimitedFileSize,
+			settings, csvOpts, timestampOracle, externalStorageFromURI, user,
+		)
+		require.EqualError(t, err, `this sink is incompatible with envelope=wrapped`)
+		_, err = makeCloudStorageSink(
+			ctx, `nodelocal://0/`+sinkDir, 1, unlimitedFileSize, settings, map[string]string{
+				changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+				changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeRow),
+			}, timestampOracle, externalStorageFromURI, user,
+		)
+		require.EqualError(t, err, `this sink is incompatible with envelope=row`)
+	})
 	t.Run(`single-node`, func(t *testing.T) {
 		before := opts[changefeedbase.OptCompression]
 		// Compression codecs include buffering that interferes with other tests,
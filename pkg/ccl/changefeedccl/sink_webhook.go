@@ -0,0 +1,388 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// webhookSinkDefaultBatchSize is the number of rows sent in each request
+	// when no batch size is configured.
+	webhookSinkDefaultBatchSize = 100
+	// webhookSinkDefaultMaxRetries is the number of times a failed request is
+	// retried when no maximum is configured.
+	webhookSinkDefaultMaxRetries = 3
+	// webhookSinkTimeout bounds the duration of a single request.
+	webhookSinkTimeout = 30 * time.Second
+)
+
+type webhookSinkConfig struct {
+	batchSize     int
+	flushInterval time.Duration
+	retryOpts     retry.Options
+	maxRetries    int
+	caCert        []byte
+	clientCert    []byte
+	clientKey     []byte
+	skipTLSVerify bool
+}
+
+// makeWebhookSinkConfig parses the configuration of a webhook sink out of the
+// query parameters of its URI, removing the parameters it consumes.
+func makeWebhookSinkConfig(q url.Values) (webhookSinkConfig, error) {
+	cfg := webhookSinkConfig{
+		batchSize: webhookSinkDefaultBatchSize,
+		retryOpts: retry.Options{
+			InitialBackoff: 500 * time.Millisecond,
+			MaxBackoff:     10 * time.Second,
+		},
+		maxRetries: webhookSinkDefaultMaxRetries,
+	}
+
+	if batchSize := q.Get(changefeedbase.SinkParamBatchSize); batchSize != `` {
+		n, err := strconv.Atoi(batchSize)
+		if err != nil || n <= 0 {
+			return cfg, errors.Errorf(`param %s must be a positive integer: %s`,
+				changefeedbase.SinkParamBatchSize, batchSize)
+		}
+		cfg.batchSize = n
+	}
+	q.Del(changefeedbase.SinkParamBatchSize)
+	if flushInterval := q.Get(changefeedbase.SinkParamFlushInterval); flushInterval != `` {
+		d, err := time.ParseDuration(flushInterval)
+		if err != nil {
+			return cfg, errors.Wrapf(err, `param %s must be a duration`, changefeedbase.SinkParamFlushInterval)
+		}
+		if d < 0 {
+			return cfg, errors.Errorf(`negative durations are not accepted: %s='%s'`,
+				changefeedbase.SinkParamFlushInterval, flushInterval)
+		}
+		cfg.flushInterval = d
+	}
+	q.Del(changefeedbase.SinkParamFlushInterval)
+	if maxRetries := q.Get(changefeedbase.SinkParamMaxRetries); maxRetries != `` {
+		n, err := strconv.Atoi(maxRetries)
+		if err != nil || n < 0 {
+			return cfg, errors.Errorf(`param %s must be a non-negative integer: %s`,
+				changefeedbase.SinkParamMaxRetries, maxRetries)
+		}
+		cfg.maxRetries = n
+	}
+	q.Del(changefeedbase.SinkParamMaxRetries)
+
+	for _, p := range []struct {
+		param string
+		dest  *[]byte
+	}{
+		{changefeedbase.SinkParamCACert, &cfg.caCert},
+		{changefeedbase.SinkParamClientCert, &cfg.clientCert},
+		{changefeedbase.SinkParamClientKey, &cfg.clientKey},
+	} {
+		if v := q.Get(p.param); v != `` {
+			var err error
+			if *p.dest, err = base64.StdEncoding.DecodeString(v); err != nil {
+				return cfg, errors.Errorf(`param %s must be base 64 encoded: %s`, p.param, err)
+			}
+		}
+		q.Del(p.param)
+	}
+	if cfg.clientCert != nil && cfg.clientKey == nil {
+		return cfg, errors.Errorf(`%s requires %s to be set`,
+			changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+	} else if cfg.clientKey != nil && cfg.clientCert == nil {
+		return cfg, errors.Errorf(`%s requires %s to be set`,
+			changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+
+	if skipVerify := q.Get(changefeedbase.SinkParamSkipTLSVerify); skipVerify != `` {
+		var err error
+		if cfg.skipTLSVerify, err = strconv.ParseBool(skipVerify); err != nil {
+			return cfg, errors.Errorf(`param %s must be a bool: %s`, changefeedbase.SinkParamSkipTLSVerify, err)
+		}
+	}
+	q.Del(changefeedbase.SinkParamSkipTLSVerify)
+	return cfg, nil
+}
+
+// webhookMessage is the representation of an emitted row in the body of the
+// requests sent by the webhook sink.
+type webhookMessage struct {
+	Topic string          `json:"topic"`
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// webhookPayload is the body of the requests sent by the webhook sink for a
+// batch of rows.
+type webhookPayload struct {
+	Payload []webhookMessage `json:"payload"`
+	Length  int              `json:"length"`
+}
+
+// webhookSink emits to an HTTPS endpoint, sending batches of rows in the body
+// of POST requests as a JSON object with a `payload` array of the emitted rows
+// and its `length`. A batch is sent when it reaches the configured size, when
+// the configured flush interval elapses, and when the sink is flushed.
+// Resolved timestamps are sent in a request of their own, with the JSON
+// encoding of the resolved timestamp as body, once every row emitted before
+// them has been delivered. Failed requests are retried with an exponential
+// backoff.
+//
+// It is not concurrency-safe; all calls to Emit and Flush should be from the
+// same goroutine.
+type webhookSink struct {
+	cfg    webhookSinkConfig
+	url    string
+	client *http.Client
+	topics map[string]struct{}
+
+	// cancel interrupts the worker goroutine sending batches at the configured
+	// flush interval.
+	cancel func()
+	worker sync.WaitGroup
+
+	// sendMu serializes the requests sent to the endpoint, so that rows and
+	// resolved timestamps are delivered in the order they were emitted.
+	sendMu syncutil.Mutex
+
+	// Only synchronized between the client goroutine and the worker goroutine.
+	mu struct {
+		syncutil.Mutex
+		// rows are the rows emitted since the last batch was sent.
+		rows []webhookMessage
+		// flushErr is the error of a batch sent by the worker goroutine. It is
+		// returned by the next call to the sink.
+		flushErr error
+	}
+}
+
+var _ Sink = &webhookSink{}
+
+func makeWebhookSink(
+	ctx context.Context, endpoint string, cfg webhookSinkConfig, targets jobspb.ChangefeedTargets,
+) (Sink, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.skipTLSVerify}
+	if cfg.caCert != nil {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(cfg.caCert) {
+			return nil, errors.Errorf(`invalid %s: no certificate found`, changefeedbase.SinkParamCACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if cfg.clientCert != nil {
+		cert, err := tls.X509KeyPair(cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			return nil, errors.Errorf(`invalid client certificate data provided: %s`, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	sink := &webhookSink{
+		cfg: cfg,
+		url: endpoint,
+		client: &http.Client{
+			Timeout:   webhookSinkTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		topics: make(map[string]struct{}),
+	}
+	for _, t := range targets {
+		sink.topics[t.StatementTimeName] = struct{}{}
+	}
+
+	ctx, sink.cancel = context.WithCancel(ctx)
+	if cfg.flushInterval > 0 {
+		sink.worker.Add(1)
+		go sink.workerLoop(ctx)
+	}
+	return sink, nil
+}
+
+func (s *webhookSink) workerLoop(ctx context.Context) {
+	defer s.worker.Done()
+
+	ticker := time.NewTicker(s.cfg.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sendRows(ctx); err != nil {
+				s.mu.Lock()
+				if s.mu.flushErr == nil {
+					s.mu.flushErr = err
+				}
+				s.mu.Unlock()
+			}
+		}
+	}
+}
+
+// takeFlushErr returns and clears the error of a batch sent by the worker
+// goroutine, if any.
+func (s *webhookSink) takeFlushErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.mu.flushErr
+	s.mu.flushErr = nil
+	return err
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(
+	ctx context.Context, table catalog.TableDescriptor, key, value []byte, updated hlc.Timestamp,
+) error {
+	topic := table.GetName()
+	if _, ok := s.topics[topic]; !ok {
+		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+	}
+	if err := s.takeFlushErr(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.mu.rows = append(s.mu.rows, webhookMessage{
+		Topic: topic,
+		Key:   append(json.RawMessage(nil), key...),
+		Value: append(json.RawMessage(nil), value...),
+	})
+	full := len(s.mu.rows) >= s.cfg.batchSize
+	s.mu.Unlock()
+
+	if full {
+		return s.sendRows(ctx)
+	}
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	// Every row emitted before the resolved timestamp must be delivered before
+	// it.
+	if err := s.Flush(ctx); err != nil {
+		return err
+	}
+	var noTopic string
+	payload, err := encoder.EncodeResolvedTimestamp(ctx, noTopic, resolved)
+	if err != nil {
+		return err
+	}
+
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.send(ctx, payload)
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context) error {
+	if err := s.sendRows(ctx); err != nil {
+		return err
+	}
+	return s.takeFlushErr()
+}
+
+// Close implements the Sink interface.
+func (s *webhookSink) Close() error {
+	s.cancel()
+	s.worker.Wait()
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// sendRows sends the rows emitted since the last batch, if any, in a single
+// request.
+func (s *webhookSink) sendRows(ctx context.Context) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	rows := s.mu.rows
+	s.mu.rows = nil
+	s.mu.Unlock()
+	if len(rows) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(webhookPayload{Payload: rows, Length: len(rows)})
+	if err != nil {
+		return err
+	}
+	if log.V(2) {
+		log.Infof(ctx, "sending %d rows to webhook sink", len(rows))
+	}
+	return s.send(ctx, body)
+}
+
+// send posts the given body to the endpoint, retrying failed requests. The
+// caller must hold sendMu.
+func (s *webhookSink) send(ctx context.Context, body []byte) error {
+	var err error
+	for r, attempt := retry.StartWithCtx(ctx, s.cfg.retryOpts), 0; r.Next(); attempt++ {
+		if err = s.post(ctx, body); err == nil {
+			return nil
+		}
+		if attempt >= s.cfg.maxRetries {
+			break
+		}
+		if log.V(1) {
+			log.Infof(ctx, "retrying failed webhook sink request: %v", err)
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+func (s *webhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(`Content-Type`, `application/json`)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		// Include the beginning of the response, which usually explains the
+		// failure, in the error.
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return errors.Errorf(`webhook sink request failed: %s: %s`, resp.Status, msg)
+	}
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	return err
+}
@@ -0,0 +1,227 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookTestServer is an HTTPS endpoint recording the bodies of the requests
+// it receives. It fails the next requests while failures is positive.
+type webhookTestServer struct {
+	*httptest.Server
+	mu struct {
+		syncutil.Mutex
+		bodies   []string
+		failures int
+	}
+}
+
+func makeWebhookTestServer() *webhookTestServer {
+	s := &webhookTestServer{}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.mu.failures > 0 {
+			s.mu.failures--
+			http.Error(w, `boom`, http.StatusServiceUnavailable)
+			return
+		}
+		s.mu.bodies = append(s.mu.bodies, string(body))
+	}))
+	return s
+}
+
+// sinkURI returns the URI of a webhook sink trusting the certificate of the
+// server, with the given additional query parameters.
+func (s *webhookTestServer) sinkURI(params string) string {
+	caCert := pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: s.Certificate().Raw})
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf(`%s://%s/feed?%s=%s&%s`, changefeedbase.SinkSchemeWebhookHTTPS, u.Host,
+		changefeedbase.SinkParamCACert, url.QueryEscape(base64.StdEncoding.EncodeToString(caCert)),
+		params)
+}
+
+func (s *webhookTestServer) failNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.failures = n
+}
+
+// popBodies returns the bodies received since the last call.
+func (s *webhookTestServer) popBodies() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bodies := s.mu.bodies
+	s.mu.bodies = nil
+	return bodies
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	table := tabledesc.NewImmutable(descpb.TableDescriptor{Name: `t`})
+	targets := jobspb.ChangefeedTargets{table.GetID(): {StatementTimeName: `t`}}
+	settings := cluster.MakeTestingClusterSettings()
+	makeSink := func(uri string, opts map[string]string) (Sink, error) {
+		var nilOracle timestampLowerBoundOracle
+		return getSink(ctx, uri, 1 /* nodeID */, opts, targets, settings, nilOracle,
+			nil /* makeExternalStorageFromURI */, security.RootUser)
+	}
+	encoder, err := makeJSONEncoder(map[string]string{
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	})
+	require.NoError(t, err)
+
+	srv := makeWebhookTestServer()
+	defer srv.Close()
+
+	t.Run(`batches`, func(t *testing.T) {
+		s, err := makeSink(srv.sinkURI(`batch_size=2`), nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		require.NoError(t, s.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"after": {"a": 1}}`), zeroTS))
+		require.Empty(t, srv.popBodies())
+		require.NoError(t, s.EmitRow(ctx, table, []byte(`[2]`), []byte(`{"after": null}`), zeroTS))
+		require.Equal(t, []string{
+			`{"payload":[{"topic":"t","key":[1],"value":{"after":{"a":1}}},` +
+				`{"topic":"t","key":[2],"value":{"after":null}}],"length":2}`,
+		}, srv.popBodies())
+
+		// Flushing sends the partial batch, and nothing when there are no rows.
+		require.NoError(t, s.EmitRow(ctx, table, []byte(`[3]`), []byte(`{"after": {"a": 3}}`), zeroTS))
+		require.NoError(t, s.Flush(ctx))
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, []string{
+			`{"payload":[{"topic":"t","key":[3],"value":{"after":{"a":3}}}],"length":1}`,
+		}, srv.popBodies())
+
+		// Resolved timestamps are sent after the rows emitted before them.
+		resolved := hlc.Timestamp{WallTime: 1, Logical: 2}
+		expected, err := encoder.EncodeResolvedTimestamp(ctx, ``, resolved)
+		require.NoError(t, err)
+		require.NoError(t, s.EmitRow(ctx, table, []byte(`[4]`), []byte(`{"after": {"a": 4}}`), zeroTS))
+		require.NoError(t, s.EmitResolvedTimestamp(ctx, encoder, resolved))
+		require.Equal(t, []string{
+			`{"payload":[{"topic":"t","key":[4],"value":{"after":{"a":4}}}],"length":1}`,
+			string(expected),
+		}, srv.popBodies())
+
+		require.EqualError(t, s.EmitRow(ctx, tabledesc.NewImmutable(descpb.TableDescriptor{Name: `u`}),
+			nil, nil, zeroTS), `cannot emit to undeclared topic: u`)
+	})
+
+	t.Run(`retries`, func(t *testing.T) {
+		s, err := makeSink(srv.sinkURI(`batch_size=1&max_retries=2`), nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		srv.failNext(2)
+		require.NoError(t, s.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+		require.Equal(t, []string{
+			`{"payload":[{"topic":"t","key":[1],"value":{}}],"length":1}`,
+		}, srv.popBodies())
+
+		srv.failNext(3)
+		err = s.EmitRow(ctx, table, []byte(`[2]`), []byte(`{}`), zeroTS)
+		require.True(t, testutils.IsError(err, `503 Service Unavailable: boom`), `%+v`, err)
+		require.Empty(t, srv.popBodies())
+	})
+
+	t.Run(`flush interval`, func(t *testing.T) {
+		s, err := makeSink(srv.sinkURI(`flush_interval=10ms`), nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		require.NoError(t, s.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+		testutils.SucceedsSoon(t, func() error {
+			bodies := srv.popBodies()
+			if len(bodies) == 0 {
+				return errors.New(`no request received yet`)
+			}
+			require.Equal(t, []string{
+				`{"payload":[{"topic":"t","key":[1],"value":{}}],"length":1}`,
+			}, bodies)
+			return nil
+		})
+	})
+
+	t.Run(`untrusted certificate`, func(t *testing.T) {
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		s, err := makeSink(`webhook-https://`+u.Host+`?max_retries=0`, nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		require.NoError(t, s.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+		require.True(t, testutils.IsError(s.Flush(ctx), `certificate`))
+
+		insecure, err := makeSink(`webhook-https://`+u.Host+`?insecure_tls_skip_verify=true`, nil)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, insecure.Close()) }()
+		require.NoError(t, insecure.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+		require.NoError(t, insecure.Flush(ctx))
+		require.Len(t, srv.popBodies(), 1)
+	})
+
+	t.Run(`invalid configuration`, func(t *testing.T) {
+		for _, tc := range []struct {
+			params string
+			opts   map[string]string
+			err    string
+		}{
+			{params: `batch_size=0`, err: `param batch_size must be a positive integer`},
+			{params: `flush_interval=-1s`, err: `negative durations are not accepted`},
+			{params: `max_retries=x`, err: `param max_retries must be a non-negative integer`},
+			{params: `client_cert=Zm9v`, err: `client_cert requires client_key to be set`},
+			{params: `insecure_tls_skip_verify=maybe`, err: `param insecure_tls_skip_verify must be a bool`},
+			{params: `topic_prefix=foo`, err: `unknown sink query parameter: topic_prefix`},
+			{
+				opts: map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatAvro)},
+				err:  `this sink is only usable with format=json`,
+			},
+		} {
+			_, err := makeSink(srv.sinkURI(tc.params), tc.opts)
+			require.True(t, testutils.IsError(err, tc.err), `%s: expected %q got %+v`, tc.params, tc.err, err)
+		}
+	})
+}
@@ -0,0 +1,617 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+)
+
+const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
+
+// exportParquetCreator is recorded as the writer of the exported files in
+// their metadata.
+const exportParquetCreator = "CockroachDB"
+
+var exportParquetCompressionCodecs = map[execinfrapb.ParquetWriterSpec_Compression]parquet.CompressionCodec{
+	execinfrapb.ParquetWriterSpec_NONE:   parquet.CompressionCodec_UNCOMPRESSED,
+	execinfrapb.ParquetWriterSpec_GZIP:   parquet.CompressionCodec_GZIP,
+	execinfrapb.ParquetWriterSpec_SNAPPY: parquet.CompressionCodec_SNAPPY,
+}
+
+// parquetEncodeFn converts a non-NULL datum into the value the Parquet writer
+// expects for the column it was created for.
+type parquetEncodeFn func(tree.Datum) (interface{}, error)
+
+// parquetColumn describes how a column of the exported rows is represented in
+// the Parquet files.
+type parquetColumn struct {
+	name   string
+	def    *parquetschema.ColumnDefinition
+	encode parquetEncodeFn
+}
+
+// parquetExporter data structure to augment the Parquet file writer,
+// encapsulating the internals to make exporting oblivious for the consumers.
+type parquetExporter struct {
+	buf     *bytes.Buffer
+	schema  *parquetschema.SchemaDefinition
+	cols    []parquetColumn
+	opts    []goparquet.FileWriterOption
+	writer  *goparquet.FileWriter
+	rowData map[string]interface{}
+}
+
+// Write appends a row to the current row group of the file, flushing the row
+// group if it reached the configured size.
+func (c *parquetExporter) Write(row rowenc.EncDatumRow) error {
+	for k := range c.rowData {
+		delete(c.rowData, k)
+	}
+	for i, ed := range row {
+		if ed.IsNull() {
+			// Omitted optional fields are written as NULLs.
+			continue
+		}
+		v, err := c.cols[i].encode(ed.Datum)
+		if err != nil {
+			return errors.Wrapf(err, "encoding column %q", c.cols[i].name)
+		}
+		c.rowData[c.cols[i].name] = v
+	}
+	return c.writer.AddData(c.rowData)
+}
+
+// Close flushes the last row group and writes the file footer into the
+// buffer.
+func (c *parquetExporter) Close() error {
+	return c.writer.Close()
+}
+
+// ResetBuffer resets the buffer and starts a new file.
+func (c *parquetExporter) ResetBuffer() error {
+	c.buf.Reset()
+	c.writer = goparquet.NewFileWriter(c.buf, c.opts...)
+	return c.writer.SetSchemaDefinition(c.schema)
+}
+
+// Bytes results in the slice of bytes with the file content.
+func (c *parquetExporter) Bytes() []byte {
+	return c.buf.Bytes()
+}
+
+// Len returns length of the buffer with content.
+func (c *parquetExporter) Len() int {
+	return c.buf.Len()
+}
+
+func (c *parquetExporter) FileName(spec execinfrapb.ParquetWriterSpec, part string) string {
+	pattern := exportParquetFilePatternDefault
+	if spec.NamePattern != "" {
+		pattern = spec.NamePattern
+	}
+	return strings.Replace(pattern, exportFilePatternPart, part, -1)
+}
+
+func newParquetExporter(
+	sp execinfrapb.ParquetWriterSpec, typs []*types.T,
+) (*parquetExporter, error) {
+	if len(sp.ColNames) != len(typs) {
+		return nil, errors.AssertionFailedf(
+			"expected %d column names, got %d", len(typs), len(sp.ColNames))
+	}
+	codec, ok := exportParquetCompressionCodecs[sp.CompressionCodec]
+	if !ok {
+		return nil, errors.Errorf("unsupported compression codec %s", sp.CompressionCodec)
+	}
+
+	cols := make([]parquetColumn, len(typs))
+	root := &parquetschema.ColumnDefinition{
+		SchemaElement: &parquet.SchemaElement{Name: "export"},
+		Children:      make([]*parquetschema.ColumnDefinition, len(typs)),
+	}
+	seen := make(map[string]struct{}, len(typs))
+	for i, name := range sp.ColNames {
+		if _, ok := seen[name]; ok {
+			return nil, errors.Errorf(
+				"duplicate column name %q is not supported by the PARQUET format, use AS to rename it", name)
+		}
+		seen[name] = struct{}{}
+		def, encode, err := newParquetColumn(name, typs[i])
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = parquetColumn{name: name, def: def, encode: encode}
+		root.Children[i] = def
+	}
+	schema := parquetschema.SchemaDefinitionFromColumnDefinition(root)
+	if err := schema.Validate(); err != nil {
+		return nil, errors.NewAssertionErrorWithWrappedErrf(err, "invalid parquet schema")
+	}
+
+	opts := []goparquet.FileWriterOption{
+		goparquet.WithCreator(exportParquetCreator),
+		goparquet.WithCompressionCodec(codec),
+	}
+	if sp.RowGroupSize > 0 {
+		opts = append(opts, goparquet.WithMaxRowGroupSize(sp.RowGroupSize))
+	}
+	return &parquetExporter{
+		buf:     bytes.NewBuffer([]byte{}),
+		schema:  schema,
+		cols:    cols,
+		opts:    opts,
+		rowData: make(map[string]interface{}, len(cols)),
+	}, nil
+}
+
+// newParquetColumn returns the definition of the optional Parquet field
+// storing values of the given type, along with the function encoding them.
+//
+// Types are mapped to the Parquet physical and logical types which preserve
+// their values; types which have no lossless Parquet counterpart (intervals,
+// time with time zone, geospatial types, etc.) as well as decimals without a
+// fixed precision are exported as strings in their text representation.
+func newParquetColumn(
+	name string, typ *types.T,
+) (*parquetschema.ColumnDefinition, parquetEncodeFn, error) {
+	elem := &parquet.SchemaElement{
+		Name:           name,
+		RepetitionType: parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_OPTIONAL),
+	}
+	def := &parquetschema.ColumnDefinition{SchemaElement: elem}
+	var encode parquetEncodeFn
+
+	switch typ.Family() {
+	case types.BoolFamily:
+		elem.Type = parquet.TypePtr(parquet.Type_BOOLEAN)
+		encode = func(d tree.Datum) (interface{}, error) {
+			return bool(tree.MustBeDBool(d)), nil
+		}
+
+	case types.IntFamily:
+		if typ.Width() == 16 || typ.Width() == 32 {
+			elem.Type = parquet.TypePtr(parquet.Type_INT32)
+			setParquetIntType(elem, int8(typ.Width()))
+			encode = func(d tree.Datum) (interface{}, error) {
+				return int32(tree.MustBeDInt(d)), nil
+			}
+		} else {
+			elem.Type = parquet.TypePtr(parquet.Type_INT64)
+			setParquetIntType(elem, 64)
+			encode = func(d tree.Datum) (interface{}, error) {
+				return int64(tree.MustBeDInt(d)), nil
+			}
+		}
+
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			elem.Type = parquet.TypePtr(parquet.Type_FLOAT)
+			encode = func(d tree.Datum) (interface{}, error) {
+				return float32(*d.(*tree.DFloat)), nil
+			}
+		} else {
+			elem.Type = parquet.TypePtr(parquet.Type_DOUBLE)
+			encode = func(d tree.Datum) (interface{}, error) {
+				return float64(*d.(*tree.DFloat)), nil
+			}
+		}
+
+	case types.DecimalFamily:
+		if typ.Precision() == 0 {
+			// The precision of the column must be known for the DECIMAL logical
+			// type, so unconstrained decimals are exported as strings.
+			return newParquetStringColumn(def)
+		}
+		precision, scale := typ.Precision(), typ.Scale()
+		elem.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+		elem.Precision = &precision
+		elem.Scale = &scale
+		elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL)
+		elem.LogicalType = parquet.NewLogicalType()
+		elem.LogicalType.DECIMAL = &parquet.DecimalType{Precision: precision, Scale: scale}
+		encode = func(d tree.Datum) (interface{}, error) {
+			return encodeParquetDecimal(&d.(*tree.DDecimal).Decimal, precision, scale)
+		}
+
+	case types.StringFamily, types.CollatedStringFamily:
+		setParquetStringType(elem)
+		encode = func(d tree.Datum) (interface{}, error) {
+			if c, ok := d.(*tree.DCollatedString); ok {
+				return []byte(c.Contents), nil
+			}
+			return []byte(tree.MustBeDString(d)), nil
+		}
+
+	case types.BytesFamily:
+		elem.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(*d.(*tree.DBytes)), nil
+		}
+
+	case types.DateFamily:
+		elem.Type = parquet.TypePtr(parquet.Type_INT32)
+		elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_DATE)
+		elem.LogicalType = parquet.NewLogicalType()
+		elem.LogicalType.DATE = parquet.NewDateType()
+		encode = func(d tree.Datum) (interface{}, error) {
+			date := d.(*tree.DDate).Date
+			if !date.IsFinite() {
+				return nil, errors.Errorf("cannot export infinite date %s", date)
+			}
+			return int32(date.UnixEpochDays()), nil
+		}
+
+	case types.TimeFamily:
+		elem.Type = parquet.TypePtr(parquet.Type_INT64)
+		elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_TIME_MICROS)
+		elem.LogicalType = parquet.NewLogicalType()
+		elem.LogicalType.TIME = &parquet.TimeType{
+			IsAdjustedToUTC: false,
+			Unit:            &parquet.TimeUnit{MICROS: parquet.NewMicroSeconds()},
+		}
+		encode = func(d tree.Datum) (interface{}, error) {
+			return int64(*d.(*tree.DTime)), nil
+		}
+
+	case types.TimestampFamily, types.TimestampTZFamily:
+		elem.Type = parquet.TypePtr(parquet.Type_INT64)
+		elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MICROS)
+		elem.LogicalType = parquet.NewLogicalType()
+		elem.LogicalType.TIMESTAMP = &parquet.TimestampType{
+			IsAdjustedToUTC: typ.Family() == types.TimestampTZFamily,
+			Unit:            &parquet.TimeUnit{MICROS: parquet.NewMicroSeconds()},
+		}
+		encode = func(d tree.Datum) (interface{}, error) {
+			switch ts := d.(type) {
+			case *tree.DTimestamp:
+				return ts.Unix()*1e6 + int64(ts.Nanosecond()/1e3), nil
+			case *tree.DTimestampTZ:
+				return ts.Unix()*1e6 + int64(ts.Nanosecond()/1e3), nil
+			}
+			return nil, errors.AssertionFailedf("unexpected timestamp datum %T", d)
+		}
+
+	case types.UuidFamily:
+		length := int32(16)
+		elem.Type = parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY)
+		elem.TypeLength = &length
+		elem.LogicalType = parquet.NewLogicalType()
+		elem.LogicalType.UUID = parquet.NewUUIDType()
+		encode = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DUuid).GetBytes(), nil
+		}
+
+	case types.JsonFamily:
+		elem.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+		elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_JSON)
+		elem.LogicalType = parquet.NewLogicalType()
+		elem.LogicalType.JSON = parquet.NewJsonType()
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DJSON).JSON.String()), nil
+		}
+
+	case types.EnumFamily:
+		elem.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+		elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_ENUM)
+		elem.LogicalType = parquet.NewLogicalType()
+		elem.LogicalType.ENUM = parquet.NewEnumType()
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DEnum).LogicalRep), nil
+		}
+
+	case types.ArrayFamily:
+		return newParquetListColumn(def, typ.ArrayContents())
+
+	default:
+		return newParquetStringColumn(def)
+	}
+	return def, encode, nil
+}
+
+// newParquetListColumn turns the given column definition into a LIST of
+// optional elements of the given type, following the three-level structure
+// mandated by the Parquet specification:
+//
+//   optional group <name> (LIST) {
+//     repeated group list {
+//       optional <element-type> element;
+//     }
+//   }
+func newParquetListColumn(
+	def *parquetschema.ColumnDefinition, elemTyp *types.T,
+) (*parquetschema.ColumnDefinition, parquetEncodeFn, error) {
+	if elemTyp.Family() == types.ArrayFamily {
+		return nil, nil, errors.Errorf("cannot export nested array type %s", elemTyp.SQLString())
+	}
+	elemDef, elemEncode, err := newParquetColumn("element", elemTyp)
+	if err != nil {
+		return nil, nil, err
+	}
+	def.SchemaElement.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_LIST)
+	def.SchemaElement.LogicalType = parquet.NewLogicalType()
+	def.SchemaElement.LogicalType.LIST = parquet.NewListType()
+	def.Children = []*parquetschema.ColumnDefinition{{
+		SchemaElement: &parquet.SchemaElement{
+			Name:           "list",
+			RepetitionType: parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_REPEATED),
+		},
+		Children: []*parquetschema.ColumnDefinition{elemDef},
+	}}
+	encode := func(d tree.Datum) (interface{}, error) {
+		arr := tree.MustBeDArray(d)
+		if len(arr.Array) == 0 {
+			// An empty list is represented by a defined group without any
+			// repeated entry.
+			return map[string]interface{}{}, nil
+		}
+		list := make([]map[string]interface{}, len(arr.Array))
+		for i, e := range arr.Array {
+			list[i] = map[string]interface{}{}
+			if e == tree.DNull {
+				continue
+			}
+			v, err := elemEncode(e)
+			if err != nil {
+				return nil, err
+			}
+			list[i]["element"] = v
+		}
+		return map[string]interface{}{"list": list}, nil
+	}
+	return def, encode, nil
+}
+
+// newParquetStringColumn turns the given column definition into a STRING
+// storing the text representation of the values, as it would be exported in
+// a CSV file.
+func newParquetStringColumn(
+	def *parquetschema.ColumnDefinition,
+) (*parquetschema.ColumnDefinition, parquetEncodeFn, error) {
+	setParquetStringType(def.SchemaElement)
+	encode := func(d tree.Datum) (interface{}, error) {
+		f := tree.NewFmtCtx(tree.FmtExport)
+		defer f.Close()
+		d.Format(f)
+		return []byte(f.String()), nil
+	}
+	return def, encode, nil
+}
+
+func setParquetStringType(elem *parquet.SchemaElement) {
+	elem.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+	elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8)
+	elem.LogicalType = parquet.NewLogicalType()
+	elem.LogicalType.STRING = parquet.NewStringType()
+}
+
+func setParquetIntType(elem *parquet.SchemaElement, width int8) {
+	convertedType := parquet.ConvertedType_INT_64
+	switch width {
+	case 16:
+		convertedType = parquet.ConvertedType_INT_16
+	case 32:
+		convertedType = parquet.ConvertedType_INT_32
+	}
+	elem.ConvertedType = parquet.ConvertedTypePtr(convertedType)
+	elem.LogicalType = parquet.NewLogicalType()
+	elem.LogicalType.INTEGER = &parquet.IntType{BitWidth: width, IsSigned: true}
+}
+
+// encodeParquetDecimal encodes the given decimal as the big-endian two's
+// complement representation of its unscaled value for the given precision and
+// scale, as expected for the DECIMAL logical type.
+func encodeParquetDecimal(d *apd.Decimal, precision, scale int32) ([]byte, error) {
+	if d.Form != apd.Finite {
+		return nil, errors.Errorf("cannot export non-finite decimal %s", d)
+	}
+	var scaled apd.Decimal
+	scaled.Set(d)
+	if err := tree.LimitDecimalWidth(&scaled, int(precision), int(scale)); err != nil {
+		return nil, err
+	}
+	unscaled := new(big.Int).Set(&scaled.Coeff)
+	if scaled.Negative {
+		unscaled.Neg(unscaled)
+	}
+	return bigIntToTwosComplement(unscaled), nil
+}
+
+// bigIntToTwosComplement returns the big-endian two's complement
+// representation of v.
+func bigIntToTwosComplement(v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			// Prepend a zero byte so that the sign bit is not set.
+			b = append([]byte{0}, b...)
+		}
+		return b
+	}
+	// For a negative value, the representation on n bytes is 2^(8n) + v, where
+	// n is large enough for the sign bit to be set.
+	n := uint(v.BitLen()/8 + 1)
+	return new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 8*n), v).Bytes()
+}
+
+func newParquetWriterProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ParquetWriterSpec,
+	input execinfra.RowSource,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+
+	c := &parquetWriter{
+		flowCtx:     flowCtx,
+		processorID: processorID,
+		spec:        spec,
+		input:       input,
+		output:      output,
+	}
+	semaCtx := tree.MakeSemaContext()
+	if err := c.out.Init(&execinfrapb.PostProcessSpec{}, c.OutputTypes(), &semaCtx, flowCtx.NewEvalCtx(), output); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type parquetWriter struct {
+	flowCtx     *execinfra.FlowCtx
+	processorID int32
+	spec        execinfrapb.ParquetWriterSpec
+	input       execinfra.RowSource
+	out         execinfra.ProcOutputHelper
+	output      execinfra.RowReceiver
+}
+
+var _ execinfra.Processor = &parquetWriter{}
+
+func (sp *parquetWriter) OutputTypes() []*types.T {
+	res := make([]*types.T, len(colinfo.ExportColumns))
+	for i := range res {
+		res[i] = colinfo.ExportColumns[i].Typ
+	}
+	return res
+}
+
+func (sp *parquetWriter) Run(ctx context.Context) {
+	ctx, span := tracing.ChildSpan(ctx, "parquetWriter")
+	defer tracing.FinishSpan(span)
+
+	err := func() error {
+		typs := sp.input.OutputTypes()
+		sp.input.Start(ctx)
+		input := execinfra.MakeNoMetadataRowSource(sp.input, sp.output)
+
+		alloc := &rowenc.DatumAlloc{}
+
+		writer, err := newParquetExporter(sp.spec, typs)
+		if err != nil {
+			return err
+		}
+
+		chunk := 0
+		done := false
+		for {
+			var rows int64
+			if err := writer.ResetBuffer(); err != nil {
+				return err
+			}
+			for {
+				if sp.spec.ChunkRows > 0 && rows >= sp.spec.ChunkRows {
+					break
+				}
+				row, err := input.NextRow()
+				if err != nil {
+					return err
+				}
+				if row == nil {
+					done = true
+					break
+				}
+				rows++
+
+				for i := range row {
+					if err := row[i].EnsureDecoded(typs[i], alloc); err != nil {
+						return err
+					}
+				}
+				if err := writer.Write(row); err != nil {
+					return err
+				}
+			}
+			if rows < 1 {
+				break
+			}
+
+			conf, err := cloudimpl.ExternalStorageConfFromURI(sp.spec.Destination, sp.spec.User)
+			if err != nil {
+				return err
+			}
+			es, err := sp.flowCtx.Cfg.ExternalStorage(ctx, conf)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+
+			nodeID, err := sp.flowCtx.EvalCtx.NodeID.OptionalNodeIDErr(47970)
+			if err != nil {
+				return err
+			}
+
+			part := fmt.Sprintf("n%d.%d", nodeID, chunk)
+			chunk++
+			filename := writer.FileName(sp.spec, part)
+			// Close writer to ensure the last row group and the file footer are
+			// flushed.
+			if err := writer.Close(); err != nil {
+				return errors.Wrapf(err, "failed to close exporting writer")
+			}
+
+			size := writer.Len()
+
+			if err := es.WriteFile(ctx, filename, bytes.NewReader(writer.Bytes())); err != nil {
+				return err
+			}
+			res := rowenc.EncDatumRow{
+				rowenc.DatumToEncDatum(
+					types.String,
+					tree.NewDString(filename),
+				),
+				rowenc.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(rows)),
+				),
+				rowenc.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(size)),
+				),
+			}
+
+			cs, err := sp.out.EmitRow(ctx, res)
+			if err != nil {
+				return err
+			}
+			if cs != execinfra.NeedMoreRows {
+				return errors.New("unexpected closure of consumer")
+			}
+			if done {
+				break
+			}
+		}
+
+		return nil
+	}()
+
+	execinfra.DrainAndClose(
+		ctx, sp.output, err, func(context.Context) {} /* pushTrailingMeta */, sp.input)
+}
+
+func init() {
+	rowexec.NewParquetWriterProcessor = newParquetWriterProcessor
+}
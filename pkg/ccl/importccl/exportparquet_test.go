@@ -0,0 +1,172 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+// readParquetFile returns the schema and the rows of the Parquet file matching
+// the given pattern.
+func readParquetFile(
+	t *testing.T, pattern string,
+) (schema string, rows []map[string]interface{}) {
+	content := readFileByGlob(t, pattern)
+	r, err := goparquet.NewFileReader(bytes.NewReader(content))
+	require.NoError(t, err)
+	for {
+		row, err := r.NextRow()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+	return r.GetSchemaDefinition().String(), rows
+}
+
+func TestExportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TYPE greeting AS ENUM ('hello', 'hi')`)
+	sqlDB.Exec(t, `CREATE TABLE foo (
+	i INT PRIMARY KEY,
+	s INT2,
+	f FLOAT,
+	d DECIMAL(10, 2),
+	ud DECIMAL,
+	str STRING,
+	b BYTES,
+	dt DATE,
+	ts TIMESTAMP,
+	tstz TIMESTAMPTZ,
+	u UUID,
+	j JSONB,
+	arr INT[],
+	iv INTERVAL,
+	e greeting
+)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (
+	1, 2, 1.5, -123.45, 3.14159, 'hello', '\x0102', '2020-01-02',
+	'2020-01-02 03:04:05.123456', '2020-01-02 03:04:05+00',
+	'63616665-6630-3064-6465-616462656566', '{"a": [1, 2]}', ARRAY[1, 2, 3],
+	'1 day 2 hours', 'hi'
+), (2, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, ARRAY[], NULL, NULL)`)
+
+	for _, codec := range []string{"none", "gzip", "snappy"} {
+		t.Run(codec, func(t *testing.T) {
+			sqlDB.Exec(t, fmt.Sprintf(
+				`EXPORT INTO PARQUET 'nodelocal://0/%[1]s' WITH compression = %[1]s FROM SELECT * FROM foo ORDER BY i`,
+				codec))
+
+			schema, rows := readParquetFile(t, filepath.Join(dir, codec, "export*-n1.0.parquet"))
+			require.Equal(t, `message export {
+  optional int64 i (INT(64, true));
+  optional int32 s (INT(16, true));
+  optional double f;
+  optional binary d (DECIMAL(10, 2));
+  optional binary ud (STRING);
+  optional binary str (STRING);
+  optional binary b;
+  optional int32 dt (DATE);
+  optional int64 ts (TIMESTAMP(MICROS, false));
+  optional int64 tstz (TIMESTAMP(MICROS, true));
+  optional fixed_len_byte_array(16) u (UUID);
+  optional binary j (JSON);
+  optional group arr (LIST) {
+    repeated group list {
+      optional int64 element (INT(64, true));
+    }
+  }
+  optional binary iv (STRING);
+  optional binary e (ENUM);
+}
+`, schema)
+			require.Equal(t, []map[string]interface{}{
+				{
+					"i":    int64(1),
+					"s":    int32(2),
+					"f":    1.5,
+					"d":    []byte{0xcf, 0xc7},
+					"ud":   []byte("3.14159"),
+					"str":  []byte("hello"),
+					"b":    []byte{1, 2},
+					"dt":   int32(18263),
+					"ts":   int64(1577934245123456),
+					"tstz": int64(1577934245000000),
+					"u":    []byte("cafef00ddeadbeef"),
+					"j":    []byte(`{"a": [1, 2]}`),
+					"arr": map[string]interface{}{"list": []map[string]interface{}{
+						{"element": int64(1)}, {"element": int64(2)}, {"element": int64(3)},
+					}},
+					"iv": []byte("1 day 02:00:00"),
+					"e":  []byte("hi"),
+				},
+				{
+					"i":   int64(2),
+					"arr": map[string]interface{}{},
+				},
+			}, rows)
+		})
+	}
+}
+
+func TestExportParquetOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE foo (i INT PRIMARY KEY, x INT)`)
+	sqlDB.Exec(t, `INSERT INTO foo SELECT i, i * 10 FROM generate_series(1, 100) AS g(i)`)
+
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/chunks' WITH chunk_rows = '60', row_group_size = '1KiB' FROM TABLE foo`)
+	_, rows := readParquetFile(t, filepath.Join(dir, "chunks", "export*-n1.0.parquet"))
+	require.Len(t, rows, 60)
+	_, rows = readParquetFile(t, filepath.Join(dir, "chunks", "export*-n1.1.parquet"))
+	require.Len(t, rows, 40)
+
+	sqlDB.ExpectErr(t, `delimiter option is only supported for CSV exports`,
+		`EXPORT INTO PARQUET 'nodelocal://0/err' WITH delimiter = '|' FROM TABLE foo`)
+	sqlDB.ExpectErr(t, `nullas option is only supported for CSV exports`,
+		`EXPORT INTO PARQUET 'nodelocal://0/err' WITH nullas = '' FROM TABLE foo`)
+	sqlDB.ExpectErr(t, `row_group_size option is only supported for PARQUET exports`,
+		`EXPORT INTO CSV 'nodelocal://0/err' WITH row_group_size = '1MiB' FROM TABLE foo`)
+	sqlDB.ExpectErr(t, `unsupported compression codec lz4`,
+		`EXPORT INTO PARQUET 'nodelocal://0/err' WITH compression = lz4 FROM TABLE foo`)
+	sqlDB.ExpectErr(t, `invalid parquet row group size`,
+		`EXPORT INTO PARQUET 'nodelocal://0/err' WITH row_group_size = '0' FROM TABLE foo`)
+	sqlDB.ExpectErr(t, `duplicate column name "i"`,
+		`EXPORT INTO PARQUET 'nodelocal://0/err' FROM SELECT i, i FROM foo`)
+}
@@ -82,7 +82,7 @@ const (
 
 	optMaxRowSize = "max_row_size"
 
-	// Turn on strict validation when importing avro records.
+	// Turn on strict validation when importing avro records or parquet files.
 	avroStrict = "strict_validation"
 	// Default input format is assumed to be OCF (object container file).
 	// This default can be changed by specified either of these options.
@@ -154,6 +154,7 @@ var mysqlOutAllowedOptions = makeStringSet(
 var mysqlDumpAllowedOptions = makeStringSet(importOptionSkipFKs)
 var pgCopyAllowedOptions = makeStringSet(pgCopyDelimiter, pgCopyNull, optMaxRowSize)
 var pgDumpAllowedOptions = makeStringSet(optMaxRowSize, importOptionSkipFKs)
+var parquetAllowedOptions = makeStringSet(avroStrict)
 
 // DROP is required because the target table needs to be take offline during
 // IMPORT INTO.
@@ -165,6 +166,7 @@ var allowedIntoFormats = map[string]struct{}{
 	"AVRO":      {},
 	"DELIMITED": {},
 	"PGCOPY":    {},
+	"PARQUET":   {},
 }
 
 func validateFormatOptions(
@@ -565,6 +567,12 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+		case "PARQUET":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, parquetAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_Parquet
+			_, format.Parquet.StrictMode = opts[avroStrict]
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
@@ -1505,7 +1513,9 @@ func (r *importResumer) dropTables(
 	}
 
 	for i := range empty {
-		if err := gcjob.ClearTableData(ctx, execCfg.DB, execCfg.DistSender, execCfg.Codec, empty[i]); err != nil {
+		if err := gcjob.ClearTableData(
+			ctx, execCfg.DB, execCfg.DistSender, execCfg.Codec, execCfg.Settings, empty[i],
+		); err != nil {
 			return errors.Wrapf(err, "clearing data for table %d", empty[i].ID)
 		}
 	}
@@ -1520,7 +1530,8 @@ func (r *importResumer) dropTables(
 		}
 		if tbl.IsNew {
 			newTableDesc.State = descpb.DescriptorState_DROP
-			// If the DropTime if set, a table uses RangeClear for fast data removal. This
+			// If the DropTime if set, a table uses RangeClear (or MVCC range
+			// tombstones, once they are enabled) for fast data removal. This
 			// operation starts at DropTime + the GC TTL. If we used now() here, it would
 			// not clean up data until the TTL from the time of the error. Instead, use 1
 			// (that is, 1ns past the epoch) to allow this to be cleaned up as soon as
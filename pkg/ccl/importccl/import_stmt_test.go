@@ -5380,6 +5380,124 @@ func TestImportAvro(t *testing.T) {
 	})
 }
 
+func TestImportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const (
+		nodes = 3
+	)
+	ctx := context.Background()
+	baseDir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	args := base.TestServerArgs{ExternalIODir: baseDir}
+	tc := testcluster.StartTestCluster(t, nodes, base.TestClusterArgs{ServerArgs: args})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.Conns[0])
+
+	sqlDB.Exec(t, `CREATE DATABASE foo; SET DATABASE = foo`)
+	sqlDB.Exec(t, `CREATE TABLE src (
+	i INT PRIMARY KEY,
+	f FLOAT,
+	d DECIMAL(10, 2),
+	s STRING,
+	b BYTES,
+	ts TIMESTAMP,
+	tstz TIMESTAMPTZ,
+	j JSONB,
+	arr STRING[]
+)`)
+	sqlDB.Exec(t, `INSERT INTO src SELECT
+	i, i::FLOAT / 2, i * 1.25, 'str' || i::STRING, ('b' || i::STRING)::BYTES,
+	'2020-01-02 03:04:05'::TIMESTAMP + i * '1 hour'::INTERVAL,
+	'2020-01-02 03:04:05+00'::TIMESTAMPTZ + i * '1 minute'::INTERVAL,
+	json_build_object('i', i), ARRAY['a', NULL, i::STRING]
+FROM generate_series(1, 1000) AS g(i)`)
+	sqlDB.Exec(t, `INSERT INTO src (i, arr) VALUES (1001, ARRAY[]), (1002, NULL)`)
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/src' WITH chunk_rows = '300' FROM TABLE src`)
+	files := "nodelocal://0/src/*.parquet"
+
+	tests := []struct {
+		name   string
+		create string
+		sql    string
+		query  string
+		err    string
+	}{
+		{
+			name:   "import-into-table",
+			create: `CREATE TABLE dst (LIKE src INCLUDING ALL)`,
+			sql:    `IMPORT INTO dst PARQUET DATA ($1)`,
+			query:  `SELECT * FROM %s ORDER BY i`,
+		},
+		{
+			name:   "import-into-table-with-strict-validation",
+			create: `CREATE TABLE dst (LIKE src INCLUDING ALL)`,
+			sql:    `IMPORT INTO dst PARQUET DATA ($1) WITH strict_validation`,
+			query:  `SELECT * FROM %s ORDER BY i`,
+		},
+		{
+			name:   "import-into-target-columns",
+			create: `CREATE TABLE dst (i INT PRIMARY KEY, s STRING, arr STRING[])`,
+			sql:    `IMPORT INTO dst (i, arr) PARQUET DATA ($1)`,
+			query:  `SELECT i, NULL::STRING, arr FROM %s ORDER BY i`,
+		},
+		{
+			name:   "relaxed-import-ignores-extra-fields",
+			create: `CREATE TABLE dst (i INT PRIMARY KEY, s STRING, extra INT)`,
+			sql:    `IMPORT INTO dst PARQUET DATA ($1)`,
+			query:  `SELECT i, s, NULL::INT FROM %s ORDER BY i`,
+		},
+		{
+			name:   "import-coerces-types",
+			create: `CREATE TABLE dst (i STRING PRIMARY KEY, f DECIMAL, d FLOAT, ts TIMESTAMPTZ, arr JSONB)`,
+			sql:    `IMPORT INTO dst PARQUET DATA ($1)`,
+			query:  `SELECT i::STRING, f::DECIMAL, d::FLOAT, ts::TIMESTAMPTZ, to_json(arr) FROM %s ORDER BY i::STRING`,
+		},
+		{
+			name:   "strict-import-errors-missing-fields",
+			create: `CREATE TABLE dst (i INT PRIMARY KEY, s STRING, extra INT)`,
+			sql:    `IMPORT INTO dst PARQUET DATA ($1) WITH strict_validation`,
+			err:    "could not find column for parquet field f",
+		},
+		{
+			name:   "strict-import-errors-extra-fields",
+			create: `CREATE TABLE dst (LIKE src INCLUDING ALL, extra INT)`,
+			sql:    `IMPORT INTO dst PARQUET DATA ($1) WITH strict_validation`,
+			err:    "column extra was not found in the parquet file",
+		},
+		{
+			name:   "fail-import-incompatible-types",
+			create: `CREATE TABLE dst (i INT PRIMARY KEY, arr INT)`,
+			sql:    `IMPORT INTO dst PARQUET DATA ($1)`,
+			err:    "cannot convert parquet list",
+		},
+		{
+			name:   "fail-import-unsupported-option",
+			create: `CREATE TABLE dst (i INT PRIMARY KEY)`,
+			sql:    `IMPORT INTO dst PARQUET DATA ($1) WITH delimiter = '|'`,
+			err:    "invalid option",
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Play a bit with producer/consumer batch sizes.
+			defer TestingSetParallelImporterReaderBatchSize(13 * i)()
+
+			sqlDB.Exec(t, `DROP TABLE IF EXISTS dst`)
+			sqlDB.Exec(t, test.create)
+			if test.err != "" {
+				sqlDB.ExpectErr(t, test.err, test.sql, files)
+				return
+			}
+			sqlDB.Exec(t, test.sql, files)
+			sqlDB.CheckQueryResults(t, `SELECT * FROM dst ORDER BY i`,
+				sqlDB.QueryStr(t, fmt.Sprintf(test.query, "src")))
+		})
+	}
+}
+
 // TestImportClientDisconnect ensures that an import job can complete even if
 // the client connection which started it closes. This test uses a helper
 // subprocess to force a closed client connection without needing to rely
@@ -0,0 +1,881 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// parquetFileColumn is a column of the schema of a parquet file.
+type parquetFileColumn struct {
+	elem     *parquet.SchemaElement
+	name     string
+	children []*parquetFileColumn
+	// defLevel and repLevel are the maximum definition and repetition levels
+	// of the column, i.e. the number of optional or repeated columns, and the
+	// number of repeated columns, on the path from its top-level column to
+	// the column itself.
+	defLevel, repLevel int32
+}
+
+// makeParquetSchema returns the top-level columns of the schema of a parquet
+// file, along with the path from a top-level column to each leaf column in
+// the order in which the leaf columns are stored.
+func makeParquetSchema(
+	elems []*parquet.SchemaElement,
+) (cols []*parquetFileColumn, leaves [][]*parquetFileColumn, _ error) {
+	if len(elems) == 0 {
+		return nil, nil, errors.New("invalid parquet schema")
+	}
+
+	// The schema elements are stored in depth-first order, starting with the
+	// root of the schema.
+	next := 1
+	var makeColumn func(path []*parquetFileColumn) (*parquetFileColumn, error)
+	makeColumn = func(path []*parquetFileColumn) (*parquetFileColumn, error) {
+		if next >= len(elems) {
+			return nil, errors.New("invalid parquet schema")
+		}
+		col := &parquetFileColumn{elem: elems[next], name: elems[next].GetName()}
+		next++
+		if len(path) > 0 {
+			col.defLevel, col.repLevel = path[len(path)-1].defLevel, path[len(path)-1].repLevel
+		}
+		switch col.elem.GetRepetitionType() {
+		case parquet.FieldRepetitionType_REPEATED:
+			col.repLevel++
+			col.defLevel++
+		case parquet.FieldRepetitionType_OPTIONAL:
+			col.defLevel++
+		}
+
+		path = append(path[:len(path):len(path)], col)
+		if col.elem.GetNumChildren() == 0 {
+			leaves = append(leaves, path)
+		}
+		for i := int32(0); i < col.elem.GetNumChildren(); i++ {
+			child, err := makeColumn(path)
+			if err != nil {
+				return nil, err
+			}
+			col.children = append(col.children, child)
+		}
+		return col, nil
+	}
+	for i := int32(0); i < elems[0].GetNumChildren(); i++ {
+		col, err := makeColumn(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, col)
+	}
+	if next != len(elems) {
+		return nil, nil, errors.New("invalid parquet schema")
+	}
+	return cols, leaves, nil
+}
+
+// assembleParquetValue adds a value read from a leaf column, given its
+// repetition and definition levels, to a record. Groups are assembled as maps
+// keyed by column name and repeated columns as slices, following the record
+// assembly described in the Dremel paper. idx holds the index of the current
+// element of each repeated column on the path to the leaf column, and it is
+// carried over between the values of the leaf column.
+func assembleParquetValue(
+	record map[string]interface{}, path []*parquetFileColumn, v interface{}, r, d int32, idx []int,
+) {
+	m := record
+	for i, col := range path {
+		if d < col.defLevel {
+			// The column is NULL or, if it is repeated, empty.
+			if _, ok := m[col.name]; !ok {
+				if isParquetRepeated(col) {
+					m[col.name] = []interface{}{}
+				} else {
+					m[col.name] = nil
+				}
+			}
+			return
+		}
+
+		isLeaf := i == len(path)-1
+		if !isParquetRepeated(col) {
+			if isLeaf {
+				m[col.name] = v
+				return
+			}
+			child, _ := m[col.name].(map[string]interface{})
+			if child == nil {
+				child = make(map[string]interface{})
+				m[col.name] = child
+			}
+			m = child
+			continue
+		}
+
+		// A repetition level lower than the one of the column starts a new
+		// list, and one equal to it starts a new element of the list.
+		switch {
+		case r < col.repLevel:
+			idx[i] = 0
+		case r == col.repLevel:
+			idx[i]++
+		}
+		list, _ := m[col.name].([]interface{})
+		if idx[i] >= len(list) {
+			var elem interface{} = v
+			if !isLeaf {
+				elem = make(map[string]interface{})
+			}
+			list = append(list, elem)
+			m[col.name] = list
+		}
+		if isLeaf {
+			return
+		}
+		m = list[idx[i]].(map[string]interface{})
+	}
+}
+
+func isParquetGroup(col *parquetFileColumn) bool {
+	return col.elem.Type == nil
+}
+
+func isParquetRepeated(col *parquetFileColumn) bool {
+	return col.elem.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED
+}
+
+func hasParquetConvertedType(elem *parquet.SchemaElement, cts ...parquet.ConvertedType) bool {
+	if !elem.IsSetConvertedType() {
+		return false
+	}
+	for _, t := range cts {
+		if elem.GetConvertedType() == t {
+			return true
+		}
+	}
+	return false
+}
+
+// parquetLogicalType returns the logical type annotation of the schema
+// element, or an empty annotation if the element has none.
+func parquetLogicalType(elem *parquet.SchemaElement) *parquet.LogicalType {
+	if elem.IsSetLogicalType() {
+		return elem.GetLogicalType()
+	}
+	return parquet.NewLogicalType()
+}
+
+func isParquetList(elem *parquet.SchemaElement) bool {
+	return parquetLogicalType(elem).IsSetLIST() ||
+		hasParquetConvertedType(elem, parquet.ConvertedType_LIST)
+}
+
+func isParquetMap(elem *parquet.SchemaElement) bool {
+	return parquetLogicalType(elem).IsSetMAP() ||
+		hasParquetConvertedType(elem, parquet.ConvertedType_MAP, parquet.ConvertedType_MAP_KEY_VALUE)
+}
+
+// parquetListElements returns the elements of a LIST annotated group along
+// with the element column. Besides the standard three-level structure, it
+// handles the two-level structures written by older writers as described by
+// the backward-compatibility rules of the parquet format.
+func parquetListElements(v interface{}, col *parquetFileColumn) ([]interface{}, *parquetFileColumn, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(col.children) != 1 || !isParquetRepeated(col.children[0]) {
+		return nil, nil, errors.Errorf("invalid parquet list %s", col.name)
+	}
+	repeated := col.children[0]
+	values, _ := m[repeated.name].([]interface{})
+	if !isParquetGroup(repeated) || len(repeated.children) != 1 ||
+		repeated.name == "array" || repeated.name == col.name+"_tuple" {
+		// The repeated column is the element itself.
+		return values, repeated, nil
+	}
+
+	elem := repeated.children[0]
+	elems := make([]interface{}, len(values))
+	for i, v := range values {
+		if m, ok := v.(map[string]interface{}); ok {
+			elems[i] = m[elem.name]
+		}
+	}
+	return elems, elem, nil
+}
+
+// parquetToDatum converts the value read for a parquet column to a datum of
+// the target type.
+func parquetToDatum(
+	v interface{}, col *parquetFileColumn, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	if isParquetRepeated(col) {
+		values, _ := v.([]interface{})
+		return parquetListToDatum(values, col, targetT, evalCtx)
+	}
+	return parquetValueToDatum(v, col, targetT, evalCtx)
+}
+
+// parquetValueToDatum converts a single, non-repeated, value of a parquet
+// column to a datum of the target type. Lists can be converted to arrays or
+// JSON, and other groups (maps and structs) can only be converted to JSON.
+func parquetValueToDatum(
+	v interface{}, col *parquetFileColumn, targetT *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	if v == nil {
+		// Let the target table schema verify whether nulls are allowed.
+		return tree.DNull, nil
+	}
+
+	if !isParquetGroup(col) {
+		d, err := parquetNativeToDatum(v, col)
+		if err != nil {
+			return nil, err
+		}
+		return parquetCoerceDatum(d, targetT, evalCtx)
+	}
+
+	if isParquetList(col.elem) {
+		elems, elemCol, err := parquetListElements(v, col)
+		if err != nil {
+			return nil, err
+		}
+		return parquetListToDatum(elems, elemCol, targetT, evalCtx)
+	}
+	if targetT.Family() != types.JsonFamily {
+		return nil, errors.Errorf("cannot convert parquet group %s to %s",
+			col.name, targetT)
+	}
+	j, err := parquetValueToJSON(v, col, evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDJSON(j), nil
+}
+
+// parquetListToDatum converts the elements of a list to an array or to a JSON
+// array, depending on the target type.
+func parquetListToDatum(
+	elems []interface{},
+	elemCol *parquetFileColumn,
+	targetT *types.T,
+	evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	switch targetT.Family() {
+	case types.ArrayFamily:
+		arr := tree.NewDArray(targetT.ArrayContents())
+		for _, elem := range elems {
+			d, err := parquetValueToDatum(elem, elemCol, targetT.ArrayContents(), evalCtx)
+			if err == nil {
+				err = arr.Append(d)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case types.JsonFamily:
+		j, err := parquetListToJSON(elems, elemCol, evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDJSON(j), nil
+	}
+	return nil, errors.Errorf("cannot convert parquet list %s to %s",
+		elemCol.name, targetT)
+}
+
+func parquetToJSON(
+	v interface{}, col *parquetFileColumn, evalCtx *tree.EvalContext,
+) (json.JSON, error) {
+	if isParquetRepeated(col) {
+		values, _ := v.([]interface{})
+		return parquetListToJSON(values, col, evalCtx)
+	}
+	return parquetValueToJSON(v, col, evalCtx)
+}
+
+func parquetListToJSON(
+	elems []interface{}, elemCol *parquetFileColumn, evalCtx *tree.EvalContext,
+) (json.JSON, error) {
+	b := json.NewArrayBuilder(len(elems))
+	for _, elem := range elems {
+		j, err := parquetValueToJSON(elem, elemCol, evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		b.Add(j)
+	}
+	return b.Build(), nil
+}
+
+// parquetValueToJSON converts a single, non-repeated, value of a parquet column
+// to JSON. Lists are converted to arrays, and maps and structs to objects.
+func parquetValueToJSON(
+	v interface{}, col *parquetFileColumn, evalCtx *tree.EvalContext,
+) (json.JSON, error) {
+	if v == nil {
+		return json.NullJSONValue, nil
+	}
+
+	if !isParquetGroup(col) {
+		d, err := parquetNativeToDatum(v, col)
+		if err != nil {
+			return nil, err
+		}
+		return tree.AsJSON(d, evalCtx.GetLocation())
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("unexpected value of type %T for parquet group %s",
+			v, col.name)
+	}
+	switch {
+	case isParquetList(col.elem):
+		elems, elemCol, err := parquetListElements(v, col)
+		if err != nil {
+			return nil, err
+		}
+		return parquetListToJSON(elems, elemCol, evalCtx)
+
+	case isParquetMap(col.elem):
+		// A map holds a repeated group of key and value columns.
+		if len(col.children) != 1 || len(col.children[0].children) != 2 ||
+			isParquetGroup(col.children[0].children[0]) {
+			return nil, errors.Errorf("invalid parquet map %s", col.name)
+		}
+		keyValue := col.children[0]
+		keyCol, valueCol := keyValue.children[0], keyValue.children[1]
+		entries, _ := m[keyValue.name].([]interface{})
+		b := json.NewObjectBuilder(len(entries))
+		for _, e := range entries {
+			entry, _ := e.(map[string]interface{})
+			key, err := parquetNativeToDatum(entry[keyCol.name], keyCol)
+			if err != nil {
+				return nil, err
+			}
+			value, err := parquetToJSON(entry[valueCol.name], valueCol, evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			b.Add(tree.AsStringWithFlags(key, tree.FmtBareStrings), value)
+		}
+		return b.Build(), nil
+
+	default:
+		b := json.NewObjectBuilder(len(col.children))
+		for _, child := range col.children {
+			j, err := parquetToJSON(m[child.name], child, evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			b.Add(child.name, j)
+		}
+		return b.Build(), nil
+	}
+}
+
+// parquetNativeToDatum converts a value of a primitive parquet column, as
+// returned by the parquet reader, to the datum matching the logical (or
+// legacy converted) type annotation of the column.
+func parquetNativeToDatum(v interface{}, col *parquetFileColumn) (tree.Datum, error) {
+	elem := col.elem
+	lt := parquetLogicalType(elem)
+	isDecimal := lt.IsSetDECIMAL() || hasParquetConvertedType(elem, parquet.ConvertedType_DECIMAL)
+	scale := elem.GetScale()
+	if lt.IsSetDECIMAL() {
+		scale = lt.DECIMAL.Scale
+	}
+	isUnsigned := (lt.IsSetINTEGER() && !lt.INTEGER.IsSigned) || hasParquetConvertedType(elem,
+		parquet.ConvertedType_UINT_8, parquet.ConvertedType_UINT_16,
+		parquet.ConvertedType_UINT_32, parquet.ConvertedType_UINT_64)
+
+	switch v := v.(type) {
+	case nil:
+		return tree.DNull, nil
+
+	case bool:
+		return tree.MakeDBool(tree.DBool(v)), nil
+
+	case int32:
+		switch {
+		case lt.IsSetDATE() || hasParquetConvertedType(elem, parquet.ConvertedType_DATE):
+			date, err := pgdate.MakeDateFromUnixEpoch(int64(v))
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDDate(date), nil
+		case lt.IsSetTIME() || hasParquetConvertedType(elem, parquet.ConvertedType_TIME_MILLIS):
+			return tree.MakeDTime(timeofday.FromInt(int64(v) * 1000)), nil
+		case isDecimal:
+			return &tree.DDecimal{Decimal: *apd.New(int64(v), -scale)}, nil
+		case isUnsigned:
+			return tree.NewDInt(tree.DInt(uint32(v))), nil
+		}
+		return tree.NewDInt(tree.DInt(v)), nil
+
+	case int64:
+		switch {
+		case lt.IsSetTIMESTAMP():
+			t := time.Unix(0, v*int64(parquetTimeUnit(lt.TIMESTAMP.Unit))).UTC()
+			if lt.TIMESTAMP.IsAdjustedToUTC {
+				return tree.MakeDTimestampTZ(t, time.Microsecond)
+			}
+			return tree.MakeDTimestamp(t, time.Microsecond)
+		case hasParquetConvertedType(elem, parquet.ConvertedType_TIMESTAMP_MILLIS):
+			return tree.MakeDTimestampTZ(time.Unix(0, v*int64(time.Millisecond)).UTC(), time.Microsecond)
+		case hasParquetConvertedType(elem, parquet.ConvertedType_TIMESTAMP_MICROS):
+			return tree.MakeDTimestampTZ(time.Unix(0, v*int64(time.Microsecond)).UTC(), time.Microsecond)
+		case lt.IsSetTIME():
+			micros := v * int64(parquetTimeUnit(lt.TIME.Unit)) / int64(time.Microsecond)
+			return tree.MakeDTime(timeofday.FromInt(micros)), nil
+		case hasParquetConvertedType(elem, parquet.ConvertedType_TIME_MICROS):
+			return tree.MakeDTime(timeofday.FromInt(v)), nil
+		case isDecimal:
+			return &tree.DDecimal{Decimal: *apd.New(v, -scale)}, nil
+		case isUnsigned && v < 0:
+			return nil, errors.Errorf("unsigned value %d out of range", uint64(v))
+		}
+		return tree.NewDInt(tree.DInt(v)), nil
+
+	case float32:
+		return tree.NewDFloat(tree.DFloat(v)), nil
+
+	case float64:
+		return tree.NewDFloat(tree.DFloat(v)), nil
+
+	case string:
+		// Byte arrays, fixed length byte arrays and INT96 values are returned
+		// as strings.
+		b := []byte(v)
+		switch {
+		case elem.GetType() == parquet.Type_INT96 && len(b) == 12:
+			// INT96 values are timestamps, as written by Spark and Impala,
+			// stored as little-endian nanoseconds of the day followed by the
+			// Julian day.
+			nanos := int64(binary.LittleEndian.Uint64(b[:8]))
+			days := int64(binary.LittleEndian.Uint32(b[8:])) - parquetJulianDayOfUnixEpoch
+			t := time.Unix(days*24*60*60, nanos).UTC()
+			return tree.MakeDTimestampTZ(t, time.Microsecond)
+		case isDecimal:
+			return &tree.DDecimal{Decimal: *apd.NewWithBigInt(twosComplementToBigInt(b), -scale)}, nil
+		case lt.IsSetUUID():
+			u, err := uuid.FromBytes(b)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDUuid(tree.DUuid{UUID: u}), nil
+		case lt.IsSetJSON() || hasParquetConvertedType(elem, parquet.ConvertedType_JSON):
+			return tree.ParseDJSON(v)
+		case lt.IsSetSTRING() || lt.IsSetENUM() ||
+			hasParquetConvertedType(elem, parquet.ConvertedType_UTF8, parquet.ConvertedType_ENUM):
+			return tree.NewDString(v), nil
+		case hasParquetConvertedType(elem, parquet.ConvertedType_INTERVAL) && len(b) == 12:
+			// Intervals are stored as little-endian unsigned months, days and
+			// milliseconds.
+			months := int64(binary.LittleEndian.Uint32(b[0:4]))
+			days := int64(binary.LittleEndian.Uint32(b[4:8]))
+			millis := int64(binary.LittleEndian.Uint32(b[8:12]))
+			return tree.NewDInterval(
+				duration.MakeDuration(millis*int64(time.Millisecond), days, months),
+				types.DefaultIntervalTypeMetadata,
+			), nil
+		}
+		return tree.NewDBytes(tree.DBytes(v)), nil
+	}
+	return nil, errors.Errorf("unsupported parquet value of type %T for column %s", v, col.name)
+}
+
+// parquetJulianDayOfUnixEpoch is the Julian day of the Unix epoch, used to
+// decode INT96 timestamps.
+const parquetJulianDayOfUnixEpoch = 2440588
+
+// parquetTimeUnit returns the duration of the time unit of a TIME or TIMESTAMP
+// logical type.
+func parquetTimeUnit(unit *parquet.TimeUnit) time.Duration {
+	switch {
+	case unit.IsSetMILLIS():
+		return time.Millisecond
+	case unit.IsSetMICROS():
+		return time.Microsecond
+	}
+	return time.Nanosecond
+}
+
+// twosComplementToBigInt decodes the big-endian two's complement encoding of
+// an integer, as used for DECIMAL values stored in byte arrays.
+func twosComplementToBigInt(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return v
+}
+
+// parquetCoerceDatum converts a datum decoded from a parquet file to the
+// target type, when the types don't already match.
+func parquetCoerceDatum(d tree.Datum, targetT *types.T, evalCtx *tree.EvalContext) (tree.Datum, error) {
+	if d == tree.DNull || targetT.Equivalent(d.ResolvedType()) {
+		return d, nil
+	}
+	switch t := d.(type) {
+	case *tree.DString:
+		// Strings can be imported into any column, as long as we can convert
+		// the string value to the target type.
+		return rowenc.ParseDatumStringAs(targetT, string(*t), evalCtx)
+	case *tree.DBytes:
+		return rowenc.ParseDatumStringAs(targetT, string(*t), evalCtx)
+	}
+	if targetT.Family() == types.JsonFamily {
+		j, err := tree.AsJSON(d, evalCtx.GetLocation())
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDJSON(j), nil
+	}
+	return tree.PerformCast(evalCtx, d, targetT)
+}
+
+// parquetField maps a top-level column of a parquet file onto a column of the
+// table being imported into.
+type parquetField struct {
+	col *parquetFileColumn
+	// idx is the index of the target column among the visible columns.
+	idx int
+}
+
+// parquetConsumer implements importRowConsumer interface.
+type parquetConsumer struct {
+	fields []parquetField
+}
+
+var _ importRowConsumer = &parquetConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (p *parquetConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return errors.Errorf("unexpected native type; expected map[string]interface{} found %T instead", native)
+	}
+
+	for _, f := range p.fields {
+		name := f.col.name
+		datum, err := parquetToDatum(record[name], f.col, conv.VisibleColTypes[f.idx], conv.EvalCtx)
+		if err != nil {
+			return errors.Wrapf(err, "column %s", name)
+		}
+		conv.Datums[f.idx] = datum
+	}
+
+	// Set any target columns missing from the parquet file to DNull.
+	for i := range conv.Datums {
+		if _, isTargetCol := conv.IsTargetCol[i]; isTargetCol && conv.Datums[i] == nil {
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// parquetReadBatchSize is the number of rows assembled at once from the
+// columns of a parquet file.
+const parquetReadBatchSize = 1024
+
+// parquetStream is an importRowProducer over the rows of a parquet file. The
+// file is read one row group at a time, and the values of the leaf columns of
+// a row group are then assembled into records in batches of rows.
+type parquetStream struct {
+	data []byte
+	meta *parquet.FileMetaData
+	// leaves holds the path to each leaf column of the file.
+	leaves  [][]*parquetFileColumn
+	numRows int64
+	read    int64
+
+	// rowGroup is the index of the next row group to read, and chunks and
+	// positions hold the column chunks of the current row group and the
+	// position of the next value to assemble in each of them.
+	rowGroup  int
+	chunks    []*parquetColumnChunk
+	positions []struct{ level, value int }
+
+	batch []map[string]interface{}
+	row   map[string]interface{}
+	err   error
+}
+
+var _ importRowProducer = &parquetStream{}
+
+// Progress implements importRowProducer interface.
+func (p *parquetStream) Progress() float32 {
+	if p.numRows == 0 {
+		return 0
+	}
+	return float32(p.read) / float32(p.numRows)
+}
+
+// Scan implements importRowProducer interface.
+func (p *parquetStream) Scan() bool {
+	if p.err != nil || p.read >= p.numRows {
+		return false
+	}
+	if len(p.batch) == 0 {
+		if p.batch, p.err = p.readBatch(); p.err != nil {
+			return false
+		}
+	}
+	p.row, p.batch = p.batch[0], p.batch[1:]
+	p.read++
+	return true
+}
+
+// readRowGroup decodes the column chunks of the next row group of the file.
+func (p *parquetStream) readRowGroup() error {
+	if p.rowGroup >= len(p.meta.RowGroups) {
+		return errors.New("unexpected end of parquet file")
+	}
+	rg := p.meta.RowGroups[p.rowGroup]
+	p.rowGroup++
+	if len(rg.Columns) != len(p.leaves) {
+		return errors.Errorf("expected %d columns in parquet row group, found %d",
+			len(p.leaves), len(rg.Columns))
+	}
+	p.chunks = make([]*parquetColumnChunk, len(p.leaves))
+	p.positions = make([]struct{ level, value int }, len(p.leaves))
+	for i, path := range p.leaves {
+		leaf := path[len(path)-1]
+		c, err := readParquetColumnChunk(p.data, rg.Columns[i], leaf.elem, leaf.repLevel, leaf.defLevel)
+		if err != nil {
+			return errors.Wrapf(err, "reading parquet column %s", leaf.name)
+		}
+		p.chunks[i] = c
+	}
+	return nil
+}
+
+// readBatch assembles the next batch of rows of the current row group,
+// reading the next row group if the current one was fully read.
+func (p *parquetStream) readBatch() ([]map[string]interface{}, error) {
+	if p.chunks == nil || len(p.leaves) == 0 ||
+		p.positions[0].level >= len(p.chunks[0].defLevels) {
+		if err := p.readRowGroup(); err != nil {
+			return nil, err
+		}
+	}
+
+	n := p.numRows - p.read
+	if n > parquetReadBatchSize {
+		n = parquetReadBatchSize
+	}
+	records := make([]map[string]interface{}, 0, n)
+	for i, path := range p.leaves {
+		c, pos := p.chunks[i], &p.positions[i]
+		name := path[len(path)-1].name
+		maxDef := path[len(path)-1].defLevel
+		// The first leaf column determines the number of rows of the batch.
+		limit := int(n)
+		if i > 0 {
+			limit = len(records)
+		}
+		// A repetition level of 0 starts a new record.
+		idx := make([]int, len(path))
+		row := -1
+		for ; pos.level < len(c.defLevels); pos.level++ {
+			r, d := c.repLevels[pos.level], c.defLevels[pos.level]
+			if r == 0 {
+				if row+1 == limit {
+					break
+				}
+				row++
+				if i == 0 {
+					records = append(records, make(map[string]interface{}))
+				}
+			}
+			if row < 0 || row >= len(records) {
+				return nil, errors.Errorf("unexpected number of rows in parquet column %s", name)
+			}
+			var v interface{}
+			if d == maxDef {
+				if pos.value >= len(c.values) {
+					return nil, errors.Errorf("unexpected number of values in parquet column %s", name)
+				}
+				v = c.values[pos.value]
+				pos.value++
+			}
+			assembleParquetValue(records[row], path, v, r, d, idx)
+		}
+		if row != len(records)-1 {
+			return nil, errors.Errorf("unexpected number of rows in parquet column %s", name)
+		}
+	}
+	if len(records) == 0 {
+		return nil, errors.New("unexpected end of parquet row group")
+	}
+	return records, nil
+}
+
+// Err implements importRowProducer interface.
+func (p *parquetStream) Err() error {
+	return p.err
+}
+
+// Row implements importRowProducer interface.
+func (p *parquetStream) Row() (interface{}, error) {
+	return p.row, nil
+}
+
+// Skip implements importRowProducer interface.
+func (p *parquetStream) Skip() error {
+	// The row was already read by Scan.
+	return nil
+}
+
+type parquetInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.ParquetOptions
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	kvCh chan row.KVBatch,
+	tableDesc *tabledesc.Immutable,
+	targetCols tree.NameList,
+	opts roachpb.ParquetOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) *parquetInputReader {
+	return &parquetInputReader{
+		importContext: &parallelImportContext{
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			targetCols: targetCols,
+			kvCh:       kvCh,
+		},
+		opts: opts,
+	}
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user string,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, p.readFile, makeExternalStorage, user)
+}
+
+// newImportParquetPipeline returns the producer and the consumer of the rows
+// of the given parquet file, mapping the top-level columns of the file onto
+// the target columns of the table by name.
+func newImportParquetPipeline(
+	p *parquetInputReader, data []byte,
+) (importRowProducer, importRowConsumer, error) {
+	meta, err := readParquetFileMetaData(data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reading parquet file")
+	}
+	cols, leaves, err := makeParquetSchema(meta.Schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targetCols := make(map[string]struct{}, len(p.importContext.targetCols))
+	for _, name := range p.importContext.targetCols {
+		targetCols[string(name)] = struct{}{}
+	}
+	colIdxByName := make(map[string]int)
+	for idx, col := range p.importContext.tableDesc.VisibleColumns() {
+		if _, ok := targetCols[col.Name]; ok || len(targetCols) == 0 {
+			colIdxByName[col.Name] = idx
+		}
+	}
+
+	consumer := &parquetConsumer{}
+	for _, col := range cols {
+		name := lex.NormalizeName(col.name)
+		idx, ok := colIdxByName[name]
+		if !ok {
+			if p.opts.StrictMode {
+				return nil, nil, errors.Errorf("could not find column for parquet field %s", name)
+			}
+			continue
+		}
+		delete(colIdxByName, name)
+		consumer.fields = append(consumer.fields, parquetField{col: col, idx: idx})
+	}
+	if p.opts.StrictMode {
+		for _, col := range p.importContext.tableDesc.VisibleColumns() {
+			if _, ok := colIdxByName[col.Name]; ok {
+				return nil, nil, errors.Errorf("column %s was not found in the parquet file", col.Name)
+			}
+		}
+	}
+
+	producer := &parquetStream{
+		data:    data,
+		meta:    meta,
+		leaves:  leaves,
+		numRows: meta.NumRows,
+	}
+	return producer, consumer, nil
+}
+
+func (p *parquetInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	// The metadata of parquet files is stored in their footer and the column
+	// chunks are read out of order, so the file is buffered in memory as
+	// external storage only provides sequential reads.
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	producer, consumer, err := newImportParquetPipeline(p, data)
+	if err != nil {
+		return err
+	}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
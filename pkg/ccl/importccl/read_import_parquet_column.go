@@ -0,0 +1,537 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/bits"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// This file decodes the column chunks of parquet files into values along
+// with their repetition and definition levels. The file metadata and page
+// headers are read with the thrift types of the parquet library used by
+// EXPORT, and pages are decompressed with its block compressors, but its
+// record assembly is bypassed: it doesn't expose the levels, and it shifts
+// the values of repeated groups following a NULL element into the next
+// records.
+
+var parquetMagic = []byte("PAR1")
+
+// readParquetFileMetaData reads the metadata stored in the footer of a parquet
+// file.
+func readParquetFileMetaData(data []byte) (*parquet.FileMetaData, error) {
+	n := len(parquetMagic)
+	if len(data) < 2*n+4 || !bytes.Equal(data[:n], parquetMagic) ||
+		!bytes.Equal(data[len(data)-n:], parquetMagic) {
+		return nil, errors.New("not a parquet file")
+	}
+	size := int64(binary.LittleEndian.Uint32(data[len(data)-n-4:]))
+	end := int64(len(data) - n - 4)
+	if size <= 0 || size > end-int64(n) {
+		return nil, errors.Errorf("invalid parquet footer length %d", size)
+	}
+	meta := parquet.NewFileMetaData()
+	if _, err := readParquetThrift(meta, data[end-size:end]); err != nil {
+		return nil, errors.Wrap(err, "reading parquet file metadata")
+	}
+	return meta, nil
+}
+
+type parquetThriftStruct interface {
+	Read(thrift.TProtocol) error
+}
+
+// readParquetThrift decodes a thrift struct from the start of the buffer, and
+// returns the number of bytes it was encoded in.
+func readParquetThrift(s parquetThriftStruct, b []byte) (int, error) {
+	r := bytes.NewReader(b)
+	if err := s.Read(thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: r})); err != nil {
+		return 0, err
+	}
+	return len(b) - r.Len(), nil
+}
+
+// parquetColumnChunk holds the content of a column chunk of a parquet file.
+// values only holds the non-NULL values, i.e. the values whose definition
+// level is the maximum definition level of the column.
+type parquetColumnChunk struct {
+	values    []interface{}
+	repLevels []int32
+	defLevels []int32
+}
+
+// readParquetColumnChunk decodes the pages of a column chunk of a leaf column,
+// given its schema element and its maximum repetition and definition levels.
+func readParquetColumnChunk(
+	data []byte, chunk *parquet.ColumnChunk, elem *parquet.SchemaElement, maxRep, maxDef int32,
+) (*parquetColumnChunk, error) {
+	if chunk.FilePath != nil {
+		return nil, errors.Errorf("parquet column chunks stored in other files are not supported")
+	}
+	cm := chunk.MetaData
+	if cm == nil {
+		return nil, errors.New("missing parquet column chunk metadata")
+	}
+	if cm.Type != elem.GetType() {
+		return nil, errors.Errorf("invalid type %s for parquet column chunk of type %s",
+			cm.Type, elem.GetType())
+	}
+	compressor, ok := goparquet.GetRegisteredBlockCompressors()[cm.Codec]
+	if !ok {
+		return nil, errors.Errorf("unsupported parquet compression codec %s", cm.Codec)
+	}
+
+	start := cm.DataPageOffset
+	if cm.DictionaryPageOffset != nil && *cm.DictionaryPageOffset > 0 && *cm.DictionaryPageOffset < start {
+		start = *cm.DictionaryPageOffset
+	}
+	end := start + cm.TotalCompressedSize
+	if start < 0 || end > int64(len(data)) || end < start {
+		return nil, errors.New("invalid parquet column chunk offsets")
+	}
+
+	c := &parquetColumnChunk{}
+	var dict []interface{}
+	for pos := start; pos < end && int64(len(c.defLevels)) < cm.NumValues; {
+		ph := parquet.NewPageHeader()
+		n, err := readParquetThrift(ph, data[pos:end])
+		if err != nil {
+			return nil, errors.Wrap(err, "reading parquet page header")
+		}
+		pos += int64(n)
+		if ph.CompressedPageSize < 0 || pos+int64(ph.CompressedPageSize) > end {
+			return nil, errors.New("invalid parquet page size")
+		}
+		page := data[pos : pos+int64(ph.CompressedPageSize)]
+		pos += int64(ph.CompressedPageSize)
+
+		switch ph.Type {
+		case parquet.PageType_DICTIONARY_PAGE:
+			h := ph.DictionaryPageHeader
+			if h == nil || dict != nil {
+				return nil, errors.New("invalid parquet dictionary page")
+			}
+			b, err := compressor.DecompressBlock(page)
+			if err != nil {
+				return nil, err
+			}
+			if dict, err = decodeParquetPlain(b, elem, int(h.NumValues)); err != nil {
+				return nil, err
+			}
+
+		case parquet.PageType_DATA_PAGE:
+			h := ph.DataPageHeader
+			if h == nil {
+				return nil, errors.New("missing parquet data page header")
+			}
+			b, err := compressor.DecompressBlock(page)
+			if err != nil {
+				return nil, err
+			}
+			n := int(h.NumValues)
+			var rls, dls []int32
+			if b, rls, err = decodeParquetLevelsV1(b, h.RepetitionLevelEncoding, maxRep, n); err != nil {
+				return nil, errors.Wrap(err, "reading repetition levels")
+			}
+			if b, dls, err = decodeParquetLevelsV1(b, h.DefinitionLevelEncoding, maxDef, n); err != nil {
+				return nil, errors.Wrap(err, "reading definition levels")
+			}
+			if err := c.addPage(b, h.Encoding, elem, dict, rls, dls, maxDef); err != nil {
+				return nil, err
+			}
+
+		case parquet.PageType_DATA_PAGE_V2:
+			h := ph.DataPageHeaderV2
+			if h == nil {
+				return nil, errors.New("missing parquet data page header")
+			}
+			// The levels are stored uncompressed ahead of the values.
+			rlLen, dlLen := int(h.RepetitionLevelsByteLength), int(h.DefinitionLevelsByteLength)
+			if rlLen < 0 || dlLen < 0 || rlLen+dlLen > len(page) {
+				return nil, errors.New("invalid parquet page levels length")
+			}
+			n := int(h.NumValues)
+			rls, err := decodeParquetLevels(page[:rlLen], maxRep, n)
+			if err != nil {
+				return nil, errors.Wrap(err, "reading repetition levels")
+			}
+			dls, err := decodeParquetLevels(page[rlLen:rlLen+dlLen], maxDef, n)
+			if err != nil {
+				return nil, errors.Wrap(err, "reading definition levels")
+			}
+			b := page[rlLen+dlLen:]
+			if h.IsCompressed {
+				if b, err = compressor.DecompressBlock(b); err != nil {
+					return nil, err
+				}
+			}
+			if err := c.addPage(b, h.Encoding, elem, dict, rls, dls, maxDef); err != nil {
+				return nil, err
+			}
+
+		default:
+			// Index pages are not needed to read the column.
+		}
+	}
+	if int64(len(c.defLevels)) != cm.NumValues {
+		return nil, errors.Errorf("expected %d values in parquet column chunk, found %d",
+			cm.NumValues, len(c.defLevels))
+	}
+	return c, nil
+}
+
+// addPage decodes the values of a data page, given its levels.
+func (c *parquetColumnChunk) addPage(
+	b []byte,
+	enc parquet.Encoding,
+	elem *parquet.SchemaElement,
+	dict []interface{},
+	rls, dls []int32,
+	maxDef int32,
+) error {
+	n := 0
+	for _, d := range dls {
+		if d == maxDef {
+			n++
+		}
+	}
+	values, err := decodeParquetValues(b, enc, elem, dict, n)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s values", enc)
+	}
+	c.values = append(c.values, values...)
+	c.repLevels = append(c.repLevels, rls...)
+	c.defLevels = append(c.defLevels, dls...)
+	return nil
+}
+
+// decodeParquetLevelsV1 decodes the levels of a version 1 data page, which are
+// prefixed with their length, and returns the rest of the page.
+func decodeParquetLevelsV1(
+	b []byte, enc parquet.Encoding, maxLevel int32, n int,
+) ([]byte, []int32, error) {
+	if maxLevel == 0 {
+		// The levels are omitted when they are all zero.
+		return b, make([]int32, n), nil
+	}
+	if enc != parquet.Encoding_RLE {
+		return nil, nil, errors.Errorf("unsupported encoding %s", enc)
+	}
+	if len(b) < 4 {
+		return nil, nil, errors.New("invalid levels length")
+	}
+	size := binary.LittleEndian.Uint32(b)
+	b = b[4:]
+	if uint64(size) > uint64(len(b)) {
+		return nil, nil, errors.New("invalid levels length")
+	}
+	levels, err := decodeParquetLevels(b[:size], maxLevel, n)
+	return b[size:], levels, err
+}
+
+// decodeParquetLevels decodes n levels encoded with the RLE/bit-packing hybrid
+// encoding.
+func decodeParquetLevels(b []byte, maxLevel int32, n int) ([]int32, error) {
+	levels := make([]int32, n)
+	if maxLevel == 0 {
+		return levels, nil
+	}
+	vs, err := decodeParquetHybrid(b, bits.Len32(uint32(maxLevel)), n)
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range vs {
+		if int32(v) > maxLevel {
+			return nil, errors.Errorf("invalid level %d", v)
+		}
+		levels[i] = int32(v)
+	}
+	return levels, nil
+}
+
+// decodeParquetHybrid decodes n values encoded with the RLE/bit-packing hybrid
+// encoding, which alternates runs of a repeated value and groups of 8
+// bit-packed values.
+func decodeParquetHybrid(b []byte, bitWidth int, n int) ([]uint64, error) {
+	if bitWidth < 0 || bitWidth > 64 {
+		return nil, errors.Errorf("invalid bit width %d", bitWidth)
+	}
+	values := make([]uint64, 0, n)
+	for len(values) < n {
+		header, k := binary.Uvarint(b)
+		if k <= 0 {
+			return nil, errors.New("invalid run header")
+		}
+		b = b[k:]
+		if header&1 == 0 {
+			// A run of a repeated value, stored in the fewest whole bytes.
+			count, width := int(header>>1), (bitWidth+7)/8
+			if len(b) < width || count > n-len(values) {
+				return nil, errors.New("invalid run")
+			}
+			var v uint64
+			for i := 0; i < width; i++ {
+				v |= uint64(b[i]) << (8 * uint(i))
+			}
+			b = b[width:]
+			for i := 0; i < count; i++ {
+				values = append(values, v)
+			}
+			continue
+		}
+		// Groups of 8 bit-packed values. The last group may be padded.
+		count := int(header>>1) * 8
+		size := count * bitWidth / 8
+		if count <= 0 || len(b) < size {
+			return nil, errors.New("invalid bit-packed run")
+		}
+		vs := unpackParquetBits(b[:size], bitWidth, count)
+		b = b[size:]
+		if count > n-len(values) {
+			vs = vs[:n-len(values)]
+		}
+		values = append(values, vs...)
+	}
+	return values, nil
+}
+
+// unpackParquetBits returns the n values of the given bit width packed from
+// the least significant bit of the buffer onwards.
+func unpackParquetBits(b []byte, bitWidth int, n int) []uint64 {
+	values := make([]uint64, n)
+	for i := range values {
+		var v uint64
+		for j := 0; j < bitWidth; j++ {
+			bit := i*bitWidth + j
+			v |= uint64(b[bit/8]>>(uint(bit)%8)&1) << uint(j)
+		}
+		values[i] = v
+	}
+	return values
+}
+
+// decodeParquetValues decodes n values of a data page. Values are returned as
+// the native type of the physical type of the column, and byte arrays, fixed
+// length byte arrays and INT96 values as strings.
+func decodeParquetValues(
+	b []byte, enc parquet.Encoding, elem *parquet.SchemaElement, dict []interface{}, n int,
+) ([]interface{}, error) {
+	switch enc {
+	case parquet.Encoding_PLAIN:
+		return decodeParquetPlain(b, elem, n)
+
+	case parquet.Encoding_PLAIN_DICTIONARY, parquet.Encoding_RLE_DICTIONARY:
+		if len(b) < 1 {
+			return nil, errors.New("missing bit width")
+		}
+		indexes, err := decodeParquetHybrid(b[1:], int(b[0]), n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i, idx := range indexes {
+			if idx >= uint64(len(dict)) {
+				return nil, errors.Errorf("invalid dictionary index %d", idx)
+			}
+			values[i] = dict[idx]
+		}
+		return values, nil
+
+	case parquet.Encoding_RLE:
+		if elem.GetType() != parquet.Type_BOOLEAN || len(b) < 4 {
+			break
+		}
+		vs, err := decodeParquetHybrid(b[4:], 1, n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i, v := range vs {
+			values[i] = v == 1
+		}
+		return values, nil
+
+	case parquet.Encoding_DELTA_BINARY_PACKED:
+		vs, _, err := decodeParquetDeltaBinaryPacked(b, n)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i, v := range vs {
+			switch elem.GetType() {
+			case parquet.Type_INT32:
+				values[i] = int32(v)
+			case parquet.Type_INT64:
+				values[i] = v
+			default:
+				return nil, errors.Errorf("invalid type %s", elem.GetType())
+			}
+		}
+		return values, nil
+
+	case parquet.Encoding_DELTA_LENGTH_BYTE_ARRAY:
+		values, _, err := decodeParquetDeltaLengthByteArray(b, n)
+		return values, err
+
+	case parquet.Encoding_DELTA_BYTE_ARRAY:
+		prefixes, k, err := decodeParquetDeltaBinaryPacked(b, n)
+		if err != nil {
+			return nil, err
+		}
+		suffixes, _, err := decodeParquetDeltaLengthByteArray(b[k:], n)
+		if err != nil {
+			return nil, err
+		}
+		var prev string
+		for i, suffix := range suffixes {
+			if prefixes[i] < 0 || prefixes[i] > int64(len(prev)) {
+				return nil, errors.Errorf("invalid prefix length %d", prefixes[i])
+			}
+			prev = prev[:prefixes[i]] + suffix.(string)
+			suffixes[i] = prev
+		}
+		return suffixes, nil
+	}
+	return nil, errors.Errorf("unsupported encoding for %s column", elem.GetType())
+}
+
+// decodeParquetPlain decodes n values stored with the PLAIN encoding.
+func decodeParquetPlain(b []byte, elem *parquet.SchemaElement, n int) ([]interface{}, error) {
+	values := make([]interface{}, n)
+	if elem.GetType() == parquet.Type_BOOLEAN {
+		if len(b) < (n+7)/8 {
+			return nil, errors.New("unexpected end of page")
+		}
+		for i, v := range unpackParquetBits(b, 1, n) {
+			values[i] = v == 1
+		}
+		return values, nil
+	}
+
+	size := 0
+	switch elem.GetType() {
+	case parquet.Type_INT32, parquet.Type_FLOAT:
+		size = 4
+	case parquet.Type_INT64, parquet.Type_DOUBLE:
+		size = 8
+	case parquet.Type_INT96:
+		size = 12
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		size = int(elem.GetTypeLength())
+	}
+	for i := range values {
+		if elem.GetType() == parquet.Type_BYTE_ARRAY {
+			if len(b) < 4 {
+				return nil, errors.New("unexpected end of page")
+			}
+			size = int(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		}
+		if size < 0 || len(b) < size {
+			return nil, errors.New("unexpected end of page")
+		}
+		switch elem.GetType() {
+		case parquet.Type_INT32:
+			values[i] = int32(binary.LittleEndian.Uint32(b))
+		case parquet.Type_INT64:
+			values[i] = int64(binary.LittleEndian.Uint64(b))
+		case parquet.Type_FLOAT:
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case parquet.Type_DOUBLE:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+		default:
+			values[i] = string(b[:size])
+		}
+		b = b[size:]
+	}
+	return values, nil
+}
+
+// decodeParquetDeltaBinaryPacked decodes n integers stored with the
+// DELTA_BINARY_PACKED encoding, and returns the number of bytes they were
+// stored in.
+func decodeParquetDeltaBinaryPacked(b []byte, n int) ([]int64, int, error) {
+	orig := len(b)
+	var header [3]uint64
+	for i := range header {
+		v, k := binary.Uvarint(b)
+		if k <= 0 {
+			return nil, 0, errors.New("invalid delta header")
+		}
+		header[i] = v
+		b = b[k:]
+	}
+	blockSize, numMiniBlocks, total := header[0], header[1], header[2]
+	if numMiniBlocks == 0 || blockSize%numMiniBlocks != 0 || (blockSize/numMiniBlocks)%8 != 0 ||
+		total < uint64(n) {
+		return nil, 0, errors.New("invalid delta header")
+	}
+	miniBlockSize := int(blockSize / numMiniBlocks)
+	v, k := binary.Varint(b)
+	if k <= 0 {
+		return nil, 0, errors.New("invalid delta header")
+	}
+	b = b[k:]
+
+	values := make([]int64, 0, n+1)
+	values = append(values, v)
+	for uint64(len(values)) < total {
+		minDelta, k := binary.Varint(b)
+		if k <= 0 || len(b) < k+int(numMiniBlocks) {
+			return nil, 0, errors.New("invalid delta block")
+		}
+		widths := b[k : k+int(numMiniBlocks)]
+		b = b[k+int(numMiniBlocks):]
+		// The miniblocks after the last value are omitted.
+		for _, width := range widths {
+			if uint64(len(values)) >= total {
+				break
+			}
+			size := miniBlockSize * int(width) / 8
+			if width > 64 || len(b) < size {
+				return nil, 0, errors.New("invalid delta miniblock")
+			}
+			for _, d := range unpackParquetBits(b[:size], int(width), miniBlockSize) {
+				if uint64(len(values)) >= total {
+					break
+				}
+				v += minDelta + int64(d)
+				values = append(values, v)
+			}
+			b = b[size:]
+		}
+	}
+	return values[:n], orig - len(b), nil
+}
+
+// decodeParquetDeltaLengthByteArray decodes n byte arrays stored with the
+// DELTA_LENGTH_BYTE_ARRAY encoding, and returns the number of bytes they were
+// stored in.
+func decodeParquetDeltaLengthByteArray(b []byte, n int) ([]interface{}, int, error) {
+	lengths, k, err := decodeParquetDeltaBinaryPacked(b, n)
+	if err != nil {
+		return nil, 0, err
+	}
+	values := make([]interface{}, n)
+	for i, l := range lengths {
+		if l < 0 || int64(len(b)-k) < l {
+			return nil, 0, errors.New("unexpected end of page")
+		}
+		values[i] = string(b[k : k+int(l)])
+		k += int(l)
+	}
+	return values, k, nil
+}
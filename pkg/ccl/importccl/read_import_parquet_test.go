@@ -0,0 +1,300 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/fraugster/parquet-go/parquetschema"
+	"github.com/stretchr/testify/require"
+)
+
+// readParquetTestFile reads the records of a parquet file with the reader
+// used by IMPORT, and returns them along with the top-level columns of the
+// file.
+func readParquetTestFile(
+	t *testing.T, data []byte,
+) (map[string]*parquetFileColumn, []map[string]interface{}) {
+	meta, err := readParquetFileMetaData(data)
+	require.NoError(t, err)
+	schema, leaves, err := makeParquetSchema(meta.Schema)
+	require.NoError(t, err)
+	cols := make(map[string]*parquetFileColumn)
+	for _, col := range schema {
+		cols[col.name] = col
+	}
+
+	stream := &parquetStream{data: data, meta: meta, leaves: leaves, numRows: meta.NumRows}
+	var records []map[string]interface{}
+	for stream.Scan() {
+		row, err := stream.Row()
+		require.NoError(t, err)
+		records = append(records, row.(map[string]interface{}))
+	}
+	require.NoError(t, stream.Err())
+	require.Len(t, records, int(meta.NumRows))
+	if meta.NumRows > 0 {
+		require.Equal(t, float32(1), stream.Progress())
+	}
+	return cols, records
+}
+
+// TestParquetToDatum verifies the conversion of the columns of a parquet file
+// laid out the way Spark writes them, including legacy annotations and nested
+// types, to datums.
+func TestParquetToDatum(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sd, err := parquetschema.ParseSchemaDefinition(`message spark_schema {
+  optional int32 id;
+  optional int96 ts;
+  optional int64 created (TIMESTAMP(MILLIS, true));
+  optional int64 local (TIMESTAMP(MICROS, false));
+  optional int32 day (DATE);
+  optional fixed_len_byte_array(5) price (DECIMAL(10, 2));
+  optional int64 amount (DECIMAL(18, 3));
+  optional binary name (STRING);
+  optional binary raw;
+  optional group tags (LIST) {
+    repeated group list {
+      optional binary element (STRING);
+    }
+  }
+  optional group legacy (LIST) {
+    repeated int32 array;
+  }
+  repeated int64 scores;
+  optional group attrs (MAP) {
+    repeated group key_value {
+      required binary key (STRING);
+      optional int64 value;
+    }
+  }
+  optional group point {
+    required double x;
+    optional double y;
+  }
+}`)
+	require.NoError(t, err)
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	var buf bytes.Buffer
+	w := goparquet.NewFileWriter(&buf, goparquet.WithSchemaDefinition(sd))
+	require.NoError(t, w.AddData(map[string]interface{}{
+		"id":      int32(5),
+		"ts":      goparquet.TimeToInt96(ts),
+		"created": ts.UnixNano() / int64(time.Millisecond),
+		"local":   ts.UnixNano() / int64(time.Microsecond),
+		"day":     int32(18263),
+		"price":   []byte{0xff, 0xff, 0xff, 0xcf, 0xc7},
+		"amount":  int64(1500),
+		"name":    []byte("hello"),
+		"raw":     []byte("1.5"),
+		"tags": map[string]interface{}{"list": []map[string]interface{}{
+			{"element": []byte("a")}, {}, {"element": []byte("b")},
+		}},
+		"legacy": map[string]interface{}{"array": []int32{1, 2}},
+		"scores": []int64{10, 20},
+		"attrs": map[string]interface{}{"key_value": []map[string]interface{}{
+			{"key": []byte("k1"), "value": int64(1)}, {"key": []byte("k2")},
+		}},
+		"point": map[string]interface{}{"x": 1.5},
+	}))
+	// NULL elements of lists must not shift the elements of the following
+	// rows.
+	require.NoError(t, w.AddData(map[string]interface{}{
+		"tags": map[string]interface{}{"list": []map[string]interface{}{
+			{}, {"element": []byte("c")},
+		}},
+	}))
+	require.NoError(t, w.AddData(map[string]interface{}{
+		"tags": map[string]interface{}{"list": []map[string]interface{}{{}}},
+	}))
+	require.NoError(t, w.AddData(map[string]interface{}{}))
+	require.NoError(t, w.Close())
+
+	cols, records := readParquetTestFile(t, buf.Bytes())
+	require.Len(t, records, 4)
+	record := records[0]
+
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	for _, tc := range []struct {
+		col      string
+		typ      *types.T
+		expected string
+		err      string
+	}{
+		{col: "id", typ: types.Int, expected: "5"},
+		{col: "id", typ: types.Int2, expected: "5"},
+		{col: "id", typ: types.String, expected: "5"},
+		{col: "id", typ: types.Decimal, expected: "5"},
+		{col: "ts", typ: types.Uuid, err: "invalid cast"},
+		{col: "ts", typ: types.TimestampTZ, expected: "2020-01-02 03:04:05.123456+00"},
+		{col: "ts", typ: types.Timestamp, expected: "2020-01-02 03:04:05.123456"},
+		{col: "created", typ: types.TimestampTZ, expected: "2020-01-02 03:04:05.123+00"},
+		{col: "local", typ: types.Timestamp, expected: "2020-01-02 03:04:05.123456"},
+		{col: "day", typ: types.Date, expected: "2020-01-02"},
+		{col: "price", typ: types.Decimal, expected: "-123.45"},
+		{col: "amount", typ: types.Decimal, expected: "1.500"},
+		{col: "amount", typ: types.Float, expected: "1.5"},
+		{col: "name", typ: types.String, expected: "hello"},
+		{col: "name", typ: types.Int, err: "could not parse"},
+		{col: "raw", typ: types.Bytes, expected: `\x312e35`},
+		{col: "raw", typ: types.Float, expected: "1.5"},
+		{col: "tags", typ: types.StringArray, expected: "ARRAY['a', NULL, 'b']"},
+		{col: "tags", typ: types.Jsonb, expected: `["a", null, "b"]`},
+		{col: "tags", typ: types.String, err: "cannot convert parquet list"},
+		{col: "legacy", typ: types.IntArray, expected: "ARRAY[1, 2]"},
+		{col: "legacy", typ: types.StringArray, expected: "ARRAY['1', '2']"},
+		{col: "scores", typ: types.IntArray, expected: "ARRAY[10, 20]"},
+		{col: "attrs", typ: types.Jsonb, expected: `{"k1": 1, "k2": null}`},
+		{col: "attrs", typ: types.String, err: "cannot convert parquet group"},
+		{col: "point", typ: types.Jsonb, expected: `{"x": 1.5, "y": null}`},
+	} {
+		t.Run(tc.col+"/"+tc.typ.SQLString(), func(t *testing.T) {
+			d, err := parquetToDatum(record[tc.col], cols[tc.col], tc.typ, &evalCtx)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			expected, err := rowenc.ParseDatumStringAs(tc.typ, tc.expected, &evalCtx)
+			require.NoError(t, err)
+			require.Equal(t, 0, d.Compare(&evalCtx, expected), "expected %s, got %s", expected, d)
+		})
+	}
+
+	for i, expected := range []string{
+		"ARRAY['a',NULL,'b']", "ARRAY[NULL,'c']", "ARRAY[NULL]", "NULL",
+	} {
+		d, err := parquetToDatum(records[i]["tags"], cols["tags"], types.StringArray, &evalCtx)
+		require.NoError(t, err)
+		require.Equal(t, expected, d.String())
+	}
+	for _, col := range []string{"id", "attrs", "point"} {
+		d, err := parquetToDatum(records[3][col], cols[col], types.Jsonb, &evalCtx)
+		require.NoError(t, err)
+		require.Equal(t, tree.DNull, d, col)
+	}
+}
+
+// TestParquetPages verifies that the records of parquet files are read
+// correctly regardless of the page layout, compression and row groups of the
+// files.
+func TestParquetPages(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sd, err := parquetschema.ParseSchemaDefinition(`message test {
+  required int64 id;
+  optional boolean flag;
+  optional binary name (STRING);
+  optional group tags (LIST) {
+    repeated group list {
+      optional binary element (STRING);
+    }
+  }
+}`)
+	require.NoError(t, err)
+
+	const numRows = 1000
+	for _, tc := range []struct {
+		name string
+		opts []goparquet.FileWriterOption
+	}{
+		{name: "default"},
+		{name: "v2", opts: []goparquet.FileWriterOption{goparquet.WithDataPageV2()}},
+		{name: "gzip", opts: []goparquet.FileWriterOption{
+			goparquet.WithCompressionCodec(parquet.CompressionCodec_GZIP)}},
+		{name: "snappy-v2", opts: []goparquet.FileWriterOption{
+			goparquet.WithCompressionCodec(parquet.CompressionCodec_SNAPPY), goparquet.WithDataPageV2()}},
+		{name: "row-groups", opts: []goparquet.FileWriterOption{goparquet.WithMaxRowGroupSize(4 << 10)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := goparquet.NewFileWriter(&buf, append(tc.opts, goparquet.WithSchemaDefinition(sd))...)
+			for i := 0; i < numRows; i++ {
+				data := map[string]interface{}{"id": int64(i)}
+				if i%3 != 0 {
+					data["flag"] = i%2 == 0
+					data["name"] = []byte(fmt.Sprintf("name%d", i%10))
+				}
+				var elems []map[string]interface{}
+				for j := 0; j < i%4; j++ {
+					if j%2 == 0 {
+						elems = append(elems, map[string]interface{}{})
+					} else {
+						elems = append(elems, map[string]interface{}{"element": []byte("x")})
+					}
+				}
+				if elems != nil {
+					data["tags"] = map[string]interface{}{"list": elems}
+				}
+				require.NoError(t, w.AddData(data))
+			}
+			require.NoError(t, w.Close())
+
+			cols, records := readParquetTestFile(t, buf.Bytes())
+			require.Len(t, records, numRows)
+			evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+			for i, record := range records {
+				require.Equal(t, int64(i), record["id"])
+				if i%3 != 0 {
+					require.Equal(t, i%2 == 0, record["flag"])
+					require.Equal(t, fmt.Sprintf("name%d", i%10), record["name"])
+				} else {
+					require.Nil(t, record["flag"])
+					require.Nil(t, record["name"])
+				}
+				d, err := parquetToDatum(record["tags"], cols["tags"], types.StringArray, &evalCtx)
+				require.NoError(t, err)
+				expected := []string{"NULL", "ARRAY[NULL]", "ARRAY[NULL,'x']", "ARRAY[NULL,'x',NULL]"}[i%4]
+				require.Equal(t, expected, d.String(), "row %d", i)
+			}
+		})
+	}
+}
+
+func TestDecodeParquetDeltaBinaryPacked(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// The examples of the specification of the encoding.
+	for _, tc := range []struct {
+		data     []byte
+		expected []int64
+	}{
+		{
+			data:     []byte{0x80, 0x01, 0x04, 0x05, 0x02, 0x02, 0x00, 0x00, 0x00, 0x00},
+			expected: []int64{1, 2, 3, 4, 5},
+		},
+		{
+			data: []byte{0x80, 0x01, 0x04, 0x08, 0x0e, 0x03, 0x02, 0x00, 0x00, 0x00,
+				0xc0, 0x3f, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			expected: []int64{7, 5, 3, 1, 2, 3, 4, 5},
+		},
+	} {
+		values, n, err := decodeParquetDeltaBinaryPacked(tc.data, len(tc.expected))
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, values)
+		require.Equal(t, len(tc.data), n)
+	}
+}
@@ -132,6 +132,8 @@ func newOracleFactory(cfg replicaoracle.Config) replicaoracle.OracleFactory {
 }
 
 func (f oracleFactory) Oracle(txn *kv.Txn) replicaoracle.Oracle {
+	// Follower reads can be served by any voting or non-voting replica, all of
+	// which the closest oracle considers.
 	if txn != nil && canUseFollowerRead(f.clusterID.Get(), f.st, txn.ReadTimestamp()) {
 		return f.closest.Oracle(txn)
 	}
@@ -820,6 +820,18 @@ memory that the store may consume, for example:
   --store=type=mem,size=90%
 
 </PRE>
+The "raft-log-path" field places the store's Raft log in a separate storage
+engine at the given path, which is typically on another device, for example:
+<PRE>
+
+  --store=path=/mnt/ssd01,raft-log-path=/mnt/nvme01/raft
+
+</PRE>
+An existing store moves its Raft log to the given path when it starts, which
+requires the cluster upgrade to this version to be finalized. Once moved, the
+Raft log cannot be moved back, and the field must always be given. Only the
+Pebble storage engine supports a separate Raft log.
+
 Commas are forbidden in all values, since they are used to separate fields.
 Also, if you use equal signs in the file path to a store, you must use the
 "path" field label.`,
@@ -1174,6 +1186,27 @@ Minimum verbosity of messages written to the log file.
 `,
 	}
 
+	Log = FlagInfo{
+		Name: "log",
+		Description: `
+Logging configuration, in YAML, which routes logging channels to sinks in
+addition to the log files. The channels are dev, ops, health, storage,
+sessions, sql-schema, user-admin, privileges, sensitive-access, sql-exec,
+sql-perf and sql-internal-perf. The sink types are file, stderr, syslog,
+fluent and http. For example:
+<PRE>
+
+  --log='sinks: [{type: syslog, channels: [sessions, sensitive-access],
+                  address: "siem.example.com:601", format: json}]'
+
+</PRE>
+Each sink may set a minimum severity (filter), a format (crdb-v1 or json),
+and whether sensitive data is redacted (redact) or marked (redactable).
+Network sinks buffer their entries and drop them rather than slowing down
+the server when they fall behind.
+`,
+	}
+
 	WriteSize = FlagInfo{
 		Name: "write-size",
 		Description: `
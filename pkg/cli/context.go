@@ -397,6 +397,9 @@ var startCtx struct {
 	// logging settings specific to file logging.
 	logDir log.DirName
 
+	// logConfig is the logging configuration given by --log.
+	logConfig string
+
 	// geoLibsDir is used to specify locations of the GEOS library.
 	geoLibsDir string
 }
@@ -415,6 +418,7 @@ func setStartContextDefaults() {
 	startCtx.listeningURLFile = ""
 	startCtx.pidFile = ""
 	startCtx.inBackground = false
+	startCtx.logConfig = ""
 	startCtx.geoLibsDir = "/usr/local/lib/cockroach"
 }
 
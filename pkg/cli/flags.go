@@ -455,6 +455,7 @@ func init() {
 	for _, cmd := range serverCmds {
 		f := cmd.Flags()
 		varFlag(f, &startCtx.logDir, cliflags.LogDir)
+		stringFlag(f, &startCtx.logConfig, cliflags.Log)
 		varFlag(f,
 			pflag.PFlagFromGoFlag(flag.Lookup(logflags.LogFilesCombinedMaxSizeName)).Value,
 			cliflags.LogDirMaxSize)
@@ -1095,6 +1095,18 @@ func setupAndInitializeLoggingAndProfiling(
 		return nil, err
 	}
 
+	// Route the logging channels to the sinks given by --log, if any.
+	if startCtx.logConfig != "" {
+		logCfg, err := log.ParseConfig(startCtx.logConfig)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := log.ApplyConfig(logCfg); err != nil {
+			return nil, err
+		}
+		telemetry.Count("server.logging.sinks.configured")
+	}
+
 	// Record redaction usage for telemetry.
 	if log.RedactableLogsEnabled() {
 		telemetry.Count("server.logging.redactable_logs.enabled")
@@ -28,7 +28,12 @@ var debugTimeSeriesDumpCmd = &cobra.Command{
 	Use:   "tsdump",
 	Short: "dump all the raw timeseries values in a cluster",
 	Long: `
-Dumps all of the raw timeseries values in a cluster.
+Dumps all of the raw timeseries values in a cluster. Histogram metrics are
+dumped both as their recorded quantiles and, unless disabled with the
+timeseries.storage.histogram_buckets.enabled cluster setting, as the number of
+values recorded into each of their buckets (in series named
+<histogram>-bucket-<n>, where bucket n holds the values in (2^(n-1), 2^n]).
+Bucket counts can be summed across nodes to compute exact percentiles.
 `,
 	RunE: MaybeDecorateGRPCError(func(cmd *cobra.Command, args []string) error {
 		ctx, cancel := context.WithCancel(context.Background())
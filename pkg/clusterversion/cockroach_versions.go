@@ -76,6 +76,18 @@ const (
 	VersionHBAForNonTLS
 	Version20_2
 	VersionStart21_1
+	VersionNonVotingReplicas
+	VersionBoundedStaleness
+	VersionRowLevelTTL
+	VersionReadCommitted
+	VersionMVCCRangeTombstones
+	VersionChangefeedFormats
+	VersionChangefeedProjections
+	VersionLoadBasedRebalancingDimensions
+	VersionSharedLocks
+	VersionMultiRegionDatabases
+	VersionChangefeedWebhookSink
+	VersionSeparatedRaftLog
 
 	// Add new versions here (step one of two).
 )
@@ -432,6 +444,88 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     VersionStart21_1,
 		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 1},
 	},
+	{
+		// VersionNonVotingReplicas enables the num_voters zone config field and
+		// the NON_VOTER replica type. Non-voting replicas receive the raft log and
+		// serve follower reads, but don't participate in quorum.
+		Key:     VersionNonVotingReplicas,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 2},
+	},
+	{
+		// VersionBoundedStaleness enables bounded staleness reads, which use the
+		// QueryResolvedTimestamp request to negotiate their timestamp.
+		Key:     VersionBoundedStaleness,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 3},
+	},
+	{
+		// VersionRowLevelTTL enables the ttl_* table storage parameters, which
+		// configure a scheduled job that deletes expired rows.
+		Key:     VersionRowLevelTTL,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 4},
+	},
+	{
+		// VersionReadCommitted enables transactions to run under the READ
+		// COMMITTED isolation level, which is tracked in their TxnMeta.
+		Key:     VersionReadCommitted,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 5},
+	},
+	{
+		// VersionMVCCRangeTombstones enables DeleteRange requests which delete
+		// their span using an MVCC range tombstone.
+		Key:     VersionMVCCRangeTombstones,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 6},
+	},
+	{
+		// VersionChangefeedFormats enables the avro, csv and protobuf changefeed
+		// formats. Nodes at older versions only know the experimental_avro name of
+		// the avro format.
+		Key:     VersionChangefeedFormats,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 7},
+	},
+	{
+		// VersionChangefeedProjections enables the column list and the WHERE clause of
+		// CREATE CHANGEFEED. Nodes at older versions ignore them and would emit every
+		// column of every row.
+		Key:     VersionChangefeedProjections,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 8},
+	},
+	{
+		// VersionLoadBasedRebalancingDimensions enables rebalancing and splitting ranges
+		// by CPU time or bytes written. Nodes at older versions don't gossip those
+		// loads and report the QPS of their ranges to the merge queue.
+		Key:     VersionLoadBasedRebalancingDimensions,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 9},
+	},
+	{
+		// VersionSharedLocks enables the acquisition of unreplicated Shared locks
+		// by SELECT ... FOR SHARE and by foreign key checks. Older nodes cannot
+		// evaluate requests that acquire Shared locks.
+		Key:     VersionSharedLocks,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 10},
+	},
+	{
+		// VersionMultiRegionDatabases enables ALTER DATABASE ... ADD REGION,
+		// DROP REGION and SURVIVE, which store a region config in the database
+		// descriptor. Nodes at older versions drop the region config when they
+		// rewrite the descriptor.
+		Key:     VersionMultiRegionDatabases,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 11},
+	},
+	{
+		// VersionChangefeedWebhookSink enables the webhook-https changefeed sink.
+		// Change aggregators on nodes at older versions don't know the scheme and
+		// fail to create the sink.
+		Key:     VersionChangefeedWebhookSink,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 12},
+	},
+	{
+		// VersionSeparatedRaftLog allows stores to move their Raft log into a
+		// separate Raft engine. Binaries which predate it refuse to open a store
+		// whose persisted cluster version is at or above it, since they would not
+		// find the Raft log of a migrated store.
+		Key:     VersionSeparatedRaftLog,
+		Version: roachpb.Version{Major: 20, Minor: 2, Unstable: 13},
+	},
 
 	// Add new versions here (step two of two).
 })
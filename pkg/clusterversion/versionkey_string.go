@@ -52,11 +52,23 @@ func _() {
 	_ = x[VersionHBAForNonTLS-41]
 	_ = x[Version20_2-42]
 	_ = x[VersionStart21_1-43]
+	_ = x[VersionNonVotingReplicas-44]
+	_ = x[VersionBoundedStaleness-45]
+	_ = x[VersionRowLevelTTL-46]
+	_ = x[VersionReadCommitted-47]
+	_ = x[VersionMVCCRangeTombstones-48]
+	_ = x[VersionChangefeedFormats-49]
+	_ = x[VersionChangefeedProjections-50]
+	_ = x[VersionLoadBasedRebalancingDimensions-51]
+	_ = x[VersionSharedLocks-52]
+	_ = x[VersionMultiRegionDatabases-53]
+	_ = x[VersionChangefeedWebhookSink-54]
+	_ = x[VersionSeparatedRaftLog-55]
 }
 
-const _VersionKey_name = "Version19_1VersionAtomicChangeReplicasTriggerVersionAtomicChangeReplicasVersionPartitionedBackupVersion19_2VersionStart20_1VersionContainsEstimatesCounterVersionChangeReplicasDemotionVersionSecondaryIndexColumnFamiliesVersionNamespaceTableWithSchemasVersionProtectedTimestampsVersionPrimaryKeyChangesVersionAuthLocalAndTrustRejectMethodsVersionPrimaryKeyColumnsOutOfFamilyZeroVersionNoExplicitForeignKeyIndexIDsVersionHashShardedIndexesVersionCreateRolePrivilegeVersionStatementDiagnosticsSystemTablesVersionSchemaChangeJobVersionSavepointsVersion20_1VersionStart20_2VersionGeospatialTypeVersionEnumsVersionRangefeedLeasesVersionAlterColumnTypeGeneralVersionAlterSystemJobsAddCreatedByColumnsVersionAddScheduledJobsTableVersionUserDefinedSchemasVersionNoOriginFKIndexesVersionClientRangeInfosOnBatchResponseVersionNodeMembershipStatusVersionRangeStatsRespHasDescVersionMinPasswordLengthVersionAbortSpanBytesVersionAlterSystemJobsAddSqllivenessColumnsAddNewSystemSqllivenessTableVersionMaterializedViewsVersionBox2DTypeVersionLeasedDatabaseDescriptorsVersionUpdateScheduledJobsSchemaVersionCreateLoginPrivilegeVersionHBAForNonTLSVersion20_2VersionStart21_1"
+const _VersionKey_name = "Version19_1VersionAtomicChangeReplicasTriggerVersionAtomicChangeReplicasVersionPartitionedBackupVersion19_2VersionStart20_1VersionContainsEstimatesCounterVersionChangeReplicasDemotionVersionSecondaryIndexColumnFamiliesVersionNamespaceTableWithSchemasVersionProtectedTimestampsVersionPrimaryKeyChangesVersionAuthLocalAndTrustRejectMethodsVersionPrimaryKeyColumnsOutOfFamilyZeroVersionNoExplicitForeignKeyIndexIDsVersionHashShardedIndexesVersionCreateRolePrivilegeVersionStatementDiagnosticsSystemTablesVersionSchemaChangeJobVersionSavepointsVersion20_1VersionStart20_2VersionGeospatialTypeVersionEnumsVersionRangefeedLeasesVersionAlterColumnTypeGeneralVersionAlterSystemJobsAddCreatedByColumnsVersionAddScheduledJobsTableVersionUserDefinedSchemasVersionNoOriginFKIndexesVersionClientRangeInfosOnBatchResponseVersionNodeMembershipStatusVersionRangeStatsRespHasDescVersionMinPasswordLengthVersionAbortSpanBytesVersionAlterSystemJobsAddSqllivenessColumnsAddNewSystemSqllivenessTableVersionMaterializedViewsVersionBox2DTypeVersionLeasedDatabaseDescriptorsVersionUpdateScheduledJobsSchemaVersionCreateLoginPrivilegeVersionHBAForNonTLSVersion20_2VersionStart21_1VersionNonVotingReplicasVersionBoundedStalenessVersionRowLevelTTLVersionReadCommittedVersionMVCCRangeTombstonesVersionChangefeedFormatsVersionChangefeedProjectionsVersionLoadBasedRebalancingDimensionsVersionSharedLocksVersionMultiRegionDatabasesVersionChangefeedWebhookSinkVersionSeparatedRaftLog"
 
-var _VersionKey_index = [...]uint16{0, 11, 45, 72, 96, 107, 123, 154, 183, 218, 250, 276, 300, 337, 376, 411, 436, 462, 501, 523, 540, 551, 567, 588, 600, 622, 651, 692, 720, 745, 769, 807, 834, 862, 886, 907, 978, 1002, 1018, 1050, 1082, 1109, 1128, 1139, 1155}
+var _VersionKey_index = [...]uint16{0, 11, 45, 72, 96, 107, 123, 154, 183, 218, 250, 276, 300, 337, 376, 411, 436, 462, 501, 523, 540, 551, 567, 588, 600, 622, 651, 692, 720, 745, 769, 807, 834, 862, 886, 907, 978, 1002, 1018, 1050, 1082, 1109, 1128, 1139, 1155, 1179, 1202, 1220, 1240, 1266, 1290, 1318, 1355, 1373, 1400, 1428, 1451}
 
 func (i VersionKey) String() string {
 	if i < 0 || i >= VersionKey(len(_VersionKey_index)-1) {
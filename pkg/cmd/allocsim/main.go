@@ -42,6 +42,7 @@ var numNodes = flag.Int("n", 4, "number of nodes")
 var duration = flag.Duration("duration", math.MaxInt64, "how long to run the simulation for")
 var blockSize = flag.Int("b", 1000, "block size")
 var configFile = flag.String("f", "", "config file that specifies an allocsim workload (overrides -n)")
+var dimension = flag.String("dimension", "", "dimension of load to rebalance and split on (qps, cpu or write_bytes); uses the cluster default if empty")
 
 // Configuration provides a way to configure allocsim via a JSON file.
 // TODO(a-robinson): Consider moving all the above options into the config file.
@@ -56,6 +57,7 @@ type Locality struct {
 	LocalityStr       string `json:"LocalityStr"`
 	NumNodes          int    `json:"NumNodes"`
 	NumWorkers        int    `json:"NumWorkers"`
+	NumScanWorkers    int    `json:"NumScanWorkers"`
 	OutgoingLatencies []*struct {
 		Name    string       `json:"Name"`
 		Latency jsonDuration `json:"Latency"`
@@ -94,7 +96,7 @@ func loadConfig(file string) (Configuration, error) {
 	*workers = config.NumWorkers
 	for _, locality := range config.Localities {
 		*numNodes += locality.NumNodes
-		*workers += locality.NumWorkers
+		*workers += locality.NumWorkers + locality.NumScanWorkers
 	}
 	return config, nil
 }
@@ -125,6 +127,9 @@ type allocStats struct {
 	leases         []int
 	replicaAdds    []int
 	leaseTransfers []int
+	qps            []float64
+	cpu            []float64
+	writeBytes     []float64
 }
 
 func newAllocSim(c *localcluster.Cluster) *allocSim {
@@ -157,6 +162,9 @@ func (a *allocSim) runWithConfig(config Configuration) {
 			startNum := firstNodeInLocality + i
 			go a.worker(node, startNum, numWorkers)
 		}
+		for i := 0; i < locality.NumScanWorkers; i++ {
+			go a.scanWorker(firstNodeInLocality + (i % locality.NumNodes))
+		}
 		firstNodeInLocality += locality.NumNodes
 	}
 	for i := 0; i < config.NumWorkers; i++ {
@@ -210,6 +218,25 @@ func (a *allocSim) worker(dbIdx, startNum, workers int) {
 	}
 }
 
+const scanStmt = `SELECT count(*) FROM allocsim.blocks WHERE id >= $1`
+
+// scanWorker repeatedly scans the blocks written by the other workers through
+// the given node, generating load that is cheap in requests and bytes written
+// but expensive in CPU time.
+func (a *allocSim) scanWorker(dbIdx int) {
+	r, _ := randutil.NewPseudoRand()
+	db := a.Nodes[dbIdx%len(a.Nodes)].DB()
+	for {
+		now := timeutil.Now()
+		if _, err := db.Exec(scanStmt, r.Int63()); err != nil {
+			a.maybeLogError(err)
+		} else {
+			atomic.AddUint64(&a.stats.ops, 1)
+			atomic.AddUint64(&a.stats.totalLatencyNanos, uint64(timeutil.Since(now).Nanoseconds()))
+		}
+	}
+}
+
 func (a *allocSim) roundRobinWorker(startNum, workers int) {
 	r, _ := randutil.NewPseudoRand()
 	for i := 0; ; i++ {
@@ -233,6 +260,9 @@ func (a *allocSim) rangeInfo() allocStats {
 		replicaAdds:    make([]int, len(a.Nodes)),
 		leases:         make([]int, len(a.Nodes)),
 		leaseTransfers: make([]int, len(a.Nodes)),
+		qps:            make([]float64, len(a.Nodes)),
+		cpu:            make([]float64, len(a.Nodes)),
+		writeBytes:     make([]float64, len(a.Nodes)),
 	}
 
 	// Retrieve the metrics for each node and extract the replica and leaseholder
@@ -272,6 +302,15 @@ func (a *allocSim) rangeInfo() allocStats {
 				if v, ok := storeMetrics["leases.transfers.success"]; ok {
 					stats.leaseTransfers[i] += int(v.(float64))
 				}
+				if v, ok := storeMetrics["rebalancing.queriespersecond"]; ok {
+					stats.qps[i] += v.(float64)
+				}
+				if v, ok := storeMetrics["rebalancing.cpunanospersecond"]; ok {
+					stats.cpu[i] += v.(float64)
+				}
+				if v, ok := storeMetrics["rebalancing.writebytespersecond"]; ok {
+					stats.writeBytes[i] += v.(float64)
+				}
 			}
 		}(i)
 	}
@@ -370,10 +409,10 @@ func (a *allocSim) finalStatus() {
 
 	fmt.Println(formatHeader("___stats___________________________", len(a.ranges.stats.replicas), a.localities))
 
-	genStats := func(name string, counts []int) {
+	genStats := func(name string, counts []float64) {
 		var total float64
 		for _, count := range counts {
-			total += float64(count)
+			total += count
 		}
 		mean := total / float64(len(counts))
 		var buf bytes.Buffer
@@ -381,15 +420,25 @@ func (a *allocSim) finalStatus() {
 		for _, count := range counts {
 			var percent, fromMean float64
 			if total != 0 {
-				percent = float64(count) / total * 100
-				fromMean = (float64(count) - mean) / total * 100
+				percent = count / total * 100
+				fromMean = (count - mean) / total * 100
 			}
 			fmt.Fprintf(&buf, " %9.9s", fmt.Sprintf("%.0f/%.0f", percent, fromMean))
 		}
 		fmt.Println(buf.String())
 	}
-	genStats("replicas", a.ranges.stats.replicas)
-	genStats("leases", a.ranges.stats.leases)
+	toFloats := func(counts []int) []float64 {
+		res := make([]float64, len(counts))
+		for i, count := range counts {
+			res[i] = float64(count)
+		}
+		return res
+	}
+	genStats("replicas", toFloats(a.ranges.stats.replicas))
+	genStats("leases", toFloats(a.ranges.stats.leases))
+	genStats("qps", a.ranges.stats.qps)
+	genStats("cpu", a.ranges.stats.cpu)
+	genStats("writes", a.ranges.stats.writeBytes)
 }
 
 func handleStart() bool {
@@ -544,6 +593,13 @@ func main() {
 	if err != nil {
 		log.Fatalf(context.Background(), "%v", err)
 	}
+	if *dimension != "" {
+		_, err := c.Nodes[0].DB().Exec(
+			"SET CLUSTER SETTING kv.allocator.load_based_rebalancing.dimension = $1", *dimension)
+		if err != nil {
+			log.Fatalf(context.Background(), "%v", err)
+		}
+	}
 	if len(config.Localities) != 0 {
 		a.runWithConfig(config)
 	} else {
@@ -107,7 +107,7 @@ func printRebalanceStats(l *logger, db *gosql.DB) error {
 		if err := db.QueryRow(
 			`SELECT (SELECT max(timestamp) FROM system.rangelog) - `+
 				`(SELECT max(timestamp) FROM system.eventlog WHERE "eventType"=$1)`,
-			`node_join`, /* sql.EventLogNodeJoin */
+			`node_join`, /* eventpb.NodeJoin */
 		).Scan(&rebalanceIntervalStr); err != nil {
 			return err
 		}
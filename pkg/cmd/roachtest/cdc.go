@@ -381,7 +381,7 @@ func runCDCSchemaRegistry(ctx context.Context, t *test, c *cluster) {
 
 	// NB: the WITH diff option was not supported until v20.1.
 	withDiff := t.IsBuildVersion("v20.1.0")
-	var opts = []string{`updated`, `resolved`, `format=experimental_avro`, `confluent_schema_registry=$2`}
+	var opts = []string{`updated`, `resolved`, `format=avro`, `confluent_schema_registry=$2`}
 	if withDiff {
 		opts = append(opts, `diff`)
 	}
@@ -240,7 +240,41 @@ func DefaultSystemZoneConfigRef() *ZoneConfig {
 func (z *ZoneConfig) IsComplete() bool {
 	return ((z.NumReplicas != nil) && (z.RangeMinBytes != nil) &&
 		(z.RangeMaxBytes != nil) && (z.GC != nil) &&
-		(!z.InheritedConstraints) && (!z.InheritedLeasePreferences))
+		(!z.InheritedConstraints) && (!z.InheritedLeasePreferences) &&
+		(z.NumVoters == nil || !z.InheritedVoterConstraints()))
+}
+
+// InheritedVoterConstraints returns whether the VoterConstraints field is
+// inherited from the zone's parent.
+func (z *ZoneConfig) InheritedVoterConstraints() bool {
+	return len(z.VoterConstraints) == 0 && !z.NullVoterConstraintsIsEmpty
+}
+
+// GetNumVoters returns the number of voting replicas for the given zone config.
+// All replicas are voters unless num_voters is set.
+//
+// This method will panic if called on a ZoneConfig with an uninitialized
+// NumReplicas field.
+func (z *ZoneConfig) GetNumVoters() int32 {
+	if z.NumReplicas == nil {
+		panic("NumReplicas must not be nil")
+	}
+	if z.NumVoters != nil && *z.NumVoters != 0 {
+		return *z.NumVoters
+	}
+	return *z.NumReplicas
+}
+
+// GetNumNonVoters returns the number of non-voting replicas as defined in the
+// zone config.
+//
+// This method will panic if called on a ZoneConfig with an uninitialized
+// NumReplicas field.
+func (z *ZoneConfig) GetNumNonVoters() int32 {
+	if n := *z.NumReplicas - z.GetNumVoters(); n > 0 {
+		return n
+	}
+	return 0
 }
 
 // ValidateTandemFields returns an error if the ZoneConfig to be written
@@ -251,10 +285,23 @@ func (z *ZoneConfig) ValidateTandemFields() error {
 	for _, constraint := range z.Constraints {
 		numConstrainedRepls += constraint.NumReplicas
 	}
+	var numConstrainedVoters int32
+	for _, constraint := range z.VoterConstraints {
+		numConstrainedVoters += constraint.NumReplicas
+	}
 
 	if numConstrainedRepls > 0 && z.NumReplicas == nil {
 		return fmt.Errorf("when per-replica constraints are set, num_replicas must be set as well")
 	}
+	if z.NumVoters != nil && z.NumReplicas == nil {
+		return fmt.Errorf("when num_voters is set, num_replicas must be set as well")
+	}
+	if numConstrainedVoters > 0 && z.NumVoters == nil {
+		return fmt.Errorf("when per-replica voter_constraints are set, num_voters must be set as well")
+	}
+	if !z.InheritedVoterConstraints() && z.InheritedConstraints {
+		return fmt.Errorf("voter_constraints can not be set unless the constraints are explicitly set as well")
+	}
 	if (z.RangeMinBytes != nil || z.RangeMaxBytes != nil) &&
 		(z.RangeMinBytes == nil || z.RangeMaxBytes == nil) {
 		return fmt.Errorf("range_min_bytes and range_max_bytes must be set together")
@@ -274,6 +321,17 @@ func (z *ZoneConfig) Validate() error {
 		}
 	}
 
+	if z.NumVoters != nil {
+		switch {
+		case *z.NumVoters <= 0:
+			return fmt.Errorf("at least one voting replica is required")
+		case *z.NumVoters == 2:
+			return fmt.Errorf("at least 3 voting replicas are required for multi-replica configurations")
+		case z.NumReplicas != nil && *z.NumReplicas != 0 && *z.NumVoters > *z.NumReplicas:
+			return fmt.Errorf("num_voters cannot be greater than num_replicas")
+		}
+	}
+
 	if z.NumReplicas != nil {
 		switch {
 		case *z.NumReplicas < 0:
@@ -285,7 +343,9 @@ func (z *ZoneConfig) Validate() error {
 				return nil
 			}
 			return fmt.Errorf("at least one replica is required")
-		case *z.NumReplicas == 2:
+		case *z.NumReplicas == 2 && z.NumVoters == nil:
+			// Two voters are worse than one, but one voter and one non-voter are
+			// fine.
 			return fmt.Errorf("at least 3 replicas are required for multi-replica configurations")
 		}
 	}
@@ -309,11 +369,44 @@ func (z *ZoneConfig) Validate() error {
 		return fmt.Errorf("GC.TTLSeconds %d less than minimum allowed 1", z.GC.TTLSeconds)
 	}
 
-	for _, constraints := range z.Constraints {
+	if err := validateConstraints(z.Constraints, z.NumReplicas, "constraints", "replicas"); err != nil {
+		return err
+	}
+	numVoters := z.NumVoters
+	if numVoters == nil {
+		numVoters = z.NumReplicas
+	}
+	if err := validateConstraints(
+		z.VoterConstraints, numVoters, "voter_constraints", "voters",
+	); err != nil {
+		return err
+	}
+
+	for _, leasePref := range z.LeasePreferences {
+		if len(leasePref.Constraints) == 0 {
+			return fmt.Errorf("every lease preference must include at least one constraint")
+		}
+		for _, constraint := range leasePref.Constraints {
+			if constraint.Type == Constraint_DEPRECATED_POSITIVE {
+				return fmt.Errorf("lease preference constraints must either be required " +
+					"(prefixed with a '+') or prohibited (prefixed with a '-')")
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateConstraints validates a set of constraints applying to numReplicas
+// replicas. The field and replicaKind are used in error messages.
+func validateConstraints(
+	constraintsList []ConstraintsConjunction, numReplicas *int32, field, replicaKind string,
+) error {
+	for _, constraints := range constraintsList {
 		for _, constraint := range constraints.Constraints {
 			if constraint.Type == Constraint_DEPRECATED_POSITIVE {
-				return fmt.Errorf("constraints must either be required (prefixed with a '+') or " +
-					"prohibited (prefixed with a '-')")
+				return fmt.Errorf("%s must either be required (prefixed with a '+') or "+
+					"prohibited (prefixed with a '-')", field)
 			}
 		}
 	}
@@ -321,41 +414,29 @@ func (z *ZoneConfig) Validate() error {
 	// We only need to further validate constraints if per-replica constraints
 	// are in use. The old style of constraints that apply to all replicas don't
 	// require validation.
-	if len(z.Constraints) > 1 || (len(z.Constraints) == 1 && z.Constraints[0].NumReplicas != 0) {
+	if len(constraintsList) > 1 || (len(constraintsList) == 1 && constraintsList[0].NumReplicas != 0) {
 		var numConstrainedRepls int64
-		for _, constraints := range z.Constraints {
+		for _, constraints := range constraintsList {
 			if constraints.NumReplicas <= 0 {
-				return fmt.Errorf("constraints must apply to at least one replica")
+				return fmt.Errorf("%s must apply to at least one replica", field)
 			}
 			numConstrainedRepls += int64(constraints.NumReplicas)
 			for _, constraint := range constraints.Constraints {
 				// TODO(a-robinson): Relax this constraint to allow prohibited replicas,
 				// as discussed on #23014.
-				if constraint.Type != Constraint_REQUIRED && z.NumReplicas != nil && constraints.NumReplicas != *z.NumReplicas {
+				if constraint.Type != Constraint_REQUIRED && numReplicas != nil && constraints.NumReplicas != *numReplicas {
 					return fmt.Errorf(
-						"only required constraints (prefixed with a '+') can be applied to a subset of replicas")
+						"only required constraints (prefixed with a '+') can be applied to a subset of %s",
+						replicaKind)
 				}
 			}
 		}
-		if z.NumReplicas != nil && numConstrainedRepls > int64(*z.NumReplicas) {
-			return fmt.Errorf("the number of replicas specified in constraints (%d) cannot be greater "+
-				"than the number of replicas configured for the zone (%d)",
-				numConstrainedRepls, *z.NumReplicas)
-		}
-	}
-
-	for _, leasePref := range z.LeasePreferences {
-		if len(leasePref.Constraints) == 0 {
-			return fmt.Errorf("every lease preference must include at least one constraint")
-		}
-		for _, constraint := range leasePref.Constraints {
-			if constraint.Type == Constraint_DEPRECATED_POSITIVE {
-				return fmt.Errorf("lease preference constraints must either be required " +
-					"(prefixed with a '+') or prohibited (prefixed with a '-')")
-			}
+		if numReplicas != nil && numConstrainedRepls > int64(*numReplicas) {
+			return fmt.Errorf("the number of replicas specified in %s (%d) cannot be greater "+
+				"than the number of %s configured for the zone (%d)",
+				field, numConstrainedRepls, replicaKind, *numReplicas)
 		}
 	}
-
 	return nil
 }
 
@@ -366,6 +447,11 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 		if parent.NumReplicas != nil {
 			z.NumReplicas = proto.Int32(*parent.NumReplicas)
 		}
+		// NumVoters is only meaningful together with NumReplicas, so it's
+		// inherited along with it.
+		if z.NumVoters == nil && parent.NumVoters != nil {
+			z.NumVoters = proto.Int32(*parent.NumVoters)
+		}
 	}
 	if z.RangeMinBytes == nil {
 		if parent.RangeMinBytes != nil {
@@ -389,6 +475,12 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.InheritedConstraints = false
 		}
 	}
+	if z.InheritedVoterConstraints() {
+		if !parent.InheritedVoterConstraints() {
+			z.VoterConstraints = parent.VoterConstraints
+			z.NullVoterConstraintsIsEmpty = parent.NullVoterConstraintsIsEmpty
+		}
+	}
 	if z.InheritedLeasePreferences {
 		if !parent.InheritedLeasePreferences {
 			z.LeasePreferences = parent.LeasePreferences
@@ -406,6 +498,12 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 				z.NumReplicas = proto.Int32(*other.NumReplicas)
 			}
 		}
+		if fieldName == "num_voters" {
+			z.NumVoters = nil
+			if other.NumVoters != nil {
+				z.NumVoters = proto.Int32(*other.NumVoters)
+			}
+		}
 		if fieldName == "range_min_bytes" {
 			z.RangeMinBytes = nil
 			if other.RangeMinBytes != nil {
@@ -429,6 +527,10 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			z.Constraints = other.Constraints
 			z.InheritedConstraints = other.InheritedConstraints
 		}
+		if fieldName == "voter_constraints" {
+			z.VoterConstraints = other.VoterConstraints
+			z.NullVoterConstraintsIsEmpty = other.NullVoterConstraintsIsEmpty
+		}
 		if fieldName == "lease_preferences" {
 			z.LeasePreferences = other.LeasePreferences
 			z.InheritedLeasePreferences = other.InheritedLeasePreferences
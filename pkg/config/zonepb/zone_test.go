@@ -51,6 +51,78 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"at least 3 replicas are required for multi-replica configurations",
 		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				NumVoters:   proto.Int32(0),
+			},
+			"at least one voting replica is required",
+		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				NumVoters:   proto.Int32(2),
+			},
+			"at least 3 voting replicas are required for multi-replica configurations",
+		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(3),
+				NumVoters:   proto.Int32(5),
+			},
+			"num_voters cannot be greater than num_replicas",
+		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(2),
+				NumVoters:   proto.Int32(1),
+			},
+			"",
+		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(5),
+				NumVoters:   proto.Int32(3),
+				VoterConstraints: []ConstraintsConjunction{
+					{
+						Constraints: []Constraint{{Value: "a", Type: Constraint_REQUIRED}},
+						NumReplicas: 2,
+					},
+					{
+						Constraints: []Constraint{{Value: "b", Type: Constraint_REQUIRED}},
+						NumReplicas: 2,
+					},
+				},
+			},
+			`the number of replicas specified in voter_constraints \(4\) cannot be greater than ` +
+				`the number of voters configured for the zone \(3\)`,
+		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(5),
+				NumVoters:   proto.Int32(3),
+				VoterConstraints: []ConstraintsConjunction{
+					{
+						Constraints: []Constraint{{Value: "a", Type: Constraint_PROHIBITED}},
+						NumReplicas: 1,
+					},
+				},
+			},
+			"can be applied to a subset of voters",
+		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(5),
+				NumVoters:   proto.Int32(3),
+				VoterConstraints: []ConstraintsConjunction{
+					{
+						Constraints: []Constraint{{Value: "a", Type: Constraint_REQUIRED}},
+						NumReplicas: 3,
+					},
+				},
+			},
+			"",
+		},
 		{
 			ZoneConfig{
 				NumReplicas:   proto.Int32(1),
@@ -274,6 +346,37 @@ func TestZoneConfigValidateTandemFields(t *testing.T) {
 			},
 			"when per-replica constraints are set, num_replicas must be set as well",
 		},
+		{
+			ZoneConfig{
+				NumVoters: proto.Int32(3),
+			},
+			"when num_voters is set, num_replicas must be set as well",
+		},
+		{
+			ZoneConfig{
+				NumReplicas: proto.Int32(5),
+				VoterConstraints: []ConstraintsConjunction{
+					{
+						Constraints: []Constraint{{Value: "a", Type: Constraint_REQUIRED}},
+						NumReplicas: 2,
+					},
+				},
+			},
+			"when per-replica voter_constraints are set, num_voters must be set as well",
+		},
+		{
+			ZoneConfig{
+				NumReplicas:          proto.Int32(5),
+				NumVoters:            proto.Int32(3),
+				InheritedConstraints: true,
+				VoterConstraints: []ConstraintsConjunction{
+					{
+						Constraints: []Constraint{{Value: "a", Type: Constraint_REQUIRED}},
+					},
+				},
+			},
+			"voter_constraints can not be set unless the constraints are explicitly set as well",
+		},
 		{
 			ZoneConfig{
 				InheritedConstraints:      true,
@@ -773,6 +876,53 @@ func TestConstraintsListYAML(t *testing.T) {
 	}
 }
 
+func TestVoterConstraintsYAML(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		input             string
+		expected          []ConstraintsConjunction
+		expectedInherited bool
+	}{
+		{input: "num_replicas: 5", expectedInherited: true},
+		{input: "voter_constraints: []", expected: []ConstraintsConjunction{}},
+		{
+			input: "voter_constraints: {+region=a: 2}",
+			expected: []ConstraintsConjunction{{
+				NumReplicas: 2,
+				Constraints: []Constraint{{Key: "region", Value: "a", Type: Constraint_REQUIRED}},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			zone := *NewZoneConfig()
+			if err := yaml.UnmarshalStrict([]byte(tc.input), &zone); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(zone.VoterConstraints, tc.expected) {
+				t.Errorf("expected voter constraints %+v, got %+v", tc.expected, zone.VoterConstraints)
+			}
+			if inherited := zone.InheritedVoterConstraints(); inherited != tc.expectedInherited {
+				t.Errorf("expected inherited voter constraints to be %t, got %t", tc.expectedInherited, inherited)
+			}
+			body, err := yaml.Marshal(zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var roundTripped ZoneConfig
+			if err := yaml.UnmarshalStrict(body, &roundTripped); err != nil {
+				t.Fatal(err)
+			}
+			if inherited := roundTripped.InheritedVoterConstraints(); inherited != tc.expectedInherited {
+				t.Errorf("expected round-tripped inherited voter constraints to be %t, got %t",
+					tc.expectedInherited, inherited)
+			}
+		})
+	}
+}
+
 func TestMarshalableZoneConfigRoundTrip(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -155,7 +155,17 @@ var (
 	// (storage/engine/rocksdb/db.cc).
 	LocalTransactionSuffix = roachpb.RKey("txn-")
 
-	// 4. Store local keys
+	// 4. MVCC range tombstone keys
+	//
+	// LocalMVCCRangeTombstonePrefix is the prefix identifying MVCC range
+	// tombstone fragments. The start key of the fragment is appended to this
+	// prefix, encoded using EncodeBytes, so that fragments sort by their start
+	// key and address to the range containing it. The value holds the end key
+	// of the fragment and the timestamps at which it was deleted.
+	LocalMVCCRangeTombstonePrefix = roachpb.Key(makeKey(localPrefix, roachpb.RKey("m")))
+	LocalMVCCRangeTombstoneMax    = LocalMVCCRangeTombstonePrefix.PrefixEnd()
+
+	// 5. Store local keys
 	//
 	// localStorePrefix is the prefix identifying per-store data.
 	localStorePrefix = makeKey(localPrefix, roachpb.Key("s"))
@@ -178,6 +188,9 @@ var (
 	// localStoreNodeTombstoneSuffix stores key value pairs that map
 	// nodeIDs to time of removal from cluster.
 	localStoreNodeTombstoneSuffix = []byte("ntmb")
+	// localStoreRaftLogSeparatedSuffix marks a store whose Raft log and
+	// HardState have been moved to a dedicated Raft engine.
+	localStoreRaftLogSeparatedSuffix = []byte("rlsp")
 	// localStoreLastUpSuffix stores the last timestamp that a store's node
 	// acknowledged that it was still running. This value will be regularly
 	// refreshed on all stores for a running node; the intention of this value
@@ -155,10 +155,11 @@ package keys
 var _ = [...]interface{}{
 	MinKey,
 
-	// There are four types of local key data enumerated below: replicated
-	// range-ID, unreplicated range-ID, range local, and store-local keys.
+	// There are five types of local key data enumerated below: replicated
+	// range-ID, unreplicated range-ID, range local, MVCC range tombstone, and
+	// store-local keys.
 	// Local keys are constructed using a prefix, an optional infix, and a
-	// suffix. The prefix and infix are used to disambiguate between the four
+	// suffix. The prefix and infix are used to disambiguate between the five
 	// types of local keys listed above, and determines inter-group ordering.
 	// The string comment next to each symbol below is the suffix pertaining to
 	// the corresponding key (and determines intra-group ordering).
@@ -167,6 +168,7 @@ var _ = [...]interface{}{
 	// 	  - RangeID unreplicated keys all share `LocalRangeIDPrefix` and
 	// 		`localRangeIDUnreplicatedInfix`.
 	// 	  - Range local keys all share `LocalRangePrefix`.
+	// 	  - MVCC range tombstone keys all share `LocalMVCCRangeTombstonePrefix`.
 	//	  - Store keys all share `localStorePrefix`.
 	//
 	// `LocalRangeIDPrefix`, `localRangePrefix` and `localStorePrefix` all in
@@ -206,7 +208,13 @@ var _ = [...]interface{}{
 	RangeDescriptorKey,      // "rdsc"
 	TransactionKey,          // "txn-"
 
-	//   4. Store local keys: These contain metadata about an individual store.
+	//   4. MVCC range tombstone keys: These store the fragments of MVCC range
+	//   tombstones written by bulk deletions. They are replicated and
+	//   addressable, and are keyed by the start key of the fragment rather
+	//   than by a suffix. They all share `LocalMVCCRangeTombstonePrefix`.
+	MVCCRangeTombstoneKey,
+
+	//   5. Store local keys: These contain metadata about an individual store.
 	//   They are unreplicated and unaddressable. The typical example is the
 	//   store 'ident' record. They all share `localStorePrefix`.
 	StoreSuggestedCompactionKey, // "comp"
@@ -214,6 +222,7 @@ var _ = [...]interface{}{
 	StoreGossipKey,              // "goss"
 	StoreHLCUpperBoundKey,       // "hlcu"
 	StoreIdentKey,               // "iden"
+	StoreRaftLogSeparatedKey,    // "rlsp"
 	StoreLastUpKey,              // "uptm"
 
 	// The global keyspace includes the meta{1,2}, system, system tenant SQL
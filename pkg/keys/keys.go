@@ -70,6 +70,12 @@ func StoreLastUpKey() roachpb.Key {
 	return MakeStoreKey(localStoreLastUpSuffix, nil)
 }
 
+// StoreRaftLogSeparatedKey returns the store-local key marking that the
+// store's Raft log lives in a separate engine.
+func StoreRaftLogSeparatedKey() roachpb.Key {
+	return MakeStoreKey(localStoreRaftLogSeparatedSuffix, nil)
+}
+
 // StoreHLCUpperBoundKey returns the store-local key for storing an upper bound
 // to the wall time used by HLC.
 func StoreHLCUpperBoundKey() roachpb.Key {
@@ -361,6 +367,32 @@ func MakeRangeKeyPrefix(key roachpb.RKey) roachpb.Key {
 	return buf
 }
 
+// MVCCRangeTombstoneKey returns the key under which the MVCC range tombstone
+// fragment starting at the given key is stored.
+func MVCCRangeTombstoneKey(start roachpb.RKey) roachpb.Key {
+	buf := make(roachpb.Key, 0, len(LocalMVCCRangeTombstonePrefix)+len(start)+3)
+	buf = append(buf, LocalMVCCRangeTombstonePrefix...)
+	buf = encoding.EncodeBytesAscending(buf, start)
+	return buf
+}
+
+// DecodeMVCCRangeTombstoneKey decodes the start key of the MVCC range
+// tombstone fragment stored under the given key.
+func DecodeMVCCRangeTombstoneKey(key roachpb.Key) (roachpb.RKey, error) {
+	if !bytes.HasPrefix(key, LocalMVCCRangeTombstonePrefix) {
+		return nil, errors.Errorf("key %q does not have %q prefix",
+			key, LocalMVCCRangeTombstonePrefix)
+	}
+	b, start, err := encoding.DecodeBytesAscending(key[len(LocalMVCCRangeTombstonePrefix):], nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, errors.Errorf("key %q has unexpected suffix %q", key, b)
+	}
+	return start, nil
+}
+
 // DecodeRangeKey decodes the range key into range start key,
 // suffix and optional detail (may be nil).
 func DecodeRangeKey(key roachpb.Key) (startKey, suffix, detail roachpb.Key, err error) {
@@ -462,6 +494,9 @@ func Addr(k roachpb.Key) (roachpb.RKey, error) {
 		if bytes.HasPrefix(k, LocalRangeIDPrefix) {
 			return nil, errors.Errorf("local range ID key %q is not addressable", k)
 		}
+		if bytes.HasPrefix(k, LocalMVCCRangeTombstonePrefix) {
+			return DecodeMVCCRangeTombstoneKey(k)
+		}
 		if !bytes.HasPrefix(k, LocalRangePrefix) {
 			return nil, errors.Errorf("local key %q malformed; should contain prefix %q",
 				k, LocalRangePrefix)
@@ -77,6 +77,8 @@ var (
 				ppFunc: localRangeIDKeyPrint, PSFunc: localRangeIDKeyParse},
 			{Name: "/Range", prefix: LocalRangePrefix, ppFunc: localRangeKeyPrint,
 				PSFunc: parseUnsupported},
+			{Name: "/MVCCRangeTombstone", prefix: LocalMVCCRangeTombstonePrefix,
+				ppFunc: mvccRangeTombstoneKeyPrint, PSFunc: parseUnsupported},
 		}},
 		{Name: "/Meta1", start: Meta1Prefix, end: Meta1KeyMax, Entries: []DictEntry{
 			{Name: "", prefix: Meta1Prefix, ppFunc: print,
@@ -190,6 +192,7 @@ var constSubKeyDict = []struct {
 	{"/gossipBootstrap", localStoreGossipSuffix},
 	{"/clusterVersion", localStoreClusterVersionSuffix},
 	{"/nodeTombstone", localStoreNodeTombstoneSuffix},
+	{"/raftLogSeparated", localStoreRaftLogSeparatedSuffix},
 	{"/suggestedCompaction", localStoreSuggestedCompactionSuffix},
 }
 
@@ -559,6 +562,14 @@ func print(_ []encoding.Direction, key roachpb.Key) string {
 	return fmt.Sprintf("/%q", []byte(key))
 }
 
+func mvccRangeTombstoneKeyPrint(_ []encoding.Direction, key roachpb.Key) string {
+	_, start, err := encoding.DecodeBytesAscending(key, nil)
+	if err != nil {
+		return fmt.Sprintf("<invalid: %s>", err)
+	}
+	return roachpb.Key(start).String()
+}
+
 func decodeKeyPrint(valDirs []encoding.Direction, key roachpb.Key) string {
 	if key.Equal(SystemConfigSpan.Key) {
 		return "/SystemConfigSpan/Start"
@@ -1774,7 +1774,21 @@ func (ds *DistSender) sendToReplicas(
 	desc := routing.Desc()
 	ba.RangeID = desc.RangeID
 	leaseholder := routing.Leaseholder()
-	replicas, err := NewReplicaSlice(ctx, ds.nodeDescs, desc, leaseholder)
+	// Requests that can be served by a follower are routed to the nearest
+	// replica, even if their sender didn't ask for it.
+	routingPolicy := ba.RoutingPolicy
+	if routingPolicy == roachpb.LEASEHOLDER &&
+		(ds.clusterID != nil) && CanSendToFollower(ds.clusterID.Get(), ds.st, ba) {
+		routingPolicy = roachpb.NEAREST
+	}
+	// Non-voting replicas can only serve requests that can be served by a
+	// follower, so only consider them if the request is routed to the nearest
+	// replica.
+	replicaFilter := OnlyPotentialLeaseholders
+	if routingPolicy == roachpb.NEAREST {
+		replicaFilter = AllExtantReplicas
+	}
+	replicas, err := NewReplicaSlice(ctx, ds.nodeDescs, desc, leaseholder, replicaFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -1787,8 +1801,8 @@ func (ds *DistSender) sendToReplicas(
 
 	// Try the leaseholder first, if the request wants it.
 	{
-		canFollowerRead := (ds.clusterID != nil) && CanSendToFollower(ds.clusterID.Get(), ds.st, ba)
-		sendToLeaseholder := (leaseholder != nil) && !canFollowerRead && ba.RequiresLeaseHolder()
+		sendToLeaseholder := (leaseholder != nil) &&
+			routingPolicy == roachpb.LEASEHOLDER && ba.RequiresLeaseHolder()
 		if sendToLeaseholder {
 			idx := replicas.Find(leaseholder.ReplicaID)
 			if idx != -1 {
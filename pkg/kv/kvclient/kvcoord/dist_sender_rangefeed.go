@@ -228,7 +228,7 @@ func (ds *DistSender) singleRangeFeed(
 	if ds.rpcContext != nil {
 		latencyFn = ds.rpcContext.RemoteClocks.Latency
 	}
-	replicas, err := NewReplicaSlice(ctx, ds.nodeDescs, desc, nil /* leaseholder */)
+	replicas, err := NewReplicaSlice(ctx, ds.nodeDescs, desc, nil /* leaseholder */, OnlyPotentialLeaseholders)
 	if err != nil {
 		return args.Timestamp, err
 	}
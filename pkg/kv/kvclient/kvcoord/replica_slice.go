@@ -39,12 +39,27 @@ func (i ReplicaInfo) addr() string {
 // A ReplicaSlice is a slice of ReplicaInfo.
 type ReplicaSlice []ReplicaInfo
 
+// ReplicaSliceFilter controls which kinds of replicas are to be included in
+// the slice for routing BatchRequests to.
+type ReplicaSliceFilter int
+
+const (
+	// OnlyPotentialLeaseholders prescribes that the ReplicaSlice should include
+	// only replicas that are allowed to be leaseholders (i.e. replicas of type
+	// VOTER_FULL).
+	OnlyPotentialLeaseholders ReplicaSliceFilter = iota
+	// AllExtantReplicas prescribes that the ReplicaSlice should include all
+	// replicas that can serve reads: the voters as well as the non-voters.
+	AllExtantReplicas
+)
+
 // NewReplicaSlice creates a ReplicaSlice from the replicas listed in the range
 // descriptor and using gossip to lookup node descriptors. Replicas on nodes
 // that are not gossiped are omitted from the result.
 //
-// Generally, only voting replicas are returned. However, if a non-nil
-// leaseholder is passed in, it will be included in the result even if the
+// If filter is OnlyPotentialLeaseholders, only voting replicas are returned.
+// If it is AllExtantReplicas, non-voting replicas are returned as well, after
+// the voting ones. In both cases, if a non-nil leaseholder is passed in, it will be included in the result even if the
 // descriptor has it as a learner (we assert that the leaseholder is part of the
 // descriptor). The idea is that the descriptor might be stale and list the
 // leaseholder as a learner erroneously, and lease info is a strong signal in
@@ -59,6 +74,7 @@ func NewReplicaSlice(
 	nodeDescs NodeDescStore,
 	desc *roachpb.RangeDescriptor,
 	leaseholder *roachpb.ReplicaDescriptor,
+	filter ReplicaSliceFilter,
 ) (ReplicaSlice, error) {
 	if leaseholder != nil {
 		if _, ok := desc.GetReplicaDescriptorByID(leaseholder.ReplicaID); !ok {
@@ -67,9 +83,18 @@ func NewReplicaSlice(
 	}
 
 	// Learner replicas won't serve reads/writes, so we'll send only to the
-	// `Voters` replicas. This is just an optimization to save a network hop,
+	// `Voters` replicas, and to the `NonVoters` replicas if the request can be
+	// served by a follower. This is just an optimization to save a network hop,
 	// everything would still work if we had `All` here.
-	voters := desc.Replicas().Voters()
+	var voters []roachpb.ReplicaDescriptor
+	switch filter {
+	case OnlyPotentialLeaseholders:
+		voters = desc.Replicas().Voters()
+	case AllExtantReplicas:
+		voters = desc.Replicas().VotersAndNonVoters()
+	default:
+		log.Fatalf(ctx, "unknown ReplicaSliceFilter %v", filter)
+	}
 	// If we know a leaseholder, though, let's make sure we include it.
 	if leaseholder != nil && len(voters) < len(desc.Replicas().All()) {
 		found := false
@@ -49,6 +49,13 @@ const (
 	// batches except EndTxn(commit=false) will be rejected.
 	txnError
 
+	// txnRetryableError means that a batch encountered a retryable error while
+	// statement retries were enabled, and the restart of the transaction was
+	// deferred. Further batches except EndTxn(commit=false) will be rejected
+	// until the transaction is rolled back to a savepoint, which resumes it at
+	// its current epoch, or restarted. See SetStatementRetriesEnabled.
+	txnRetryableError
+
 	// txnFinalized means that an EndTxn(commit=true) has been executed
 	// successfully, or an EndTxn(commit=false) was sent - regardless of
 	// whether it executed successfully or not. Further batches except
@@ -104,6 +111,16 @@ type TxnCoordSender struct {
 		// clients on Send().
 		storedErr *roachpb.Error
 
+		// statementRetriesEnabled is set if retryable errors should move the
+		// transaction to the txnRetryableError state instead of restarting it.
+		// It can only be set for isolation levels with per-statement read
+		// snapshots.
+		statementRetriesEnabled bool
+		// retryableErr is set when txnState == txnRetryableError. It is returned
+		// to clients on Send(). The transaction it carries is the one which the
+		// deferred restart moves to.
+		retryableErr *roachpb.TransactionRetryWithProtoRefreshError
+
 		// active is set whenever the transaction has sent any requests. Rolling
 		// back to a savepoint taken before the TxnCoordSender became active resets
 		// the field to false.
@@ -577,6 +594,8 @@ func (tc *TxnCoordSender) maybeRejectClientLocked(
 		// All good.
 	case txnError:
 		return tc.mu.storedErr
+	case txnRetryableError:
+		return roachpb.NewError(tc.mu.retryableErr)
 	case txnFinalized:
 		msg := fmt.Sprintf("client already committed or rolled back the transaction. "+
 			"Trying to execute: %s", ba.Summary())
@@ -706,8 +725,28 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 		return retErr
 	}
 
+	// If the client is able to retry the statement which hit the error, defer
+	// the restart. The transaction stays at its current epoch, but its write
+	// timestamp and priority are moved up as they would be by the restart.
+	if tc.mu.statementRetriesEnabled && canRetryStatement(pErr) {
+		log.VEventf(ctx, 2, "deferring restart to allow statement retry")
+		tc.mu.txnState = txnRetryableError
+		tc.mu.retryableErr = retErr
+		tc.mu.txn.WriteTimestamp.Forward(newTxn.WriteTimestamp)
+		tc.mu.txn.UpgradePriority(newTxn.Priority)
+		tc.mu.txn.WriteTooOld = false
+		return retErr
+	}
+
+	tc.restartLocked(ctx, &newTxn)
+	return retErr
+}
+
+// restartLocked moves the transaction to the new epoch of the given
+// transaction, which has been prepared for a retry.
+func (tc *TxnCoordSender) restartLocked(ctx context.Context, newTxn *roachpb.Transaction) {
 	// This is where we get a new epoch.
-	tc.mu.txn.Update(&newTxn)
+	tc.mu.txn.Update(newTxn)
 
 	// Reset state as this is a retryable txn error that is incrementing
 	// the transaction's epoch.
@@ -715,7 +754,17 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 	for _, reqInt := range tc.interceptorStack {
 		reqInt.epochBumpedLocked()
 	}
-	return retErr
+}
+
+// canRetryStatement returns whether the retryable error can be handled by
+// retrying the statement which hit it, as opposed to restarting the
+// transaction. This is not the case for errors caused by the writes of
+// earlier statements having failed.
+func canRetryStatement(pErr *roachpb.Error) bool {
+	if tErr, ok := pErr.GetDetail().(*roachpb.TransactionRetryError); ok {
+		return tErr.Reason != roachpb.RETRY_ASYNC_WRITE_FAILURE
+	}
+	return true
 }
 
 // updateStateLocked updates the transaction state in both the success and error
@@ -866,6 +915,24 @@ func (tc *TxnCoordSender) SetUserPriority(pri roachpb.UserPriority) error {
 	return nil
 }
 
+// SetIsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) SetIsoLevel(isoLevel enginepb.IsolationLevel) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.mu.active && isoLevel != tc.mu.txn.IsoLevel {
+		return errors.New("cannot change the isolation level of a running transaction")
+	}
+	tc.mu.txn.IsoLevel = isoLevel
+	return nil
+}
+
+// IsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) IsoLevel() enginepb.IsolationLevel {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.txn.IsoLevel
+}
+
 // SetDebugName is part of the client.TxnSender interface.
 func (tc *TxnCoordSender) SetDebugName(name string) {
 	tc.mu.Lock()
@@ -955,6 +1022,7 @@ func (tc *TxnCoordSender) ManualRestart(
 	// The txn might have entered the txnError state after the epoch was bumped.
 	// Reset the state for the retry.
 	tc.mu.txnState = txnPending
+	tc.mu.retryableErr = nil
 }
 
 // IsSerializablePushAndRefreshNotPossible is part of the client.TxnSender interface.
@@ -962,6 +1030,11 @@ func (tc *TxnCoordSender) IsSerializablePushAndRefreshNotPossible() bool {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
 
+	// Transactions which tolerate write skew can commit after being pushed
+	// without refreshing their reads.
+	if tc.mu.txn.IsoLevel.ToleratesWriteSkew() {
+		return false
+	}
 	isTxnPushed := tc.mu.txn.WriteTimestamp != tc.mu.txn.ReadTimestamp
 	refreshAttemptNotPossible := tc.interceptorAlloc.txnSpanRefresher.refreshInvalid ||
 		tc.mu.txn.CommitTimestampFixed
@@ -1127,6 +1200,54 @@ func (tc *TxnCoordSender) Step(ctx context.Context) error {
 	return tc.interceptorAlloc.txnSeqNumAllocator.stepLocked(ctx)
 }
 
+// StepReadTimestamp is part of the TxnSender interface.
+func (tc *TxnCoordSender) StepReadTimestamp(ctx context.Context) error {
+	if tc.typ != kv.RootTxn {
+		return errors.AssertionFailedf("cannot step read timestamp in non-root txn")
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	txn := &tc.mu.txn
+	if !txn.IsoLevel.PerStatementReadSnapshot() || txn.CommitTimestampFixed {
+		return nil
+	}
+	if pErr := tc.maybeRejectClientLocked(ctx, nil /* ba */); pErr != nil {
+		return pErr.GoError()
+	}
+
+	// Read at the current time, or at the provisional commit timestamp if it
+	// is ahead of it. The uncertainty interval starts anew at the new read
+	// timestamp, so the observed timestamps collected so far can no longer be
+	// used to shrink it.
+	now := tc.clock.Now()
+	txn.ReadTimestamp.Forward(now)
+	txn.ReadTimestamp.Forward(txn.WriteTimestamp)
+	txn.WriteTimestamp.Forward(txn.ReadTimestamp)
+	txn.MaxTimestamp.Forward(now.Add(tc.clock.MaxOffset().Nanoseconds(), 0))
+	txn.ObservedTimestamps = nil
+	tc.interceptorAlloc.txnSpanRefresher.stepReadTimestampLocked(txn.ReadTimestamp)
+	return nil
+}
+
+// SetStatementRetriesEnabled is part of the TxnSender interface.
+func (tc *TxnCoordSender) SetStatementRetriesEnabled(ctx context.Context, enabled bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.mu.statementRetriesEnabled = enabled && tc.typ == kv.RootTxn &&
+		tc.mu.txn.IsoLevel.PerStatementReadSnapshot()
+	if !tc.mu.statementRetriesEnabled && tc.mu.txnState == txnRetryableError {
+		// The statement is not going to be retried, so perform the restart
+		// which was deferred.
+		newTxn := tc.mu.retryableErr.Transaction
+		tc.mu.txnState = txnPending
+		tc.mu.retryableErr = nil
+		tc.restartLocked(ctx, &newTxn)
+	}
+}
+
 // ConfigureStepping is part of the TxnSender interface.
 func (tc *TxnCoordSender) ConfigureStepping(
 	ctx context.Context, mode kv.SteppingMode,
@@ -1144,3 +1265,17 @@ func (tc *TxnCoordSender) GetSteppingMode(ctx context.Context) (curMode kv.Stepp
 	}
 	return curMode
 }
+
+// GetReadSeqNum is part of the TxnSender interface.
+func (tc *TxnCoordSender) GetReadSeqNum() enginepb.TxnSeq {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.interceptorAlloc.txnSeqNumAllocator.readSeq
+}
+
+// SetReadSeqNum is part of the TxnSender interface.
+func (tc *TxnCoordSender) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.interceptorAlloc.txnSeqNumAllocator.setReadSeqLocked(seq)
+}
@@ -134,7 +134,10 @@ func (tc *TxnCoordSender) RollbackToSavepoint(ctx context.Context, s kv.Savepoin
 	}
 
 	// Restore the transaction's state, in case we're rewiding after an error.
+	// This includes retryable errors whose restart was deferred to let the
+	// client retry the statement which hit them.
 	tc.mu.txnState = txnPending
+	tc.mu.retryableErr = nil
 
 	tc.mu.active = sp.active
 
@@ -2392,3 +2392,91 @@ func TestPutsInStagingTxn(t *testing.T) {
 	// seen a batch with the STAGING status.
 	require.True(t, putInStagingSeen)
 }
+
+// TestTxnCoordSenderStatementRetries verifies that, when statement retries are
+// enabled, a retryable error hit by a READ COMMITTED transaction leaves it at
+// its current epoch until it is either rolled back to a savepoint or statement
+// retries are disabled, which performs the deferred restart.
+func TestTxnCoordSenderStatementRetries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	clock := hlc.NewClock(hlc.UnixNano, time.Nanosecond)
+	ambient := log.AmbientContext{Tracer: tracing.NewTracer()}
+	sender := &mockSender{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	// Writes to key "b" fail with a retryable error, until failB is unset.
+	failB := true
+	sender.match(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		br := ba.CreateReply()
+		br.Txn = ba.Txn.Clone()
+		for i, ru := range ba.Requests {
+			switch req := ru.GetInner().(type) {
+			case *roachpb.PutRequest:
+				if failB && req.Key.Equal(roachpb.Key("b")) {
+					return nil, roachpb.NewErrorWithTxn(
+						roachpb.NewTransactionRetryError(roachpb.RETRY_WRITE_TOO_OLD, "test err"),
+						ba.Txn)
+				}
+			case *roachpb.QueryIntentRequest:
+				br.Responses[i].GetQueryIntent().FoundIntent = true
+			case *roachpb.EndTxnRequest:
+				br.Txn.Status = roachpb.COMMITTED
+			}
+		}
+		return br, nil
+	})
+
+	factory := NewTxnCoordSenderFactory(
+		TxnCoordSenderFactoryConfig{
+			AmbientCtx: ambient,
+			Clock:      clock,
+			Stopper:    stopper,
+			Settings:   cluster.MakeTestingClusterSettings(),
+			TestingKnobs: ClientTestingKnobs{
+				// Disable span refresh, otherwise it kicks and retries batches by
+				// itself.
+				MaxTxnRefreshAttempts: -1,
+			},
+		},
+		sender,
+	)
+	db := kv.NewDB(testutils.MakeAmbientCtx(), factory, clock, stopper)
+
+	testutils.RunTrueAndFalse(t, "rollback", func(t *testing.T, rollback bool) {
+		failB = true
+		txn := kv.NewTxn(ctx, db, 0 /* gatewayNodeID */)
+		require.NoError(t, txn.SetIsoLevel(enginepb.ReadCommitted))
+		require.NoError(t, txn.Put(ctx, "a", "a"))
+
+		txn.SetStatementRetriesEnabled(ctx, true)
+		require.NoError(t, txn.StepReadTimestamp(ctx))
+		sp, err := txn.CreateSavepoint(ctx)
+		require.NoError(t, err)
+		err = txn.Put(ctx, "b", "b")
+		require.True(t, errors.HasType(err, (*roachpb.TransactionRetryWithProtoRefreshError)(nil)), "%v", err)
+		require.Equal(t, enginepb.TxnEpoch(0), txn.Epoch())
+
+		// Further requests are rejected with the same error.
+		err = txn.Put(ctx, "c", "c")
+		require.True(t, errors.HasType(err, (*roachpb.TransactionRetryWithProtoRefreshError)(nil)), "%v", err)
+
+		failB = false
+		if rollback {
+			// Rolling back to the savepoint allows the statement to be retried
+			// at the current epoch.
+			require.NoError(t, txn.RollbackToSavepoint(ctx, sp))
+			require.NoError(t, txn.StepReadTimestamp(ctx))
+			require.NoError(t, txn.Put(ctx, "b", "b"))
+			txn.SetStatementRetriesEnabled(ctx, false)
+			require.Equal(t, enginepb.TxnEpoch(0), txn.Epoch())
+		} else {
+			// Disabling statement retries restarts the transaction.
+			txn.SetStatementRetriesEnabled(ctx, false)
+			require.Equal(t, enginepb.TxnEpoch(1), txn.Epoch())
+		}
+		require.NoError(t, txn.Commit(ctx))
+	})
+}
@@ -154,6 +154,17 @@ func (s *txnSeqNumAllocator) stepLocked(ctx context.Context) error {
 // stepping when it was previously enabled already. This is the
 // behavior needed to provide the documented API semantics of
 // sender.ConfigureStepping() (see client/sender.go).
+// setReadSeqLocked sets the sequence number at which read-only operations are
+// performed in stepping mode. It cannot be ahead of the write seqnum.
+func (s *txnSeqNumAllocator) setReadSeqLocked(seq enginepb.TxnSeq) error {
+	if seq > s.writeSeq {
+		return errors.AssertionFailedf(
+			"cannot read at seqnum %d ahead of the write seqnum %d", seq, s.writeSeq)
+	}
+	s.readSeq = seq
+	return nil
+}
+
 func (s *txnSeqNumAllocator) configureSteppingLocked(
 	newMode kv.SteppingMode,
 ) (prevMode kv.SteppingMode) {
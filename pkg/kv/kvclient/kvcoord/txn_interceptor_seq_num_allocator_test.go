@@ -228,6 +228,55 @@ func TestSequenceNumberAllocationWithStep(t *testing.T) {
 	require.NotNil(t, br)
 }
 
+// TestSequenceNumberAllocationSetReadSeq tests that read-only requests are
+// performed at a read seqnum restored with setReadSeqLocked, until the next
+// step.
+func TestSequenceNumberAllocationSetReadSeq(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	s, mockSender := makeMockTxnSeqNumAllocator()
+
+	txn := makeTxnProto()
+	keyA := roachpb.Key("a")
+
+	s.configureSteppingLocked(true /* enabled */)
+	var ba roachpb.BatchRequest
+	ba.Header = roachpb.Header{Txn: &txn}
+	ba.Add(&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: keyA}})
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	})
+	_, pErr := s.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+	require.NoError(t, s.stepLocked(ctx))
+	savedSeq := s.readSeq
+	require.Equal(t, enginepb.TxnSeq(1), savedSeq)
+
+	_, pErr = s.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+	require.NoError(t, s.stepLocked(ctx))
+	require.Equal(t, enginepb.TxnSeq(2), s.readSeq)
+
+	require.NoError(t, s.setReadSeqLocked(savedSeq))
+	ba.Requests = nil
+	ba.Add(&roachpb.GetRequest{RequestHeader: roachpb.RequestHeader{Key: keyA}})
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		require.Equal(t, savedSeq, ba.Requests[0].GetInner().Header().Sequence)
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	})
+	_, pErr = s.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+
+	// The read seqnum cannot be ahead of the write seqnum.
+	require.Error(t, s.setReadSeqLocked(3))
+	require.Equal(t, savedSeq, s.readSeq)
+}
+
 // TestSequenceNumberAllocationTxnRequests tests sequence number allocation's
 // interaction with transaction state requests (HeartbeatTxn and EndTxn). Only
 // EndTxn requests should be assigned unique sequence numbers.
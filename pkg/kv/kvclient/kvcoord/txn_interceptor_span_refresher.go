@@ -463,7 +463,10 @@ func (sr *txnSpanRefresher) maybeRefreshPreemptively(
 
 	// If true, this batch is guaranteed to fail without a refresh.
 	args, hasET := ba.GetArg(roachpb.EndTxn)
-	refreshInevitable := hasET && args.(*roachpb.EndTxnRequest).Commit
+	// Transactions which tolerate write skew can commit at their pushed write
+	// timestamp, so the EndTxn doesn't need a refresh.
+	refreshInevitable := hasET && args.(*roachpb.EndTxnRequest).Commit &&
+		!ba.Txn.IsoLevel.ToleratesWriteSkew()
 
 	// If neither condition is true, defer the refresh.
 	if !refreshFree && !refreshInevitable {
@@ -677,6 +680,15 @@ func (sr *txnSpanRefresher) epochBumpedLocked() {
 	sr.refreshedTimestamp.Reset()
 }
 
+// stepReadTimestampLocked is called when the transaction establishes a new
+// read snapshot at the given timestamp. The spans read at earlier snapshots
+// no longer need to be refreshed.
+func (sr *txnSpanRefresher) stepReadTimestampLocked(ts hlc.Timestamp) {
+	sr.refreshFootprint.clear()
+	sr.refreshInvalid = false
+	sr.refreshedTimestamp.Forward(ts)
+}
+
 // createSavepointLocked is part of the txnReqInterceptor interface.
 func (sr *txnSpanRefresher) createSavepointLocked(ctx context.Context, s *savepoint) {
 	s.refreshSpans = make([]roachpb.Span, len(sr.refreshFootprint.asSlice()))
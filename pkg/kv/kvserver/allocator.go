@@ -57,6 +57,13 @@ const (
 	removeDeadReplicaPriority               float64 = 1000
 	removeDecommissioningReplicaPriority    float64 = 200
 	removeExtraReplicaPriority              float64 = 100
+
+	// Non-voting replicas aren't part of the quorum, so repairing them is less
+	// urgent than any of the operations on voting replicas above.
+	addMissingNonVoterPriority            float64 = 60
+	removeDeadNonVoterPriority            float64 = 50
+	removeDecommissioningNonVoterPriority float64 = 40
+	removeExtraNonVoterPriority           float64 = 30
 )
 
 // MinLeaseTransferStatsDuration configures the minimum amount of time a
@@ -108,6 +115,10 @@ const (
 	AllocatorConsiderRebalance
 	AllocatorRangeUnavailable
 	AllocatorFinalizeAtomicReplicationChange
+	AllocatorAddNonVoter
+	AllocatorRemoveNonVoter
+	AllocatorRemoveDeadNonVoter
+	AllocatorRemoveDecommissioningNonVoter
 )
 
 var allocatorActionNames = map[AllocatorAction]string{
@@ -122,6 +133,10 @@ var allocatorActionNames = map[AllocatorAction]string{
 	AllocatorConsiderRebalance:               "consider rebalance",
 	AllocatorRangeUnavailable:                "range unavailable",
 	AllocatorFinalizeAtomicReplicationChange: "finalize conf change",
+	AllocatorAddNonVoter:                     "add non-voter",
+	AllocatorRemoveNonVoter:                  "remove non-voter",
+	AllocatorRemoveDeadNonVoter:              "remove dead non-voter",
+	AllocatorRemoveDecommissioningNonVoter:   "remove decommissioning non-voter",
 }
 
 func (a AllocatorAction) String() string {
@@ -354,8 +369,14 @@ func (a *Allocator) ComputeAction(
 		// removeLearnerReplicaPriority as the highest priority.
 		return AllocatorRemoveLearner, removeLearnerReplicaPriority
 	}
-	// computeAction expects to operate only on voters.
-	return a.computeAction(ctx, zone, desc.Replicas().Voters())
+	// computeAction expects to operate only on voters. Non-voting replicas are
+	// only considered once the voting replicas are in order.
+	voterReplicas := desc.Replicas().Voters()
+	action, priority := a.computeAction(ctx, zone, voterReplicas)
+	if action != AllocatorConsiderRebalance {
+		return action, priority
+	}
+	return a.computeNonVoterAction(ctx, zone, len(voterReplicas), desc.Replicas().NonVoters())
 }
 
 func (a *Allocator) computeAction(
@@ -365,7 +386,7 @@ func (a *Allocator) computeAction(
 	have := len(voterReplicas)
 	decommissioningReplicas := a.storePool.decommissioningReplicas(voterReplicas)
 	clusterNodes := a.storePool.ClusterNodeCount()
-	need := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
+	need := GetNeededReplicas(zone.GetNumVoters(), clusterNodes)
 	desiredQuorum := computeQuorum(need)
 	quorum := computeQuorum(have)
 
@@ -453,6 +474,105 @@ func (a *Allocator) computeAction(
 	return AllocatorConsiderRebalance, 0
 }
 
+// GetNeededNonVoters calculates the number of non-voting replicas a range
+// should have given its zone config, the number of voting replicas it has and
+// the number of nodes available for up-replication. Non-voting replicas are
+// never placed on a node that already has a voting replica of the range.
+func GetNeededNonVoters(zone *zonepb.ZoneConfig, numVoters, clusterNodes int) int {
+	need := int(zone.GetNumNonVoters())
+	if clusterNodes-numVoters < need {
+		need = clusterNodes - numVoters
+	}
+	if need < 0 {
+		need = 0
+	}
+	return need
+}
+
+// computeNonVoterAction determines the operation needed to repair the
+// non-voting replicas of a range. It is only consulted once the voting
+// replicas of the range don't need any repair.
+func (a *Allocator) computeNonVoterAction(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	numVoters int,
+	nonVoterReplicas []roachpb.ReplicaDescriptor,
+) (AllocatorAction, float64) {
+	have := len(nonVoterReplicas)
+	need := GetNeededNonVoters(zone, numVoters, a.storePool.ClusterNodeCount())
+
+	if have < need {
+		// The range is missing non-voting replicas. Unlike with voters, there's
+		// no quorum to protect, so dead or decommissioning non-voters are removed
+		// first and replaced afterwards through this branch.
+		priority := addMissingNonVoterPriority + float64(need-have)
+		action := AllocatorAddNonVoter
+		log.VEventf(ctx, 3, "%s - missing non-voter need=%d, have=%d, priority=%.2f",
+			action, need, have, priority)
+		return action, priority
+	}
+
+	if _, deadNonVoters := a.storePool.liveAndDeadReplicas(nonVoterReplicas); len(deadNonVoters) > 0 {
+		priority := removeDeadNonVoterPriority
+		action := AllocatorRemoveDeadNonVoter
+		log.VEventf(ctx, 3, "%s - dead=%d, priority=%.2f", action, len(deadNonVoters), priority)
+		return action, priority
+	}
+
+	if decommissioning := a.storePool.decommissioningReplicas(nonVoterReplicas); len(decommissioning) > 0 {
+		priority := removeDecommissioningNonVoterPriority
+		action := AllocatorRemoveDecommissioningNonVoter
+		log.VEventf(ctx, 3, "%s - num_decommissioning=%d, priority=%.2f",
+			action, len(decommissioning), priority)
+		return action, priority
+	}
+
+	if have > need {
+		priority := removeExtraNonVoterPriority
+		action := AllocatorRemoveNonVoter
+		log.VEventf(ctx, 3, "%s - need=%d, have=%d, priority=%.2f", action, need, have, priority)
+		return action, priority
+	}
+
+	// Nothing needs to be done, but we may want to rebalance.
+	return AllocatorConsiderRebalance, 0
+}
+
+// targetReplicaType indicates whether the allocator is placing a voting or a
+// non-voting replica. Voting replicas must satisfy the zone's voter constraints
+// in addition to its constraints.
+type targetReplicaType int
+
+const (
+	voterTarget targetReplicaType = iota
+	nonVoterTarget
+)
+
+// analyzeConstraints analyzes the constraints of the given zone against the
+// existing replicas of a range. When placing a voting replica, the zone's
+// voter constraints are analyzed against the existing voting replicas as well.
+func (a *Allocator) analyzeConstraints(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	existingReplicas []roachpb.ReplicaDescriptor,
+	targetType targetReplicaType,
+) constraint.AnalyzedConstraints {
+	analyzed := constraint.AnalyzeConstraints(
+		ctx, a.storePool.getStoreDescriptor, existingReplicas, zone)
+	if targetType == voterTarget && len(zone.VoterConstraints) > 0 {
+		var existingVoters []roachpb.ReplicaDescriptor
+		for _, repl := range existingReplicas {
+			if repl.GetType() != roachpb.NON_VOTER {
+				existingVoters = append(existingVoters, repl)
+			}
+		}
+		voterAnalyzed := constraint.AnalyzeVoterConstraints(
+			ctx, a.storePool.getStoreDescriptor, existingVoters, zone)
+		analyzed.VoterConstraints = &voterAnalyzed
+	}
+	return analyzed
+}
+
 type decisionDetails struct {
 	Target   string
 	Existing string `json:",omitempty"`
@@ -467,11 +587,28 @@ type decisionDetails struct {
 // TODO(tbg): AllocateReplacement?
 func (a *Allocator) AllocateTarget(
 	ctx context.Context, zone *zonepb.ZoneConfig, existingReplicas []roachpb.ReplicaDescriptor,
+) (*roachpb.StoreDescriptor, string, error) {
+	return a.allocateTarget(ctx, zone, existingReplicas, voterTarget)
+}
+
+// AllocateNonVoterTarget is like AllocateTarget, but for a non-voting replica,
+// which isn't subject to the zone's voter constraints.
+func (a *Allocator) AllocateNonVoterTarget(
+	ctx context.Context, zone *zonepb.ZoneConfig, existingReplicas []roachpb.ReplicaDescriptor,
+) (*roachpb.StoreDescriptor, string, error) {
+	return a.allocateTarget(ctx, zone, existingReplicas, nonVoterTarget)
+}
+
+func (a *Allocator) allocateTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	existingReplicas []roachpb.ReplicaDescriptor,
+	targetType targetReplicaType,
 ) (*roachpb.StoreDescriptor, string, error) {
 	sl, aliveStoreCount, throttled := a.storePool.getStoreList(storeFilterThrottled)
 
 	target, details := a.allocateTargetFromList(
-		ctx, sl, zone, existingReplicas, a.scorerOptions())
+		ctx, sl, zone, existingReplicas, a.scorerOptions(), targetType)
 
 	if target != nil {
 		return target, details, nil
@@ -498,9 +635,9 @@ func (a *Allocator) allocateTargetFromList(
 	zone *zonepb.ZoneConfig,
 	candidateReplicas []roachpb.ReplicaDescriptor,
 	options scorerOptions,
+	targetType targetReplicaType,
 ) (*roachpb.StoreDescriptor, string) {
-	analyzedConstraints := constraint.AnalyzeConstraints(
-		ctx, a.storePool.getStoreDescriptor, candidateReplicas, zone)
+	analyzedConstraints := a.analyzeConstraints(ctx, zone, candidateReplicas, targetType)
 	candidates := allocateCandidates(
 		sl, analyzedConstraints, candidateReplicas,
 		a.storePool.getLocalitiesByStore(candidateReplicas), options,
@@ -526,6 +663,7 @@ func (a Allocator) simulateRemoveTarget(
 	candidates []roachpb.ReplicaDescriptor,
 	existingReplicas []roachpb.ReplicaDescriptor,
 	rangeUsageInfo RangeUsageInfo,
+	targetType targetReplicaType,
 ) (roachpb.ReplicaDescriptor, string, error) {
 	// Update statistics first
 	// TODO(a-robinson): This could theoretically interfere with decisions made by other goroutines,
@@ -537,7 +675,7 @@ func (a Allocator) simulateRemoveTarget(
 		a.storePool.updateLocalStoreAfterRebalance(targetStore, rangeUsageInfo, roachpb.REMOVE_REPLICA)
 	}()
 	log.VEventf(ctx, 3, "simulating which replica would be removed after adding s%d", targetStore)
-	return a.RemoveTarget(ctx, zone, candidates, existingReplicas)
+	return a.removeTarget(ctx, zone, candidates, existingReplicas, targetType)
 }
 
 // RemoveTarget returns a suitable replica to remove from the provided replica
@@ -550,6 +688,27 @@ func (a Allocator) RemoveTarget(
 	zone *zonepb.ZoneConfig,
 	candidates []roachpb.ReplicaDescriptor,
 	existingReplicas []roachpb.ReplicaDescriptor,
+) (roachpb.ReplicaDescriptor, string, error) {
+	return a.removeTarget(ctx, zone, candidates, existingReplicas, voterTarget)
+}
+
+// RemoveNonVoterTarget is like RemoveTarget, but picks one of the given
+// non-voting replicas, which aren't subject to the zone's voter constraints.
+func (a Allocator) RemoveNonVoterTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	candidates []roachpb.ReplicaDescriptor,
+	existingReplicas []roachpb.ReplicaDescriptor,
+) (roachpb.ReplicaDescriptor, string, error) {
+	return a.removeTarget(ctx, zone, candidates, existingReplicas, nonVoterTarget)
+}
+
+func (a Allocator) removeTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	candidates []roachpb.ReplicaDescriptor,
+	existingReplicas []roachpb.ReplicaDescriptor,
+	targetType targetReplicaType,
 ) (roachpb.ReplicaDescriptor, string, error) {
 	if len(candidates) == 0 {
 		return roachpb.ReplicaDescriptor{}, "", errors.Errorf("must supply at least one candidate replica to allocator.RemoveTarget()")
@@ -562,8 +721,7 @@ func (a Allocator) RemoveTarget(
 	}
 	sl, _, _ := a.storePool.getStoreListFromIDs(existingStoreIDs, storeFilterNone)
 
-	analyzedConstraints := constraint.AnalyzeConstraints(
-		ctx, a.storePool.getStoreDescriptor, existingReplicas, zone)
+	analyzedConstraints := a.analyzeConstraints(ctx, zone, existingReplicas, targetType)
 	options := a.scorerOptions()
 	rankedCandidates := removeCandidates(
 		sl,
@@ -622,6 +780,19 @@ func (a Allocator) RebalanceTarget(
 	existingReplicas []roachpb.ReplicaDescriptor,
 	rangeUsageInfo RangeUsageInfo,
 	filter storeFilter,
+) (add roachpb.ReplicationTarget, remove roachpb.ReplicationTarget, details string, ok bool) {
+	return a.rebalanceTarget(
+		ctx, zone, raftStatus, existingReplicas, rangeUsageInfo, filter, voterTarget)
+}
+
+func (a Allocator) rebalanceTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	raftStatus *raft.Status,
+	existingReplicas []roachpb.ReplicaDescriptor,
+	rangeUsageInfo RangeUsageInfo,
+	filter storeFilter,
+	targetType targetReplicaType,
 ) (add roachpb.ReplicationTarget, remove roachpb.ReplicationTarget, details string, ok bool) {
 	sl, _, _ := a.storePool.getStoreList(filter)
 
@@ -655,8 +826,7 @@ func (a Allocator) RebalanceTarget(
 		}
 	}
 
-	analyzedConstraints := constraint.AnalyzeConstraints(
-		ctx, a.storePool.getStoreDescriptor, existingReplicas, zone)
+	analyzedConstraints := a.analyzeConstraints(ctx, zone, existingReplicas, targetType)
 	options := a.scorerOptions()
 	results := rebalanceCandidates(
 		ctx,
@@ -718,6 +888,7 @@ func (a Allocator) RebalanceTarget(
 			replicaCandidates,
 			existingPlusOneNew,
 			rangeUsageInfo,
+			targetType,
 		)
 		if err != nil {
 			log.Warningf(ctx, "simulating RemoveTarget failed: %+v", err)
@@ -755,6 +926,32 @@ func (a Allocator) RebalanceTarget(
 	return addTarget, removeTarget, string(detailsBytes), true
 }
 
+// RebalanceNonVoterTarget is like RebalanceTarget, but for the non-voting
+// replicas of a range. The non-voting replicas aren't part of the raft group's
+// quorum, so no raft status is consulted when simulating the removal, and a
+// target that already holds a voting replica of the range is never returned.
+//
+// NB: the diversity of the candidates is only scored against the other
+// non-voting replicas; the voting replicas are not taken into account.
+func (a Allocator) RebalanceNonVoterTarget(
+	ctx context.Context,
+	zone *zonepb.ZoneConfig,
+	voterReplicas, nonVoterReplicas []roachpb.ReplicaDescriptor,
+	rangeUsageInfo RangeUsageInfo,
+	filter storeFilter,
+) (add roachpb.ReplicationTarget, remove roachpb.ReplicationTarget, details string, ok bool) {
+	if len(nonVoterReplicas) == 0 {
+		return add, remove, "", false
+	}
+	add, remove, details, ok = a.rebalanceTarget(
+		ctx, zone, nil /* raftStatus */, nonVoterReplicas, rangeUsageInfo, filter, nonVoterTarget)
+	if ok && nodeHasReplica(add.NodeID, voterReplicas) {
+		log.VEventf(ctx, 2, "not rebalancing non-voter to s%d because it holds a voter", add.StoreID)
+		return roachpb.ReplicationTarget{}, roachpb.ReplicationTarget{}, "", false
+	}
+	return add, remove, details, ok
+}
+
 func (a *Allocator) scorerOptions() scorerOptions {
 	return scorerOptions{
 		deterministic:           a.storePool.deterministic,
@@ -90,7 +90,8 @@ var rangeRebalanceThreshold = func() *settings.FloatSetting {
 type scorerOptions struct {
 	deterministic           bool
 	rangeRebalanceThreshold float64
-	qpsRebalanceThreshold   float64 // only considered if non-zero
+	loadRebalanceThreshold  float64 // only considered if non-zero
+	loadDimension           LBRebalancingDimension
 }
 
 type balanceDimensions struct {
@@ -428,12 +429,14 @@ func allocateCandidates(
 		diversityScore := diversityAllocateScore(s, existingStoreLocalities)
 		balanceScore := balanceScore(sl, s.Capacity, options)
 		var convergesScore int
-		if options.qpsRebalanceThreshold > 0 {
-			if s.Capacity.QueriesPerSecond < underfullThreshold(sl.candidateQueriesPerSecond.mean, options.qpsRebalanceThreshold) {
+		if options.loadRebalanceThreshold > 0 {
+			load := options.loadDimension.storeLoad(s.Capacity)
+			meanLoad := options.loadDimension.candidateStoreLoad(sl).mean
+			if load < underfullThreshold(meanLoad, options.loadRebalanceThreshold) {
 				convergesScore = 1
-			} else if s.Capacity.QueriesPerSecond < sl.candidateQueriesPerSecond.mean {
+			} else if load < meanLoad {
 				convergesScore = 0
-			} else if s.Capacity.QueriesPerSecond < overfullThreshold(sl.candidateQueriesPerSecond.mean, options.qpsRebalanceThreshold) {
+			} else if load < overfullThreshold(meanLoad, options.loadRebalanceThreshold) {
 				convergesScore = -1
 			} else {
 				convergesScore = -2
@@ -903,6 +906,19 @@ func sameLocalityAndAttrs(s1, s2 roachpb.StoreDescriptor) bool {
 // config package.
 func allocateConstraintsCheck(
 	store roachpb.StoreDescriptor, analyzed constraint.AnalyzedConstraints,
+) (valid bool, necessary bool) {
+	valid, necessary = allocateConjunctionsCheck(store, analyzed)
+	if analyzed.VoterConstraints == nil {
+		return valid, necessary
+	}
+	return combineConstraintsChecks(
+		valid, necessary, allocateConjunctionsCheck, store, *analyzed.VoterConstraints)
+}
+
+// allocateConjunctionsCheck is allocateConstraintsCheck for a single set of
+// analyzed constraints.
+func allocateConjunctionsCheck(
+	store roachpb.StoreDescriptor, analyzed constraint.AnalyzedConstraints,
 ) (valid bool, necessary bool) {
 	// All stores are valid when there are no constraints.
 	if len(analyzed.Constraints) == 0 {
@@ -935,6 +951,19 @@ func allocateConstraintsCheck(
 // be used on an existing replica of the range, not a potential addition.
 func removeConstraintsCheck(
 	store roachpb.StoreDescriptor, analyzed constraint.AnalyzedConstraints,
+) (valid bool, necessary bool) {
+	valid, necessary = removeConjunctionsCheck(store, analyzed)
+	if analyzed.VoterConstraints == nil {
+		return valid, necessary
+	}
+	return combineConstraintsChecks(
+		valid, necessary, removeConjunctionsCheck, store, *analyzed.VoterConstraints)
+}
+
+// removeConjunctionsCheck is removeConstraintsCheck for a single set of
+// analyzed constraints.
+func removeConjunctionsCheck(
+	store roachpb.StoreDescriptor, analyzed constraint.AnalyzedConstraints,
 ) (valid bool, necessary bool) {
 	// All stores are valid when there are no constraints.
 	if len(analyzed.Constraints) == 0 {
@@ -971,6 +1000,44 @@ func rebalanceFromConstraintsCheck(
 	store roachpb.StoreDescriptor,
 	fromStoreID roachpb.StoreID,
 	analyzed constraint.AnalyzedConstraints,
+) (valid bool, necessary bool) {
+	valid, necessary = rebalanceFromConjunctionsCheck(store, fromStoreID, analyzed)
+	if analyzed.VoterConstraints == nil {
+		return valid, necessary
+	}
+	return combineConstraintsChecks(valid, necessary,
+		func(store roachpb.StoreDescriptor, analyzed constraint.AnalyzedConstraints) (bool, bool) {
+			return rebalanceFromConjunctionsCheck(store, fromStoreID, analyzed)
+		},
+		store, *analyzed.VoterConstraints)
+}
+
+// combineConstraintsChecks combines the result of checking a store against the
+// constraints of a zone with the result of checking it against the zone's
+// voter constraints. A voting replica must satisfy both, and is necessary if
+// either of them requires it.
+func combineConstraintsChecks(
+	valid, necessary bool,
+	check func(roachpb.StoreDescriptor, constraint.AnalyzedConstraints) (bool, bool),
+	store roachpb.StoreDescriptor,
+	voterAnalyzed constraint.AnalyzedConstraints,
+) (bool, bool) {
+	if !valid {
+		return false, false
+	}
+	voterValid, voterNecessary := check(store, voterAnalyzed)
+	if !voterValid {
+		return false, false
+	}
+	return true, necessary || voterNecessary
+}
+
+// rebalanceFromConjunctionsCheck is rebalanceFromConstraintsCheck for a
+// single set of analyzed constraints.
+func rebalanceFromConjunctionsCheck(
+	store roachpb.StoreDescriptor,
+	fromStoreID roachpb.StoreID,
+	analyzed constraint.AnalyzedConstraints,
 ) (valid bool, necessary bool) {
 	// All stores are valid when there are no constraints.
 	if len(analyzed.Constraints) == 0 {
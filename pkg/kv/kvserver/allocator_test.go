@@ -508,6 +508,68 @@ func TestAllocatorExistingReplica(t *testing.T) {
 	}
 }
 
+func TestAllocatorVoterConstraints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	stopper, g, _, a, _ := createTestAllocator(10, false /* deterministic */)
+	ctx := context.Background()
+	defer stopper.Stop(ctx)
+	gossiputil.NewStoreGossiper(g).GossipStores(multiDiversityDCStores, t)
+
+	// One replica must be in datacenter d, but none of the voters can be.
+	zone := zonepb.ZoneConfig{
+		NumReplicas: proto.Int32(5),
+		NumVoters:   proto.Int32(3),
+		Constraints: []zonepb.ConstraintsConjunction{
+			{
+				NumReplicas: 1,
+				Constraints: []zonepb.Constraint{
+					{Key: "datacenter", Value: "d", Type: zonepb.Constraint_REQUIRED},
+				},
+			},
+		},
+		VoterConstraints: []zonepb.ConstraintsConjunction{
+			{
+				Constraints: []zonepb.Constraint{
+					{Key: "datacenter", Value: "d", Type: zonepb.Constraint_PROHIBITED},
+				},
+			},
+		},
+	}
+	inDatacenterD := func(storeID roachpb.StoreID) bool {
+		return storeID == 7 || storeID == 8
+	}
+
+	for i := 0; i < 10; i++ {
+		result, _, err := a.AllocateTarget(ctx, &zone, replicas(1, 3))
+		if err != nil {
+			t.Fatalf("unable to perform allocation: %+v", err)
+		}
+		if inDatacenterD(result.StoreID) {
+			t.Errorf("expected voter not to be allocated in datacenter d: %+v", result)
+		}
+
+		result, _, err = a.AllocateNonVoterTarget(ctx, &zone, replicas(1, 3, 5))
+		if err != nil {
+			t.Fatalf("unable to perform allocation: %+v", err)
+		}
+		if !inDatacenterD(result.StoreID) {
+			t.Errorf("expected non-voter to be allocated in datacenter d: %+v", result)
+		}
+	}
+
+	// A voter in datacenter d is removed before any other voter.
+	existing := replicas(1, 3, 7)
+	removed, _, err := a.RemoveTarget(ctx, &zone, existing, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed.StoreID != 7 {
+		t.Errorf("expected the voter on s7 to be removed, got %+v", removed)
+	}
+}
+
 func TestAllocatorMultipleStoresPerNode(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -4583,6 +4645,62 @@ func TestAllocatorComputeAction(t *testing.T) {
 	}
 }
 
+func TestAllocatorComputeActionNonVoter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	zone := zonepb.ZoneConfig{
+		NumReplicas: proto.Int32(5),
+		NumVoters:   proto.Int32(3),
+	}
+	makeDesc := func(voters, nonVoters []roachpb.StoreID) roachpb.RangeDescriptor {
+		var desc roachpb.RangeDescriptor
+		for _, storeID := range voters {
+			desc.AddReplica(roachpb.NodeID(storeID), storeID, roachpb.VOTER_FULL)
+		}
+		for _, storeID := range nonVoters {
+			desc.AddReplica(roachpb.NodeID(storeID), storeID, roachpb.NON_VOTER)
+		}
+		return desc
+	}
+
+	testCases := []struct {
+		voters, nonVoters []roachpb.StoreID
+		expectedAction    AllocatorAction
+	}{
+		// Voters are repaired before non-voters are considered.
+		{[]roachpb.StoreID{1, 2}, nil, AllocatorAdd},
+		{[]roachpb.StoreID{1, 2, 3, 4}, nil, AllocatorRemove},
+		{[]roachpb.StoreID{1, 2, 3}, []roachpb.StoreID{4}, AllocatorAddNonVoter},
+		{[]roachpb.StoreID{1, 2, 3}, []roachpb.StoreID{4, 6}, AllocatorRemoveDeadNonVoter},
+		{[]roachpb.StoreID{1, 2, 3}, []roachpb.StoreID{4, 7}, AllocatorRemoveDecommissioningNonVoter},
+		{[]roachpb.StoreID{1, 2, 3}, []roachpb.StoreID{4, 5, 8}, AllocatorRemoveNonVoter},
+		{[]roachpb.StoreID{1, 2, 3}, []roachpb.StoreID{4, 5}, AllocatorConsiderRebalance},
+	}
+
+	stopper, _, sp, a, _ := createTestAllocator(10, false /* deterministic */)
+	ctx := context.Background()
+	defer stopper.Stop(ctx)
+
+	// Store six is dead and store seven is decommissioning.
+	mockStorePool(sp,
+		[]roachpb.StoreID{1, 2, 3, 4, 5, 8},
+		nil,
+		[]roachpb.StoreID{6},
+		[]roachpb.StoreID{7},
+		nil,
+	)
+
+	for i, tcase := range testCases {
+		desc := makeDesc(tcase.voters, tcase.nonVoters)
+		action, _ := a.ComputeAction(ctx, &zone, &desc)
+		if tcase.expectedAction != action {
+			t.Errorf("Test case %d expected action %q, got action %q",
+				i, allocatorActionNames[tcase.expectedAction], allocatorActionNames[action])
+		}
+	}
+}
+
 func TestAllocatorComputeActionRemoveDead(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
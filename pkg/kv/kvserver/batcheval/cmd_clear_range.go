@@ -141,13 +141,15 @@ func computeStatsDelta(
 		// explicitly ignored (i.e. SysCount, SysBytes).
 		delta = cArgs.EvalCtx.GetMVCCStats()
 		delta.SysCount, delta.SysBytes, delta.AbortSpanBytes = 0, 0, 0 // no change to system stats
+		delta.RangeTombstoneCount = 0
 	}
 
 	// If we can't use the fast stats path, or race test is enabled,
 	// compute stats across the key span to be cleared.
 	if !fast || util.RaceEnabled {
 		iter := readWriter.NewIterator(storage.IterOptions{UpperBound: to})
-		computed, err := iter.ComputeStats(from, to, delta.LastUpdateNanos)
+		computed, err := storage.ComputeStatsWithRangeTombstones(
+			readWriter, iter, from, to, delta.LastUpdateNanos)
 		iter.Close()
 		if err != nil {
 			return enginepb.MVCCStats{}, err
@@ -13,11 +13,14 @@ package batcheval
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -36,6 +39,13 @@ func declareKeysDeleteRange(
 	} else {
 		DefaultDeclareIsolatedKeys(desc, header, req, latchSpans, lockSpans)
 	}
+	if args.UseRangeTombstone {
+		// The MVCC range tombstone fragments of the range may be rewritten.
+		latchSpans.AddNonMVCC(spanset.SpanReadWrite, roachpb.Span{
+			Key:    keys.MVCCRangeTombstoneKey(desc.StartKey),
+			EndKey: keys.MVCCRangeTombstoneKey(desc.EndKey),
+		})
+	}
 }
 
 // DeleteRange deletes the range of key/value pairs specified by
@@ -47,6 +57,22 @@ func DeleteRange(
 	h := cArgs.Header
 	reply := resp.(*roachpb.DeleteRangeResponse)
 
+	if args.UseRangeTombstone {
+		if !cArgs.EvalCtx.ClusterSettings().Version.IsActive(ctx, clusterversion.VersionMVCCRangeTombstones) {
+			return result.Result{}, errors.New("MVCC range tombstones require all nodes to be upgraded")
+		}
+		if h.Txn != nil {
+			return result.Result{}, errors.New("MVCC range tombstones cannot be written transactionally")
+		}
+		if args.Inline || args.ReturnKeys {
+			return result.Result{}, errors.New(
+				"MVCC range tombstones cannot be used to delete inline values or return keys")
+		}
+		err := storage.MVCCDeleteRangeUsingTombstone(
+			ctx, readWriter, cArgs.Stats, args.Key, args.EndKey, h.Timestamp)
+		return result.Result{}, err
+	}
+
 	var timestamp hlc.Timestamp
 	if !args.Inline {
 		timestamp = h.Timestamp
@@ -121,6 +121,10 @@ func declareKeysEndTxn(
 					Key:    keys.MakeRangeKeyPrefix(st.LeftDesc.StartKey),
 					EndKey: keys.MakeRangeKeyPrefix(st.RightDesc.EndKey).PrefixEnd(),
 				})
+				latchSpans.AddNonMVCC(spanset.SpanReadWrite, roachpb.Span{
+					Key:    keys.MVCCRangeTombstoneKey(st.LeftDesc.StartKey),
+					EndKey: keys.MVCCRangeTombstoneKey(st.RightDesc.EndKey),
+				})
 
 				leftRangeIDPrefix := keys.MakeRangeIDReplicatedPrefix(header.RangeID)
 				latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{
@@ -400,8 +404,9 @@ func IsEndTxnTriggeringRetryError(
 		isTxnPushed := txn.WriteTimestamp != readTimestamp
 
 		// Return a transaction retry error if the commit timestamp isn't equal to
-		// the txn timestamp.
-		if isTxnPushed {
+		// the txn timestamp, unless the transaction's isolation level permits it
+		// to commit above the timestamp at which it read.
+		if isTxnPushed && !txn.IsoLevel.ToleratesWriteSkew() {
 			retry, reason = true, roachpb.RETRY_SERIALIZABLE
 		}
 	}
@@ -824,6 +829,14 @@ func splitTrigger(
 			split.RightDesc.StartKey, split.RightDesc.EndKey, desc)
 	}
 
+	// Split any MVCC range tombstone fragment straddling the split key, since
+	// fragments must not extend beyond the range containing them.
+	if err := storage.MVCCSplitRangeTombstones(
+		ctx, batch, &bothDeltaMS, split.RightDesc.StartKey.AsRawKey(),
+	); err != nil {
+		return enginepb.MVCCStats{}, result.Result{}, errors.Wrap(err, "unable to split range tombstones")
+	}
+
 	// Compute the absolute stats for the (post-split) LHS. No more
 	// modifications to it are allowed after this line.
 
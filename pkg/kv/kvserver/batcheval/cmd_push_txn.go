@@ -234,6 +234,11 @@ func PushTxn(
 	case CanPushWithPriority(&args.PusherTxn, &reply.PusheeTxn):
 		reason = "pusher has priority"
 		pusherWins = true
+	case pushType == roachpb.PUSH_TIMESTAMP && reply.PusheeTxn.IsoLevel.ToleratesWriteSkew():
+		// Pushing the timestamp of a transaction which tolerates write skew
+		// does not force it to restart, so there is no reason to wait.
+		reason = "pushee tolerates write skew"
+		pusherWins = true
 	case args.Force:
 		reason = "forced push"
 		pusherWins = true
@@ -0,0 +1,94 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+func declareKeysQueryResolvedTimestamp(
+	_ *roachpb.RangeDescriptor,
+	_ roachpb.Header,
+	req roachpb.Request,
+	latchSpans, _ *spanset.SpanSet,
+) {
+	// The request scans the span for intents, but doesn't care about the
+	// values, so it doesn't need to be isolated from concurrent writes.
+	latchSpans.AddNonMVCC(spanset.SpanReadOnly, req.Header().Span())
+}
+
+func init() {
+	RegisterReadOnlyCommand(roachpb.QueryResolvedTimestamp, declareKeysQueryResolvedTimestamp, QueryResolvedTimestamp)
+}
+
+// QueryResolvedTimestamp returns the timestamp below which the evaluating
+// replica can serve consistent reads over the request's span without
+// redirecting them to the leaseholder. That is the replica's closed timestamp,
+// unless an intent in the span is older: a read at or above the timestamp of
+// an intent would have to wait for the intent's transaction to finish, which
+// the replica can't determine without consulting the leaseholder.
+func QueryResolvedTimestamp(
+	ctx context.Context, reader storage.Reader, cArgs CommandArgs, resp roachpb.Response,
+) (result.Result, error) {
+	reply := resp.(*roachpb.QueryResolvedTimestampResponse)
+	reply.ResolvedTS = cArgs.EvalCtx.GetClosedTimestamp(ctx)
+
+	minIntentTS, err := computeMinIntentTimestamp(reader, cArgs.Args.Header().Span())
+	if err != nil {
+		return result.Result{}, err
+	}
+	if !minIntentTS.IsEmpty() {
+		reply.ResolvedTS.Backward(minIntentTS.Prev())
+	}
+	return result.Result{}, nil
+}
+
+// computeMinIntentTimestamp returns the timestamp of the oldest intent in the
+// span, or an empty timestamp if the span doesn't contain any intents.
+func computeMinIntentTimestamp(reader storage.Reader, span roachpb.Span) (hlc.Timestamp, error) {
+	iter := reader.NewIterator(storage.IterOptions{UpperBound: span.EndKey})
+	defer iter.Close()
+
+	var meta enginepb.MVCCMetadata
+	var minTS hlc.Timestamp
+	for iter.SeekGE(storage.MakeMVCCMetadataKey(span.Key)); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return hlc.Timestamp{}, err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if key.IsValue() {
+			// The key has no metadata, so it has no intent.
+			continue
+		}
+		if err := protoutil.Unmarshal(iter.UnsafeValue(), &meta); err != nil {
+			return hlc.Timestamp{}, errors.Wrapf(err, "unmarshaling mvcc meta: %v", key)
+		}
+		if meta.Txn == nil {
+			// An inline value.
+			continue
+		}
+		if ts := hlc.Timestamp(meta.Timestamp); minTS.IsEmpty() || ts.Less(minTS) {
+			minTS = ts
+		}
+	}
+	return minTS, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryResolvedTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	db := storage.NewDefaultInMem()
+	defer db.Close()
+
+	makeTS := func(ts int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: ts}
+	}
+	writeValue := func(k string, ts int64) {
+		require.NoError(t, storage.MVCCPut(
+			ctx, db, nil, roachpb.Key(k), makeTS(ts), roachpb.MakeValueFromString("a"), nil))
+	}
+	writeIntent := func(k string, ts int64) {
+		txn := roachpb.MakeTransaction("test", roachpb.Key(k), 0, makeTS(ts), 0)
+		require.NoError(t, storage.MVCCPut(
+			ctx, db, nil, roachpb.Key(k), makeTS(ts), roachpb.MakeValueFromString("a"), &txn))
+	}
+	writeInline := func(k string) {
+		require.NoError(t, storage.MVCCPut(
+			ctx, db, nil, roachpb.Key(k), hlc.Timestamp{}, roachpb.MakeValueFromString("a"), nil))
+	}
+
+	// Setup:
+	//
+	//  a: value @ 5
+	//  b: inline value
+	//  c: value @ 6, intent @ 12
+	//  d: intent @ 8
+	//  e: value @ 2
+	//  f: intent @ 20
+	writeValue("a", 5)
+	writeInline("b")
+	writeValue("c", 6)
+	writeIntent("c", 12)
+	writeIntent("d", 8)
+	writeValue("e", 2)
+	writeIntent("f", 20)
+
+	for _, tc := range []struct {
+		name     string
+		span     [2]string
+		closedTS hlc.Timestamp
+		expTS    hlc.Timestamp
+	}{
+		{
+			name:     "no intents",
+			span:     [2]string{"a", "c"},
+			closedTS: makeTS(10),
+			expTS:    makeTS(10),
+		},
+		{
+			name:     "intent above closed timestamp",
+			span:     [2]string{"a", "d"},
+			closedTS: makeTS(10),
+			expTS:    makeTS(10),
+		},
+		{
+			name:     "intent below closed timestamp",
+			span:     [2]string{"a", "e"},
+			closedTS: makeTS(10),
+			expTS:    makeTS(8).Prev(),
+		},
+		{
+			name:     "oldest of several intents",
+			span:     [2]string{"c", "g"},
+			closedTS: makeTS(30),
+			expTS:    makeTS(8).Prev(),
+		},
+		{
+			name:     "intent outside of span",
+			span:     [2]string{"e", "f"},
+			closedTS: makeTS(30),
+			expTS:    makeTS(30),
+		},
+		{
+			name:     "empty closed timestamp",
+			span:     [2]string{"a", "g"},
+			closedTS: hlc.Timestamp{},
+			expTS:    hlc.Timestamp{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			evalCtx := &MockEvalCtx{ClosedTimestamp: tc.closedTS}
+			cArgs := CommandArgs{
+				EvalCtx: evalCtx.EvalContext(),
+				Args: &roachpb.QueryResolvedTimestampRequest{
+					RequestHeader: roachpb.RequestHeader{
+						Key:    roachpb.Key(tc.span[0]),
+						EndKey: roachpb.Key(tc.span[1]),
+					},
+				},
+			}
+			var resp roachpb.QueryResolvedTimestampResponse
+			_, err := QueryResolvedTimestamp(ctx, db, cArgs, &resp)
+			require.NoError(t, err)
+			require.Equal(t, tc.expTS, resp.ResolvedTS)
+		})
+	}
+}
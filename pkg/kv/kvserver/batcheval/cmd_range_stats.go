@@ -42,7 +42,7 @@ func RangeStats(
 ) (result.Result, error) {
 	reply := resp.(*roachpb.RangeStatsResponse)
 	reply.MVCCStats = cArgs.EvalCtx.GetMVCCStats()
-	reply.QueriesPerSecond = cArgs.EvalCtx.GetSplitQPS()
+	reply.LoadPerSecond = cArgs.EvalCtx.GetSplitLoad(ctx)
 	desc, lease := cArgs.EvalCtx.GetDescAndLease(ctx)
 	reply.RangeInfo = &roachpb.RangeInfo{Desc: desc, Lease: lease}
 	return result.Result{}, nil
@@ -113,7 +113,11 @@ func TruncateLog(
 	// bugs that let it diverge. It might be easier to compute the stats
 	// from scratch, stopping when 4mb (defaultRaftLogTruncationThreshold)
 	// is reached as at that point we'll truncate aggressively anyway.
-	iter := readWriter.NewIterator(storage.IterOptions{UpperBound: end})
+	var raftReader storage.Reader = readWriter
+	if raftEng := cArgs.EvalCtx.RaftEngine(); raftEng != nil {
+		raftReader = raftEng
+	}
+	iter := raftReader.NewIterator(storage.IterOptions{UpperBound: end})
 	defer iter.Close()
 	// We can pass zero as nowNanos because we're only interested in SysBytes.
 	ms, err := iter.ComputeStats(start, end, 0 /* nowNanos */)
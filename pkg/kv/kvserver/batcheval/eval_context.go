@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/limit"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"golang.org/x/time/rate"
 )
@@ -41,6 +42,9 @@ type Limiters struct {
 	// is a temporary state at the beginning of a rangefeed which is expensive
 	// because it uses an engine iterator.
 	ConcurrentRangefeedIters limit.ConcurrentRequestLimiter
+	// RangefeedCatchupScanRate paces the rangefeed catch-up scans across the
+	// store by the number of bytes they emit.
+	RangefeedCatchupScanRate *quotapool.RateLimiter
 }
 
 // EvalContext is the interface through which command evaluation accesses the
@@ -51,6 +55,9 @@ type EvalContext interface {
 	EvalKnobs() kvserverbase.BatchEvalTestingKnobs
 
 	Engine() storage.Engine
+	// RaftEngine returns the engine holding the range's Raft log if the store
+	// keeps it apart from Engine(), and nil otherwise.
+	RaftEngine() storage.Engine
 	Clock() *hlc.Clock
 	DB() *kv.DB
 	AbortSpan() *abortspan.AbortSpan
@@ -85,11 +92,17 @@ type EvalContext interface {
 	// results due to concurrent writes.
 	GetMVCCStats() enginepb.MVCCStats
 
-	// GetSplitQPS returns the queries/s request rate for this range.
+	// GetSplitLoad returns the load per second on this range, along the
+	// dimension that load based splitting considers.
 	//
 	// NOTE: This should not be used when the load based splitting cluster
 	// setting is disabled.
-	GetSplitQPS() float64
+	GetSplitLoad(ctx context.Context) float64
+
+	// GetClosedTimestamp returns the timestamp below which the replica can
+	// serve consistent reads without holding the lease, or an empty timestamp
+	// if it can't serve any such reads.
+	GetClosedTimestamp(ctx context.Context) hlc.Timestamp
 
 	GetGCThreshold() hlc.Timestamp
 	GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error)
@@ -109,7 +122,8 @@ type MockEvalCtx struct {
 	StoreID          roachpb.StoreID
 	Clock            *hlc.Clock
 	Stats            enginepb.MVCCStats
-	QPS              float64
+	SplitLoad        float64
+	ClosedTimestamp  hlc.Timestamp
 	AbortSpan        *abortspan.AbortSpan
 	GCThreshold      hlc.Timestamp
 	Term, FirstIndex uint64
@@ -141,6 +155,9 @@ func (m *mockEvalCtxImpl) EvalKnobs() kvserverbase.BatchEvalTestingKnobs {
 func (m *mockEvalCtxImpl) Engine() storage.Engine {
 	panic("unimplemented")
 }
+func (m *mockEvalCtxImpl) RaftEngine() storage.Engine {
+	return nil
+}
 func (m *mockEvalCtxImpl) Clock() *hlc.Clock {
 	return m.MockEvalCtx.Clock
 }
@@ -192,8 +209,11 @@ func (m *mockEvalCtxImpl) ContainsKey(key roachpb.Key) bool {
 func (m *mockEvalCtxImpl) GetMVCCStats() enginepb.MVCCStats {
 	return m.Stats
 }
-func (m *mockEvalCtxImpl) GetSplitQPS() float64 {
-	return m.QPS
+func (m *mockEvalCtxImpl) GetSplitLoad(context.Context) float64 {
+	return m.SplitLoad
+}
+func (m *mockEvalCtxImpl) GetClosedTimestamp(context.Context) hlc.Timestamp {
+	return m.ClosedTimestamp
 }
 func (m *mockEvalCtxImpl) CanCreateTxnRecord(
 	uuid.UUID, []byte, hlc.Timestamp,
@@ -3024,39 +3024,40 @@ func TestStoreRangeMergeRaftSnapshot(t *testing.T) {
 		if snapType != kvserver.SnapshotRequest_RAFT || inSnap.State.Desc.RangeID != roachpb.RangeID(2) {
 			return nil
 		}
-		// The seven SSTs we are expecting to ingest are in the following order:
+		// The eight SSTs we are expecting to ingest are in the following order:
 		// 1. Replicated range-id local keys of the range in the snapshot.
 		// 2. Range-local keys of the range in the snapshot.
-		// 3. User keys of the range in the snapshot.
-		// 4. Unreplicated range-id local keys of the range in the snapshot.
-		// 5. SST to clear range-id local keys of the subsumed replica with
-		//    RangeID 3.
+		// 3. MVCC range tombstone keys of the range in the snapshot.
+		// 4. User keys of the range in the snapshot.
+		// 5. Unreplicated range-id local keys of the range in the snapshot.
 		// 6. SST to clear range-id local keys of the subsumed replica with
+		//    RangeID 3.
+		// 7. SST to clear range-id local keys of the subsumed replica with
 		//    RangeID 4.
-		// 7. SST to clear the user keys of the subsumed replicas.
+		// 8. SST to clear the user keys of the subsumed replicas.
 		//
-		// NOTE: There are no range-local keys in [d, /Max) in the store we're
-		// sending a snapshot to, so we aren't expecting an SST to clear those
-		// keys.
-		if len(sstNames) != 7 {
-			return errors.Errorf("expected to ingest 7 SSTs, got %d SSTs", len(sstNames))
+		// NOTE: There are no range-local keys or MVCC range tombstone keys in
+		// [d, /Max) in the store we're sending a snapshot to, so we aren't
+		// expecting SSTs to clear those keys.
+		if len(sstNames) != 8 {
+			return errors.Errorf("expected to ingest 8 SSTs, got %d SSTs", len(sstNames))
 		}
 
-		// Only try to predict SSTs 3 and 5-7. SSTs 1, 2 and 4 are excluded in
+		// Only try to predict SSTs 3-4 and 6-8. SSTs 1, 2 and 5 are excluded in
 		// the test since the state of the Raft log can be non-deterministic
 		// with extra entries being appended to the sender's log after the
 		// snapshot has already been sent.
 		var sstNamesSubset []string
-		sstNamesSubset = append(sstNamesSubset, sstNames[2])
-		sstNamesSubset = append(sstNamesSubset, sstNames[4:]...)
+		sstNamesSubset = append(sstNamesSubset, sstNames[2:4]...)
+		sstNamesSubset = append(sstNamesSubset, sstNames[5:]...)
 
 		// Construct the expected SSTs and ensure that they are byte-by-byte
 		// equal. This verification ensures that the SSTs have the same
 		// tombstones and range deletion tombstones.
 		var expectedSSTs [][]byte
 
-		// Construct SST #1 through #3 as numbered above, but only ultimately
-		// keep the 3rd one.
+		// Construct SST #1 through #4 as numbered above, but only ultimately
+		// keep the 3rd and 4th ones.
 		keyRanges := rditer.MakeReplicatedKeyRanges(inSnap.State.Desc)
 		it := rditer.NewReplicaDataIterator(inSnap.State.Desc, sendingEng, true /* replicatedOnly */, false /* seekEnd */)
 		defer it.Close()
@@ -3091,7 +3092,7 @@ func TestStoreRangeMergeRaftSnapshot(t *testing.T) {
 		}
 		expectedSSTs = expectedSSTs[2:]
 
-		// Construct SSTs #5 and #6: range-id local keys of subsumed replicas
+		// Construct SSTs #6 and #7: range-id local keys of subsumed replicas
 		// with RangeIDs 3 and 4.
 		for _, rangeID := range []roachpb.RangeID{roachpb.RangeID(3), roachpb.RangeID(4)} {
 			sstFile := &storage.MemFile{}
@@ -3113,7 +3114,7 @@ func TestStoreRangeMergeRaftSnapshot(t *testing.T) {
 			expectedSSTs = append(expectedSSTs, sstFile.Data())
 		}
 
-		// Construct SST #7: user key range of subsumed replicas.
+		// Construct SST #8: user key range of subsumed replicas.
 		sstFile := &storage.MemFile{}
 		sst := storage.MakeIngestionSSTWriter(sstFile)
 		defer sst.Close()
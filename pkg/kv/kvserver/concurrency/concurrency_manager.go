@@ -306,7 +306,7 @@ func (m *managerImpl) HandleTransactionPushError(
 
 // OnLockAcquired implements the LockManager interface.
 func (m *managerImpl) OnLockAcquired(ctx context.Context, acq *roachpb.LockAcquisition) {
-	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, lock.Exclusive, acq.Durability); err != nil {
+	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, acq.Strength, acq.Durability); err != nil {
 		log.Fatalf(ctx, "%v", err)
 	}
 }
@@ -435,6 +435,33 @@ func (r *Request) writeConflictTimestamp() hlc.Timestamp {
 	return ts
 }
 
+// lockStrength returns the strength of the locks that the request acquires on
+// the keys in its LockSpans that it accesses with SpanReadWrite access. A
+// request whose only locking operations are reads that acquire Shared locks
+// is compatible with Shared locks held by other transactions. All other
+// requests are treated as acquiring Exclusive locks.
+func (r *Request) lockStrength() lock.Strength {
+	if r.Txn == nil {
+		// Non-transactional requests do not acquire locks, but any writes they
+		// perform conflict with all locks.
+		return lock.Exclusive
+	}
+	str := lock.None
+	for _, ru := range r.Requests {
+		req := ru.GetInner()
+		if !roachpb.IsLocking(req) {
+			continue
+		}
+		if reqStr := roachpb.LockingStrength(req); reqStr > str {
+			str = reqStr
+		}
+	}
+	if str != lock.Shared {
+		return lock.Exclusive
+	}
+	return lock.Shared
+}
+
 func (r *Request) isSingle(m roachpb.Method) bool {
 	if len(r.Requests) != 1 {
 		return false
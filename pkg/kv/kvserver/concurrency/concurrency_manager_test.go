@@ -306,6 +306,7 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 
 				// Confirm that the request has a corresponding write request.
 				found := false
+				str := lock.Exclusive
 				for _, ru := range guard.Req.Requests {
 					req := ru.GetInner()
 					keySpan := roachpb.Span{Key: roachpb.Key(key)}
@@ -313,6 +314,7 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 						req.Header().Span().Contains(keySpan) &&
 						req.Header().Sequence == seqNum {
 						found = true
+						str = roachpb.LockingStrength(req)
 						break
 					}
 				}
@@ -325,7 +327,7 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 
 				mon.runSync("acquire lock", func(ctx context.Context) {
 					log.Eventf(ctx, "txn %s @ %s", txn.ID.Short(), key)
-					acq := roachpb.MakeLockAcquisition(txnAcquire, roachpb.Key(key), dur)
+					acq := roachpb.MakeLockAcquisition(txnAcquire, roachpb.Key(key), str, dur)
 					m.OnLockAcquired(ctx, &acq)
 				})
 				return c.waitAndCollect(t, mon)
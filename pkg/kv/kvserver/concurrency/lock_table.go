@@ -262,6 +262,9 @@ type lockTableGuardImpl struct {
 	spans   *spanset.SpanSet
 	readTS  hlc.Timestamp
 	writeTS hlc.Timestamp
+	// The strength of the locks the request may acquire on the keys it accesses
+	// with SpanReadWrite access: Shared or Exclusive. See Request.lockStrength.
+	str lock.Strength
 
 	// Snapshots of the trees for which this request has some spans. Note that
 	// the lockStates in these snapshots may have been removed from
@@ -518,6 +521,46 @@ func (lh *lockHolderInfo) isEmpty() bool {
 	return lh.txn == nil && lh.seqs == nil && lh.ts.IsEmpty()
 }
 
+// Information about a transaction holding a lock. We track information for
+// each durability level separately since a transaction can go through
+// multiple epochs and TxnSeq and may acquire the same lock in replicated and
+// unreplicated mode at different stages.
+type txnLockHolder struct {
+	holder [lock.MaxDurability + 1]lockHolderInfo
+}
+
+func makeTxnLockHolder(
+	durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
+) txnLockHolder {
+	var h txnLockHolder
+	h.holder[durability] = lockHolderInfo{
+		txn:  txn,
+		ts:   ts,
+		seqs: append([]enginepb.TxnSeq(nil), txn.Sequence),
+	}
+	return h
+}
+
+// Returns the transaction holding the lock and the timestamp at which it is
+// held.
+func (h *txnLockHolder) getTxnAndTS() (*enginepb.TxnMeta, hlc.Timestamp) {
+	// If the lock is held as both replicated and unreplicated we want to
+	// provide the lower of the two timestamps, since the lower timestamp
+	// contends with more transactions. Else we provide whichever one it is held
+	// at.
+
+	// Start with the assumption that it is held as replicated.
+	index := lock.Replicated
+	// Condition under which we prefer the unreplicated holder.
+	if h.holder[index].txn == nil || (h.holder[lock.Unreplicated].txn != nil &&
+		// If we are evaluating the following clause we are sure that it is held
+		// as both replicated and unreplicated.
+		h.holder[lock.Unreplicated].ts.Less(h.holder[lock.Replicated].ts)) {
+		index = lock.Unreplicated
+	}
+	return h.holder[index].txn, h.holder[index].ts
+}
+
 // Per lock state in lockTableImpl.
 //
 // NOTE: we can't easily pool lockState objects without some form of reference
@@ -538,20 +581,27 @@ type lockState struct {
 
 	// Invariant summary (see detailed comments below):
 	// - both holder.locked and waitQ.reservation != nil cannot be true.
-	// - if holder.locked and multiple holderInfos have txn != nil: all the
+	// - holder.locked <=> len(holder.holders) > 0. Each of the holders
+	//   belongs to a different transaction, and there is more than one only
+	//   if holder.strength is Shared.
+	// - if multiple holderInfos of a txnLockHolder have txn != nil: all the
 	//   txns must have the same txn.ID.
 	// - !holder.locked => waitingReaders.Len() == 0. That is, readers wait
 	//   only if the lock is held. They do not wait for a reservation.
+	// - holder.strength == Shared => waitingReaders.Len() == 0, since readers
+	//   do not conflict with Shared locks.
 	// - If reservation != nil, that request is not in queuedWriters.
 
-	// Information about whether the lock is held and the holder. We track
-	// information for each durability level separately since a transaction can
-	// go through multiple epochs and TxnSeq and may acquire the same lock in
-	// replicated and unreplicated mode at different stages.
+	// Information about whether the lock is held and the holders.
 	holder struct {
 		locked bool
-		// LockStrength is always Exclusive
-		holder [lock.MaxDurability + 1]lockHolderInfo
+		// The strength with which the lock is held, Shared or Exclusive. It does
+		// not decrease while the lock is held, even if the holder releases the
+		// part of the lock that it acquired with Exclusive strength.
+		strength lock.Strength
+		// The transactions holding the lock, in the order in which they acquired
+		// it.
+		holders []txnLockHolder
 	}
 
 	// Information about the requests waiting on the lock.
@@ -651,7 +701,23 @@ type lockWaitQueue struct {
 	//   This is a deadlock caused by the lock table unless req2 partially
 	//   breaks the reservation at A.
 	//
-	// Extension for Shared and Upgrade locks:
+	// Shared locks:
+	// A lock can be held with Exclusive strength by a single transaction or
+	// with Shared strength by any number of transactions. Shared locks are
+	// only held with Unreplicated durability. Non-locking reads do
+	// not conflict with Shared locks, so they never wait on a lock held with
+	// Shared strength. Requests that want to acquire Shared locks (see
+	// lockTableGuardImpl.str) wait in queuedWriters, like writers. Such a
+	// request does not conflict with the holders of a Shared lock, but it does
+	// not jump ahead of conflicting requests that are already waiting in the
+	// queue with a lower seqNum either, since that could starve them. In that
+	// case it depends on the first such conflicting waiter instead of on a
+	// lock holder. A request that wants to acquire a Shared lock makes and
+	// breaks reservations like a writer does. Once it acquires the lock, the
+	// other waiters that want to acquire Shared locks and are not queued
+	// behind a conflicting waiter are done waiting.
+	//
+	// Extension for joint reservations and Upgrade locks:
 	// There are 3 aspects to consider: holders; reservers; the dependencies
 	// that need to be captured when waiting.
 	//
@@ -756,11 +822,16 @@ func (l *lockState) Format(buf *strings.Builder) {
 		// UUIDs using a counter and makes this output more readable.
 		fmt.Fprintf(b, "txn: %v, ts: %v, seq: %v\n", txn.ID, ts, txn.Sequence)
 	}
-	writeHolderInfo := func(b *strings.Builder, txn *enginepb.TxnMeta, ts hlc.Timestamp) {
-		fmt.Fprintf(b, "  holder: txn: %v, ts: %v, info: ", txn.ID, ts)
+	writeHolderInfo := func(b *strings.Builder, th *txnLockHolder) {
+		txn, ts := th.getTxnAndTS()
+		fmt.Fprintf(b, "  holder: txn: %v, ts: %v, ", txn.ID, ts)
+		if l.holder.strength == lock.Shared {
+			fmt.Fprintf(b, "str: shared, ")
+		}
+		fmt.Fprintf(b, "info: ")
 		first := true
-		for i := range l.holder.holder {
-			h := &l.holder.holder[i]
+		for i := range th.holder {
+			h := &th.holder[i]
 			if h.txn == nil {
 				continue
 			}
@@ -781,12 +852,13 @@ func (l *lockState) Format(buf *strings.Builder) {
 		}
 		fmt.Fprintln(b, "")
 	}
-	txn, ts := l.getLockHolder()
-	if txn == nil {
+	if !l.holder.locked {
 		fmt.Fprintf(buf, "  res: req: %d, ", l.reservation.seqNum)
 		writeResInfo(buf, l.reservation.txn, l.reservation.writeTS)
 	} else {
-		writeHolderInfo(buf, txn, ts)
+		for i := range l.holder.holders {
+			writeHolderInfo(buf, &l.holder.holders[i])
+		}
 	}
 	// TODO(sumeer): Add an optional `description string` field to Request and
 	// lockTableGuardImpl that tests can set to avoid relying on the seqNum to
@@ -843,26 +915,19 @@ func (l *lockState) tryBreakReservation(seqNum uint64) bool {
 // waitForDistinguished states.
 // REQUIRES: l.mu is locked.
 func (l *lockState) informActiveWaiters() {
-	waitForState := waitingState{kind: waitFor, key: l.key}
-	findDistinguished := l.distinguishedWaiter == nil
-	if lockHolderTxn, _ := l.getLockHolder(); lockHolderTxn != nil {
-		waitForState.txn = lockHolderTxn
-		waitForState.held = true
-	} else {
-		waitForState.txn = l.reservation.txn
-		if !findDistinguished && l.distinguishedWaiter.isSameTxnAsReservation(waitForState) {
-			findDistinguished = true
-			l.distinguishedWaiter = nil
-		}
+	if !l.holder.locked && l.distinguishedWaiter != nil &&
+		l.distinguishedWaiter.isSameTxn(l.reservation.txn) {
+		l.distinguishedWaiter = nil
 	}
+	findDistinguished := l.distinguishedWaiter == nil
 
 	for e := l.waitingReaders.Front(); e != nil; e = e.Next() {
-		state := waitForState
-		state.guardAccess = spanset.SpanReadOnly
 		// Since there are waiting readers we could not have transitioned out of
 		// or into a state with a reservation, since readers do not wait for
 		// reservations.
 		g := e.Value.(*lockTableGuardImpl)
+		state := waitingState{kind: waitFor, key: l.key, guardAccess: spanset.SpanReadOnly}
+		state.txn, state.held = l.waitingFor(g, spanset.SpanReadOnly)
 		if findDistinguished {
 			l.distinguishedWaiter = g
 			findDistinguished = false
@@ -881,12 +946,11 @@ func (l *lockState) informActiveWaiters() {
 			continue
 		}
 		g := qg.guard
-		var state waitingState
-		if g.isSameTxnAsReservation(waitForState) {
+		state := waitingState{kind: waitFor, key: l.key, guardAccess: spanset.SpanReadWrite}
+		state.txn, state.held = l.waitingFor(g, spanset.SpanReadWrite)
+		if g.isSameTxnAsReservation(state) {
 			state = waitingState{kind: waitSelf}
 		} else {
-			state = waitForState
-			state.guardAccess = spanset.SpanReadWrite
 			if findDistinguished {
 				l.distinguishedWaiter = g
 				findDistinguished = false
@@ -903,7 +967,8 @@ func (l *lockState) informActiveWaiters() {
 }
 
 // releaseWritersFromTxn removes all waiting writers for the lockState that are
-// part of the specified transaction.
+// part of the specified transaction, which holds the lock, and that do not
+// conflict with the other holders of the lock.
 // REQUIRES: l.mu is locked.
 func (l *lockState) releaseWritersFromTxn(txn *enginepb.TxnMeta) {
 	for e := l.queuedWriters.Front(); e != nil; {
@@ -911,7 +976,7 @@ func (l *lockState) releaseWritersFromTxn(txn *enginepb.TxnMeta) {
 		curr := e
 		e = e.Next()
 		g := qg.guard
-		if g.isSameTxn(txn) {
+		if g.isSameTxn(txn) && !l.writerConflictsWithLockHolders(g) {
 			if qg.active {
 				if g == l.distinguishedWaiter {
 					l.distinguishedWaiter = nil
@@ -959,10 +1024,8 @@ func (l *lockState) tryMakeNewDistinguished() {
 // REQUIRES: l.mu is locked.
 func (l *lockState) isEmptyLock() bool {
 	if !l.holder.locked && l.reservation == nil {
-		for i := range l.holder.holder {
-			if !l.holder.holder[i].isEmpty() {
-				panic("lockState with !locked but non-zero lockHolderInfo")
-			}
+		if len(l.holder.holders) > 0 {
+			panic("lockState with !locked but non-empty holders")
 		}
 		if l.waitingReaders.Len() > 0 || l.queuedWriters.Len() > 0 {
 			panic("lockState with waiters but no holder or reservation")
@@ -976,50 +1039,154 @@ func (l *lockState) isEmptyLock() bool {
 // given id.
 // REQUIRES: l.mu is locked.
 func (l *lockState) isLockedBy(id uuid.UUID) bool {
-	if l.holder.locked {
-		var holderID uuid.UUID
-		if l.holder.holder[lock.Unreplicated].txn != nil {
-			holderID = l.holder.holder[lock.Unreplicated].txn.ID
-		} else {
-			holderID = l.holder.holder[lock.Replicated].txn.ID
+	return l.holderIndex(id) >= 0
+}
+
+// Returns the index in holder.holders of the transaction with the given id,
+// or -1 if that transaction does not hold the lock.
+// REQUIRES: l.mu is locked.
+func (l *lockState) holderIndex(id uuid.UUID) int {
+	for i := range l.holder.holders {
+		if txn, _ := l.holder.holders[i].getTxnAndTS(); txn.ID == id {
+			return i
 		}
-		return id == holderID
 	}
-	return false
+	return -1
 }
 
-// Returns information about the current lock holder if the lock is held, else
+// Returns information about the first lock holder if the lock is held, else
 // returns nil.
 // REQUIRES: l.mu is locked.
 func (l *lockState) getLockHolder() (*enginepb.TxnMeta, hlc.Timestamp) {
 	if !l.holder.locked {
 		return nil, hlc.Timestamp{}
 	}
+	return l.holder.holders[0].getTxnAndTS()
+}
 
-	// If the lock is held as both replicated and unreplicated we want to
-	// provide the lower of the two timestamps, since the lower timestamp
-	// contends with more transactions. Else we provide whichever one it is held
-	// at.
+// Returns information about a lock holder from a different transaction than
+// that of the request g, else returns nil.
+// REQUIRES: l.mu is locked.
+func (l *lockState) getConflictingLockHolder(
+	g *lockTableGuardImpl,
+) (*enginepb.TxnMeta, hlc.Timestamp) {
+	for i := range l.holder.holders {
+		if txn, ts := l.holder.holders[i].getTxnAndTS(); !g.isSameTxn(txn) {
+			return txn, ts
+		}
+	}
+	return nil, hlc.Timestamp{}
+}
 
-	// Start with the assumption that it is held as replicated.
-	index := lock.Replicated
-	// Condition under which we prefer the unreplicated holder.
-	if l.holder.holder[index].txn == nil || (l.holder.holder[lock.Unreplicated].txn != nil &&
-		// If we are evaluating the following clause we are sure that it is held
-		// as both replicated and unreplicated.
-		l.holder.holder[lock.Unreplicated].ts.Less(l.holder.holder[lock.Replicated].ts)) {
-		index = lock.Unreplicated
+// Returns true iff the request g, which accesses the key with SpanReadWrite
+// access, conflicts with the lock holders.
+// REQUIRES: l.mu is locked.
+func (l *lockState) writerConflictsWithLockHolders(g *lockTableGuardImpl) bool {
+	if !l.holder.locked || (l.holder.strength == lock.Shared && g.str == lock.Shared) {
+		return false
+	}
+	txn, _ := l.getConflictingLockHolder(g)
+	return txn != nil
+}
+
+// Returns the first waiter in queuedWriters that is ahead of the request g,
+// which wants to acquire a Shared lock, and conflicts with it. That is a
+// transactional request from a different transaction which wants to acquire
+// an Exclusive lock. Returns nil if there is no such waiter.
+// REQUIRES: l.mu is locked.
+func (l *lockState) firstConflictingWaiterAhead(g *lockTableGuardImpl) *lockTableGuardImpl {
+	for e := l.queuedWriters.Front(); e != nil; e = e.Next() {
+		w := e.Value.(*queuedGuard).guard
+		if w == g {
+			break
+		}
+		if w.seqNum < g.seqNum && w.txn != nil && w.str != lock.Shared && !g.isSameTxn(w.txn) {
+			return w
+		}
 	}
-	return l.holder.holder[index].txn, l.holder.holder[index].ts
+	return nil
 }
 
-// Removes the current lock holder from the lock.
+// Returns the transaction that the active waiter g with access sa is waiting
+// for, and whether that transaction holds the lock. A request that wants to
+// acquire a Shared lock does not conflict with the holders of a Shared lock,
+// so it waits for the first conflicting waiter ahead of it in the queue.
+// REQUIRES: l.mu is locked and the lock is held or reserved.
+func (l *lockState) waitingFor(
+	g *lockTableGuardImpl, sa spanset.SpanAccess,
+) (_ *enginepb.TxnMeta, held bool) {
+	if !l.holder.locked {
+		return l.reservation.txn, false
+	}
+	if sa == spanset.SpanReadWrite && l.holder.strength == lock.Shared && g.str == lock.Shared {
+		if w := l.firstConflictingWaiterAhead(g); w != nil {
+			return w.txn, false
+		}
+	}
+	if txn, _ := l.getConflictingLockHolder(g); txn != nil {
+		return txn, true
+	}
+	txn, _ := l.getLockHolder()
+	return txn, true
+}
+
+// Releases the waiters that want to acquire a Shared lock when the lock is
+// held with Shared strength, unless they are queued behind a conflicting
+// waiter.
+// REQUIRES: l.mu is locked.
+func (l *lockState) releaseSharedWaiters() {
+	if !l.holder.locked || l.holder.strength != lock.Shared {
+		return
+	}
+	for e := l.queuedWriters.Front(); e != nil; {
+		qg := e.Value.(*queuedGuard)
+		curr := e
+		e = e.Next()
+		g := qg.guard
+		if g.str != lock.Shared || l.firstConflictingWaiterAhead(g) != nil {
+			continue
+		}
+		l.queuedWriters.Remove(curr)
+		if qg.active {
+			if g == l.distinguishedWaiter {
+				l.distinguishedWaiter = nil
+			}
+			g.doneWaitingAtLock(false, l)
+		} else {
+			g.mu.Lock()
+			delete(g.mu.locks, l)
+			g.mu.Unlock()
+		}
+	}
+}
+
+// Removes the current lock holders from the lock.
 // REQUIRES: l.mu is locked.
 func (l *lockState) clearLockHolder() {
 	l.holder.locked = false
-	for i := range l.holder.holder {
-		l.holder.holder[i] = lockHolderInfo{}
+	l.holder.strength = lock.None
+	l.holder.holders = nil
+}
+
+// Removes the lock holder at index i of holder.holders from the lock. Returns
+// whether the lockState can be garbage collected.
+// REQUIRES: l.mu is locked.
+func (l *lockState) removeLockHolder(i int) (gc bool) {
+	if len(l.holder.holders) == 1 {
+		l.clearLockHolder()
+		return l.lockIsFree()
+	}
+	// The lock remains held with Shared strength by the other holders.
+	l.holder.holders = append(l.holder.holders[:i], l.holder.holders[i+1:]...)
+	if len(l.holder.holders) == 1 {
+		// Waiting requests from the remaining holder's txn no longer conflict
+		// with any other holder.
+		txn, _ := l.holder.holders[0].getTxnAndTS()
+		l.releaseWritersFromTxn(txn)
 	}
+	l.releaseSharedWaiters()
+	l.informActiveWaiters()
+	return false
 }
 
 // Decides whether the request g with access sa should actively wait at this
@@ -1041,8 +1208,8 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 	}
 
 	// Lock is not empty.
-	lockHolderTxn, lockHolderTS := l.getLockHolder()
-	if lockHolderTxn != nil && g.isSameTxn(lockHolderTxn) {
+	lockHolderTxn, lockHolderTS := l.getConflictingLockHolder(g)
+	if l.holder.locked && lockHolderTxn == nil {
 		// Already locked by this txn.
 		return false
 	}
@@ -1052,6 +1219,10 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 			// Reads only care about locker, not a reservation.
 			return false
 		}
+		if l.holder.strength == lock.Shared {
+			// Reads do not conflict with Shared locks.
+			return false
+		}
 		// Locked by some other txn.
 		if g.readTS.Less(lockHolderTS) {
 			return false
@@ -1079,8 +1250,19 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 
 	waitForState := waitingState{kind: waitFor, key: l.key}
 	if lockHolderTxn != nil {
-		waitForState.txn = lockHolderTxn
-		waitForState.held = true
+		if sa == spanset.SpanReadWrite && l.holder.strength == lock.Shared && g.str == lock.Shared {
+			// Compatible with the lock holders, but the request does not jump
+			// ahead of conflicting requests that are already waiting, since that
+			// could starve them.
+			w := l.firstConflictingWaiterAhead(g)
+			if w == nil {
+				return false
+			}
+			waitForState.txn = w.txn
+		} else {
+			waitForState.txn = lockHolderTxn
+			waitForState.held = true
+		}
 	} else {
 		if l.reservation == g {
 			// Already reserved by this request.
@@ -1180,23 +1362,42 @@ func (l *lockState) tryActiveWait(g *lockTableGuardImpl, sa spanset.SpanAccess,
 	return true
 }
 
-// Acquires this lock. Returns the list of guards that are done actively
-// waiting at this key -- these will be requests from the same transaction
-// that is acquiring the lock.
+// Acquires this lock with strength str, which is Shared or Exclusive. Waiting
+// requests that no longer need to wait are released -- these will be requests
+// from the same transaction that is acquiring the lock and, if the lock is
+// held with Shared strength, requests that want to acquire a Shared lock.
 // Acquires l.mu.
 func (l *lockState) acquireLock(
-	_ lock.Strength, durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
+	str lock.Strength, durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
 ) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	if l.holder.locked {
 		// Already held.
-		beforeTxn, beforeTs := l.getLockHolder()
-		if txn.ID != beforeTxn.ID {
-			return errors.AssertionFailedf("existing lock cannot be acquired by different transaction")
+		i := l.holderIndex(txn.ID)
+		if i < 0 {
+			if str != lock.Shared || l.holder.strength != lock.Shared {
+				return errors.AssertionFailedf("existing lock cannot be acquired by different transaction")
+			}
+			// Shared locks are compatible, so the txn joins the existing holders.
+			l.holder.holders = append(l.holder.holders, makeTxnLockHolder(durability, txn, ts))
+			l.releaseWritersFromTxn(txn)
+			return nil
 		}
-		seqs := l.holder.holder[durability].seqs
-		if l.holder.holder[durability].txn != nil && l.holder.holder[durability].txn.Epoch < txn.Epoch {
+		if str == lock.Exclusive && l.holder.strength == lock.Shared {
+			if len(l.holder.holders) > 1 {
+				return errors.AssertionFailedf(
+					"existing lock cannot be upgraded while held by a different transaction")
+			}
+			// Waiters that were compatible with the Shared lock conflict with the
+			// Exclusive lock.
+			l.holder.strength = lock.Exclusive
+			defer l.informActiveWaiters()
+		}
+		h := &l.holder.holders[i]
+		_, beforeTs := h.getTxnAndTS()
+		seqs := h.holder[durability].seqs
+		if h.holder[durability].txn != nil && h.holder[durability].txn.Epoch < txn.Epoch {
 			// Clear the sequences for the older epoch.
 			seqs = seqs[:0]
 		}
@@ -1214,11 +1415,11 @@ func (l *lockState) acquireLock(
 				seqs = append(seqs, 0)
 				copy(seqs[i+1:], seqs[i:])
 				seqs[i] = txn.Sequence
-				l.holder.holder[durability].seqs = seqs
+				h.holder[durability].seqs = seqs
 			}
 			return nil
 		}
-		l.holder.holder[durability].txn = txn
+		h.holder[durability].txn = txn
 		// Forward the lock's timestamp instead of assigning to it blindly.
 		// While lock acquisition uses monotonically increasing timestamps
 		// from the perspective of the transaction's coordinator, this does
@@ -1237,7 +1438,7 @@ func (l *lockState) acquireLock(
 		// regress, so by forwarding its timestamp during the second acquisition
 		// instead if assigning to it blindly, it remains at 20.
 		//
-		// However, a lock's timestamp as reported by getLockHolder can regress
+		// However, a lock's timestamp as reported by getTxnAndTS can regress
 		// if it is acquired at a lower timestamp and a different durability
 		// than it was previously held with. This is necessary to support
 		// because the hard constraint which we must uphold here that the
@@ -1257,10 +1458,10 @@ func (l *lockState) acquireLock(
 		// timestamp at that point, which may cause them to conflict with the
 		// lock even if they had not conflicted before. In a sense, it is no
 		// different than the first time a lock is added to the lockTable.
-		l.holder.holder[durability].ts.Forward(ts)
-		l.holder.holder[durability].seqs = append(seqs, txn.Sequence)
+		h.holder[durability].ts.Forward(ts)
+		h.holder[durability].seqs = append(seqs, txn.Sequence)
 
-		_, afterTs := l.getLockHolder()
+		_, afterTs := h.getTxnAndTS()
 		if beforeTs.Less(afterTs) {
 			l.increasedLockTs(afterTs)
 		}
@@ -1299,12 +1500,13 @@ func (l *lockState) acquireLock(
 	}
 	l.reservation = nil
 	l.holder.locked = true
-	l.holder.holder[durability].txn = txn
-	l.holder.holder[durability].ts = ts
-	l.holder.holder[durability].seqs = append([]enginepb.TxnSeq(nil), txn.Sequence)
+	l.holder.strength = str
+	l.holder.holders = append(l.holder.holders, makeTxnLockHolder(durability, txn, ts))
 
 	// If there are waiting requests from the same txn, they no longer need to wait.
 	l.releaseWritersFromTxn(txn)
+	// Neither do requests that want to acquire a Shared lock, if compatible.
+	l.releaseSharedWaiters()
 
 	// Inform active waiters since lock has transitioned to held.
 	l.informActiveWaiters()
@@ -1321,13 +1523,16 @@ func (l *lockState) discoveredLock(
 	defer l.mu.Unlock()
 
 	if l.holder.locked {
-		if !l.isLockedBy(txn.ID) {
+		if len(l.holder.holders) > 1 || !l.isLockedBy(txn.ID) {
 			return errors.AssertionFailedf("discovered lock by different transaction than existing lock")
 		}
 	} else {
 		l.holder.locked = true
+		l.holder.holders = append(l.holder.holders, txnLockHolder{})
 	}
-	holder := &l.holder.holder[lock.Replicated]
+	// Replicated locks are discovered as intents, which are Exclusive.
+	l.holder.strength = lock.Exclusive
+	holder := &l.holder.holders[0].holder[lock.Replicated]
 	if holder.txn == nil {
 		holder.txn = txn
 		holder.ts = ts
@@ -1405,16 +1610,18 @@ func (l *lockState) discoveredLock(
 func (l *lockState) tryClearLock(force bool) bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	replicatedHeld := l.holder.locked && l.holder.holder[lock.Replicated].txn != nil
+	// Shared locks are never replicated, so a replicated lock is held by a
+	// single holder.
+	replicatedHeld := l.holder.locked && l.holder.holders[0].holder[lock.Replicated].txn != nil
 	if replicatedHeld && l.distinguishedWaiter == nil && !force {
 		// Replicated lock is held and has no distinguished waiter.
 		return false
 	}
 
-	// Remove unreplicated holder.
-	l.holder.holder[lock.Unreplicated] = lockHolderInfo{}
 	var waitState waitingState
 	if replicatedHeld && !force {
+		// Remove unreplicated holder.
+		l.holder.holders[0].holder[lock.Unreplicated] = lockHolderInfo{}
 		lockHolderTxn, _ := l.getLockHolder()
 		// Note that none of the current waiters can be requests
 		// from lockHolderTxn.
@@ -1496,22 +1703,22 @@ func removeIgnored(
 func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (gc bool, err error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	if !l.isLockedBy(up.Txn.ID) {
+	idx := l.holderIndex(up.Txn.ID)
+	if idx < 0 {
 		return false, nil
 	}
 	if up.Status.IsFinalized() {
-		l.clearLockHolder()
-		gc = l.lockIsFree()
-		return gc, nil
+		return l.removeLockHolder(idx), nil
 	}
 
 	txn := &up.Txn
 	ts := up.Txn.WriteTimestamp
-	_, beforeTs := l.getLockHolder()
+	h := &l.holder.holders[idx]
+	_, beforeTs := h.getTxnAndTS()
 	advancedTs := beforeTs.Less(ts)
 	isLocked := false
-	for i := range l.holder.holder {
-		holder := &l.holder.holder[i]
+	for i := range h.holder {
+		holder := &h.holder[i]
 		if holder.txn == nil {
 			continue
 		}
@@ -1534,7 +1741,7 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (gc bool, err error) {
 			*holder = lockHolderInfo{}
 			continue
 		}
-		// Unreplicated lock held in same epoch or a higher epoch.
+		// Lock held in same epoch or a higher epoch.
 		if advancedTs {
 			// We may advance ts here but not update the holder.txn object below
 			// for the reason stated in the comment about mvccResolveWriteIntent().
@@ -1558,9 +1765,7 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (gc bool, err error) {
 	}
 
 	if !isLocked {
-		l.clearLockHolder()
-		gc = l.lockIsFree()
-		return gc, nil
+		return l.removeLockHolder(idx), nil
 	}
 
 	if advancedTs {
@@ -1652,6 +1857,13 @@ func (l *lockState) requestDone(g *lockTableGuardImpl) (gc bool) {
 	if !doneRemoval {
 		panic("lockTable bug")
 	}
+	if l.holder.locked && l.holder.strength == lock.Shared && g.str != lock.Shared {
+		// Waiters that want to acquire a Shared lock may have been queued behind
+		// g, which conflicted with them.
+		l.releaseSharedWaiters()
+		l.informActiveWaiters()
+		return false
+	}
 	if distinguishedRemoved {
 		l.tryMakeNewDistinguished()
 	}
@@ -1678,8 +1890,10 @@ func (l *lockState) tryFreeLockOnReplicatedAcquire() bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// Bail if not locked with only the Unreplicated durability.
-	if !l.holder.locked || l.holder.holder[lock.Replicated].txn != nil {
+	// Bail if not locked by a single holder with only the Unreplicated
+	// durability.
+	if !l.holder.locked || len(l.holder.holders) > 1 ||
+		l.holder.holders[0].holder[lock.Replicated].txn != nil {
 		return false
 	}
 
@@ -1783,6 +1997,7 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 		g.spans = req.LockSpans
 		g.readTS = req.readConflictTimestamp()
 		g.writeTS = req.writeConflictTimestamp()
+		g.str = req.lockStrength()
 		g.sa = spanset.NumSpanAccess - 1
 		g.index = -1
 	} else {
@@ -1901,8 +2116,22 @@ func (t *lockTableImpl) AcquireLock(
 		// If not enabled, don't track any locks.
 		return nil
 	}
-	if strength != lock.Exclusive {
-		return errors.AssertionFailedf("lock strength not Exclusive")
+	switch strength {
+	case lock.Shared:
+		if durability == lock.Replicated {
+			// Only locking reads acquire Shared locks, and they acquire them with
+			// Unreplicated durability. Replicated Shared locks would have to be
+			// persisted outside of the MVCC keyspace, since unlike replicated
+			// Exclusive locks they can't be stored as intents.
+			return errors.AssertionFailedf("replicated Shared locks are not supported")
+		}
+	case lock.Exclusive:
+	case lock.Upgrade:
+		// Upgrade locks are not supported by the lockTable, so they are tracked
+		// as Exclusive locks, which provide stronger isolation.
+		strength = lock.Exclusive
+	default:
+		return errors.AssertionFailedf("unsupported lock strength %s", strength)
 	}
 	ss := spanset.SpanGlobal
 	if keys.IsLocal(key) {
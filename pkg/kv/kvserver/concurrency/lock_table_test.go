@@ -52,10 +52,11 @@ new-txn txn=<name> ts=<int>[,<int>] epoch=<int> [seq=<int>]
 
  Creates a TxnMeta.
 
-new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+...
+new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+... [str=shared]
 ----
 
- Creates a Request.
+ Creates a Request. If str=shared, the request acquires Shared locks on the
+ keys it writes, else Exclusive locks.
 
 scan r=<name>
 ----
@@ -64,11 +65,12 @@ scan r=<name>
  Calls lockTable.ScanAndEnqueue. If the request has an existing guard, uses it.
  If a guard is returned, stores it for later use.
 
-acquire r=<name> k=<key> durability=r|u
+acquire r=<name> k=<key> durability=r|u [str=shared|exclusive]
 ----
 <error string>
 
  Acquires lock for the request, using the existing guard for that request.
+ The lock is Exclusive unless specified otherwise.
 
 release txn=<name> span=<start>[,<end>]
 ----
@@ -213,6 +215,13 @@ func TestLockTableBasic(t *testing.T) {
 						ReadTimestamp: ts,
 					}
 				}
+				if d.HasArg("str") {
+					if str := scanLockStrength(t, d); str == lock.Shared {
+						req.Requests = []roachpb.RequestUnion{{Value: &roachpb.RequestUnion_Scan{
+							Scan: &roachpb.ScanRequest{KeyLocking: lock.Shared},
+						}}}
+					}
+				}
 				requestsByName[reqName] = req
 				return ""
 
@@ -246,7 +255,11 @@ func TestLockTableBasic(t *testing.T) {
 				if s[0] == 'r' {
 					durability = lock.Replicated
 				}
-				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), lock.Exclusive, durability); err != nil {
+				str := lock.Exclusive
+				if d.HasArg("str") {
+					str = scanLockStrength(t, d)
+				}
+				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), str, durability); err != nil {
 					return err.Error()
 				}
 				return lt.(*lockTableImpl).String()
@@ -460,6 +473,20 @@ func scanTimestamp(t *testing.T, d *datadriven.TestData) hlc.Timestamp {
 	return ts
 }
 
+func scanLockStrength(t *testing.T, d *datadriven.TestData) lock.Strength {
+	var strS string
+	d.ScanArgs(t, "str", &strS)
+	switch strS {
+	case "shared":
+		return lock.Shared
+	case "exclusive":
+		return lock.Exclusive
+	default:
+		d.Fatalf(t, "unknown lock strength: %s", strS)
+		return 0
+	}
+}
+
 func getSpan(t *testing.T, d *datadriven.TestData, str string) roachpb.Span {
 	parts := strings.Split(str, ",")
 	span := roachpb.Span{Key: roachpb.Key(parts[0])}
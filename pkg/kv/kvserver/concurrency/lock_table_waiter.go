@@ -288,6 +288,14 @@ func (w *lockTableWaiterImpl) WaitOn(
 					delay = 0
 				}
 
+				// Similarly, if the request only needs to push the lock
+				// holder's timestamp and the lock holder tolerates write
+				// skew, the push does not force it to restart, so push
+				// immediately.
+				if state.guardAccess == spanset.SpanReadOnly && toleratesWriteSkew(state.txn) {
+					delay = 0
+				}
+
 				if delay > 0 {
 					if timer == nil {
 						timer = timeutil.NewTimer()
@@ -716,6 +724,10 @@ func hasMinPriority(txn *enginepb.TxnMeta) bool {
 	return txn != nil && txn.Priority == enginepb.MinTxnPriority
 }
 
+func toleratesWriteSkew(txn *enginepb.TxnMeta) bool {
+	return txn != nil && txn.IsoLevel.ToleratesWriteSkew()
+}
+
 func hasMaxPriority(txn *roachpb.Transaction) bool {
 	return txn != nil && txn.Priority == enginepb.MaxTxnPriority
 }
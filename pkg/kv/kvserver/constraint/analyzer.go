@@ -34,6 +34,11 @@ type AnalyzedConstraints struct {
 	// constraints the store satisfies. This field is unused if there are no
 	// constraints.
 	Satisfies map[roachpb.StoreID][]int
+	// VoterConstraints, when non-nil, holds the analysis of the zone's voter
+	// constraints against the existing voting replicas. It is only populated
+	// when placing voting replicas, which must satisfy both sets of
+	// constraints.
+	VoterConstraints *AnalyzedConstraints
 }
 
 // AnalyzeConstraints processes the zone config constraints that apply to a
@@ -45,18 +50,40 @@ func AnalyzeConstraints(
 	getStoreDescFn func(roachpb.StoreID) (roachpb.StoreDescriptor, bool),
 	existing []roachpb.ReplicaDescriptor,
 	zone *zonepb.ZoneConfig,
+) AnalyzedConstraints {
+	return analyzeConstraints(ctx, getStoreDescFn, existing, *zone.NumReplicas, zone.Constraints)
+}
+
+// AnalyzeVoterConstraints is like AnalyzeConstraints, but analyzes the zone's
+// voter constraints against the existing voting replicas of a range.
+func AnalyzeVoterConstraints(
+	ctx context.Context,
+	getStoreDescFn func(roachpb.StoreID) (roachpb.StoreDescriptor, bool),
+	existingVoters []roachpb.ReplicaDescriptor,
+	zone *zonepb.ZoneConfig,
+) AnalyzedConstraints {
+	return analyzeConstraints(
+		ctx, getStoreDescFn, existingVoters, zone.GetNumVoters(), zone.VoterConstraints)
+}
+
+func analyzeConstraints(
+	ctx context.Context,
+	getStoreDescFn func(roachpb.StoreID) (roachpb.StoreDescriptor, bool),
+	existing []roachpb.ReplicaDescriptor,
+	numReplicas int32,
+	constraints []zonepb.ConstraintsConjunction,
 ) AnalyzedConstraints {
 	result := AnalyzedConstraints{
-		Constraints: zone.Constraints,
+		Constraints: constraints,
 	}
 
-	if len(zone.Constraints) > 0 {
-		result.SatisfiedBy = make([][]roachpb.StoreID, len(zone.Constraints))
+	if len(constraints) > 0 {
+		result.SatisfiedBy = make([][]roachpb.StoreID, len(constraints))
 		result.Satisfies = make(map[roachpb.StoreID][]int)
 	}
 
 	var constrainedReplicas int32
-	for i, subConstraints := range zone.Constraints {
+	for i, subConstraints := range constraints {
 		constrainedReplicas += subConstraints.NumReplicas
 		for _, repl := range existing {
 			// If for some reason we don't have the store descriptor (which shouldn't
@@ -70,7 +97,7 @@ func AnalyzeConstraints(
 			}
 		}
 	}
-	if constrainedReplicas > 0 && constrainedReplicas < *zone.NumReplicas {
+	if constrainedReplicas > 0 && constrainedReplicas < numReplicas {
 		result.UnconstrainedReplicas = true
 	}
 	return result
@@ -283,13 +283,25 @@ func processReplicatedKeyRange(
 	// be added with that version and the batch will be sent. When the newest
 	// version for a key has been reached, if haveGarbageForThisKey, we'll add the
 	// current key to the batch with the gcTimestampForThisKey.
+	//
+	// Versions shadowed by an MVCC range tombstone at or below the threshold
+	// are garbage as well, since no read at or above the threshold can observe
+	// them. Once all such versions have been removed, the range tombstones at or
+	// below the threshold are removed too.
 	var (
 		batchGCKeys           []roachpb.GCRequest_GCKey
 		batchGCKeysBytes      int64
 		haveGarbageForThisKey bool
 		gcTimestampForThisKey hlc.Timestamp
 		sentBatchForThisKey   bool
+		failedBatch           bool
 	)
+	tombstones, err := storage.ReadMVCCRangeTombstones(
+		snap, desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey(),
+	)
+	if err != nil {
+		return err
+	}
 	it := makeGCIterator(desc, snap)
 	defer it.close()
 	for ; ; it.step() {
@@ -308,11 +320,18 @@ func processReplicatedKeyRange(
 			continue
 		}
 		isNewest := s.curIsNewest()
-		if isGarbage(threshold, s.cur, s.next, isNewest) {
+		shadowTS, shadowed := tombstones.Covering(s.cur.Key.Key).ShadowedAt(s.cur.Key.Timestamp)
+		shadowed = shadowed && shadowTS.LessEq(threshold)
+		if shadowed || isGarbage(threshold, s.cur, s.next, isNewest) {
 			keyBytes := int64(s.cur.Key.EncodedSize())
 			batchGCKeysBytes += keyBytes
 			haveGarbageForThisKey = true
 			gcTimestampForThisKey = s.cur.Key.Timestamp
+			if shadowed && isNewest {
+				// The newest version can only be removed along with the range
+				// tombstone deleting it.
+				gcTimestampForThisKey = shadowTS
+			}
 			info.AffectedVersionsKeyBytes += keyBytes
 			info.AffectedVersionsValBytes += int64(len(s.cur.Value))
 		}
@@ -345,6 +364,7 @@ func processReplicatedKeyRange(
 				// thresholds. We may leave some inconsistent history
 				// behind, but nobody can read it.
 				log.Warningf(ctx, "failed to GC a batch of keys: %v", err)
+				failedBatch = true
 			}
 			batchGCKeys = nil
 			batchGCKeysBytes = 0
@@ -356,6 +376,27 @@ func processReplicatedKeyRange(
 			return err
 		}
 	}
+	// Removing a range tombstone reveals the versions below it, so only do so
+	// if all of the versions it shadows have been removed.
+	if failedBatch {
+		return nil
+	}
+	var gcTombstones []roachpb.GCRequest_GCKey
+	for i := range tombstones {
+		t := &tombstones[i]
+		if n := len(t.Timestamps); n == 0 || threshold.Less(t.Timestamps[n-1]) {
+			continue
+		}
+		gcTombstones = append(gcTombstones, roachpb.GCRequest_GCKey{
+			Key:       keys.MVCCRangeTombstoneKey(roachpb.RKey(t.StartKey)),
+			Timestamp: threshold,
+		})
+	}
+	if len(gcTombstones) > 0 {
+		if err := gcer.GC(ctx, gcTombstones); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
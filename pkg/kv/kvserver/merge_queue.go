@@ -201,7 +201,7 @@ func (mq *mergeQueue) requestRangeStats(
 		}
 		desc = &br.RangeInfos[0].Desc
 	}
-	return desc, res.MVCCStats, res.QueriesPerSecond, nil
+	return desc, res.MVCCStats, res.LoadPerSecond, nil
 }
 
 func (mq *mergeQueue) process(
@@ -221,8 +221,8 @@ func (mq *mergeQueue) process(
 	}
 
 	lhsDesc := lhsRepl.Desc()
-	lhsQPS := lhsRepl.GetSplitQPS()
-	rhsDesc, rhsStats, rhsQPS, err := mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
+	lhsLoad := lhsRepl.GetSplitLoad(ctx)
+	rhsDesc, rhsStats, rhsLoad, err := mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
 	if err != nil {
 		return false, err
 	}
@@ -248,23 +248,23 @@ func (mq *mergeQueue) process(
 	mergedStats := lhsStats
 	mergedStats.Add(rhsStats)
 
-	var mergedQPS float64
+	var mergedLoad float64
 	if lhsRepl.SplitByLoadEnabled() {
-		mergedQPS = lhsQPS + rhsQPS
+		mergedLoad = lhsLoad + rhsLoad
 	}
 
 	// Check if the merged range would need to be split, if so, skip merge.
 	// Use a lower threshold for load based splitting so we don't find ourselves
 	// in a situation where we keep merging ranges that would be split soon after
 	// by a small increase in load.
-	conservativeLoadBasedSplitThreshold := 0.5 * lhsRepl.SplitByLoadQPSThreshold()
+	conservativeLoadBasedSplitThreshold := 0.5 * lhsRepl.SplitByLoadThreshold()
 	shouldSplit, _ := shouldSplitRange(ctx, mergedDesc, mergedStats,
 		lhsRepl.GetMaxBytes(), lhsRepl.shouldBackpressureWrites(), sysCfg)
-	if shouldSplit || mergedQPS >= conservativeLoadBasedSplitThreshold {
+	if shouldSplit || mergedLoad >= conservativeLoadBasedSplitThreshold {
 		log.VEventf(ctx, 2,
 			"skipping merge to avoid thrashing: merged range %s may split "+
-				"(estimated size, estimated QPS: %d, %v)",
-			mergedDesc, mergedStats.Total(), mergedQPS)
+				"(estimated size, estimated load: %d, %v)",
+			mergedDesc, mergedStats.Total(), mergedLoad)
 		return false, nil
 	}
 
@@ -288,19 +288,27 @@ func (mq *mergeQueue) process(
 	}
 	lhsReplicas, rhsReplicas := lhsDesc.Replicas().All(), rhsDesc.Replicas().All()
 
-	// Defensive sanity check that everything is now a voter.
+	// Defensive sanity check that everything is now a voter or a non-voter.
 	for i := range lhsReplicas {
-		if lhsReplicas[i].GetType() != roachpb.VOTER_FULL {
+		if typ := lhsReplicas[i].GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
 			return false, errors.Errorf(`cannot merge non-voter replicas on lhs: %v`, lhsReplicas)
 		}
 	}
 	for i := range rhsReplicas {
-		if rhsReplicas[i].GetType() != roachpb.VOTER_FULL {
+		if typ := rhsReplicas[i].GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
 			return false, errors.Errorf(`cannot merge non-voter replicas on rhs: %v`, rhsReplicas)
 		}
 	}
 
 	if !replicaSetsEqual(lhsReplicas, rhsReplicas) {
+		// NB: AdminRelocateRange only knows how to move voters, so ranges with
+		// non-voting replicas can only be merged once the replicate queue has
+		// placed them on the same stores.
+		if len(lhsDesc.Replicas().NonVoters()) > 0 || len(rhsDesc.Replicas().NonVoters()) > 0 {
+			log.VEventf(ctx, 2, "skipping merge: non-voting replicas of %s and %s are not collocated",
+				lhsDesc, rhsDesc)
+			return false, nil
+		}
 		var targets []roachpb.ReplicationTarget
 		for _, lhsReplDesc := range lhsReplicas {
 			targets = append(targets, roachpb.ReplicationTarget{
@@ -326,13 +334,13 @@ func (mq *mergeQueue) process(
 	}
 
 	log.VEventf(ctx, 2, "merging to produce range: %s-%s", mergedDesc.StartKey, mergedDesc.EndKey)
-	reason := fmt.Sprintf("lhs+rhs has (size=%s+%s=%s qps=%.2f+%.2f=%.2fqps) below threshold (size=%s, qps=%.2f)",
+	reason := fmt.Sprintf("lhs+rhs has (size=%s+%s=%s load=%.2f+%.2f=%.2f) below threshold (size=%s, load=%.2f)",
 		humanizeutil.IBytes(lhsStats.Total()),
 		humanizeutil.IBytes(rhsStats.Total()),
 		humanizeutil.IBytes(mergedStats.Total()),
-		lhsQPS,
-		rhsQPS,
-		mergedQPS,
+		lhsLoad,
+		rhsLoad,
+		mergedLoad,
 		humanizeutil.IBytes(minBytes),
 		conservativeLoadBasedSplitThreshold,
 	)
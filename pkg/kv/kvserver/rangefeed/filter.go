@@ -11,7 +11,11 @@
 package rangefeed
 
 import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 )
 
@@ -53,3 +57,54 @@ func (r *Filter) NeedPrevVal(s roachpb.Span) bool {
 func (r *Filter) NeedVal(s roachpb.Span) bool {
 	return r.needVals.Overlaps(s.AsRange())
 }
+
+// EventFilter restricts the value events delivered to a registration to those
+// on keys that the registration is interested in. See the KeyPrefixes and
+// ColumnFamilies fields on roachpb.RangeFeedRequest for their semantics. The
+// zero value does not filter any events.
+type EventFilter struct {
+	KeyPrefixes    []roachpb.Key
+	ColumnFamilies []uint32
+}
+
+// MakeEventFilter returns the EventFilter requested by the RangeFeedRequest.
+func MakeEventFilter(args *roachpb.RangeFeedRequest) EventFilter {
+	return EventFilter{
+		KeyPrefixes:    args.KeyPrefixes,
+		ColumnFamilies: args.ColumnFamilies,
+	}
+}
+
+// matches returns whether value events on the given key pass the filter.
+func (f *EventFilter) matches(key roachpb.Key) bool {
+	if len(f.KeyPrefixes) > 0 {
+		found := false
+		for _, prefix := range f.KeyPrefixes {
+			if bytes.HasPrefix(key, prefix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.ColumnFamilies) > 0 {
+		n, err := keys.GetRowPrefixLength(key)
+		if err != nil || n == len(key) {
+			// Not a SQL row key, so there's no column family to filter on.
+			return true
+		}
+		_, famID, err := encoding.DecodeUvarintAscending(key[n:])
+		if err != nil {
+			return true
+		}
+		for _, id := range f.ColumnFamilies {
+			if uint64(id) == famID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/errors"
 )
@@ -74,6 +76,15 @@ type Config struct {
 	// all streams to make sure they have not been canceled.
 	CheckStreamsInterval time.Duration
 
+	// MemMonitor, if set, accounts for the memory used by the buffered events
+	// and catch-up scans of the Processor's registrations. A registration
+	// which cannot allocate memory for an event is disconnected as a slow
+	// consumer.
+	MemMonitor *mon.BytesMonitor
+	// CatchupScanLimiter, if set, paces the catch-up scans of the Processor's
+	// registrations by the number of bytes they output.
+	CatchupScanLimiter *quotapool.RateLimiter
+
 	// Metrics is for production monitoring of RangeFeeds.
 	Metrics *Metrics
 }
@@ -381,6 +392,9 @@ func (p *Processor) sendStop(pErr *roachpb.Error) {
 // The optionally provided "catch-up" iterator is used to read changes from the
 // engine which occurred after the provided start timestamp.
 //
+// Only value events on keys that pass the provided filter are delivered to the
+// registration.
+//
 // If the method returns false, the processor will have been stopped, so calling
 // Stop is not necessary. If the method returns true, it will also return an
 // updated operation filter that includes the operations required by the new
@@ -392,6 +406,7 @@ func (p *Processor) Register(
 	startTS hlc.Timestamp,
 	catchupIterConstructor IteratorConstructor,
 	withDiff bool,
+	filter EventFilter,
 	stream Stream,
 	errC chan<- *roachpb.Error,
 ) (bool, *Filter) {
@@ -401,9 +416,14 @@ func (p *Processor) Register(
 	p.syncEventC()
 
 	r := newRegistration(
-		span.AsRawSpanWithNoLocals(), startTS, catchupIterConstructor, withDiff,
+		span.AsRawSpanWithNoLocals(), startTS, catchupIterConstructor, withDiff, filter,
 		p.Config.EventChanCap, p.Metrics, stream, errC,
 	)
+	if p.MemMonitor != nil {
+		acc := p.MemMonitor.MakeBoundAccount()
+		r.mu.memAcc = &acc
+	}
+	r.catchupLimiter = p.CatchupScanLimiter
 	select {
 	case p.regC <- r:
 		// Wait for response.
@@ -202,6 +202,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		EventFilter{},
 		r1Stream,
 		r1ErrC,
 	)
@@ -326,6 +327,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,  /* catchUpIter */
 		true, /* withDiff */
+		EventFilter{},
 		r2Stream,
 		r2ErrC,
 	)
@@ -411,6 +413,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		EventFilter{},
 		r3Stream,
 		r3ErrC,
 	)
@@ -433,7 +436,7 @@ func TestNilProcessor(t *testing.T) {
 	// The following should panic because they are not safe
 	// to call on a nil Processor.
 	require.Panics(t, func() { p.Start(stop.NewStopper(), nil) })
-	require.Panics(t, func() { p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, false, nil, nil) })
+	require.Panics(t, func() { p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, false, EventFilter{}, nil, nil) })
 }
 
 func TestProcessorSlowConsumer(t *testing.T) {
@@ -449,6 +452,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		EventFilter{},
 		r1Stream,
 		r1ErrC,
 	)
@@ -459,6 +463,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		EventFilter{},
 		r2Stream,
 		r2ErrC,
 	)
@@ -578,6 +583,7 @@ func TestProcessorInitializeResolvedTimestamp(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		EventFilter{},
 		r1Stream,
 		make(chan *roachpb.Error, 1),
 	)
@@ -825,7 +831,7 @@ func TestProcessorConcurrentStop(t *testing.T) {
 			runtime.Gosched()
 			s := newTestStream()
 			errC := make(chan<- *roachpb.Error, 1)
-			p.Register(p.Span, hlc.Timestamp{}, nil, false, s, errC)
+			p.Register(p.Span, hlc.Timestamp{}, nil, false, EventFilter{}, s, errC)
 		}()
 		go func() {
 			defer wg.Done()
@@ -894,7 +900,7 @@ func TestProcessorRegistrationObservesOnlyNewEvents(t *testing.T) {
 			s := newTestStream()
 			regs[s] = firstIdx
 			errC := make(chan *roachpb.Error, 1)
-			p.Register(p.Span, hlc.Timestamp{}, nil, false, s, errC)
+			p.Register(p.Span, hlc.Timestamp{}, nil, false, EventFilter{}, s, errC)
 			regDone <- struct{}{}
 		}
 	}()
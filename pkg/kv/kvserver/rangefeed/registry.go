@@ -24,7 +24,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -57,7 +59,10 @@ type registration struct {
 	catchupTimestamp       hlc.Timestamp
 	catchupIterConstructor func() storage.SimpleIterator
 	withDiff               bool
+	filter                 EventFilter
 	metrics                *Metrics
+	// catchupLimiter, if set, paces the catch-up scan.
+	catchupLimiter *quotapool.RateLimiter
 
 	// Output.
 	stream Stream
@@ -70,6 +75,14 @@ type registration struct {
 
 	mu struct {
 		sync.Locker
+		// memAcc, if set, accounts for the memory used by the events in buf
+		// and by the catch-up scan. It is closed when the registration is
+		// disconnected.
+		memAcc *mon.BoundAccount
+		// budgetExhausted is set along with overflowed if the live event that
+		// was dropped didn't fit in the memory budget. It holds the size of
+		// that event.
+		budgetExhausted int64
 		// True if this registration buffer has overflowed, dropping a live event.
 		// This will cause the registration to exit with an error once the buffer
 		// has been emptied.
@@ -88,6 +101,7 @@ func newRegistration(
 	startTS hlc.Timestamp,
 	catchupIterConstructor func() storage.SimpleIterator,
 	withDiff bool,
+	filter EventFilter,
 	bufferSz int,
 	metrics *Metrics,
 	stream Stream,
@@ -98,6 +112,7 @@ func newRegistration(
 		catchupTimestamp:       startTS,
 		catchupIterConstructor: catchupIterConstructor,
 		withDiff:               withDiff,
+		filter:                 filter,
 		metrics:                metrics,
 		stream:                 stream,
 		errC:                   errC,
@@ -109,29 +124,101 @@ func newRegistration(
 }
 
 // publish attempts to send a single event to the output buffer for this
-// registration. If the output buffer is full, the overflowed flag is set,
-// indicating that live events were lost and a catchup scan should be initiated.
-// If overflowed is already set, events are ignored and not written to the
-// buffer.
+// registration. If the output buffer is full or the event does not fit in the
+// memory budget, the overflowed flag is set, indicating that live events were
+// lost and a catchup scan should be initiated. If overflowed is already set,
+// events are ignored and not written to the buffer. Value events that don't
+// pass the registration's filter are dropped.
 func (r *registration) publish(event *roachpb.RangeFeedEvent) {
 	r.validateEvent(event)
+	if val := event.Val; val != nil && !r.filter.matches(val.Key) {
+		return
+	}
 	event = r.maybeStripEvent(event)
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if r.mu.overflowed {
+	if r.mu.overflowed || r.mu.disconnected {
 		return
 	}
+	var size int64
+	if r.mu.memAcc != nil {
+		size = int64(event.Size())
+		if err := r.mu.memAcc.Grow(r.stream.Context(), size); err != nil {
+			// The node is out of rangefeed memory and we are dropping this
+			// event. Registration will need a catch-up scan.
+			r.metrics.RangeFeedBudgetExhausted.Inc(1)
+			r.mu.overflowed = true
+			r.mu.budgetExhausted = size
+			return
+		}
+	}
 	select {
 	case r.buf <- event:
 		r.mu.caughtUp = false
 	default:
 		// Buffer exceeded and we are dropping this event. Registration will need
 		// a catch-up scan.
+		if r.mu.memAcc != nil {
+			r.mu.memAcc.Shrink(r.stream.Context(), size)
+		}
 		r.mu.overflowed = true
 	}
 }
 
+// growMem accounts for n more bytes of memory used by the registration. It is
+// a no-op if the registration has no memory budget or has been disconnected.
+func (r *registration) growMem(ctx context.Context, n int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.memAcc == nil || r.mu.disconnected {
+		return nil
+	}
+	return r.mu.memAcc.Grow(ctx, n)
+}
+
+// budgetRetryOptions are the backoff options of registrations waiting for
+// other registrations to release memory from the memory budget. In total,
+// they wait for about 30s before giving up.
+var budgetRetryOptions = retry.Options{
+	InitialBackoff: 10 * time.Millisecond,
+	Multiplier:     2,
+	MaxBackoff:     time.Second,
+	MaxRetries:     35,
+}
+
+// waitForMem is like growMem, but if the memory budget is exhausted, it backs
+// off until other registrations release enough memory. It returns an error if
+// the context is canceled, or if the budget remains exhausted after
+// budgetRetryOptions.MaxRetries.
+func (r *registration) waitForMem(ctx context.Context, n int64) error {
+	err := r.growMem(ctx, n)
+	if err == nil {
+		return nil
+	}
+	r.metrics.RangeFeedBudgetExhausted.Inc(1)
+	for re := retry.StartWithCtx(ctx, budgetRetryOptions); re.Next(); {
+		if err = r.growMem(ctx, n); err == nil {
+			return nil
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// shrinkMem releases n bytes of memory previously accounted for by growMem or
+// publish.
+func (r *registration) shrinkMem(ctx context.Context, n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.memAcc == nil || r.mu.disconnected {
+		return
+	}
+	r.mu.memAcc.Shrink(ctx, n)
+}
+
 // validateEvent checks that the event contains enough information for the
 // registation.
 func (r *registration) validateEvent(event *roachpb.RangeFeedEvent) {
@@ -213,6 +300,9 @@ func (r *registration) disconnect(pErr *roachpb.Error) {
 		if r.mu.outputLoopCancelFn != nil {
 			r.mu.outputLoopCancelFn()
 		}
+		if r.mu.memAcc != nil {
+			r.mu.memAcc.Close(r.stream.Context())
+		}
 		r.mu.disconnected = true
 		r.errC <- pErr
 	}
@@ -232,7 +322,7 @@ func (r *registration) disconnect(pErr *roachpb.Error) {
 // have been emitted.
 func (r *registration) outputLoop(ctx context.Context) error {
 	// If the registration has a catch-up scan, run it.
-	if err := r.maybeRunCatchupScan(); err != nil {
+	if err := r.maybeRunCatchupScan(ctx); err != nil {
 		err = errors.Wrap(err, "catch-up scan failed")
 		log.Errorf(ctx, "%v", err)
 		return err
@@ -241,19 +331,34 @@ func (r *registration) outputLoop(ctx context.Context) error {
 	// Normal buffered output loop.
 	for {
 		overflowed := false
+		var budgetExhausted int64
 		r.mu.Lock()
 		if len(r.buf) == 0 {
 			overflowed = r.mu.overflowed
+			budgetExhausted = r.mu.budgetExhausted
 			r.mu.caughtUp = true
 		}
 		r.mu.Unlock()
 		if overflowed {
+			if budgetExhausted > 0 {
+				// The client reconnects as soon as the registration is disconnected.
+				// If the budget is still exhausted, its new registration would run
+				// a catch-up scan only to overflow again, so wait until the event we
+				// dropped would fit first.
+				if err := r.waitForMem(ctx, budgetExhausted); err == nil {
+					r.shrinkMem(ctx, budgetExhausted)
+				} else if ctxErr := ctx.Err(); ctxErr != nil {
+					return ctxErr
+				}
+			}
 			return newErrBufferCapacityExceeded().GoError()
 		}
 
 		select {
 		case nextEvent := <-r.buf:
-			if err := r.stream.Send(nextEvent); err != nil {
+			err := r.stream.Send(nextEvent)
+			r.shrinkMem(ctx, int64(nextEvent.Size()))
+			if err != nil {
 				return err
 			}
 		case <-ctx.Done():
@@ -277,9 +382,15 @@ func (r *registration) runOutputLoop(ctx context.Context) {
 // This uses the iterator provided when the registration was originally created;
 // after the scan completes, the iterator will be closed.
 //
+// The events for each key are buffered before being output, and are accounted
+// for in the registration's memory budget. If the budget is exhausted, the scan
+// waits for other registrations to release memory. If the registration has a
+// catch-up limiter, the scan is paced according to the number of bytes it
+// outputs.
+//
 // If the registration does not have a catchUpIteratorConstructor, this method
 // is a no-op.
-func (r *registration) maybeRunCatchupScan() error {
+func (r *registration) maybeRunCatchupScan(ctx context.Context) error {
 	if r.catchupIterConstructor == nil {
 		return nil
 	}
@@ -301,6 +412,7 @@ func (r *registration) maybeRunCatchupScan() error {
 	// the encountered values in reverse. This also allows us to buffer events
 	// as we fill in previous values.
 	var lastKey roachpb.Key
+	var reorderBufBytes int64
 	reorderBuf := make([]roachpb.RangeFeedEvent, 0, 5)
 	addPrevToLastEvent := func(val []byte) {
 		if l := len(reorderBuf); l > 0 {
@@ -318,8 +430,22 @@ func (r *registration) maybeRunCatchupScan() error {
 			}
 		}
 		reorderBuf = reorderBuf[:0]
+		if reorderBufBytes == 0 {
+			return nil
+		}
+		r.shrinkMem(ctx, reorderBufBytes)
+		n := reorderBufBytes
+		reorderBufBytes = 0
+		if r.catchupLimiter != nil {
+			return r.catchupLimiter.WaitN(ctx, n)
+		}
 		return nil
 	}
+	defer func() {
+		if reorderBufBytes > 0 {
+			r.shrinkMem(ctx, reorderBufBytes)
+		}
+	}()
 
 	// Iterate though all keys using Next. We want to publish all committed
 	// versions of each key that are after the registration's startTS, so we
@@ -371,6 +497,12 @@ func (r *registration) maybeRunCatchupScan() error {
 		key := lastKey
 		ts := unsafeKey.Timestamp
 
+		// Skip keys that the registration is not interested in.
+		if !sameKey && !r.filter.matches(key) {
+			catchupIter.NextKey()
+			continue
+		}
+
 		// Ignore the version if it's not inline and its timestamp is at
 		// or before the registration's (exclusive) starting timestamp.
 		ignore := !(ts.IsEmpty() || r.catchupTimestamp.Less(ts))
@@ -383,6 +515,13 @@ func (r *registration) maybeRunCatchupScan() error {
 
 		var val []byte
 		a, val = a.Copy(unsafeVal, 0)
+		if err := r.waitForMem(ctx, int64(len(val))); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return newErrBufferCapacityExceeded().GoError()
+		}
+		reorderBufBytes += int64(len(val))
 		if r.withDiff {
 			// Update the last version with its previous value (this version).
 			addPrevToLastEvent(val)
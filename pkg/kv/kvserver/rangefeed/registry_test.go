@@ -13,13 +13,18 @@ package rangefeed
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
-	_ "github.com/cockroachdb/cockroach/pkg/keys" // hook up pretty printer
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/stretchr/testify/require"
@@ -106,6 +111,7 @@ func newTestRegistration(
 			ts,
 			makeIteratorConstructor(catchup),
 			withDiff,
+			EventFilter{},
 			5,
 			NewMetrics(),
 			s,
@@ -253,7 +259,7 @@ func TestRegistrationCatchUpScan(t *testing.T) {
 	}, hlc.Timestamp{WallTime: 4}, iter, true /* withDiff */)
 
 	require.Zero(t, r.metrics.RangeFeedCatchupScanNanos.Count())
-	require.NoError(t, r.maybeRunCatchupScan())
+	require.NoError(t, r.maybeRunCatchupScan(context.Background()))
 	require.True(t, iter.closed)
 	require.NotZero(t, r.metrics.RangeFeedCatchupScanNanos.Count())
 
@@ -310,6 +316,129 @@ func TestRegistrationCatchUpScan(t *testing.T) {
 	require.Equal(t, expEvents, r.Events())
 }
 
+func TestRegistrationEventFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	val := roachpb.Value{RawBytes: []byte("val"), Timestamp: hlc.Timestamp{WallTime: 13}}
+	ev1, ev2 := new(roachpb.RangeFeedEvent), new(roachpb.RangeFeedEvent)
+	ev1.MustSetValue(&roachpb.RangeFeedValue{Key: keyA, Value: val})
+	ev2.MustSetValue(&roachpb.RangeFeedValue{Key: keyB, Value: val})
+
+	// Only keys prefixed by "ab" or "b" pass the filter.
+	r := newTestRegistration(spAC, hlc.Timestamp{WallTime: 1}, newTestIterator([]storage.MVCCKeyValue{
+		makeKV("a", "valA", 10),
+		makeKV("ab", "valAB", 11),
+		makeKV("b", "valB", 12),
+	}), false)
+	r.filter = EventFilter{KeyPrefixes: []roachpb.Key{roachpb.Key("ab"), keyB}}
+	r.publish(ev1)
+	r.publish(ev2)
+	require.Equal(t, 1, len(r.buf))
+	go r.runOutputLoop(context.Background())
+	require.NoError(t, r.waitForCaughtUp())
+	expEvents := []*roachpb.RangeFeedEvent{
+		rangeFeedValue(
+			roachpb.Key("ab"),
+			roachpb.Value{RawBytes: []byte("valAB"), Timestamp: hlc.Timestamp{WallTime: 11}},
+		),
+		rangeFeedValue(
+			keyB,
+			roachpb.Value{RawBytes: []byte("valB"), Timestamp: hlc.Timestamp{WallTime: 12}},
+		),
+		ev2,
+	}
+	require.Equal(t, expEvents, r.Events())
+	r.disconnect(nil)
+	<-r.errC
+}
+
+func TestEventFilterColumnFamilies(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	row := encoding.EncodeVarintAscending(keys.SystemSQLCodec.IndexPrefix(50, 1), 7)
+	famKey := func(famID uint32) roachpb.Key {
+		return keys.MakeFamilyKey(append([]byte(nil), row...), famID)
+	}
+	f := EventFilter{ColumnFamilies: []uint32{0, 2}}
+	require.True(t, f.matches(famKey(0)))
+	require.False(t, f.matches(famKey(1)))
+	require.True(t, f.matches(famKey(2)))
+	require.False(t, f.matches(famKey(300)))
+	// Keys that aren't SQL row keys are not filtered.
+	require.True(t, f.matches(keyA))
+
+	f = EventFilter{KeyPrefixes: []roachpb.Key{keys.SystemSQLCodec.TablePrefix(51)}, ColumnFamilies: []uint32{1}}
+	require.False(t, f.matches(famKey(1)))
+	require.True(t, (&EventFilter{}).matches(famKey(1)))
+}
+
+func TestRegistrationMemoryBudget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	val := roachpb.Value{RawBytes: []byte("val"), Timestamp: hlc.Timestamp{WallTime: 1}}
+	ev := new(roachpb.RangeFeedEvent)
+	ev.MustSetValue(&roachpb.RangeFeedValue{Key: keyA, Value: val})
+
+	// The budget fits two events, so the third overflows the registration
+	// even though its buffer has room to spare.
+	st := cluster.MakeTestingClusterSettings()
+	m := mon.NewMonitor("rangefeed", mon.MemoryResource, nil, nil, 1, math.MaxInt64, st)
+	m.Start(ctx, nil, mon.MakeStandaloneBudget(int64(2*ev.Size())))
+	defer m.Stop(ctx)
+
+	r := newTestRegistration(spAB, hlc.Timestamp{}, nil, false)
+	acc := m.MakeBoundAccount()
+	r.mu.memAcc = &acc
+	for i := 0; i < 3; i++ {
+		r.publish(ev)
+	}
+	require.Equal(t, 2, len(r.buf))
+	require.Equal(t, int64(1), r.metrics.RangeFeedBudgetExhausted.Count())
+	go r.runOutputLoop(ctx)
+	require.Equal(t, newErrBufferCapacityExceeded(), <-r.errC)
+	require.Equal(t, 2, len(r.Events()))
+	require.Zero(t, m.AllocBytes())
+}
+
+func TestRegistrationWaitForMem(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	st := cluster.MakeTestingClusterSettings()
+	m := mon.NewMonitor("rangefeed", mon.MemoryResource, nil, nil, 1, math.MaxInt64, st)
+	m.Start(ctx, nil, mon.MakeStandaloneBudget(10))
+	defer m.Stop(ctx)
+
+	// Another registration holds the entire budget.
+	other := m.MakeBoundAccount()
+	require.NoError(t, other.Grow(ctx, 10))
+
+	r := newTestRegistration(spAB, hlc.Timestamp{}, nil, false)
+	acc := m.MakeBoundAccount()
+	r.mu.memAcc = &acc
+
+	// A registration whose context is canceled stops waiting.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Equal(t, context.Canceled, r.waitForMem(cancelCtx, 5))
+	require.Equal(t, int64(1), r.metrics.RangeFeedBudgetExhausted.Count())
+
+	// Otherwise, it waits until the other registration releases its memory.
+	errC := make(chan error, 1)
+	go func() { errC <- r.waitForMem(ctx, 5) }()
+	select {
+	case err := <-errC:
+		t.Fatalf("unexpectedly done waiting for memory: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	other.Shrink(ctx, 10)
+	require.NoError(t, <-errC)
+	require.Equal(t, int64(5), m.AllocBytes())
+	r.shrinkMem(ctx, 5)
+	require.Zero(t, m.AllocBytes())
+}
+
 func TestRegistryBasic(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -46,6 +46,7 @@ func MakeAllKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 	return []KeyRange{
 		MakeRangeIDLocalKeyRange(d.RangeID, false /* replicatedOnly */),
 		MakeRangeLocalKeyRange(d),
+		MakeMVCCRangeTombstoneKeyRange(d),
 		MakeUserKeyRange(d),
 	}
 }
@@ -58,11 +59,13 @@ func MakeAllKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 //
 // 1. Replicated range-id local key range
 // 2. Range-local key range
-// 3. User key range
+// 3. MVCC range tombstone key range
+// 4. User key range
 func MakeReplicatedKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 	return []KeyRange{
 		MakeRangeIDLocalKeyRange(d.RangeID, true /* replicatedOnly */),
 		MakeRangeLocalKeyRange(d),
+		MakeMVCCRangeTombstoneKeyRange(d),
 		MakeUserKeyRange(d),
 	}
 }
@@ -95,6 +98,15 @@ func MakeRangeLocalKeyRange(d *roachpb.RangeDescriptor) KeyRange {
 	}
 }
 
+// MakeMVCCRangeTombstoneKeyRange returns the key range of the MVCC range
+// tombstone fragments starting within the range.
+func MakeMVCCRangeTombstoneKeyRange(d *roachpb.RangeDescriptor) KeyRange {
+	return KeyRange{
+		Start: storage.MakeMVCCMetadataKey(keys.MVCCRangeTombstoneKey(d.StartKey)),
+		End:   storage.MakeMVCCMetadataKey(keys.MVCCRangeTombstoneKey(d.EndKey)),
+	}
+}
+
 // MakeUserKeyRange returns the user key range.
 func MakeUserKeyRange(d *roachpb.RangeDescriptor) KeyRange {
 	// The first range in the keyspace starts at KeyMin, which includes the
@@ -204,6 +204,14 @@ type Replica struct {
 	// writeStats tracks the number of keys written by applied raft commands
 	// in order to aid in replica rebalancing decisions.
 	writeStats *replicaStats
+	// cpuStats tracks the time, in nanoseconds, spent evaluating requests on
+	// the replica, which approximates the CPU time they use, in order to aid in
+	// lease and replica rebalancing decisions.
+	cpuStats *replicaStats
+	// writeBytesStats tracks the number of bytes written by applied raft
+	// commands, including ingested SSTables, in order to aid in replica
+	// rebalancing decisions.
+	writeBytesStats *replicaStats
 
 	// creatingReplica is set when a replica is created as uninitialized
 	// via a raft message.
@@ -565,6 +573,9 @@ type Replica struct {
 
 	// loadBasedSplitter keeps information about load-based splitting.
 	loadBasedSplitter split.Decider
+	// loadBasedSplitterDimension is the LBRebalancingDimension of the load
+	// recorded by loadBasedSplitter. Accessed atomically.
+	loadBasedSplitterDimension int64
 
 	unreachablesMu struct {
 		syncutil.Mutex
@@ -762,6 +773,15 @@ func (r *Replica) Engine() storage.Engine {
 	return r.store.Engine()
 }
 
+// RaftEngine returns the engine holding the Replica's Raft log if the store
+// keeps it apart from the Replica's other data, and nil otherwise.
+func (r *Replica) RaftEngine() storage.Engine {
+	if !r.store.raftLogSeparated() {
+		return nil
+	}
+	return r.store.RaftEngine()
+}
+
 // AbortSpan returns the Replica's AbortSpan.
 func (r *Replica) AbortSpan() *abortspan.AbortSpan {
 	// Despite its name, the AbortSpan doesn't hold on-disk data in
@@ -931,13 +951,15 @@ func (r *Replica) GetMVCCStats() enginepb.MVCCStats {
 	return *r.mu.state.Stats
 }
 
-// GetSplitQPS returns the Replica's queries/s request rate.
+// GetSplitLoad returns the Replica's load per second along the configured
+// LBRebalancingDimension, as measured for load based splitting.
 //
 // NOTE: This should only be used for load based splitting, only
 // works when the load based splitting cluster setting is enabled.
 //
 // Use QueriesPerSecond() for current QPS stats for all other purposes.
-func (r *Replica) GetSplitQPS() float64 {
+func (r *Replica) GetSplitLoad(ctx context.Context) float64 {
+	r.maybeResetLoadBasedSplitter(loadDimension(ctx, r.store.cfg.Settings))
 	return r.loadBasedSplitter.LastQPS(timeutil.Now())
 }
 
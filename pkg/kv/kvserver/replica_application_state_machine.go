@@ -344,6 +344,8 @@ func (sm *replicaStateMachine) NewBatch(ephemeral bool) apply.Batch {
 	b.r = r
 	b.sm = sm
 	b.batch = r.store.engine.NewBatch()
+	b.mustSync = false
+	b.raftLogTruncation = nil
 	r.mu.RLock()
 	b.state = r.mu.state
 	b.state.Stats = &b.stats
@@ -385,11 +387,21 @@ type replicaAppBatch struct {
 	// changeRemovesReplica tracks whether the command in the batch (there must
 	// be only one) removes this replica from the range.
 	changeRemovesReplica bool
+	// mustSync tracks whether the batch must be committed synchronously. If the
+	// store's Raft log is kept in a separate engine, this is the case for
+	// batches whose effects on the Raft engine follow their commit: splits,
+	// merges and log truncations.
+	mustSync bool
+	// raftLogTruncation, if set, is the truncated state up to which the Raft
+	// log is removed from the store's separate Raft engine once the batch has
+	// been committed.
+	raftLogTruncation *roachpb.RaftTruncatedState
 
 	// Statistics.
 	entries      int
 	emptyEntries int
 	mutations    int
+	writeBytes   int64
 	start        time.Time
 }
 
@@ -527,6 +539,7 @@ func (b *replicaAppBatch) stageWriteBatch(ctx context.Context, cmd *replicatedCm
 	} else {
 		b.mutations += mutations
 	}
+	b.writeBytes += int64(len(wb.Data))
 	if err := b.batch.ApplyBatchRepr(wb.Data, false); err != nil {
 		return wrapWithNonDeterministicFailure(err, "unable to apply WriteBatch")
 	}
@@ -607,6 +620,7 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		if added := res.Delta.KeyCount; added > 0 {
 			b.r.writeStats.recordCount(float64(added), 0)
 		}
+		b.r.writeBytesStats.recordCount(float64(len(res.AddSSTable.Data)), 0)
 		res.AddSSTable = nil
 	}
 
@@ -621,6 +635,9 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		// Alternatively if we discover that the RHS has already been removed
 		// from this store, clean up its data.
 		splitPreApply(ctx, b.batch, res.Split.SplitTrigger, b.r)
+		// The new HardState of the RHS is handed off to the Raft engine when
+		// the RHS is initialized after the batch commits.
+		b.mustSync = b.mustSync || b.r.store.raftLogSeparated()
 
 		// The rangefeed processor will no longer be provided logical ops for
 		// its entire range, so it needs to be shut down and all registrations
@@ -673,6 +690,9 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to destroy replica before merge")
 		}
+		// The Raft state of the RHS is removed from the Raft engine by
+		// postDestroyRaftMuLocked after the batch commits.
+		b.mustSync = b.mustSync || b.r.store.raftLogSeparated()
 
 		// Shut down rangefeed processors on either side of the merge.
 		//
@@ -699,10 +719,20 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 	}
 
 	if res.State != nil && res.State.TruncatedState != nil {
+		// If the Raft log is separated, the truncated log entries are removed
+		// from the Raft engine only once the new truncated state is durable.
+		raftLogWriter := storage.Writer(b.batch)
+		if b.r.store.raftLogSeparated() {
+			raftLogWriter = nil
+		}
 		if apply, err := handleTruncatedStateBelowRaft(
 			ctx, b.state.TruncatedState, res.State.TruncatedState, b.r.raftMu.stateLoader, b.batch,
+			raftLogWriter,
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to handle truncated state")
+		} else if apply && raftLogWriter == nil {
+			b.mustSync = true
+			b.raftLogTruncation = res.State.TruncatedState
 		} else if !apply {
 			// The truncated state was discarded, so make sure we don't apply
 			// it to our in-memory state.
@@ -872,13 +902,19 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	// applied again upon startup. However, if we're removing the replica's data
 	// then we sync this batch as it is not safe to call postDestroyRaftMuLocked
 	// before ensuring that the replica's data has been synchronously removed.
-	// See handleChangeReplicasResult().
-	sync := b.changeRemovesReplica
+	// See handleChangeReplicasResult(). Batches whose effects on a separate
+	// Raft engine follow their commit are synced as well.
+	sync := b.changeRemovesReplica || b.mustSync
 	if err := b.batch.Commit(sync); err != nil {
 		return wrapWithNonDeterministicFailure(err, "unable to commit Raft entry batch")
 	}
 	b.batch.Close()
 	b.batch = nil
+	if b.raftLogTruncation != nil {
+		if err := r.truncateRaftEngineLogRaftMuLocked(b.raftLogTruncation); err != nil {
+			return wrapWithNonDeterministicFailure(err, "unable to truncate Raft log")
+		}
+	}
 
 	// Update the replica's applied indexes and mvcc stats.
 	r.mu.Lock()
@@ -908,6 +944,7 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	// Record the write activity, passing a 0 nodeID because replica.writeStats
 	// intentionally doesn't track the origin of the writes.
 	b.r.writeStats.recordCount(float64(b.mutations), 0 /* nodeID */)
+	b.r.writeBytesStats.recordCount(float64(b.writeBytes), 0 /* nodeID */)
 
 	now := timeutil.Now()
 	if needsSplitBySize && r.splitQueueThrottle.ShouldProcess(now) {
@@ -635,9 +635,11 @@ func (r *Replica) AdminMerge(
 		}
 		// For simplicity, don't handle learner replicas or joint states, expect
 		// the caller to resolve them first. (Defensively, we check that there
-		// are no non-voter replicas, in case some third type is later added).
-		// This behavior can be changed later if the complexity becomes worth
-		// it, but it's not right now.
+		// are no replicas other than full voters and non-voters, in case some
+		// other type is later added). This behavior can be changed later if the
+		// complexity becomes worth it, but it's not right now. Non-voters are
+		// merged like voters, as long as both sides have them on the same
+		// stores.
 		//
 		// NB: the merge queue transitions out of any joint states and removes
 		// any learners it sees. It's sort of silly that we don't do that here
@@ -646,16 +648,18 @@ func (r *Replica) AdminMerge(
 		// queues should fix things up quickly).
 		lReplicas, rReplicas := origLeftDesc.Replicas(), rightDesc.Replicas()
 
-		predFullVoter := func(rDesc roachpb.ReplicaDescriptor) bool {
-			return rDesc.GetType() == roachpb.VOTER_FULL
+		predFullVoterOrNonVoter := func(rDesc roachpb.ReplicaDescriptor) bool {
+			typ := rDesc.GetType()
+			return typ == roachpb.VOTER_FULL || typ == roachpb.NON_VOTER
 		}
-		if len(lReplicas.Filter(predFullVoter)) != len(lReplicas.All()) {
+		if len(lReplicas.Filter(predFullVoterOrNonVoter)) != len(lReplicas.All()) {
 			return errors.Errorf("cannot merge range with non-voter replicas on lhs: %s", lReplicas)
 		}
-		if len(rReplicas.Filter(predFullVoter)) != len(rReplicas.All()) {
+		if len(rReplicas.Filter(predFullVoterOrNonVoter)) != len(rReplicas.All()) {
 			return errors.Errorf("cannot merge range with non-voter replicas on rhs: %s", rReplicas)
 		}
-		if !replicaSetsEqual(lReplicas.All(), rReplicas.All()) {
+		if !replicaSetsEqual(lReplicas.Voters(), rReplicas.Voters()) ||
+			!replicaSetsEqual(lReplicas.NonVoters(), rReplicas.NonVoters()) {
 			return errors.Errorf("ranges not collocated; %s != %s", lReplicas, rReplicas)
 		}
 		mergeReplicas := lReplicas.All()
@@ -967,13 +971,20 @@ func (r *Replica) ChangeReplicas(
 		!UseAtomicReplicationChanges.Get(&st.SV)
 
 	if unroll {
-		// Legacy behavior.
-		for i := range chgs {
+		// Legacy behavior. Promotions and demotions of non-voters consist of two
+		// adjacent changes to the same store which can't be carried out
+		// individually.
+		for len(chgs) > 0 {
+			n := 1
+			if len(chgs) > 1 && chgs[0].Target == chgs[1].Target {
+				n = 2
+			}
 			var err error
-			desc, err = r.changeReplicasImpl(ctx, desc, priority, reason, details, chgs[i:i+1])
+			desc, err = r.changeReplicasImpl(ctx, desc, priority, reason, details, chgs[:n])
 			if err != nil {
 				return nil, err
 			}
+			chgs = chgs[n:]
 		}
 		return desc, nil
 	}
@@ -1001,8 +1012,9 @@ func (r *Replica) changeReplicasImpl(
 	if err := validateReplicationChanges(desc, chgs); err != nil {
 		return nil, err
 	}
+	_, _, rest := splitPromotionsAndDemotions(chgs)
 
-	if adds := chgs.Additions(); len(adds) > 0 {
+	if adds := rest.Additions(); len(adds) > 0 {
 		// Lock learner snapshots even before we run the ConfChange txn to add them
 		// to prevent a race with the raft snapshot queue trying to send it first.
 		// Note that this lock needs to cover sending the snapshots which happens in
@@ -1028,6 +1040,15 @@ func (r *Replica) changeReplicasImpl(
 		}
 	}
 
+	if adds := rest.NonVoterAdditions(); len(adds) > 0 {
+		// Non-voters are added directly and caught up with a snapshot. Since
+		// they don't vote, there's nothing left to do for them afterwards.
+		desc, err = r.addNonVoters(ctx, desc, priority, reason, details, adds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Catch up any learners, then run the atomic replication change that adds the
 	// final voters and removes any undesirable replicas.
 	desc, err = r.atomicReplicationChange(ctx, desc, priority, reason, details, chgs)
@@ -1042,7 +1063,7 @@ func (r *Replica) changeReplicasImpl(
 		}
 		// Don't leave a learner replica lying around if we didn't succeed in
 		// promoting it to a voter.
-		if targets := chgs.Additions(); len(targets) > 0 {
+		if targets := rest.Additions(); len(targets) > 0 {
 			log.Infof(ctx, "could not promote %v to voter, rolling back: %v", targets, err)
 			for _, target := range targets {
 				r.tryRollBackLearnerReplica(ctx, r.Desc(), target, reason, details)
@@ -1081,7 +1102,7 @@ func maybeLeaveAtomicChangeReplicas(
 
 // maybeLeaveAtomicChangeReplicasAndRemoveLearners transitions out of the joint
 // config (if there is one), and then removes all learners. After this function
-// returns, all remaining replicas will be of type VOTER_FULL.
+// returns, all remaining replicas will be of type VOTER_FULL or NON_VOTER.
 func maybeLeaveAtomicChangeReplicasAndRemoveLearners(
 	ctx context.Context, store *Store, desc *roachpb.RangeDescriptor,
 ) (*roachpb.RangeDescriptor, error) {
@@ -1120,9 +1141,65 @@ func maybeLeaveAtomicChangeReplicasAndRemoveLearners(
 	return desc, nil
 }
 
+// splitPromotionsAndDemotions returns the targets of the non-voter promotions
+// (an ADD_REPLICA paired with a REMOVE_NON_VOTER on the same store) and of the
+// voter demotions (a REMOVE_REPLICA paired with an ADD_NON_VOTER on the same
+// store) in the given changes, as well as the remaining changes.
+func splitPromotionsAndDemotions(
+	chgs roachpb.ReplicationChanges,
+) (promotions, demotions []roachpb.ReplicationTarget, rest roachpb.ReplicationChanges) {
+	paired := func(chg roachpb.ReplicationChange, typ roachpb.ReplicaChangeType) bool {
+		for _, other := range chgs {
+			if other.ChangeType == typ && other.Target == chg.Target {
+				return true
+			}
+		}
+		return false
+	}
+	for _, chg := range chgs {
+		switch {
+		case chg.ChangeType == roachpb.ADD_REPLICA && paired(chg, roachpb.REMOVE_NON_VOTER):
+			promotions = append(promotions, chg.Target)
+		case chg.ChangeType == roachpb.REMOVE_REPLICA && paired(chg, roachpb.ADD_NON_VOTER):
+			demotions = append(demotions, chg.Target)
+		case chg.ChangeType == roachpb.REMOVE_NON_VOTER && paired(chg, roachpb.ADD_REPLICA),
+			chg.ChangeType == roachpb.ADD_NON_VOTER && paired(chg, roachpb.REMOVE_REPLICA):
+			// The other half of a promotion or demotion.
+		default:
+			rest = append(rest, chg)
+		}
+	}
+	return promotions, demotions, rest
+}
+
 func validateReplicationChanges(
 	desc *roachpb.RangeDescriptor, chgs roachpb.ReplicationChanges,
 ) error {
+	// Promotions and demotions are expressed as two changes to the same store,
+	// so check them separately from the remaining changes.
+	promotions, demotions, chgs := splitPromotionsAndDemotions(chgs)
+	for _, target := range promotions {
+		rDesc, ok := desc.GetReplicaDescriptor(target.StoreID)
+		if !ok || rDesc.NodeID != target.NodeID || rDesc.GetType() != roachpb.NON_VOTER {
+			return errors.Errorf("unable to promote %v which is not a non-voter in %s", target, desc)
+		}
+	}
+	for _, target := range demotions {
+		rDesc, ok := desc.GetReplicaDescriptor(target.StoreID)
+		if !ok || rDesc.NodeID != target.NodeID || rDesc.GetType() != roachpb.VOTER_FULL {
+			return errors.Errorf("unable to demote %v which is not a voter in %s", target, desc)
+		}
+	}
+	for _, chg := range chgs {
+		for _, targets := range [][]roachpb.ReplicationTarget{promotions, demotions} {
+			for _, target := range targets {
+				if chg.Target.NodeID == target.NodeID {
+					return errors.Errorf("changes %+v refer to n%d twice", chgs, target.NodeID)
+				}
+			}
+		}
+	}
+
 	// First make sure that the changes don't self-overlap (i.e. we're not adding
 	// a replica twice, or removing and immediately re-adding it).
 	byNodeAndStoreID := make(map[roachpb.NodeID]map[roachpb.StoreID]roachpb.ReplicationChange, len(chgs))
@@ -1134,11 +1211,11 @@ func validateReplicationChanges(
 		} else {
 			// The only operation that is allowed within a node is an Add/Remove.
 			for _, prevChg := range byStoreID {
-				if prevChg.ChangeType == chg.ChangeType {
+				if prevChg.ChangeType.IsAddition() == chg.ChangeType.IsAddition() {
 					return fmt.Errorf("changes %+v refer to n%d twice for change %v",
 						chgs, chg.Target.NodeID, chg.ChangeType)
 				}
-				if prevChg.ChangeType != roachpb.ADD_REPLICA {
+				if !prevChg.ChangeType.IsAddition() {
 					return fmt.Errorf("can only add-remove a replica within a node, but got %+v", chgs)
 				}
 			}
@@ -1163,7 +1240,7 @@ func validateReplicationChanges(
 			chg, k := byStoreID[rDesc.StoreID]
 			// We should be removing the replica from the existing store during a
 			// rebalance within the node.
-			if !k || chg.ChangeType != roachpb.REMOVE_REPLICA {
+			if !k || !chg.ChangeType.IsRemoval() {
 				return errors.Errorf(
 					"Expected replica to be removed from %v during a lateral rebalance %v within the node.", rDesc, chgs)
 			}
@@ -1175,7 +1252,12 @@ func validateReplicationChanges(
 		// (2) add on the node, when we only have one replica.
 		// See https://github.com/cockroachdb/cockroach/issues/40333.
 		if ok {
-			if chg.ChangeType == roachpb.REMOVE_REPLICA {
+			if chg.ChangeType.IsRemoval() {
+				// Non-voters have to be removed as such, and vice versa.
+				if isNonVoter := rDesc.GetType() == roachpb.NON_VOTER; isNonVoter != (chg.ChangeType == roachpb.REMOVE_NON_VOTER) {
+					return errors.Errorf("unable to %s %v which is a %s in %s",
+						chg.ChangeType, chg.Target, rDesc.GetType(), desc)
+				}
 				continue
 			}
 			// Looks like we found a replica with the same store and node id. If the
@@ -1187,6 +1269,12 @@ func validateReplicationChanges(
 				return errors.Errorf(
 					"unable to add replica %v which is already present as a learner in %s", chg.Target, desc)
 			}
+			// A non-voter can't be added again, and a voter can only be added on
+			// its store through a promotion.
+			if rDesc.GetType() == roachpb.NON_VOTER {
+				return errors.Errorf(
+					"unable to add replica %v which is already present as a non-voter in %s", chg.Target, desc)
+			}
 
 			// Otherwise, we already had a full voter replica. Can't add another to
 			// this store.
@@ -1196,7 +1284,7 @@ func validateReplicationChanges(
 		for _, c := range byStoreID {
 			// We're adding a replica that's already there. This isn't allowed, even
 			// when the newly added one would be on a different store.
-			if c.ChangeType == roachpb.ADD_REPLICA {
+			if c.ChangeType.IsAddition() {
 				if len(desc.Replicas().All()) > 1 {
 					return errors.Errorf("unable to add replica %v; node already has a replica in %s", c.Target.StoreID, desc)
 				}
@@ -1209,7 +1297,7 @@ func validateReplicationChanges(
 	// Any removals left in the map now refer to nonexisting replicas, and we refuse them.
 	for _, byStoreID := range byNodeAndStoreID {
 		for _, chg := range byStoreID {
-			if chg.ChangeType != roachpb.REMOVE_REPLICA {
+			if !chg.ChangeType.IsRemoval() {
 				continue
 			}
 			return errors.Errorf("removing %v which is not in %s", chg.Target, desc)
@@ -1246,6 +1334,48 @@ func addLearnerReplicas(
 	return desc, nil
 }
 
+// addNonVoters adds non-voting replicas to the given replication targets, one
+// at a time, and sends each of them a snapshot. Non-voters are raft learners,
+// so they don't affect quorum while they're being caught up. If a non-voter
+// can't be caught up, it's rolled back.
+func (r *Replica) addNonVoters(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	priority SnapshotRequest_Priority,
+	reason kvserverpb.RangeLogEventReason,
+	details string,
+	targets []roachpb.ReplicationTarget,
+) (*roachpb.RangeDescriptor, error) {
+	// As for learners, keep the raft snapshot queue from sending a snapshot of
+	// its own while we send ours.
+	releaseSnapshotLockFn := r.lockLearnerSnapshot(ctx, targets)
+	defer releaseSnapshotLockFn()
+
+	for _, target := range targets {
+		iChgs := []internalReplicationChange{{target: target, typ: internalChangeTypeAddNonVoter}}
+		var err error
+		desc, err = execChangeReplicasTxn(ctx, r.store, desc, reason, details, iChgs)
+		if err != nil {
+			return nil, err
+		}
+		if fn := r.store.cfg.TestingKnobs.ReplicaSkipLearnerSnapshot; fn != nil && fn() {
+			continue
+		}
+		rDesc, ok := desc.GetReplicaDescriptor(target.StoreID)
+		if !ok {
+			return nil, errors.Errorf("programming error: replica %v not found in %v", target, desc)
+		}
+		if err := r.sendSnapshot(ctx, rDesc, SnapshotRequest_LEARNER, priority); err != nil {
+			if fn := r.store.cfg.TestingKnobs.ReplicaAddSkipLearnerRollback; fn == nil || !fn() {
+				log.Infof(ctx, "could not catch up non-voter %v, rolling back: %v", target, err)
+				r.tryRollBackLearnerReplica(ctx, desc, target, reason, details)
+			}
+			return nil, err
+		}
+	}
+	return desc, nil
+}
+
 // lockLearnerSnapshot stops the raft snapshot queue from sending snapshots to
 // the soon-to-be added learner replicas to prevent duplicate snapshots from
 // being sent. This lock is best effort because it times out and it is a node
@@ -1309,8 +1439,11 @@ func (r *Replica) atomicReplicationChange(
 	// both sides.
 
 	iChgs := make([]internalReplicationChange, 0, len(chgs))
+	// Non-voters have been added by the caller already, see
+	// changeReplicasImpl.
+	promotions, demotions, rest := splitPromotionsAndDemotions(chgs)
 
-	for _, target := range chgs.Additions() {
+	for _, target := range rest.Additions() {
 		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypePromoteLearner})
 		// All adds must be present as learners right now, and we send them
 		// snapshots in anticipation of promoting them to voters.
@@ -1347,14 +1480,23 @@ func (r *Replica) atomicReplicationChange(
 		}
 	}
 
-	if adds := chgs.Additions(); len(adds) > 0 {
+	if adds := rest.Additions(); len(adds) > 0 {
 		if fn := r.store.cfg.TestingKnobs.ReplicaAddStopAfterLearnerSnapshot; fn != nil && fn(adds) {
 			return desc, nil
 		}
 	}
 
+	// Non-voters are promoted and demoted in place; they already have (or keep)
+	// all of the range's data.
+	for _, target := range promotions {
+		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypePromoteNonVoter})
+	}
+	for _, target := range demotions {
+		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypeDemoteVoterToNonVoter})
+	}
+
 	canUseDemotion := r.store.ClusterSettings().Version.IsActive(ctx, clusterversion.VersionChangeReplicasDemotion)
-	for _, target := range chgs.Removals() {
+	for _, target := range rest.Removals() {
 		typ := internalChangeTypeRemove
 		if rDesc, ok := desc.GetReplicaDescriptor(target.StoreID); ok && rDesc.GetType() == roachpb.VOTER_FULL && canUseDemotion {
 			typ = internalChangeTypeDemote
@@ -1362,6 +1504,29 @@ func (r *Replica) atomicReplicationChange(
 		iChgs = append(iChgs, internalReplicationChange{target: target, typ: typ})
 	}
 
+	nonVoterRemovals := rest.NonVoterRemovals()
+	if len(iChgs) == 0 && len(nonVoterRemovals) > 1 {
+		// NB: unroll the removals because we can't atomically remove multiple
+		// raft learners without changing the voters too, see
+		// internalChangeTypeRemove.
+		for _, target := range nonVoterRemovals {
+			var err error
+			desc, err = execChangeReplicasTxn(ctx, r.store, desc, reason, details,
+				[]internalReplicationChange{{target: target, typ: internalChangeTypeRemove}})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return desc, nil
+	}
+	for _, target := range nonVoterRemovals {
+		iChgs = append(iChgs, internalReplicationChange{target: target, typ: internalChangeTypeRemove})
+	}
+	if len(iChgs) == 0 {
+		// Only non-voters were added, and there's nothing left to do.
+		return desc, nil
+	}
+
 	var err error
 	desc, err = execChangeReplicasTxn(ctx, r.store, desc, reason, details, iChgs)
 	if err != nil {
@@ -1377,10 +1542,10 @@ func (r *Replica) atomicReplicationChange(
 	return maybeLeaveAtomicChangeReplicasAndRemoveLearners(ctx, r.store, desc)
 }
 
-// tryRollbackLearnerReplica attempts to remove a learner specified by the
-// target. If no such learner is found in the descriptor (including when it is a
-// voter instead), no action is taken. Otherwise, a single time-limited
-// best-effort attempt at removing the learner is made.
+// tryRollbackLearnerReplica attempts to remove a learner or a non-voter
+// specified by the target. If no such replica is found in the descriptor
+// (including when it is a voter instead), no action is taken. Otherwise, a
+// single time-limited best-effort attempt at removing the replica is made.
 func (r *Replica) tryRollBackLearnerReplica(
 	ctx context.Context,
 	desc *roachpb.RangeDescriptor,
@@ -1389,7 +1554,7 @@ func (r *Replica) tryRollBackLearnerReplica(
 	details string,
 ) {
 	repDesc, ok := desc.GetReplicaDescriptor(target.StoreID)
-	if !ok || repDesc.GetType() != roachpb.LEARNER {
+	if typ := repDesc.GetType(); !ok || (typ != roachpb.LEARNER && typ != roachpb.NON_VOTER) {
 		// There's no learner to roll back.
 		log.Event(ctx, "learner to roll back not found; skipping")
 		return
@@ -1426,6 +1591,11 @@ const (
 	_ internalChangeType = iota + 1
 	internalChangeTypeAddLearner
 	internalChangeTypePromoteLearner
+	// internalChangeTypeAddNonVoter adds a non-voter, which raft treats like a
+	// learner.
+	internalChangeTypeAddNonVoter
+	// internalChangeTypePromoteNonVoter changes a non-voter to a voter.
+	internalChangeTypePromoteNonVoter
 	// internalChangeTypeDemote changes a voter to a learner. This will
 	// necessarily go through joint consensus since it requires two individual
 	// changes (only one changes the quorum, so we could allow it in a simple
@@ -1433,6 +1603,10 @@ const (
 	// removals throughout (i.e. they show up in `ChangeReplicasTrigger.Removed()`,
 	// but not in `.Added()`).
 	internalChangeTypeDemote
+	// internalChangeTypeDemoteVoterToNonVoter changes a voter to a non-voter.
+	// Like internalChangeTypeDemote, it necessarily goes through joint consensus
+	// and is treated like a removal throughout.
+	internalChangeTypeDemoteVoterToNonVoter
 	// NB: can't remove multiple learners at once (need to remove at least one
 	// voter with them), see:
 	// https://github.com/cockroachdb/cockroach/pull/40268
@@ -1454,7 +1628,8 @@ func (c internalReplicationChanges) leaveJoint() bool { return len(c) == 0 }
 func (c internalReplicationChanges) useJoint() bool {
 	// NB: demotions require joint consensus because of limitations in etcd/raft.
 	// These could be lifted, but it doesn't seem worth it.
-	return len(c) > 1 || c[0].typ == internalChangeTypeDemote
+	return len(c) > 1 || c[0].typ == internalChangeTypeDemote ||
+		c[0].typ == internalChangeTypeDemoteVoterToNonVoter
 }
 
 type storeSettings interface {
@@ -1497,18 +1672,31 @@ func prepareChangeReplicasTrigger(
 					return nil, errors.Errorf("cannot promote target %v which is missing as Learner", chg.target)
 				}
 				added = append(added, rDesc)
+			case internalChangeTypeAddNonVoter:
+				added = append(added,
+					updatedDesc.AddReplica(chg.target.NodeID, chg.target.StoreID, roachpb.NON_VOTER))
+			case internalChangeTypePromoteNonVoter:
+				typ := roachpb.VOTER_FULL
+				if useJoint {
+					typ = roachpb.VOTER_INCOMING
+				}
+				rDesc, prevTyp, ok := updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, typ)
+				if !ok || prevTyp != roachpb.NON_VOTER {
+					return nil, errors.Errorf("cannot promote target %v which is missing as NonVoter", chg.target)
+				}
+				added = append(added, rDesc)
 			case internalChangeTypeRemove:
 				rDesc, ok := updatedDesc.GetReplicaDescriptor(chg.target.StoreID)
 				if !ok {
 					return nil, errors.Errorf("target %s not found", chg.target)
 				}
 				prevTyp := rDesc.GetType()
-				if !useJoint || prevTyp == roachpb.LEARNER {
+				if !useJoint || prevTyp == roachpb.LEARNER || prevTyp == roachpb.NON_VOTER {
 					rDesc, _ = updatedDesc.RemoveReplica(chg.target.NodeID, chg.target.StoreID)
 				} else if prevTyp != roachpb.VOTER_FULL {
 					// NB: prevTyp is already known to be VOTER_FULL because of
-					// !InAtomicReplicationChange() and the learner handling
-					// above. We check it anyway.
+					// !InAtomicReplicationChange() and the learner and non-voter
+					// handling above. We check it anyway.
 					return nil, errors.Errorf("cannot transition from %s to VOTER_OUTGOING", prevTyp)
 				} else {
 					rDesc, _, _ = updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, roachpb.VOTER_OUTGOING)
@@ -1532,6 +1720,22 @@ func prepareChangeReplicasTrigger(
 				}
 				rDesc, _, _ = updatedDesc.SetReplicaType(chg.target.NodeID, chg.target.StoreID, roachpb.VOTER_DEMOTING)
 				removed = append(removed, rDesc)
+			case internalChangeTypeDemoteVoterToNonVoter:
+				// Same as above, except that the replica stays around as a
+				// non-voter once the joint config is left.
+				rDesc, ok := updatedDesc.GetReplicaDescriptor(chg.target.StoreID)
+				if !ok {
+					return nil, errors.Errorf("target %s not found", chg.target)
+				}
+				if !useJoint {
+					return nil, errors.Errorf("demotions require joint consensus")
+				}
+				if prevTyp := rDesc.GetType(); prevTyp != roachpb.VOTER_FULL {
+					return nil, errors.Errorf("cannot transition from %s to VOTER_DEMOTING_NON_VOTER", prevTyp)
+				}
+				rDesc, _, _ = updatedDesc.SetReplicaType(
+					chg.target.NodeID, chg.target.StoreID, roachpb.VOTER_DEMOTING_NON_VOTER)
+				removed = append(removed, rDesc)
 			default:
 				return nil, errors.Errorf("unsupported internal change type %d", chg.typ)
 			}
@@ -1553,6 +1757,9 @@ func prepareChangeReplicasTrigger(
 			case roachpb.VOTER_DEMOTING:
 				updatedDesc.SetReplicaType(rDesc.NodeID, rDesc.StoreID, roachpb.LEARNER)
 				isJoint = true
+			case roachpb.VOTER_DEMOTING_NON_VOTER:
+				updatedDesc.SetReplicaType(rDesc.NodeID, rDesc.StoreID, roachpb.NON_VOTER)
+				isJoint = true
 			default:
 			}
 		}
@@ -1794,9 +2001,10 @@ func execChangeReplicasTxn(
 // the snapshot was a success.
 //
 // `receiveSnapshot` takes the key-value pairs sent and incrementally creates
-// three SSTs from them for direct ingestion: one for the replicated range-ID
-// local keys, one for the range local keys, and one for the user keys. The
-// reason it creates three separate SSTs is to prevent overlaps with the
+// four SSTs from them for direct ingestion: one for the replicated range-ID
+// local keys, one for the range local keys, one for the MVCC range tombstone
+// keys, and one for the user keys. The reason it creates four separate SSTs
+// is to prevent overlaps with the
 // memtable and existing SSTs in RocksDB. Each of the SSTs also has a range
 // deletion tombstone to delete the existing data in the range.
 //
@@ -2291,7 +2499,8 @@ func (s *Store) relocateOne(
 			storeList,
 			zone,
 			rangeReplicas,
-			s.allocator.scorerOptions())
+			s.allocator.scorerOptions(),
+			voterTarget)
 		if targetStore == nil {
 			return nil, nil, fmt.Errorf("none of the remaining targets %v are legal additions to %v",
 				addTargets, desc.Replicas())
@@ -633,8 +633,8 @@ func (r *Replica) sha512(
 	// all of the replicated key space.
 	if !statsOnly {
 		for _, span := range rditer.MakeReplicatedKeyRanges(&desc) {
-			spanMS, err := storage.ComputeStatsGo(
-				iter, span.Start.Key, span.End.Key, 0 /* nowNanos */, visitor,
+			spanMS, err := storage.ComputeStatsWithRangeTombstones(
+				snap, iter, span.Start.Key, span.End.Key, 0 /* nowNanos */, visitor,
 			)
 			if err != nil {
 				return nil, err
@@ -121,6 +121,21 @@ func (r *Replica) postDestroyRaftMuLocked(ctx context.Context, ms enginepb.MVCCS
 		})
 	}
 
+	// If the Raft log is separated, the Raft state of the replica wasn't part
+	// of the batch which removed its data. That batch is durable by now, so the
+	// Raft state can go as well.
+	if r.store.raftLogSeparated() {
+		raftEng := r.store.RaftEngine()
+		batch := raftEng.NewWriteOnlyBatch()
+		defer batch.Close()
+		if err := clearRaftEngineState(raftEng, batch, r.RangeID); err != nil {
+			return err
+		}
+		if err := batch.Commit(true); err != nil {
+			return err
+		}
+	}
+
 	// NB: we need the nil check below because it's possible that we're GC'ing a
 	// Replica without a replicaID, in which case it does not have a sideloaded
 	// storage.
@@ -95,6 +95,11 @@ func (rec *SpanSetReplicaEvalContext) Engine() storage.Engine {
 	return rec.i.Engine()
 }
 
+// RaftEngine returns the separate Raft engine, if any.
+func (rec *SpanSetReplicaEvalContext) RaftEngine() storage.Engine {
+	return rec.i.RaftEngine()
+}
+
 // GetFirstIndex returns the first index.
 func (rec *SpanSetReplicaEvalContext) GetFirstIndex() (uint64, error) {
 	return rec.i.GetFirstIndex()
@@ -147,9 +152,14 @@ func (rec SpanSetReplicaEvalContext) GetMVCCStats() enginepb.MVCCStats {
 	return rec.i.GetMVCCStats()
 }
 
-// GetSplitQPS returns the Replica's queries/s rate for splitting purposes.
-func (rec SpanSetReplicaEvalContext) GetSplitQPS() float64 {
-	return rec.i.GetSplitQPS()
+// GetSplitLoad returns the Replica's load per second for splitting purposes.
+func (rec SpanSetReplicaEvalContext) GetSplitLoad(ctx context.Context) float64 {
+	return rec.i.GetSplitLoad(ctx)
+}
+
+// GetClosedTimestamp returns the Replica's closed timestamp.
+func (rec SpanSetReplicaEvalContext) GetClosedTimestamp(ctx context.Context) hlc.Timestamp {
+	return rec.i.GetClosedTimestamp(ctx)
 }
 
 // CanCreateTxnRecord determines whether a transaction record can be created
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
@@ -137,6 +138,29 @@ func optimizePuts(
 	return reqs
 }
 
+// mayAccessMVCCRangeTombstones returns whether evaluating the batch may need
+// to read or write MVCC range tombstones. This is the case if the range
+// contains range tombstone fragments or the batch writes a range tombstone,
+// which is only possible once VersionMVCCRangeTombstones is active. Requests
+// that are evaluated concurrently with the batch and write the range's first
+// range tombstone conflict with it on latches if their spans overlap, so the
+// stats can be relied upon here.
+func mayAccessMVCCRangeTombstones(
+	ctx context.Context, rec batcheval.EvalContext, ba *roachpb.BatchRequest,
+) bool {
+	writesRangeTombstone := false
+	for _, union := range ba.Requests {
+		if dr, ok := union.GetInner().(*roachpb.DeleteRangeRequest); ok && dr.UseRangeTombstone {
+			writesRangeTombstone = true
+			break
+		}
+	}
+	if !writesRangeTombstone && rec.GetMVCCStats().RangeTombstoneCount == 0 {
+		return false
+	}
+	return rec.ClusterSettings().Version.IsActive(ctx, clusterversion.VersionMVCCRangeTombstones)
+}
+
 // evaluateBatch evaluates a batch request by splitting it up into its
 // individual commands, passing them to evaluateCommand, and combining
 // the results.
@@ -159,6 +183,12 @@ func evaluateBatch(
 		}
 	}()
 
+	// Most ranges don't contain any MVCC range tombstones, in which case reads
+	// and writes can skip looking them up.
+	if !mayAccessMVCCRangeTombstones(ctx, rec, ba) {
+		readWriter = storage.DisableMVCCRangeTombstoneLookups(readWriter)
+	}
+
 	// NB: Don't mutate BatchRequest directly.
 	baReqs := ba.Requests
 	baHeader := ba.Header
@@ -52,16 +52,16 @@ func (r *Replica) canServeFollowerRead(
 		return pErr
 	}
 
-	// There's no known reason that a non-VOTER_FULL replica couldn't serve follower
-	// reads (or RangeFeed), but as of the time of writing, these are expected
-	// to be short-lived, so it's not worth working out the edge-cases. Revisit if
-	// we add long-lived learners or feel that incoming/outgoing voters also need
-	// to be able to serve follower reads.
+	// Full voters and non-voters can serve follower reads. There's no known
+	// reason that the other replica types couldn't, but as of the time of
+	// writing, these are expected to be short-lived, so it's not worth working
+	// out the edge-cases. Revisit if we feel that learners or incoming/outgoing
+	// voters also need to be able to serve follower reads.
 	repDesc, err := r.GetReplicaDescriptor()
 	if err != nil {
 		return roachpb.NewError(err)
 	}
-	if typ := repDesc.GetType(); typ != roachpb.VOTER_FULL {
+	if typ := repDesc.GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
 		log.Eventf(ctx, "%s replicas cannot serve follower reads", typ)
 		return pErr
 	}
@@ -107,6 +107,15 @@ func (r *Replica) canServeFollowerRead(
 	return nil
 }
 
+// GetClosedTimestamp returns the maximum closed timestamp for this range, or
+// an empty timestamp if the range's lease doesn't allow for follower reads.
+//
+// GetClosedTimestamp is part of the EvalContext interface.
+func (r *Replica) GetClosedTimestamp(ctx context.Context) hlc.Timestamp {
+	ts, _ := r.maxClosed(ctx)
+	return ts
+}
+
 // maxClosed returns the maximum closed timestamp for this range.
 // It is computed as the most recent of the known closed timestamp for the
 // current lease holder for this range as tracked by the closed timestamp
@@ -151,8 +151,10 @@ func (rgcq *replicaGCQueue) shouldQueue(
 	// command which sets it to VOTER_OUTGOING we would conservatively wait
 	// 10 days before removing the node. Finally we consider replicas which are
 	// VOTER_INCOMING as suspect because no replica should stay in that state for
-	// too long and being conservative here doesn't seem worthwhile.
-	isSuspect := replDesc.GetType() != roachpb.VOTER_FULL
+	// too long and being conservative here doesn't seem worthwhile. Non-voters
+	// are long-lived, like full voters, so they aren't suspect by themselves.
+	typ := replDesc.GetType()
+	isSuspect := typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER
 	if raftStatus := repl.RaftStatus(); raftStatus != nil {
 		isSuspect = isSuspect ||
 			(raftStatus.SoftState.RaftState == raft.StateCandidate ||
@@ -90,7 +90,7 @@ func newUnloadedReplica(
 	r.mu.zone = store.cfg.DefaultZoneConfig
 	r.mu.replicaID = replicaID
 	split.Init(&r.loadBasedSplitter, rand.Intn, func() float64 {
-		return float64(SplitByLoadQPSThreshold.Get(&store.cfg.Settings.SV))
+		return splitByLoadThreshold(r.AnnotateCtx(context.TODO()), store.cfg.Settings)
 	})
 	r.mu.proposals = map[kvserverbase.CmdIDKey]*ProposalData{}
 	r.mu.checksums = map[uuid.UUID]ReplicaChecksum{}
@@ -105,6 +105,8 @@ func newUnloadedReplica(
 	// Pass nil for the localityOracle because we intentionally don't track the
 	// origin locality of write load.
 	r.writeStats = newReplicaStats(store.Clock(), nil)
+	r.cpuStats = newReplicaStats(store.Clock(), nil)
+	r.writeBytesStats = newReplicaStats(store.Clock(), nil)
 
 	// Init rangeStr with the range ID.
 	r.rangeStr.store(replicaID, &roachpb.RangeDescriptor{RangeID: desc.RangeID})
@@ -150,7 +152,15 @@ func (r *Replica) loadRaftMuLockedReplicaMuLocked(desc *roachpb.RangeDescriptor)
 	if r.mu.state, err = r.mu.stateLoader.Load(ctx, r.Engine(), desc); err != nil {
 		return err
 	}
-	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.Engine())
+	// A split or snapshot may have left a HardState in the store's engine that
+	// still needs to be handed off to the Raft engine. This is the case for the
+	// right-hand side of a split, and after a crash in the middle of a handoff.
+	if desc.IsInitialized() && r.store.raftLogSeparated() {
+		if err := r.handOffRaftStateRaftMuLocked(ctx, r.mu.stateLoader); err != nil {
+			return err
+		}
+	}
+	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.store.RaftEngine(), r.Engine())
 	if err != nil {
 		return err
 	}
@@ -394,6 +394,7 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+		r.cpuStats.resetRequestCounts()
 	}
 
 	// Inform the concurrency manager that the lease holder has been updated.
@@ -445,6 +446,7 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+		r.cpuStats.resetRequestCounts()
 	}
 
 	// Potentially re-gossip if the range contains system data (e.g. system
@@ -738,7 +740,18 @@ func (r *Replica) evaluateProposal(
 	// important since evaluating a proposal is expensive.
 	// TODO(tschottdorf): absorb all returned values in `res` below this point
 	// in the call stack as well.
+	start := timeutil.Now()
 	batch, ms, br, res, pErr := r.evaluateWriteBatch(ctx, idKey, ba, latchSpans)
+	var writeBytes int64
+	if pErr == nil {
+		if batch != nil {
+			writeBytes = int64(batch.Len())
+		}
+		if res.Replicated.AddSSTable != nil {
+			writeBytes += int64(len(res.Replicated.AddSSTable.Data))
+		}
+	}
+	r.recordBatchEvaluation(ctx, latchSpans, timeutil.Since(start), writeBytes)
 
 	// Note: reusing the proposer's batch when applying the command on the
 	// proposer was explored as an optimization but resulted in no performance
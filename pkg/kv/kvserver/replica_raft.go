@@ -647,8 +647,10 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 
 	// Use a more efficient write-only batch because we don't need to do any
 	// reads from the batch. Any reads are performed via the "distinct" batch
-	// which passes the reads through to the underlying DB.
-	batch := r.store.Engine().NewWriteOnlyBatch()
+	// which passes the reads through to the underlying DB. The log entries and
+	// HardState live in the store's Raft engine, which is the store's engine
+	// unless the Raft log is separated.
+	batch := r.store.RaftEngine().NewWriteOnlyBatch()
 	defer batch.Close()
 
 	// We know that all of the writes from here forward will be to distinct keys.
@@ -1657,11 +1659,16 @@ func (r *Replica) acquireMergeLock(
 // the associated RaftLogDelta. It is usually expected to be true, but may not
 // be for the first truncation after on a replica that recently received a
 // snapshot.
+//
+// The removal of the truncated log entries is written to raftLogWriter. It is
+// nil if the Raft log is kept in a separate engine, in which case the caller
+// removes the entries once the truncated state is durable.
 func handleTruncatedStateBelowRaft(
 	ctx context.Context,
 	oldTruncatedState, newTruncatedState *roachpb.RaftTruncatedState,
 	loader stateloader.StateLoader,
 	readWriter storage.ReadWriter,
+	raftLogWriter storage.Writer,
 ) (_apply bool, _ error) {
 	// If this is a log truncation, load the resulting unreplicated or legacy
 	// replicated truncated state (in that order). If the migration is happening
@@ -1689,11 +1696,11 @@ func handleTruncatedStateBelowRaft(
 	// perform well here because the tombstones could be "collapsed",
 	// but it is hardly worth the risk at this point.
 	prefixBuf := &loader.RangeIDPrefixBuf
-	for idx := oldTruncatedState.Index + 1; idx <= newTruncatedState.Index; idx++ {
+	for idx := oldTruncatedState.Index + 1; raftLogWriter != nil && idx <= newTruncatedState.Index; idx++ {
 		// NB: RangeIDPrefixBufs have sufficient capacity (32 bytes) to
 		// avoid allocating when constructing Raft log keys (16 bytes).
 		unsafeKey := prefixBuf.RaftLogKey(idx)
-		if err := readWriter.Clear(storage.MakeMVCCMetadataKey(unsafeKey)); err != nil {
+		if err := raftLogWriter.Clear(storage.MakeMVCCMetadataKey(unsafeKey)); err != nil {
 			return false, errors.Wrapf(err, "unable to clear truncated Raft entries for %+v", newTruncatedState)
 		}
 	}
@@ -64,11 +64,18 @@ var _ raft.Storage = (*replicaRaftStorage)(nil)
 // InitialState requires that r.mu is held.
 func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState, error) {
 	ctx := r.AnnotateCtx(context.TODO())
-	hs, err := r.mu.stateLoader.LoadHardState(ctx, r.store.Engine())
+	hs, err := r.mu.stateLoader.LoadHardState(ctx, r.store.RaftEngine())
 	// For uninitialized ranges, membership is unknown at this point.
 	if raft.IsEmptyHardState(hs) || err != nil {
 		return raftpb.HardState{}, raftpb.ConfState{}, err
 	}
+	// The commit index is not synced on its own. If the Raft log lives in a
+	// separate engine, a crash may thus lose a commit index that the state
+	// machine has already applied. The applied entries were synced before
+	// they were applied, so it is safe to move the commit index forward.
+	if applied := r.mu.state.RaftAppliedIndex; hs.Commit < applied && r.store.raftLogSeparated() {
+		hs.Commit = applied
+	}
 	cs := r.mu.state.Desc.Replicas().ConfState()
 	return hs, cs, nil
 }
@@ -77,29 +84,46 @@ func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState,
 // and this method will always return at least one entry even if it exceeds
 // maxBytes. Sideloaded proposals count towards maxBytes with their payloads inlined.
 func (r *replicaRaftStorage) Entries(lo, hi, maxBytes uint64) ([]raftpb.Entry, error) {
-	readonly := r.store.Engine().NewReadOnly()
-	defer readonly.Close()
+	raftReader, stateReader, closeReaders := (*Replica)(r).raftReaders()
+	defer closeReaders()
 	ctx := r.AnnotateCtx(context.TODO())
 	if r.raftMu.sideloaded == nil {
 		return nil, errors.New("sideloaded storage is uninitialized")
 	}
-	return entries(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache,
+	return entries(ctx, r.mu.stateLoader, raftReader, stateReader, r.RangeID, r.store.raftEntryCache,
 		r.raftMu.sideloaded, lo, hi, maxBytes)
 }
 
+// raftReaders returns read-only views of the engine holding the replica's
+// Raft log and of the engine holding the rest of its state, along with a
+// function that closes them. Both views are of the same engine unless the
+// store's Raft log is separated.
+func (r *Replica) raftReaders() (raftReader, stateReader storage.Reader, closeReaders func()) {
+	readonly := r.store.Engine().NewReadOnly()
+	if !r.store.raftLogSeparated() {
+		return readonly, readonly, readonly.Close
+	}
+	raftReadonly := r.store.RaftEngine().NewReadOnly()
+	return raftReadonly, readonly, func() {
+		raftReadonly.Close()
+		readonly.Close()
+	}
+}
+
 // raftEntriesLocked requires that r.mu is held.
 func (r *Replica) raftEntriesLocked(lo, hi, maxBytes uint64) ([]raftpb.Entry, error) {
 	return (*replicaRaftStorage)(r).Entries(lo, hi, maxBytes)
 }
 
-// entries retrieves entries from the engine. To accommodate loading the term,
+// entries retrieves entries from the engine. The log is read from raftReader
+// and the truncated state from stateReader. To accommodate loading the term,
 // `sideloaded` can be supplied as nil, in which case sideloaded entries will
 // not be inlined, the raft entry cache will not be populated with *any* of the
 // loaded entries, and maxBytes will not be applied to the payloads.
 func entries(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
-	reader storage.Reader,
+	raftReader, stateReader storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	sideloaded SideloadStorage,
@@ -175,7 +199,7 @@ func entries(
 		return nil
 	}
 
-	if err := iterateEntries(ctx, reader, rangeID, expectedIndex, hi, scanFunc); err != nil {
+	if err := iterateEntries(ctx, raftReader, rangeID, expectedIndex, hi, scanFunc); err != nil {
 		return nil, err
 	}
 	// Cache the fetched entries, if we may.
@@ -201,7 +225,7 @@ func entries(
 		}
 
 		// Was the missing index after the last index?
-		lastIndex, err := rsl.LoadLastIndex(ctx, reader)
+		lastIndex, err := rsl.LoadLastIndex(ctx, raftReader, stateReader)
 		if err != nil {
 			return nil, err
 		}
@@ -214,7 +238,7 @@ func entries(
 	}
 
 	// No results, was it due to unavailability or truncation?
-	ts, _, err := rsl.LoadRaftTruncatedState(ctx, reader)
+	ts, _, err := rsl.LoadRaftTruncatedState(ctx, stateReader)
 	if err != nil {
 		return nil, err
 	}
@@ -260,10 +284,10 @@ func (r *replicaRaftStorage) Term(i uint64) (uint64, error) {
 	if e, ok := r.store.raftEntryCache.Get(r.RangeID, i); ok {
 		return e.Term, nil
 	}
-	readonly := r.store.Engine().NewReadOnly()
-	defer readonly.Close()
+	raftReader, stateReader, closeReaders := (*Replica)(r).raftReaders()
+	defer closeReaders()
 	ctx := r.AnnotateCtx(context.TODO())
-	return term(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache, i)
+	return term(ctx, r.mu.stateLoader, raftReader, stateReader, r.RangeID, r.store.raftEntryCache, i)
 }
 
 // raftTermLocked requires that r.mu is locked for reading.
@@ -274,16 +298,16 @@ func (r *Replica) raftTermRLocked(i uint64) (uint64, error) {
 func term(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
-	reader storage.Reader,
+	raftReader, stateReader storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	i uint64,
 ) (uint64, error) {
 	// entries() accepts a `nil` sideloaded storage and will skip inlining of
 	// sideloaded entries. We only need the term, so this is what we do.
-	ents, err := entries(ctx, rsl, reader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
+	ents, err := entries(ctx, rsl, raftReader, stateReader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
 	if errors.Is(err, raft.ErrCompacted) {
-		ts, _, err := rsl.LoadRaftTruncatedState(ctx, reader)
+		ts, _, err := rsl.LoadRaftTruncatedState(ctx, stateReader)
 		if err != nil {
 			return 0, err
 		}
@@ -402,6 +426,10 @@ func (r *Replica) GetSnapshot(
 	// the corresponding Raft command not applied yet).
 	r.raftMu.Lock()
 	snap := r.store.engine.NewSnapshot()
+	raftSnap := storage.Reader(snap)
+	if r.store.raftLogSeparated() {
+		raftSnap = r.store.raftEngine.NewSnapshot()
+	}
 	r.mu.Lock()
 	appliedIndex := r.mu.state.RaftAppliedIndex
 	// Cleared when OutgoingSnapshot closes.
@@ -418,6 +446,9 @@ func (r *Replica) GetSnapshot(
 		if err != nil {
 			release()
 			snap.Close()
+			if raftSnap != snap {
+				raftSnap.Close()
+			}
 		}
 	}()
 
@@ -444,7 +475,7 @@ func (r *Replica) GetSnapshot(
 	// create a new state loader.
 	snapData, err := snapshot(
 		ctx, snapUUID, stateloader.Make(rangeID), snapType,
-		snap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
+		snap, raftSnap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
 	)
 	if err != nil {
 		log.Errorf(ctx, "error generating snapshot: %+v", err)
@@ -463,6 +494,9 @@ type OutgoingSnapshot struct {
 	RaftSnap raftpb.Snapshot
 	// The RocksDB snapshot that will be streamed from.
 	EngineSnap storage.Reader
+	// The snapshot of the engine holding the Raft log, taken together with
+	// EngineSnap. It is EngineSnap itself unless the Raft log is separated.
+	RaftEngineSnap storage.Reader
 	// The complete range iterator for the snapshot to stream.
 	Iter *rditer.ReplicaDataIterator
 	// The replica state within the snapshot.
@@ -490,6 +524,9 @@ func (s *OutgoingSnapshot) SafeFormat(w redact.SafePrinter, _ rune) {
 func (s *OutgoingSnapshot) Close() {
 	s.Iter.Close()
 	s.EngineSnap.Close()
+	if s.RaftEngineSnap != s.EngineSnap {
+		s.RaftEngineSnap.Close()
+	}
 	if s.onClose != nil {
 		s.onClose()
 	}
@@ -532,7 +569,7 @@ func snapshot(
 	snapUUID uuid.UUID,
 	rsl stateloader.StateLoader,
 	snapType SnapshotRequest_Type,
-	snap storage.Reader,
+	snap, raftSnap storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	withSideloaded func(func(SideloadStorage) error) error,
@@ -558,7 +595,7 @@ func snapshot(
 		return OutgoingSnapshot{}, err
 	}
 
-	term, err := term(ctx, rsl, snap, rangeID, eCache, appliedIndex)
+	term, err := term(ctx, rsl, raftSnap, snap, rangeID, eCache, appliedIndex)
 	if err != nil {
 		return OutgoingSnapshot{}, errors.Errorf("failed to fetch term of %d: %s", appliedIndex, err)
 	}
@@ -577,6 +614,7 @@ func snapshot(
 		RaftEntryCache: eCache,
 		WithSideloaded: withSideloaded,
 		EngineSnap:     snap,
+		RaftEngineSnap: raftSnap,
 		Iter:           iter,
 		State:          state,
 		SnapUUID:       snapUUID,
@@ -796,6 +834,13 @@ func (r *Replica) applySnapshot(
 		// the commit index.
 		log.Fatalf(ctx, "found empty HardState for non-empty Snapshot %+v", snap)
 	}
+	if len(inSnap.LogEntries) > 0 && r.store.raftLogSeparated() {
+		// The log entries would have to be written to the Raft engine, which
+		// can't be done atomically with the ingestion below. Only legacy
+		// snapshots carry log entries.
+		return errors.Errorf("%s keeps its Raft log in a separate engine and cannot apply "+
+			"snapshot %s carrying %d log entries", r.store, inSnap, len(inSnap.LogEntries))
+	}
 
 	var stats struct {
 		// Time to process subsumed replicas.
@@ -841,7 +886,8 @@ func (r *Replica) applySnapshot(
 		return errors.Wrapf(err, "error clearing range of unreplicated SST writer")
 	}
 
-	// Update HardState.
+	// Update HardState. If the Raft log is separated, the HardState is handed
+	// off to the Raft engine once the SSTs have been ingested.
 	if err := r.raftMu.stateLoader.SetHardState(ctx, &unreplicatedSST, hs); err != nil {
 		return errors.Wrapf(err, "unable to write HardState to unreplicated SST writer")
 	}
@@ -944,6 +990,10 @@ func (r *Replica) applySnapshot(
 	// has not yet been updated. Any errors past this point must therefore be
 	// treated as fatal.
 
+	if err := r.handOffRaftStateRaftMuLocked(ctx, r.raftMu.stateLoader); err != nil {
+		log.Fatalf(ctx, "unable to hand off HardState while applying snapshot: %+v", err)
+	}
+
 	if err := r.clearSubsumedReplicaInMemoryData(ctx, subsumedRepls, mergedTombstoneReplicaID); err != nil {
 		log.Fatalf(ctx, "failed to clear in-memory data of subsumed replicas while applying snapshot: %+v", err)
 	}
@@ -1023,9 +1073,10 @@ func (r *Replica) clearSubsumedReplicaDiskData(
 	subsumedRepls []*Replica,
 	subsumedNextReplicaID roachpb.ReplicaID,
 ) error {
-	getKeyRanges := func(desc *roachpb.RangeDescriptor) [2]rditer.KeyRange {
+	getKeyRanges := func(desc *roachpb.RangeDescriptor) [3]rditer.KeyRange {
 		return [...]rditer.KeyRange{
 			rditer.MakeRangeLocalKeyRange(desc),
+			rditer.MakeMVCCRangeTombstoneKeyRange(desc),
 			rditer.MakeUserKeyRange(desc),
 		}
 	}
@@ -201,7 +201,10 @@ func (r *Replica) RangeFeed(
 	if usingCatchupIter {
 		catchUpIterFunc = func() storage.SimpleIterator {
 
-			innerIter := r.Engine().NewIterator(storage.IterOptions{
+			// MVCC range tombstones are presented to the catch-up scan as
+			// deletions of the keys they deleted.
+			innerIter := storage.NewIteratorWithRangeTombstones(r.Engine(), storage.IterOptions{
+				LowerBound: args.Span.Key,
 				UpperBound: args.Span.EndKey,
 				// RangeFeed originally intended to use the time-bound iterator
 				// performance optimization. However, they've had correctness issues in
@@ -220,7 +223,8 @@ func (r *Replica) RangeFeed(
 		}
 	}
 	p := r.registerWithRangefeedRaftMuLocked(
-		ctx, rSpan, args.Timestamp, catchUpIterFunc, args.WithDiff, lockedStream, errC,
+		ctx, rSpan, args.Timestamp, catchUpIterFunc, args.WithDiff,
+		rangefeed.MakeEventFilter(args), lockedStream, errC,
 	)
 	r.raftMu.Unlock()
 
@@ -287,11 +291,9 @@ func (r *Replica) updateRangefeedFilterLocked() bool {
 // The size of an event is 112 bytes, so this will result in an allocation on
 // the order of ~512KB per RangeFeed. That's probably ok given the number of
 // ranges on a node that we'd like to support with active rangefeeds, but it's
-// certainly on the upper end of the range.
-//
-// TODO(dan): Everyone seems to agree that this memory limit would be better set
-// at a store-wide level, but there doesn't seem to be an easy way to accomplish
-// that.
+// certainly on the upper end of the range. The memory used by the events
+// buffered for each registration is additionally accounted for in the node's
+// rangefeed memory budget (see StoreConfig.RangefeedMemMonitor).
 const defaultEventChanCap = 4096
 
 // registerWithRangefeedRaftMuLocked sets up a Rangefeed registration over the
@@ -303,6 +305,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	startTS hlc.Timestamp,
 	catchupIter rangefeed.IteratorConstructor,
 	withDiff bool,
+	eventFilter rangefeed.EventFilter,
 	stream rangefeed.Stream,
 	errC chan<- *roachpb.Error,
 ) *rangefeed.Processor {
@@ -312,7 +315,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	r.rangefeedMu.Lock()
 	p := r.rangefeedMu.proc
 	if p != nil {
-		reg, filter := p.Register(span, startTS, catchupIter, withDiff, stream, errC)
+		reg, filter := p.Register(span, startTS, catchupIter, withDiff, eventFilter, stream, errC)
 		if reg {
 			// Registered successfully with an existing processor.
 			// Update the rangefeed filter to avoid filtering ops
@@ -333,15 +336,17 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	desc := r.Desc()
 	tp := rangefeedTxnPusher{ir: r.store.intentResolver, r: r}
 	cfg := rangefeed.Config{
-		AmbientContext:   r.AmbientContext,
-		Clock:            r.Clock(),
-		Span:             desc.RSpan(),
-		TxnPusher:        &tp,
-		PushTxnsInterval: r.store.TestingKnobs().RangeFeedPushTxnsInterval,
-		PushTxnsAge:      r.store.TestingKnobs().RangeFeedPushTxnsAge,
-		EventChanCap:     defaultEventChanCap,
-		EventChanTimeout: 50 * time.Millisecond,
-		Metrics:          r.store.metrics.RangeFeedMetrics,
+		AmbientContext:     r.AmbientContext,
+		Clock:              r.Clock(),
+		Span:               desc.RSpan(),
+		TxnPusher:          &tp,
+		PushTxnsInterval:   r.store.TestingKnobs().RangeFeedPushTxnsInterval,
+		PushTxnsAge:        r.store.TestingKnobs().RangeFeedPushTxnsAge,
+		EventChanCap:       defaultEventChanCap,
+		EventChanTimeout:   50 * time.Millisecond,
+		MemMonitor:         r.store.cfg.RangefeedMemMonitor,
+		CatchupScanLimiter: r.store.limiters.RangefeedCatchupScanRate,
+		Metrics:            r.store.metrics.RangeFeedMetrics,
 	}
 	p = rangefeed.NewProcessor(cfg)
 
@@ -365,7 +370,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	// any other goroutines are able to stop the processor. In other words,
 	// this ensures that the only time the registration fails is during
 	// server shutdown.
-	reg, filter := p.Register(span, startTS, catchupIter, withDiff, stream, errC)
+	reg, filter := p.Register(span, startTS, catchupIter, withDiff, eventFilter, stream, errC)
 	if !reg {
 		select {
 		case <-r.store.Stopper().ShouldQuiesce():
@@ -24,17 +24,23 @@ const (
 type replicaWithStats struct {
 	repl *Replica
 	qps  float64
-	// TODO(a-robinson): Include writes-per-second and logicalBytes of storage?
+	// cpu is the time in nanoseconds per second spent evaluating requests.
+	cpu float64
+	// writeBytes is the number of bytes written per second.
+	writeBytes float64
+	// TODO(a-robinson): Include logicalBytes of storage?
 }
 
 // replicaRankings maintains top-k orderings of the replicas in a store along
-// different dimensions of concern, such as QPS, keys written per second, and
-// disk used.
+// different dimensions of concern, such as QPS, CPU time and bytes written per
+// second.
 type replicaRankings struct {
 	mu struct {
 		syncutil.Mutex
-		qpsAccumulator *rrAccumulator
-		byQPS          []replicaWithStats
+		accumulator  *rrAccumulator
+		byQPS        []replicaWithStats
+		byCPU        []replicaWithStats
+		byWriteBytes []replicaWithStats
 	}
 }
 
@@ -45,24 +51,42 @@ func newReplicaRankings() *replicaRankings {
 func (rr *replicaRankings) newAccumulator() *rrAccumulator {
 	res := &rrAccumulator{}
 	res.qps.val = func(r replicaWithStats) float64 { return r.qps }
+	res.cpu.val = func(r replicaWithStats) float64 { return r.cpu }
+	res.writeBytes.val = func(r replicaWithStats) float64 { return r.writeBytes }
 	return res
 }
 
 func (rr *replicaRankings) update(acc *rrAccumulator) {
 	rr.mu.Lock()
-	rr.mu.qpsAccumulator = acc
+	rr.mu.accumulator = acc
 	rr.mu.Unlock()
 }
 
 func (rr *replicaRankings) topQPS() []replicaWithStats {
+	return rr.topLoad(LBRebalancingQueries)
+}
+
+// topLoad returns the replicas with the highest load along the given
+// dimension, in decreasing order.
+func (rr *replicaRankings) topLoad(dim LBRebalancingDimension) []replicaWithStats {
 	rr.mu.Lock()
 	defer rr.mu.Unlock()
+	var pq *rrPriorityQueue
+	var top *[]replicaWithStats
+	switch dim {
+	case LBRebalancingCPU:
+		pq, top = &rr.mu.accumulator.cpu, &rr.mu.byCPU
+	case LBRebalancingWriteBytes:
+		pq, top = &rr.mu.accumulator.writeBytes, &rr.mu.byWriteBytes
+	default:
+		pq, top = &rr.mu.accumulator.qps, &rr.mu.byQPS
+	}
 	// If we have a new set of data, consume it. Otherwise, just return the most
 	// recently consumed data.
-	if rr.mu.qpsAccumulator.qps.Len() > 0 {
-		rr.mu.byQPS = consumeAccumulator(&rr.mu.qpsAccumulator.qps)
+	if pq.Len() > 0 {
+		*top = consumeAccumulator(pq)
 	}
-	return rr.mu.byQPS
+	return *top
 }
 
 // rrAccumulator is used to update the replicas tracked by replicaRankings.
@@ -74,21 +98,31 @@ func (rr *replicaRankings) topQPS() []replicaWithStats {
 // prevents concurrent loaders of data from messing with each other -- the last
 // `update`d accumulator will win.
 type rrAccumulator struct {
-	qps rrPriorityQueue
+	qps        rrPriorityQueue
+	cpu        rrPriorityQueue
+	writeBytes rrPriorityQueue
 }
 
 func (a *rrAccumulator) addReplica(repl replicaWithStats) {
+	a.qps.maybeAdd(repl)
+	a.cpu.maybeAdd(repl)
+	a.writeBytes.maybeAdd(repl)
+}
+
+// maybeAdd adds the replica to the queue if the queue isn't full or if the
+// replica is more deserving than the least deserving replica in it.
+func (pq *rrPriorityQueue) maybeAdd(repl replicaWithStats) {
 	// If the heap isn't full, just push the new replica and return.
-	if a.qps.Len() < numTopReplicasToTrack {
-		heap.Push(&a.qps, repl)
+	if pq.Len() < numTopReplicasToTrack {
+		heap.Push(pq, repl)
 		return
 	}
 
 	// Otherwise, conditionally push if the new replica is more deserving than
 	// the current tip of the heap.
-	if repl.qps > a.qps.entries[0].qps {
-		heap.Pop(&a.qps)
-		heap.Push(&a.qps, repl)
+	if pq.val(repl) > pq.val(pq.entries[0]) {
+		heap.Pop(pq)
+		heap.Push(pq, repl)
 	}
 }
 
@@ -73,3 +73,32 @@ func TestReplicaRankings(t *testing.T) {
 		}
 	}
 }
+
+func TestReplicaRankingsByLoadDimension(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	rr := newReplicaRankings()
+	acc := rr.newAccumulator()
+	acc.addReplica(replicaWithStats{repl: &Replica{RangeID: 1}, qps: 3, cpu: 1, writeBytes: 2})
+	acc.addReplica(replicaWithStats{repl: &Replica{RangeID: 2}, qps: 2, cpu: 3, writeBytes: 1})
+	acc.addReplica(replicaWithStats{repl: &Replica{RangeID: 3}, qps: 1, cpu: 2, writeBytes: 3})
+	rr.update(acc)
+
+	for _, tc := range []struct {
+		dim  LBRebalancingDimension
+		want []roachpb.RangeID
+	}{
+		{LBRebalancingQueries, []roachpb.RangeID{1, 2, 3}},
+		{LBRebalancingCPU, []roachpb.RangeID{2, 3, 1}},
+		{LBRebalancingWriteBytes, []roachpb.RangeID{3, 1, 2}},
+	} {
+		var got []roachpb.RangeID
+		for _, r := range rr.topLoad(tc.dim) {
+			got = append(got, r.repl.RangeID)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("dimension %d: got ranges %v; want %v", tc.dim, got, tc.want)
+		}
+	}
+}
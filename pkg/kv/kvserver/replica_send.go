@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/txnwait"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
@@ -300,7 +301,10 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 		} else {
 			// If the request is a write or a consistent read, it requires the
 			// range lease or permission to serve via follower reads.
-			if status, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
+			resume := admission.PauseAdmittedWork(ctx)
+			status, pErr = r.redirectOnOrAcquireLease(ctx)
+			resume()
+			if pErr != nil {
 				if nErr := r.canServeFollowerRead(ctx, ba, pErr); nErr != nil {
 					return nil, nErr
 				}
@@ -326,8 +330,11 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 		// Acquire latches to prevent overlapping requests from executing until
 		// this request completes. After latching, wait on any conflicting locks
 		// to ensure that the request has full isolation during evaluation. This
-		// returns a request guard that must be eventually released.
+		// returns a request guard that must be eventually released. The KV
+		// admission slot of the request, if any, is released while it waits, so
+		// that the requests it's waiting on can run.
 		var resp []roachpb.ResponseUnion
+		resume := admission.PauseAdmittedWork(ctx)
 		g, resp, pErr = r.concMgr.SequenceReq(ctx, g, concurrency.Request{
 			Txn:             ba.Txn,
 			Timestamp:       ba.Timestamp,
@@ -338,6 +345,7 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 			LatchSpans:      latchSpans,
 			LockSpans:       lockSpans,
 		})
+		resume()
 		if pErr != nil {
 			return nil, pErr
 		} else if resp != nil {
@@ -12,11 +12,13 @@ package kvserver
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
@@ -34,6 +36,23 @@ var SplitByLoadQPSThreshold = settings.RegisterPublicIntSetting(
 	2500, // 2500 req/s
 )
 
+// SplitByLoadCPUThreshold wraps "kv.range_split.load_cpu_threshold".
+var SplitByLoadCPUThreshold = settings.RegisterPublicNonNegativeDurationSetting(
+	"kv.range_split.load_cpu_threshold",
+	"the time spent evaluating requests per second over which, the range becomes a candidate for "+
+		"load based splitting when kv.allocator.load_based_rebalancing.dimension is cpu",
+	500*time.Millisecond,
+)
+
+// SplitByLoadWriteBytesThreshold wraps
+// "kv.range_split.load_write_bytes_threshold".
+var SplitByLoadWriteBytesThreshold = settings.RegisterPublicByteSizeSetting(
+	"kv.range_split.load_write_bytes_threshold",
+	"the bytes written per second over which, the range becomes a candidate for "+
+		"load based splitting when kv.allocator.load_based_rebalancing.dimension is write_bytes",
+	16<<20, // 16 MiB/s
+)
+
 // SplitByLoadMergeDelay wraps "kv.range_split.by_load_merge_delay".
 var SplitByLoadMergeDelay = settings.RegisterNonNegativeDurationSetting(
 	"kv.range_split.by_load_merge_delay",
@@ -41,9 +60,26 @@ var SplitByLoadMergeDelay = settings.RegisterNonNegativeDurationSetting(
 	5*time.Minute,
 )
 
-// SplitByLoadQPSThreshold returns the QPS request rate for a given replica.
-func (r *Replica) SplitByLoadQPSThreshold() float64 {
-	return float64(SplitByLoadQPSThreshold.Get(&r.store.cfg.Settings.SV))
+// splitByLoadThreshold returns the load over which a range becomes a
+// candidate for load based splitting, in the units of the configured
+// LBRebalancingDimension.
+func splitByLoadThreshold(ctx context.Context, st *cluster.Settings) float64 {
+	sv := &st.SV
+	switch loadDimension(ctx, st) {
+	case LBRebalancingCPU:
+		return float64(SplitByLoadCPUThreshold.Get(sv))
+	case LBRebalancingWriteBytes:
+		return float64(SplitByLoadWriteBytesThreshold.Get(sv))
+	default:
+		return float64(SplitByLoadQPSThreshold.Get(sv))
+	}
+}
+
+// SplitByLoadThreshold returns the load over which the replica becomes a
+// candidate for load based splitting, in the units of the configured
+// LBRebalancingDimension.
+func (r *Replica) SplitByLoadThreshold() float64 {
+	return splitByLoadThreshold(r.AnnotateCtx(context.TODO()), r.store.cfg.Settings)
 }
 
 // SplitByLoadEnabled returns whether load based splitting is enabled.
@@ -55,17 +91,57 @@ func (r *Replica) SplitByLoadEnabled() bool {
 }
 
 // recordBatchForLoadBasedSplitting records the batch's spans to be considered
-// for load based splitting.
+// for load based splitting, if splits are based on the number of requests.
 func (r *Replica) recordBatchForLoadBasedSplitting(
 	ctx context.Context, ba *roachpb.BatchRequest, spans *spanset.SpanSet,
+) {
+	if dim := loadDimension(ctx, r.store.cfg.Settings); dim == LBRebalancingQueries {
+		r.recordLoadForLoadBasedSplitting(ctx, dim, len(ba.Requests), spans)
+	}
+}
+
+// recordBatchEvaluation records the time spent evaluating a batch and the bytes
+// it wrote, which are used for load-based rebalancing and, depending on the
+// configured LBRebalancingDimension, load based splitting. Write bytes are
+// recorded in the replica's writeBytesStats when they are applied, since that
+// happens on every replica, not only on the leaseholder.
+func (r *Replica) recordBatchEvaluation(
+	ctx context.Context, spans *spanset.SpanSet, evalDuration time.Duration, writeBytes int64,
+) {
+	r.cpuStats.recordCount(float64(evalDuration.Nanoseconds()), 0)
+	switch dim := loadDimension(ctx, r.store.cfg.Settings); dim {
+	case LBRebalancingCPU:
+		r.recordLoadForLoadBasedSplitting(ctx, dim, int(evalDuration.Nanoseconds()), spans)
+	case LBRebalancingWriteBytes:
+		if writeBytes > 0 {
+			r.recordLoadForLoadBasedSplitting(ctx, dim, int(writeBytes), spans)
+		}
+	}
+}
+
+// recordLoadForLoadBasedSplitting records n units of load along the given
+// dimension on the given spans to be considered for load based splitting.
+func (r *Replica) recordLoadForLoadBasedSplitting(
+	ctx context.Context, dim LBRebalancingDimension, n int, spans *spanset.SpanSet,
 ) {
 	if !r.SplitByLoadEnabled() {
 		return
 	}
-	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), len(ba.Requests), func() roachpb.Span {
+	r.maybeResetLoadBasedSplitter(dim)
+	shouldInitSplit := r.loadBasedSplitter.Record(timeutil.Now(), n, func() roachpb.Span {
 		return spans.BoundarySpan(spanset.SpanGlobal)
 	})
 	if shouldInitSplit {
 		r.store.splitQueue.MaybeAddAsync(ctx, r, r.store.Clock().Now())
 	}
 }
+
+// maybeResetLoadBasedSplitter resets the load based splitter when the dimension
+// of the load recorded by it changes, so that the load measured so far, in
+// other units, is not compared against the threshold of the new dimension.
+func (r *Replica) maybeResetLoadBasedSplitter(dim LBRebalancingDimension) {
+	prev := LBRebalancingDimension(atomic.SwapInt64(&r.loadBasedSplitterDimension, int64(dim)))
+	if prev != dim {
+		r.loadBasedSplitter.Clear()
+	}
+}
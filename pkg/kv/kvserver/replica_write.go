@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -188,6 +189,10 @@ func (r *Replica) executeWriteBatch(
 		}
 	}()
 
+	// The KV admission slot of the request, if any, isn't needed while the
+	// command is replicated.
+	resume := admission.PauseAdmittedWork(ctx)
+	defer resume()
 	for {
 		select {
 		case propResult := <-ch:
@@ -238,6 +238,12 @@ func (rq *replicateQueue) shouldQueue(
 			log.VEventf(ctx, 2, "rebalance target found, enqueuing")
 			return true, 0
 		}
+		_, _, _, ok = rq.allocator.RebalanceNonVoterTarget(
+			ctx, zone, voterReplicas, desc.Replicas().NonVoters(), rangeUsageInfo, storeFilterThrottled)
+		if ok {
+			log.VEventf(ctx, 2, "non-voter rebalance target found, enqueuing")
+			return true, 0
+		}
 		log.VEventf(ctx, 2, "no rebalance target found, not enqueuing")
 	}
 
@@ -408,6 +414,39 @@ func (rq *replicateQueue) processOneChange(
 		return rq.removeDead(ctx, repl, deadVoterReplicas, dryRun)
 	case AllocatorRemoveLearner:
 		return rq.removeLearner(ctx, repl, dryRun)
+	case AllocatorAddNonVoter:
+		return rq.addNonVoter(ctx, repl, dryRun)
+	case AllocatorRemoveNonVoter:
+		nonVoterReplicas := desc.Replicas().NonVoters()
+		removeReplica, details, err := rq.allocator.RemoveNonVoterTarget(
+			ctx, zone, nonVoterReplicas, desc.Replicas().All())
+		if err != nil {
+			return false, err
+		}
+		rq.metrics.RemoveReplicaCount.Inc(1)
+		log.VEventf(ctx, 1, "removing non-voter %+v due to over-replication", removeReplica)
+		return rq.removeNonVoter(
+			ctx, repl, removeReplica, kvserverpb.ReasonRangeOverReplicated, details, dryRun)
+	case AllocatorRemoveDeadNonVoter:
+		_, deadNonVoterReplicas := rq.allocator.storePool.liveAndDeadReplicas(desc.Replicas().NonVoters())
+		if len(deadNonVoterReplicas) == 0 {
+			// Nothing to do.
+			return false, nil
+		}
+		rq.metrics.RemoveDeadReplicaCount.Inc(1)
+		log.VEventf(ctx, 1, "removing dead non-voter %+v from store", deadNonVoterReplicas[0])
+		return rq.removeNonVoter(
+			ctx, repl, deadNonVoterReplicas[0], kvserverpb.ReasonStoreDead, "", dryRun)
+	case AllocatorRemoveDecommissioningNonVoter:
+		decommissioningReplicas := rq.allocator.storePool.decommissioningReplicas(desc.Replicas().NonVoters())
+		if len(decommissioningReplicas) == 0 {
+			// Nothing to do.
+			return false, nil
+		}
+		rq.metrics.RemoveReplicaCount.Inc(1)
+		log.VEventf(ctx, 1, "removing decommissioning non-voter %+v from store", decommissioningReplicas[0])
+		return rq.removeNonVoter(
+			ctx, repl, decommissioningReplicas[0], kvserverpb.ReasonStoreDecommissioning, "", dryRun)
 	case AllocatorConsiderRebalance:
 		return rq.considerRebalance(ctx, repl, voterReplicas, canTransferLease, dryRun)
 	case AllocatorFinalizeAtomicReplicationChange:
@@ -494,7 +533,7 @@ func (rq *replicateQueue) addOrReplace(
 	}
 
 	clusterNodes := rq.allocator.storePool.ClusterNodeCount()
-	need := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
+	need := GetNeededReplicas(zone.GetNumVoters(), clusterNodes)
 
 	// Only up-replicate if there are suitable allocation targets such that,
 	// either the replication goal is met, or it is possible to get to the next
@@ -530,7 +569,7 @@ func (rq *replicateQueue) addOrReplace(
 		}
 	}
 	rq.metrics.AddReplicaCount.Inc(1)
-	ops := roachpb.MakeReplicationChanges(roachpb.ADD_REPLICA, newReplica)
+	ops := voterAdditionChanges(desc, newReplica)
 	if removeIdx < 0 {
 		log.VEventf(ctx, 1, "adding replica %+v: %s",
 			newReplica, rangeRaftProgress(repl.RaftStatus(), existingReplicas))
@@ -705,11 +744,18 @@ func (rq *replicateQueue) remove(
 		NodeID:  removeReplica.NodeID,
 		StoreID: removeReplica.StoreID,
 	}
-	desc, _ := repl.DescAndZone()
+	desc, zone := repl.DescAndZone()
+	ops := roachpb.MakeReplicationChanges(roachpb.REMOVE_REPLICA, target)
+	if rq.needsNonVoter(desc, zone, len(existingReplicas)-1, 0 /* promoted */) {
+		// Demote the voter instead of removing it, since the range is also
+		// missing a non-voting replica.
+		log.VEventf(ctx, 1, "demoting replica %+v to a non-voter", removeReplica)
+		ops = append(ops, roachpb.MakeReplicationChanges(roachpb.ADD_NON_VOTER, target)...)
+	}
 	if err := rq.changeReplicas(
 		ctx,
 		repl,
-		roachpb.MakeReplicationChanges(roachpb.REMOVE_REPLICA, target),
+		ops,
 		desc,
 		SnapshotRequest_UNKNOWN, // unused
 		kvserverpb.ReasonRangeOverReplicated,
@@ -725,7 +771,7 @@ func (rq *replicateQueue) removeDecommissioning(
 	ctx context.Context, repl *Replica, dryRun bool,
 ) (requeue bool, _ error) {
 	desc, _ := repl.DescAndZone()
-	decommissioningReplicas := rq.allocator.storePool.decommissioningReplicas(desc.Replicas().All())
+	decommissioningReplicas := rq.allocator.storePool.decommissioningReplicas(desc.Replicas().Voters())
 	if len(decommissioningReplicas) == 0 {
 		log.VEventf(ctx, 1, "range of replica %s was identified as having decommissioning replicas, "+
 			"but no decommissioning replicas were found", repl)
@@ -830,6 +876,93 @@ func (rq *replicateQueue) removeLearner(
 	return true, nil
 }
 
+// addNonVoter adds a non-voting replica to the range.
+func (rq *replicateQueue) addNonVoter(
+	ctx context.Context, repl *Replica, dryRun bool,
+) (requeue bool, _ error) {
+	desc, zone := repl.DescAndZone()
+	// Non-voters are never placed on a node that already has a replica of the
+	// range, voting or not.
+	newStore, details, err := rq.allocator.AllocateNonVoterTarget(ctx, zone, desc.Replicas().All())
+	if err != nil {
+		return false, err
+	}
+	target := roachpb.ReplicationTarget{
+		NodeID:  newStore.Node.NodeID,
+		StoreID: newStore.StoreID,
+	}
+	rq.metrics.AddReplicaCount.Inc(1)
+	log.VEventf(ctx, 1, "adding non-voter %+v", target)
+	if err := rq.changeReplicas(
+		ctx,
+		repl,
+		roachpb.MakeReplicationChanges(roachpb.ADD_NON_VOTER, target),
+		desc,
+		SnapshotRequest_RECOVERY,
+		kvserverpb.ReasonRangeUnderReplicated,
+		details,
+		dryRun,
+	); err != nil {
+		return false, err
+	}
+	// Always requeue to see if more work needs to be done.
+	return true, nil
+}
+
+// removeNonVoter removes the given non-voting replica from the range. Since
+// the leaseholder is always a voter, there's no need to consider transferring
+// the lease away first.
+func (rq *replicateQueue) removeNonVoter(
+	ctx context.Context,
+	repl *Replica,
+	removeReplica roachpb.ReplicaDescriptor,
+	reason kvserverpb.RangeLogEventReason,
+	details string,
+	dryRun bool,
+) (requeue bool, _ error) {
+	target := roachpb.ReplicationTarget{
+		NodeID:  removeReplica.NodeID,
+		StoreID: removeReplica.StoreID,
+	}
+	if err := rq.changeReplicas(
+		ctx,
+		repl,
+		roachpb.MakeReplicationChanges(roachpb.REMOVE_NON_VOTER, target),
+		repl.Desc(),
+		SnapshotRequest_UNKNOWN, // unused
+		reason,
+		details,
+		dryRun,
+	); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// needsNonVoter returns whether the range would be missing a non-voting
+// replica after a change that leaves it with numVoters voting replicas and
+// promotes the given number of its non-voting replicas.
+func (rq *replicateQueue) needsNonVoter(
+	desc *roachpb.RangeDescriptor, zone *zonepb.ZoneConfig, numVoters, promoted int,
+) bool {
+	clusterNodes := rq.allocator.storePool.ClusterNodeCount()
+	have := len(desc.Replicas().NonVoters()) - promoted
+	return GetNeededNonVoters(zone, numVoters, clusterNodes) > have
+}
+
+// voterAdditionChanges returns the changes that add a voting replica on the
+// given target. If the target already holds a non-voting replica of the range,
+// that replica is promoted instead.
+func voterAdditionChanges(
+	desc *roachpb.RangeDescriptor, target roachpb.ReplicationTarget,
+) []roachpb.ReplicationChange {
+	chgs := roachpb.MakeReplicationChanges(roachpb.ADD_REPLICA, target)
+	if rDesc, ok := desc.GetReplicaDescriptor(target.StoreID); ok && rDesc.GetType() == roachpb.NON_VOTER {
+		chgs = append(chgs, roachpb.MakeReplicationChanges(roachpb.REMOVE_NON_VOTER, target)...)
+	}
+	return chgs
+}
+
 func (rq *replicateQueue) considerRebalance(
 	ctx context.Context,
 	repl *Replica,
@@ -856,14 +989,20 @@ func (rq *replicateQueue) considerRebalance(
 			return false, nil
 		} else {
 			// We have a replica to remove and one we can add, so let's swap them
-			// out.
-			chgs := []roachpb.ReplicationChange{
-				// NB: we place the addition first because in the case of
-				// atomic replication changes being turned off, the changes
-				// will be executed individually in the order in which they
-				// appear.
-				{Target: addTarget, ChangeType: roachpb.ADD_REPLICA},
-				{Target: removeTarget, ChangeType: roachpb.REMOVE_REPLICA},
+			// out. If the new voter is promoted from a non-voter, the old one is
+			// demoted in its place.
+			//
+			// NB: we place the addition first because in the case of atomic
+			// replication changes being turned off, the changes will be executed
+			// individually in the order in which they appear.
+			addChgs := voterAdditionChanges(desc, addTarget)
+			chgs := append(addChgs, roachpb.ReplicationChange{
+				Target: removeTarget, ChangeType: roachpb.REMOVE_REPLICA,
+			})
+			if rq.needsNonVoter(desc, zone, len(existingReplicas), len(addChgs)-1) {
+				chgs = append(chgs, roachpb.ReplicationChange{
+					Target: removeTarget, ChangeType: roachpb.ADD_NON_VOTER,
+				})
 			}
 
 			if len(existingReplicas) == 1 {
@@ -888,7 +1027,7 @@ func (rq *replicateQueue) considerRebalance(
 				// when we know it's necessary, picking the smaller of two evils.
 				//
 				// See https://github.com/cockroachdb/cockroach/issues/40333.
-				chgs = chgs[:1]
+				chgs = addChgs
 				log.VEventf(ctx, 1, "can't swap replica due to lease; falling back to add")
 			}
 
@@ -910,6 +1049,31 @@ func (rq *replicateQueue) considerRebalance(
 			}
 			return true, nil
 		}
+
+		if addTarget, removeTarget, details, ok := rq.allocator.RebalanceNonVoterTarget(
+			ctx, zone, existingReplicas, desc.Replicas().NonVoters(), rangeUsageInfo,
+			storeFilterThrottled,
+		); ok {
+			rq.metrics.RebalanceReplicaCount.Inc(1)
+			log.VEventf(ctx, 1, "rebalancing non-voter %+v to %+v", removeTarget, addTarget)
+			chgs := []roachpb.ReplicationChange{
+				{Target: addTarget, ChangeType: roachpb.ADD_NON_VOTER},
+				{Target: removeTarget, ChangeType: roachpb.REMOVE_NON_VOTER},
+			}
+			if err := rq.changeReplicas(
+				ctx,
+				repl,
+				chgs,
+				desc,
+				SnapshotRequest_REBALANCE,
+				kvserverpb.ReasonRebalance,
+				details,
+				dryRun,
+			); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
 	}
 
 	if canTransferLease() {
@@ -295,6 +295,10 @@ func (s *SpanSet) CheckAllowedAt(
 func (s *SpanSet) checkAllowed(
 	access SpanAccess, span roachpb.Span, check func(SpanAccess, Span) bool,
 ) error {
+	if access == SpanReadOnly && isMVCCRangeTombstoneSpan(span) {
+		return nil
+	}
+
 	scope := SpanGlobal
 	if (span.Key != nil && keys.IsLocal(span.Key)) ||
 		(span.EndKey != nil && keys.IsLocal(span.EndKey)) {
@@ -312,6 +316,25 @@ func (s *SpanSet) checkAllowed(
 	return errors.Errorf("cannot %s undeclared span %s\ndeclared:\n%s\nstack:\n%s", access, span, s, debug.Stack())
 }
 
+// isMVCCRangeTombstoneSpan returns whether the span lies within the MVCC
+// range tombstone keyspace. MVCC reads consult the range tombstones covering
+// the keys they read without declaring their keys, which is safe because the
+// writers of range tombstones declare MVCC write access to the keys they
+// cover, so the latches of the covered keys serialize the two.
+func isMVCCRangeTombstoneSpan(span roachpb.Span) bool {
+	if span.Key == nil {
+		return span.EndKey.Compare(keys.LocalMVCCRangeTombstonePrefix) > 0 &&
+			span.EndKey.Compare(keys.LocalMVCCRangeTombstoneMax) <= 0
+	}
+	if span.Key.Compare(keys.LocalMVCCRangeTombstonePrefix) < 0 {
+		return false
+	}
+	if span.EndKey == nil {
+		return span.Key.Compare(keys.LocalMVCCRangeTombstoneMax) < 0
+	}
+	return span.EndKey.Compare(keys.LocalMVCCRangeTombstoneMax) <= 0
+}
+
 // contains returns whether s1 contains s2. Unlike Span.Contains, this function
 // supports spans with a nil start key and a non-nil end key (e.g. "[nil, c)").
 // In this form, s2.Key (inclusive) is considered to be the previous key to
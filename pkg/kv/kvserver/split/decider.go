@@ -22,20 +22,24 @@ import (
 
 const minSplitSuggestionInterval = time.Minute
 
-// A Decider collects measurements about the activity (measured in qps) on a
-// Replica and, assuming that qps thresholds are exceeded, tries to determine
-// a split key that would approximately result in halving the load on each of
-// the resultant ranges.
+// A Decider collects measurements about the activity (measured in qps, or in
+// some other unit of load such as CPU time or bytes written) on a Replica and,
+// assuming that thresholds are exceeded, tries to determine a split key that
+// would approximately result in halving the load on each of the resultant
+// ranges.
 //
 // Operations should call `Record` with a current timestamp. Operation counts
-// are aggregated over a second and a qps computed. If the QPS is above threshold,
-// a split finder is instantiated and the spans supplied to Record are sampled
-// for a duration (on the order of ten seconds). Assuming that load consistently
-// remains over threshold, and the workload touches a diverse enough set of keys
-// to benefit from a split, sampling will eventually instruct a caller of Record
-// to carry out a split. When the split is initiated, it can obtain the suggested
-// split point from MaybeSplitKey (which may have disappeared either due to a drop
-// in qps or a change in the workload).
+// (or amounts of load) are aggregated over a second and a qps computed. If the
+// QPS is above threshold, a split finder is instantiated and the spans supplied
+// to Record are sampled for a duration (on the order of ten seconds). Assuming
+// that load consistently remains over threshold, and the workload touches a
+// diverse enough set of keys to benefit from a split, sampling will eventually
+// instruct a caller of Record to carry out a split. When the split is
+// initiated, it can obtain the suggested split point from MaybeSplitKey (which
+// may have disappeared either due to a drop in qps or a change in the
+// workload). Note that the split finder balances the number of sampled
+// operations rather than their load, so for units other than requests the
+// suggested split point is only an approximation.
 type Decider struct {
 	intn         func(n int) int // supplied to Init
 	qpsThreshold func() float64  // supplied to Init
@@ -60,8 +64,8 @@ func Init(lbs *Decider, intn func(n int) int, qpsThreshold func() float64) {
 	lbs.qpsThreshold = qpsThreshold
 }
 
-// Record notifies the Decider that 'n' operations are being carried out which
-// operate on the span returned by the supplied method. The closure will only
+// Record notifies the Decider that 'n' operations (or units of load) are being
+// carried out which operate on the span returned by the supplied method. The closure will only
 // be called when necessary, that is, when the Decider is considering a split
 // and is sampling key spans to determine a suitable split point.
 //
@@ -189,3 +193,15 @@ func (d *Decider) Reset() {
 	d.mu.count = 0
 	d.mu.Unlock()
 }
+
+// Clear is like Reset, but it also discards the load measured so far, as if
+// the Decider had just been initialized.
+func (d *Decider) Clear() {
+	d.mu.Lock()
+	d.mu.lastQPSRollover = time.Time{}
+	d.mu.qps = 0
+	d.mu.count = 0
+	d.mu.splitFinder = nil
+	d.mu.lastSplitSuggestion = time.Time{}
+	d.mu.Unlock()
+}
@@ -244,3 +244,38 @@ func TestDeciderIgnoresEnsureSafeSplitKeyOnError(t *testing.T) {
 
 	require.Equal(t, c1().Key, k)
 }
+
+func TestDeciderClear(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	intn := rand.New(rand.NewSource(12)).Intn
+
+	var d Decider
+	Init(&d, intn, func() float64 { return 10.0 })
+
+	ms := func(i int) time.Time {
+		ts, err := time.Parse(time.RFC3339, "2000-01-01T00:00:00Z")
+		require.NoError(t, err)
+		return ts.Add(time.Duration(i) * time.Millisecond)
+	}
+	op := func(s string) func() roachpb.Span {
+		return func() roachpb.Span { return roachpb.Span{Key: roachpb.Key(s)} }
+	}
+
+	// Engage the split finder with load above the threshold.
+	d.Record(ms(0), 1, op("a"))
+	d.Record(ms(1000), 20, op("a"))
+	require.Equal(t, float64(20), d.LastQPS(ms(1000)))
+	require.NotNil(t, d.mu.splitFinder)
+
+	d.Clear()
+	require.Nil(t, d.mu.splitFinder)
+	require.Zero(t, d.mu.count)
+
+	// The recorded load is gone, and the next record starts measuring anew as
+	// after Init.
+	require.Equal(t, float64(0), d.LastQPS(ms(1500)))
+	require.False(t, d.Record(ms(1600), 5, op("a")))
+	require.Equal(t, float64(0), d.LastQPS(ms(1600)))
+	require.Nil(t, d.mu.splitFinder)
+}
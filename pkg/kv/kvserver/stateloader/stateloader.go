@@ -507,10 +507,14 @@ func (rsl StateLoader) SetGCThreshold(
 
 // The rest is not technically part of ReplicaState.
 
-// LoadLastIndex loads the last index.
-func (rsl StateLoader) LoadLastIndex(ctx context.Context, reader storage.Reader) (uint64, error) {
+// LoadLastIndex loads the last index. The Raft log is read from raftReader,
+// while the truncated state is read from stateReader; the two are the same
+// unless the Raft log is kept in a separate engine.
+func (rsl StateLoader) LoadLastIndex(
+	ctx context.Context, raftReader, stateReader storage.Reader,
+) (uint64, error) {
 	prefix := rsl.RaftLogPrefix()
-	iter := reader.NewIterator(storage.IterOptions{LowerBound: prefix})
+	iter := raftReader.NewIterator(storage.IterOptions{LowerBound: prefix})
 	defer iter.Close()
 
 	var lastIndex uint64
@@ -527,7 +531,7 @@ func (rsl StateLoader) LoadLastIndex(ctx context.Context, reader storage.Reader)
 	if lastIndex == 0 {
 		// The log is empty, which means we are either starting from scratch
 		// or the entire log has been truncated away.
-		lastEnt, _, err := rsl.LoadRaftTruncatedState(ctx, reader)
+		lastEnt, _, err := rsl.LoadRaftTruncatedState(ctx, stateReader)
 		if err != nil {
 			return 0, err
 		}
@@ -60,6 +60,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/limit"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
@@ -127,11 +128,23 @@ var addSSTableRequestLimit = settings.RegisterPositiveIntSetting(
 	1,
 )
 
-// concurrentRangefeedItersLimit limits concurrent rangefeed catchup iterators.
+// concurrentRangefeedItersLimit limits concurrent rangefeed catchup iterators,
+// and thereby the number of catch-up scans a store runs concurrently. Catch-up
+// scans buffer their output in the node's rangefeed memory budget, so running
+// many of them at once exhausts it; registrations beyond the limit queue
+// instead.
 var concurrentRangefeedItersLimit = settings.RegisterPositiveIntSetting(
 	"kv.rangefeed.concurrent_catchup_iterators",
 	"number of rangefeeds catchup iterators a store will allow concurrently before queueing",
-	64,
+	16,
+)
+
+// rangefeedCatchupScanRate limits the rate at which rangefeed catchup scans
+// emit data.
+var rangefeedCatchupScanRate = settings.RegisterByteSizeSetting(
+	"kv.rangefeed.catchup_scan_max_rate",
+	"the rate limit (bytes/sec) for the data emitted by the rangefeed catchup scans of a store",
+	1<<40,
 )
 
 // Minimum time interval between system config updates which will lead to
@@ -402,6 +415,7 @@ type Store struct {
 	cfg                StoreConfig
 	db                 *kv.DB
 	engine             storage.Engine       // The underlying key-value store
+	raftEngine         storage.Engine       // Holds the Raft log and HardState; may be engine
 	compactor          *compactor.Compactor // Schedules compaction of the engine
 	tsCache            tscache.Cache        // Most recent timestamps for keys / key ranges
 	allocator          Allocator            // Makes allocation decisions
@@ -732,6 +746,16 @@ type StoreConfig struct {
 	// subsystem. It is queried during the GC process and in the handling of
 	// AdminVerifyProtectedTimestampRequest.
 	ProtectedTimestampCache protectedts.Cache
+
+	// RaftEngines maps a store's engine to the dedicated engine holding that
+	// store's Raft log and HardState. Stores whose engine is not present keep
+	// their Raft log in the same engine as the state machine.
+	RaftEngines map[storage.Engine]storage.Engine
+
+	// RangefeedMemMonitor, if set, is the node-level monitor accounting for
+	// the memory used by rangefeed registrations' buffered events and
+	// catch-up scans.
+	RangefeedMemMonitor *mon.BytesMonitor
 }
 
 // ConsistencyTestingKnobs is a BatchEvalTestingKnobs struct used to control the
@@ -807,6 +831,10 @@ func NewStore(
 		nodeDesc: nodeDesc,
 		metrics:  newStoreMetrics(cfg.HistogramWindowInterval),
 	}
+	s.raftEngine = eng
+	if raftEng, ok := cfg.RaftEngines[eng]; ok {
+		s.raftEngine = raftEng
+	}
 	if cfg.RPCContext != nil {
 		s.allocator = MakeAllocator(cfg.StorePool, cfg.RPCContext.RemoteClocks.Latency)
 	} else {
@@ -918,6 +946,15 @@ func NewStore(
 		s.limiters.ConcurrentRangefeedIters.SetLimit(
 			int(concurrentRangefeedItersLimit.Get(&cfg.Settings.SV)))
 	})
+	s.limiters.RangefeedCatchupScanRate = quotapool.NewRateLimiter(
+		"rangefeedCatchupScanLimiter",
+		quotapool.Limit(rangefeedCatchupScanRate.Get(&cfg.Settings.SV)),
+		rangefeedCatchupScanRate.Get(&cfg.Settings.SV))
+	rangefeedCatchupScanRate.SetOnChange(&cfg.Settings.SV, func() {
+		s.limiters.RangefeedCatchupScanRate.UpdateLimit(
+			quotapool.Limit(rangefeedCatchupScanRate.Get(&cfg.Settings.SV)),
+			rangefeedCatchupScanRate.Get(&cfg.Settings.SV))
+	})
 
 	s.tenantRateLimiters = tenantrate.NewLimiterFactory(cfg.Settings, &cfg.TestingKnobs.TenantRateKnobs)
 	s.metrics.registry.AddMetricStruct(s.tenantRateLimiters.Metrics())
@@ -1411,6 +1448,12 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	ctx = s.AnnotateCtx(ctx)
 	log.Event(ctx, "read store identity")
 
+	// Move the Raft log into the separate Raft engine if one was configured
+	// and this store hasn't been migrated yet.
+	if err := s.maybeSeparateRaftLog(ctx); err != nil {
+		return err
+	}
+
 	// Add the store ID to the scanner's AmbientContext before starting it, since
 	// the AmbientContext provided during construction did not include it.
 	// Note that this is just a hacky way of getting around that without
@@ -1544,6 +1587,14 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 		return err
 	}
 
+	// Remove Raft state left behind in the Raft engine by replicas whose
+	// destruction was interrupted by a crash.
+	if s.raftLogSeparated() {
+		if err := s.sweepRaftEngine(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Start Raft processing goroutines.
 	s.cfg.Transport.Listen(s.StoreID(), s)
 	s.processRaft(ctx)
@@ -2274,6 +2325,15 @@ func (s *Store) Clock() *hlc.Clock { return s.cfg.Clock }
 // Engine accessor.
 func (s *Store) Engine() storage.Engine { return s.engine }
 
+// RaftEngine accessor. This is the engine holding the Raft log and HardState
+// of the store's replicas, which is the store's Engine unless a separate Raft
+// engine was configured.
+func (s *Store) RaftEngine() storage.Engine { return s.raftEngine }
+
+// raftLogSeparated returns whether the store keeps its Raft log in a separate
+// engine.
+func (s *Store) raftLogSeparated() bool { return s.raftEngine != s.engine }
+
 // DB accessor.
 func (s *Store) DB() *kv.DB { return s.cfg.DB }
 
@@ -2343,6 +2403,8 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	var logicalBytes int64
 	var totalQueriesPerSecond float64
 	var totalWritesPerSecond float64
+	var totalCPUPerSecond float64
+	var totalWriteBytesPerSecond float64
 	replicaCount := s.metrics.ReplicaCount.Value()
 	bytesPerReplica := make([]float64, 0, replicaCount)
 	writesPerReplica := make([]float64, 0, replicaCount)
@@ -2369,9 +2431,21 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 			totalWritesPerSecond += wps
 			writesPerReplica = append(writesPerReplica, wps)
 		}
+		var cpu float64
+		if avgCPU, dur := r.cpuStats.avgQPS(); dur >= MinStatsDuration {
+			cpu = avgCPU
+			totalCPUPerSecond += avgCPU
+		}
+		var writeBytes float64
+		if wbps, dur := r.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+			writeBytes = wbps
+			totalWriteBytesPerSecond += wbps
+		}
 		rankingsAccumulator.addReplica(replicaWithStats{
-			repl: r,
-			qps:  qps,
+			repl:       r,
+			qps:        qps,
+			cpu:        cpu,
+			writeBytes: writeBytes,
 		})
 		return true
 	})
@@ -2380,6 +2454,8 @@ func (s *Store) Capacity(ctx context.Context, useCached bool) (roachpb.StoreCapa
 	capacity.LogicalBytes = logicalBytes
 	capacity.QueriesPerSecond = totalQueriesPerSecond
 	capacity.WritesPerSecond = totalWritesPerSecond
+	capacity.CPUPerSecond = totalCPUPerSecond
+	capacity.WriteBytesPerSecond = totalWriteBytesPerSecond
 	capacity.BytesPerReplica = roachpb.PercentilesFromData(bytesPerReplica)
 	capacity.WritesPerReplica = roachpb.PercentilesFromData(writesPerReplica)
 	s.recordNewPerSecondStats(totalQueriesPerSecond, totalWritesPerSecond)
@@ -2485,6 +2561,8 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		quiescentCount                int64
 		averageQueriesPerSecond       float64
 		averageWritesPerSecond        float64
+		averageCPUNanosPerSecond      float64
+		averageWriteBytesPerSecond    float64
 
 		rangeCount                int64
 		unavailableRangeCount     int64
@@ -2541,6 +2619,12 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 		if wps, dur := rep.writeStats.avgQPS(); dur >= MinStatsDuration {
 			averageWritesPerSecond += wps
 		}
+		if cpu, dur := rep.cpuStats.avgQPS(); dur >= MinStatsDuration {
+			averageCPUNanosPerSecond += cpu
+		}
+		if wbps, dur := rep.writeBytesStats.avgQPS(); dur >= MinStatsDuration {
+			averageWriteBytesPerSecond += wbps
+		}
 		mc, ok := rep.maxClosed(ctx)
 		if ok && (minMaxClosedTS.IsEmpty() || mc.Less(minMaxClosedTS)) {
 			minMaxClosedTS = mc
@@ -2556,6 +2640,8 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	s.metrics.QuiescentCount.Update(quiescentCount)
 	s.metrics.AverageQueriesPerSecond.Update(averageQueriesPerSecond)
 	s.metrics.AverageWritesPerSecond.Update(averageWritesPerSecond)
+	s.metrics.AverageCPUNanosPerSecond.Update(averageCPUNanosPerSecond)
+	s.metrics.AverageWriteBytesPerSecond.Update(averageWriteBytesPerSecond)
 	s.recordNewPerSecondStats(averageQueriesPerSecond, averageWritesPerSecond)
 
 	s.metrics.RangeCount.Update(rangeCount)
@@ -67,6 +67,12 @@ func (s *Store) MergeRange(
 		// logic that depends on them.
 		leftRepl.writeStats.resetRequestCounts()
 	}
+	if leftRepl.writeBytesStats != nil {
+		leftRepl.writeBytesStats.resetRequestCounts()
+	}
+	if leftRepl.cpuStats != nil {
+		leftRepl.cpuStats.resetRequestCounts()
+	}
 
 	// Clear the concurrency manager's lock and txn wait-queues to redirect the
 	// queued transactions to the left-hand replica, if necessary.
@@ -373,12 +373,12 @@ func (sp *StorePool) updateLocalStoreAfterRebalance(
 		// network). We can't update the local store at this time.
 		return
 	}
-	switch changeType {
-	case roachpb.ADD_REPLICA:
+	switch {
+	case changeType.IsAddition():
 		detail.desc.Capacity.RangeCount++
 		detail.desc.Capacity.LogicalBytes += rangeUsageInfo.LogicalBytes
 		detail.desc.Capacity.WritesPerSecond += rangeUsageInfo.WritesPerSecond
-	case roachpb.REMOVE_REPLICA:
+	case changeType.IsRemoval():
 		detail.desc.Capacity.RangeCount--
 		if detail.desc.Capacity.LogicalBytes <= rangeUsageInfo.LogicalBytes {
 			detail.desc.Capacity.LogicalBytes = 0
@@ -574,6 +574,14 @@ type StoreList struct {
 	// candidateWritesPerSecond tracks writes-per-second stats for stores that are
 	// eligible to be rebalance targets.
 	candidateWritesPerSecond stat
+
+	// candidateCPUPerSecond tracks evaluation time stats, in nanoseconds per
+	// second, for stores that are eligible to be rebalance targets.
+	candidateCPUPerSecond stat
+
+	// candidateWriteBytesPerSecond tracks bytes-written-per-second stats for
+	// stores that are eligible to be rebalance targets.
+	candidateWriteBytesPerSecond stat
 }
 
 // Generates a new store list based on the passed in descriptors. It will
@@ -588,6 +596,8 @@ func makeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 		sl.candidateLogicalBytes.update(float64(desc.Capacity.LogicalBytes))
 		sl.candidateQueriesPerSecond.update(desc.Capacity.QueriesPerSecond)
 		sl.candidateWritesPerSecond.update(desc.Capacity.WritesPerSecond)
+		sl.candidateCPUPerSecond.update(desc.Capacity.CPUPerSecond)
+		sl.candidateWriteBytesPerSecond.update(desc.Capacity.WriteBytesPerSecond)
 	}
 	return sl
 }
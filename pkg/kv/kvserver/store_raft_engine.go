@@ -0,0 +1,319 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"go.etcd.io/etcd/raft"
+)
+
+// A store can keep the Raft log and HardState of its replicas in a Raft
+// engine of their own (see StoreConfig.RaftEngines), so that log appends and
+// truncations don't compete with the compactions of the state machine. All
+// other replica state, including the RaftTruncatedState and the applied
+// state, stays in the store's engine.
+//
+// Writes to the two engines can't share a batch, so their order is what keeps
+// them consistent across crashes:
+//
+// - Log entries and the HardState are synced to the Raft engine before the
+//   entries are applied to the state machine. The commit index isn't always
+//   synced, so after a crash it may trail the applied index; InitialState
+//   accounts for that.
+// - Log entries are removed from the Raft engine only once the batch moving
+//   the TruncatedState past them has been synced.
+// - Snapshots and splits write the replica's new HardState into the state
+//   engine, atomically with the rest of the new replica state. The HardState
+//   is then handed off to the Raft engine, replacing the log there. A crash
+//   before the handoff completes is repaired when the replica is next loaded.
+// - The Raft state of a destroyed replica is removed from the Raft engine
+//   only after the removal of its data has been synced. State left behind by
+//   a crash is removed by sweepRaftEngine when the store starts.
+//
+// Moving the Raft log of an existing store into a Raft engine is one-way. It
+// requires VersionSeparatedRaftLog, so that older binaries refuse to open the
+// store afterwards.
+
+// maybeSeparateRaftLog checks that the store's Raft log lives where the
+// store's configuration says, moving it into the separate Raft engine if one
+// was configured and the store still keeps its Raft log in its own engine.
+// Moving the Raft log back is not supported.
+func (s *Store) maybeSeparateRaftLog(ctx context.Context) error {
+	var separatedAt hlc.Timestamp
+	separated, err := storage.MVCCGetProto(ctx, s.engine, keys.StoreRaftLogSeparatedKey(),
+		hlc.Timestamp{}, &separatedAt, storage.MVCCGetOptions{})
+	if err != nil {
+		return err
+	}
+	if !s.raftLogSeparated() {
+		if separated {
+			return errors.Errorf("%s keeps its Raft log in a separate engine since %s, "+
+				"but no Raft engine was configured", s, separatedAt)
+		}
+		return nil
+	}
+
+	raftIdent, err := ReadStoreIdent(ctx, s.raftEngine)
+	if err != nil && !errors.HasType(err, (*NotBootstrappedError)(nil)) {
+		return err
+	}
+	if err == nil && raftIdent != *s.Ident {
+		return errors.Errorf("Raft engine of %s belongs to store %+v", s, raftIdent)
+	}
+	if separated {
+		if err != nil {
+			return errors.Wrapf(err, "Raft engine of %s", s)
+		}
+		return nil
+	}
+
+	// Binaries which don't know about separate Raft engines would not find the
+	// Raft log once it has been moved. They refuse to open a store whose
+	// persisted cluster version is ahead of their own, so the store's cluster
+	// version must have reached VersionSeparatedRaftLog first.
+	if cv, err := ReadClusterVersion(ctx, s.engine); err != nil {
+		return err
+	} else if minVersion := clusterversion.VersionByKey(
+		clusterversion.VersionSeparatedRaftLog,
+	); cv.Version.Less(minVersion) {
+		return errors.Errorf("%s cannot move its Raft log into a separate Raft engine "+
+			"at cluster version %s; all nodes must be upgraded to %s first", s, cv, minVersion)
+	}
+
+	log.Infof(ctx, "moving Raft log into separate Raft engine")
+	if err := storage.MVCCPutProto(ctx, s.raftEngine, nil, keys.StoreIdentKey(),
+		hlc.Timestamp{}, nil, s.Ident); err != nil {
+		return err
+	}
+	var moved int
+	if err := iterateRangeIDs(s.engine, func(rangeID roachpb.RangeID) error {
+		moved++
+		return moveRaftState(s.engine, s.raftEngine, rangeID)
+	}); err != nil {
+		return errors.Wrap(err, "while moving Raft log into separate Raft engine")
+	}
+
+	// The marker is written synchronously, which also makes the removal of the
+	// moved Raft state from the store's engine durable.
+	batch := s.engine.NewWriteOnlyBatch()
+	defer batch.Close()
+	separatedAt = s.cfg.Clock.Now()
+	if err := storage.MVCCPutProto(ctx, batch, nil, keys.StoreRaftLogSeparatedKey(),
+		hlc.Timestamp{}, nil, &separatedAt); err != nil {
+		return err
+	}
+	if err := batch.Commit(true); err != nil {
+		return err
+	}
+	log.Infof(ctx, "moved Raft state of %d ranges into separate Raft engine", moved)
+	return nil
+}
+
+// iterateRangeIDs calls fn for each range ID which has range ID local data
+// in the given reader.
+func iterateRangeIDs(reader storage.Reader, fn func(roachpb.RangeID) error) error {
+	start := keys.LocalRangeIDPrefix.AsRawKey()
+	end := keys.LocalRangeIDPrefix.PrefixEnd().AsRawKey()
+	iter := reader.NewIterator(storage.IterOptions{UpperBound: end})
+	defer iter.Close()
+	iter.SeekGE(storage.MakeMVCCMetadataKey(start))
+	for {
+		if ok, err := iter.Valid(); err != nil || !ok {
+			return err
+		}
+		rangeID, _, _, _, err := keys.DecodeRangeIDKey(iter.UnsafeKey().Key)
+		if err != nil {
+			return err
+		}
+		if err := fn(rangeID); err != nil {
+			return err
+		}
+		iter.SeekGE(storage.MakeMVCCMetadataKey(keys.MakeRangeIDPrefix(rangeID + 1)))
+	}
+}
+
+// moveRaftState moves the Raft log and HardState of the given range from the
+// store's engine into the Raft engine. The Raft engine is synced before the
+// state is removed from the store's engine, so an interrupted move can simply
+// be retried.
+func moveRaftState(eng, raftEng storage.Engine, rangeID roachpb.RangeID) error {
+	prefixBuf := keys.MakeRangeIDPrefixBuf(rangeID)
+	hsKey := prefixBuf.RaftHardStateKey()
+	logPrefix := prefixBuf.RaftLogPrefix()
+	spans := []roachpb.Span{
+		{Key: hsKey, EndKey: hsKey.Next()},
+		{Key: logPrefix, EndKey: logPrefix.PrefixEnd()},
+	}
+
+	raftBatch := raftEng.NewWriteOnlyBatch()
+	defer raftBatch.Close()
+	batch := eng.NewWriteOnlyBatch()
+	defer batch.Close()
+	for _, span := range spans {
+		if err := func() error {
+			iter := eng.NewIterator(storage.IterOptions{UpperBound: span.EndKey})
+			defer iter.Close()
+			for iter.SeekGE(storage.MakeMVCCMetadataKey(span.Key)); ; iter.Next() {
+				if ok, err := iter.Valid(); err != nil || !ok {
+					return err
+				}
+				key := iter.Key()
+				if err := raftBatch.Put(key, iter.Value()); err != nil {
+					return err
+				}
+				if err := batch.Clear(key); err != nil {
+					return err
+				}
+			}
+		}(); err != nil {
+			return err
+		}
+	}
+	if raftBatch.Empty() {
+		return nil
+	}
+	if err := raftBatch.Commit(true); err != nil {
+		return err
+	}
+	return batch.Commit(false)
+}
+
+// clearRaftEngineState writes the removal of the Raft log and HardState of
+// the given range from the Raft engine to the writer.
+func clearRaftEngineState(
+	raftEng storage.Reader, writer storage.Writer, rangeID roachpb.RangeID,
+) error {
+	prefix := keys.MakeRangeIDUnreplicatedPrefix(rangeID)
+	iter := raftEng.NewIterator(storage.IterOptions{UpperBound: prefix.PrefixEnd()})
+	defer iter.Close()
+	return writer.ClearIterRange(iter, prefix, prefix.PrefixEnd())
+}
+
+// sweepRaftEngine removes Raft state which was left behind in the Raft engine
+// by replicas whose destruction was interrupted by a crash. It must be called
+// after all initialized replicas have been loaded. Uninitialized replicas
+// only ever keep a HardState with a zero commit index and no log entries, so
+// any other state without an initialized replica is stale.
+func (s *Store) sweepRaftEngine(ctx context.Context) error {
+	var swept int
+	if err := iterateRangeIDs(s.raftEngine, func(rangeID roachpb.RangeID) error {
+		if _, ok := s.mu.replicas.Load(int64(rangeID)); ok {
+			return nil
+		}
+		rsl := stateloader.Make(rangeID)
+		hs, err := rsl.LoadHardState(ctx, s.raftEngine)
+		if err != nil {
+			return err
+		}
+		if hs.Commit == 0 {
+			logPrefix := rsl.RaftLogPrefix()
+			iter := s.raftEngine.NewIterator(storage.IterOptions{UpperBound: logPrefix.PrefixEnd()})
+			iter.SeekGE(storage.MakeMVCCMetadataKey(logPrefix))
+			hasLog, err := iter.Valid()
+			iter.Close()
+			if err != nil || !hasLog {
+				return err
+			}
+		}
+		batch := s.raftEngine.NewWriteOnlyBatch()
+		defer batch.Close()
+		if err := clearRaftEngineState(s.raftEngine, batch, rangeID); err != nil {
+			return err
+		}
+		swept++
+		return batch.Commit(true)
+	}); err != nil {
+		return errors.Wrap(err, "while sweeping Raft engine")
+	}
+	if swept > 0 {
+		log.Infof(ctx, "removed stale Raft state of %d ranges from Raft engine", swept)
+	}
+	return nil
+}
+
+// handOffRaftStateRaftMuLocked moves a HardState which a snapshot or split
+// wrote into the store's engine over to the Raft engine, replacing the
+// replica's Raft log there. It is a no-op if there is no such HardState,
+// which is always the case unless the store's Raft log is separated.
+func (r *Replica) handOffRaftStateRaftMuLocked(
+	ctx context.Context, rsl stateloader.StateLoader,
+) error {
+	hs, err := rsl.LoadHardState(ctx, r.store.Engine())
+	if err != nil || raft.IsEmptyHardState(hs) {
+		return err
+	}
+	raftEng := r.store.RaftEngine()
+	oldHS, err := rsl.LoadHardState(ctx, raftEng)
+	if err != nil {
+		return err
+	}
+	// The replica may have voted at a later term in the meantime, for example
+	// while it was the uninitialized right-hand side of a split. Neither the
+	// term nor a vote may ever regress.
+	if oldHS.Term > hs.Term {
+		hs.Term, hs.Vote = oldHS.Term, oldHS.Vote
+	} else if oldHS.Term == hs.Term && hs.Vote == 0 {
+		hs.Vote = oldHS.Vote
+	}
+
+	raftBatch := raftEng.NewWriteOnlyBatch()
+	defer raftBatch.Close()
+	if err := clearRaftEngineState(raftEng, raftBatch, r.RangeID); err != nil {
+		return err
+	}
+	if err := rsl.SetHardState(ctx, raftBatch, hs); err != nil {
+		return err
+	}
+	if err := raftBatch.Commit(true); err != nil {
+		return err
+	}
+
+	// Remove the HardState from the store's engine only now that the Raft
+	// engine durably reflects it. The removal is synced: a handoff repeated
+	// after a crash would discard log entries appended in the meantime.
+	batch := r.store.Engine().NewWriteOnlyBatch()
+	defer batch.Close()
+	if err := batch.Clear(storage.MakeMVCCMetadataKey(rsl.RaftHardStateKey())); err != nil {
+		return err
+	}
+	return batch.Commit(true)
+}
+
+// truncateRaftEngineLogRaftMuLocked removes the log entries up to and
+// including the given truncated state's index from the Raft engine. It must
+// only be called once the truncated state has been synced to the store's
+// engine. Entries left behind by earlier truncations that were interrupted
+// by a crash are removed as well, so the removal itself needn't be synced.
+func (r *Replica) truncateRaftEngineLogRaftMuLocked(ts *roachpb.RaftTruncatedState) error {
+	if fn := r.store.cfg.TestingKnobs.BeforeRaftEngineTruncation; fn != nil {
+		fn(r.RangeID, *ts)
+	}
+	raftEng := r.store.RaftEngine()
+	prefix := keys.RaftLogPrefix(r.RangeID)
+	end := keys.RaftLogKey(r.RangeID, ts.Index+1)
+	batch := raftEng.NewWriteOnlyBatch()
+	defer batch.Close()
+	iter := raftEng.NewIterator(storage.IterOptions{UpperBound: end})
+	defer iter.Close()
+	if err := batch.ClearIterRange(iter, prefix, end); err != nil {
+		return err
+	}
+	return batch.Commit(false)
+}
@@ -0,0 +1,425 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/bootstrap"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft/raftpb"
+)
+
+// putRaftLog writes log entries with indexes lo through hi to the writer.
+func putRaftLog(
+	t *testing.T, rw storage.ReadWriter, rangeID roachpb.RangeID, lo, hi, term uint64,
+) {
+	for i := lo; i <= hi; i++ {
+		require.NoError(t, storage.MVCCPutProto(context.Background(), rw, nil,
+			keys.RaftLogKey(rangeID, i), hlc.Timestamp{}, nil, &raftpb.Entry{Index: i, Term: term}))
+	}
+}
+
+// raftLogIndexes returns the indexes of the log entries of the given range
+// found in the reader.
+func raftLogIndexes(t *testing.T, reader storage.Reader, rangeID roachpb.RangeID) []uint64 {
+	var indexes []uint64
+	require.NoError(t, iterateEntries(context.Background(), reader, rangeID, 0, math.MaxUint64,
+		func(kv roachpb.KeyValue) error {
+			var ent raftpb.Entry
+			if err := kv.Value.GetProto(&ent); err != nil {
+				return err
+			}
+			indexes = append(indexes, ent.Index)
+			return nil
+		}))
+	return indexes
+}
+
+func loadHardState(t *testing.T, reader storage.Reader, rangeID roachpb.RangeID) raftpb.HardState {
+	hs, err := stateloader.Make(rangeID).LoadHardState(context.Background(), reader)
+	require.NoError(t, err)
+	return hs
+}
+
+// bootstrapTestEngine bootstraps a store with a single range on the engine.
+func bootstrapTestEngine(t *testing.T, cfg *StoreConfig, eng storage.Engine) {
+	ctx := context.Background()
+	require.NoError(t, WriteClusterVersion(ctx, eng, clusterversion.TestingClusterVersion))
+	require.NoError(t, InitEngine(ctx, eng, testIdent))
+	kvs, _ := bootstrap.MakeMetadataSchema(
+		keys.SystemSQLCodec, cfg.DefaultZoneConfig, cfg.DefaultSystemZoneConfig,
+	).GetInitialValues()
+	require.NoError(t, WriteInitialClusterData(
+		ctx, eng, kvs /* initialValues */, clusterversion.TestingBinaryVersion,
+		1 /* numStores */, nil /* splits */, cfg.Clock.PhysicalNow(),
+	))
+}
+
+// newTestStoreWithRaftEngine creates a store on the given engine, keeping its
+// Raft log in raftEng unless it is nil. The store isn't started.
+func newTestStoreWithRaftEngine(
+	t *testing.T, stopper *stop.Stopper, cfg StoreConfig, eng, raftEng storage.Engine,
+) *Store {
+	config.TestingSetupZoneConfigHook(stopper)
+	rpcContext := rpc.NewContext(rpc.ContextOptions{
+		TenantID:   roachpb.SystemTenantID,
+		AmbientCtx: cfg.AmbientCtx,
+		Config:     &base.Config{Insecure: true},
+		Clock:      cfg.Clock,
+		Stopper:    stopper,
+		Settings:   cfg.Settings,
+	})
+	server := rpc.NewServer(rpcContext) // never started
+	cfg.Gossip = gossip.NewTest(1, rpcContext, server, stopper, metric.NewRegistry(), cfg.DefaultZoneConfig)
+	cfg.StorePool = NewTestStorePool(cfg)
+	cfg.TestingKnobs.DisableScanner = true
+	cfg.TestingKnobs.DisableSplitQueue = true
+	cfg.TestingKnobs.DisableMergeQueue = true
+	cfg.Transport = NewDummyRaftTransport(cfg.Settings)
+	if raftEng != nil {
+		cfg.RaftEngines = map[storage.Engine]storage.Engine{eng: raftEng}
+	}
+	factory := &testSenderFactory{}
+	cfg.DB = kv.NewDB(cfg.AmbientCtx, factory, cfg.Clock, stopper)
+	store := NewStore(context.Background(), cfg, eng, &roachpb.NodeDescriptor{NodeID: 1})
+	factory.setStore(store)
+	require.NoError(t, store.Gossip().AddInfoProto(gossip.KeySystemConfig,
+		&config.SystemConfigEntries{}, 0))
+	return store
+}
+
+// TestMoveRaftState verifies that moveRaftState moves the Raft log and
+// HardState of a range, and only those, into the Raft engine, and that an
+// interrupted move can be retried.
+func TestMoveRaftState(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+	raftEng := storage.NewDefaultInMem()
+	defer raftEng.Close()
+
+	const rangeID = roachpb.RangeID(3)
+	hs := raftpb.HardState{Term: 6, Vote: 1, Commit: 12}
+	require.NoError(t, stateloader.Make(rangeID).SetHardState(ctx, eng, hs))
+	putRaftLog(t, eng, rangeID, 11, 15, 6)
+	// Other range ID local state stays in the store's engine, as does the Raft
+	// state of other ranges.
+	gcKey := keys.RangeLastReplicaGCTimestampKey(rangeID)
+	gcTS := hlc.Timestamp{WallTime: 123}
+	require.NoError(t, storage.MVCCPutProto(ctx, eng, nil, gcKey, hlc.Timestamp{}, nil, &gcTS))
+	putRaftLog(t, eng, rangeID+1, 1, 2, 1)
+
+	// A move interrupted after the Raft engine was synced left the Raft state
+	// in both engines.
+	putRaftLog(t, raftEng, rangeID, 11, 13, 6)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, moveRaftState(eng, raftEng, rangeID))
+		require.Equal(t, hs, loadHardState(t, raftEng, rangeID))
+		require.Equal(t, []uint64{11, 12, 13, 14, 15}, raftLogIndexes(t, raftEng, rangeID))
+		require.Equal(t, raftpb.HardState{}, loadHardState(t, eng, rangeID))
+		require.Empty(t, raftLogIndexes(t, eng, rangeID))
+		require.Equal(t, []uint64{1, 2}, raftLogIndexes(t, eng, rangeID+1))
+		var gcTSAfter hlc.Timestamp
+		ok, err := storage.MVCCGetProto(ctx, eng, gcKey, hlc.Timestamp{}, &gcTSAfter, storage.MVCCGetOptions{})
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, gcTS, gcTSAfter)
+	}
+}
+
+// TestHandOffRaftState verifies that the HardState written to the store's
+// engine by a snapshot or split replaces the Raft state in the Raft engine,
+// including when an earlier handoff was interrupted, and that the term and
+// vote never regress.
+func TestHandOffRaftState(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	const rangeID = roachpb.RangeID(3)
+	rsl := stateloader.Make(rangeID)
+	oldHS := raftpb.HardState{Term: 6, Vote: 1, Commit: 12}
+	newHS := raftpb.HardState{Term: 7, Vote: 2, Commit: 20}
+
+	for _, tc := range []struct {
+		name string
+		// raftHS and raftLog are the Raft state in the Raft engine before the
+		// handoff.
+		raftHS  raftpb.HardState
+		raftLog bool
+		// hs is the HardState written by the snapshot or split.
+		hs    raftpb.HardState
+		expHS raftpb.HardState
+	}{
+		{name: "before handoff", raftHS: oldHS, raftLog: true, hs: newHS, expHS: newHS},
+		// A handoff interrupted after the Raft engine was synced left the
+		// HardState in both engines.
+		{name: "interrupted handoff", raftHS: newHS, hs: newHS, expHS: newHS},
+		// The uninitialized right-hand side of a split voted at a later term.
+		{
+			name:   "later term",
+			raftHS: raftpb.HardState{Term: 8, Vote: 3},
+			hs:     newHS,
+			expHS:  raftpb.HardState{Term: 8, Vote: 3, Commit: 20},
+		},
+		{
+			name:   "same term without vote",
+			raftHS: raftpb.HardState{Term: 7, Vote: 3},
+			hs:     raftpb.HardState{Term: 7, Commit: 20},
+			expHS:  raftpb.HardState{Term: 7, Vote: 3, Commit: 20},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stopper := stop.NewStopper()
+			defer stopper.Stop(ctx)
+			eng := storage.NewDefaultInMem()
+			stopper.AddCloser(eng)
+			raftEng := storage.NewDefaultInMem()
+			stopper.AddCloser(raftEng)
+			cfg := TestStoreConfig(nil)
+			s := newTestStoreWithRaftEngine(t, stopper, cfg, eng, raftEng)
+			r := &Replica{RangeID: rangeID, store: s}
+
+			require.NoError(t, rsl.SetHardState(ctx, raftEng, tc.raftHS))
+			if tc.raftLog {
+				putRaftLog(t, raftEng, rangeID, 11, 15, 6)
+			}
+			require.NoError(t, rsl.SetHardState(ctx, eng, tc.hs))
+
+			require.NoError(t, r.handOffRaftStateRaftMuLocked(ctx, rsl))
+			require.Equal(t, tc.expHS, loadHardState(t, raftEng, rangeID))
+			require.Empty(t, raftLogIndexes(t, raftEng, rangeID))
+			require.Equal(t, raftpb.HardState{}, loadHardState(t, eng, rangeID))
+
+			// Without a HardState in the store's engine, the handoff is a no-op.
+			putRaftLog(t, raftEng, rangeID, 21, 22, tc.expHS.Term)
+			require.NoError(t, r.handOffRaftStateRaftMuLocked(ctx, rsl))
+			require.Equal(t, tc.expHS, loadHardState(t, raftEng, rangeID))
+			require.Equal(t, []uint64{21, 22}, raftLogIndexes(t, raftEng, rangeID))
+		})
+	}
+}
+
+// TestTruncateRaftEngineLog verifies that truncating the log in the Raft
+// engine also removes the entries left behind by an interrupted earlier
+// truncation.
+func TestTruncateRaftEngineLog(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	eng := storage.NewDefaultInMem()
+	stopper.AddCloser(eng)
+	raftEng := storage.NewDefaultInMem()
+	stopper.AddCloser(raftEng)
+	cfg := TestStoreConfig(nil)
+	s := newTestStoreWithRaftEngine(t, stopper, cfg, eng, raftEng)
+
+	const rangeID = roachpb.RangeID(3)
+	r := &Replica{RangeID: rangeID, store: s}
+	putRaftLog(t, raftEng, rangeID, 5, 15, 6)
+	putRaftLog(t, raftEng, rangeID+1, 1, 2, 1)
+
+	require.NoError(t, r.truncateRaftEngineLogRaftMuLocked(
+		&roachpb.RaftTruncatedState{Index: 10, Term: 6}))
+	require.Equal(t, []uint64{11, 12, 13, 14, 15}, raftLogIndexes(t, raftEng, rangeID))
+	require.Equal(t, []uint64{1, 2}, raftLogIndexes(t, raftEng, rangeID+1))
+}
+
+// TestStoreSeparateRaftLog starts an existing store with a separate Raft
+// engine and verifies that its Raft log is moved there, that it can't be
+// started without it anymore, and that the Raft engine is kept consistent
+// with the store's engine across restarts and log truncations.
+func TestStoreSeparateRaftLog(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	eng := storage.NewDefaultInMem()
+	stopper.AddCloser(eng)
+	raftEng := storage.NewDefaultInMem()
+	stopper.AddCloser(raftEng)
+
+	var truncations int32
+	cfg := TestStoreConfig(nil)
+	cfg.TestingKnobs.BeforeRaftEngineTruncation = func(
+		rangeID roachpb.RangeID, ts roachpb.RaftTruncatedState,
+	) {
+		// The truncated state must be durable before the entries go away.
+		durableTS, _, err := stateloader.Make(rangeID).LoadRaftTruncatedState(ctx, eng)
+		if err != nil {
+			t.Error(err)
+		} else if durableTS.Index < ts.Index {
+			t.Errorf("truncating Raft engine log to %d before truncated state %d is durable",
+				ts.Index, durableTS.Index)
+		}
+		atomic.AddInt32(&truncations, 1)
+	}
+	bootstrapTestEngine(t, &cfg, eng)
+	const rangeID = roachpb.RangeID(1)
+	bootstrapHS := loadHardState(t, eng, rangeID)
+	require.NotEqual(t, raftpb.HardState{}, bootstrapHS)
+
+	// startStore starts a store on eng, which keeps its Raft log in raftEng
+	// unless it is nil, and returns the store along with a function stopping
+	// it.
+	startStore := func(raftEng storage.Engine) (*Store, func(), error) {
+		storeStopper := stop.NewStopper()
+		s := newTestStoreWithRaftEngine(t, storeStopper, cfg, eng, raftEng)
+		if err := s.Start(ctx, storeStopper); err != nil {
+			storeStopper.Stop(ctx)
+			return nil, nil, err
+		}
+		s.WaitForInit()
+		return s, func() { storeStopper.Stop(ctx) }, nil
+	}
+
+	// The Raft log can't be moved before the cluster version allows it.
+	require.NoError(t, WriteClusterVersion(ctx, eng, clusterversion.ClusterVersion{
+		Version: clusterversion.VersionByKey(clusterversion.VersionSeparatedRaftLog - 1),
+	}))
+	_, _, err := startStore(raftEng)
+	require.True(t, testutils.IsError(err, "all nodes must be upgraded"), "%v", err)
+	require.Equal(t, bootstrapHS, loadHardState(t, eng, rangeID))
+	require.NoError(t, WriteClusterVersion(ctx, eng, clusterversion.TestingClusterVersion))
+
+	// Starting with a Raft engine moves the Raft log.
+	s, stopStore, err := startStore(raftEng)
+	require.NoError(t, err)
+	require.Equal(t, raftpb.HardState{}, loadHardState(t, eng, rangeID))
+	require.NotEqual(t, raftpb.HardState{}, loadHardState(t, raftEng, rangeID))
+	raftIdent, err := ReadStoreIdent(ctx, raftEng)
+	require.NoError(t, err)
+	require.Equal(t, *s.Ident, raftIdent)
+
+	// Log entries are appended to the Raft engine only, and truncated there
+	// once the new truncated state is durable.
+	key := roachpb.Key("a")
+	for i := 0; i < 10; i++ {
+		args := incrementArgs(key, 1)
+		_, pErr := kv.SendWrapped(ctx, s.TestSender(), args)
+		require.NoError(t, pErr.GoError())
+	}
+	repl, err := s.GetReplica(rangeID)
+	require.NoError(t, err)
+	lastIndex, err := repl.GetLastIndex()
+	require.NoError(t, err)
+	require.Contains(t, raftLogIndexes(t, raftEng, rangeID), lastIndex)
+	require.Empty(t, raftLogIndexes(t, eng, rangeID))
+
+	truncateArgs := truncateLogArgs(lastIndex, rangeID)
+	_, pErr := kv.SendWrappedWith(ctx, s.TestSender(), roachpb.Header{RangeID: rangeID}, &truncateArgs)
+	require.NoError(t, pErr.GoError())
+	testutils.SucceedsSoon(t, func() error {
+		if atomic.LoadInt32(&truncations) == 0 {
+			return errors.New("Raft engine log not truncated yet")
+		}
+		for _, idx := range raftLogIndexes(t, raftEng, rangeID) {
+			if idx < lastIndex {
+				return errors.Errorf("entry %d still in Raft engine", idx)
+			}
+		}
+		return nil
+	})
+	hs := loadHardState(t, raftEng, rangeID)
+	stopStore()
+
+	// A store whose Raft log was moved can't be started without its Raft
+	// engine.
+	_, _, err = startStore(nil)
+	require.True(t, testutils.IsError(err, "no Raft engine was configured"), "%v", err)
+
+	// Simulate a crash during a snapshot's handoff of the HardState, and during
+	// the destruction of replicas which left their Raft state behind. Like a
+	// snapshot, truncate the log up to the applied index.
+	rsl := stateloader.Make(rangeID)
+	appliedIndex, _, err := rsl.LoadAppliedIndex(ctx, eng)
+	require.NoError(t, err)
+	appliedTerm, err := term(ctx, rsl, raftEng, eng, rangeID, nil /* eCache */, appliedIndex)
+	require.NoError(t, err)
+	require.NoError(t, rsl.SetRaftTruncatedState(ctx, eng,
+		&roachpb.RaftTruncatedState{Index: appliedIndex, Term: appliedTerm}))
+	snapHS := raftpb.HardState{Term: hs.Term + 1, Commit: appliedIndex}
+	require.NoError(t, rsl.SetHardState(ctx, eng, snapHS))
+	stale := []struct {
+		rangeID roachpb.RangeID
+		hs      raftpb.HardState
+		log     bool
+		swept   bool
+	}{
+		{rangeID: 100, hs: raftpb.HardState{Term: 6, Commit: 12}, swept: true},
+		{rangeID: 101, hs: raftpb.HardState{Term: 6, Commit: 12}, log: true, swept: true},
+		{rangeID: 102, hs: raftpb.HardState{Term: 6}, log: true, swept: true},
+		// An uninitialized replica only keeps a HardState without commit index.
+		{rangeID: 103, hs: raftpb.HardState{Term: 6, Vote: 1}},
+	}
+	for _, st := range stale {
+		require.NoError(t, stateloader.Make(st.rangeID).SetHardState(ctx, raftEng, st.hs))
+		if st.log {
+			putRaftLog(t, raftEng, st.rangeID, 11, 12, 6)
+		}
+	}
+
+	s, stopStore, err = startStore(raftEng)
+	require.NoError(t, err)
+	defer stopStore()
+	// The replica may have campaigned since the handoff, but its HardState
+	// can't regress.
+	checkHandOff := func() {
+		require.Equal(t, raftpb.HardState{}, loadHardState(t, eng, rangeID))
+		hs := loadHardState(t, raftEng, rangeID)
+		require.GreaterOrEqual(t, hs.Term, snapHS.Term)
+		require.GreaterOrEqual(t, hs.Commit, snapHS.Commit)
+	}
+	checkHandOff()
+	for _, st := range stale {
+		if st.swept {
+			require.Equal(t, raftpb.HardState{}, loadHardState(t, raftEng, st.rangeID), "r%d", st.rangeID)
+			require.Empty(t, raftLogIndexes(t, raftEng, st.rangeID), "r%d", st.rangeID)
+		} else {
+			require.Equal(t, st.hs, loadHardState(t, raftEng, st.rangeID), "r%d", st.rangeID)
+		}
+	}
+
+	// Sweeping again is a no-op.
+	require.NoError(t, s.sweepRaftEngine(ctx))
+	require.Equal(t, stale[3].hs, loadHardState(t, raftEng, stale[3].rangeID))
+	checkHandOff()
+}
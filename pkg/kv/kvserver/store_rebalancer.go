@@ -12,16 +12,19 @@ package kvserver
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -40,6 +43,13 @@ const (
 	// by less than this amount even if the amount is greater than the percentage
 	// threshold. This avoids too many lease transfers in lightly loaded clusters.
 	minQPSThresholdDifference = 100
+	// minCPUThresholdDifference is like minQPSThresholdDifference, but for the
+	// time spent evaluating requests per second, in nanoseconds. It amounts to
+	// a tenth of a core.
+	minCPUThresholdDifference = float64(100 * time.Millisecond)
+	// minWriteBytesThresholdDifference is like minQPSThresholdDifference, but
+	// for the bytes written per second.
+	minWriteBytesThresholdDifference = 1 << 20 // 1 MiB/s
 )
 
 var (
@@ -84,6 +94,21 @@ var LoadBasedRebalancingMode = settings.RegisterPublicEnumSetting(
 	},
 )
 
+// LoadBasedRebalancingDimension controls which dimension of load store-level
+// rebalancing and load-based splitting try to balance.
+var LoadBasedRebalancingDimension = settings.RegisterPublicEnumSetting(
+	"kv.allocator.load_based_rebalancing.dimension",
+	"what dimension of load rebalancing and load-based splitting consider; "+
+		"qps balances requests, cpu the time spent evaluating them and "+
+		"write_bytes the bytes written",
+	"qps",
+	map[int64]string{
+		int64(LBRebalancingQueries):    "qps",
+		int64(LBRebalancingCPU):        "cpu",
+		int64(LBRebalancingWriteBytes): "write_bytes",
+	},
+)
+
 // qpsRebalanceThreshold is much like rangeRebalanceThreshold, but for
 // QPS rather than range count. This should be set higher than
 // rangeRebalanceThreshold because QPS can naturally vary over time as
@@ -115,6 +140,121 @@ const (
 	LBRebalancingLeasesAndReplicas
 )
 
+// LBRebalancingDimension is a dimension of load that store-level rebalancing
+// and load-based splitting can try to balance.
+type LBRebalancingDimension int64
+
+const (
+	// LBRebalancingQueries balances the number of requests per second served by
+	// the leaseholders.
+	LBRebalancingQueries LBRebalancingDimension = iota
+	// LBRebalancingCPU balances the time spent per second evaluating requests,
+	// which approximates the CPU time they use.
+	LBRebalancingCPU
+	// LBRebalancingWriteBytes balances the number of bytes written per second,
+	// including ingested SSTables.
+	LBRebalancingWriteBytes
+)
+
+// loadDimension returns the LBRebalancingDimension set in the given settings.
+// Nodes at versions older than VersionLoadBasedRebalancingDimensions neither
+// gossip the CPU time and bytes written of their stores nor measure them for
+// load based splitting, so the setting is ignored in favor of QPS until the
+// upgrade is finalized.
+func loadDimension(ctx context.Context, st *cluster.Settings) LBRebalancingDimension {
+	if !st.Version.IsActive(ctx, clusterversion.VersionLoadBasedRebalancingDimensions) {
+		return LBRebalancingQueries
+	}
+	return LBRebalancingDimension(LoadBasedRebalancingDimension.Get(&st.SV))
+}
+
+// storeLoad returns the load of a store along the dimension.
+func (d LBRebalancingDimension) storeLoad(sc roachpb.StoreCapacity) float64 {
+	switch d {
+	case LBRebalancingCPU:
+		return sc.CPUPerSecond
+	case LBRebalancingWriteBytes:
+		return sc.WriteBytesPerSecond
+	default:
+		return sc.QueriesPerSecond
+	}
+}
+
+// addStoreLoad adds the given load, which may be negative, to the load of a
+// store along the dimension.
+func (d LBRebalancingDimension) addStoreLoad(sc *roachpb.StoreCapacity, delta float64) {
+	switch d {
+	case LBRebalancingCPU:
+		sc.CPUPerSecond += delta
+	case LBRebalancingWriteBytes:
+		sc.WriteBytesPerSecond += delta
+	default:
+		sc.QueriesPerSecond += delta
+	}
+}
+
+// replicaLoad returns the load of a replica along the dimension.
+func (d LBRebalancingDimension) replicaLoad(r replicaWithStats) float64 {
+	switch d {
+	case LBRebalancingCPU:
+		return r.cpu
+	case LBRebalancingWriteBytes:
+		return r.writeBytes
+	default:
+		return r.qps
+	}
+}
+
+// leaseLoad returns the portion of a replica's load along the dimension that
+// follows its lease. Requests are served and evaluated by the leaseholder, but
+// writes are applied by every replica, so transferring a lease does not move
+// any write bytes.
+func (d LBRebalancingDimension) leaseLoad(r replicaWithStats) float64 {
+	if d == LBRebalancingWriteBytes {
+		return 0
+	}
+	return d.replicaLoad(r)
+}
+
+// candidateStoreLoad returns the load stats along the dimension of the stores
+// that are eligible to be rebalance targets.
+func (d LBRebalancingDimension) candidateStoreLoad(sl StoreList) stat {
+	switch d {
+	case LBRebalancingCPU:
+		return sl.candidateCPUPerSecond
+	case LBRebalancingWriteBytes:
+		return sl.candidateWriteBytesPerSecond
+	default:
+		return sl.candidateQueriesPerSecond
+	}
+}
+
+// minThresholdDifference is the minimum difference from the cluster mean of
+// the load of a store along the dimension that store-level rebalancing should
+// care about. See minQPSThresholdDifference.
+func (d LBRebalancingDimension) minThresholdDifference() float64 {
+	switch d {
+	case LBRebalancingCPU:
+		return minCPUThresholdDifference
+	case LBRebalancingWriteBytes:
+		return minWriteBytesThresholdDifference
+	default:
+		return minQPSThresholdDifference
+	}
+}
+
+// format formats a load along the dimension for logging.
+func (d LBRebalancingDimension) format(load float64) string {
+	switch d {
+	case LBRebalancingCPU:
+		return fmt.Sprintf("%s/s cpu", time.Duration(load))
+	case LBRebalancingWriteBytes:
+		return fmt.Sprintf("%s/s written", humanizeutil.IBytes(int64(load)))
+	default:
+		return fmt.Sprintf("%.2f qps", load)
+	}
+}
+
 // StoreRebalancer is responsible for examining how the associated store's load
 // compares to the load on other stores in the cluster and transferring leases
 // or replicas away if the local store is overloaded.
@@ -205,13 +345,15 @@ func (sr *StoreRebalancer) Start(ctx context.Context, stopper *stop.Stopper) {
 func (sr *StoreRebalancer) rebalanceStore(
 	ctx context.Context, mode LBRebalancingMode, storeList StoreList,
 ) {
-	qpsThresholdFraction := qpsRebalanceThreshold.Get(&sr.st.SV)
+	dim := loadDimension(ctx, sr.st)
+	thresholdFraction := qpsRebalanceThreshold.Get(&sr.st.SV)
 
-	// First check if we should transfer leases away to better balance QPS.
-	qpsMinThreshold := math.Min(storeList.candidateQueriesPerSecond.mean*(1-qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean-minQPSThresholdDifference)
-	qpsMaxThreshold := math.Max(storeList.candidateQueriesPerSecond.mean*(1+qpsThresholdFraction),
-		storeList.candidateQueriesPerSecond.mean+minQPSThresholdDifference)
+	// First check if we should transfer leases away to better balance load.
+	meanLoad := dim.candidateStoreLoad(storeList).mean
+	minThreshold := math.Min(meanLoad*(1-thresholdFraction),
+		meanLoad-dim.minThresholdDifference())
+	maxThreshold := math.Max(meanLoad*(1+thresholdFraction),
+		meanLoad+dim.minThresholdDifference())
 
 	var localDesc *roachpb.StoreDescriptor
 	for i := range storeList.stores {
@@ -224,9 +366,9 @@ func (sr *StoreRebalancer) rebalanceStore(
 		return
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
-		log.VEventf(ctx, 1, "local QPS %.2f is below max threshold %.2f (mean=%.2f); no rebalancing needed",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold, storeList.candidateQueriesPerSecond.mean)
+	if !(dim.storeLoad(localDesc.Capacity) > maxThreshold) {
+		log.VEventf(ctx, 1, "local load %s is below max threshold %s (mean=%s); no rebalancing needed",
+			dim.format(dim.storeLoad(localDesc.Capacity)), dim.format(maxThreshold), dim.format(meanLoad))
 		return
 	}
 
@@ -234,20 +376,21 @@ func (sr *StoreRebalancer) rebalanceStore(
 	storeMap := storeListToMap(storeList)
 
 	log.Infof(ctx,
-		"considering load-based lease transfers for s%d with %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"considering load-based lease transfers for s%d with %s (mean=%s, upperThreshold=%s)",
+		localDesc.StoreID, dim.format(dim.storeLoad(localDesc.Capacity)), dim.format(meanLoad),
+		dim.format(maxThreshold))
 
-	hottestRanges := sr.replRankings.topQPS()
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	hottestRanges := sr.replRankings.topLoad(dim)
+	for dim.storeLoad(localDesc.Capacity) > maxThreshold {
 		replWithStats, target, considerForRebalance := sr.chooseLeaseToTransfer(
-			ctx, &hottestRanges, localDesc, storeList, storeMap, qpsMinThreshold, qpsMaxThreshold)
+			ctx, &hottestRanges, localDesc, storeList, storeMap, minThreshold, maxThreshold)
 		replicasToMaybeRebalance = append(replicasToMaybeRebalance, considerForRebalance...)
 		if replWithStats.repl == nil {
 			break
 		}
 
-		log.VEventf(ctx, 1, "transferring r%d (%.2f qps) to s%d to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, target.StoreID)
+		log.VEventf(ctx, 1, "transferring r%d (%s) to s%d to better balance load",
+			replWithStats.repl.RangeID, dim.format(dim.replicaLoad(replWithStats)), target.StoreID)
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "transfer lease", timeout, func(ctx context.Context) error {
 			return sr.rq.transferLease(ctx, replWithStats.repl, target, replWithStats.qps)
@@ -261,53 +404,55 @@ func (sr *StoreRebalancer) rebalanceStore(
 		// additional transfers are needed we'll be making the decisions with more
 		// up-to-date info. The StorePool copies are updated by transferLease.
 		localDesc.Capacity.LeaseCount--
-		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		dim.addStoreLoad(&localDesc.Capacity, -dim.leaseLoad(replWithStats))
 		if otherDesc := storeMap[target.StoreID]; otherDesc != nil {
 			otherDesc.Capacity.LeaseCount++
-			otherDesc.Capacity.QueriesPerSecond += replWithStats.qps
+			dim.addStoreLoad(&otherDesc.Capacity, dim.leaseLoad(replWithStats))
 		}
 	}
 
-	if !(localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold) {
+	if !(dim.storeLoad(localDesc.Capacity) > maxThreshold) {
 		log.Infof(ctx,
-			"load-based lease transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-			localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+			"load-based lease transfers successfully brought s%d down to %s (mean=%s, upperThreshold=%s)",
+			localDesc.StoreID, dim.format(dim.storeLoad(localDesc.Capacity)), dim.format(meanLoad),
+			dim.format(maxThreshold))
 		return
 	}
 
 	if mode != LBRebalancingLeasesAndReplicas {
 		log.Infof(ctx,
-			"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f)",
-			localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+			"ran out of leases worth transferring and load (%s) is still above desired threshold (%s)",
+			dim.format(dim.storeLoad(localDesc.Capacity)), dim.format(maxThreshold))
 		return
 	}
 	log.Infof(ctx,
-		"ran out of leases worth transferring and qps (%.2f) is still above desired threshold (%.2f); considering load-based replica rebalances",
-		localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+		"ran out of leases worth transferring and load (%s) is still above desired threshold (%s); considering load-based replica rebalances",
+		dim.format(dim.storeLoad(localDesc.Capacity)), dim.format(maxThreshold))
 
 	// Re-combine replicasToMaybeRebalance with what remains of hottestRanges so
 	// that we'll reconsider them for replica rebalancing.
 	replicasToMaybeRebalance = append(replicasToMaybeRebalance, hottestRanges...)
 
-	for localDesc.Capacity.QueriesPerSecond > qpsMaxThreshold {
+	for dim.storeLoad(localDesc.Capacity) > maxThreshold {
 		replWithStats, targets := sr.chooseReplicaToRebalance(
 			ctx,
 			&replicasToMaybeRebalance,
 			localDesc,
 			storeList,
 			storeMap,
-			qpsMinThreshold,
-			qpsMaxThreshold)
+			minThreshold,
+			maxThreshold)
 		if replWithStats.repl == nil {
 			log.Infof(ctx,
-				"ran out of replicas worth transferring and qps (%.2f) is still above desired threshold (%.2f); will check again soon",
-				localDesc.Capacity.QueriesPerSecond, qpsMaxThreshold)
+				"ran out of replicas worth transferring and load (%s) is still above desired threshold (%s); will check again soon",
+				dim.format(dim.storeLoad(localDesc.Capacity)), dim.format(maxThreshold))
 			return
 		}
 
 		descBeforeRebalance := replWithStats.repl.Desc()
-		log.VEventf(ctx, 1, "rebalancing r%d (%.2f qps) from %v to %v to better balance load",
-			replWithStats.repl.RangeID, replWithStats.qps, descBeforeRebalance.Replicas(), targets)
+		log.VEventf(ctx, 1, "rebalancing r%d (%s) from %v to %v to better balance load",
+			replWithStats.repl.RangeID, dim.format(dim.replicaLoad(replWithStats)),
+			descBeforeRebalance.Replicas(), targets)
 		timeout := sr.rq.processTimeoutFunc(sr.st, replWithStats.repl)
 		if err := contextutil.RunWithTimeout(ctx, "relocate range", timeout, func(ctx context.Context) error {
 			return sr.rq.store.AdminRelocateRange(ctx, *descBeforeRebalance, targets)
@@ -331,21 +476,24 @@ func (sr *StoreRebalancer) rebalanceStore(
 			}
 		}
 		localDesc.Capacity.LeaseCount--
-		localDesc.Capacity.QueriesPerSecond -= replWithStats.qps
+		dim.addStoreLoad(&localDesc.Capacity, -dim.replicaLoad(replWithStats))
 		for i := range targets {
 			if storeDesc := storeMap[targets[i].StoreID]; storeDesc != nil {
 				storeDesc.Capacity.RangeCount++
 				if i == 0 {
 					storeDesc.Capacity.LeaseCount++
-					storeDesc.Capacity.QueriesPerSecond += replWithStats.qps
+					dim.addStoreLoad(&storeDesc.Capacity, dim.replicaLoad(replWithStats))
+				} else {
+					dim.addStoreLoad(&storeDesc.Capacity, dim.replicaLoad(replWithStats)-dim.leaseLoad(replWithStats))
 				}
 			}
 		}
 	}
 
 	log.Infof(ctx,
-		"load-based replica transfers successfully brought s%d down to %.2f qps (mean=%.2f, upperThreshold=%.2f)",
-		localDesc.StoreID, localDesc.Capacity.QueriesPerSecond, storeList.candidateQueriesPerSecond.mean, qpsMaxThreshold)
+		"load-based replica transfers successfully brought s%d down to %s (mean=%s, upperThreshold=%s)",
+		localDesc.StoreID, dim.format(dim.storeLoad(localDesc.Capacity)), dim.format(meanLoad),
+		dim.format(maxThreshold))
 }
 
 // TODO(a-robinson): Should we take the number of leases on each store into
@@ -356,9 +504,10 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, roachpb.ReplicaDescriptor, []replicaWithStats) {
+	dim := loadDimension(ctx, sr.st)
 	var considerForRebalance []replicaWithStats
 	now := sr.rq.store.Clock().Now()
 	for {
@@ -373,39 +522,40 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 			return replicaWithStats{}, roachpb.ReplicaDescriptor{}, considerForRebalance
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		if shouldNotMoveAway(ctx, dim, dim.leaseLoad(replWithStats), replWithStats, localDesc, now, minLoad) {
 			continue
 		}
 
-		// Don't bother moving leases whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra leases to spare anyway). It's
-		// just unnecessary churn with no benefit to move leases responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Don't bother moving leases whose load is below some small fraction of
+		// the store's load (unless the store has extra leases to spare anyway).
+		// It's just unnecessary churn with no benefit to move leases responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		if dim.leaseLoad(replWithStats) < dim.storeLoad(localDesc.Capacity)*minLoadFraction &&
 			float64(localDesc.Capacity.LeaseCount) <= storeList.candidateLeases.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %s is too little to matter relative to s%d's %s total",
+				replWithStats.repl.RangeID, dim.format(dim.leaseLoad(replWithStats)), localDesc.StoreID,
+				dim.format(dim.storeLoad(localDesc.Capacity)))
 			continue
 		}
 
 		desc, zone := replWithStats.repl.DescAndZone()
-		log.VEventf(ctx, 3, "considering lease transfer for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering lease transfer for r%d with %s",
+			desc.RangeID, dim.format(dim.leaseLoad(replWithStats)))
 
-		// Check all the other replicas in order of increasing qps. Learner replicas
-		// aren't allowed to become the leaseholder or raft leader, so only consider
-		// the `Voters` replicas.
+		// Check all the other replicas in order of increasing load. Learner
+		// replicas aren't allowed to become the leaseholder or raft leader, so
+		// only consider the `Voters` replicas.
 		candidates := desc.Replicas().DeepCopy().Voters()
 		sort.Slice(candidates, func(i, j int) bool {
-			var iQPS, jQPS float64
+			var iLoad, jLoad float64
 			if desc := storeMap[candidates[i].StoreID]; desc != nil {
-				iQPS = desc.Capacity.QueriesPerSecond
+				iLoad = dim.storeLoad(desc.Capacity)
 			}
 			if desc := storeMap[candidates[j].StoreID]; desc != nil {
-				jQPS = desc.Capacity.QueriesPerSecond
+				jLoad = dim.storeLoad(desc.Capacity)
 			}
-			return iQPS < jQPS
+			return iLoad < jLoad
 		})
 
 		var raftStatus *raft.Status
@@ -416,8 +566,9 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 				continue
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if shouldNotMoveTo(ctx, storeMap, replWithStats, candidate.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := dim.candidateStoreLoad(storeList).mean
+			if shouldNotMoveTo(ctx, dim, dim.leaseLoad(replWithStats), storeMap, replWithStats,
+				candidate.StoreID, meanLoad, minLoad, maxLoad) {
 				continue
 			}
 
@@ -465,9 +616,10 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 	localDesc *roachpb.StoreDescriptor,
 	storeList StoreList,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
-	minQPS float64,
-	maxQPS float64,
+	minLoad float64,
+	maxLoad float64,
 ) (replicaWithStats, []roachpb.ReplicationTarget) {
+	dim := loadDimension(ctx, sr.st)
 	now := sr.rq.store.Clock().Now()
 	for {
 		if len(*hottestRanges) == 0 {
@@ -480,34 +632,42 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			return replicaWithStats{}, nil
 		}
 
-		if shouldNotMoveAway(ctx, replWithStats, localDesc, now, minQPS) {
+		if shouldNotMoveAway(ctx, dim, dim.replicaLoad(replWithStats), replWithStats, localDesc, now, minLoad) {
 			continue
 		}
 
-		// Don't bother moving ranges whose QPS is below some small fraction of the
-		// store's QPS (unless the store has extra ranges to spare anyway). It's
-		// just unnecessary churn with no benefit to move ranges responsible for,
-		// for example, 1 qps on a store with 5000 qps.
-		const minQPSFraction = .001
-		if replWithStats.qps < localDesc.Capacity.QueriesPerSecond*minQPSFraction &&
+		// Don't bother moving ranges whose load is below some small fraction of
+		// the store's load (unless the store has extra ranges to spare anyway).
+		// It's just unnecessary churn with no benefit to move ranges responsible
+		// for, for example, 1 qps on a store with 5000 qps.
+		const minLoadFraction = .001
+		if dim.replicaLoad(replWithStats) < dim.storeLoad(localDesc.Capacity)*minLoadFraction &&
 			float64(localDesc.Capacity.RangeCount) <= storeList.candidateRanges.mean {
-			log.VEventf(ctx, 5, "r%d's %.2f qps is too little to matter relative to s%d's %.2f total qps",
-				replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, localDesc.Capacity.QueriesPerSecond)
+			log.VEventf(ctx, 5, "r%d's %s is too little to matter relative to s%d's %s total",
+				replWithStats.repl.RangeID, dim.format(dim.replicaLoad(replWithStats)), localDesc.StoreID,
+				dim.format(dim.storeLoad(localDesc.Capacity)))
 			continue
 		}
 
 		desc, zone := replWithStats.repl.DescAndZone()
-		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %.2f qps",
-			desc.RangeID, replWithStats.qps)
+		log.VEventf(ctx, 3, "considering replica rebalance for r%d with %s",
+			desc.RangeID, dim.format(dim.replicaLoad(replWithStats)))
+
+		// NB: AdminRelocateRange only knows how to move voting replicas, so
+		// ranges with non-voting replicas are left to the replicate queue.
+		if len(desc.Replicas().NonVoters()) > 0 {
+			log.VEventf(ctx, 3, "skipping r%d: it has non-voting replicas", desc.RangeID)
+			continue
+		}
 
 		clusterNodes := sr.rq.allocator.storePool.ClusterNodeCount()
-		desiredReplicas := GetNeededReplicas(*zone.NumReplicas, clusterNodes)
+		desiredReplicas := GetNeededReplicas(zone.GetNumVoters(), clusterNodes)
 		targets := make([]roachpb.ReplicationTarget, 0, desiredReplicas)
 		targetReplicas := make([]roachpb.ReplicaDescriptor, 0, desiredReplicas)
 		currentReplicas := desc.Replicas().All()
 
 		// Check the range's existing diversity score, since we want to ensure we
-		// don't hurt locality diversity just to improve QPS.
+		// don't hurt locality diversity just to improve load.
 		curDiversity := rangeDiversityScore(
 			sr.rq.allocator.storePool.getLocalitiesByStore(currentReplicas))
 
@@ -516,11 +676,11 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			if currentReplicas[i].StoreID == localDesc.StoreID {
 				continue
 			}
-			// Keep the replica in the range if we don't know its QPS or if its QPS
-			// is below the upper threshold. Punishing stores not in our store map
-			// could cause mass evictions if the storePool gets out of sync.
+			// Keep the replica in the range if we don't know its load or if its
+			// load is below the upper threshold. Punishing stores not in our store
+			// map could cause mass evictions if the storePool gets out of sync.
 			storeDesc, ok := storeMap[currentReplicas[i].StoreID]
-			if !ok || storeDesc.Capacity.QueriesPerSecond < maxQPS {
+			if !ok || dim.storeLoad(storeDesc.Capacity) < maxLoad {
 				targets = append(targets, roachpb.ReplicationTarget{
 					NodeID:  currentReplicas[i].NodeID,
 					StoreID: currentReplicas[i].StoreID,
@@ -534,7 +694,8 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 
 		// Then pick out which new stores to add the remaining replicas to.
 		options := sr.rq.allocator.scorerOptions()
-		options.qpsRebalanceThreshold = qpsRebalanceThreshold.Get(&sr.st.SV)
+		options.loadRebalanceThreshold = qpsRebalanceThreshold.Get(&sr.st.SV)
+		options.loadDimension = dim
 		for len(targets) < desiredReplicas {
 			// Use the preexisting AllocateTarget logic to ensure that considerations
 			// such as zone constraints, locality diversity, and full disk come
@@ -545,6 +706,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 				zone,
 				targetReplicas,
 				options,
+				voterTarget,
 			)
 			if target == nil {
 				log.VEventf(ctx, 3, "no rebalance targets found to replace the current store for r%d",
@@ -552,8 +714,9 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 				break
 			}
 
-			meanQPS := storeList.candidateQueriesPerSecond.mean
-			if shouldNotMoveTo(ctx, storeMap, replWithStats, target.StoreID, meanQPS, minQPS, maxQPS) {
+			meanLoad := dim.candidateStoreLoad(storeList).mean
+			if shouldNotMoveTo(ctx, dim, dim.replicaLoad(replWithStats), storeMap, replWithStats,
+				target.StoreID, meanLoad, minLoad, maxLoad) {
 				break
 			}
 
@@ -572,7 +735,7 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 		// TODO(a-robinson): Support more incremental improvements -- move what we
 		// can if it makes things better even if it isn't great. For example,
 		// moving one of the other existing replicas that's on a store with less
-		// qps than the max threshold but above the mean would help in certain
+		// load than the max threshold but above the mean would help in certain
 		// locality configurations.
 		if len(targets) < desiredReplicas {
 			log.VEventf(ctx, 3, "couldn't find enough rebalance targets for r%d (%d/%d)",
@@ -587,10 +750,10 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			continue
 		}
 
-		// Pick the replica with the least QPS to be leaseholder;
+		// Pick the replica with the least load to be leaseholder;
 		// RelocateRange transfers the lease to the first provided target.
 		newLeaseIdx := 0
-		newLeaseQPS := math.MaxFloat64
+		newLeaseLoad := math.MaxFloat64
 		var raftStatus *raft.Status
 		for i := 0; i < len(targets); i++ {
 			// Ensure we don't transfer the lease to an existing replica that is behind
@@ -605,9 +768,9 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 			}
 
 			storeDesc, ok := storeMap[targets[i].StoreID]
-			if ok && storeDesc.Capacity.QueriesPerSecond < newLeaseQPS {
+			if ok && dim.storeLoad(storeDesc.Capacity) < newLeaseLoad {
 				newLeaseIdx = i
-				newLeaseQPS = storeDesc.Capacity.QueriesPerSecond
+				newLeaseLoad = dim.storeLoad(storeDesc.Capacity)
 			}
 		}
 		targets[0], targets[newLeaseIdx] = targets[newLeaseIdx], targets[0]
@@ -615,33 +778,41 @@ func (sr *StoreRebalancer) chooseReplicaToRebalance(
 	}
 }
 
+// shouldNotMoveAway returns whether moving the given load of a replica away
+// from the local store would bring it below the min threshold.
 func shouldNotMoveAway(
 	ctx context.Context,
+	dim LBRebalancingDimension,
+	load float64,
 	replWithStats replicaWithStats,
 	localDesc *roachpb.StoreDescriptor,
 	now hlc.Timestamp,
-	minQPS float64,
+	minLoad float64,
 ) bool {
 	if !replWithStats.repl.OwnsValidLease(ctx, now) {
 		log.VEventf(ctx, 3, "store doesn't own the lease for r%d", replWithStats.repl.RangeID)
 		return true
 	}
-	if localDesc.Capacity.QueriesPerSecond-replWithStats.qps < minQPS {
-		log.VEventf(ctx, 3, "moving r%d's %.2f qps would bring s%d below the min threshold (%.2f)",
-			replWithStats.repl.RangeID, replWithStats.qps, localDesc.StoreID, minQPS)
+	if dim.storeLoad(localDesc.Capacity)-load < minLoad {
+		log.VEventf(ctx, 3, "moving r%d's %s would bring s%d below the min threshold (%s)",
+			replWithStats.repl.RangeID, dim.format(load), localDesc.StoreID, dim.format(minLoad))
 		return true
 	}
 	return false
 }
 
+// shouldNotMoveTo returns whether moving the given load of a replica to the
+// candidate store would overload it.
 func shouldNotMoveTo(
 	ctx context.Context,
+	dim LBRebalancingDimension,
+	load float64,
 	storeMap map[roachpb.StoreID]*roachpb.StoreDescriptor,
 	replWithStats replicaWithStats,
 	candidateStore roachpb.StoreID,
-	meanQPS float64,
-	minQPS float64,
-	maxQPS float64,
+	meanLoad float64,
+	minLoad float64,
+	maxLoad float64,
 ) bool {
 	storeDesc, ok := storeMap[candidateStore]
 	if !ok {
@@ -649,18 +820,21 @@ func shouldNotMoveTo(
 		return true
 	}
 
-	newCandidateQPS := storeDesc.Capacity.QueriesPerSecond + replWithStats.qps
-	if storeDesc.Capacity.QueriesPerSecond < minQPS {
-		if newCandidateQPS > maxQPS {
+	candidateLoad := dim.storeLoad(storeDesc.Capacity)
+	newCandidateLoad := candidateLoad + load
+	if candidateLoad < minLoad {
+		if newCandidateLoad > maxLoad {
 			log.VEventf(ctx, 3,
-				"r%d's %.2f qps would push s%d over the max threshold (%.2f) with %.2f qps afterwards",
-				replWithStats.repl.RangeID, replWithStats.qps, candidateStore, maxQPS, newCandidateQPS)
+				"r%d's %s would push s%d over the max threshold (%s) with %s afterwards",
+				replWithStats.repl.RangeID, dim.format(load), candidateStore, dim.format(maxLoad),
+				dim.format(newCandidateLoad))
 			return true
 		}
-	} else if newCandidateQPS > meanQPS {
+	} else if newCandidateLoad > meanLoad {
 		log.VEventf(ctx, 3,
-			"r%d's %.2f qps would push s%d over the mean (%.2f) with %.2f qps afterwards",
-			replWithStats.repl.RangeID, replWithStats.qps, candidateStore, meanQPS, newCandidateQPS)
+			"r%d's %s would push s%d over the mean (%s) with %s afterwards",
+			replWithStats.repl.RangeID, dim.format(load), candidateStore, dim.format(meanLoad),
+			dim.format(newCandidateLoad))
 		return true
 	}
 
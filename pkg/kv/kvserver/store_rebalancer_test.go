@@ -15,8 +15,11 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils/gossiputil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -73,8 +76,10 @@ var (
 
 type testRange struct {
 	// The first storeID in the list will be the leaseholder.
-	storeIDs []roachpb.StoreID
-	qps      float64
+	storeIDs   []roachpb.StoreID
+	qps        float64
+	cpu        float64
+	writeBytes float64
 }
 
 func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
@@ -100,8 +105,10 @@ func loadRanges(rr *replicaRankings, s *Store, ranges []testRange) {
 		repl.leaseholderStats = newReplicaStats(s.Clock(), nil)
 		repl.writeStats = newReplicaStats(s.Clock(), nil)
 		acc.addReplica(replicaWithStats{
-			repl: repl,
-			qps:  r.qps,
+			repl:       repl,
+			qps:        r.qps,
+			cpu:        r.cpu,
+			writeBytes: r.writeBytes,
 		})
 	}
 	rr.update(acc)
@@ -191,6 +198,127 @@ func TestChooseLeaseToTransfer(t *testing.T) {
 	}
 }
 
+func TestChooseLeaseToTransferByLoadDimension(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	// Give the stores the same distribution of CPU time and bytes written as
+	// noLocalityStores has of QPS, but no QPS at all.
+	var stores []*roachpb.StoreDescriptor
+	for _, desc := range noLocalityStores {
+		load := desc.Capacity.QueriesPerSecond
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: desc.StoreID,
+			Node:    desc.Node,
+			Capacity: roachpb.StoreCapacity{
+				CPUPerSecond:        load * float64(time.Millisecond),
+				WriteBytesPerSecond: load * (1 << 10),
+			},
+		})
+	}
+
+	stopper, g, _, a, _ := createTestAllocator(10, false /* deterministic */)
+	defer stopper.Stop(context.Background())
+	gossiputil.NewStoreGossiper(g).GossipStores(stores, t)
+	storeList, _, _ := a.storePool.getStoreList(storeFilterThrottled)
+	storeMap := storeListToMap(storeList)
+
+	localDesc := *stores[0]
+	cfg := TestStoreConfig(nil)
+	s := createTestStoreWithoutStart(t, stopper, testStoreOpts{createSystemRanges: true}, &cfg)
+	s.Ident = &roachpb.StoreIdent{StoreID: localDesc.StoreID}
+	rq := newReplicateQueue(s, g, a)
+	rr := newReplicaRankings()
+
+	sr := NewStoreRebalancer(cfg.AmbientCtx, cfg.Settings, rq, rr)
+	sr.getRaftStatusFn = func(r *Replica) *raft.Status {
+		status := &raft.Status{
+			Progress: make(map[uint64]tracker.Progress),
+		}
+		status.Lead = uint64(r.ReplicaID())
+		status.Commit = 1
+		for _, replica := range r.Desc().InternalReplicas {
+			status.Progress[uint64(replica.ReplicaID)] = tracker.Progress{
+				Match: 1,
+				State: tracker.StateReplicate,
+			}
+		}
+		return status
+	}
+
+	testCases := []struct {
+		dim          LBRebalancingDimension
+		storeIDs     []roachpb.StoreID
+		load         float64
+		expectTarget roachpb.StoreID
+	}{
+		// Without any QPS, there's no reason to transfer leases based on QPS.
+		{LBRebalancingQueries, []roachpb.StoreID{1, 5}, 200, 0},
+		{LBRebalancingCPU, []roachpb.StoreID{1, 4}, 100, 4},
+		{LBRebalancingCPU, []roachpb.StoreID{1, 5}, 100, 5},
+		{LBRebalancingCPU, []roachpb.StoreID{1, 4}, 200, 0},
+		{LBRebalancingCPU, []roachpb.StoreID{1, 5}, 200, 5},
+		{LBRebalancingCPU, []roachpb.StoreID{1, 5}, 800, 0},
+		// Writes are applied by every replica, so transferring leases doesn't
+		// move any write bytes.
+		{LBRebalancingWriteBytes, []roachpb.StoreID{1, 5}, 200, 0},
+	}
+
+	for _, tc := range testCases {
+		LoadBasedRebalancingDimension.Override(&cfg.Settings.SV, int64(tc.dim))
+		loadRanges(rr, s, []testRange{{
+			storeIDs:   tc.storeIDs,
+			cpu:        tc.load * float64(time.Millisecond),
+			writeBytes: tc.load * (1 << 10),
+		}})
+		hottestRanges := rr.topLoad(tc.dim)
+		minLoad := 800 * tc.dim.storeLoad(roachpb.StoreCapacity{
+			QueriesPerSecond: 1, CPUPerSecond: float64(time.Millisecond), WriteBytesPerSecond: 1 << 10,
+		})
+		_, target, _ := sr.chooseLeaseToTransfer(
+			ctx, &hottestRanges, &localDesc, storeList, storeMap, minLoad, 1.5*minLoad)
+		if target.StoreID != tc.expectTarget {
+			t.Errorf("got target store %d for range with replicas %v and %d load along dimension %d; want %d",
+				target.StoreID, tc.storeIDs, int(tc.load), tc.dim, tc.expectTarget)
+		}
+	}
+}
+
+func TestLoadDimensionRequiresVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	oldSettings := cluster.MakeTestingClusterSettingsWithVersions(
+		clusterversion.TestingBinaryVersion,
+		clusterversion.VersionByKey(clusterversion.VersionLoadBasedRebalancingDimensions-1),
+		true, /* initializeVersion */
+	)
+	newSettings := cluster.MakeTestingClusterSettings()
+
+	for _, tc := range []struct {
+		st           *cluster.Settings
+		expDim       LBRebalancingDimension
+		expThreshold float64
+	}{
+		// Until all nodes are upgraded, the setting is ignored in favor of QPS.
+		{oldSettings, LBRebalancingQueries, float64(SplitByLoadQPSThreshold.Get(&oldSettings.SV))},
+		{newSettings, LBRebalancingCPU, float64(SplitByLoadCPUThreshold.Get(&newSettings.SV))},
+	} {
+		LoadBasedRebalancingDimension.Override(&tc.st.SV, int64(LBRebalancingCPU))
+		if dim := loadDimension(ctx, tc.st); dim != tc.expDim {
+			t.Errorf("expected dimension %d, got %d", tc.expDim, dim)
+		}
+		if threshold := splitByLoadThreshold(ctx, tc.st); threshold != tc.expThreshold {
+			t.Errorf("expected split threshold %f, got %f", tc.expThreshold, threshold)
+		}
+	}
+}
+
 func TestChooseReplicaToRebalance(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -82,6 +82,10 @@ func splitPreApply(
 		// the HardState and tombstone. Note that we only do this if rightRepl
 		// exists; if it doesn't, there's no Raft state to massage (when rightRepl
 		// was removed, a tombstone was written instead).
+		//
+		// If the Raft log is separated, the HardState lives in the Raft engine
+		// and is left alone by the clearing below.
+		separated := r.store.raftLogSeparated()
 		var hs raftpb.HardState
 		if rightRepl != nil {
 			// Assert that the rightRepl is not initialized. We're about to clear out
@@ -90,9 +94,11 @@ func splitPreApply(
 			if rightRepl.IsInitialized() {
 				log.Fatalf(ctx, "unexpectedly found initialized newer RHS of split: %v", rightRepl.Desc())
 			}
-			hs, err = rightRepl.raftMu.stateLoader.LoadHardState(ctx, readWriter)
-			if err != nil {
-				log.Fatalf(ctx, "failed to load hard state for removed rhs: %v", err)
+			if !separated {
+				hs, err = rightRepl.raftMu.stateLoader.LoadHardState(ctx, readWriter)
+				if err != nil {
+					log.Fatalf(ctx, "failed to load hard state for removed rhs: %v", err)
+				}
 			}
 		}
 		const rangeIDLocalOnly = false
@@ -100,7 +106,7 @@ func splitPreApply(
 		if err := clearRangeData(&split.RightDesc, readWriter, readWriter, rangeIDLocalOnly, mustUseClearRange); err != nil {
 			log.Fatalf(ctx, "failed to clear range data for removed rhs: %v", err)
 		}
-		if rightRepl != nil {
+		if rightRepl != nil && !separated {
 			if err := rightRepl.raftMu.stateLoader.SetHardState(ctx, readWriter, hs); err != nil {
 				log.Fatalf(ctx, "failed to set hard state with 0 commit index for removed rhs: %v", err)
 			}
@@ -320,13 +326,17 @@ func (s *Store) SplitRange(
 	// Clear the original range's request stats, since they include requests for
 	// spans that are now owned by the new range.
 	leftRepl.leaseholderStats.resetRequestCounts()
+	leftRepl.cpuStats.resetRequestCounts()
 
 	if rightReplOrNil == nil {
 		throwawayRightWriteStats := new(replicaStats)
 		leftRepl.writeStats.splitRequestCounts(throwawayRightWriteStats)
+		throwawayRightWriteBytesStats := new(replicaStats)
+		leftRepl.writeBytesStats.splitRequestCounts(throwawayRightWriteBytesStats)
 	} else {
 		rightRepl := rightReplOrNil
 		leftRepl.writeStats.splitRequestCounts(rightRepl.writeStats)
+		leftRepl.writeBytesStats.splitRequestCounts(rightRepl.writeBytesStats)
 		if err := s.addReplicaInternalLocked(rightRepl); err != nil {
 			return errors.Errorf("unable to add replica %v: %s", rightRepl, err)
 		}
@@ -230,6 +230,10 @@ type StoreTestingKnobs struct {
 	// through a joint configuration, even when this isn't necessary (because
 	// the replication change affects only one replica).
 	ReplicationAlwaysUseJointConfig func() bool
+	// BeforeRaftEngineTruncation is run before log entries are removed from the
+	// store's separate Raft engine, with the truncated state up to which they
+	// are removed.
+	BeforeRaftEngineTruncation func(roachpb.RangeID, roachpb.RaftTruncatedState)
 	// BeforeSnapshotSSTIngestion is run just before the SSTs are ingested when
 	// applying a snapshot.
 	BeforeSnapshotSSTIngestion func(IncomingSnapshot, SnapshotRequest_Type, []string) error
@@ -58,8 +58,9 @@ func TestingOverrideTxnLivenessThreshold(t time.Duration) func() {
 
 // ShouldPushImmediately returns whether the PushTxn request should
 // proceed without queueing. This is true for pushes which are neither
-// ABORT nor TIMESTAMP, but also for ABORT and TIMESTAMP pushes where
-// the pushee has min priority or pusher has max priority.
+// ABORT nor TIMESTAMP, for TIMESTAMP pushes where the pushee tolerates
+// write skew, but also for ABORT and TIMESTAMP pushes where the pushee
+// has min priority or pusher has max priority.
 func ShouldPushImmediately(req *roachpb.PushTxnRequest) bool {
 	if req.Force {
 		return true
@@ -67,6 +68,9 @@ func ShouldPushImmediately(req *roachpb.PushTxnRequest) bool {
 	if !(req.PushType == roachpb.PUSH_ABORT || req.PushType == roachpb.PUSH_TIMESTAMP) {
 		return true
 	}
+	if req.PushType == roachpb.PUSH_TIMESTAMP && req.PusheeTxn.IsoLevel.ToleratesWriteSkew() {
+		return true
+	}
 	p1, p2 := req.PusherTxn.Priority, req.PusheeTxn.Priority
 	if p1 > p2 && (p1 == enginepb.MaxTxnPriority || p2 == enginepb.MinTxnPriority) {
 		return true
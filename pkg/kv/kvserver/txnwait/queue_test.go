@@ -117,6 +117,38 @@ func TestShouldPushImmediately(t *testing.T) {
 	}
 }
 
+func TestShouldPushImmediatelyIsoLevel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		typ        roachpb.PushTxnType
+		isoLevel   enginepb.IsolationLevel
+		shouldPush bool
+	}{
+		{roachpb.PUSH_ABORT, enginepb.Serializable, false},
+		{roachpb.PUSH_ABORT, enginepb.ReadCommitted, false},
+		{roachpb.PUSH_TIMESTAMP, enginepb.Serializable, false},
+		{roachpb.PUSH_TIMESTAMP, enginepb.ReadCommitted, true},
+	}
+	for _, test := range testCases {
+		t.Run("", func(t *testing.T) {
+			req := roachpb.PushTxnRequest{
+				PushType: test.typ,
+				PusherTxn: roachpb.Transaction{
+					TxnMeta: enginepb.TxnMeta{
+						Priority: enginepb.TxnPriority(1),
+					},
+				},
+				PusheeTxn: enginepb.TxnMeta{
+					Priority: enginepb.TxnPriority(1),
+					IsoLevel: test.isoLevel,
+				},
+			}
+			require.Equal(t, test.shouldPush, ShouldPushImmediately(&req))
+		})
+	}
+}
+
 func makeTS(w int64, l int32) hlc.Timestamp {
 	return hlc.Timestamp{WallTime: w, Logical: l}
 }
@@ -126,6 +126,13 @@ type TxnSender interface {
 	// SetUserPriority sets the txn's priority.
 	SetUserPriority(roachpb.UserPriority) error
 
+	// SetIsoLevel sets the txn's isolation level. It must be called before
+	// any requests are sent through the txn.
+	SetIsoLevel(enginepb.IsolationLevel) error
+
+	// IsoLevel returns the txn's isolation level.
+	IsoLevel() enginepb.IsolationLevel
+
 	// SetDebugName sets the txn's debug name.
 	SetDebugName(name string)
 
@@ -276,6 +283,24 @@ type TxnSender interface {
 	// The method is idempotent.
 	Step(context.Context) error
 
+	// StepReadTimestamp establishes a new read snapshot for transactions
+	// running under an isolation level which permits each statement to read
+	// from its own snapshot. The read timestamp of the transaction is moved
+	// up to the current time, and the spans read so far are forgotten, since
+	// they no longer need to be refreshed. It is a no-op for other isolation
+	// levels and for transactions whose commit timestamp has been fixed.
+	StepReadTimestamp(context.Context) error
+
+	// SetStatementRetriesEnabled configures how the txn handles retryable
+	// errors under an isolation level which permits each statement to read
+	// from its own snapshot. When enabled, retryable errors which do not
+	// abort the transaction leave it at its current epoch and make it reject
+	// further requests until it is rolled back to a savepoint, which lets the
+	// caller retry the statement which hit the error. Disabling statement
+	// retries while such an error is pending restarts the transaction at a
+	// new epoch, as is done for all retryable errors otherwise.
+	SetStatementRetriesEnabled(context.Context, bool)
+
 	// ConfigureStepping sets the sequencing point behavior.
 	//
 	// Note that a Sender is initially in the non-stepping mode,
@@ -290,6 +315,18 @@ type TxnSender interface {
 	// GetSteppingMode accompanies ConfigureStepping. It is provided
 	// for use in tests and assertion checks.
 	GetSteppingMode(ctx context.Context) (curMode SteppingMode)
+
+	// GetReadSeqNum returns the sequence number at which read-only
+	// operations are performed in stepping mode, i.e. the sequence
+	// number established by the last Step().
+	GetReadSeqNum() enginepb.TxnSeq
+
+	// SetReadSeqNum sets the sequence number at which read-only
+	// operations are performed in stepping mode. It lets a reader that
+	// is suspended while other operations step the transaction resume
+	// reading from the snapshot it started with. The sequence number
+	// must have been returned by GetReadSeqNum in the current epoch.
+	SetReadSeqNum(seq enginepb.TxnSeq) error
 }
 
 // SteppingMode is the argument type to ConfigureStepping.
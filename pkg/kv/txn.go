@@ -17,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -49,6 +50,12 @@ type Txn struct {
 	// systemConfigTrigger is set to true when modifying keys from the SystemConfig
 	// span. This sets the SystemConfigTrigger on EndTxnRequest.
 	systemConfigTrigger bool
+	// routingPolicy is attached to all requests sent through this transaction.
+	// See SetRoutingPolicy.
+	routingPolicy roachpb.RoutingPolicy
+	// admissionHeader is attached to all requests sent through this
+	// transaction. See NewTxnWithAdmissionControl.
+	admissionHeader roachpb.AdmissionHeader
 
 	// mu holds fields that need to be synchronized for concurrent request execution.
 	mu struct {
@@ -117,6 +124,26 @@ func NewTxnWithSteppingEnabled(ctx context.Context, db *DB, gatewayNodeID roachp
 	return txn
 }
 
+// NewTxnWithAdmissionControl is like NewTxnWithSteppingEnabled, but the
+// requests sent through the transaction are subject to admission control on
+// the nodes evaluating them, in the order given by the priority and the
+// transaction's creation time.
+func NewTxnWithAdmissionControl(
+	ctx context.Context,
+	db *DB,
+	gatewayNodeID roachpb.NodeID,
+	source roachpb.AdmissionHeader_Source,
+	priority admission.WorkPriority,
+) *Txn {
+	txn := NewTxnWithSteppingEnabled(ctx, db, gatewayNodeID)
+	txn.admissionHeader = roachpb.AdmissionHeader{
+		Priority:   int32(priority),
+		CreateTime: db.clock.PhysicalNow(),
+		Source:     source,
+	}
+	return txn
+}
+
 // NewTxnFromProto is like NewTxn but assumes the Transaction object is already initialized.
 // Do not use this directly; use NewTxn() instead.
 // This function exists for testing only.
@@ -225,6 +252,26 @@ func (txn *Txn) SetUserPriority(userPriority roachpb.UserPriority) error {
 	return txn.mu.sender.SetUserPriority(userPriority)
 }
 
+// SetIsoLevel sets the transaction's isolation level. Transactions default to
+// serializable isolation. The isolation level must be set before any
+// operations are performed on the transaction.
+func (txn *Txn) SetIsoLevel(isoLevel enginepb.IsolationLevel) error {
+	if txn.typ != RootTxn {
+		return errors.AssertionFailedf("SetIsoLevel() called on leaf txn")
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetIsoLevel(isoLevel)
+}
+
+// IsoLevel returns the transaction's isolation level.
+func (txn *Txn) IsoLevel() enginepb.IsolationLevel {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.IsoLevel()
+}
+
 // TestingSetPriority sets the transaction priority. It is intended for
 // internal (testing) use only.
 func (txn *Txn) TestingSetPriority(priority enginepb.TxnPriority) {
@@ -911,6 +958,12 @@ func (txn *Txn) Send(
 	if txn.gatewayNodeID != 0 {
 		ba.Header.GatewayNodeID = txn.gatewayNodeID
 	}
+	if txn.routingPolicy != roachpb.LEASEHOLDER {
+		ba.Header.RoutingPolicy = txn.routingPolicy
+	}
+	if txn.admissionHeader.Source != roachpb.AdmissionHeader_OTHER {
+		ba.AdmissionHeader = txn.admissionHeader
+	}
 
 	txn.mu.Lock()
 	requestTxnID := txn.mu.ID
@@ -944,6 +997,13 @@ func (txn *Txn) handleErrIfRetryableLocked(ctx context.Context, err error) {
 	if !errors.As(err, &retryErr) {
 		return
 	}
+	if retryErr.TxnID == retryErr.Transaction.ID &&
+		txn.mu.sender.Epoch() < retryErr.Transaction.Epoch {
+		// The sender deferred the restart to allow the statement which hit the
+		// error to be retried (see SetStatementRetriesEnabled). The transaction
+		// remains at its current epoch, and so does its deadline.
+		return
+	}
 	txn.resetDeadlineLocked()
 	txn.replaceRootSenderIfTxnAbortedLocked(ctx, retryErr, retryErr.TxnID)
 }
@@ -1126,6 +1186,20 @@ func (txn *Txn) SetFixedTimestamp(ctx context.Context, ts hlc.Timestamp) {
 	txn.mu.sender.SetFixedTimestamp(ctx, ts)
 }
 
+// SetRoutingPolicy sets the policy used to route the transaction's requests to
+// the replicas of each range. It's used by bounded staleness reads, which run
+// at a fixed timestamp that every replica they touch is known to be able to
+// serve, to send their reads to the nearest replica rather than to the
+// leaseholder. Replicas that can't serve a read redirect it to the leaseholder.
+//
+// The policy must be set before the transaction sends any requests.
+func (txn *Txn) SetRoutingPolicy(policy roachpb.RoutingPolicy) {
+	if txn.typ != RootTxn {
+		panic(errors.AssertionFailedf("SetRoutingPolicy() called on leaf txn"))
+	}
+	txn.routingPolicy = policy
+}
+
 // GenerateForcedRetryableError returns a TransactionRetryWithProtoRefreshError that will
 // cause the txn to be retried.
 //
@@ -1247,6 +1321,37 @@ func (txn *Txn) Step(ctx context.Context) error {
 	return txn.mu.sender.Step(ctx)
 }
 
+// StepReadTimestamp establishes a new read snapshot for the following
+// statement, if the transaction's isolation level permits each statement to
+// read from its own snapshot. See TxnSender.StepReadTimestamp.
+// This method is only valid when called on RootTxns.
+func (txn *Txn) StepReadTimestamp(ctx context.Context) error {
+	if txn.typ != RootTxn {
+		return errors.AssertionFailedf("StepReadTimestamp() called on leaf txn")
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.StepReadTimestamp(ctx)
+}
+
+// SetStatementRetriesEnabled configures whether retryable errors leave the
+// transaction in a state where the statement which hit them can be retried
+// by rolling back to a savepoint. See TxnSender.SetStatementRetriesEnabled.
+// This method is only valid when called on RootTxns.
+func (txn *Txn) SetStatementRetriesEnabled(ctx context.Context, enabled bool) {
+	if txn.typ != RootTxn {
+		panic(errors.AssertionFailedf("SetStatementRetriesEnabled() called on leaf txn"))
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	prevEpoch := txn.mu.sender.Epoch()
+	txn.mu.sender.SetStatementRetriesEnabled(ctx, enabled)
+	if txn.mu.sender.Epoch() != prevEpoch {
+		// A deferred restart was performed.
+		txn.resetDeadlineLocked()
+	}
+}
+
 // ConfigureStepping configures step-wise execution in the
 // transaction.
 func (txn *Txn) ConfigureStepping(ctx context.Context, mode SteppingMode) (prevMode SteppingMode) {
@@ -1255,6 +1360,23 @@ func (txn *Txn) ConfigureStepping(ctx context.Context, mode SteppingMode) (prevM
 	return txn.mu.sender.ConfigureStepping(ctx, mode)
 }
 
+// GetReadSeqNum returns the sequence number at which the transaction's
+// read-only operations are performed in step-wise execution.
+func (txn *Txn) GetReadSeqNum() enginepb.TxnSeq {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.GetReadSeqNum()
+}
+
+// SetReadSeqNum sets the sequence number at which the transaction's read-only
+// operations are performed in step-wise execution. See
+// TxnSender.SetReadSeqNum.
+func (txn *Txn) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetReadSeqNum(seq)
+}
+
 // CreateSavepoint establishes a savepoint.
 // This method is only valid when called on RootTxns.
 func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
@@ -151,6 +151,18 @@ func LockingDurability(args Request) lock.Durability {
 	return lock.Replicated
 }
 
+// LockingStrength returns the strength of the locks acquired by the request.
+// The function assumes that IsLocking(args).
+func LockingStrength(args Request) lock.Strength {
+	switch t := args.(type) {
+	case *ScanRequest:
+		return t.KeyLocking
+	case *ReverseScanRequest:
+		return t.KeyLocking
+	}
+	return lock.Exclusive
+}
+
 // IsIntentWrite returns true if the request produces write intents at
 // the request's sequence number when used within a transaction.
 func IsIntentWrite(args Request) bool {
@@ -360,6 +372,20 @@ func (avptr *AdminVerifyProtectedTimestampResponse) combine(c combinable) error
 
 var _ combinable = &AdminVerifyProtectedTimestampResponse{}
 
+// combine implements the combinable interface.
+func (qr *QueryResolvedTimestampResponse) combine(c combinable) error {
+	if qr != nil {
+		otherQR := c.(*QueryResolvedTimestampResponse)
+		if err := qr.ResponseHeader.combine(otherQR.Header()); err != nil {
+			return err
+		}
+		qr.ResolvedTS.Backward(otherQR.ResolvedTS)
+	}
+	return nil
+}
+
+var _ combinable = &QueryResolvedTimestampResponse{}
+
 // combine implements the combinable interface.
 func (sr *ReverseScanResponse) combine(c combinable) error {
 	otherSR := c.(*ReverseScanResponse)
@@ -748,6 +774,9 @@ func (*RangeStatsRequest) Method() Method { return RangeStats }
 // Method implements the Request interface.
 func (*AdminVerifyProtectedTimestampRequest) Method() Method { return AdminVerifyProtectedTimestamp }
 
+// Method implements the Request interface.
+func (*QueryResolvedTimestampRequest) Method() Method { return QueryResolvedTimestamp }
+
 // ShallowCopy implements the Request interface.
 func (gr *GetRequest) ShallowCopy() Request {
 	shallowCopy := *gr
@@ -1012,6 +1041,12 @@ func (r *AdminVerifyProtectedTimestampRequest) ShallowCopy() Request {
 	return &shallowCopy
 }
 
+// ShallowCopy implements the Request interface.
+func (r *QueryResolvedTimestampRequest) ShallowCopy() Request {
+	shallowCopy := *r
+	return &shallowCopy
+}
+
 // NewGet returns a Request initialized to get the value at key.
 func NewGet(key Key) Request {
 	return &GetRequest{
@@ -1210,6 +1245,12 @@ func (drr *DeleteRangeRequest) flags() int {
 	if drr.Inline {
 		return isWrite | isRange | isAlone
 	}
+	// Similarly, MVCC range tombstones cannot be written transactionally. They
+	// consult the timestamp cache so that they are not written beneath a read
+	// of the span they delete.
+	if drr.UseRangeTombstone {
+		return isWrite | isRange | isAlone | consultsTSCache
+	}
 	// DeleteRange updates the timestamp cache as it doesn't leave intents or
 	// tombstones for keys which don't yet exist, but still wants to prevent
 	// anybody from writing under it. Note that, even if we didn't update the ts
@@ -1317,6 +1358,10 @@ func (r *RefreshRangeRequest) flags() int {
 func (*SubsumeRequest) flags() int    { return isRead | isAlone | updatesTSCache }
 func (*RangeStatsRequest) flags() int { return isRead }
 
+// QueryResolvedTimestampRequest is sent with INCONSISTENT read consistency, so
+// it can be evaluated by any replica without a lease.
+func (*QueryResolvedTimestampRequest) flags() int { return isRead | isRange }
+
 // IsParallelCommit returns whether the EndTxn request is attempting to perform
 // a parallel commit. See txn_interceptor_committer.go for a discussion about
 // parallel commits.
@@ -1418,16 +1463,30 @@ func (rc ReplicationChanges) byType(typ ReplicaChangeType) []ReplicationTarget {
 	return sl
 }
 
-// Additions returns a slice of all contained replication changes that add replicas.
+// Additions returns a slice of all contained replication changes that add
+// voting replicas.
 func (rc ReplicationChanges) Additions() []ReplicationTarget {
 	return rc.byType(ADD_REPLICA)
 }
 
-// Removals returns a slice of all contained replication changes that remove replicas.
+// Removals returns a slice of all contained replication changes that remove
+// voting replicas.
 func (rc ReplicationChanges) Removals() []ReplicationTarget {
 	return rc.byType(REMOVE_REPLICA)
 }
 
+// NonVoterAdditions returns a slice of all contained replication changes that
+// add non-voting replicas.
+func (rc ReplicationChanges) NonVoterAdditions() []ReplicationTarget {
+	return rc.byType(ADD_NON_VOTER)
+}
+
+// NonVoterRemovals returns a slice of all contained replication changes that
+// remove non-voting replicas.
+func (rc ReplicationChanges) NonVoterRemovals() []ReplicationTarget {
+	return rc.byType(REMOVE_NON_VOTER)
+}
+
 // Changes returns the changes requested by this AdminChangeReplicasRequest, taking
 // the deprecated method of doing so into account.
 func (acrr *AdminChangeReplicasRequest) Changes() []ReplicationChange {
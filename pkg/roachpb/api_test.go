@@ -14,6 +14,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/stretchr/testify/require"
 )
 
@@ -216,6 +217,26 @@ func TestCombinable(t *testing.T) {
 		}, v1)
 
 	})
+
+	t.Run("QueryResolvedTimestamp", func(t *testing.T) {
+		v1 := &QueryResolvedTimestampResponse{
+			ResolvedTS: hlc.Timestamp{WallTime: 2},
+		}
+
+		if _, ok := interface{}(v1).(combinable); !ok {
+			t.Fatal("QueryResolvedTimestampResponse unexpectedly does not implement combinable")
+		}
+		v2 := &QueryResolvedTimestampResponse{
+			ResolvedTS: hlc.Timestamp{WallTime: 3},
+		}
+		v3 := &QueryResolvedTimestampResponse{
+			ResolvedTS: hlc.Timestamp{WallTime: 1},
+		}
+		require.NoError(t, v1.combine(v2))
+		require.EqualValues(t, hlc.Timestamp{WallTime: 2}, v1.ResolvedTS)
+		require.NoError(t, v1.combine(v3))
+		require.EqualValues(t, hlc.Timestamp{WallTime: 1}, v1.ResolvedTS)
+	})
 }
 
 // TestMustSetInner makes sure that calls to MustSetInner correctly reset the
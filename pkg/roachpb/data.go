@@ -1069,6 +1069,11 @@ func (t *Transaction) Update(o *Transaction) {
 	if len(t.Key) == 0 {
 		t.Key = o.Key
 	}
+	// Like the key, the isolation level is set before the transaction sends
+	// its first request, so it can only be missing from t.
+	if t.IsoLevel == enginepb.Serializable {
+		t.IsoLevel = o.IsoLevel
+	}
 
 	// Update epoch-scoped state, depending on the two transactions' epochs.
 	if t.Epoch < o.Epoch {
@@ -1379,6 +1384,7 @@ func PrepareTransactionForRetry(
 		// TODO(andrei): Should we preserve the ObservedTimestamps across the
 		// restart?
 		errTxnPri := txn.Priority
+		errTxnIsoLevel := txn.IsoLevel
 		// Start the new transaction at the current time from the local clock.
 		// The local hlc should have been advanced to at least the error's
 		// timestamp already.
@@ -1394,6 +1400,8 @@ func PrepareTransactionForRetry(
 		)
 		// Use the priority communicated back by the server.
 		txn.Priority = errTxnPri
+		// The new transaction runs under the same isolation level.
+		txn.IsoLevel = errTxnIsoLevel
 	case *ReadWithinUncertaintyIntervalError:
 		txn.WriteTimestamp.Forward(
 			readWithinUncertaintyIntervalRetryTimestamp(ctx, &txn, tErr, pErr.OriginNode))
@@ -1564,13 +1572,14 @@ func confChangeImpl(
 			if err := checkExists(rDesc); err != nil {
 				return nil, err
 			}
-		case VOTER_DEMOTING:
+		case VOTER_DEMOTING, VOTER_DEMOTING_NON_VOTER:
 			// If a voter is demoted through joint consensus, it will
 			// be turned into a demoting voter first.
 			if err := checkExists(rDesc); err != nil {
 				return nil, err
 			}
-			// It's being re-added as a learner, not only removed.
+			// It's being re-added as a learner (or a non-voter, which raft
+			// doesn't distinguish from learners), not only removed.
 			sl = append(sl, raftpb.ConfChangeSingle{
 				Type:   raftpb.ConfChangeAddLearnerNode,
 				NodeID: uint64(rDesc.ReplicaID),
@@ -1585,8 +1594,9 @@ func confChangeImpl(
 			if err := checkNotExists(rDesc); err != nil {
 				return nil, err
 			}
-		case VOTER_FULL:
-			// A voter can't be in the descriptor if it's being removed.
+		case VOTER_FULL, NON_VOTER:
+			// A voter or non-voter can't be in the descriptor if it's being
+			// removed.
 			if err := checkNotExists(rDesc); err != nil {
 				return nil, err
 			}
@@ -1610,7 +1620,7 @@ func confChangeImpl(
 			changeType = raftpb.ConfChangeAddNode
 		case VOTER_INCOMING:
 			// We're adding a voter, but will transition into a joint config
-			// first.
+			// first. This is also how a non-voter is promoted to a voter.
 			changeType = raftpb.ConfChangeAddNode
 		case LEARNER:
 			// We're adding a learner.
@@ -1619,6 +1629,10 @@ func confChangeImpl(
 			// Demotions (i.e. transitioning from voter to learner) are not
 			// represented in `added`; they're handled in `removed` above.
 			changeType = raftpb.ConfChangeAddLearnerNode
+		case NON_VOTER:
+			// We're adding a non-voter, which is a learner as far as raft is
+			// concerned. Demotions to non-voters are handled in `removed` above.
+			changeType = raftpb.ConfChangeAddLearnerNode
 		default:
 			// A voter that is demoting was just removed and re-added in the
 			// `removals` handler. We should not see it again here.
@@ -1638,7 +1652,7 @@ func confChangeImpl(
 	var enteringJoint bool
 	for _, rDesc := range replicas {
 		switch rDesc.GetType() {
-		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING:
+		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING, VOTER_DEMOTING_NON_VOTER:
 			enteringJoint = true
 		default:
 		}
@@ -2003,9 +2017,11 @@ func AsIntents(txn *enginepb.TxnMeta, keys []Key) []Intent {
 }
 
 // MakeLockAcquisition makes a lock acquisition message from the given
-// txn, key, and durability level.
-func MakeLockAcquisition(txn *Transaction, key Key, dur lock.Durability) LockAcquisition {
-	return LockAcquisition{Span: Span{Key: key}, Txn: txn.TxnMeta, Durability: dur}
+// txn, key, strength, and durability level.
+func MakeLockAcquisition(
+	txn *Transaction, key Key, str lock.Strength, dur lock.Durability,
+) LockAcquisition {
+	return LockAcquisition{Span: Span{Key: key}, Txn: txn.TxnMeta, Strength: str, Durability: dur}
 }
 
 // MakeLockUpdate makes a lock update from the given txn and span.
@@ -2364,6 +2380,28 @@ func init() {
 // SafeValue implements the redact.SafeValue interface.
 func (ReplicaChangeType) SafeValue() {}
 
+// IsAddition returns whether the change type adds a replica, either a voter or
+// a non-voter.
+func (c ReplicaChangeType) IsAddition() bool {
+	switch c {
+	case ADD_REPLICA, ADD_NON_VOTER:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRemoval returns whether the change type removes a replica, either a voter
+// or a non-voter.
+func (c ReplicaChangeType) IsRemoval() bool {
+	switch c {
+	case REMOVE_REPLICA, REMOVE_NON_VOTER:
+		return true
+	default:
+		return false
+	}
+}
+
 func (ri RangeInfo) String() string {
 	return fmt.Sprintf("desc: %s lease: %s", ri.Desc, ri.Lease)
 }
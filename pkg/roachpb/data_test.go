@@ -460,6 +460,7 @@ var nonZeroTxn = Transaction{
 		MinTimestamp:   makeTS(10, 11),
 		Priority:       957356782,
 		Sequence:       123,
+		IsoLevel:       enginepb.ReadCommitted,
 	},
 	Name:                 "name",
 	Status:               COMMITTED,
@@ -1752,6 +1753,7 @@ func TestChangeReplicasTrigger_ConfChange(t *testing.T) {
 	vo1 := sl(VOTER_OUTGOING, 1)
 	vi1 := sl(VOTER_INCOMING, 1)
 	vl1 := sl(LEARNER, 1)
+	nv1 := sl(NON_VOTER, 1)
 
 	testCases := []struct {
 		crt mockCRT
@@ -1883,6 +1885,37 @@ func TestChangeReplicasTrigger_ConfChange(t *testing.T) {
 				}},
 		},
 
+		// Adding and removing a non-voter, which raft treats as a learner.
+		{crt: mk(in{v2: true, add: nv1, repls: nv1}), exp: raftpb.ConfChangeV2{
+			Transition: raftpb.ConfChangeTransitionAuto,
+			Changes: []raftpb.ConfChangeSingle{{
+				Type:   raftpb.ConfChangeAddLearnerNode,
+				NodeID: 1,
+			}},
+		}},
+		{crt: mk(in{v2: true, del: nv1, repls: nv1}), err: "(n3,s2):1NON_VOTER must no longer be present in descriptor"},
+		{crt: mk(in{v2: true, del: nv1}), exp: raftpb.ConfChangeV2{
+			Transition: raftpb.ConfChangeTransitionAuto,
+			Changes: []raftpb.ConfChangeSingle{{
+				Type:   raftpb.ConfChangeRemoveNode,
+				NodeID: 1,
+			}},
+		}},
+
+		// Promoting non-voter 2 while demoting voter 1 to a non-voter.
+		{crt: mk(in{
+			add:   sl(VOTER_INCOMING, 2),
+			del:   sl(VOTER_DEMOTING_NON_VOTER, 1),
+			repls: sl(VOTER_DEMOTING_NON_VOTER, 1, VOTER_INCOMING, 2, VOTER_FULL, 3),
+		}), exp: raftpb.ConfChangeV2{
+			Transition: raftpb.ConfChangeTransitionJointExplicit,
+			Changes: []raftpb.ConfChangeSingle{
+				{NodeID: 1, Type: raftpb.ConfChangeRemoveNode},
+				{NodeID: 1, Type: raftpb.ConfChangeAddLearnerNode},
+				{NodeID: 2, Type: raftpb.ConfChangeAddNode},
+			}},
+		},
+
 		// Leave a joint config.
 		{
 			crt: mk(in{repls: sl(VOTER_FULL, 1)}),
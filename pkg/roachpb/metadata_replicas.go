@@ -52,6 +52,20 @@ func ReplicaTypeLearner() *ReplicaType {
 	return &t
 }
 
+// ReplicaTypeNonVoter returns a NON_VOTER pointer suitable for use in
+// a nullable proto field.
+func ReplicaTypeNonVoter() *ReplicaType {
+	t := NON_VOTER
+	return &t
+}
+
+// ReplicaTypeVoterDemotingNonVoter returns a VOTER_DEMOTING_NON_VOTER pointer
+// suitable for use in a nullable proto field.
+func ReplicaTypeVoterDemotingNonVoter() *ReplicaType {
+	t := VOTER_DEMOTING_NON_VOTER
+	return &t
+}
+
 // ReplicaDescriptors is a set of replicas, usually the nodes/stores on which
 // replicas of a range are stored.
 type ReplicaDescriptors struct {
@@ -89,8 +103,8 @@ func (d ReplicaDescriptors) String() string {
 	return redact.StringWithoutMarkers(d)
 }
 
-// All returns every replica in the set, including both voter replicas and
-// learner replicas. Voter replicas are ordered first in the returned slice.
+// All returns every replica in the set, including voter, non-voter and learner
+// replicas. Voter replicas are ordered first in the returned slice.
 func (d ReplicaDescriptors) All() []ReplicaDescriptor {
 	return d.wrapped
 }
@@ -108,12 +122,20 @@ func predLearner(rDesc ReplicaDescriptor) bool {
 	return rDesc.GetType() == LEARNER
 }
 
+func predNonVoter(rDesc ReplicaDescriptor) bool {
+	return rDesc.GetType() == NON_VOTER
+}
+
+func predVoterOrNonVoter(rDesc ReplicaDescriptor) bool {
+	return predVoterFullOrIncoming(rDesc) || predNonVoter(rDesc)
+}
+
 // Voters returns the current and future voter replicas in the set. This means
 // that during an atomic replication change, only the replicas that will be
 // voters once the change completes will be returned; "outgoing" voters will not
 // be returned even though they do in the current state retain their voting
 // rights. When no atomic membership change is ongoing, this is simply the set
-// of all non-learners.
+// of all replicas that are neither learners nor non-voters.
 //
 // This may allocate, but it also may return the underlying slice as a
 // performance optimization, so it's not safe to modify the returned value.
@@ -139,11 +161,12 @@ func (d ReplicaDescriptors) Voters() []ReplicaDescriptor {
 // ConfChange, a raft snapshot (of type LEARNER) is sent to catch it up, and
 // then a second ConfChange promotes it to a full replica.
 //
-// This means that learners are currently always expected to have a short
-// lifetime, approximately the time it takes to send a snapshot. Ideas have been
-// kicked around to use learners with follower reads, which could be a cheap way
-// to allow many geographies to have local reads without affecting write
-// latencies. If implemented, these learners would have long lifetimes.
+// This means that learners are always expected to have a short lifetime,
+// approximately the time it takes to send a snapshot. Long-lived replicas that
+// don't vote, which allow many geographies to have local follower reads without
+// affecting write latencies, are non-voters instead (see NonVoters). Raft
+// treats both the same way, but non-voters are never removed just because they
+// exist.
 //
 // For simplicity, CockroachDB treats learner replicas the same as voter
 // replicas as much as possible, but there are a few exceptions:
@@ -215,6 +238,28 @@ func (d ReplicaDescriptors) Learners() []ReplicaDescriptor {
 	return d.Filter(predLearner)
 }
 
+// NonVoters returns the non-voting replicas in the set. This may allocate, but
+// it also may return the underlying slice as a performance optimization, so
+// it's not safe to modify the returned value.
+//
+// Non-voters receive the raft log and can serve follower reads, but they are
+// raft learners and so don't count towards quorum, can't become the raft leader
+// and can't hold the lease. Unlike learners, they are long-lived: the allocator
+// adds and removes them to satisfy the difference between the num_replicas and
+// num_voters fields of the zone config. Voters which are being demoted to
+// non-voters (VOTER_DEMOTING_NON_VOTER) are not included.
+func (d ReplicaDescriptors) NonVoters() []ReplicaDescriptor {
+	return d.Filter(predNonVoter)
+}
+
+// VotersAndNonVoters returns the current and future voters (see Voters) as well
+// as the non-voting replicas in the set, i.e. all the replicas which are
+// expected to have a copy of the range's data once any ongoing atomic
+// replication change completes, and which can serve follower reads.
+func (d ReplicaDescriptors) VotersAndNonVoters() []ReplicaDescriptor {
+	return d.Filter(predVoterOrNonVoter)
+}
+
 // Filter returns only the replica descriptors for which the supplied method
 // returns true. The memory returned may be shared with the receiver.
 func (d ReplicaDescriptors) Filter(pred func(rDesc ReplicaDescriptor) bool) []ReplicaDescriptor {
@@ -285,9 +330,9 @@ func (d *ReplicaDescriptors) RemoveReplica(
 func (d ReplicaDescriptors) InAtomicReplicationChange() bool {
 	for _, rDesc := range d.wrapped {
 		switch rDesc.GetType() {
-		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING:
+		case VOTER_INCOMING, VOTER_OUTGOING, VOTER_DEMOTING, VOTER_DEMOTING_NON_VOTER:
 			return true
-		case VOTER_FULL, LEARNER:
+		case VOTER_FULL, LEARNER, NON_VOTER:
 		default:
 			panic(fmt.Sprintf("unknown replica type %d", rDesc.GetType()))
 		}
@@ -315,10 +360,11 @@ func (d ReplicaDescriptors) ConfState() raftpb.ConfState {
 			cs.Voters = append(cs.Voters, id)
 		case VOTER_OUTGOING:
 			cs.VotersOutgoing = append(cs.VotersOutgoing, id)
-		case VOTER_DEMOTING:
+		case VOTER_DEMOTING, VOTER_DEMOTING_NON_VOTER:
 			cs.VotersOutgoing = append(cs.VotersOutgoing, id)
 			cs.LearnersNext = append(cs.LearnersNext, id)
-		case LEARNER:
+		case LEARNER, NON_VOTER:
+			// Raft doesn't distinguish between learners and non-voters.
 			cs.Learners = append(cs.Learners, id)
 		default:
 			panic(fmt.Sprintf("unknown ReplicaType %d", typ))
@@ -333,7 +379,7 @@ func (d ReplicaDescriptors) ConfState() raftpb.ConfState {
 func (d ReplicaDescriptors) CanMakeProgress(liveFunc func(descriptor ReplicaDescriptor) bool) bool {
 	isVoterOldConfig := func(rDesc ReplicaDescriptor) bool {
 		switch rDesc.GetType() {
-		case VOTER_FULL, VOTER_OUTGOING, VOTER_DEMOTING:
+		case VOTER_FULL, VOTER_OUTGOING, VOTER_DEMOTING, VOTER_DEMOTING_NON_VOTER:
 			return true
 		default:
 			return false
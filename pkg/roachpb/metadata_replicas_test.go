@@ -43,6 +43,8 @@ var vi = ReplicaTypeVoterIncoming()
 var vo = ReplicaTypeVoterOutgoing()
 var vd = ReplicaTypeVoterDemoting()
 var l = ReplicaTypeLearner()
+var nv = ReplicaTypeNonVoter()
+var vdnv = ReplicaTypeVoterDemotingNonVoter()
 
 func TestVotersLearnersAll(t *testing.T) {
 
@@ -58,6 +60,9 @@ func TestVotersLearnersAll(t *testing.T) {
 		{rd(vi, 1)},
 		{rd(vo, 1)},
 		{rd(l, 1), rd(vo, 2), rd(vi, 3), rd(vi, 4)},
+		{rd(nv, 1)},
+		{rd(v, 1), rd(nv, 2), rd(l, 3)},
+		{rd(nv, 1), rd(vdnv, 2), rd(vi, 3), rd(nv, 4)},
 	}
 	for _, test := range tests {
 		t.Run("", func(t *testing.T) {
@@ -76,16 +81,21 @@ func TestVotersLearnersAll(t *testing.T) {
 				seen[learner] = struct{}{}
 				assert.Equal(t, LEARNER, learner.GetType())
 			}
+			for _, nonVoter := range r.NonVoters() {
+				seen[nonVoter] = struct{}{}
+				assert.Equal(t, NON_VOTER, nonVoter.GetType())
+			}
+			assert.Equal(t, len(r.Voters())+len(r.NonVoters()), len(r.VotersAndNonVoters()))
 
 			all := r.All()
-			// Make sure that VOTER_OUTGOING is the only type that is skipped both
-			// by Learners() and Voters()
+			// Make sure that the outgoing voter types are the only types that are
+			// skipped by Learners(), NonVoters() and Voters().
 			for _, rd := range all {
 				typ := rd.GetType()
 				if _, seen := seen[rd]; !seen {
-					assert.Equal(t, VOTER_OUTGOING, typ)
+					assert.Contains(t, []ReplicaType{VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER}, typ)
 				} else {
-					assert.NotEqual(t, VOTER_OUTGOING, typ)
+					assert.NotContains(t, []ReplicaType{VOTER_OUTGOING, VOTER_DEMOTING_NON_VOTER}, typ)
 				}
 			}
 			assert.Equal(t, len(test), len(all))
@@ -198,6 +208,16 @@ func TestReplicaDescriptorsConfState(t *testing.T) {
 			[]ReplicaDescriptor{rd(vo, 1), rd(vd, 2), rd(vi, 3), rd(vi, 4), rd(l, 5)},
 			"Voters:[3 4] VotersOutgoing:[1 2] Learners:[5] LearnersNext:[2] AutoLeave:false",
 		},
+		// Non-voters are learners as far as raft is concerned.
+		{
+			[]ReplicaDescriptor{rd(v, 1), rd(nv, 2), rd(l, 3)},
+			"Voters:[1] VotersOutgoing:[] Learners:[2 3] LearnersNext:[] AutoLeave:false",
+		},
+		// Demoting n2 to a non-voter while promoting the non-voter n3.
+		{
+			[]ReplicaDescriptor{rd(v, 1), rd(vdnv, 2), rd(vi, 3), rd(nv, 4)},
+			"Voters:[1 3] VotersOutgoing:[1 2] Learners:[4] LearnersNext:[2] AutoLeave:false",
+		},
 	}
 
 	for _, test := range tests {
@@ -158,6 +158,9 @@ const (
 	// VerifyProtectedTimestamp determines whether the specified protection record
 	// will be respected by this Range.
 	AdminVerifyProtectedTimestamp
+	// QueryResolvedTimestamp returns the timestamp below which a replica can
+	// serve consistent reads without consulting the leaseholder.
+	QueryResolvedTimestamp
 	// NumMethods represents the total number of API methods.
 	NumMethods
 )
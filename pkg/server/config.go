@@ -54,6 +54,9 @@ const (
 	defaultScanInterval      = 10 * time.Minute
 	defaultScanMinIdleTime   = 10 * time.Millisecond
 	defaultScanMaxIdleTime   = 1 * time.Second
+	// defaultRangefeedMemoryBudget is the default amount of memory that the
+	// rangefeed registrations on a node may use to buffer events.
+	defaultRangefeedMemoryBudget = 256 << 20 // 256 MB
 
 	DefaultStorePath = "cockroach-data"
 	// TempDirPrefix is the filename prefix of any temporary subdirectory
@@ -204,6 +207,12 @@ type KVConfig struct {
 	// server.
 	TimeSeriesServerConfig ts.ServerConfig
 
+	// RangefeedMemoryBudget is the amount of memory in bytes that the rangefeed
+	// registrations on this node may use to buffer events, including during
+	// their catch-up scans. Registrations exceeding it are disconnected.
+	// Environment Variable: COCKROACH_RANGEFEED_MEMORY_BUDGET
+	RangefeedMemoryBudget int64
+
 	// Parsed values.
 
 	// NodeAttributes is the parsed representation of Attrs.
@@ -287,6 +296,8 @@ func MakeKVConfig(storeSpec base.StoreSpec) KVConfig {
 		ScanMaxIdleTime:                defaultScanMaxIdleTime,
 		EventLogEnabled:                defaultEventLogEnabled,
 		EnableWebSessionAuthentication: !disableWebLogin,
+		RangefeedMemoryBudget: envutil.EnvOrDefaultBytes(
+			"COCKROACH_RANGEFEED_MEMORY_BUDGET", defaultRangefeedMemoryBudget),
 		Stores: base.StoreSpecList{
 			Specs: []base.StoreSpec{storeSpec},
 		},
@@ -632,6 +643,54 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 	return enginesCopy, nil
 }
 
+// RaftEngines maps the engines of stores which keep their Raft log in a
+// separate engine to that engine, allowing convenient closing.
+type RaftEngines map[storage.Engine]storage.Engine
+
+// Close closes all the Raft engines.
+func (e *RaftEngines) Close() {
+	for _, raftEng := range *e {
+		raftEng.Close()
+	}
+	*e = nil
+}
+
+// CreateRaftEngines creates the separate Raft engines of the stores in
+// cfg.Stores whose spec sets a Raft log path. The engines must be those
+// returned by CreateEngines.
+func (cfg *Config) CreateRaftEngines(ctx context.Context, engines Engines) (RaftEngines, error) {
+	raftEngines := RaftEngines{}
+	defer raftEngines.Close()
+
+	for i, spec := range cfg.Stores.Specs {
+		if spec.RaftLogPath == "" {
+			continue
+		}
+		if cfg.StorageEngine != enginepb.EngineTypeDefault && cfg.StorageEngine != enginepb.EngineTypePebble {
+			return nil, errors.Errorf("store %d: a separate Raft log requires the Pebble storage engine", i)
+		}
+		pebbleConfig := storage.PebbleConfig{
+			StorageConfig: base.StorageConfig{
+				Dir:             spec.RaftLogPath,
+				Settings:        cfg.Settings,
+				UseFileRegistry: spec.UseFileRegistry,
+				ExtraOptions:    spec.ExtraOptions,
+			},
+			Opts: storage.DefaultPebbleOptions(),
+		}
+		raftEng, err := storage.NewPebble(ctx, pebbleConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "store %d: creating Raft engine", i)
+		}
+		raftEngines[engines[i]] = raftEng
+		log.Infof(ctx, "store %d: Raft log in %s", i, spec.RaftLogPath)
+	}
+
+	raftEnginesCopy := raftEngines
+	raftEngines = nil
+	return raftEnginesCopy, nil
+}
+
 // InitNode parses node attributes and initializes the gossip bootstrap
 // resolvers.
 func (cfg *Config) InitNode(ctx context.Context) error {
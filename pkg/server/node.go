@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/url"
 	"sort"
 	"time"
 
@@ -36,9 +37,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/bootstrap"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -62,6 +65,9 @@ const (
 	FirstNodeID         = 1
 	graphiteIntervalKey = "external.graphite.interval"
 	maxGraphiteInterval = 15 * time.Minute
+
+	remoteWriteIntervalKey = "external.prometheus.remote_write.interval"
+	maxRemoteWriteInterval = 15 * time.Minute
 )
 
 // Metric names.
@@ -108,6 +114,54 @@ var (
 		10*time.Second,
 		maxGraphiteInterval,
 	)
+	// remoteWriteEndpoint is the URL, if any, of a Prometheus remote write
+	// endpoint.
+	remoteWriteEndpoint = func() *settings.StringSetting {
+		s := settings.RegisterValidatedStringSetting(
+			"external.prometheus.remote_write.endpoint",
+			"if nonempty, push server metrics to the Prometheus remote write endpoint at the specified URL",
+			"",
+			func(_ *settings.Values, s string) error {
+				if s == "" {
+					return nil
+				}
+				u, err := url.Parse(s)
+				if err != nil {
+					return err
+				}
+				if u.Scheme != "http" && u.Scheme != "https" {
+					return errors.Newf("invalid remote write URL %q: scheme must be http or https", s)
+				}
+				return nil
+			},
+		)
+		s.SetVisibility(settings.Public)
+		return s
+	}()
+	// remoteWriteInterval is how often metrics are pushed to the remote write
+	// endpoint, if enabled.
+	remoteWriteInterval = settings.RegisterPublicNonNegativeDurationSettingWithMaximum(
+		remoteWriteIntervalKey,
+		"the interval at which metrics are pushed to the Prometheus remote write endpoint (if enabled)",
+		10*time.Second,
+		maxRemoteWriteInterval,
+	)
+	// remoteWriteLabels are added to every series pushed to the remote write
+	// endpoint.
+	remoteWriteLabels = func() *settings.StringSetting {
+		s := settings.RegisterValidatedStringSetting(
+			"external.prometheus.remote_write.labels",
+			"comma-separated name=value labels added to every metric pushed to the Prometheus remote "+
+				"write endpoint (an instance label holding the node's HTTP address is added unless specified)",
+			"",
+			func(_ *settings.Values, s string) error {
+				_, err := metric.ParseRemoteWriteLabels(s)
+				return err
+			},
+		)
+		s.SetVisibility(settings.Public)
+		return s
+	}()
 )
 
 type nodeMetrics struct {
@@ -130,14 +184,20 @@ func makeNodeMetrics(reg *metric.Registry, histogramWindow time.Duration) nodeMe
 
 // callComplete records very high-level metrics about the number of completed
 // calls and their latency. Currently, this only records statistics at the batch
-// level; stats on specific lower-level kv operations are not recorded.
-func (nm nodeMetrics) callComplete(d time.Duration, pErr *roachpb.Error) {
+// level; stats on specific lower-level kv operations are not recorded. If the
+// call was traced by an exported trace, the latency is recorded with the trace
+// as an exemplar.
+func (nm nodeMetrics) callComplete(ctx context.Context, d time.Duration, pErr *roachpb.Error) {
 	if pErr != nil && pErr.TransactionRestart == roachpb.TransactionRestart_NONE {
 		nm.Err.Inc(1)
 	} else {
 		nm.Success.Inc(1)
 	}
-	nm.Latency.RecordValue(d.Nanoseconds())
+	if traceID, ok := tracing.ExportedTraceID(ctx); ok {
+		nm.Latency.RecordValueWithExemplar(d.Nanoseconds(), traceID)
+	} else {
+		nm.Latency.RecordValue(d.Nanoseconds())
+	}
 }
 
 // A Node manages a map of stores (by store ID) for which it serves
@@ -164,6 +224,11 @@ type Node struct {
 	txnMetrics   kvcoord.TxnMetrics
 
 	perReplicaServer kvserver.Server
+
+	// kvAdmissionQ and storeGrantCoords, if set, subject the batches issued by
+	// SQL to admission control. See admitBatch.
+	kvAdmissionQ     *admission.WorkQueue
+	storeGrantCoords *admission.StoreGrantCoordinators
 }
 
 var _ roachpb.InternalServer = &Node{}
@@ -292,6 +357,8 @@ func NewNode(
 	txnMetrics kvcoord.TxnMetrics,
 	execCfg *sql.ExecutorConfig,
 	clusterID *base.ClusterIDContainer,
+	kvAdmissionQ *admission.WorkQueue,
+	storeGrantCoords *admission.StoreGrantCoordinators,
 ) *Node {
 	var eventLogger sql.EventLogger
 	if execCfg != nil {
@@ -306,6 +373,9 @@ func NewNode(
 		txnMetrics:  txnMetrics,
 		eventLogger: eventLogger,
 		clusterID:   clusterID,
+
+		kvAdmissionQ:     kvAdmissionQ,
+		storeGrantCoords: storeGrantCoords,
 	}
 	n.perReplicaServer = kvserver.MakeServer(&n.Descriptor, n.stores)
 	return n
@@ -732,6 +802,44 @@ func (n *Node) startGraphiteStatsExporter(st *cluster.Settings) {
 	})
 }
 
+// startPrometheusRemoteWriteExporter begins periodically pushing the node's
+// metrics to the configured Prometheus remote write endpoint. The instance
+// label of the pushed series defaults to the supplied address.
+func (n *Node) startPrometheusRemoteWriteExporter(st *cluster.Settings, instance string) {
+	ctx := logtags.AddTag(n.AnnotateCtx(context.Background()), "prometheus remote write exporter", nil)
+	pm := metric.MakePrometheusExporter()
+
+	n.stopper.RunWorker(ctx, func(ctx context.Context) {
+		var timer timeutil.Timer
+		defer timer.Stop()
+		for {
+			timer.Reset(remoteWriteInterval.Get(&st.SV))
+			select {
+			case <-n.stopper.ShouldStop():
+				return
+			case <-timer.C:
+				timer.Read = true
+				endpoint := remoteWriteEndpoint.Get(&st.SV)
+				if endpoint == "" {
+					continue
+				}
+				labels, err := metric.ParseRemoteWriteLabels(remoteWriteLabels.Get(&st.SV))
+				if err != nil {
+					// The setting is validated, so this should not happen.
+					log.Warningf(ctx, "invalid remote write labels: %v", err)
+					continue
+				}
+				if _, ok := labels["instance"]; !ok {
+					labels["instance"] = instance
+				}
+				if err := n.recorder.ExportToPrometheusRemoteWrite(ctx, endpoint, labels, &pm); err != nil {
+					log.Infof(ctx, "error pushing metrics to prometheus remote write endpoint: %s", err)
+				}
+			}
+		}
+	})
+}
+
 // startWriteNodeStatus begins periodically persisting status summaries for the
 // node and its stores.
 func (n *Node) startWriteNodeStatus(frequency time.Duration) {
@@ -810,12 +918,22 @@ func (n *Node) recordJoinEvent() {
 		return
 	}
 
-	logEventType := sql.EventLogNodeRestart
-	lastUp := n.lastUp
+	var event eventpb.EventPayload
+	var nodeDetails *eventpb.CommonNodeEventDetails
 	if n.initialStart {
-		logEventType = sql.EventLogNodeJoin
-		lastUp = n.startedAt
+		ev := &eventpb.NodeJoin{}
+		event = ev
+		nodeDetails = &ev.CommonNodeEventDetails
+		nodeDetails.LastUp = n.startedAt
+	} else {
+		ev := &eventpb.NodeRestart{}
+		event = ev
+		nodeDetails = &ev.CommonNodeEventDetails
+		nodeDetails.LastUp = n.lastUp
 	}
+	nodeDetails.NodeID = int32(n.Descriptor.NodeID)
+	nodeDetails.ClusterID = n.clusterID.Get().String()
+	nodeDetails.StartedAt = n.startedAt
 
 	n.stopper.RunWorker(context.Background(), func(bgCtx context.Context) {
 		ctx, span := n.AnnotateCtxWithSpan(bgCtx, "record-join-event")
@@ -827,18 +945,12 @@ func (n *Node) recordJoinEvent() {
 				return n.eventLogger.InsertEventRecord(
 					ctx,
 					txn,
-					logEventType,
 					int32(n.Descriptor.NodeID),
 					int32(n.Descriptor.NodeID),
-					struct {
-						Descriptor roachpb.NodeDescriptor
-						ClusterID  uuid.UUID
-						StartedAt  int64
-						LastUp     int64
-					}{n.Descriptor, n.clusterID.Get(), n.startedAt, lastUp},
+					event,
 				)
 			}); err != nil {
-				log.Warningf(ctx, "%s: unable to log %s event: %s", n, logEventType, err)
+				log.Warningf(ctx, "%s: unable to log %s event: %s", n, eventpb.GetEventTypeName(event), err)
 			} else {
 				return
 			}
@@ -879,6 +991,12 @@ func (n *Node) batchInternal(
 			log.Eventf(ctx, "node received request: %s", args.Summary())
 		}
 
+		ctx, admittedWorkDone, err := n.admitBatch(ctx, args)
+		if err != nil {
+			return err
+		}
+		defer admittedWorkDone()
+
 		tStart := timeutil.Now()
 		var pErr *roachpb.Error
 		br, pErr = n.stores.Send(ctx, *args)
@@ -889,7 +1007,7 @@ func (n *Node) batchInternal(
 		if br.Error != nil {
 			panic(roachpb.ErrorUnexpectedlySet(n.stores, br))
 		}
-		n.metrics.callComplete(timeutil.Since(tStart), pErr)
+		n.metrics.callComplete(ctx, timeutil.Since(tStart), pErr)
 		br.Error = pErr
 		return nil
 	}); err != nil {
@@ -898,6 +1016,80 @@ func (n *Node) batchInternal(
 	return br, nil
 }
 
+// systemKeyspaceEnd is the end of the keyspace holding the meta ranges and
+// the system tables. Batches on it are admitted ahead of other work.
+var systemKeyspaceEnd = keys.SystemSQLCodec.TablePrefix(keys.MaxReservedDescID + 1)
+
+// admitBatch blocks until the batch is admitted by admission control, and
+// returns the context in which to evaluate it and a function to call once it's
+// evaluated.
+//
+// Only batches issued by SQL are subject to admission control. Batches issued
+// by the node itself, like node liveness heartbeats, bypass it so that the
+// node's own operation doesn't wait behind user work, but are still accounted
+// for. Writes first wait for the store they're addressed to to be able to
+// absorb them, and then for a KV slot. The slot is only held while the batch
+// evaluates: the replica releases it while the batch waits for the lease,
+// latches, locks or replication (see admission.PauseAdmittedWork), since
+// other KV work, which may itself be waiting for a slot, must run for the
+// batch to make progress.
+func (n *Node) admitBatch(
+	ctx context.Context, ba *roachpb.BatchRequest,
+) (_ context.Context, admittedWorkDone func(), _ error) {
+	noop := func() {}
+	if n.kvAdmissionQ == nil {
+		return ctx, noop, nil
+	}
+	info := admission.WorkInfo{
+		Priority:        admission.WorkPriority(ba.AdmissionHeader.Priority),
+		CreateTime:      ba.AdmissionHeader.CreateTime,
+		BypassAdmission: ba.AdmissionHeader.Source != roachpb.AdmissionHeader_FROM_SQL,
+	}
+	if info.CreateTime == 0 {
+		info.CreateTime = timeutil.Now().UnixNano()
+	}
+	if len(ba.Requests) > 0 &&
+		ba.Requests[0].GetInner().Header().Key.Compare(systemKeyspaceEnd) < 0 {
+		info.Priority = admission.HighPri
+	}
+	if ba.IsWrite() && n.storeGrantCoords != nil {
+		storeQ := n.storeGrantCoords.GetQueue(ba.Replica.StoreID)
+		enabled, err := storeQ.Admit(ctx, info)
+		if err != nil {
+			return ctx, noop, err
+		}
+		if enabled {
+			// The write consumes its store token, so it's done as far as the
+			// store's queue is concerned.
+			storeQ.AdmittedWorkDone()
+		}
+	}
+	enabled, err := n.kvAdmissionQ.Admit(ctx, info)
+	if err != nil || !enabled {
+		return ctx, noop, err
+	}
+	ctx, admittedWorkDone = n.kvAdmissionQ.ContextWithAdmittedWork(ctx)
+	return ctx, admittedWorkDone, nil
+}
+
+// storesIOMetrics returns the storage engine metrics of the node's stores
+// used by admission control.
+func (n *Node) storesIOMetrics() (map[roachpb.StoreID]admission.IOMetrics, error) {
+	ioMetrics := make(map[roachpb.StoreID]admission.IOMetrics)
+	err := n.stores.VisitStores(func(s *kvserver.Store) error {
+		m, err := s.Engine().GetMetrics()
+		if err != nil {
+			return err
+		}
+		ioMetrics[s.StoreID()] = admission.IOMetrics{
+			L0FileCount:     m.L0FileCount,
+			L0SublevelCount: m.L0SublevelCount,
+		}
+		return nil
+	})
+	return ioMetrics, err
+}
+
 // Batch implements the roachpb.InternalServer interface.
 func (n *Node) Batch(
 	ctx context.Context, args *roachpb.BatchRequest,
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net"
 	"net/http"
 	"os"
@@ -59,6 +60,7 @@ import (
 	_ "github.com/cockroachdb/cockroach/pkg/sql/gcjob" // register jobs declared outside of pkg/sql
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	_ "github.com/cockroachdb/cockroach/pkg/sql/ttljob" // register jobs declared outside of pkg/sql
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
@@ -66,11 +68,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ts"
 	"github.com/cockroachdb/cockroach/pkg/ui"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
@@ -138,6 +143,11 @@ type Server struct {
 	recorder     *status.MetricsRecorder
 	runtime      *status.RuntimeStatSampler
 
+	// grantCoord and storeGrantCoords perform admission control for the
+	// node's KV and SQL work.
+	grantCoord       *admission.GrantCoordinator
+	storeGrantCoords *admission.StoreGrantCoordinators
+
 	admin          *adminServer
 	status         *statusServer
 	authentication *authenticationServer
@@ -263,6 +273,12 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	}
 	stopper.AddCloser(&engines)
 
+	raftEngines, err := cfg.CreateRaftEngines(ctx, engines)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Raft engines")
+	}
+	stopper.AddCloser(&raftEngines)
+
 	nodeTombStorage := &nodeTombstoneStorage{engs: engines}
 	checkPingFor := func(ctx context.Context, nodeID roachpb.NodeID) error {
 		ts, err := nodeTombStorage.IsDecommissioned(ctx, nodeID)
@@ -480,6 +496,19 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		return nil, err
 	}
 
+	// The rangefeed memory monitor is shared by the rangefeeds on all of the
+	// node's stores.
+	rangefeedMemMonitor := mon.NewMonitor(
+		"rangefeed",
+		mon.MemoryResource,
+		nil,           /* curCount */
+		nil,           /* maxHist */
+		-1,            /* increment: use default increment */
+		math.MaxInt64, /* noteworthy */
+		st,
+	)
+	rangefeedMemMonitor.Start(ctx, nil, mon.MakeStandaloneBudget(cfg.RangefeedMemoryBudget))
+
 	// Break a circular dependency: we need a Node to make a StoreConfig (for
 	// ClosedTimestamp), but the Node needs a StoreConfig to be made.
 	var lateBoundNode *Node
@@ -530,6 +559,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		ExternalStorage:         externalStorage,
 		ExternalStorageFromURI:  externalStorageFromURI,
 		ProtectedTimestampCache: protectedtsProvider,
+		RaftEngines:             raftEngines,
+		RangefeedMemMonitor:     rangefeedMemMonitor,
 	}
 	if storeTestingKnobs := cfg.TestingKnobs.Store; storeTestingKnobs != nil {
 		storeCfg.TestingKnobs = *storeTestingKnobs.(*kvserver.StoreTestingKnobs)
@@ -538,9 +569,19 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	recorder := status.NewMetricsRecorder(clock, nodeLiveness, rpcContext, g, st)
 	registry.AddMetricStruct(rpcContext.RemoteClocks.Metrics())
 
+	grantCoord := admission.NewGrantCoordinator(st, cfg.HistogramWindowInterval())
+	storeGrantCoords := admission.NewStoreGrantCoordinators(st, cfg.HistogramWindowInterval())
+	for _, m := range grantCoord.MetricStructs() {
+		registry.AddMetricStruct(m)
+	}
+	for _, m := range storeGrantCoords.MetricStructs() {
+		registry.AddMetricStruct(m)
+	}
+
 	node := NewNode(
 		storeCfg, recorder, registry, stopper,
-		txnMetrics, nil /* execCfg */, &rpcContext.ClusterID)
+		txnMetrics, nil /* execCfg */, &rpcContext.ClusterID,
+		grantCoord.KVWorkQueue(), storeGrantCoords)
 	lateBoundNode = node
 	roachpb.RegisterInternalServer(grpcServer.Server, node)
 	kvserver.RegisterPerReplicaServer(grpcServer.Server, node.perReplicaServer)
@@ -608,6 +649,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 			externalStorage:        externalStorage,
 			externalStorageFromURI: externalStorageFromURI,
 			isMeta1Leaseholder:     node.stores.IsMeta1Leaseholder,
+			sqlAdmissionQ:          grantCoord.SQLWorkQueue(),
 		},
 		SQLConfig:                &cfg.SQLConfig,
 		BaseConfig:               &cfg.BaseConfig,
@@ -653,6 +695,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		db:                     db,
 		node:                   node,
 		registry:               registry,
+		grantCoord:             grantCoord,
+		storeGrantCoords:       storeGrantCoords,
 		recorder:               recorder,
 		runtime:                runtimeSampler,
 		admin:                  sAdmin,
@@ -1543,6 +1587,13 @@ func (s *Server) PreStart(ctx context.Context) error {
 		return err
 	}
 
+	// Identify this node in the traces exported to OpenTelemetry and Jaeger
+	// collectors.
+	if tr, ok := s.cfg.AmbientCtx.Tracer.(*tracing.Tracer); ok {
+		tr.SetResourceAttribute("cockroach.node_id", s.NodeID().String())
+		tr.SetResourceAttribute("cockroach.locality", s.cfg.Locality.String())
+	}
+
 	log.Event(ctx, "started node")
 	if err := s.startPersistingHLCUpperBound(
 		ctx,
@@ -1556,6 +1607,15 @@ func (s *Server) PreStart(ctx context.Context) error {
 	}
 	s.replicationReporter.Start(ctx, s.stopper)
 
+	// Start adjusting the admission control of the node's work to its CPU
+	// usage and to the health of its stores' storage engines.
+	if err := s.grantCoord.Start(ctx, s.stopper); err != nil {
+		return err
+	}
+	if err := s.storeGrantCoords.Start(ctx, s.stopper, s.node.storesIOMetrics); err != nil {
+		return err
+	}
+
 	s.refreshSettings()
 
 	sentry.ConfigureScope(func(scope *sentry.Scope) {
@@ -1603,6 +1663,15 @@ func (s *Server) PreStart(ctx context.Context) error {
 		}
 	})
 
+	var remoteWriteOnce sync.Once
+	remoteWriteEndpoint.SetOnChange(&s.st.SV, func() {
+		if remoteWriteEndpoint.Get(&s.st.SV) != "" {
+			remoteWriteOnce.Do(func() {
+				s.node.startPrometheusRemoteWriteExporter(s.st, s.cfg.HTTPAdvertiseAddr)
+			})
+		}
+	})
+
 	s.grpc.setMode(modeOperational)
 
 	log.Infof(ctx, "starting %s server at %s (use: %s)",
@@ -2031,16 +2100,24 @@ func (s *Server) Decommission(
 	}
 
 	eventLogger := sql.MakeEventLogger(s.sqlServer.execCfg)
-	var eventType sql.EventLogType
+	var event eventpb.EventPayload
+	var nodeDetails *eventpb.CommonNodeDecommissionDetails
 	if targetStatus.Decommissioning() {
-		eventType = sql.EventLogNodeDecommissioning
+		ev := &eventpb.NodeDecommissioning{}
+		nodeDetails = &ev.CommonNodeDecommissionDetails
+		event = ev
 	} else if targetStatus.Decommissioned() {
-		eventType = sql.EventLogNodeDecommissioned
+		ev := &eventpb.NodeDecommissioned{}
+		nodeDetails = &ev.CommonNodeDecommissionDetails
+		event = ev
 	} else if targetStatus.Active() {
-		eventType = sql.EventLogNodeRecommissioned
+		ev := &eventpb.NodeRecommissioned{}
+		nodeDetails = &ev.CommonNodeDecommissionDetails
+		event = ev
 	} else {
 		panic("unexpected target membership status")
 	}
+	nodeDetails.RequestingNodeID = int32(s.NodeID())
 
 	for _, nodeID := range nodeIDs {
 		statusChanged, err := s.nodeLiveness.SetMembershipStatus(ctx, nodeID, targetStatus)
@@ -2057,12 +2134,17 @@ func (s *Server) Decommission(
 			// update, this would force a 2PC and potentially leave write intents in
 			// the node liveness range. Better to make the event logging best effort
 			// than to slow down future node liveness transactions.
+			nodeDetails.TargetNodeID = int32(nodeID)
+			// Reset the timestamp, which was populated by the previous
+			// iteration if any.
+			event.CommonDetails().Timestamp = 0
 			if err := s.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
 				return eventLogger.InsertEventRecord(
-					ctx, txn, eventType, int32(nodeID), int32(s.NodeID()), struct{}{},
+					ctx, txn, int32(nodeID), int32(s.NodeID()), event,
 				)
 			}); err != nil {
-				log.Errorf(ctx, "unable to record %s event for node %d: %s", eventType, nodeID, err)
+				log.Errorf(ctx, "unable to record %s event for node %d: %s",
+					eventpb.GetEventTypeName(event), nodeID, err)
 			}
 		}
 	}
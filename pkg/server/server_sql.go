@@ -59,6 +59,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sqlmigrations"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -125,6 +126,10 @@ type sqlServerOptionalKVArgs struct {
 	// Used by backup/restore.
 	externalStorage        cloud.ExternalStorageFactory
 	externalStorageFromURI cloud.ExternalStorageFromURIFactory
+
+	// sqlAdmissionQ is used by DistSQL to subject the setup of flows to
+	// admission control.
+	sqlAdmissionQ *admission.WorkQueue
 }
 
 // sqlServerOptionalTenantArgs are the arguments supplied to newSQLServer which
@@ -353,6 +358,7 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 		Executor:       cfg.circularInternalExecutor,
 		RPCContext:     cfg.rpcContext,
 		Stopper:        cfg.stopper,
+		SQLAdmissionQ:  cfg.sqlAdmissionQ,
 
 		TempStorage:     tempEngine,
 		TempStoragePath: cfg.TempStorageConfig.Path,
@@ -489,7 +495,7 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 		// on the Stopper, below.
 
 		ExecLogger: log.NewSecondaryLogger(
-			loggerCtx, nil /* dirName */, "sql-exec",
+			loggerCtx, nil /* dirName */, "sql-exec", log.ChannelSQLExec,
 			true /* enableGc */, false /* forceSyncWrites */, true, /* enableMsgCount */
 		),
 
@@ -503,22 +509,22 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 		// (failing) connection attempts to cause a DoS failure; this
 		// would be a good reason to invest into a syslog sink for logs.
 		AuthLogger: log.NewSecondaryLogger(
-			loggerCtx, nil /* dirName */, "auth",
+			loggerCtx, nil /* dirName */, "auth", log.ChannelSessions,
 			true /* enableGc */, true /* forceSyncWrites */, true, /* enableMsgCount */
 		),
 
 		// AuditLogger syncs to disk for the same reason as AuthLogger.
 		AuditLogger: log.NewSecondaryLogger(
-			loggerCtx, cfg.AuditLogDirName, "sql-audit",
+			loggerCtx, cfg.AuditLogDirName, "sql-audit", log.ChannelSensitiveAccess,
 			true /* enableGc */, true /* forceSyncWrites */, true, /* enableMsgCount */
 		),
 
 		SlowQueryLogger: log.NewSecondaryLogger(
-			loggerCtx, nil, "sql-slow",
+			loggerCtx, nil, "sql-slow", log.ChannelSQLPerf,
 			true /* enableGc */, false /* forceSyncWrites */, true, /* enableMsgCount */
 		),
 
-		SlowInternalQueryLogger: log.NewSecondaryLogger(loggerCtx, nil, "sql-slow-internal-only",
+		SlowInternalQueryLogger: log.NewSecondaryLogger(loggerCtx, nil, "sql-slow-internal-only", log.ChannelSQLInternalPerf,
 			true /* enableGc */, false /* forceSyncWrites */, true /* enableMsgCount */),
 
 		QueryCache:                 querycache.New(cfg.QueryCacheSize),
@@ -569,6 +575,9 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 	if backupRestoreKnobs := cfg.TestingKnobs.BackupRestore; backupRestoreKnobs != nil {
 		execCfg.BackupRestoreTestingKnobs = backupRestoreKnobs.(*sql.BackupRestoreTestingKnobs)
 	}
+	if ttlKnobs := cfg.TestingKnobs.TTL; ttlKnobs != nil {
+		execCfg.TTLTestingKnobs = ttlKnobs.(*sql.TTLTestingKnobs)
+	}
 
 	statsRefresher := stats.MakeRefresher(
 		cfg.Settings,
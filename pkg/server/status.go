@@ -64,6 +64,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
 	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/prometheus/common/expfmt"
 	"go.etcd.io/etcd/raft"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -116,6 +117,7 @@ var (
 type metricMarshaler interface {
 	json.Marshaler
 	PrintAsText(io.Writer) error
+	PrintAsOpenMetrics(io.Writer) error
 }
 
 func propagateGatewayMetadata(ctx context.Context) context.Context {
@@ -1468,9 +1470,24 @@ type varsHandler struct {
 	metricSource metricMarshaler
 }
 
+// varsFormatOpenMetrics is the value of the format query parameter of
+// _status/vars which selects the OpenMetrics format.
+const varsFormatOpenMetrics = "openmetrics"
+
 func (h varsHandler) handleVars(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set(httputil.ContentTypeHeader, httputil.PlaintextContentType)
-	err := h.metricSource.PrintAsText(w)
+	var err error
+	// The OpenMetrics format includes trace exemplars on histograms, but
+	// requires counters to be suffixed with _total, which ours aren't.
+	// Prometheus accepts OpenMetrics by default, so it's only served when
+	// explicitly requested, rather than negotiated from the Accept header, so
+	// that the series of existing scrapers don't change.
+	if r.URL.Query().Get("format") == varsFormatOpenMetrics {
+		w.Header().Set(httputil.ContentTypeHeader, string(expfmt.FmtOpenMetrics))
+		err = h.metricSource.PrintAsOpenMetrics(w)
+	} else {
+		w.Header().Set(httputil.ContentTypeHeader, httputil.PlaintextContentType)
+		err = h.metricSource.PrintAsText(w)
+	}
 	if err != nil {
 		log.Errorf(r.Context(), "%v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -88,6 +88,13 @@ var childMetricsEnabled = settings.RegisterBoolSetting("server.child_metrics.ena
 	"enables the exporting of child metrics, additional prometheus time series with extra labels",
 	false)
 
+var histogramBucketsEnabled = settings.RegisterBoolSetting(
+	"timeseries.storage.histogram_buckets.enabled",
+	"if set, the buckets of histogram metrics are persisted as time series, "+
+		"allowing exact percentiles to be computed across nodes; each histogram "+
+		"adds 64 time series per node or store",
+	false)
+
 // MetricsRecorder is used to periodically record the information in a number of
 // metric registries.
 //
@@ -271,6 +278,26 @@ func (mr *MetricsRecorder) PrintAsText(w io.Writer) error {
 	return err
 }
 
+// PrintAsOpenMetrics is like PrintAsText, but writes the metrics in the
+// OpenMetrics text format, which includes exemplars.
+func (mr *MetricsRecorder) PrintAsOpenMetrics(w io.Writer) error {
+	var buf bytes.Buffer
+	if err := mr.lockAndPrintAsOpenMetrics(&buf); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// lockAndPrintAsOpenMetrics grabs the recorder lock and generates the
+// OpenMetrics metrics page.
+func (mr *MetricsRecorder) lockAndPrintAsOpenMetrics(w io.Writer) error {
+	mr.promMu.Lock()
+	defer mr.promMu.Unlock()
+	mr.scrapePrometheusLocked()
+	return mr.promMu.prometheusExporter.PrintAsOpenMetrics(w)
+}
+
 // lockAndPrintAsText grabs the recorder lock and generates the prometheus
 // metrics page.
 func (mr *MetricsRecorder) lockAndPrintAsText(w io.Writer) error {
@@ -292,6 +319,18 @@ func (mr *MetricsRecorder) ExportToGraphite(
 	return graphiteExporter.Push(ctx, endpoint)
 }
 
+// ExportToPrometheusRemoteWrite sends the current metric values to a
+// Prometheus remote write endpoint, adding the supplied labels to every
+// series. Like ExportToGraphite, it scrapes into the supplied
+// PrometheusExporter to avoid races with mr.promMu.prometheusExporter.
+func (mr *MetricsRecorder) ExportToPrometheusRemoteWrite(
+	ctx context.Context, endpoint string, labels map[string]string, pm *metric.PrometheusExporter,
+) error {
+	mr.scrapeIntoPrometheus(pm)
+	remoteWriteExporter := metric.MakeRemoteWriteExporter(pm)
+	return remoteWriteExporter.Push(ctx, endpoint, labels)
+}
+
 // GetTimeSeriesData serializes registered metrics for consumption by
 // CockroachDB's time series system.
 func (mr *MetricsRecorder) GetTimeSeriesData() []tspb.TimeSeriesData {
@@ -311,11 +350,13 @@ func (mr *MetricsRecorder) GetTimeSeriesData() []tspb.TimeSeriesData {
 
 	// Record time series from node-level registries.
 	now := mr.clock.PhysicalNow()
+	recordBuckets := histogramBucketsEnabled.Get(&mr.settings.SV)
 	recorder := registryRecorder{
 		registry:       mr.mu.nodeRegistry,
 		format:         nodeTimeSeriesPrefix,
 		source:         strconv.FormatInt(int64(mr.mu.desc.NodeID), 10),
 		timestampNanos: now,
+		recordBuckets:  recordBuckets,
 	}
 	recorder.record(&data)
 
@@ -326,6 +367,7 @@ func (mr *MetricsRecorder) GetTimeSeriesData() []tspb.TimeSeriesData {
 			format:         storeTimeSeriesPrefix,
 			source:         strconv.FormatInt(int64(storeID), 10),
 			timestampNanos: now,
+			recordBuckets:  recordBuckets,
 		}
 		storeRecorder.record(&data)
 	}
@@ -519,6 +561,9 @@ type registryRecorder struct {
 	format         string
 	source         string
 	timestampNanos int64
+	// recordBuckets, if set, additionally records the bucket counts of each
+	// histogram in the registry (see eachHistogramBucket).
+	recordBuckets bool
 }
 
 func extractValue(mtr interface{}) (float64, error) {
@@ -579,8 +624,37 @@ func eachRecordableValue(reg *metric.Registry, fn func(string, float64)) {
 	})
 }
 
+// eachHistogramBucket visits each histogram in the registry, calling the
+// supplied function once for each non-empty bucket of the histogram's
+// cumulative distribution with the name of the time series storing that
+// bucket (see tspb.HistogramBucketName) and the number of values recorded into
+// it. Unlike the quantiles recorded by eachRecordableValue, bucket counts from
+// different sources can be meaningfully added together, which is what allows
+// the time series query engine to compute percentiles across nodes.
+func eachHistogramBucket(reg *metric.Registry, fn func(string, float64)) {
+	reg.Each(func(name string, mtr interface{}) {
+		histogram, ok := mtr.(*metric.Histogram)
+		if !ok {
+			return
+		}
+		var counts [tspb.HistogramBucketCount]int64
+		for _, bar := range histogram.Snapshot().Distribution() {
+			if bar.Count == 0 {
+				continue
+			}
+			counts[tspb.HistogramBucketForValue(bar.To)] += bar.Count
+		}
+		for bucket, count := range counts {
+			if count == 0 {
+				continue
+			}
+			fn(tspb.HistogramBucketName(name, bucket), float64(count))
+		}
+	})
+}
+
 func (rr registryRecorder) record(dest *[]tspb.TimeSeriesData) {
-	eachRecordableValue(rr.registry, func(name string, val float64) {
+	fn := func(name string, val float64) {
 		*dest = append(*dest, tspb.TimeSeriesData{
 			Name:   fmt.Sprintf(rr.format, name),
 			Source: rr.source,
@@ -591,7 +665,11 @@ func (rr registryRecorder) record(dest *[]tspb.TimeSeriesData) {
 				},
 			},
 		})
-	})
+	}
+	eachRecordableValue(rr.registry, fn)
+	if rr.recordBuckets {
+		eachHistogramBucket(rr.registry, fn)
+	}
 }
 
 // GetTotalMemory returns either the total system memory (in bytes) or if
@@ -143,6 +143,7 @@ func TestMetricsRecorder(t *testing.T) {
 	}
 	manual := hlc.NewManualClock(100)
 	st := cluster.MakeTestingClusterSettings()
+	histogramBucketsEnabled.Override(&st.SV, true)
 	recorder := NewMetricsRecorder(hlc.NewClock(manual.UnixNano, time.Nanosecond), nil, nil, nil, st)
 	recorder.AddStore(store1)
 	recorder.AddStore(store2)
@@ -252,6 +253,26 @@ func TestMetricsRecorder(t *testing.T) {
 		}
 	}
 
+	// addExpectedBuckets generates expected bucket data for a histogram which
+	// has recorded the single value val. Buckets are not part of status
+	// summaries.
+	addExpectedBuckets := func(prefix, name string, source, time, val int64, isNode bool) {
+		tsPrefix := "cr.node."
+		if !isNode {
+			tsPrefix = "cr.store."
+		}
+		expected = append(expected, tspb.TimeSeriesData{
+			Name:   tsPrefix + tspb.HistogramBucketName(prefix+name, tspb.HistogramBucketForValue(val)),
+			Source: strconv.FormatInt(source, 10),
+			Datapoints: []tspb.TimeSeriesDatapoint{
+				{
+					TimestampNanos: time,
+					Value:          1,
+				},
+			},
+		})
+	}
+
 	// Add metric for node ID.
 	g := metric.NewGauge(metric.Metadata{Name: "node-id"})
 	g.Update(int64(nodeDesc.NodeID))
@@ -294,6 +315,7 @@ func TestMetricsRecorder(t *testing.T) {
 				for _, q := range recordHistogramQuantiles {
 					addExpected(reg.prefix, data.name+q.suffix, reg.source, 100, data.val, reg.isNode)
 				}
+				addExpectedBuckets(reg.prefix, data.name, reg.source, 100, data.val, reg.isNode)
 			case "latency":
 				l := metric.NewLatency(metric.Metadata{Name: reg.prefix + data.name}, time.Hour)
 				reg.reg.AddMetric(l)
@@ -303,6 +325,7 @@ func TestMetricsRecorder(t *testing.T) {
 				for _, q := range recordHistogramQuantiles {
 					addExpected(reg.prefix, data.name+q.suffix, reg.source, 100, data.val, reg.isNode)
 				}
+				addExpectedBuckets(reg.prefix, data.name, reg.source, 100, data.val, reg.isNode)
 			default:
 				t.Fatalf("unexpected: %+v", data)
 			}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -1174,6 +1175,51 @@ func TestStatusVars(t *testing.T) {
 	}
 }
 
+// TestStatusVarsOpenMetrics verifies that the /_status/vars endpoint serves
+// the OpenMetrics format only when requested with the format query parameter,
+// even to clients which accept it.
+func TestStatusVarsOpenMetrics(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+
+	httpClient, err := s.GetAdminAuthenticatedHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(query string) (contentType string, body []byte) {
+		req, err := http.NewRequest("GET", s.AdminURL()+statusPrefix+"vars"+query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The Accept header sent by Prometheus.
+		req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1,"+
+			"text/plain;version=0.0.4;q=0.5,*/*;q=0.1")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Header.Get(httputil.ContentTypeHeader), body
+	}
+
+	if ct, body := get(""); ct != httputil.PlaintextContentType {
+		t.Errorf("expected content type %q, got %q", httputil.PlaintextContentType, ct)
+	} else if bytes.HasSuffix(body, []byte("# EOF\n")) {
+		t.Errorf("expected the prometheus text format, got: %s", body)
+	}
+	if ct, body := get("?format=openmetrics"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("expected the OpenMetrics content type, got %q", ct)
+	} else if !bytes.HasSuffix(body, []byte("# EOF\n")) {
+		t.Errorf("expected the OpenMetrics format, got: %s", body)
+	}
+}
+
 func TestSpanStatsResponse(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
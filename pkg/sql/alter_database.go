@@ -12,12 +12,20 @@ package sql
 
 import (
 	"context"
+	"sort"
+	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
 )
 
 type alterDatabaseOwnerNode struct {
@@ -80,23 +88,252 @@ func (n *alterDatabaseOwnerNode) Next(runParams) (bool, error) { return false, n
 func (n *alterDatabaseOwnerNode) Values() tree.Datums          { return tree.Datums{} }
 func (n *alterDatabaseOwnerNode) Close(context.Context)        {}
 
+// checkMultiRegionSupported returns an error if the region config of a
+// database cannot be changed yet. Nodes running a version which doesn't know
+// about region configs would drop them when rewriting database descriptors.
+func checkMultiRegionSupported(ctx context.Context, st *cluster.Settings) error {
+	if !st.Version.IsActive(ctx, clusterversion.VersionMultiRegionDatabases) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"multi-region databases are not supported until version upgrade is finalized")
+	}
+	return nil
+}
+
+type alterDatabaseAddRegionNode struct {
+	n    *tree.AlterDatabaseAddRegion
+	desc *dbdesc.Mutable
+}
+
 // AlterDatabaseAddRegion transforms a tree.AlterDatabaseAddRegion into a plan node.
 func (p *planner) AlterDatabaseAddRegion(
 	ctx context.Context, n *tree.AlterDatabaseAddRegion,
 ) (planNode, error) {
-	return nil, unimplemented.New("alter database add region", "implementation pending")
+	if err := checkMultiRegionSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return nil, err
+	}
+	if err := checkPrivilegeForSetZoneConfig(ctx, p, tree.ZoneSpecifier{Database: n.Name}); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.ResolveMutableDatabaseDescriptor(ctx, string(n.Name), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+
+	return &alterDatabaseAddRegionNode{n: n, desc: dbDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because the node reads the zone configuration of the database
+// after possibly having written it in the same transaction.
+func (n *alterDatabaseAddRegionNode) ReadingOwnWrites() {}
+
+func (n *alterDatabaseAddRegionNode) startExec(params runParams) error {
+	liveRegions, err := params.p.getLiveClusterRegions(params.ctx)
+	if err != nil {
+		return err
+	}
+
+	toAdd := make([]string, 0, len(n.n.Regions))
+	for _, name := range n.n.Regions {
+		region := string(name)
+		if err := checkLiveClusterRegion(liveRegions, region); err != nil {
+			return err
+		}
+		if n.desc.HasRegion(region) {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"region %q already added to database %q", region, n.desc.Name)
+		}
+		for _, r := range toAdd {
+			if r == region {
+				return pgerror.Newf(pgcode.DuplicateObject,
+					"region %q specified more than once", region)
+			}
+		}
+		toAdd = append(toAdd, region)
+	}
+
+	if n.desc.RegionConfig == nil {
+		// The first region added to a database becomes its primary region.
+		n.desc.RegionConfig = &descpb.DatabaseDescriptor_RegionConfig{
+			SurvivalGoal:  descpb.SurvivalGoal_ZONE_FAILURE,
+			PrimaryRegion: toAdd[0],
+		}
+	}
+	n.desc.RegionConfig.Regions = append(n.desc.RegionConfig.Regions, toAdd...)
+	sort.Strings(n.desc.RegionConfig.Regions)
+
+	return writeDatabaseRegionConfigChange(params, n.desc, n.n, &eventpb.AlterDatabaseAddRegion{
+		DatabaseName: n.desc.Name,
+		RegionName:   strings.Join(toAdd, ", "),
+	})
+}
+
+func (n *alterDatabaseAddRegionNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterDatabaseAddRegionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterDatabaseAddRegionNode) Close(context.Context)        {}
+
+type alterDatabaseDropRegionNode struct {
+	n    *tree.AlterDatabaseDropRegion
+	desc *dbdesc.Mutable
 }
 
 // AlterDatabaseDropRegion transforms a tree.AlterDatabaseDropRegion into a plan node.
 func (p *planner) AlterDatabaseDropRegion(
 	ctx context.Context, n *tree.AlterDatabaseDropRegion,
 ) (planNode, error) {
-	return nil, unimplemented.New("alter database drop region", "implementation pending")
+	if err := checkMultiRegionSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return nil, err
+	}
+	if err := checkPrivilegeForSetZoneConfig(ctx, p, tree.ZoneSpecifier{Database: n.Name}); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.ResolveMutableDatabaseDescriptor(ctx, string(n.Name), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if !dbDesc.IsMultiRegion() {
+		return nil, pgerror.Newf(pgcode.InvalidDatabaseDefinition,
+			"database %q is not multi-region enabled", dbDesc.Name)
+	}
+
+	return &alterDatabaseDropRegionNode{n: n, desc: dbDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because the node reads the zone configuration of the database
+// after possibly having written it in the same transaction.
+func (n *alterDatabaseDropRegionNode) ReadingOwnWrites() {}
+
+func (n *alterDatabaseDropRegionNode) startExec(params runParams) error {
+	regionConfig := n.desc.RegionConfig
+	toDrop := make(map[string]struct{}, len(n.n.Regions))
+	dropped := make([]string, 0, len(n.n.Regions))
+	for _, name := range n.n.Regions {
+		region := string(name)
+		if !n.desc.HasRegion(region) {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"region %q has not been added to database %q", region, n.desc.Name)
+		}
+		if _, ok := toDrop[region]; !ok {
+			dropped = append(dropped, region)
+		}
+		toDrop[region] = struct{}{}
+	}
+
+	remaining := make([]string, 0, len(regionConfig.Regions))
+	for _, region := range regionConfig.Regions {
+		if _, ok := toDrop[region]; !ok {
+			remaining = append(remaining, region)
+		}
+	}
+
+	if len(remaining) == 0 {
+		// Dropping every region turns the database back into a regular,
+		// non multi-region database.
+		n.desc.RegionConfig = nil
+	} else {
+		if _, ok := toDrop[regionConfig.PrimaryRegion]; ok {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.InvalidDatabaseDefinition,
+					"cannot drop region %q as it is the primary region of database %q",
+					regionConfig.PrimaryRegion, n.desc.Name),
+				"drop all other regions before dropping the primary region",
+			)
+		}
+		regionConfig.Regions = remaining
+		if err := validateSurvivalGoal(regionConfig); err != nil {
+			return err
+		}
+	}
+
+	return writeDatabaseRegionConfigChange(params, n.desc, n.n, &eventpb.AlterDatabaseDropRegion{
+		DatabaseName: n.desc.Name,
+		RegionName:   strings.Join(dropped, ", "),
+	})
+}
+
+func (n *alterDatabaseDropRegionNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterDatabaseDropRegionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterDatabaseDropRegionNode) Close(context.Context)        {}
+
+type alterDatabaseSurviveNode struct {
+	n    *tree.AlterDatabaseSurvive
+	desc *dbdesc.Mutable
 }
 
 // AlterDatabaseSurvive transforms a tree.AlterDatabaseSurvive into a plan node.
 func (p *planner) AlterDatabaseSurvive(
 	ctx context.Context, n *tree.AlterDatabaseSurvive,
 ) (planNode, error) {
-	return nil, unimplemented.New("alter database survive", "implementation pending")
+	if err := checkMultiRegionSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return nil, err
+	}
+	if err := checkPrivilegeForSetZoneConfig(ctx, p, tree.ZoneSpecifier{Database: n.Name}); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.ResolveMutableDatabaseDescriptor(ctx, string(n.Name), true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if !dbDesc.IsMultiRegion() {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.InvalidDatabaseDefinition,
+				"database %q is not multi-region enabled", dbDesc.Name),
+			"you must add a region to the database before setting a survival goal",
+		)
+	}
+
+	return &alterDatabaseSurviveNode{n: n, desc: dbDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because the node reads the zone configuration of the database
+// after possibly having written it in the same transaction.
+func (n *alterDatabaseSurviveNode) ReadingOwnWrites() {}
+
+func (n *alterDatabaseSurviveNode) startExec(params runParams) error {
+	survivalGoal, err := survivalGoalFromSurvive(n.n.Survive)
+	if err != nil {
+		return err
+	}
+	n.desc.RegionConfig.SurvivalGoal = survivalGoal
+	if err := validateSurvivalGoal(n.desc.RegionConfig); err != nil {
+		return err
+	}
+
+	return writeDatabaseRegionConfigChange(params, n.desc, n.n, &eventpb.AlterDatabaseSurvive{
+		DatabaseName: n.desc.Name,
+		SurvivalGoal: survivalGoal.String(),
+	})
+}
+
+func (n *alterDatabaseSurviveNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterDatabaseSurviveNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterDatabaseSurviveNode) Close(context.Context)        {}
+
+// writeDatabaseRegionConfigChange writes out the database descriptor after
+// its region config was changed, regenerates the zone configuration of the
+// database from the new region config and logs the change in the event log.
+func writeDatabaseRegionConfigChange(
+	params runParams, desc *dbdesc.Mutable, stmt tree.Statement, event eventpb.EventPayload,
+) error {
+	stmtStr := tree.AsStringWithFQNames(stmt, params.Ann())
+	if err := params.p.writeNonDropDatabaseChange(params.ctx, desc, stmtStr); err != nil {
+		return err
+	}
+
+	if err := applyRegionConfigToDatabaseZoneConfig(
+		params.ctx,
+		params.p.txn,
+		desc.ID,
+		desc.RegionConfig,
+		params.ExecCfg(),
+	); err != nil {
+		return err
+	}
+
+	return params.p.logEvent(params.ctx, desc.ID, event)
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 )
 
@@ -92,18 +93,11 @@ func (n *alterTypeNode) startExec(params runParams) error {
 	}
 
 	// Write a log event.
-	return MakeEventLogger(params.p.ExecCfg()).InsertEventRecord(
-		params.ctx,
-		params.p.txn,
-		EventLogAlterType,
-		int32(n.desc.ID),
-		int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
-		struct {
-			TypeName  string
-			Statement string
-			User      string
-		}{n.desc.Name, tree.AsStringWithFQNames(n.n, params.Ann()), params.p.User()},
-	)
+	return params.p.logEvent(params.ctx,
+		n.desc.ID,
+		&eventpb.AlterType{
+			TypeName: n.desc.Name,
+		})
 }
 
 func (p *planner) addEnumValue(
@@ -0,0 +1,139 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// negotiateBoundedStaleness determines the timestamp a bounded staleness read
+// runs at and how its requests should be routed.
+//
+// The timestamp is the newest one at which the nearest replica of every range
+// of the tables in the statement's FROM clause can serve the read, which is
+// the minimum of their closed timestamps. If that timestamp is older than
+// minTS, the read can't be served locally, so it instead reads the latest data
+// from the leaseholders.
+//
+// Reads of tables that aren't part of the negotiation, like those in
+// subqueries, are still correct: a replica that can't serve a read at the
+// negotiated timestamp redirects it to the leaseholder.
+func (p *planner) negotiateBoundedStaleness(
+	ctx context.Context, stmt tree.Statement, minTS hlc.Timestamp,
+) (hlc.Timestamp, roachpb.RoutingPolicy, error) {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.VersionBoundedStaleness) {
+		return hlc.Timestamp{}, 0, pgerror.New(pgcode.FeatureNotSupported,
+			"AS OF SYSTEM TIME: bounded staleness reads are not supported until the "+
+				"cluster upgrade is finalized")
+	}
+
+	spans, err := p.boundedStalenessSpans(ctx, stmt)
+	if err != nil {
+		return hlc.Timestamp{}, 0, err
+	}
+	now := p.ExecCfg().Clock.Now()
+	if len(spans) == 0 {
+		// There are no tables to negotiate a timestamp for.
+		return now, roachpb.LEASEHOLDER, nil
+	}
+
+	var ba roachpb.BatchRequest
+	ba.Timestamp = now
+	ba.ReadConsistency = roachpb.INCONSISTENT
+	ba.RoutingPolicy = roachpb.NEAREST
+	for _, span := range spans {
+		ba.Add(&roachpb.QueryResolvedTimestampRequest{
+			RequestHeader: roachpb.RequestHeaderFromSpan(span),
+		})
+	}
+	br, pErr := p.ExecCfg().DB.NonTransactionalSender().Send(ctx, ba)
+	if pErr != nil {
+		return hlc.Timestamp{}, 0, pErr.GoError()
+	}
+	resolvedTS := now
+	for _, ru := range br.Responses {
+		resolvedTS.Backward(ru.GetInner().(*roachpb.QueryResolvedTimestampResponse).ResolvedTS)
+	}
+
+	if resolvedTS.Less(minTS) {
+		log.VEventf(ctx, 2, "bounded staleness read: resolved timestamp %s is below the "+
+			"minimum timestamp bound %s, reading from the leaseholders", resolvedTS, minTS)
+		return now, roachpb.LEASEHOLDER, nil
+	}
+	log.VEventf(ctx, 2, "bounded staleness read: reading at resolved timestamp %s", resolvedTS)
+	return resolvedTS, roachpb.NEAREST, nil
+}
+
+// boundedStalenessSpans returns the spans of the tables in the FROM clause of
+// a bounded staleness read. Names that don't resolve to tables, like those of
+// CTEs, are ignored.
+func (p *planner) boundedStalenessSpans(
+	ctx context.Context, stmt tree.Statement,
+) ([]roachpb.Span, error) {
+	for {
+		explain, ok := stmt.(*tree.Explain)
+		if !ok {
+			break
+		}
+		stmt = explain.Statement
+	}
+	sel, ok := stmt.(*tree.Select)
+	if !ok {
+		return nil, nil
+	}
+	sc := selectClauseForAsOf(sel)
+	if sc == nil {
+		return nil, nil
+	}
+
+	var tableNames []tree.TableName
+	var walk func(tree.TableExpr)
+	walk = func(expr tree.TableExpr) {
+		switch t := expr.(type) {
+		case *tree.AliasedTableExpr:
+			walk(t.Expr)
+		case *tree.ParenTableExpr:
+			walk(t.Expr)
+		case *tree.JoinTableExpr:
+			walk(t.Left)
+			walk(t.Right)
+		case *tree.TableName:
+			tableNames = append(tableNames, *t)
+		}
+	}
+	for _, expr := range sc.From.Tables {
+		walk(expr)
+	}
+
+	var spans []roachpb.Span
+	flags := tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveAnyTableKind)
+	flags.Required = false
+	for i := range tableNames {
+		desc, err := resolver.ResolveExistingTableObject(ctx, p, &tableNames[i], flags)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil || !desc.IsTable() || desc.IsVirtualTable() {
+			continue
+		}
+		spans = append(spans, desc.TableSpan(p.ExecCfg().Codec))
+	}
+	return spans, nil
+}
@@ -172,6 +172,26 @@ func (desc *Immutable) Dropped() bool {
 	return desc.State == descpb.DescriptorState_DROP
 }
 
+// IsMultiRegion returns whether the database has multi-region properties
+// configured.
+func (desc *Immutable) IsMultiRegion() bool {
+	return desc.RegionConfig != nil
+}
+
+// HasRegion returns whether the given region is one of the regions of the
+// database.
+func (desc *Immutable) HasRegion(region string) bool {
+	if desc.RegionConfig == nil {
+		return false
+	}
+	for _, r := range desc.RegionConfig.Regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
 // DescriptorProto wraps a DatabaseDescriptor in a Descriptor.
 func (desc *Immutable) DescriptorProto() *descpb.Descriptor {
 	return &descpb.Descriptor{
@@ -197,6 +217,10 @@ func (desc *Immutable) Validate() error {
 		return fmt.Errorf("invalid database ID %d", desc.GetID())
 	}
 
+	if err := desc.validateRegionConfig(); err != nil {
+		return err
+	}
+
 	// Fill in any incorrect privileges that may have been missed due to mixed-versions.
 	// TODO(mberhault): remove this in 2.1 (maybe 2.2) when privilege-fixing migrations have been
 	// run again and mixed-version clusters always write "good" descriptors.
@@ -206,6 +230,30 @@ func (desc *Immutable) Validate() error {
 	return desc.Privileges.Validate(desc.GetID(), privilege.Database)
 }
 
+// validateRegionConfig validates that the region config, if set, is
+// well-formed: it contains at least one region, no duplicate regions, and a
+// primary region which is one of its regions.
+func (desc *Immutable) validateRegionConfig() error {
+	if desc.RegionConfig == nil {
+		return nil
+	}
+	if len(desc.RegionConfig.Regions) == 0 {
+		return fmt.Errorf("multi-region database %q has no regions", desc.GetName())
+	}
+	seen := make(map[string]struct{}, len(desc.RegionConfig.Regions))
+	for _, region := range desc.RegionConfig.Regions {
+		if _, ok := seen[region]; ok {
+			return fmt.Errorf("duplicate region %q in database %q", region, desc.GetName())
+		}
+		seen[region] = struct{}{}
+	}
+	if _, ok := seen[desc.RegionConfig.PrimaryRegion]; !ok {
+		return fmt.Errorf("primary region %q of database %q is not one of its regions",
+			desc.RegionConfig.PrimaryRegion, desc.GetName())
+	}
+	return nil
+}
+
 // SchemaMeta implements the tree.SchemaMeta interface.
 // TODO (rohany): I don't want to keep this here, but it seems to be used
 //  by backup only for the fake resolution that occurs in backup. Is it possible
@@ -79,3 +79,51 @@ func TestMakeDatabaseDesc(t *testing.T) {
 		t.Fatalf("wrong number of privilege users, expected 2, got: %d", len(desc.GetPrivileges().Users))
 	}
 }
+
+func TestValidateRegionConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		regionConfig *descpb.DatabaseDescriptor_RegionConfig
+		err          string
+	}{
+		{
+			regionConfig: nil,
+		},
+		{
+			regionConfig: &descpb.DatabaseDescriptor_RegionConfig{
+				Regions:       []string{"region_a", "region_b"},
+				PrimaryRegion: "region_b",
+			},
+		},
+		{
+			regionConfig: &descpb.DatabaseDescriptor_RegionConfig{},
+			err:          `multi-region database "db" has no regions`,
+		},
+		{
+			regionConfig: &descpb.DatabaseDescriptor_RegionConfig{
+				Regions:       []string{"region_a", "region_a"},
+				PrimaryRegion: "region_a",
+			},
+			err: `duplicate region "region_a" in database "db"`,
+		},
+		{
+			regionConfig: &descpb.DatabaseDescriptor_RegionConfig{
+				Regions:       []string{"region_a"},
+				PrimaryRegion: "region_b",
+			},
+			err: `primary region "region_b" of database "db" is not one of its regions`,
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			desc := NewInitial(52, "db", security.AdminRole)
+			desc.RegionConfig = tc.regionConfig
+			err := desc.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -280,6 +280,24 @@ func (desc *TableDescriptor) Persistence() tree.Persistence {
 	return tree.PersistencePermanent
 }
 
+// HasRowLevelTTL returns true if the table has row-level TTL configured.
+func (desc *TableDescriptor) HasRowLevelTTL() bool {
+	return desc.RowLevelTTL != nil
+}
+
+// RowLevelTTLExpirationColumnName is the name of the hidden column added to
+// tables created with the ttl_expire_after storage parameter.
+const RowLevelTTLExpirationColumnName = "crdb_internal_expiration"
+
+// EffectiveExpirationExpr returns the SQL expression which determines the
+// expiration time of a row.
+func (ttl *TableDescriptor_RowLevelTTL) EffectiveExpirationExpr() string {
+	if ttl.ExpirationExpr != "" {
+		return ttl.ExpirationExpr
+	}
+	return RowLevelTTLExpirationColumnName
+}
+
 // IsVirtualTable returns true if the TableDescriptor describes a
 // virtual Table (like the information_schema tables) and thus doesn't
 // need to be physically stored.
@@ -1805,6 +1805,9 @@ func (desc *Immutable) ValidateTable(ctx context.Context) error {
 		if err := desc.validatePartitioning(); err != nil {
 			return err
 		}
+		if err := desc.validateRowLevelTTL(columnNames); err != nil {
+			return err
+		}
 	}
 
 	// Fill in any incorrect privileges that may have been missed due to mixed-versions.
@@ -1865,6 +1868,30 @@ func (desc *Immutable) ValidateTable(ctx context.Context) error {
 	return desc.Privileges.Validate(desc.GetID(), privilege.Table)
 }
 
+// validateRowLevelTTL validates that the row-level TTL configuration of the
+// table, if any, is well formed.
+func (desc *Immutable) validateRowLevelTTL(columnNames map[string]descpb.ColumnID) error {
+	ttl := desc.RowLevelTTL
+	if ttl == nil {
+		return nil
+	}
+	if (ttl.ExpireAfter == "") == (ttl.ExpirationExpr == "") {
+		return errors.AssertionFailedf(
+			"exactly one of expire_after and expiration_expr must be set for row-level TTL")
+	}
+	if ttl.ExpireAfter != "" {
+		if _, ok := columnNames[descpb.RowLevelTTLExpirationColumnName]; !ok {
+			return errors.AssertionFailedf("expected column %s for row-level TTL",
+				descpb.RowLevelTTLExpirationColumnName)
+		}
+	}
+	if desc.IsInterleaved() {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"interleaved tables do not support row-level TTL")
+	}
+	return nil
+}
+
 func (desc *Immutable) validateColumnFamilies(columnIDs map[descpb.ColumnID]string) error {
 	if len(desc.Families) < 1 {
 		return fmt.Errorf("at least 1 column family must be specified")
@@ -47,6 +47,10 @@ type file struct {
 	// read at once. This allows a region of coldata.Batches to be deserialized
 	// without reading a whole file into memory.
 	offsets []int
+	// numBatches contains the number of batches serialized in each of the
+	// regions delimited by offsets, which allows a rewindable queue to seek
+	// to an arbitrary batch by reading only the region containing it.
+	numBatches []int
 	// curOffsetIdx is an index into offsets.
 	curOffsetIdx int
 	totalSize    int
@@ -183,6 +187,10 @@ type diskQueue struct {
 	deserializerState struct {
 		*colserde.FileDeserializer
 		curBatch int
+		// newRegion is true if the deserializer has just been positioned in
+		// the middle of a new region by Seek, in which case the next Dequeue
+		// needs to allocate new null bitmaps for the batch.
+		newRegion bool
 	}
 	// readFileIdx is an index into the current file in files the deserializer is
 	// reading from.
@@ -224,6 +232,13 @@ type RewindableQueue interface {
 	// Rewind resets the Queue so that it Dequeues all Enqueued batches from the
 	// start.
 	Rewind() error
+	// Seek positions the Queue so that the next Dequeue returns the batchIdx'th
+	// Enqueued batch (counting from zero). If batchIdx is not less than the
+	// number of Enqueued batches, the next Dequeue returns a zero-length batch.
+	// Unlike Rewind, Seek only reads the region of the file containing the
+	// requested batch, so it can be used for random access to the batches.
+	// Seek must only be called once all batches have been Enqueued.
+	Seek(ctx context.Context, batchIdx int) error
 }
 
 const (
@@ -488,6 +503,7 @@ func (d *diskQueue) writeFooterAndFlush(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	d.files[d.writeFileIdx].numBatches = append(d.files[d.writeFileIdx].numBatches, d.numBufferedBatches)
 	d.numBufferedBatches = 0
 	d.files[d.writeFileIdx].totalSize += written
 	if err := d.diskAcc.Grow(ctx, int64(written)); err != nil {
@@ -663,6 +679,19 @@ func (d *diskQueue) maybeInitDeserializer(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// switchToDequeueing transitions the queue into the dequeueing state.
+func (d *diskQueue) switchToDequeueing() {
+	if d.state == diskQueueStateEnqueueing && d.cfg.CacheMode != DiskQueueCacheModeDefault {
+		// This is the first Dequeue after Enqueues, so reuse the write cache for
+		// reads. Note that the buffer for compressed reads is reused in
+		// maybeInitDeserializer in either case, so there is nothing to do here for
+		// that.
+		d.writer.buffer.Reset()
+		d.scratchDecompressedReadBytes = d.writer.buffer.Bytes()
+	}
+	d.state = diskQueueStateDequeueing
+}
+
 // Dequeue dequeues a batch from disk and deserializes it into b. Note that the
 // deserialized batch is only valid until the next call to Dequeue.
 func (d *diskQueue) Dequeue(ctx context.Context, b coldata.Batch) (bool, error) {
@@ -674,15 +703,7 @@ func (d *diskQueue) Dequeue(ctx context.Context, b coldata.Batch) (bool, error)
 			return false, err
 		}
 	}
-	if d.state == diskQueueStateEnqueueing && d.cfg.CacheMode != DiskQueueCacheModeDefault {
-		// This is the first Dequeue after Enqueues, so reuse the write cache for
-		// reads. Note that the buffer for compressed reads is reused in
-		// maybeInitDeserializer in either case, so there is nothing to do here for
-		// that.
-		d.writer.buffer.Reset()
-		d.scratchDecompressedReadBytes = d.writer.buffer.Bytes()
-	}
-	d.state = diskQueueStateDequeueing
+	d.switchToDequeueing()
 
 	if d.deserializerState.FileDeserializer != nil && d.deserializerState.curBatch >= d.deserializerState.NumBatches() {
 		// Finished all the batches, set the deserializer to nil to initialize a new
@@ -704,7 +725,8 @@ func (d *diskQueue) Dequeue(ctx context.Context, b coldata.Batch) (bool, error)
 		// No data will be added.
 		b.SetLength(0)
 	} else {
-		if d.deserializerState.curBatch == 0 {
+		if d.deserializerState.curBatch == 0 || d.deserializerState.newRegion {
+			d.deserializerState.newRegion = false
 			// It is possible that the caller has appended more columns to the
 			// batch than it provided types during diskQueue's creation. We
 			// will only be touching the prefix of the batch that we have been
@@ -740,6 +762,7 @@ func (d *diskQueue) Rewind() error {
 		return err
 	}
 	d.deserializerState.curBatch = 0
+	d.deserializerState.newRegion = false
 	d.readFile = nil
 	d.readFileIdx = 0
 	for i := range d.files {
@@ -747,3 +770,57 @@ func (d *diskQueue) Rewind() error {
 	}
 	return nil
 }
+
+// Seek is part of the RewindableQueue interface.
+func (d *diskQueue) Seek(ctx context.Context, batchIdx int) error {
+	if !d.rewindable || !d.done {
+		return errors.AssertionFailedf("Seek can only be called on a RewindableDiskQueue once all batches have been enqueued")
+	}
+	if batchIdx < 0 {
+		return errors.AssertionFailedf("attempted to Seek to negative batch index %d", batchIdx)
+	}
+	d.switchToDequeueing()
+	// Find the file and the region within it that contain the batch.
+	fileIdx, offsetIdx := len(d.files), 0
+findRegion:
+	for i := range d.files {
+		for j, n := range d.files[i].numBatches {
+			if batchIdx < n {
+				fileIdx, offsetIdx = i, j
+				break findRegion
+			}
+			batchIdx -= n
+		}
+	}
+	if fileIdx == d.readFileIdx && d.deserializerState.FileDeserializer != nil &&
+		d.files[fileIdx].curOffsetIdx == offsetIdx {
+		// The region is already being read, so there is no need to read it
+		// again.
+		d.deserializerState.curBatch = batchIdx
+		return nil
+	}
+	if err := d.closeFileDeserializer(); err != nil {
+		return err
+	}
+	if fileIdx != d.readFileIdx {
+		if err := d.CloseRead(); err != nil {
+			return err
+		}
+		d.readFile = nil
+	}
+	d.readFileIdx = fileIdx
+	for i := fileIdx; i < len(d.files); i++ {
+		d.files[i].curOffsetIdx = 0
+	}
+	if fileIdx == len(d.files) {
+		// The batch is past the end of the queue.
+		return nil
+	}
+	d.files[fileIdx].curOffsetIdx = offsetIdx
+	if _, err := d.maybeInitDeserializer(ctx); err != nil {
+		return err
+	}
+	d.deserializerState.curBatch = batchIdx
+	d.deserializerState.newRegion = true
+	return nil
+}
@@ -168,6 +168,78 @@ func TestDiskQueue(t *testing.T) {
 	}
 }
 
+func TestRewindableDiskQueueSeek(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+
+	rng, _ := randutil.NewPseudoRand()
+	for _, bufferSizeBytes := range []int{1 /* one batch per region */, 16<<10 + rng.Intn(1<<20)} {
+		for _, maxFileSizeBytes := range []int{10 << 10 /* 10 KiB */, 1<<20 + rng.Intn(64<<20)} {
+			numBatches := 1 + rng.Intn(32)
+			t.Run(fmt.Sprintf("BufferSizeBytes=%s/MaxFileSizeBytes=%s/NumBatches=%d",
+				humanizeutil.IBytes(int64(bufferSizeBytes)),
+				humanizeutil.IBytes(int64(maxFileSizeBytes)), numBatches), func(t *testing.T) {
+				batches := make([]coldata.Batch, 0, numBatches)
+				op := coldatatestutils.NewRandomDataOp(testAllocator, rng, coldatatestutils.RandomDataOpArgs{
+					NumBatches: cap(batches),
+					BatchSize:  1 + rng.Intn(coldata.BatchSize()),
+					Nulls:      true,
+					BatchAccumulator: func(b coldata.Batch, typs []*types.T) {
+						batches = append(batches, coldatatestutils.CopyBatch(b, typs, testColumnFactory))
+					},
+				})
+				typs := op.Typs()
+
+				queueCfg.CacheMode = colcontainer.DiskQueueCacheModeDefault
+				queueCfg.BufferSizeBytes = bufferSizeBytes
+				queueCfg.MaxFileSizeBytes = maxFileSizeBytes
+				q, err := colcontainer.NewRewindableDiskQueue(ctx, typs, queueCfg, testDiskAcc)
+				require.NoError(t, err)
+				// Seeking is not allowed until all batches have been enqueued.
+				require.Error(t, q.Seek(ctx, 0))
+				for {
+					b := op.Next(ctx)
+					require.NoError(t, q.Enqueue(ctx, b))
+					if b.Length() == 0 {
+						break
+					}
+				}
+
+				b := coldata.NewMemBatch(typs, testColumnFactory)
+				for i := 0; i < 4*numBatches; i++ {
+					batchIdx := rng.Intn(numBatches + 1)
+					require.NoError(t, q.Seek(ctx, batchIdx))
+					// Dequeue a few consecutive batches after seeking.
+					for n := 1 + rng.Intn(3); n > 0; n-- {
+						ok, err := q.Dequeue(ctx, b)
+						require.NoError(t, err)
+						require.True(t, ok)
+						if batchIdx >= numBatches {
+							require.Equal(t, 0, b.Length())
+							break
+						}
+						coldata.AssertEquivalentBatches(t, batches[batchIdx], b)
+						batchIdx++
+					}
+				}
+
+				// Rewinding after seeking reads all batches from the start.
+				require.NoError(t, q.Rewind())
+				for _, expected := range batches {
+					ok, err := q.Dequeue(ctx, b)
+					require.NoError(t, err)
+					require.True(t, ok)
+					coldata.AssertEquivalentBatches(t, expected, b)
+				}
+				require.NoError(t, q.Close(ctx))
+			})
+		}
+	}
+}
+
 // Flags for BenchmarkQueue.
 var (
 	bufferSizeBytes = flag.String("bufsize", "128KiB", "number of bytes to buffer in memory before flushing")
@@ -0,0 +1,438 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/marusama/semaphore"
+)
+
+// BufferedWindowArgs contains the arguments needed to create an operator that
+// computes a window function that needs to see the whole partition before
+// producing any output (e.g. window functions with custom frames, lag, lead,
+// ntile, first_value or aggregates used as window functions).
+type BufferedWindowArgs struct {
+	UnlimitedAllocator *colmem.Allocator
+	MemoryLimit        int64
+	DiskQueueCfg       colcontainer.DiskQueueCfg
+	FDSemaphore        semaphore.Semaphore
+	DiskAcc            *mon.BoundAccount
+	EvalCtx            *tree.EvalContext
+	Input              colexecbase.Operator
+	InputTypes         []*types.T
+	// WindowFn is the window function to be computed. The operator takes
+	// ownership of it and will close it when the operator is closed.
+	WindowFn tree.WindowFunc
+	// Func is the window function specification. It is used to choose the
+	// columnar implementation of the window function when there is one.
+	Func       execinfrapb.WindowerSpec_Func
+	OutputType *types.T
+	// Frame is the window frame of the window function. It can be nil in which
+	// case the default frame is used.
+	Frame    *execinfrapb.WindowerSpec_Frame
+	Ordering execinfrapb.Ordering
+	ArgsIdxs []uint32
+	// FilterColIdx is the index of the boolean column of the FILTER clause or
+	// tree.NoColumnIdx if there is no FILTER clause.
+	FilterColIdx int
+	// OutputColIdx must be equal to the number of input columns, i.e. the
+	// output column is always appended.
+	OutputColIdx int
+	// PartitionColIdx is the index of the boolean column that is true for the
+	// first tuple of each partition or tree.NoColumnIdx if there is no
+	// PARTITION BY clause.
+	PartitionColIdx int
+	// PeersColIdx is the index of the boolean column that is true for the
+	// first tuple of each peer group. It must always be set.
+	PeersColIdx int
+}
+
+// NewBufferedWindowOperator creates a new Operator that computes the given
+// window function. The operator buffers all tuples of a partition (spilling
+// them to disk if the memory limit is reached) and then computes the window
+// function for each tuple over the buffered partition. This allows for
+// supporting all window frames (ROWS, RANGE and GROUPS modes with any bounds
+// and exclusion), the FILTER clause and aggregate functions used as window
+// functions. lag, lead, ntile, first_value, last_value, nth_value and the
+// aggregate functions with the columnar implementation are computed directly
+// on the buffered vectors whereas the row-by-row implementation of the window
+// function is used for the remaining ones.
+func NewBufferedWindowOperator(args BufferedWindowArgs) (colexecbase.Operator, error) {
+	if args.OutputColIdx != len(args.InputTypes) {
+		return nil, errors.AssertionFailedf(
+			"buffered window operator expects the output column to be appended, "+
+				"output column index %d, %d input columns", args.OutputColIdx, len(args.InputTypes),
+		)
+	}
+	if args.PeersColIdx == tree.NoColumnIdx {
+		return nil, errors.AssertionFailedf("buffered window operator requires the peers column")
+	}
+	w := &bufferedWindowOp{
+		OneInputNode:    NewOneInputNode(args.Input),
+		allocator:       args.UnlimitedAllocator,
+		evalCtx:         args.EvalCtx,
+		windowFn:        args.WindowFn,
+		outputType:      args.OutputType,
+		outputColIdx:    args.OutputColIdx,
+		partitionColIdx: args.PartitionColIdx,
+	}
+	w.partition = &bufferedWindowPartition{
+		buffer: newSpillingBuffer(
+			args.UnlimitedAllocator, args.MemoryLimit, args.DiskQueueCfg,
+			args.FDSemaphore, args.InputTypes, args.DiskAcc,
+		),
+		inputTypes:  args.InputTypes,
+		peersColIdx: args.PeersColIdx,
+		scratch:     make([]tree.Datum, 1),
+		scratchSel:  make([]int, 1),
+	}
+	w.frameRun = &tree.WindowFrameRun{
+		Rows:         w.partition,
+		ArgsIdxs:     args.ArgsIdxs,
+		FilterColIdx: args.FilterColIdx,
+	}
+	if args.Frame != nil {
+		if err := args.Frame.InitFrameRun(
+			w.frameRun, args.Ordering, args.InputTypes, &w.partition.datumAlloc,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if !w.frameRun.Frame.IsDefaultFrame() {
+		// We have a custom frame not equivalent to default one, so if we have
+		// an aggregate function, we want to reset it for each row. Not
+		// resetting is an optimization since we're not computing the result
+		// over the whole frame but only as a result of the current row and
+		// previous results of aggregation.
+		builtins.ShouldReset(w.windowFn)
+	}
+	w.evaluator = newBufferedWindowFnEvaluator(&args, w.partition, w.frameRun)
+	return w, nil
+}
+
+type bufferedWindowState int
+
+const (
+	// bufferedWindowBuffering is the state in which the operator appends the
+	// tuples of the current partition to the spilling buffer. Once it sees
+	// the first tuple of the next partition (or a zero-length batch), the
+	// operator transitions to bufferedWindowEmitting state.
+	bufferedWindowBuffering bufferedWindowState = iota
+	// bufferedWindowEmitting is the state in which the operator emits the
+	// buffered tuples of the current partition together with the result of
+	// the window function computation. Once all buffered tuples are emitted,
+	// the operator transitions either back to bufferedWindowBuffering state
+	// or to bufferedWindowFinished state if the input has been exhausted.
+	bufferedWindowEmitting
+	// bufferedWindowFinished is the state in which the operator emits the
+	// zero-length batch.
+	bufferedWindowFinished
+)
+
+type bufferedWindowOp struct {
+	OneInputNode
+	closerHelper
+
+	allocator       *colmem.Allocator
+	evalCtx         *tree.EvalContext
+	windowFn        tree.WindowFunc
+	evaluator       bufferedWindowFnEvaluator
+	outputType      *types.T
+	outputColIdx    int
+	partitionColIdx int
+
+	state     bufferedWindowState
+	inputDone bool
+	// currentBatch is the last batch read from the input and nextIdx is the
+	// index of the first tuple of currentBatch that hasn't been buffered yet.
+	currentBatch coldata.Batch
+	nextIdx      int
+
+	partition *bufferedWindowPartition
+	frameRun  *tree.WindowFrameRun
+	// peerGroupEndIdx is the index of the first tuple after the current peer
+	// group within the partition.
+	peerGroupEndIdx int
+
+	output coldata.Batch
+}
+
+var _ closableOperator = &bufferedWindowOp{}
+
+func (w *bufferedWindowOp) Init() {
+	w.Input().Init()
+	w.state = bufferedWindowBuffering
+	w.currentBatch = coldata.ZeroBatch
+	outputTypes := make([]*types.T, len(w.partition.inputTypes), len(w.partition.inputTypes)+1)
+	copy(outputTypes, w.partition.inputTypes)
+	outputTypes = append(outputTypes, w.outputType)
+	w.output = w.allocator.NewMemBatchWithFixedCapacity(outputTypes, coldata.BatchSize())
+}
+
+func (w *bufferedWindowOp) Next(ctx context.Context) coldata.Batch {
+	w.partition.ctx = ctx
+	for {
+		switch w.state {
+		case bufferedWindowBuffering:
+			if w.nextIdx >= w.currentBatch.Length() {
+				w.currentBatch = w.Input().Next(ctx)
+				w.nextIdx = 0
+				if w.currentBatch.Length() == 0 {
+					w.inputDone = true
+					if w.partition.buffer.length() == 0 {
+						w.state = bufferedWindowFinished
+					} else {
+						w.startEmittingPartition(ctx)
+					}
+					continue
+				}
+			}
+			n := w.currentBatch.Length()
+			startIdx, endIdx := w.nextIdx, n
+			if w.partitionColIdx != tree.NoColumnIdx {
+				// Find the first tuple of the next partition, if it is in the
+				// current batch.
+				partitionCol := w.currentBatch.ColVec(w.partitionColIdx).Bool()
+				sel := w.currentBatch.Selection()
+				i := startIdx
+				if w.partition.buffer.length() == 0 {
+					// The first tuple belongs to the current partition.
+					i++
+				}
+				for ; i < n; i++ {
+					idx := i
+					if sel != nil {
+						idx = sel[i]
+					}
+					if partitionCol[idx] {
+						break
+					}
+				}
+				endIdx = i
+			}
+			if startIdx < endIdx {
+				w.partition.buffer.appendTuples(ctx, w.currentBatch, startIdx, endIdx)
+			}
+			w.nextIdx = endIdx
+			if endIdx < n {
+				// A new partition begins within the current batch, so the
+				// current partition has been fully buffered.
+				w.startEmittingPartition(ctx)
+			}
+
+		case bufferedWindowEmitting:
+			partitionSize := w.partition.buffer.length()
+			if w.frameRun.RowIdx >= partitionSize {
+				w.partition.buffer.reset(ctx)
+				if w.inputDone {
+					w.state = bufferedWindowFinished
+				} else {
+					w.state = bufferedWindowBuffering
+				}
+				continue
+			}
+			w.output.ResetInternalBatch()
+			toEmit := partitionSize - w.frameRun.RowIdx
+			if toEmit > coldata.BatchSize() {
+				toEmit = coldata.BatchSize()
+			}
+			w.copyBufferedTuples(ctx, w.frameRun.RowIdx, toEmit)
+			w.computeWindowFunction(ctx, toEmit)
+			w.output.SetLength(toEmit)
+			return w.output
+
+		case bufferedWindowFinished:
+			if err := w.Close(ctx); err != nil {
+				colexecerror.InternalError(err)
+			}
+			return coldata.ZeroBatch
+
+		default:
+			colexecerror.InternalError(errors.AssertionFailedf("buffered window operator in unhandled state"))
+			// This code is unreachable, but the compiler cannot infer that.
+			return nil
+		}
+	}
+}
+
+// startEmittingPartition prepares the window function and the frame for the
+// computation over the fully buffered partition.
+func (w *bufferedWindowOp) startEmittingPartition(ctx context.Context) {
+	w.state = bufferedWindowEmitting
+	w.evaluator.startPartition(ctx)
+	w.frameRun.RowIdx = 0
+	w.frameRun.CurRowPeerGroupNum = 0
+	if err := w.frameRun.PeerHelper.Init(w.frameRun, w.partition); err != nil {
+		colexecerror.InternalError(err)
+	}
+	w.peerGroupEndIdx = w.frameRun.PeerHelper.GetFirstPeerIdx(0) + w.frameRun.PeerHelper.GetRowCount(0)
+}
+
+// copyBufferedTuples copies toCopy buffered tuples starting from startIdx
+// into the input columns of the output batch.
+func (w *bufferedWindowOp) copyBufferedTuples(ctx context.Context, startIdx int, toCopy int) {
+	inputVecs := w.output.ColVecs()[:w.outputColIdx]
+	w.allocator.PerformOperation(inputVecs, func() {
+		for destIdx := 0; destIdx < toCopy; {
+			batch, rowIdx, err := w.partition.buffer.getTuple(ctx, startIdx+destIdx)
+			if err != nil {
+				colexecerror.InternalError(err)
+			}
+			n := batch.Length() - rowIdx
+			if n > toCopy-destIdx {
+				n = toCopy - destIdx
+			}
+			for colIdx, vec := range inputVecs {
+				vec.Copy(
+					coldata.CopySliceArgs{
+						SliceArgs: coldata.SliceArgs{
+							Src:         batch.ColVec(colIdx),
+							DestIdx:     destIdx,
+							SrcStartIdx: rowIdx,
+							SrcEndIdx:   rowIdx + n,
+						},
+					},
+				)
+			}
+			destIdx += n
+		}
+	})
+}
+
+// computeWindowFunction computes the window function for the next toCompute
+// tuples of the partition and writes the results into the output column.
+func (w *bufferedWindowOp) computeWindowFunction(ctx context.Context, toCompute int) {
+	output := w.output.ColVec(w.outputColIdx)
+	w.allocator.PerformOperation([]coldata.Vec{output}, func() {
+		for i := 0; i < toCompute; i++ {
+			if w.frameRun.RowIdx >= w.peerGroupEndIdx {
+				// The current tuple begins a new peer group.
+				if err := w.frameRun.PeerHelper.Update(w.frameRun); err != nil {
+					colexecerror.InternalError(err)
+				}
+				w.frameRun.CurRowPeerGroupNum++
+				w.peerGroupEndIdx = w.frameRun.PeerHelper.GetFirstPeerIdx(w.frameRun.CurRowPeerGroupNum) +
+					w.frameRun.PeerHelper.GetRowCount(w.frameRun.CurRowPeerGroupNum)
+			}
+			if err := w.evaluator.compute(ctx, output, i); err != nil {
+				colexecerror.ExpectedError(err)
+			}
+			w.frameRun.RowIdx++
+		}
+	})
+}
+
+func (w *bufferedWindowOp) Close(ctx context.Context) error {
+	if !w.close() {
+		return nil
+	}
+	w.evaluator.close(ctx)
+	w.windowFn.Close(ctx, w.evalCtx)
+	return w.partition.buffer.close(ctx)
+}
+
+// bufferedWindowPartition provides the access to the tuples of a partition
+// buffered in a spillingBuffer in the form required by the row-by-row
+// implementations of window functions. It also serves as the peer grouper of
+// the partition, relying on the peers column.
+type bufferedWindowPartition struct {
+	ctx         context.Context
+	buffer      *spillingBuffer
+	inputTypes  []*types.T
+	peersColIdx int
+	datumAlloc  rowenc.DatumAlloc
+	// scratch and scratchSel are used to convert a single value to a datum.
+	scratch    []tree.Datum
+	scratchSel []int
+}
+
+var _ tree.IndexedRows = &bufferedWindowPartition{}
+var _ tree.PeerGroupChecker = &bufferedWindowPartition{}
+
+// Len implements the tree.IndexedRows interface.
+func (p *bufferedWindowPartition) Len() int {
+	return p.buffer.length()
+}
+
+// GetRow implements the tree.IndexedRows interface.
+func (p *bufferedWindowPartition) GetRow(_ context.Context, idx int) (tree.IndexedRow, error) {
+	return bufferedWindowRow{partition: p, idx: idx}, nil
+}
+
+// InSameGroup implements the tree.PeerGroupChecker interface. It is always
+// called with two consecutive indices.
+func (p *bufferedWindowPartition) InSameGroup(i, j int) (bool, error) {
+	if j != i+1 {
+		return false, errors.AssertionFailedf("unexpectedly InSameGroup called with non-consecutive indices %d and %d", i, j)
+	}
+	batch, rowIdx, err := p.buffer.getTuple(p.ctx, j)
+	if err != nil {
+		return false, err
+	}
+	return !batch.ColVec(p.peersColIdx).Bool()[rowIdx], nil
+}
+
+// getDatum returns the value in column colIdx of the idx'th tuple.
+func (p *bufferedWindowPartition) getDatum(idx, colIdx int) (tree.Datum, error) {
+	batch, rowIdx, err := p.buffer.getTuple(p.ctx, idx)
+	if err != nil {
+		return nil, err
+	}
+	p.scratchSel[0] = rowIdx
+	colconv.ColVecToDatumAndDeselect(p.scratch, batch.ColVec(colIdx), 1 /* length */, p.scratchSel, &p.datumAlloc)
+	return p.scratch[0], nil
+}
+
+// bufferedWindowRow is a tuple of a partition buffered by the buffered window
+// operator.
+type bufferedWindowRow struct {
+	partition *bufferedWindowPartition
+	idx       int
+}
+
+var _ tree.IndexedRow = bufferedWindowRow{}
+
+// GetIdx implements the tree.IndexedRow interface.
+func (r bufferedWindowRow) GetIdx() int {
+	return r.idx
+}
+
+// GetDatum implements the tree.IndexedRow interface.
+func (r bufferedWindowRow) GetDatum(colIdx int) (tree.Datum, error) {
+	return r.partition.getDatum(r.idx, colIdx)
+}
+
+// GetDatums implements the tree.IndexedRow interface.
+func (r bufferedWindowRow) GetDatums(startColIdx, endColIdx int) (tree.Datums, error) {
+	datums := make(tree.Datums, 0, endColIdx-startColIdx)
+	for colIdx := startColIdx; colIdx < endColIdx; colIdx++ {
+		d, err := r.partition.getDatum(r.idx, colIdx)
+		if err != nil {
+			return nil, err
+		}
+		datums = append(datums, d)
+	}
+	return datums, nil
+}
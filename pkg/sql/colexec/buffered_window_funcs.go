@@ -0,0 +1,550 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/typeconv"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// bufferedWindowFnEvaluator computes a window function over a partition
+// buffered by the buffered window operator.
+type bufferedWindowFnEvaluator interface {
+	// startPartition is called once all tuples of a partition have been
+	// buffered, before the window function is computed for any of them.
+	startPartition(ctx context.Context)
+	// compute computes the window function for the current row of the frame
+	// run and writes the result into the output vector at outputIdx. It is
+	// called for consecutive rows of the partition.
+	compute(ctx context.Context, output coldata.Vec, outputIdx int) error
+	// close releases the resources held by the evaluator.
+	close(ctx context.Context)
+}
+
+// newBufferedWindowFnEvaluator returns the columnar implementation of the
+// window function if there is one. Otherwise, the row-by-row implementation
+// of the window function is evaluated over the buffered partition.
+func newBufferedWindowFnEvaluator(
+	args *BufferedWindowArgs, partition *bufferedWindowPartition, frameRun *tree.WindowFrameRun,
+) bufferedWindowFnEvaluator {
+	base := columnarWindowFnBase{
+		partition: partition,
+		evalCtx:   args.EvalCtx,
+		frameRun:  frameRun,
+	}
+	if args.Func.AggregateFunc != nil {
+		if fn := newAggregateWindowFn(base, args); fn != nil {
+			return fn
+		}
+	} else if args.Func.WindowFunc != nil {
+		switch fn := *args.Func.WindowFunc; fn {
+		case execinfrapb.WindowerSpec_LAG, execinfrapb.WindowerSpec_LEAD:
+			w := &leadLagWindowFn{
+				columnarWindowFnBase: base,
+				forward:              fn == execinfrapb.WindowerSpec_LEAD,
+				argIdx:               int(args.ArgsIdxs[0]),
+				offsetIdx:            tree.NoColumnIdx,
+				defaultIdx:           tree.NoColumnIdx,
+			}
+			if len(args.ArgsIdxs) > 1 {
+				w.offsetIdx = int(args.ArgsIdxs[1])
+			}
+			if len(args.ArgsIdxs) > 2 {
+				w.defaultIdx = int(args.ArgsIdxs[2])
+			}
+			return w
+		case execinfrapb.WindowerSpec_FIRST_VALUE, execinfrapb.WindowerSpec_LAST_VALUE,
+			execinfrapb.WindowerSpec_NTH_VALUE:
+			w := &valueWindowFn{
+				columnarWindowFnBase: base,
+				fn:                   fn,
+				argIdx:               int(args.ArgsIdxs[0]),
+				nthIdx:               tree.NoColumnIdx,
+			}
+			if fn == execinfrapb.WindowerSpec_NTH_VALUE {
+				w.nthIdx = int(args.ArgsIdxs[1])
+			}
+			return w
+		case execinfrapb.WindowerSpec_NTILE:
+			return &ntileWindowFn{
+				columnarWindowFnBase: base,
+				argIdx:               int(args.ArgsIdxs[0]),
+			}
+		}
+	}
+	return &rowWindowFnEvaluator{
+		windowFn:            args.WindowFn,
+		evalCtx:             args.EvalCtx,
+		frameRun:            frameRun,
+		datumToVecConverter: GetDatumToPhysicalFn(args.OutputType),
+	}
+}
+
+// rowWindowFnEvaluator evaluates the row-by-row implementation of a window
+// function. It is used for the window functions that don't have a columnar
+// implementation.
+type rowWindowFnEvaluator struct {
+	windowFn            tree.WindowFunc
+	evalCtx             *tree.EvalContext
+	frameRun            *tree.WindowFrameRun
+	datumToVecConverter func(tree.Datum) interface{}
+}
+
+var _ bufferedWindowFnEvaluator = &rowWindowFnEvaluator{}
+
+func (e *rowWindowFnEvaluator) startPartition(ctx context.Context) {
+	e.windowFn.Reset(ctx)
+}
+
+func (e *rowWindowFnEvaluator) compute(
+	ctx context.Context, output coldata.Vec, outputIdx int,
+) error {
+	res, err := e.windowFn.Compute(ctx, e.evalCtx, e.frameRun)
+	if err != nil {
+		return err
+	}
+	if res == tree.DNull {
+		output.Nulls().SetNull(outputIdx)
+	} else {
+		coldata.SetValueAt(output, e.datumToVecConverter(res), outputIdx)
+	}
+	return nil
+}
+
+func (e *rowWindowFnEvaluator) close(context.Context) {}
+
+// columnarWindowFnBase contains the state shared by the columnar
+// implementations of window functions, which operate directly on the vectors
+// of the buffered partition.
+type columnarWindowFnBase struct {
+	partition *bufferedWindowPartition
+	evalCtx   *tree.EvalContext
+	frameRun  *tree.WindowFrameRun
+}
+
+// frameBounds returns the window frame of the current row as the half-open
+// interval of row indices.
+func (b *columnarWindowFnBase) frameBounds(ctx context.Context) (int, int, error) {
+	start, err := b.frameRun.FrameStartIdx(ctx, b.evalCtx)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := b.frameRun.FrameEndIdx(ctx, b.evalCtx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// isRowExcluded returns whether the row at index idx is excluded from the
+// window frame of the current row by the frame exclusion clause.
+func (b *columnarWindowFnBase) isRowExcluded(idx int) bool {
+	wfr := b.frameRun
+	if wfr.Frame.DefaultFrameExclusion() {
+		return false
+	}
+	switch wfr.Frame.Exclusion {
+	case tree.ExcludeCurrentRow:
+		return idx == wfr.RowIdx
+	case tree.ExcludeGroup, tree.ExcludeTies:
+		firstPeerIdx := wfr.PeerHelper.GetFirstPeerIdx(wfr.CurRowPeerGroupNum)
+		inPeerGroup := firstPeerIdx <= idx && idx < firstPeerIdx+wfr.PeerHelper.GetRowCount(wfr.CurRowPeerGroupNum)
+		if wfr.Frame.Exclusion == tree.ExcludeTies {
+			return inPeerGroup && idx != wfr.RowIdx
+		}
+		return inPeerGroup
+	default:
+		return false
+	}
+}
+
+// getInt returns the integer in column colIdx of the idx'th row of the
+// partition, or false if the value is NULL.
+func (b *columnarWindowFnBase) getInt(ctx context.Context, idx, colIdx int) (int64, bool, error) {
+	batch, rowIdx, err := b.partition.buffer.getTuple(ctx, idx)
+	if err != nil {
+		return 0, false, err
+	}
+	vec := batch.ColVec(colIdx)
+	if vec.Nulls().MaybeHasNulls() && vec.Nulls().NullAt(rowIdx) {
+		return 0, false, nil
+	}
+	return vec.Int64()[rowIdx], true, nil
+}
+
+// copyValue copies the value in column colIdx of the idx'th row of the
+// partition into the output vector at outputIdx.
+func (b *columnarWindowFnBase) copyValue(
+	ctx context.Context, output coldata.Vec, outputIdx int, idx int, colIdx int,
+) error {
+	batch, rowIdx, err := b.partition.buffer.getTuple(ctx, idx)
+	if err != nil {
+		return err
+	}
+	output.Copy(
+		coldata.CopySliceArgs{
+			SliceArgs: coldata.SliceArgs{
+				Src:         batch.ColVec(colIdx),
+				DestIdx:     outputIdx,
+				SrcStartIdx: rowIdx,
+				SrcEndIdx:   rowIdx + 1,
+			},
+		},
+	)
+	return nil
+}
+
+func (b *columnarWindowFnBase) startPartition(context.Context) {}
+
+func (b *columnarWindowFnBase) close(context.Context) {}
+
+// leadLagWindowFn is the columnar implementation of lag and lead.
+type leadLagWindowFn struct {
+	columnarWindowFnBase
+	forward bool
+	argIdx  int
+	// offsetIdx and defaultIdx are the indices of the columns with the offset
+	// and the default value arguments or tree.NoColumnIdx if these arguments
+	// are omitted.
+	offsetIdx  int
+	defaultIdx int
+}
+
+var _ bufferedWindowFnEvaluator = &leadLagWindowFn{}
+
+func (w *leadLagWindowFn) compute(ctx context.Context, output coldata.Vec, outputIdx int) error {
+	rowIdx := w.frameRun.RowIdx
+	offset := 1
+	if w.offsetIdx != tree.NoColumnIdx {
+		o, ok, err := w.getInt(ctx, rowIdx, w.offsetIdx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Nulls().SetNull(outputIdx)
+			return nil
+		}
+		offset = int(o)
+	}
+	if !w.forward {
+		offset = -offset
+	}
+	if targetIdx := rowIdx + offset; targetIdx >= 0 && targetIdx < w.partition.Len() {
+		return w.copyValue(ctx, output, outputIdx, targetIdx, w.argIdx)
+	}
+	// The target row is outside of the partition, so we return the default
+	// value if it is provided or NULL otherwise.
+	if w.defaultIdx != tree.NoColumnIdx {
+		return w.copyValue(ctx, output, outputIdx, rowIdx, w.defaultIdx)
+	}
+	output.Nulls().SetNull(outputIdx)
+	return nil
+}
+
+var errInvalidArgumentForNthValue = pgerror.Newf(
+	pgcode.InvalidParameterValue, "argument of nth_value() must be greater than zero")
+
+// valueWindowFn is the columnar implementation of first_value, last_value and
+// nth_value.
+type valueWindowFn struct {
+	columnarWindowFnBase
+	fn     execinfrapb.WindowerSpec_WindowFunc
+	argIdx int
+	// nthIdx is the index of the column with the second argument of nth_value.
+	nthIdx int
+}
+
+var _ bufferedWindowFnEvaluator = &valueWindowFn{}
+
+func (w *valueWindowFn) compute(ctx context.Context, output coldata.Vec, outputIdx int) error {
+	nth := 1
+	if w.nthIdx != tree.NoColumnIdx {
+		n, ok, err := w.getInt(ctx, w.frameRun.RowIdx, w.nthIdx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Nulls().SetNull(outputIdx)
+			return nil
+		}
+		if n <= 0 {
+			return errInvalidArgumentForNthValue
+		}
+		nth = int(n)
+	}
+	start, end, err := w.frameBounds(ctx)
+	if err != nil {
+		return err
+	}
+	// Find the nth row of the frame that isn't excluded from it, counting
+	// from the end of the frame for last_value.
+	idx, step := start, 1
+	if w.fn == execinfrapb.WindowerSpec_LAST_VALUE {
+		idx, step = end-1, -1
+	}
+	for ; idx >= start && idx < end; idx += step {
+		if w.isRowExcluded(idx) {
+			continue
+		}
+		if nth--; nth == 0 {
+			return w.copyValue(ctx, output, outputIdx, idx, w.argIdx)
+		}
+	}
+	// The frame doesn't contain enough rows.
+	output.Nulls().SetNull(outputIdx)
+	return nil
+}
+
+var errInvalidArgumentForNtile = pgerror.Newf(
+	pgcode.InvalidParameterValue, "argument of ntile() must be greater than zero")
+
+// ntileWindowFn is the columnar implementation of ntile.
+type ntileWindowFn struct {
+	columnarWindowFnBase
+	argIdx int
+
+	// initialized is set once the number of buckets has been read. Until then,
+	// the argument is read from every row, and the result is NULL while it is
+	// NULL.
+	initialized bool
+	// ntile is the current result, and curBucketCount is the number of rows in
+	// the current bucket so far.
+	ntile          int64
+	curBucketCount int
+	// boundary is the number of rows in the current bucket, and remainder is
+	// the number of leading buckets that contain an extra row.
+	boundary  int
+	remainder int
+}
+
+var _ bufferedWindowFnEvaluator = &ntileWindowFn{}
+
+func (w *ntileWindowFn) startPartition(context.Context) {
+	w.initialized = false
+}
+
+func (w *ntileWindowFn) compute(ctx context.Context, output coldata.Vec, outputIdx int) error {
+	if !w.initialized {
+		nbuckets, ok, err := w.getInt(ctx, w.frameRun.RowIdx, w.argIdx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			output.Nulls().SetNull(outputIdx)
+			return nil
+		}
+		if nbuckets <= 0 {
+			return errInvalidArgumentForNtile
+		}
+		total := w.partition.Len()
+		w.initialized = true
+		w.ntile = 1
+		w.curBucketCount = 0
+		w.remainder = 0
+		w.boundary = total / int(nbuckets)
+		if w.boundary <= 0 {
+			w.boundary = 1
+		} else {
+			// If the total number is not divisible, add 1 row to leading buckets.
+			w.remainder = total % int(nbuckets)
+			if w.remainder != 0 {
+				w.boundary++
+			}
+		}
+	}
+	w.curBucketCount++
+	if w.boundary < w.curBucketCount {
+		// Move to the next bucket.
+		if w.remainder != 0 && int(w.ntile) == w.remainder {
+			w.remainder = 0
+			w.boundary--
+		}
+		w.ntile++
+		w.curBucketCount = 1
+	}
+	output.Int64()[outputIdx] = w.ntile
+	return nil
+}
+
+// aggregateWindowFn is the columnar implementation of aggregate functions used
+// as window functions. The aggregate function is computed over the window
+// frame of each row using the columnar (hash) aggregate function.
+type aggregateWindowFn struct {
+	columnarWindowFnBase
+	allocator    *colmem.Allocator
+	aggFnsAlloc  *aggregateFuncsAlloc
+	argIdxs      []uint32
+	filterColIdx int
+	// incremental is true if the frame of every row starts at the beginning of
+	// the partition and the aggregate function can produce its intermediate
+	// results, in which case the rows are aggregated only once.
+	incremental bool
+	// aggFn and aggregatedEndIdx are the aggregate function used in the
+	// incremental mode and the index of the first row it hasn't aggregated.
+	aggFn            aggregateFunc
+	aggregatedEndIdx int
+	// scratch is the single-element vector that the aggregate function writes
+	// its result into.
+	scratch coldata.Vec
+	sel     []int
+}
+
+var _ bufferedWindowFnEvaluator = &aggregateWindowFn{}
+
+// newAggregateWindowFn returns the columnar implementation of the aggregate
+// function used as a window function or nil if there is no such
+// implementation.
+func newAggregateWindowFn(base columnarWindowFnBase, args *BufferedWindowArgs) *aggregateWindowFn {
+	aggFn := *args.Func.AggregateFunc
+	if !isAggOptimized(aggFn) {
+		return nil
+	}
+	spec := &execinfrapb.AggregatorSpec{
+		Aggregations: []execinfrapb.AggregatorSpec_Aggregation{{Func: aggFn, ColIdx: args.ArgsIdxs}},
+	}
+	aggFnsAlloc, _, _, err := newAggregateFuncsAlloc(
+		args.UnlimitedAllocator, args.InputTypes, spec, args.EvalCtx,
+		nil /* constructors */, nil /* constArguments */, []*types.T{args.OutputType},
+		hashAggregatorAllocSize, true, /* isHashAgg */
+	)
+	if err != nil {
+		// The aggregate function isn't supported for the argument type.
+		return nil
+	}
+	w := &aggregateWindowFn{
+		columnarWindowFnBase: base,
+		allocator:            args.UnlimitedAllocator,
+		aggFnsAlloc:          aggFnsAlloc,
+		argIdxs:              args.ArgsIdxs,
+		filterColIdx:         args.FilterColIdx,
+		scratch:              args.UnlimitedAllocator.NewMemColumn(args.OutputType, 1 /* capacity */),
+		sel:                  make([]int, coldata.BatchSize()),
+	}
+	frame := base.frameRun.Frame
+	w.incremental = (frame == nil || frame.Bounds.StartBound.BoundType == tree.UnboundedPreceding) &&
+		frame.DefaultFrameExclusion() && aggFlushIsRepeatable(aggFn, args.InputTypes, args.ArgsIdxs)
+	return w
+}
+
+// aggFlushIsRepeatable returns whether the aggregate function can be flushed
+// and then continue aggregating more rows. Some implementations release their
+// result on flush.
+func aggFlushIsRepeatable(
+	aggFn execinfrapb.AggregatorSpec_Func, inputTypes []*types.T, argIdxs []uint32,
+) bool {
+	switch aggFn {
+	case execinfrapb.AggregatorSpec_CONCAT_AGG:
+		return false
+	case execinfrapb.AggregatorSpec_MIN, execinfrapb.AggregatorSpec_MAX,
+		execinfrapb.AggregatorSpec_ANY_NOT_NULL:
+		switch typeconv.TypeFamilyToCanonicalTypeFamily(inputTypes[argIdxs[0]].Family()) {
+		case types.BytesFamily, typeconv.DatumVecCanonicalTypeFamily:
+			return false
+		}
+	}
+	return true
+}
+
+func (w *aggregateWindowFn) startPartition(context.Context) {
+	w.aggFn = nil
+	w.aggregatedEndIdx = 0
+}
+
+func (w *aggregateWindowFn) newAggFn() aggregateFunc {
+	fn := w.aggFnsAlloc.makeAggregateFuncs()[0]
+	fn.Init(nil /* groups */, w.scratch)
+	return fn
+}
+
+// aggregate feeds the rows of the partition in range [startIdx, endIdx) that
+// are neither excluded from the frame nor filtered out into the aggregate
+// function.
+func (w *aggregateWindowFn) aggregate(
+	ctx context.Context, fn aggregateFunc, startIdx, endIdx int,
+) error {
+	for idx := startIdx; idx < endIdx; {
+		batch, rowIdx, err := w.partition.buffer.getTuple(ctx, idx)
+		if err != nil {
+			return err
+		}
+		n := batch.Length() - rowIdx
+		if n > endIdx-idx {
+			n = endIdx - idx
+		}
+		var filter []bool
+		var filterNulls *coldata.Nulls
+		if w.filterColIdx != tree.NoColumnIdx {
+			filterVec := batch.ColVec(w.filterColIdx)
+			filter, filterNulls = filterVec.Bool(), filterVec.Nulls()
+		}
+		sel := w.sel[:0]
+		for i := rowIdx; i < rowIdx+n; i++ {
+			if w.isRowExcluded(idx + i - rowIdx) {
+				continue
+			}
+			if filter != nil && (!filter[i] || filterNulls.NullAt(i)) {
+				continue
+			}
+			sel = append(sel, i)
+		}
+		if len(sel) > 0 {
+			fn.Compute(batch.ColVecs(), w.argIdxs, len(sel), sel)
+		}
+		idx += n
+	}
+	return nil
+}
+
+func (w *aggregateWindowFn) compute(ctx context.Context, output coldata.Vec, outputIdx int) error {
+	start, end, err := w.frameBounds(ctx)
+	if err != nil {
+		return err
+	}
+	fn := w.aggFn
+	if w.incremental {
+		if fn == nil || end < w.aggregatedEndIdx {
+			fn = w.newAggFn()
+			w.aggFn, w.aggregatedEndIdx = fn, start
+		}
+		if err := w.aggregate(ctx, fn, w.aggregatedEndIdx, end); err != nil {
+			return err
+		}
+		w.aggregatedEndIdx = end
+	} else {
+		fn = w.newAggFn()
+		if err := w.aggregate(ctx, fn, start, end); err != nil {
+			return err
+		}
+	}
+	w.allocator.PerformOperation([]coldata.Vec{w.scratch}, func() {
+		w.scratch.Nulls().UnsetNulls()
+		fn.Flush(0 /* outputIdx */)
+	})
+	output.Copy(
+		coldata.CopySliceArgs{
+			SliceArgs: coldata.SliceArgs{
+				Src:         w.scratch,
+				DestIdx:     outputIdx,
+				SrcStartIdx: 0,
+				SrcEndIdx:   1,
+			},
+		},
+	)
+	return nil
+}
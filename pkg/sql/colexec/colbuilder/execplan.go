@@ -225,26 +225,15 @@ func isSupported(mode sessiondata.VectorizeExecMode, spec *execinfrapb.Processor
 
 	case core.Windower != nil:
 		for _, wf := range core.Windower.WindowFns {
-			if wf.Frame != nil {
-				frame, err := wf.Frame.ConvertToAST()
-				if err != nil {
-					return err
-				}
-				if !frame.IsDefaultFrame() {
-					return errors.Newf("window functions with non-default window frames are not supported")
+			if wf.Func.WindowFunc != nil {
+				if _, supported := SupportedWindowFns[*wf.Func.WindowFunc]; !supported {
+					return errors.Newf("window function %s is not supported", wf.String())
 				}
 			}
-			if wf.FilterColIdx != tree.NoColumnIdx {
-				return errors.Newf("window functions with FILTER clause are not supported")
-			}
-			if wf.Func.AggregateFunc != nil {
-				return errors.Newf("aggregate functions used as window functions are not supported")
-			}
-
-			if _, supported := SupportedWindowFns[*wf.Func.WindowFunc]; !supported {
-				return errors.Newf("window function %s is not supported", wf.String())
-			}
 			if !isFullVectorization {
+				if windowFnIsBuffered(wf.Func) {
+					return errors.Newf("window function %s can only run in vectorize 'on' mode", wf.String())
+				}
 				switch *wf.Func.WindowFunc {
 				case execinfrapb.WindowerSpec_PERCENT_RANK, execinfrapb.WindowerSpec_CUME_DIST:
 					return errors.Newf("window function %s can only run in vectorize 'on' mode", wf.String())
@@ -973,7 +962,14 @@ func NewColOperator(
 				copy(typs, result.ColumnTypes)
 				tempColOffset, partitionColIdx := uint32(0), tree.NoColumnIdx
 				peersColIdx := tree.NoColumnIdx
-				windowFn := *wf.Func.WindowFunc
+				argTypes := make([]*types.T, len(wf.ArgsIdxs))
+				for i, argIdx := range wf.ArgsIdxs {
+					argTypes[i] = typs[argIdx]
+				}
+				windowConstructor, returnType, err := execinfrapb.GetWindowFunctionInfo(wf.Func, argTypes...)
+				if err != nil {
+					return r, err
+				}
 				if len(core.Windower.PartitionBy) > 0 {
 					// TODO(yuzefovich): add support for hashing partitioner (probably by
 					// leveraging hash routers once we can distribute). The decision about
@@ -1006,12 +1002,15 @@ func NewColOperator(
 				if err != nil {
 					return r, err
 				}
-				if windowFnNeedsPeersInfo(*wf.Func.WindowFunc) {
+				if windowFnNeedsPeersInfo(wf.Func) {
 					peersColIdx = int(wf.OutputColIdx + tempColOffset)
 					input, err = colexec.NewWindowPeerGrouper(
 						streamingAllocator, input, typs, wf.Ordering.Columns,
 						partitionColIdx, peersColIdx,
 					)
+					if err != nil {
+						return r, err
+					}
 					// Window peer grouper will append a boolean column.
 					tempColOffset++
 					typs = typs[:len(typs)+1]
@@ -1019,37 +1018,78 @@ func NewColOperator(
 				}
 
 				outputIdx := int(wf.OutputColIdx + tempColOffset)
-				switch windowFn {
-				case execinfrapb.WindowerSpec_ROW_NUMBER:
-					result.Op = colexec.NewRowNumberOperator(streamingAllocator, input, outputIdx, partitionColIdx)
-				case execinfrapb.WindowerSpec_RANK, execinfrapb.WindowerSpec_DENSE_RANK:
-					result.Op, err = colexec.NewRankOperator(
-						streamingAllocator, input, windowFn, wf.Ordering.Columns,
-						outputIdx, partitionColIdx, peersColIdx,
-					)
-				case execinfrapb.WindowerSpec_PERCENT_RANK, execinfrapb.WindowerSpec_CUME_DIST:
-					// We are using an unlimited memory monitor here because
-					// relative rank operators themselves are responsible for
-					// making sure that we stay within the memory limit, and
-					// they will fall back to disk if necessary.
-					memAccName := memMonitorsPrefix + "relative-rank"
-					unlimitedAllocator := colmem.NewAllocator(
-						ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, memAccName), factory,
-					)
-					diskAcc := result.createDiskAccount(ctx, flowCtx, memAccName)
-					result.Op, err = colexec.NewRelativeRankOperator(
-						unlimitedAllocator, execinfra.GetWorkMemLimit(flowCtx.Cfg), args.DiskQueueCfg,
-						args.FDSemaphore, input, typs, windowFn, wf.Ordering.Columns,
-						outputIdx, partitionColIdx, peersColIdx, diskAcc,
-					)
-					// NewRelativeRankOperator sometimes returns a constOp when there
-					// are no ordering columns, so we check that the returned operator
-					// is an Closer.
-					if c, ok := result.Op.(colexec.Closer); ok {
-						result.ToClose = append(result.ToClose, c)
+				if windowFnIsBuffered(wf.Func) {
+					// We are using an unlimited memory monitor here because the
+					// buffered window operator itself is responsible for making
+					// sure that we stay within the memory limit, and it will fall
+					// back to disk if necessary.
+					memAccName := memMonitorsPrefix + "buffered"
+					unlimitedMemAcc := result.createBufferingUnlimitedMemAccount(ctx, flowCtx, memAccName)
+					evalCtx := flowCtx.NewEvalCtx()
+					evalCtx.SingleDatumAggMemAccount = unlimitedMemAcc
+					// All tuples of a partition are spilled to disk before any
+					// of them are read, so the cache can be reused.
+					diskQueueCfg := args.DiskQueueCfg
+					diskQueueCfg.CacheMode = colcontainer.DiskQueueCacheModeReuseCache
+					diskQueueCfg.SetDefaultBufferSizeBytesForCacheMode()
+					result.Op, err = colexec.NewBufferedWindowOperator(colexec.BufferedWindowArgs{
+						UnlimitedAllocator: colmem.NewAllocator(ctx, unlimitedMemAcc, factory),
+						MemoryLimit:        execinfra.GetWorkMemLimit(flowCtx.Cfg),
+						DiskQueueCfg:       diskQueueCfg,
+						FDSemaphore:        args.FDSemaphore,
+						DiskAcc:            result.createDiskAccount(ctx, flowCtx, memAccName),
+						EvalCtx:            evalCtx,
+						Input:              input,
+						InputTypes:         typs,
+						WindowFn:           windowConstructor(evalCtx),
+						Func:               wf.Func,
+						OutputType:         returnType,
+						Frame:              wf.Frame,
+						Ordering:           wf.Ordering,
+						ArgsIdxs:           wf.ArgsIdxs,
+						FilterColIdx:       int(wf.FilterColIdx),
+						OutputColIdx:       outputIdx,
+						PartitionColIdx:    partitionColIdx,
+						PeersColIdx:        peersColIdx,
+					})
+					if err != nil {
+						return r, err
+					}
+					result.ToClose = append(result.ToClose, result.Op.(colexec.Closer))
+				} else {
+					windowFn := *wf.Func.WindowFunc
+					switch windowFn {
+					case execinfrapb.WindowerSpec_ROW_NUMBER:
+						result.Op = colexec.NewRowNumberOperator(streamingAllocator, input, outputIdx, partitionColIdx)
+					case execinfrapb.WindowerSpec_RANK, execinfrapb.WindowerSpec_DENSE_RANK:
+						result.Op, err = colexec.NewRankOperator(
+							streamingAllocator, input, windowFn, wf.Ordering.Columns,
+							outputIdx, partitionColIdx, peersColIdx,
+						)
+					case execinfrapb.WindowerSpec_PERCENT_RANK, execinfrapb.WindowerSpec_CUME_DIST:
+						// We are using an unlimited memory monitor here because
+						// relative rank operators themselves are responsible for
+						// making sure that we stay within the memory limit, and
+						// they will fall back to disk if necessary.
+						memAccName := memMonitorsPrefix + "relative-rank"
+						unlimitedAllocator := colmem.NewAllocator(
+							ctx, result.createBufferingUnlimitedMemAccount(ctx, flowCtx, memAccName), factory,
+						)
+						diskAcc := result.createDiskAccount(ctx, flowCtx, memAccName)
+						result.Op, err = colexec.NewRelativeRankOperator(
+							unlimitedAllocator, execinfra.GetWorkMemLimit(flowCtx.Cfg), args.DiskQueueCfg,
+							args.FDSemaphore, input, typs, windowFn, wf.Ordering.Columns,
+							outputIdx, partitionColIdx, peersColIdx, diskAcc,
+						)
+						// NewRelativeRankOperator sometimes returns a constOp when there
+						// are no ordering columns, so we check that the returned operator
+						// is an Closer.
+						if c, ok := result.Op.(colexec.Closer); ok {
+							result.ToClose = append(result.ToClose, c)
+						}
+					default:
+						return r, errors.AssertionFailedf("window function %s is not supported", wf.String())
 					}
-				default:
-					return r, errors.AssertionFailedf("window function %s is not supported", wf.String())
 				}
 
 				if tempColOffset > 0 {
@@ -1063,10 +1103,6 @@ func NewColOperator(
 					result.Op = colexec.NewSimpleProjectOp(result.Op, int(wf.OutputColIdx+tempColOffset), projection)
 				}
 
-				_, returnType, err := execinfrapb.GetWindowFunctionInfo(wf.Func, []*types.T{}...)
-				if err != nil {
-					return r, err
-				}
 				result.ColumnTypes = appendOneType(result.ColumnTypes, returnType)
 				input = result.Op
 			}
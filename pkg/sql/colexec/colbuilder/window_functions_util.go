@@ -24,6 +24,34 @@ var SupportedWindowFns = map[execinfrapb.WindowerSpec_WindowFunc]struct{}{
 	execinfrapb.WindowerSpec_DENSE_RANK:   {},
 	execinfrapb.WindowerSpec_PERCENT_RANK: {},
 	execinfrapb.WindowerSpec_CUME_DIST:    {},
+	execinfrapb.WindowerSpec_NTILE:        {},
+	execinfrapb.WindowerSpec_LAG:          {},
+	execinfrapb.WindowerSpec_LEAD:         {},
+	execinfrapb.WindowerSpec_FIRST_VALUE:  {},
+	execinfrapb.WindowerSpec_LAST_VALUE:   {},
+	execinfrapb.WindowerSpec_NTH_VALUE:    {},
+}
+
+// windowFnIsBuffered returns whether a window function is computed by the
+// buffered window operator which buffers the whole partition and evaluates
+// the row-by-row implementation of the window function over it. Aggregate
+// functions used as window functions are always buffered, as are all window
+// functions that don't have a specialized vectorized implementation.
+func windowFnIsBuffered(fn execinfrapb.WindowerSpec_Func) bool {
+	if fn.AggregateFunc != nil {
+		return true
+	}
+	switch *fn.WindowFunc {
+	case
+		execinfrapb.WindowerSpec_ROW_NUMBER,
+		execinfrapb.WindowerSpec_RANK,
+		execinfrapb.WindowerSpec_DENSE_RANK,
+		execinfrapb.WindowerSpec_PERCENT_RANK,
+		execinfrapb.WindowerSpec_CUME_DIST:
+		return false
+	default:
+		return true
+	}
 }
 
 // windowFnNeedsPeersInfo returns whether a window function pays attention to
@@ -31,8 +59,13 @@ var SupportedWindowFns = map[execinfrapb.WindowerSpec_WindowFunc]struct{}{
 // same partition - from PARTITION BY clause - that are not distinct on the
 // columns in ORDER BY clause). For most window functions, the result of
 // computation should be the same for "peers", so most window functions do need
-// this information.
-func windowFnNeedsPeersInfo(windowFn execinfrapb.WindowerSpec_WindowFunc) bool {
+// this information. The buffered window functions always need it because it is
+// used to determine the window frame.
+func windowFnNeedsPeersInfo(fn execinfrapb.WindowerSpec_Func) bool {
+	if windowFnIsBuffered(fn) {
+		return true
+	}
+	windowFn := *fn.WindowFunc
 	switch windowFn {
 	case execinfrapb.WindowerSpec_ROW_NUMBER:
 		// row_number doesn't pay attention to the concept of "peers."
@@ -0,0 +1,378 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/marusama/semaphore"
+)
+
+// spillingBufferNumCachedDiskBatches is the number of batches read from disk
+// that the spillingBuffer keeps in memory. Window frames usually slide over
+// the buffered tuples, so keeping two batches allows for the frame to span
+// the boundary between two batches without reading from disk at all. Any
+// other batch is read by seeking the disk queue to it, which only reads the
+// region of the file that contains the batch, so the cost of accessing a
+// tuple that isn't cached doesn't depend on its position in the buffer.
+const spillingBufferNumCachedDiskBatches = 2
+
+// spillingBuffer is an append-only buffer of tuples that supports random
+// access to the tuples by their index. The tuples are kept in memory until the
+// allocator reports that more memory than the caller-provided memoryLimit is
+// in use, at which point all new tuples are appended to a rewindable disk
+// queue instead. The tuples on disk are accessed by seeking the disk queue to
+// the batch that contains them.
+//
+// All tuples must be appended before any of them are accessed. Once the
+// caller is done with the buffered tuples, reset can be used to reuse the
+// buffer.
+type spillingBuffer struct {
+	unlimitedAllocator *colmem.Allocator
+	memoryLimit        int64
+	typs               []*types.T
+
+	// inMemBatches contains the batches with the tuples kept in memory. All
+	// batches have the capacity of coldata.BatchSize(), and all of them except
+	// for the last one are full.
+	inMemBatches []coldata.Batch
+	// numInMemBatches is the number of batches in inMemBatches that are
+	// currently in use (the remaining ones are kept around for reuse after
+	// reset).
+	numInMemBatches int
+	numInMemTuples  int
+
+	diskQueueCfg colcontainer.DiskQueueCfg
+	diskQueue    colcontainer.RewindableQueue
+	fdSemaphore  semaphore.Semaphore
+	diskAcc      *mon.BoundAccount
+	// diskAppendScratch accumulates the tuples to be written to disk until it
+	// is full. This ensures that all batches on disk except for the last one
+	// are full, so the index of the batch containing a tuple can be computed.
+	diskAppendScratch coldata.Batch
+	numOnDiskTuples   int
+	// doneAppending is true once the disk queue has been finalized.
+	doneAppending bool
+
+	diskReadState struct {
+		// scratch is the batch that tuples are dequeued from disk into.
+		scratch coldata.Batch
+		// nextBatchIdx is the index of the batch that will be returned by the
+		// next call to Dequeue on the disk queue (without seeking).
+		nextBatchIdx int
+		// cached contains the copies of the most recently read batches from disk
+		// and cachedIdxs contains their indices (-1 if the slot is empty).
+		cached     [spillingBufferNumCachedDiskBatches]coldata.Batch
+		cachedIdxs [spillingBufferNumCachedDiskBatches]int
+		// nextSlot is the slot to be evicted when a new batch is read from
+		// disk.
+		nextSlot int
+	}
+}
+
+// newSpillingBuffer creates a new spillingBuffer. An unlimited allocator must
+// be passed in. The spillingBuffer will use this allocator to check whether
+// memory usage exceeds the given memory limit and use disk if so.
+// If fdSemaphore is nil, no Acquire or Release calls will happen.
+func newSpillingBuffer(
+	unlimitedAllocator *colmem.Allocator,
+	memoryLimit int64,
+	cfg colcontainer.DiskQueueCfg,
+	fdSemaphore semaphore.Semaphore,
+	typs []*types.T,
+	diskAcc *mon.BoundAccount,
+) *spillingBuffer {
+	// Reduce the memory limit by what the DiskQueue and the cached batches may
+	// need.
+	memoryLimit -= int64(cfg.BufferSizeBytes)
+	memoryLimit -= int64((spillingBufferNumCachedDiskBatches + 2) * colmem.EstimateBatchSizeBytes(typs, coldata.BatchSize()))
+	if memoryLimit < 0 {
+		memoryLimit = 0
+	}
+	b := &spillingBuffer{
+		unlimitedAllocator: unlimitedAllocator,
+		memoryLimit:        memoryLimit,
+		typs:               typs,
+		diskQueueCfg:       cfg,
+		fdSemaphore:        fdSemaphore,
+		diskAcc:            diskAcc,
+	}
+	b.resetDiskReadState()
+	return b
+}
+
+// length returns the number of tuples in the buffer.
+func (b *spillingBuffer) length() int {
+	return b.numInMemTuples + b.numOnDiskTuples
+}
+
+// appendTuples appends the tuples in range [startIdx, endIdx) of the batch
+// (with the selection vector applied, if present) to the buffer.
+func (b *spillingBuffer) appendTuples(ctx context.Context, batch coldata.Batch, startIdx, endIdx int) {
+	if b.doneAppending {
+		colexecerror.InternalError(errors.AssertionFailedf("attempted to append to spillingBuffer after accessing it"))
+	}
+	sel := batch.Selection()
+	for startIdx < endIdx {
+		var dest coldata.Batch
+		if b.diskQueue == nil {
+			dest = b.getInMemTailBatch()
+			if dest == nil {
+				if err := b.spillToDisk(ctx); err != nil {
+					colexecerror.InternalError(err)
+				}
+			}
+		}
+		if b.diskQueue != nil {
+			dest = b.diskAppendScratch
+		}
+		destStartIdx := dest.Length()
+		toAppend := dest.Capacity() - destStartIdx
+		if toAppend > endIdx-startIdx {
+			toAppend = endIdx - startIdx
+		}
+		b.unlimitedAllocator.PerformOperation(dest.ColVecs(), func() {
+			for colIdx, vec := range dest.ColVecs() {
+				vec.Copy(
+					coldata.CopySliceArgs{
+						SliceArgs: coldata.SliceArgs{
+							Src:         batch.ColVec(colIdx),
+							Sel:         sel,
+							DestIdx:     destStartIdx,
+							SrcStartIdx: startIdx,
+							SrcEndIdx:   startIdx + toAppend,
+						},
+					},
+				)
+			}
+			dest.SetLength(destStartIdx + toAppend)
+		})
+		startIdx += toAppend
+		if b.diskQueue == nil {
+			b.numInMemTuples += toAppend
+			continue
+		}
+		b.numOnDiskTuples += toAppend
+		if dest.Length() == dest.Capacity() {
+			if err := b.diskQueue.Enqueue(ctx, dest); err != nil {
+				colexecerror.InternalError(err)
+			}
+			dest.ResetInternalBatch()
+			dest.SetLength(0)
+		}
+	}
+}
+
+// getInMemTailBatch returns the in-memory batch that new tuples should be
+// copied into or nil if the buffer must spill to disk because the memory
+// limit has been reached.
+func (b *spillingBuffer) getInMemTailBatch() coldata.Batch {
+	if b.numInMemBatches > 0 {
+		tail := b.inMemBatches[b.numInMemBatches-1]
+		if tail.Length() < tail.Capacity() {
+			return tail
+		}
+	}
+	if b.unlimitedAllocator.Used() > b.memoryLimit {
+		return nil
+	}
+	if b.numInMemBatches < len(b.inMemBatches) {
+		// Reuse the batch that was allocated before the last reset.
+		b.numInMemBatches++
+		return b.inMemBatches[b.numInMemBatches-1]
+	}
+	tail := b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.typs, coldata.BatchSize())
+	b.inMemBatches = append(b.inMemBatches, tail)
+	b.numInMemBatches++
+	return tail
+}
+
+func (b *spillingBuffer) numFDsOpenAtAnyGivenTime() int {
+	if b.diskQueueCfg.CacheMode != colcontainer.DiskQueueCacheModeDefault {
+		// The access pattern must be write-everything then read-everything so
+		// either a read FD or a write FD are open at any one point.
+		return 1
+	}
+	// Otherwise, both will be open.
+	return 2
+}
+
+func (b *spillingBuffer) spillToDisk(ctx context.Context) error {
+	if b.fdSemaphore != nil {
+		if err := b.fdSemaphore.Acquire(ctx, b.numFDsOpenAtAnyGivenTime()); err != nil {
+			return err
+		}
+	}
+	log.VEvent(ctx, 1, "spilled to disk")
+	diskQueue, err := colcontainer.NewRewindableDiskQueue(ctx, b.typs, b.diskQueueCfg, b.diskAcc)
+	if err != nil {
+		return err
+	}
+	// Only assign b.diskQueue if there was no error, otherwise the returned
+	// value may be non-nil but invalid.
+	b.diskQueue = diskQueue
+	if b.diskAppendScratch == nil {
+		b.diskAppendScratch = b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.typs, coldata.BatchSize())
+	}
+	return nil
+}
+
+// finishAppending flushes the tuples that haven't been written to disk yet
+// and finalizes the disk queue so that it can be read from.
+func (b *spillingBuffer) finishAppending(ctx context.Context) error {
+	if b.doneAppending {
+		return nil
+	}
+	b.doneAppending = true
+	if b.diskQueue == nil {
+		return nil
+	}
+	if b.diskAppendScratch.Length() > 0 {
+		if err := b.diskQueue.Enqueue(ctx, b.diskAppendScratch); err != nil {
+			return err
+		}
+	}
+	return b.diskQueue.Enqueue(ctx, coldata.ZeroBatch)
+}
+
+// getTuple returns the batch containing the tuple with the given index as well
+// as the position of the tuple within that batch. The returned batch is only
+// valid until the next call to getTuple.
+func (b *spillingBuffer) getTuple(ctx context.Context, idx int) (coldata.Batch, int, error) {
+	if idx < 0 || idx >= b.length() {
+		return nil, 0, errors.AssertionFailedf(
+			"index %d is out of bounds of spillingBuffer with %d tuples", idx, b.length())
+	}
+	if idx < b.numInMemTuples {
+		return b.inMemBatches[idx/coldata.BatchSize()], idx % coldata.BatchSize(), nil
+	}
+	if err := b.finishAppending(ctx); err != nil {
+		return nil, 0, err
+	}
+	idx -= b.numInMemTuples
+	batchIdx := idx / coldata.BatchSize()
+	batch, err := b.getDiskBatch(ctx, batchIdx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return batch, idx % coldata.BatchSize(), nil
+}
+
+// getDiskBatch returns the batchIdx'th batch written to disk. The batch is
+// read from disk (seeking the disk queue to it if it isn't the next one) only
+// if it isn't among the cached batches.
+func (b *spillingBuffer) getDiskBatch(ctx context.Context, batchIdx int) (coldata.Batch, error) {
+	s := &b.diskReadState
+	for slot, cachedIdx := range s.cachedIdxs {
+		if cachedIdx == batchIdx {
+			return s.cached[slot], nil
+		}
+	}
+	if batchIdx != s.nextBatchIdx {
+		if err := b.diskQueue.Seek(ctx, batchIdx); err != nil {
+			return nil, err
+		}
+		s.nextBatchIdx = batchIdx
+	}
+	if s.scratch == nil {
+		s.scratch = b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.typs, coldata.BatchSize())
+	}
+	ok, err := b.diskQueue.Dequeue(ctx, s.scratch)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || s.scratch.Length() == 0 {
+		return nil, errors.AssertionFailedf("unexpectedly ran out of batches to dequeue in spillingBuffer")
+	}
+	s.nextBatchIdx++
+	// The dequeued batch is only valid until the next call to Dequeue, so we
+	// need to copy it into the cache.
+	slot := s.nextSlot
+	s.nextSlot = (s.nextSlot + 1) % spillingBufferNumCachedDiskBatches
+	if s.cached[slot] == nil {
+		s.cached[slot] = b.unlimitedAllocator.NewMemBatchWithFixedCapacity(b.typs, coldata.BatchSize())
+	}
+	cached := s.cached[slot]
+	cached.ResetInternalBatch()
+	n := s.scratch.Length()
+	b.unlimitedAllocator.PerformOperation(cached.ColVecs(), func() {
+		for colIdx, vec := range cached.ColVecs() {
+			vec.Copy(
+				coldata.CopySliceArgs{
+					SliceArgs: coldata.SliceArgs{
+						Src:       s.scratch.ColVec(colIdx),
+						SrcEndIdx: n,
+					},
+				},
+			)
+		}
+		cached.SetLength(n)
+	})
+	s.cachedIdxs[slot] = batchIdx
+	return cached, nil
+}
+
+func (b *spillingBuffer) resetDiskReadState() {
+	s := &b.diskReadState
+	s.nextBatchIdx = 0
+	s.nextSlot = 0
+	for i := range s.cachedIdxs {
+		s.cachedIdxs[i] = -1
+	}
+}
+
+// reset removes all tuples from the buffer so that it can be reused. The
+// in-memory batches are kept around to be reused.
+func (b *spillingBuffer) reset(ctx context.Context) {
+	for _, batch := range b.inMemBatches[:b.numInMemBatches] {
+		batch.ResetInternalBatch()
+		batch.SetLength(0)
+	}
+	b.numInMemBatches = 0
+	b.numInMemTuples = 0
+	if err := b.closeDiskQueue(ctx); err != nil {
+		colexecerror.InternalError(err)
+	}
+	if b.diskAppendScratch != nil {
+		b.diskAppendScratch.ResetInternalBatch()
+		b.diskAppendScratch.SetLength(0)
+	}
+	b.numOnDiskTuples = 0
+	b.doneAppending = false
+	b.resetDiskReadState()
+}
+
+func (b *spillingBuffer) closeDiskQueue(ctx context.Context) error {
+	if b.diskQueue == nil {
+		return nil
+	}
+	if err := b.diskQueue.Close(ctx); err != nil {
+		return err
+	}
+	if b.fdSemaphore != nil {
+		b.fdSemaphore.Release(b.numFDsOpenAtAnyGivenTime())
+	}
+	b.diskQueue = nil
+	return nil
+}
+
+// close releases the resources held by the buffer.
+func (b *spillingBuffer) close(ctx context.Context) error {
+	return b.closeDiskQueue(ctx)
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/col/coldatatestutils"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpillingBuffer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(ctx)
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+	queueCfg.CacheMode = colcontainer.DiskQueueCacheModeReuseCache
+	queueCfg.SetDefaultBufferSizeBytesForCacheMode()
+
+	rng, _ := randutil.NewPseudoRand()
+	for _, memoryLimit := range []int64{1, 1<<20 + int64(rng.Intn(64<<20)) /* 1 MiB up to 64 MiB */} {
+		numBatches := 1 + rng.Intn(16)
+		log.Infof(ctx, "MemoryLimit=%s/NumBatches=%d", humanizeutil.IBytes(memoryLimit), numBatches)
+		// Create random input and remember all of its tuples.
+		var expected tuples
+		op := coldatatestutils.NewRandomDataOp(testAllocator, rng, coldatatestutils.RandomDataOpArgs{
+			NumBatches: numBatches,
+			BatchSize:  1 + rng.Intn(coldata.BatchSize()),
+			Selection:  true,
+			Nulls:      true,
+		})
+		typs := op.Typs()
+
+		memAcc := testMemMonitor.MakeBoundAccount()
+		buf := newSpillingBuffer(
+			colmem.NewAllocator(ctx, &memAcc, testColumnFactory), memoryLimit, queueCfg,
+			colexecbase.NewTestingSemaphore(1), typs, testDiskAcc,
+		)
+		for {
+			b := op.Next(ctx)
+			if b.Length() == 0 {
+				break
+			}
+			for i := 0; i < b.Length(); i++ {
+				expected = append(expected, getTupleFromBatch(b, i))
+			}
+			// Append the batch in two chunks to exercise partial appends.
+			splitIdx := rng.Intn(b.Length() + 1)
+			buf.appendTuples(ctx, b, 0, splitIdx)
+			buf.appendTuples(ctx, b, splitIdx, b.Length())
+		}
+		require.Equal(t, len(expected), buf.length())
+
+		checkTuple := func(idx int) {
+			b, rowIdx, err := buf.getTuple(ctx, idx)
+			require.NoError(t, err)
+			actual := getTupleFromBatch(b, rowIdx)
+			if !tupleEquals(expected[idx], actual, &evalCtx) {
+				t.Fatalf("tuple %d: expected %s, found %s", idx, expected[idx], actual)
+			}
+		}
+		// Read all tuples sequentially, then in random order, which requires
+		// seeking the disk queue if the buffer spilled, and then backwards.
+		for i := range expected {
+			checkTuple(i)
+		}
+		for i := 0; i < len(expected); i++ {
+			checkTuple(rng.Intn(len(expected)))
+		}
+		for i := len(expected) - 1; i >= 0; i-- {
+			checkTuple(i)
+		}
+
+		// The buffer must be reusable after a reset.
+		buf.reset(ctx)
+		require.Equal(t, 0, buf.length())
+
+		require.NoError(t, buf.close(ctx))
+		memAcc.Close(ctx)
+
+		// Verify no directories are left over.
+		directories, err := queueCfg.FS.List(queueCfg.Path)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(directories))
+	}
+}
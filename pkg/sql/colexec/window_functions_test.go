@@ -57,7 +57,8 @@ func TestWindowFunctions(t *testing.T) {
 		},
 	}
 	// All supported window function operators will use from 0 to 3 disk queues
-	// with each using a single FD at any point in time. Additionally, the
+	// (the buffered window operator uses a single one) with each using a single
+	// FD at any point in time. Additionally, the
 	// disk-backed sorter (that will be planned depending on PARTITION BY and
 	// ORDER BY combinations) will be limited to this number using a testing
 	// knob, so 3 is necessary and sufficient.
@@ -70,6 +71,12 @@ func TestWindowFunctions(t *testing.T) {
 	denseRankFn := execinfrapb.WindowerSpec_DENSE_RANK
 	percentRankFn := execinfrapb.WindowerSpec_PERCENT_RANK
 	cumeDistFn := execinfrapb.WindowerSpec_CUME_DIST
+	ntileFn := execinfrapb.WindowerSpec_NTILE
+	lagFn := execinfrapb.WindowerSpec_LAG
+	leadFn := execinfrapb.WindowerSpec_LEAD
+	nthValueFn := execinfrapb.WindowerSpec_NTH_VALUE
+	countFn := execinfrapb.AggregatorSpec_COUNT
+	maxFn := execinfrapb.AggregatorSpec_MAX
 	accounts := make([]*mon.BoundAccount, 0)
 	monitors := make([]*mon.BytesMonitor, 0)
 	for _, spillForced := range []bool{false, true} {
@@ -280,6 +287,150 @@ func TestWindowFunctions(t *testing.T) {
 					},
 				},
 			},
+			// Buffered window functions.
+			{
+				tuples:   tuples{{1, 3}, {2, 2}, {1, 1}, {1, 2}, {2, 1}, {nil, 1}},
+				expected: tuples{{nil, 1, nil}, {1, 1, nil}, {1, 2, 1}, {1, 3, 2}, {2, 1, nil}, {2, 2, 1}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &lagFn},
+							ArgsIdxs:     []uint32{1},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{1, 2}, {1, nil}, {1, 2}, {1, 3}, {2, 1}, {nil, nil}},
+				expected: tuples{{nil, nil, nil}, {1, nil, nil}, {1, 2, 2}, {1, 2, 2}, {1, 3, 3}, {2, 1, 1}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{AggregateFunc: &maxFn},
+							ArgsIdxs:     []uint32{1},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{1, 4}, {1, 1}, {1, 3}, {1, 2}, {2, 1}, {2, 2}},
+				expected: tuples{{1, 1, 1}, {1, 2, 2}, {1, 3, 2}, {1, 4, 2}, {2, 1, 1}, {2, 2, 2}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &countFn},
+							ArgsIdxs: []uint32{1},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							Frame: &execinfrapb.WindowerSpec_Frame{
+								Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+								Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+									Start: execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+										IntOffset: 1,
+									},
+									End: &execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_CURRENT_ROW,
+									},
+								},
+							},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{1, 3, 2}, {1, 1, 2}, {2, 1, 2}, {1, 2, 2}},
+				expected: tuples{{1, 1, 2, 1}, {1, 2, 2, 1}, {1, 3, 2, 2}, {2, 1, 2, 1}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &ntileFn},
+							ArgsIdxs:     []uint32{2},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 3,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{1, 1, 2, -1}, {1, 2, 2, -1}, {1, 3, nil, -1}, {2, 1, 1, -1}},
+				expected: tuples{{1, 1, 2, -1, 3}, {1, 2, 2, -1, -1}, {1, 3, nil, -1, nil}, {2, 1, 1, -1, -1}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &leadFn},
+							ArgsIdxs:     []uint32{1, 2, 3},
+							Ordering:     execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							OutputColIdx: 4,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{1, 2, 2}, {1, 1, 2}, {1, 3, 2}},
+				expected: tuples{{1, 1, 2, 3}, {1, 2, 2, 3}, {1, 3, 2, 2}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{WindowFunc: &nthValueFn},
+							ArgsIdxs: []uint32{1, 2},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							Frame: &execinfrapb.WindowerSpec_Frame{
+								Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+								Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+									Start: execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_UNBOUNDED_PRECEDING,
+									},
+									End: &execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_UNBOUNDED_FOLLOWING,
+									},
+								},
+								Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_CURRENT_ROW,
+							},
+							OutputColIdx: 3,
+						},
+					},
+				},
+			},
+			{
+				tuples:   tuples{{1, 1}, {1, 3}, {1, 2}, {2, 5}},
+				expected: tuples{{1, 1, 2}, {1, 2, 3}, {1, 3, 2}, {2, 5, nil}},
+				windowerSpec: execinfrapb.WindowerSpec{
+					PartitionBy: []uint32{0},
+					WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+						{
+							Func:     execinfrapb.WindowerSpec_Func{AggregateFunc: &maxFn},
+							ArgsIdxs: []uint32{1},
+							Ordering: execinfrapb.Ordering{Columns: []execinfrapb.Ordering_Column{{ColIdx: 1}}},
+							Frame: &execinfrapb.WindowerSpec_Frame{
+								Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+								Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+									Start: execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING,
+										IntOffset: 1,
+									},
+									End: &execinfrapb.WindowerSpec_Frame_Bound{
+										BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING,
+										IntOffset: 1,
+									},
+								},
+								Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_CURRENT_ROW,
+							},
+							OutputColIdx: 2,
+						},
+					},
+				},
+			},
 		} {
 			log.Infof(ctx, "spillForced=%t/%s", spillForced, tc.windowerSpec.WindowFns[0].Func.String())
 			var semsToCheck []semaphore.Semaphore
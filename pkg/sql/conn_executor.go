@@ -20,6 +20,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -38,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
@@ -53,6 +55,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
+	opentracing "github.com/opentracing/opentracing-go"
 	"golang.org/x/net/trace"
 )
 
@@ -630,6 +633,7 @@ func (s *Server) newConnExecutor(
 		s.cfg.LeaseManager, s.cfg.Settings, sd, s.cfg.HydratedTables)
 	ex.extraTxnState.txnRewindPos = -1
 	ex.extraTxnState.schemaChangeJobsCache = make(map[descpb.ID]*jobs.Job)
+	ex.extraTxnState.sqlCursors = make(map[string]*sqlCursor)
 	ex.mu.ActiveQueries = make(map[ClusterWideID]*queryMeta)
 	ex.machine = fsm.MakeMachine(TxnStateTransitions, stateNoTxn{}, &ex.state)
 
@@ -689,6 +693,7 @@ func (s *Server) newConnExecutorWithTxn(
 		txn.ReadTimestamp().GoTime(),
 		nil, /* historicalTimestamp */
 		roachpb.UnspecifiedUserPriority,
+		enginepb.Serializable, /* ignored when txn is not nil */
 		tree.ReadWrite,
 		txn,
 		ex.transitionCtx)
@@ -854,6 +859,7 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 			ctx, prepStmtNamespace{}, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 		)
 		ex.extraTxnState.prepStmtsNamespaceMemAcc.Close(ctx)
+		ex.closeCursors(ctx, func(*sqlCursor) bool { return true })
 	}
 
 	if ex.sessionTracing.Enabled() {
@@ -1000,6 +1006,11 @@ type connExecutor struct {
 		// connExecutor's closure.
 		prepStmtsNamespaceMemAcc mon.BoundAccount
 
+		// sqlCursors contains the cursors declared with DECLARE. Cursors declared
+		// WITH HOLD outlive the transaction that created them, like prepared
+		// statements.
+		sqlCursors map[string]*sqlCursor
+
 		// onTxnFinish (if non-nil) will be called when txn is finished (either
 		// committed or aborted). It is set when txn is started but can remain
 		// unset when txn is executed within another higher-level txn.
@@ -1229,6 +1240,19 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 	}
 
+	// Close the cursors that don't outlive the transaction. Holdable cursors
+	// declared in a transaction survive only if it commits. Note that rolling
+	// back to a savepoint does not close the cursors declared after it.
+	switch ev {
+	case txnCommit:
+		ex.closeCursors(ctx, func(c *sqlCursor) bool { return !c.hold })
+		for _, c := range ex.extraTxnState.sqlCursors {
+			c.createdInTxn = false
+		}
+	case txnRollback, txnRestart:
+		ex.closeCursors(ctx, func(c *sqlCursor) bool { return c.createdInTxn })
+	}
+
 	switch ev {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.savepoints.clear()
@@ -1375,6 +1399,47 @@ func (ex *connExecutor) run(
 	}
 }
 
+// clientTraceParent returns the span context propagated by the client for the
+// statement executed by cmd, if any. A traceparent passed in a statement
+// comment takes precedence over the traceparent session variable. Invalid
+// traceparent comments are ignored.
+//
+// Returns nil if the session is being traced: session tracing collects the
+// statements' spans in the session's own recording.
+func (ex *connExecutor) clientTraceParent(ctx context.Context, cmd Command) opentracing.SpanContext {
+	var traceParent string
+	switch tcmd := cmd.(type) {
+	case ExecStmt:
+		traceParent = tcmd.TraceParent
+	case ExecPortal:
+		portal, ok := ex.extraTxnState.prepStmtsNamespace.portals[tcmd.Name]
+		if !ok {
+			return nil
+		}
+		traceParent = portal.Stmt.TraceParent
+	default:
+		return nil
+	}
+	if traceParent == "" {
+		traceParent = ex.sessionData.TraceParent
+	}
+	if traceParent == "" {
+		return nil
+	}
+	if sp := opentracing.SpanFromContext(ctx); sp != nil && tracing.IsRecording(sp) {
+		return nil
+	}
+	tr, ok := ex.server.cfg.AmbientCtx.Tracer.(*tracing.Tracer)
+	if !ok {
+		return nil
+	}
+	remoteParent, err := tr.ExtractTraceParent(traceParent)
+	if err != nil {
+		return nil
+	}
+	return remoteParent
+}
+
 // errDrainingComplete is returned by execCmd when the connExecutor previously got
 // a DrainRequest and the time is ripe to finish this session (i.e. we're no
 // longer in a transaction).
@@ -1395,11 +1460,20 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		return err // err could be io.EOF
 	}
 
-	ctx, sp := tracing.EnsureChildSpan(
-		ctx, ex.server.cfg.AmbientCtx.Tracer,
-		// We print the type of command, not the String() which includes long
-		// statements.
-		cmd.command())
+	var sp opentracing.Span
+	if remoteParent := ex.clientTraceParent(ctx, cmd); remoteParent != nil {
+		// The client propagated its trace context; make the statement part of
+		// the client's trace.
+		sp = ex.server.cfg.AmbientCtx.Tracer.StartSpan(
+			cmd.command(), opentracing.ChildOf(remoteParent), tracing.LogTagsFromCtx(ctx))
+		ctx = opentracing.ContextWithSpan(ctx, sp)
+	} else {
+		ctx, sp = tracing.EnsureChildSpan(
+			ctx, ex.server.cfg.AmbientCtx.Tracer,
+			// We print the type of command, not the String() which includes long
+			// statements.
+			cmd.command())
+	}
 	defer sp.Finish()
 
 	if log.ExpensiveLogEnabled(ctx, 2) || ex.eventLog != nil {
@@ -1563,6 +1637,10 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+	case CopyOut:
+		copyRes := ex.clientComm.CreateCopyOutResult(pos, ex.sessionData.DataConversion)
+		res = copyRes
+		ev, payload = ex.execCopyOut(ctx, tcmd, copyRes)
 	case DrainRequest:
 		// We received a drain request. We terminate immediately if we're not in a
 		// transaction. If we are in a transaction, we'll finish as soon as a Sync
@@ -1751,6 +1829,8 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case CopyIn:
 				// Can't advance.
+			case CopyOut:
+				// Can't advance.
 			case DrainRequest:
 				canAdvance = true
 			case Flush:
@@ -1838,48 +1918,9 @@ func (ex *connExecutor) execCopyIn(
 		return ev, payload, nil
 	}
 
-	// If we're in an explicit txn, then the copying will be done within that
-	// txn. Otherwise, we tell the copyMachine to manage its own transactions
-	// and give it a closure to reset the accumulated extraTxnState.
-	var txnOpt copyTxnOpt
-	if isOpen {
-		txnOpt = copyTxnOpt{
-			txn:           ex.state.mu.txn,
-			txnTimestamp:  ex.state.sqlTimestamp,
-			stmtTimestamp: ex.server.cfg.Clock.PhysicalTime(),
-		}
-	} else {
-		txnOpt = copyTxnOpt{
-			resetExtraTxnState: func(ctx context.Context) error {
-				return ex.resetExtraTxnState(ctx, noEvent)
-			},
-		}
-	}
+	txnOpt, cleanup := ex.makeCopyTxnOpt(ctx, isOpen)
+	defer cleanup(ctx)
 
-	var monToStop *mon.BytesMonitor
-	defer func() {
-		if monToStop != nil {
-			monToStop.Stop(ctx)
-		}
-	}()
-	if isNoTxn {
-		// HACK: We're reaching inside ex.state and starting the monitor. Normally
-		// that's driven by the state machine, but we're bypassing the state machine
-		// here.
-		ex.state.mon.Start(ctx, ex.sessionMon, mon.BoundAccount{} /* reserved */)
-		monToStop = ex.state.mon
-	}
-	txnOpt.resetPlanner = func(ctx context.Context, p *planner, txn *kv.Txn, txnTS time.Time, stmtTS time.Time) {
-		// HACK: We're reaching inside ex.state and changing sqlTimestamp by hand.
-		// It is used by resetPlanner. Normally sqlTimestamp is updated by the
-		// state machine, but the copyMachine manages its own transactions without
-		// going through the state machine.
-		ex.state.sqlTimestamp = txnTS
-		ex.statsCollector = ex.newStatsCollector()
-		ex.statsCollector.reset(&ex.server.sqlStats, ex.appStats, &ex.phaseTimes)
-		ex.initPlanner(ctx, p)
-		ex.resetPlanner(ctx, p, txn, stmtTS)
-	}
 	var cm copyMachineInterface
 	var err error
 	if isCopyToExternalStorage(cmd) {
@@ -1914,6 +1955,92 @@ func (ex *connExecutor) execCopyIn(
 	return nil, nil, nil
 }
 
+// execCopyOut handles the CopyTo statement by creating a copyOutMachine which
+// streams the rows being copied to the client through res.
+func (ex *connExecutor) execCopyOut(
+	ctx context.Context, cmd CopyOut, res CopyOutResult,
+) (fsm.Event, fsm.EventPayload) {
+	state := ex.machine.CurState()
+	_, isNoTxn := state.(stateNoTxn)
+	_, isOpen := state.(stateOpen)
+	if !isNoTxn && !isOpen {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{
+			err: sqlerrors.NewTransactionAbortedError("" /* customMsg */)}
+		return ev, payload
+	}
+
+	txnOpt, cleanup := ex.makeCopyTxnOpt(ctx, isOpen)
+	defer cleanup(ctx)
+
+	cm := newCopyOutMachine(
+		cmd.Stmt, res, txnOpt, ex.server.cfg,
+		// execPlan
+		func(ctx context.Context, p *planner, res RestrictedCommandResult) error {
+			_, err := ex.execWithDistSQLEngine(ctx, p, tree.Rows, res, false /* distribute */, nil /* progressAtomic */)
+			return err
+		},
+	)
+	if err := cm.run(ctx); err != nil {
+		// As for COPY FROM, we don't have a retriable error story for the copy
+		// machine; see execCopyIn.
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload
+	}
+	return nil, nil
+}
+
+// makeCopyTxnOpt returns the copyTxnOpt for a COPY statement. If we're in an
+// explicit txn, then the copying will be done within that txn. Otherwise, we
+// tell the copy machine to manage its own transactions and give it a closure
+// to reset the accumulated extraTxnState.
+//
+// The returned function needs to be called once the copying is done.
+func (ex *connExecutor) makeCopyTxnOpt(
+	ctx context.Context, isOpen bool,
+) (copyTxnOpt, func(context.Context)) {
+	var txnOpt copyTxnOpt
+	if isOpen {
+		txnOpt = copyTxnOpt{
+			txn:           ex.state.mu.txn,
+			txnTimestamp:  ex.state.sqlTimestamp,
+			stmtTimestamp: ex.server.cfg.Clock.PhysicalTime(),
+		}
+	} else {
+		txnOpt = copyTxnOpt{
+			resetExtraTxnState: func(ctx context.Context) error {
+				return ex.resetExtraTxnState(ctx, noEvent)
+			},
+		}
+	}
+
+	var monToStop *mon.BytesMonitor
+	if !isOpen {
+		// HACK: We're reaching inside ex.state and starting the monitor. Normally
+		// that's driven by the state machine, but we're bypassing the state machine
+		// here.
+		ex.state.mon.Start(ctx, ex.sessionMon, mon.BoundAccount{} /* reserved */)
+		monToStop = ex.state.mon
+	}
+	txnOpt.resetPlanner = func(ctx context.Context, p *planner, txn *kv.Txn, txnTS time.Time, stmtTS time.Time) {
+		// HACK: We're reaching inside ex.state and changing sqlTimestamp by hand.
+		// It is used by resetPlanner. Normally sqlTimestamp is updated by the
+		// state machine, but the copy machine manages its own transactions without
+		// going through the state machine.
+		ex.state.sqlTimestamp = txnTS
+		ex.statsCollector = ex.newStatsCollector()
+		ex.statsCollector.reset(&ex.server.sqlStats, ex.appStats, &ex.phaseTimes)
+		ex.initPlanner(ctx, p)
+		ex.resetPlanner(ctx, p, txn, stmtTS)
+	}
+	return txnOpt, func(ctx context.Context) {
+		if monToStop != nil {
+			monToStop.Stop(ctx)
+		}
+	}
+}
+
 // stmtHasNoData returns true if describing a result of the input statement
 // type should return NoData.
 func stmtHasNoData(stmt tree.Statement) bool {
@@ -1999,7 +2126,7 @@ func (ex *connExecutor) makeErrEvent(err error, stmt tree.Statement) (fsm.Event,
 
 // setTransactionModes implements the txnModesSetter interface.
 func (ex *connExecutor) setTransactionModes(
-	modes tree.TransactionModes, asOfTs hlc.Timestamp,
+	ctx context.Context, modes tree.TransactionModes, asOfTs hlc.Timestamp,
 ) error {
 	// This method cheats and manipulates ex.state directly, not through an event.
 	// The alternative would be to create a special event, but it's unclear how
@@ -2013,9 +2140,14 @@ func (ex *connExecutor) setTransactionModes(
 			return err
 		}
 	}
-	if modes.Isolation != tree.UnspecifiedIsolation && modes.Isolation != tree.SerializableIsolation {
-		return errors.AssertionFailedf(
-			"unknown isolation level: %s", errors.Safe(modes.Isolation))
+	if modes.Isolation != tree.UnspecifiedIsolation {
+		level, err := ex.txnIsolationLevelToKV(ctx, modes.Isolation)
+		if err != nil {
+			return err
+		}
+		if err := ex.state.setIsolationLevel(level); err != nil {
+			return err
+		}
 	}
 	rwMode := modes.ReadWriteMode
 	if modes.AsOf.Expr != nil && (asOfTs == hlc.Timestamp{}) {
@@ -2055,6 +2187,41 @@ func (ex *connExecutor) txnPriorityWithSessionDefault(mode tree.UserPriority) ro
 	return txnPriorityToProto(mode)
 }
 
+// txnIsolationLevelToKV maps a SQL isolation level to the one the KV
+// transaction runs under. READ COMMITTED is upgraded to SERIALIZABLE unless it
+// has been enabled through the cluster setting and the cluster version
+// supports it. Transactions of internal executors always run under
+// SERIALIZABLE isolation.
+func (ex *connExecutor) txnIsolationLevelToKV(
+	ctx context.Context, level tree.IsolationLevel,
+) (enginepb.IsolationLevel, error) {
+	if ex.executorType == executorTypeInternal {
+		return enginepb.Serializable, nil
+	}
+	switch level {
+	case tree.UnspecifiedIsolation, tree.SerializableIsolation:
+		return enginepb.Serializable, nil
+	case tree.ReadCommittedIsolation:
+		st := ex.server.cfg.Settings
+		if readCommittedIsolationEnabled.Get(&st.SV) &&
+			st.Version.IsActive(ctx, clusterversion.VersionReadCommitted) {
+			return enginepb.ReadCommitted, nil
+		}
+		return enginepb.Serializable, nil
+	default:
+		return 0, errors.AssertionFailedf("unknown isolation level: %s", level)
+	}
+}
+
+func (ex *connExecutor) txnIsolationLevelWithSessionDefault(
+	ctx context.Context, level tree.IsolationLevel,
+) (enginepb.IsolationLevel, error) {
+	if level == tree.UnspecifiedIsolation {
+		level = tree.IsolationLevel(ex.sessionData.DefaultTxnIsolationLevel)
+	}
+	return ex.txnIsolationLevelToKV(ctx, level)
+}
+
 func (ex *connExecutor) readWriteModeWithSessionDefault(
 	mode tree.ReadWriteMode,
 ) tree.ReadWriteMode {
@@ -2169,6 +2336,7 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sessionDataMutator = ex.dataMutator
 	p.noticeSender = nil
 	p.preparedStatements = ex.getPrepStmtsAccessor()
+	p.sqlCursors = connExCursorsAccessor{ex: ex}
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
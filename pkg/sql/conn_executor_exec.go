@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
@@ -61,7 +62,8 @@ import (
 // stmt: The statement to execute.
 // res: Used to produce query results.
 // pinfo: The values to use for the statement's placeholders. If nil is passed,
-// 	 then the statement cannot have any placeholder.
+//
+//	then the statement cannot have any placeholder.
 func (ex *connExecutor) execStmt(
 	ctx context.Context, stmt Statement, res RestrictedCommandResult, pinfo *tree.PlaceholderInfo,
 ) (fsm.Event, fsm.EventPayload, error) {
@@ -438,6 +440,10 @@ func (ex *connExecutor) execStmtInOpenState(
 		}
 	}()
 
+	// cursor is set when executing a DECLARE statement; the cursor's query is
+	// started instead of executing the statement.
+	var cursor *sqlCursor
+
 	switch s := stmt.AST.(type) {
 	case *tree.BeginTransaction:
 		// BEGIN is always an error when in the Open state. It's legitimate only in
@@ -539,6 +545,20 @@ func (ex *connExecutor) execStmtInOpenState(
 		if s.DiscardRows {
 			p.discardRows = true
 		}
+
+	case *tree.DeclareCursor:
+		// The cursor's query is started below, once the placeholders are
+		// assigned.
+		var err error
+		cursor, err = ex.newSQLCursor(ctx, s, stmt.SQL, os.ImplicitTxn.Get())
+		if err != nil {
+			return makeErrEvent(err)
+		}
+		defer func() {
+			if cursor != nil {
+				cursor.close(ctx)
+			}
+		}()
 	}
 
 	p.semaCtx.Annotations = tree.MakeAnnotations(stmt.NumAnnotations)
@@ -547,32 +567,46 @@ func (ex *connExecutor) execStmtInOpenState(
 	// don't return any event unless an error happens.
 
 	if os.ImplicitTxn.Get() {
-		asOfTs, err := p.isAsOf(ctx, stmt.AST)
+		asOf, err := p.isAsOf(ctx, stmt.AST)
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if asOfTs != nil {
-			p.semaCtx.AsOfTimestamp = asOfTs
+		if asOf != nil {
+			asOfTs := asOf.Timestamp
+			if asOf.BoundedStaleness {
+				var routingPolicy roachpb.RoutingPolicy
+				asOfTs, routingPolicy, err = p.negotiateBoundedStaleness(ctx, stmt.AST, asOf.Timestamp)
+				if err != nil {
+					return makeErrEvent(err)
+				}
+				p.semaCtx.AsOfBoundedStaleness = asOf
+				ex.state.setRoutingPolicy(routingPolicy)
+			}
+			p.semaCtx.AsOfTimestamp = &asOfTs
 			p.extendedEvalCtx.SetTxnTimestamp(asOfTs.GoTime())
-			ex.state.setHistoricalTimestamp(ctx, *asOfTs)
+			ex.state.setHistoricalTimestamp(ctx, asOfTs)
 		}
 	} else {
 		// If we're in an explicit txn, we allow AOST but only if it matches with
 		// the transaction's timestamp. This is useful for running AOST statements
 		// using the InternalExecutor inside an external transaction; one might want
 		// to do that to force p.avoidCachedDescriptors to be set below.
-		ts, err := p.isAsOf(ctx, stmt.AST)
+		asOf, err := p.isAsOf(ctx, stmt.AST)
 		if err != nil {
 			return makeErrEvent(err)
 		}
-		if ts != nil {
-			if readTs := ex.state.getReadTimestamp(); *ts != readTs {
+		if asOf != nil {
+			if asOf.BoundedStaleness {
+				return makeErrEvent(pgerror.New(pgcode.FeatureNotSupported,
+					"AS OF SYSTEM TIME: bounded staleness reads cannot be used in explicit transactions"))
+			}
+			if readTs := ex.state.getReadTimestamp(); asOf.Timestamp != readTs {
 				err = pgerror.Newf(pgcode.Syntax,
 					"inconsistent AS OF SYSTEM TIME timestamp; expected: %s", readTs)
 				err = errors.WithHint(err, "try SET TRANSACTION AS OF SYSTEM TIME")
 				return makeErrEvent(err)
 			}
-			p.semaCtx.AsOfTimestamp = ts
+			p.semaCtx.AsOfTimestamp = &asOf.Timestamp
 		}
 	}
 
@@ -628,6 +662,21 @@ func (ex *connExecutor) execStmtInOpenState(
 		return makeErrEvent(err)
 	}
 
+	// Under isolation levels weaker than SERIALIZABLE, each statement also
+	// reads from its own snapshot, taken when the statement begins to run.
+	// Leased descriptors are released so that they are acquired anew at the
+	// new read timestamp. Internal executors running within such a transaction
+	// leave its snapshot alone, as they run in the middle of a statement.
+	stmtRetries := false
+	if ex.state.isoLevel.PerStatementReadSnapshot() && !ex.state.isHistorical &&
+		ex.executorType == executorTypeExec {
+		ex.extraTxnState.descCollection.ReleaseLeases(ctx)
+		if err := ex.state.mu.txn.StepReadTimestamp(ctx); err != nil {
+			return makeErrEvent(err)
+		}
+		stmtRetries = !os.ImplicitTxn.Get()
+	}
+
 	if err := p.semaCtx.Placeholders.Assign(pinfo, stmt.NumPlaceholders); err != nil {
 		return makeErrEvent(err)
 	}
@@ -636,7 +685,19 @@ func (ex *connExecutor) execStmtInOpenState(
 	p.stmt = &stmt
 	p.cancelChecker = cancelchecker.NewCancelChecker(ctx)
 	p.autoCommit = os.ImplicitTxn.Get() && !ex.server.cfg.TestingKnobs.DisableAutoCommit
-	if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
+	if cursor != nil {
+		if err := ex.startSQLCursor(ctx, cursor, stmt.AST.(*tree.DeclareCursor), p); err != nil {
+			return makeErrEvent(err)
+		}
+		ex.extraTxnState.sqlCursors[cursor.name] = cursor
+		cursor = nil
+		return nil, nil, nil
+	}
+	if stmtRetries {
+		if err := ex.dispatchStmtWithRetriesToExecutionEngine(ctx, p, res); err != nil {
+			return nil, nil, err
+		}
+	} else if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
 		return nil, nil, err
 	}
 	if err := res.Err(); err != nil {
@@ -688,7 +749,8 @@ func (ex *connExecutor) checkDescriptorTwoVersionInvariant(ctx context.Context)
 		// Create a new transaction to retry with a higher timestamp than the
 		// timestamps used in the retry loop above.
 		userPriority := ex.state.mu.txn.UserPriority()
-		ex.state.mu.txn = kv.NewTxnWithSteppingEnabled(ctx, ex.transitionCtx.db, ex.transitionCtx.nodeIDOrZero)
+		ex.state.mu.txn = kv.NewTxnWithAdmissionControl(ctx, ex.transitionCtx.db,
+			ex.transitionCtx.nodeIDOrZero, roachpb.AdmissionHeader_FROM_SQL, admission.NormalPri)
 		if err := ex.state.mu.txn.SetUserPriority(userPriority); err != nil {
 			return err
 		}
@@ -715,6 +777,10 @@ func (ex *connExecutor) commitSQLTransaction(
 func (ex *connExecutor) commitSQLTransactionInternal(
 	ctx context.Context, stmt tree.Statement,
 ) error {
+	if err := ex.prepareCursorsForCommit(ctx); err != nil {
+		return err
+	}
+
 	if err := validatePrimaryKeys(&ex.extraTxnState.descCollection); err != nil {
 		return err
 	}
@@ -1080,9 +1146,14 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		if err != nil {
 			return ex.makeErrEvent(err, s)
 		}
+		isoLevel, err := ex.txnIsolationLevelWithSessionDefault(ctx, s.Modes.Isolation)
+		if err != nil {
+			return ex.makeErrEvent(err, s)
+		}
 		return eventTxnStart{ImplicitTxn: fsm.False},
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(s.Modes.UserPriority),
+				isoLevel,
 				mode,
 				sqlTs,
 				historicalTs,
@@ -1094,9 +1165,14 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		// NB: Implicit transactions are created without a historical timestamp even
 		// though the statement might contain an AOST clause. In these cases the
 		// clause is evaluated and applied execStmtInOpenState.
+		isoLevel, err := ex.txnIsolationLevelWithSessionDefault(ctx, tree.UnspecifiedIsolation)
+		if err != nil {
+			return ex.makeErrEvent(err, stmt.AST)
+		}
 		return eventTxnStart{ImplicitTxn: fsm.True},
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(tree.UnspecifiedUserPriority),
+				isoLevel,
 				ex.readWriteModeWithSessionDefault(tree.UnspecifiedReadWriteMode),
 				ex.server.cfg.Clock.PhysicalTime(),
 				nil, /* historicalTimestamp */
@@ -1106,9 +1182,9 @@ func (ex *connExecutor) execStmtInNoTxnState(
 
 // execStmtInAbortedState executes a statement in a txn that's in state
 // Aborted or RestartWait. All statements result in error events except:
-// - COMMIT / ROLLBACK: aborts the current transaction.
-// - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
-//   allowing it to be retried.
+//   - COMMIT / ROLLBACK: aborts the current transaction.
+//   - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
+//     allowing it to be retried.
 func (ex *connExecutor) execStmtInAbortedState(
 	ctx context.Context, stmt Statement, res RestrictedCommandResult,
 ) (_ fsm.Event, payload fsm.EventPayload) {
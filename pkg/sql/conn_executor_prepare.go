@@ -96,6 +96,7 @@ func (ex *connExecutor) execPrepare(
 	// Remember the inferred placeholder types so they can be reported on
 	// Describe.
 	ps.InferredTypes = inferredTypes
+	ps.TraceParent = parseCmd.TraceParent
 	return nil, nil
 }
 
@@ -232,13 +233,18 @@ func (ex *connExecutor) populatePrepared(
 	}
 	p.extendedEvalCtx.PrepareOnly = true
 
-	protoTS, err := p.isAsOf(ctx, stmt.AST)
+	asOf, err := p.isAsOf(ctx, stmt.AST)
 	if err != nil {
 		return 0, err
 	}
-	if protoTS != nil {
-		p.semaCtx.AsOfTimestamp = protoTS
-		txn.SetFixedTimestamp(ctx, *protoTS)
+	if asOf != nil {
+		// The timestamp of a bounded staleness read is only negotiated when it
+		// is executed, so prepare it at its minimum timestamp bound.
+		if asOf.BoundedStaleness {
+			p.semaCtx.AsOfBoundedStaleness = asOf
+		}
+		p.semaCtx.AsOfTimestamp = &asOf.Timestamp
+		txn.SetFixedTimestamp(ctx, asOf.Timestamp)
 	}
 
 	// PREPARE has a limited subset of statements it can be run with. Postgres
@@ -0,0 +1,139 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// maxStmtRetries is the number of times a statement of a READ COMMITTED
+// transaction is retried after a retryable error before the error is
+// returned, at which point the whole transaction is retried if possible.
+const maxStmtRetries = 10
+
+// dispatchStmtWithRetriesToExecutionEngine executes a statement of a
+// transaction whose statements each read from their own snapshot. Unlike
+// under SERIALIZABLE isolation, a retryable error hit by such a statement
+// (typically a write-write conflict, including one hit by a locking read like
+// SELECT FOR UPDATE) does not invalidate the reads of the earlier statements.
+// The statement is instead rolled back and retried on a new read snapshot, as
+// long as none of its results have been passed on to the client.
+//
+// Like dispatchToExecutionEngine, query execution errors are written to res.
+func (ex *connExecutor) dispatchStmtWithRetriesToExecutionEngine(
+	ctx context.Context, planner *planner, res RestrictedCommandResult,
+) error {
+	txn := ex.state.mu.txn
+	txnID, epoch := txn.ID(), txn.Epoch()
+	txn.SetStatementRetriesEnabled(ctx, true)
+	// If the last attempt failed with a retryable error, disabling statement
+	// retries performs the transaction restart which the error would have
+	// caused without them.
+	defer txn.SetStatementRetriesEnabled(ctx, false)
+
+	for attempt := 1; ; attempt++ {
+		sp, err := txn.CreateSavepoint(ctx)
+		if err != nil {
+			res.SetError(err)
+			return nil
+		}
+		stmtRes := &stmtRetryResult{RestrictedCommandResult: res}
+		if err := ex.dispatchToExecutionEngine(ctx, planner, stmtRes); err != nil {
+			return err
+		}
+		retryErr := stmtRes.err
+		if retryErr == nil || !errIsRetriable(retryErr) || stmtRes.forwarded ||
+			attempt > maxStmtRetries {
+			stmtRes.finish(ctx)
+			return nil
+		}
+		// The transaction keeps its epoch if the restart was deferred, which
+		// isn't the case if it was aborted.
+		if txn.ID() != txnID || txn.Epoch() != epoch {
+			stmtRes.finish(ctx)
+			return nil
+		}
+		log.VEventf(ctx, 2, "retrying statement (attempt %d) after: %v", attempt, retryErr)
+		if err := txn.RollbackToSavepoint(ctx, sp); err != nil {
+			res.SetError(err)
+			return nil
+		}
+		if err := txn.StepReadTimestamp(ctx); err != nil {
+			res.SetError(err)
+			return nil
+		}
+	}
+}
+
+// stmtRetryResult is a RestrictedCommandResult which allows a statement to be
+// retried after an error. The result columns are held back until the first
+// row is added, and the error is held back until the statement is done. Once
+// any rows have been passed on to the client, the statement can no longer be
+// retried.
+type stmtRetryResult struct {
+	RestrictedCommandResult
+	cols    colinfo.ResultColumns
+	colsSet bool
+	err     error
+	// forwarded is set once any results have been passed on to the wrapped
+	// result.
+	forwarded bool
+}
+
+var _ RestrictedCommandResult = &stmtRetryResult{}
+
+// SetColumns is part of the RestrictedCommandResult interface.
+func (r *stmtRetryResult) SetColumns(_ context.Context, cols colinfo.ResultColumns) {
+	r.cols = cols
+	r.colsSet = true
+}
+
+// AddRow is part of the RestrictedCommandResult interface.
+func (r *stmtRetryResult) AddRow(ctx context.Context, row tree.Datums) error {
+	r.forwardColumns(ctx)
+	r.forwarded = true
+	return r.RestrictedCommandResult.AddRow(ctx, row)
+}
+
+// IncrementRowsAffected is part of the RestrictedCommandResult interface.
+func (r *stmtRetryResult) IncrementRowsAffected(n int) {
+	r.forwarded = true
+	r.RestrictedCommandResult.IncrementRowsAffected(n)
+}
+
+// SetError is part of the RestrictedCommandResult interface.
+func (r *stmtRetryResult) SetError(err error) {
+	r.err = err
+}
+
+// Err is part of the RestrictedCommandResult interface.
+func (r *stmtRetryResult) Err() error {
+	return r.err
+}
+
+// finish passes on the results which were held back to the wrapped result.
+func (r *stmtRetryResult) finish(ctx context.Context) {
+	r.forwardColumns(ctx)
+	if r.err != nil {
+		r.RestrictedCommandResult.SetError(r.err)
+	}
+}
+
+func (r *stmtRetryResult) forwardColumns(ctx context.Context) {
+	if r.colsSet {
+		r.RestrictedCommandResult.SetColumns(ctx, r.cols)
+		r.colsSet = false
+	}
+}
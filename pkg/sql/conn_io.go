@@ -135,6 +135,9 @@ type ExecStmt struct {
 	// stats reporting.
 	ParseStart time.Time
 	ParseEnd   time.Time
+	// TraceParent is the W3C traceparent passed by the client in a comment of
+	// the query string, if any.
+	TraceParent string
 }
 
 // command implements the Command interface.
@@ -189,6 +192,10 @@ type PrepareStmt struct {
 	RawTypeHints []oid.Oid
 	ParseStart   time.Time
 	ParseEnd     time.Time
+	// TraceParent is the W3C traceparent passed by the client in a comment of
+	// the query string, if any. It applies to all the executions of the
+	// prepared statement.
+	TraceParent string
 }
 
 // command implements the Command interface.
@@ -331,6 +338,22 @@ func (CopyIn) String() string {
 
 var _ Command = CopyIn{}
 
+// CopyOut is the command for execution of the Copy-out pgwire subprotocol.
+// Unlike CopyIn, it doesn't need control of the network connection: the data
+// being copied is streamed to the client through the command's CopyOutResult.
+type CopyOut struct {
+	Stmt *tree.CopyTo
+}
+
+// command implements the Command interface.
+func (CopyOut) command() string { return "copy" }
+
+func (CopyOut) String() string {
+	return "CopyOut"
+}
+
+var _ Command = CopyOut{}
+
 // DrainRequest represents a notice that the server is draining and command
 // processing should stop soon.
 //
@@ -615,6 +638,8 @@ type ClientComm interface {
 	CreateEmptyQueryResult(pos CmdPos) EmptyQueryResult
 	// CreateCopyInResult creates a result for a Copy-in command.
 	CreateCopyInResult(pos CmdPos) CopyInResult
+	// CreateCopyOutResult creates a result for a Copy-out command.
+	CreateCopyOutResult(pos CmdPos, conv sessiondata.DataConversionConfig) CopyOutResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
 
@@ -798,6 +823,34 @@ type CopyInResult interface {
 	ResultBase
 }
 
+// CopyOutResult represents the result of a CopyOut command. The rows passed to
+// AddRow are encoded according to the CopyFormatOptions given to BeginCopyOut
+// and sent to the client as CopyData messages. Closing this result ends the
+// Copy-out subprotocol and produces the CommandComplete message.
+type CopyOutResult interface {
+	RestrictedCommandResult
+	CommandResultClose
+
+	// BeginCopyOut sends the message initiating the Copy-out subprotocol (COPY
+	// ... TO STDOUT), informing the client about the columns being copied. It
+	// must be called before any row is added to the result.
+	BeginCopyOut(ctx context.Context, cols colinfo.ResultColumns, opts CopyFormatOptions) error
+}
+
+// CopyFormatOptions describes how the data of a COPY statement is encoded.
+type CopyFormatOptions struct {
+	Format tree.CopyFormat
+	// Delimiter separates the columns of a row in the text and CSV formats.
+	Delimiter byte
+	// Null is the string representing a NULL value in the text and CSV formats.
+	Null string
+	// Quote is the quoting character used by the CSV format.
+	Quote byte
+	// Header, if set, makes the CSV format output a line with the column names
+	// before the rows.
+	Header bool
+}
+
 // ClientLock is an interface returned by ClientComm.lockCommunication(). It
 // represents a lock on the delivery of results to a SQL client. While such a
 // lock is used, no more results are delivered. The lock itself can be used to
@@ -871,17 +924,24 @@ type bufferedCommandResult struct {
 
 	// closeCallback, if set, is called when Close()/Discard() is called.
 	closeCallback func(*bufferedCommandResult, resCloseType, error)
+
+	// rowsWriter, if set, receives the columns, rows and error of the result
+	// instead of them being buffered.
+	rowsWriter internalRowsWriter
 }
 
 var _ RestrictedCommandResult = &bufferedCommandResult{}
 var _ CommandResultClose = &bufferedCommandResult{}
 
 // SetColumns is part of the RestrictedCommandResult interface.
-func (r *bufferedCommandResult) SetColumns(_ context.Context, cols colinfo.ResultColumns) {
+func (r *bufferedCommandResult) SetColumns(ctx context.Context, cols colinfo.ResultColumns) {
 	if r.errOnly {
 		panic("SetColumns() called when errOnly is set")
 	}
 	r.cols = cols
+	if r.rowsWriter != nil {
+		r.rowsWriter.SetColumns(ctx, cols)
+	}
 }
 
 // BufferParamStatusUpdate is part of the RestrictedCommandResult interface.
@@ -904,6 +964,9 @@ func (r *bufferedCommandResult) AddRow(ctx context.Context, row tree.Datums) err
 	if r.errOnly {
 		panic("AddRow() called when errOnly is set")
 	}
+	if r.rowsWriter != nil {
+		return r.rowsWriter.AddRow(ctx, row)
+	}
 	rowCopy := make(tree.Datums, len(row))
 	copy(rowCopy, row)
 	r.rows = append(r.rows, rowCopy)
@@ -917,6 +980,9 @@ func (r *bufferedCommandResult) DisableBuffering() {
 // SetError is part of the RestrictedCommandResult interface.
 func (r *bufferedCommandResult) SetError(err error) {
 	r.err = err
+	if r.rowsWriter != nil {
+		r.rowsWriter.SetError(err)
+	}
 }
 
 // Err is part of the RestrictedCommandResult interface.
@@ -56,8 +56,11 @@ type copyMachine struct {
 	table         tree.TableExpr
 	columns       tree.NameList
 	resultColumns colinfo.ResultColumns
-	format        tree.CopyFormat
+	opts          CopyFormatOptions
 	binaryState   binaryState
+	// skipHeader is set while the header line of CSV data is still to be
+	// skipped.
+	skipHeader bool
 	// forceNotNull disables converting values matching the null string to
 	// NULL. The spec says this is only supported for CSV, and also must specify
 	// which columns it applies to.
@@ -112,7 +115,6 @@ func newCopyMachine(
 		//  but that dependency can be removed by refactoring it.
 		table:   &n.Table,
 		columns: n.Columns,
-		format:  n.Options.CopyFormat,
 		txnOpt:  txnOpt,
 		// The planner will be prepared before use.
 		p:              planner{execCfg: execCfg, alloc: &rowenc.DatumAlloc{}},
@@ -127,6 +129,13 @@ func newCopyMachine(
 	}()
 	c.parsingEvalCtx = c.p.EvalContext()
 
+	opts, err := c.p.evalCopyFormatOptions(ctx, &n.Options)
+	if err != nil {
+		return nil, err
+	}
+	c.opts = opts
+	c.skipHeader = c.opts.Header
+
 	flags := tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc)
 	tableDesc, err := resolver.ResolveExistingTableObject(ctx, &c.p, &n.Table, flags)
 	if err != nil {
@@ -237,12 +246,126 @@ Loop:
 const (
 	nullString = `\N`
 	lineDelim  = '\n'
-)
+	fieldDelim = '\t'
 
-var (
-	fieldDelim = []byte{'\t'}
+	csvNullString = ``
+	csvFieldDelim = ','
+	csvQuote      = '"'
 )
 
+// makeCopyFormatOptions returns the default options for the given format.
+func makeCopyFormatOptions(format tree.CopyFormat) CopyFormatOptions {
+	switch format {
+	case tree.CopyFormatCSV:
+		return CopyFormatOptions{
+			Format:    format,
+			Delimiter: csvFieldDelim,
+			Null:      csvNullString,
+			Quote:     csvQuote,
+		}
+	case tree.CopyFormatBinary:
+		return CopyFormatOptions{Format: format}
+	default:
+		return CopyFormatOptions{
+			Format:    format,
+			Delimiter: fieldDelim,
+			Null:      nullString,
+		}
+	}
+}
+
+// evalCopyFormatOptions evaluates the options of a COPY statement which
+// describe the format of the copied data.
+func (p *planner) evalCopyFormatOptions(
+	ctx context.Context, o *tree.CopyOptions,
+) (CopyFormatOptions, error) {
+	opts := makeCopyFormatOptions(o.CopyFormat)
+	evalChar := func(e tree.Expr, name string) (byte, error) {
+		s, err := p.evalCopyStringOption(ctx, e)
+		if err != nil {
+			return 0, err
+		}
+		if len(s) != 1 {
+			return 0, pgerror.Newf(pgcode.FeatureNotSupported,
+				"COPY %s must be a single one-byte character", name)
+		}
+		return s[0], nil
+	}
+
+	if o.CopyFormat == tree.CopyFormatBinary {
+		if o.Delimiter != nil {
+			return opts, pgerror.New(pgcode.Syntax, "cannot specify DELIMITER in BINARY mode")
+		}
+		if o.Null != nil {
+			return opts, pgerror.New(pgcode.Syntax, "cannot specify NULL in BINARY mode")
+		}
+	}
+	if o.CopyFormat != tree.CopyFormatCSV {
+		if o.Header {
+			return opts, pgerror.New(pgcode.FeatureNotSupported,
+				"COPY HEADER available only in CSV mode")
+		}
+		if o.Quote != nil {
+			return opts, pgerror.New(pgcode.FeatureNotSupported,
+				"COPY quote available only in CSV mode")
+		}
+	}
+	opts.Header = o.Header
+
+	if o.Delimiter != nil {
+		d, err := evalChar(o.Delimiter, "delimiter")
+		if err != nil {
+			return opts, err
+		}
+		if d == '\n' || d == '\r' {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"COPY delimiter cannot be newline or carriage return")
+		}
+		if d == '\\' && opts.Format == tree.CopyFormatText {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				`COPY delimiter cannot be "\"`)
+		}
+		opts.Delimiter = d
+	}
+	if o.Quote != nil {
+		q, err := evalChar(o.Quote, "quote")
+		if err != nil {
+			return opts, err
+		}
+		opts.Quote = q
+	}
+	if o.Null != nil {
+		s, err := p.evalCopyStringOption(ctx, o.Null)
+		if err != nil {
+			return opts, err
+		}
+		if strings.ContainsAny(s, "\r\n") {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"COPY null representation cannot use newline or carriage return")
+		}
+		opts.Null = s
+	}
+
+	if opts.Format == tree.CopyFormatCSV && opts.Delimiter == opts.Quote {
+		return opts, pgerror.New(pgcode.InvalidParameterValue,
+			"COPY delimiter and quote must be different")
+	}
+	if opts.Format != tree.CopyFormatBinary && strings.IndexByte(opts.Null, opts.Delimiter) != -1 {
+		return opts, pgerror.New(pgcode.InvalidParameterValue,
+			"COPY delimiter must not appear in the NULL specification")
+	}
+	return opts, nil
+}
+
+// evalCopyStringOption evaluates the string value of a COPY option.
+func (p *planner) evalCopyStringOption(ctx context.Context, e tree.Expr) (string, error) {
+	fn, err := p.TypeAsString(ctx, e, "COPY")
+	if err != nil {
+		return "", err
+	}
+	return fn()
+}
+
 // processCopyData buffers incoming data and, once the buffer fills up, inserts
 // the accumulated rows.
 //
@@ -269,11 +392,13 @@ func (c *copyMachine) processCopyData(ctx context.Context, data string, final bo
 	}
 	c.buf.WriteString(data)
 	var readFn func(ctx context.Context, final bool) (brk bool, err error)
-	switch c.format {
+	switch c.opts.Format {
 	case tree.CopyFormatText:
 		readFn = c.readTextData
 	case tree.CopyFormatBinary:
 		readFn = c.readBinaryData
+	case tree.CopyFormatCSV:
+		readFn = c.readCSVData
 	default:
 		panic("unknown copy format")
 	}
@@ -318,6 +443,47 @@ func (c *copyMachine) readTextData(ctx context.Context, final bool) (brk bool, e
 	return false, err
 }
 
+func (c *copyMachine) readCSVData(ctx context.Context, final bool) (brk bool, err error) {
+	// Find the end of the record. Quoted fields can contain newlines, so we
+	// need to keep track of the quotes in order to find it. An escaped quote
+	// (which is represented by two quotes) flips the state twice.
+	data := c.buf.Bytes()
+	end := -1
+	inQuote := false
+	for i, ch := range data {
+		if ch == c.opts.Quote {
+			inQuote = !inQuote
+		} else if ch == lineDelim && !inQuote {
+			end = i
+			break
+		}
+	}
+	var line []byte
+	if end == -1 {
+		if !final {
+			// Wait for the rest of the record, to be processed next time.
+			return true, nil
+		}
+		line = c.buf.Next(c.buf.Len())
+	} else {
+		// Remove lineDelim from end.
+		line = c.buf.Next(end + 1)[:end]
+		// Remove a single '\r' at EOL, if present.
+		if len(line) > 0 && line[len(line)-1] == '\r' {
+			line = line[:len(line)-1]
+		}
+	}
+	if c.buf.Len() == 0 && bytes.Equal(line, []byte(`\.`)) {
+		return true, nil
+	}
+	if c.skipHeader {
+		c.skipHeader = false
+		return false, nil
+	}
+	err = c.readCSVTuple(ctx, line)
+	return false, err
+}
+
 func (c *copyMachine) readBinaryData(ctx context.Context, final bool) (brk bool, err error) {
 	switch c.binaryState {
 	case binaryStateNeedSignature:
@@ -514,7 +680,7 @@ func (c *copyMachine) insertRows(ctx context.Context) (retErr error) {
 }
 
 func (c *copyMachine) readTextTuple(ctx context.Context, line []byte) error {
-	parts := bytes.Split(line, fieldDelim)
+	parts := bytes.Split(line, []byte{c.opts.Delimiter})
 	if len(parts) != len(c.resultColumns) {
 		return pgerror.Newf(pgcode.BadCopyFileFormat,
 			"expected %d values, got %d", len(c.resultColumns), len(parts))
@@ -524,7 +690,7 @@ func (c *copyMachine) readTextTuple(ctx context.Context, line []byte) error {
 		s := string(part)
 		// Although the spec says this is only supported for CSV, we need it here to
 		// disable NULL conversion during file uploads.
-		if !c.forceNotNull && s == nullString {
+		if !c.forceNotNull && s == c.opts.Null {
 			exprs[i] = tree.DNull
 			continue
 		}
@@ -559,6 +725,75 @@ func (c *copyMachine) readTextTuple(ctx context.Context, line []byte) error {
 	return nil
 }
 
+// readCSVTuple parses a single CSV record. Fields can be quoted, in which case
+// they can contain the delimiter, newlines and quotes (which are escaped by
+// doubling them). Only unquoted fields that match the null string are NULL.
+func (c *copyMachine) readCSVTuple(ctx context.Context, line []byte) error {
+	exprs := make(tree.Exprs, 0, len(c.resultColumns))
+	var field []byte
+	quoted, inQuote := false, false
+	addField := func() error {
+		if len(exprs) == len(c.resultColumns) {
+			return pgerror.Newf(pgcode.BadCopyFileFormat,
+				"expected %d values, got more", len(c.resultColumns))
+		}
+		s, wasQuoted := string(field), quoted
+		field, quoted = field[:0], false
+		if !wasQuoted && s == c.opts.Null {
+			exprs = append(exprs, tree.DNull)
+			return nil
+		}
+		d, err := rowenc.ParseDatumStringAsWithRawBytes(
+			c.resultColumns[len(exprs)].Typ, s, c.parsingEvalCtx,
+		)
+		if err != nil {
+			return err
+		}
+		if err := c.rowsMemAcc.Grow(ctx, int64(d.Size())); err != nil {
+			return err
+		}
+		exprs = append(exprs, d)
+		return nil
+	}
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case inQuote && ch == c.opts.Quote:
+			if i+1 < len(line) && line[i+1] == c.opts.Quote {
+				field = append(field, ch)
+				i++
+			} else {
+				inQuote = false
+			}
+		case inQuote:
+			field = append(field, ch)
+		case ch == c.opts.Quote:
+			inQuote, quoted = true, true
+		case ch == c.opts.Delimiter:
+			if err := addField(); err != nil {
+				return err
+			}
+		default:
+			field = append(field, ch)
+		}
+	}
+	if inQuote {
+		return pgerror.New(pgcode.BadCopyFileFormat, "unterminated CSV quoted field")
+	}
+	if err := addField(); err != nil {
+		return err
+	}
+	if len(exprs) != len(c.resultColumns) {
+		return pgerror.Newf(pgcode.BadCopyFileFormat,
+			"expected %d values, got %d", len(c.resultColumns), len(exprs))
+	}
+	if err := c.rowsMemAcc.Grow(ctx, int64(unsafe.Sizeof(exprs))); err != nil {
+		return err
+	}
+	c.rows = append(c.rows, exprs)
+	return nil
+}
+
 // decodeCopy unescapes a single COPY field.
 //
 // See: https://www.postgresql.org/docs/9.5/static/sql-copy.html#AEN74432
@@ -89,6 +89,7 @@ func newFileUploadMachine(
 	}
 	c := &copyMachine{
 		conn: conn,
+		opts: makeCopyFormatOptions(tree.CopyFormatText),
 		// The planner will be prepared before use.
 		p: planner{execCfg: execCfg, alloc: &rowenc.DatumAlloc{}},
 	}
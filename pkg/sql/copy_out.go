@@ -0,0 +1,128 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// copyOutMachine supports the Copy-out pgwire subprotocol (COPY...TO STDOUT).
+// The machine is created by the Executor when that statement is executed. It
+// plans the query producing the rows to be copied (either the query given in
+// the statement or a SELECT of the given table columns) and streams its results
+// to the client through the command's CopyOutResult, which is responsible for
+// encoding them in the requested format and for sending the protocol messages.
+//
+// See: https://www.postgresql.org/docs/current/static/sql-copy.html
+// and: https://www.postgresql.org/docs/current/static/protocol-flow.html#PROTOCOL-COPY
+type copyOutMachine struct {
+	stmt *tree.CopyTo
+	res  CopyOutResult
+
+	// execPlan is a function to be used to execute the plan (stored in the
+	// planner) which produces the rows to be copied.
+	execPlan func(ctx context.Context, p *planner, res RestrictedCommandResult) error
+
+	txnOpt copyTxnOpt
+
+	// p is the planner used to plan the query.
+	p planner
+}
+
+var _ copyMachineInterface = &copyOutMachine{}
+
+// newCopyOutMachine creates a new copyOutMachine.
+func newCopyOutMachine(
+	stmt *tree.CopyTo,
+	res CopyOutResult,
+	txnOpt copyTxnOpt,
+	execCfg *ExecutorConfig,
+	execPlan func(ctx context.Context, p *planner, res RestrictedCommandResult) error,
+) *copyOutMachine {
+	return &copyOutMachine{
+		stmt:   stmt,
+		res:    res,
+		txnOpt: txnOpt,
+		// The planner will be prepared before use.
+		p:        planner{execCfg: execCfg, alloc: &rowenc.DatumAlloc{}},
+		execPlan: execPlan,
+	}
+}
+
+// run plans and executes the query of the COPY statement, sending its results
+// to the client.
+func (c *copyOutMachine) run(ctx context.Context) (retErr error) {
+	cleanup := c.p.preparePlannerForCopy(ctx, c.txnOpt)
+	defer func() {
+		retErr = cleanup(ctx, retErr)
+	}()
+
+	opts, err := c.p.evalCopyFormatOptions(ctx, &c.stmt.Options)
+	if err != nil {
+		return err
+	}
+
+	ast := c.stmt.Statement
+	if ast == nil {
+		if ast, err = c.selectTableColumns(ctx); err != nil {
+			return err
+		}
+	} else if ast.StatementType() != tree.Rows {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"COPY query must have a RETURNING clause")
+	}
+	c.p.stmt = &Statement{}
+	c.p.stmt.AST = ast
+	if err := c.p.makeOptimizerPlan(ctx); err != nil {
+		return err
+	}
+	defer c.p.curPlan.close(ctx)
+
+	if err := c.res.BeginCopyOut(ctx, c.p.curPlan.main.planColumns(), opts); err != nil {
+		return err
+	}
+	if err := c.execPlan(ctx, &c.p, c.res); err != nil {
+		return err
+	}
+	return c.res.Err()
+}
+
+// selectTableColumns returns a SELECT statement of the columns to be copied
+// from the table of the COPY statement.
+func (c *copyOutMachine) selectTableColumns(ctx context.Context) (tree.Statement, error) {
+	flags := tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc)
+	tableDesc, err := resolver.ResolveExistingTableObject(ctx, &c.p, &c.stmt.Table, flags)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := colinfo.ProcessTargetColumns(tableDesc, c.stmt.Columns,
+		true /* ensureColumns */, false /* allowMutations */)
+	if err != nil {
+		return nil, err
+	}
+	exprs := make(tree.SelectExprs, len(cols))
+	for i := range cols {
+		exprs[i] = tree.SelectExpr{Expr: tree.NewUnresolvedName(cols[i].Name)}
+	}
+	return &tree.Select{
+		Select: &tree.SelectClause{
+			Exprs: exprs,
+			From:  tree.From{Tables: tree.TableExprs{&c.stmt.Table}},
+		},
+	}, nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 )
 
 type createSequenceNode struct {
@@ -68,16 +69,14 @@ func (n *createSequenceNode) startExec(params runParams) error {
 	}
 
 	return doCreateSequence(
-		params, n.n.String(), n.dbDesc, schemaID, &n.n.Name, n.n.Persistence, n.n.Options,
+		params, n.dbDesc, schemaID, &n.n.Name, n.n.Persistence, n.n.Options,
 		tree.AsStringWithFQNames(n.n, params.Ann()),
 	)
 }
 
-// doCreateSequence performs the creation of a sequence in KV. The
-// context argument is a string to use in the event log.
+// doCreateSequence performs the creation of a sequence in KV.
 func doCreateSequence(
 	params runParams,
-	context string,
 	dbDesc catalog.DatabaseDescriptor,
 	schemaID descpb.ID,
 	name *tree.TableName,
@@ -149,18 +148,11 @@ func doCreateSequence(
 
 	// Log Create Sequence event. This is an auditable log event and is
 	// recorded in the same transaction as the table descriptor update.
-	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
-		params.ctx,
-		params.p.txn,
-		EventLogCreateSequence,
-		int32(desc.ID),
-		int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
-		struct {
-			SequenceName string
-			Statement    string
-			User         string
-		}{name.FQString(), context, params.SessionData().User},
-	)
+	return params.p.logEvent(params.ctx,
+		desc.ID,
+		&eventpb.CreateSequence{
+			SequenceName: name.FQString(),
+		})
 }
 
 func (*createSequenceNode) Next(runParams) (bool, error) { return false, nil }
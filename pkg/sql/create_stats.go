@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 )
 
@@ -564,16 +565,14 @@ func (r *createStatsResumer) Resume(
 	// system.table_statistics table, but that would require calling
 	// MakeEventLogger from the distsqlrun package.
 	return evalCtx.ExecCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
-		return MakeEventLogger(evalCtx.ExecCfg).InsertEventRecord(
-			ctx,
-			txn,
-			EventLogCreateStatistics,
-			int32(details.Table.ID),
-			int32(evalCtx.NodeID.SQLInstanceID()),
-			struct {
-				TableName string
-				Statement string
-			}{details.FQTableName, details.Statement},
+		return logEventInternalForSQLStatements(ctx, evalCtx.ExecCfg, txn,
+			details.Table.ID,
+			r.job.Payload().Username,
+			"", /* appName */
+			details.Statement,
+			&eventpb.CreateStatistics{
+				TableName: details.FQTableName,
+			},
 		)
 	})
 }
@@ -47,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -323,6 +324,14 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	if desc.HasRowLevelTTL() {
+		sj, err := params.p.createRowLevelTTLScheduledJob(params.ctx, desc)
+		if err != nil {
+			return err
+		}
+		desc.RowLevelTTL.ScheduleID = sj.ScheduleID()
+	}
+
 	// Descriptor written to store here.
 	if err := params.p.createDescriptorWithID(
 		params.ctx, tKey.Key(params.ExecCfg().Codec), id, desc, params.EvalContext().Settings,
@@ -362,18 +371,11 @@ func (n *createTableNode) startExec(params runParams) error {
 
 	// Log Create Table event. This is an auditable log event and is
 	// recorded in the same transaction as the table descriptor update.
-	if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
-		params.ctx,
-		params.p.txn,
-		EventLogCreateTable,
-		int32(desc.ID),
-		int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
-		struct {
-			TableName string
-			Statement string
-			User      string
-		}{n.n.Table.FQString(), n.n.String(), params.SessionData().User},
-	); err != nil {
+	if err := params.p.logEvent(params.ctx,
+		desc.ID,
+		&eventpb.CreateTable{
+			TableName: n.n.Table.FQString(),
+		}); err != nil {
 		return err
 	}
 
@@ -1218,7 +1220,7 @@ func NewTableDesc(
 		semaCtx,
 		evalCtx,
 		n.StorageParams,
-		&paramparse.TableStorageParamObserver{},
+		paramparse.NewTableStorageParamObserver(&desc.TableDescriptor),
 	); err != nil {
 		return nil, err
 	}
@@ -1340,6 +1342,17 @@ func NewTableDesc(
 		}
 	}
 
+	if desc.HasRowLevelTTL() {
+		if err := checkRowLevelTTLSupported(ctx, st, n); err != nil {
+			return nil, err
+		}
+		if desc.RowLevelTTL.ExpireAfter != "" {
+			if err := addRowLevelTTLExpirationColumn(ctx, &desc, semaCtx, evalCtx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Now that we've constructed our columns, we pop into any of our computed
 	// columns so that we can dequalify any column references.
 	sourceInfo := colinfo.NewSourceInfoForSingleTable(
@@ -1702,6 +1715,12 @@ func NewTableDesc(
 		}
 	}
 
+	if desc.HasRowLevelTTL() {
+		if err := validateRowLevelTTL(ctx, &desc, semaCtx, &n.Table); err != nil {
+			return nil, err
+		}
+	}
+
 	// AllocateIDs mutates its receiver. `return desc, desc.AllocateIDs()`
 	// happens to work in gc, but does not work in gccgo.
 	//
@@ -1777,7 +1796,6 @@ func newTableDesc(
 		if seqName != nil {
 			if err := doCreateSequence(
 				params,
-				n.String(),
 				seqDbDesc,
 				parentSchemaID,
 				seqName,
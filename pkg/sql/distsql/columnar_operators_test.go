@@ -1031,67 +1031,133 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	maxNum := 10
 	typs := make([]*types.T, maxCols)
 	for i := range typs {
-		// TODO(yuzefovich): randomize the types of the columns once we support
-		// window functions that take in arguments.
+		// TODO(yuzefovich): randomize the types of the columns.
 		typs[i] = types.Int
 	}
+	sumFn := execinfrapb.AggregatorSpec_SUM
+	countFn := execinfrapb.AggregatorSpec_COUNT
+	maxFn := execinfrapb.AggregatorSpec_MAX
+	var windowFns []execinfrapb.WindowerSpec_Func
 	for windowFn := range colbuilder.SupportedWindowFns {
-		for _, partitionBy := range [][]uint32{
-			{},     // No PARTITION BY clause.
-			{0},    // Partitioning on the first input column.
-			{0, 1}, // Partitioning on the first and second input columns.
-		} {
-			for _, nOrderingCols := range []int{
-				0, // No ORDER BY clause.
-				1, // ORDER BY on at most one column.
-				2, // ORDER BY on at most two columns.
+		windowFn := windowFn
+		windowFns = append(windowFns, execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn})
+	}
+	for _, aggFn := range []*execinfrapb.AggregatorSpec_Func{&sumFn, &countFn, &maxFn} {
+		windowFns = append(windowFns, execinfrapb.WindowerSpec_Func{AggregateFunc: aggFn})
+	}
+	frames := []*execinfrapb.WindowerSpec_Frame{
+		nil, /* default frame */
+		{
+			Mode: execinfrapb.WindowerSpec_Frame_ROWS,
+			Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+				Start: execinfrapb.WindowerSpec_Frame_Bound{BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING, IntOffset: 2},
+				End:   &execinfrapb.WindowerSpec_Frame_Bound{BoundType: execinfrapb.WindowerSpec_Frame_CURRENT_ROW},
+			},
+		},
+		{
+			Mode: execinfrapb.WindowerSpec_Frame_GROUPS,
+			Bounds: execinfrapb.WindowerSpec_Frame_Bounds{
+				Start: execinfrapb.WindowerSpec_Frame_Bound{BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING, IntOffset: 1},
+				End:   &execinfrapb.WindowerSpec_Frame_Bound{BoundType: execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING, IntOffset: 1},
+			},
+			Exclusion: execinfrapb.WindowerSpec_Frame_EXCLUDE_CURRENT_ROW,
+		},
+	}
+	for _, fn := range windowFns {
+		for _, frame := range frames {
+			if frame != nil && fn.AggregateFunc == nil {
+				// Only aggregate functions used as window functions pay attention
+				// to the custom frames out of all non-ranking functions, so we
+				// don't bother testing others with custom frames.
+				continue
+			}
+			for _, partitionBy := range [][]uint32{
+				{},     // No PARTITION BY clause.
+				{0},    // Partitioning on the first input column.
+				{0, 1}, // Partitioning on the first and second input columns.
 			} {
-				for nCols := 1; nCols <= maxCols; nCols++ {
-					if len(partitionBy) > nCols || nOrderingCols > nCols {
-						continue
-					}
-					inputTypes := typs[:nCols:nCols]
-					rows := rowenc.MakeRandIntRowsInRange(rng, nRows, nCols, maxNum, nullProbability)
+				for _, nOrderingCols := range []int{
+					0, // No ORDER BY clause.
+					1, // ORDER BY on at most one column.
+					2, // ORDER BY on at most two columns.
+				} {
+					for nCols := 1; nCols <= maxCols; nCols++ {
+						if len(partitionBy) > nCols || nOrderingCols > nCols {
+							continue
+						}
+						inputTypes := typs[:nCols:nCols]
+						rows := rowenc.MakeRandIntRowsInRange(rng, nRows, nCols, maxNum, nullProbability)
 
-					windowerSpec := &execinfrapb.WindowerSpec{
-						PartitionBy: partitionBy,
-						WindowFns: []execinfrapb.WindowerSpec_WindowFn{
-							{
-								Func:         execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn},
-								Ordering:     generateOrderingGivenPartitionBy(rng, nCols, nOrderingCols, partitionBy),
-								OutputColIdx: uint32(nCols),
-								FilterColIdx: tree.NoColumnIdx,
+						var argsIdxs []uint32
+						if fn.AggregateFunc != nil {
+							argsIdxs = []uint32{uint32(nCols - 1)}
+						} else {
+							switch *fn.WindowFunc {
+							case execinfrapb.WindowerSpec_LAG,
+								execinfrapb.WindowerSpec_LEAD,
+								execinfrapb.WindowerSpec_FIRST_VALUE,
+								execinfrapb.WindowerSpec_LAST_VALUE:
+								argsIdxs = []uint32{uint32(nCols - 1)}
+							case execinfrapb.WindowerSpec_NTILE:
+								argsIdxs = []uint32{0}
+							case execinfrapb.WindowerSpec_NTH_VALUE:
+								argsIdxs = []uint32{uint32(nCols - 1), 0}
+							}
+							switch *fn.WindowFunc {
+							case execinfrapb.WindowerSpec_NTILE, execinfrapb.WindowerSpec_NTH_VALUE:
+								// The integer argument to these functions must be
+								// positive.
+								for _, row := range rows {
+									if !row[0].IsNull() {
+										row[0] = rowenc.IntEncDatum(int(tree.MustBeDInt(row[0].Datum)) + 1)
+									}
+								}
+							}
+						}
+						windowerSpec := &execinfrapb.WindowerSpec{
+							PartitionBy: partitionBy,
+							WindowFns: []execinfrapb.WindowerSpec_WindowFn{
+								{
+									Func:         fn,
+									ArgsIdxs:     argsIdxs,
+									Ordering:     generateOrderingGivenPartitionBy(rng, nCols, nOrderingCols, partitionBy),
+									Frame:        frame,
+									OutputColIdx: uint32(nCols),
+									FilterColIdx: tree.NoColumnIdx,
+								},
 							},
-						},
-					}
-					if windowFn == execinfrapb.WindowerSpec_ROW_NUMBER &&
-						len(partitionBy)+len(windowerSpec.WindowFns[0].Ordering.Columns) < nCols {
-						// The output of row_number is not deterministic if there are
-						// columns that are not present in either PARTITION BY or ORDER BY
-						// clauses, so we skip such a configuration.
-						continue
-					}
+						}
+						if windowFnDependsOnRowOrder(fn, frame) &&
+							len(partitionBy)+len(windowerSpec.WindowFns[0].Ordering.Columns) < nCols {
+							// The output of the window function is not deterministic if
+							// there are columns that are not present in either PARTITION
+							// BY or ORDER BY clauses, so we skip such a configuration.
+							continue
+						}
 
-					pspec := &execinfrapb.ProcessorSpec{
-						Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
-						Core:  execinfrapb.ProcessorCoreUnion{Windower: windowerSpec},
-					}
-					// Currently, we only support window functions that take no
-					// arguments, so we leave the second argument empty.
-					_, outputType, err := execinfrapb.GetWindowFunctionInfo(execinfrapb.WindowerSpec_Func{WindowFunc: &windowFn})
-					require.NoError(t, err)
-					args := verifyColOperatorArgs{
-						anyOrder:    true,
-						inputTypes:  [][]*types.T{inputTypes},
-						inputs:      []rowenc.EncDatumRows{rows},
-						outputTypes: append(inputTypes, outputType),
-						pspec:       pspec,
-					}
-					if err := verifyColOperator(args); err != nil {
-						fmt.Printf("seed = %d\n", seed)
-						prettyPrintTypes(inputTypes, "t" /* tableName */)
-						prettyPrintInput(rows, inputTypes, "t" /* tableName */)
-						t.Fatal(err)
+						pspec := &execinfrapb.ProcessorSpec{
+							Input: []execinfrapb.InputSyncSpec{{ColumnTypes: inputTypes}},
+							Core:  execinfrapb.ProcessorCoreUnion{Windower: windowerSpec},
+						}
+						argTypes := make([]*types.T, len(argsIdxs))
+						for i, argIdx := range argsIdxs {
+							argTypes[i] = inputTypes[argIdx]
+						}
+						_, outputType, err := execinfrapb.GetWindowFunctionInfo(fn, argTypes...)
+						require.NoError(t, err)
+						args := verifyColOperatorArgs{
+							anyOrder:    true,
+							inputTypes:  [][]*types.T{inputTypes},
+							inputs:      []rowenc.EncDatumRows{rows},
+							outputTypes: append(inputTypes, outputType),
+							pspec:       pspec,
+						}
+						if err := verifyColOperator(args); err != nil {
+							fmt.Printf("seed = %d\n", seed)
+							prettyPrintTypes(inputTypes, "t" /* tableName */)
+							prettyPrintInput(rows, inputTypes, "t" /* tableName */)
+							t.Fatal(err)
+						}
 					}
 				}
 			}
@@ -1099,6 +1165,25 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 	}
 }
 
+// windowFnDependsOnRowOrder returns whether the result of the window function
+// depends on the order of the rows within a peer group.
+func windowFnDependsOnRowOrder(
+	fn execinfrapb.WindowerSpec_Func, frame *execinfrapb.WindowerSpec_Frame,
+) bool {
+	if fn.AggregateFunc != nil {
+		return frame != nil && frame.Mode == execinfrapb.WindowerSpec_Frame_ROWS
+	}
+	switch *fn.WindowFunc {
+	case execinfrapb.WindowerSpec_RANK,
+		execinfrapb.WindowerSpec_DENSE_RANK,
+		execinfrapb.WindowerSpec_PERCENT_RANK,
+		execinfrapb.WindowerSpec_CUME_DIST:
+		return false
+	default:
+		return true
+	}
+}
+
 // generateRandomSupportedTypes generates nCols random types that are supported
 // by the vectorized engine.
 func generateRandomSupportedTypes(rng *rand.Rand, nCols int) []*types.T {
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -194,6 +195,20 @@ func (ds *ServerImpl) setupFlow(
 		return ctx, nil, err
 	}
 
+	if q := ds.ServerConfig.SQLAdmissionQ; q != nil {
+		enabled, err := q.Admit(ctx, admission.WorkInfo{
+			Priority:   admission.NormalPri,
+			CreateTime: timeutil.Now().UnixNano(),
+		})
+		if err != nil {
+			return ctx, nil, err
+		}
+		if enabled {
+			// SQL work doesn't hold on to a resource once admitted.
+			q.AdmittedWorkDone()
+		}
+	}
+
 	const opName = "flow"
 	var sp opentracing.Span
 	if parentSpan == nil {
@@ -3465,7 +3465,8 @@ func (dsp *DistSQLPlanner) createPlanForWindow(
 }
 
 // createPlanForExport creates a physical plan for EXPORT.
-// We add a new stage of CSVWriter processors to the input plan.
+// We add a new stage of CSVWriter or ParquetWriter processors to the input
+// plan.
 func (dsp *DistSQLPlanner) createPlanForExport(
 	planCtx *PlanningCtx, n *exportNode,
 ) (*PhysicalPlan, error) {
@@ -3474,13 +3475,31 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		return nil, err
 	}
 
-	core := execinfrapb.ProcessorCoreUnion{CSVWriter: &execinfrapb.CSVWriterSpec{
-		Destination:      n.destination,
-		NamePattern:      n.fileNamePattern,
-		Options:          n.csvOpts,
-		ChunkRows:        int64(n.chunkRows),
-		CompressionCodec: n.fileCompression,
-	}}
+	var core execinfrapb.ProcessorCoreUnion
+	switch n.fileFormat {
+	case exportFormatParquet:
+		cols := planColumns(n.source)
+		colNames := make([]string, len(cols))
+		for i := range cols {
+			colNames[i] = cols[i].Name
+		}
+		core.ParquetWriter = &execinfrapb.ParquetWriterSpec{
+			Destination:      n.destination,
+			NamePattern:      n.fileNamePattern,
+			ChunkRows:        int64(n.chunkRows),
+			CompressionCodec: n.parquetCompression,
+			RowGroupSize:     n.rowGroupSize,
+			ColNames:         colNames,
+		}
+	default:
+		core.CSVWriter = &execinfrapb.CSVWriterSpec{
+			Destination:      n.destination,
+			NamePattern:      n.fileNamePattern,
+			Options:          n.csvOpts,
+			ChunkRows:        int64(n.chunkRows),
+			CompressionCodec: n.fileCompression,
+		}
+	}
 
 	resTypes := make([]*types.T, len(colinfo.ExportColumns))
 	for i := range colinfo.ExportColumns {
@@ -3490,7 +3509,7 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		core, execinfrapb.PostProcessSpec{}, resTypes, execinfrapb.Ordering{},
 	)
 
-	// The CSVWriter produces the same columns as the EXPORT statement.
+	// The writer produces the same columns as the EXPORT statement.
 	plan.PlanToStreamColMap = identityMap(plan.PlanToStreamColMap, len(colinfo.ExportColumns))
 	return plan, nil
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 )
 
@@ -320,6 +321,14 @@ func (n *DropRoleNode) startExec(params runParams) error {
 		if err != nil {
 			return err
 		}
+
+		if numUsersDeleted > 0 {
+			if err := params.p.logEvent(params.ctx,
+				0, /* no target */
+				&eventpb.DropRole{RoleName: normalizedUsername}); err != nil {
+				return err
+			}
+		}
 	}
 
 	if numRoleMembershipsDeleted > 0 {
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 )
 
@@ -141,18 +142,11 @@ func (n *dropSchemaNode) startExec(params runParams) error {
 	// Log Drop Schema event. This is an auditable log event and is recorded
 	// in the same transaction as table descriptor update.
 	for _, sc := range n.d.schemasToDelete {
-		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
-			ctx,
-			p.txn,
-			EventLogDropSchema,
-			int32(sc.ID),
-			int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
-			struct {
-				SchemaName string
-				Statement  string
-				User       string
-			}{sc.Name, n.n.String(), p.SessionData().User},
-		); err != nil {
+		if err := p.logEvent(ctx,
+			sc.ID,
+			&eventpb.DropSchema{
+				SchemaName: sc.Name,
+			}); err != nil {
 			return err
 		}
 	}
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
@@ -127,20 +128,12 @@ func (n *dropTableNode) startExec(params runParams) error {
 		// Log a Drop Table event for this table. This is an auditable log event
 		// and is recorded in the same transaction as the table descriptor
 		// update.
-		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
-			ctx,
-			params.p.txn,
-			EventLogDropTable,
-			int32(droppedDesc.ID),
-			int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
-			struct {
-				TableName           string
-				Statement           string
-				User                string
-				CascadeDroppedViews []string
-			}{toDel.tn.FQString(), n.n.String(),
-				params.SessionData().User, droppedViews},
-		); err != nil {
+		if err := params.p.logEvent(ctx,
+			droppedDesc.ID,
+			&eventpb.DropTable{
+				TableName:           toDel.tn.FQString(),
+				CascadeDroppedViews: droppedViews,
+			}); err != nil {
 			return err
 		}
 	}
@@ -413,6 +406,12 @@ func (p *planner) initiateDropTable(
 		return err
 	}
 
+	if tableDesc.HasRowLevelTTL() {
+		if err := p.deleteRowLevelTTLScheduledJob(ctx, tableDesc.RowLevelTTL.ScheduleID); err != nil {
+			return err
+		}
+	}
+
 	tableDesc.State = descpb.DescriptorState_DROP
 	if drainName {
 		parentSchemaID := tableDesc.GetParentSchemaID()
@@ -12,125 +12,95 @@ package sql
 
 import (
 	"context"
-	"encoding/json"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 )
 
-// EventLogType represents an event type that can be recorded in the event log.
-type EventLogType string
-
-// NOTE: When you add a new event type here. Please manually add it to
-// pkg/ui/src/util/eventTypes.ts so that it will be recognized in the UI.
-const (
-	// EventLogCreateDatabase is recorded when a database is created.
-	EventLogCreateDatabase EventLogType = "create_database"
-	// EventLogDropDatabase is recorded when a database is dropped.
-	EventLogDropDatabase EventLogType = "drop_database"
-	// EventLogRenameDatabase is recorded when a database is renamed.
-	EventLogRenameDatabase EventLogType = "rename_database"
-
-	// EventLogDropSchema is recorded when a schema is dropped.
-	EventLogDropSchema EventLogType = "drop_schema"
-
-	// EventLogCreateTable is recorded when a table is created.
-	EventLogCreateTable EventLogType = "create_table"
-	// EventLogDropTable is recorded when a table is dropped.
-	EventLogDropTable EventLogType = "drop_table"
-	// EventLogRenameTable is recorded when a table is renamed.
-	EventLogRenameTable EventLogType = "rename_table"
-	// EventLogTruncateTable is recorded when a table is truncated.
-	EventLogTruncateTable EventLogType = "truncate_table"
-	// EventLogAlterTable is recorded when a table is altered.
-	EventLogAlterTable EventLogType = "alter_table"
-	// EventLogCommentOnColumn is recorded when a column is commented.
-	EventLogCommentOnColumn EventLogType = "comment_on_column"
-	// EventLogCommentOnTable is recorded when a table is commented.
-	EventLogCommentOnDatabase EventLogType = "comment_on_database"
-	// EventLogCommentOnTable is recorded when a table is commented.
-	EventLogCommentOnTable EventLogType = "comment_on_table"
-	// EventLogCommentOnIndex is recorded when a index is commented.
-	EventLogCommentOnIndex EventLogType = "comment_on_index"
-
-	// EventLogCreateIndex is recorded when an index is created.
-	EventLogCreateIndex EventLogType = "create_index"
-	// EventLogDropIndex is recorded when an index is dropped.
-	EventLogDropIndex EventLogType = "drop_index"
-	// EventLogAlterIndex is recorded when an index is altered.
-	EventLogAlterIndex EventLogType = "alter_index"
-
-	// EventLogCreateView is recorded when a view is created.
-	EventLogCreateView EventLogType = "create_view"
-	// EventLogDropView is recorded when a view is dropped.
-	EventLogDropView EventLogType = "drop_view"
-
-	// EventLogCreateSequence is recorded when a sequence is created.
-	EventLogCreateSequence EventLogType = "create_sequence"
-	// EventLogDropSequence is recorded when a sequence is dropped.
-	EventLogDropSequence EventLogType = "drop_sequence"
-	// EventLogAlterSequence is recorded when a sequence is altered.
-	EventLogAlterSequence EventLogType = "alter_sequence"
-
-	// EventLogReverseSchemaChange is recorded when an in-progress schema change
-	// encounters a problem and is reversed.
-	EventLogReverseSchemaChange EventLogType = "reverse_schema_change"
-	// EventLogFinishSchemaChange is recorded when a previously initiated schema
-	// change has completed.
-	EventLogFinishSchemaChange EventLogType = "finish_schema_change"
-	// EventLogFinishSchemaRollback is recorded when a previously
-	// initiated schema change rollback has completed.
-	EventLogFinishSchemaRollback EventLogType = "finish_schema_change_rollback"
-
-	// EventLogCreateType is recorded when a type is created.
-	EventLogCreateType EventLogType = "create_type"
-	// EventLogDropType is recorded when a type is dropped.
-	EventLogDropType EventLogType = "drop_type"
-	// EventAlterType is recorded when a type is altered.
-	EventLogAlterType EventLogType = "alter_type"
-
-	// EventLogNodeJoin is recorded when a node joins the cluster.
-	EventLogNodeJoin EventLogType = "node_join"
-	// EventLogNodeRestart is recorded when an existing node rejoins the cluster
-	// after being offline.
-	EventLogNodeRestart EventLogType = "node_restart"
-
-	// EventLogNodeDecommissioned is recorded when a node is marked as
-	// decommissioning.
-	EventLogNodeDecommissioning EventLogType = "node_decommissioning"
-	// EventLogNodeDecommissioned is recorded when a node is marked as
-	// decommissioned.
-	EventLogNodeDecommissioned EventLogType = "node_decommissioned"
-	// EventLogNodeRecommissioned is recorded when a decommissioning node is
-	// recommissioned.
-	EventLogNodeRecommissioned EventLogType = "node_recommissioned"
-
-	// EventLogSetClusterSetting is recorded when a cluster setting is changed.
-	EventLogSetClusterSetting EventLogType = "set_cluster_setting"
-
-	// EventLogSetZoneConfig is recorded when a zone config is changed.
-	EventLogSetZoneConfig EventLogType = "set_zone_config"
-	// EventLogRemoveZoneConfig is recorded when a zone config is removed.
-	EventLogRemoveZoneConfig EventLogType = "remove_zone_config"
+// logEvent emits a structured event in the context of the current
+// SQL statement: the event is recorded in system.eventlog as part of
+// the planner's transaction and emitted to its logging channel once
+// the transaction commits.
+func (p *planner) logEvent(
+	ctx context.Context, descID descpb.ID, event eventpb.EventPayload,
+) error {
+	return p.logEventWithTxn(ctx, p.txn, descID, event)
+}
 
-	// EventLogCreateStatistics is recorded when statistics are collected for a
-	// table.
-	EventLogCreateStatistics EventLogType = "create_statistics"
+// logEventWithTxn is like logEvent but records the event as part of
+// the given transaction, for statements that do not use the planner's
+// transaction to perform their changes.
+func (p *planner) logEventWithTxn(
+	ctx context.Context, txn *kv.Txn, descID descpb.ID, event eventpb.EventPayload,
+) error {
+	stmt := ""
+	if p.stmt != nil {
+		stmt = p.stmt.String()
+	}
+	return logEventInternalForSQLStatements(ctx, p.ExecCfg(), txn,
+		descID, p.User(), p.SessionData().ApplicationName, stmt, event)
+}
 
-	// EventLogGrantPrivilege is recorded when privileges are added to a user
-	// for a database object.
-	EventLogGrantPrivilege EventLogType = "grant_privilege"
-	// EventLogRevokePrivilege is recorded when privileges are removed from a
-	// user for a database object.
-	EventLogRevokePrivilege EventLogType = "revoke_privilege"
-)
+// logEventInternalForSQLStatements populates the fields common to all
+// SQL events and records the event.
+func logEventInternalForSQLStatements(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	txn *kv.Txn,
+	descID descpb.ID,
+	user string,
+	appName string,
+	stmt string,
+	event eventpb.EventPayload,
+) error {
+	m, ok := event.(eventpb.EventWithCommonSQLPayload)
+	if !ok {
+		return errors.AssertionFailedf("unknown SQL event type: %T", event)
+	}
+	sqlCommon := m.CommonSQLDetails()
+	sqlCommon.Statement = stmt
+	sqlCommon.User = user
+	sqlCommon.DescriptorID = uint32(descID)
+	sqlCommon.ApplicationName = appName
+
+	return MakeEventLogger(execCfg).InsertEventRecord(
+		ctx, txn,
+		int32(descID),
+		int32(execCfg.NodeID.SQLInstanceID()),
+		event,
+	)
+}
 
-// EventLogSetClusterSettingDetail is the json details for a settings change.
-type EventLogSetClusterSettingDetail struct {
-	SettingName string
-	Value       string
-	User        string
+// logEventInternalForSchemaChanges populates the fields common to all
+// the events of the asynchronous schema changer and records the event.
+func logEventInternalForSchemaChanges(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	txn *kv.Txn,
+	sqlInstanceID base.SQLInstanceID,
+	descID descpb.ID,
+	mutationID descpb.MutationID,
+	event eventpb.EventPayload,
+) error {
+	m, ok := event.(eventpb.EventWithCommonSchemaChangePayload)
+	if !ok {
+		return errors.AssertionFailedf("unknown schema change event type: %T", event)
+	}
+	scCommon := m.CommonSchemaChangeDetails()
+	scCommon.InstanceID = int32(sqlInstanceID)
+	scCommon.DescriptorID = uint32(descID)
+	scCommon.MutationID = uint32(mutationID)
+
+	return MakeEventLogger(execCfg).InsertEventRecord(
+		ctx, txn,
+		int32(descID),
+		int32(sqlInstanceID),
+		event,
+	)
 }
 
 // An EventLogger exposes methods used to record events to the event table.
@@ -144,22 +114,26 @@ func MakeEventLogger(execCfg *ExecutorConfig) EventLogger {
 }
 
 // InsertEventRecord inserts a single event into the event log as part of the
-// provided transaction.
+// provided transaction. The event is also emitted to its logging channel
+// once the transaction commits.
+//
+// The timestamp and the type of the event are populated automatically
+// if not set already.
 func (ev EventLogger) InsertEventRecord(
-	ctx context.Context,
-	txn *kv.Txn,
-	eventType EventLogType,
-	targetID, reportingID int32,
-	info interface{},
+	ctx context.Context, txn *kv.Txn, targetID, reportingID int32, info eventpb.EventPayload,
 ) error {
+	common := info.CommonDetails()
+	if common.Timestamp == 0 {
+		common.Timestamp = txn.ReadTimestamp().WallTime
+	}
+	eventType := eventpb.GetEventTypeName(info)
+	if common.EventType == "" {
+		common.EventType = eventType
+	}
+
 	// Record event record insertion in local log output.
 	txn.AddCommitTrigger(func(ctx context.Context) {
-		log.Infof(
-			ctx, "Event: %q, target: %d, info: %+v",
-			eventType,
-			targetID,
-			info,
-		)
+		log.StructuredEvent(ctx, info)
 	})
 
 	const insertEventTableStmt = `
@@ -170,18 +144,14 @@ VALUES(
   now(), $1, $2, $3, $4
 )
 `
+	infoBytes := []byte{'{'}
+	_, infoBytes = info.AppendJSONFields(false, infoBytes)
+	infoBytes = append(infoBytes, '}')
 	args := []interface{}{
 		eventType,
 		targetID,
 		reportingID,
-		nil, // info
-	}
-	if info != nil {
-		infoBytes, err := json.Marshal(info)
-		if err != nil {
-			return err
-		}
-		args[3] = string(infoBytes)
+		string(infoBytes),
 	}
 	rows, err := ev.Exec(ctx, "log-event", txn, insertEventTableStmt, args...)
 	if err != nil {
@@ -155,6 +155,16 @@ var traceTxnThreshold = settings.RegisterPublicDurationSetting(
 	"duration beyond which all transactions are traced (set to 0 to disable)", 0,
 )
 
+// readCommittedIsolationEnabled gates the use of the READ COMMITTED isolation
+// level. When disabled, transactions requesting it are upgraded to
+// SERIALIZABLE, as Postgres does for isolation levels it doesn't implement.
+var readCommittedIsolationEnabled = settings.RegisterPublicBoolSetting(
+	"sql.txn.read_committed_isolation.enabled",
+	"set to true to allow transactions to use the READ COMMITTED isolation level; "+
+		"if false, transactions requesting it run under SERIALIZABLE isolation",
+	false,
+)
+
 // traceSessionEventLogEnabled can be used to enable the event log
 // that is normally kept for every SQL connection. The event log has a
 // non-trivial performance impact and also reveals SQL statements
@@ -254,6 +264,12 @@ var implicitSelectForUpdateClusterMode = settings.RegisterBoolSetting(
 	true,
 )
 
+var sharedLockingForFKChecksClusterMode = settings.RegisterBoolSetting(
+	"sql.defaults.shared_locking_for_fk_checks.enabled",
+	"default value for enable_shared_locking_for_fk_checks session setting; enables FOR SHARE locking of the referenced rows during foreign key checks",
+	false,
+)
+
 var insertFastPathClusterMode = settings.RegisterBoolSetting(
 	"sql.defaults.insert_fast_path.enabled",
 	"default value for enable_insert_fast_path session setting; enables a specialized insert path",
@@ -704,6 +720,7 @@ type ExecutorConfig struct {
 	EvalContextTestingKnobs       tree.EvalContextTestingKnobs
 	TenantTestingKnobs            *TenantTestingKnobs
 	BackupRestoreTestingKnobs     *BackupRestoreTestingKnobs
+	TTLTestingKnobs               *TTLTestingKnobs
 	// HistogramWindowInterval is (server.Config).HistogramWindowInterval.
 	HistogramWindowInterval time.Duration
 
@@ -895,6 +912,21 @@ var _ base.ModuleTestingKnobs = &BackupRestoreTestingKnobs{}
 // ModuleTestingKnobs implements the base.ModuleTestingKnobs interface.
 func (*BackupRestoreTestingKnobs) ModuleTestingKnobs() {}
 
+// TTLTestingKnobs contains knobs for row-level TTL jobs.
+type TTLTestingKnobs struct {
+	// AOSTDuration, if non-zero, overrides how far in the past the scans for
+	// expired rows are performed.
+	AOSTDuration time.Duration
+	// BeforeDeleteBatch is called before each batch of expired rows is deleted,
+	// once the rows have been admitted by the rate limiter.
+	BeforeDeleteBatch func(rows []tree.Datums)
+}
+
+var _ base.ModuleTestingKnobs = &TTLTestingKnobs{}
+
+// ModuleTestingKnobs implements the base.ModuleTestingKnobs interface.
+func (*TTLTestingKnobs) ModuleTestingKnobs() {}
+
 func shouldDistributeGivenRecAndMode(
 	rec distRecommendation, mode sessiondata.DistSQLExecMode,
 ) bool {
@@ -932,6 +964,13 @@ func getPlanDistribution(
 		return physicalplan.LocalPlan
 	}
 
+	// Bounded staleness reads are routed to the nearest replicas by the
+	// gateway's transaction, which isn't the case for the leaf transactions of
+	// remote flows.
+	if p.semaCtx.AsOfBoundedStaleness != nil {
+		return physicalplan.LocalPlan
+	}
+
 	if _, singleTenant := nodeID.OptionalNodeID(); !singleTenant {
 		return physicalplan.LocalPlan
 	}
@@ -1081,15 +1120,25 @@ func checkResultType(typ *types.T) error {
 func (p *planner) EvalAsOfTimestamp(
 	ctx context.Context, asOf tree.AsOfClause,
 ) (_ hlc.Timestamp, err error) {
-	ts, err := tree.EvalAsOfTimestamp(ctx, asOf, &p.semaCtx, p.EvalContext())
+	asOfSystemTime, err := p.evalAsOf(ctx, asOf, false /* allowBoundedStaleness */)
+	return asOfSystemTime.Timestamp, err
+}
+
+// evalAsOf evaluates an AS OF SYSTEM TIME clause, which may specify a bounded
+// staleness read if allowBoundedStaleness is set.
+func (p *planner) evalAsOf(
+	ctx context.Context, asOf tree.AsOfClause, allowBoundedStaleness bool,
+) (tree.AsOfSystemTime, error) {
+	asOfSystemTime, err := tree.EvalAsOf(ctx, asOf, &p.semaCtx, p.EvalContext(), allowBoundedStaleness)
 	if err != nil {
-		return hlc.Timestamp{}, err
+		return tree.AsOfSystemTime{}, err
 	}
-	if now := p.execCfg.Clock.Now(); now.Less(ts) {
-		return hlc.Timestamp{}, errors.Errorf(
-			"AS OF SYSTEM TIME: cannot specify timestamp in the future (%s > %s)", ts, now)
+	if now := p.execCfg.Clock.Now(); now.Less(asOfSystemTime.Timestamp) {
+		return tree.AsOfSystemTime{}, errors.Errorf(
+			"AS OF SYSTEM TIME: cannot specify timestamp in the future (%s > %s)",
+			asOfSystemTime.Timestamp, now)
 	}
-	return ts, nil
+	return asOfSystemTime, nil
 }
 
 // ParseHLC parses a string representation of an `hlc.Timestamp`.
@@ -1115,36 +1164,34 @@ func ParseHLC(s string) (hlc.Timestamp, error) {
 
 // isAsOf analyzes a statement to bypass the logic in newPlan(), since
 // that requires the transaction to be started already. If the returned
-// timestamp is not nil, it is the timestamp to which a transaction
-// should be set. The statements that will be checked are Select,
-// ShowTrace (of a Select statement), Scrub, Export, and CreateStats.
-func (p *planner) isAsOf(ctx context.Context, stmt tree.Statement) (*hlc.Timestamp, error) {
+// AS OF SYSTEM TIME clause is not nil, the transaction should be set to its
+// timestamp, or to the timestamp negotiated for it if it specifies a bounded
+// staleness read. The statements that will be checked are Select, ShowTrace
+// (of a Select statement), Scrub, Export, and CreateStats. Only Select
+// statements can be bounded staleness reads.
+func (p *planner) isAsOf(ctx context.Context, stmt tree.Statement) (*tree.AsOfSystemTime, error) {
 	var asOf tree.AsOfClause
+	allowBoundedStaleness := false
 	switch s := stmt.(type) {
 	case *tree.Select:
-		selStmt := s.Select
-		var parenSel *tree.ParenSelect
-		var ok bool
-		for parenSel, ok = selStmt.(*tree.ParenSelect); ok; parenSel, ok = selStmt.(*tree.ParenSelect) {
-			selStmt = parenSel.Select.Select
-		}
-
-		sc, ok := selStmt.(*tree.SelectClause)
-		if !ok {
-			return nil, nil
-		}
-		if sc.From.AsOf.Expr == nil {
+		sc := selectClauseForAsOf(s)
+		if sc == nil || sc.From.AsOf.Expr == nil {
 			return nil, nil
 		}
-
 		asOf = sc.From.AsOf
+		allowBoundedStaleness = true
 	case *tree.Scrub:
 		if s.AsOf.Expr == nil {
 			return nil, nil
 		}
 		asOf = s.AsOf
 	case *tree.Export:
-		return p.isAsOf(ctx, s.Query)
+		asOfSystemTime, err := p.isAsOf(ctx, s.Query)
+		if err == nil && asOfSystemTime != nil && asOfSystemTime.BoundedStaleness {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"AS OF SYSTEM TIME: bounded staleness reads cannot be exported")
+		}
+		return asOfSystemTime, err
 	case *tree.CreateStats:
 		if s.Options.AsOf.Expr == nil {
 			return nil, nil
@@ -1155,8 +1202,21 @@ func (p *planner) isAsOf(ctx context.Context, stmt tree.Statement) (*hlc.Timesta
 	default:
 		return nil, nil
 	}
-	ts, err := p.EvalAsOfTimestamp(ctx, asOf)
-	return &ts, err
+	asOfSystemTime, err := p.evalAsOf(ctx, asOf, allowBoundedStaleness)
+	return &asOfSystemTime, err
+}
+
+// selectClauseForAsOf returns the SelectClause whose AS OF SYSTEM TIME clause
+// applies to the given statement, or nil if there is none.
+func selectClauseForAsOf(s *tree.Select) *tree.SelectClause {
+	selStmt := s.Select
+	var parenSel *tree.ParenSelect
+	var ok bool
+	for parenSel, ok = selStmt.(*tree.ParenSelect); ok; parenSel, ok = selStmt.(*tree.ParenSelect) {
+		selStmt = parenSel.Select.Select
+	}
+	sc, _ := selStmt.(*tree.SelectClause)
+	return sc
 }
 
 // isSavepoint returns true if stmt is a SAVEPOINT statement.
@@ -2029,6 +2089,10 @@ func (m *sessionDataMutator) SetDefaultTransactionPriority(val tree.UserPriority
 	m.data.DefaultTxnPriority = int(val)
 }
 
+func (m *sessionDataMutator) SetDefaultTransactionIsolationLevel(val tree.IsolationLevel) {
+	m.data.DefaultTxnIsolationLevel = int(val)
+}
+
 func (m *sessionDataMutator) SetDefaultReadOnly(val bool) {
 	m.data.DefaultReadOnly = val
 }
@@ -2099,6 +2163,10 @@ func (m *sessionDataMutator) SetImplicitSelectForUpdate(val bool) {
 	m.data.ImplicitSelectForUpdate = val
 }
 
+func (m *sessionDataMutator) SetSharedLockingForFKChecks(val bool) {
+	m.data.SharedLockingForFKChecks = val
+}
+
 func (m *sessionDataMutator) SetInsertFastPath(val bool) {
 	m.data.InsertFastPath = val
 }
@@ -2144,6 +2212,10 @@ func (m *sessionDataMutator) SetIdleInSessionTimeout(timeout time.Duration) {
 	m.data.IdleInSessionTimeout = timeout
 }
 
+func (m *sessionDataMutator) SetTraceParent(traceParent string) {
+	m.data.TraceParent = traceParent
+}
+
 func (m *sessionDataMutator) SetIdleInTransactionSessionTimeout(timeout time.Duration) {
 	m.data.IdleInTransactionSessionTimeout = timeout
 }
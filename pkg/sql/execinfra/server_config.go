@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -74,6 +75,10 @@ type ServerConfig struct {
 	Stopper      *stop.Stopper
 	TestingKnobs TestingKnobs
 
+	// SQLAdmissionQ, if set, is used to subject the setup of flows to admission
+	// control.
+	SQLAdmissionQ *admission.WorkQueue
+
 	// ParentMemoryMonitor is normally the root SQL monitor. It should only be
 	// used when setting up a server, or in tests.
 	ParentMemoryMonitor *mon.BytesMonitor
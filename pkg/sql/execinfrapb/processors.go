@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/errors"
 )
 
@@ -497,3 +498,81 @@ func (spec *WindowerSpec_Frame) ConvertToAST() (*tree.WindowFrame, error) {
 		Exclusion: exclusion,
 	}, nil
 }
+
+// convertOffsetToDatum returns the offset of the given frame bound as a datum.
+// ROWS and GROUPS modes use integer offsets whereas RANGE mode uses an offset
+// of a type that depends on the type of the ordering column.
+func (spec WindowerSpec_Frame_Bound) convertOffsetToDatum(
+	mode WindowerSpec_Frame_Mode, datumAlloc *rowenc.DatumAlloc,
+) (tree.Datum, error) {
+	switch mode {
+	case WindowerSpec_Frame_ROWS, WindowerSpec_Frame_GROUPS:
+		return tree.NewDInt(tree.DInt(int(spec.IntOffset))), nil
+	case WindowerSpec_Frame_RANGE:
+		datum, rem, err := rowenc.DecodeTableValue(datumAlloc, spec.OffsetType.Type, spec.TypedOffset)
+		if err != nil {
+			return nil, errors.NewAssertionErrorWithWrappedErrf(err,
+				"error decoding %d bytes", errors.Safe(len(spec.TypedOffset)))
+		}
+		if len(rem) != 0 {
+			return nil, errors.AssertionFailedf(
+				"%d trailing bytes in encoded value", errors.Safe(len(rem)))
+		}
+		return datum, nil
+	default:
+		return nil, errors.AssertionFailedf("unexpected WindowFrameMode: %d", errors.Safe(mode))
+	}
+}
+
+// InitFrameRun populates the frame related fields of frameRun (the frame
+// itself, the offsets of its bounds and, for RANGE mode with offsets, the
+// information about the ordering column) based on the frame specification.
+// ordering is the ordering of the window function and inputTypes are the
+// types of the rows the frame is run over.
+func (spec *WindowerSpec_Frame) InitFrameRun(
+	frameRun *tree.WindowFrameRun,
+	ordering Ordering,
+	inputTypes []*types.T,
+	datumAlloc *rowenc.DatumAlloc,
+) error {
+	var err error
+	if frameRun.Frame, err = spec.ConvertToAST(); err != nil {
+		return err
+	}
+	isOffsetBound := func(bt WindowerSpec_Frame_BoundType) bool {
+		return bt == WindowerSpec_Frame_OFFSET_PRECEDING || bt == WindowerSpec_Frame_OFFSET_FOLLOWING
+	}
+	startBound, endBound := spec.Bounds.Start, spec.Bounds.End
+	if isOffsetBound(startBound.BoundType) {
+		if frameRun.StartBoundOffset, err = startBound.convertOffsetToDatum(spec.Mode, datumAlloc); err != nil {
+			return err
+		}
+	}
+	if endBound != nil && isOffsetBound(endBound.BoundType) {
+		if frameRun.EndBoundOffset, err = endBound.convertOffsetToDatum(spec.Mode, datumAlloc); err != nil {
+			return err
+		}
+	}
+	if frameRun.RangeModeWithOffsets() {
+		ordCol := ordering.Columns[0]
+		frameRun.OrdColIdx = int(ordCol.ColIdx)
+		// We need this +1 because encoding.Direction has extra value "_"
+		// as zeroth "entry" which its proto equivalent doesn't have.
+		frameRun.OrdDirection = encoding.Direction(ordCol.Direction + 1)
+
+		colTyp := inputTypes[ordCol.ColIdx]
+		// Type of offset depends on the ordering column's type.
+		offsetTyp := colTyp
+		if types.IsDateTimeType(colTyp) {
+			// For datetime related ordering columns, offset must be an Interval.
+			offsetTyp = types.Interval
+		}
+		plusOp, minusOp, found := tree.WindowFrameRangeOps{}.LookupImpl(colTyp, offsetTyp)
+		if !found {
+			return pgerror.Newf(pgcode.Windowing,
+				"given logical offset cannot be combined with ordering column")
+		}
+		frameRun.PlusOp, frameRun.MinusOp = plusOp, minusOp
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 )
 
 // SQL execution is separated in 3+ phases:
@@ -188,11 +189,11 @@ func (ex *connExecutor) recordStatementSummary(
 			if _, ok := stmt.AST.(*tree.Select); ok {
 				m.DistSQLSelectCount.Inc(1)
 			}
-			m.DistSQLExecLatency.RecordValue(runLatRaw.Nanoseconds())
-			m.DistSQLServiceLatency.RecordValue(svcLatRaw.Nanoseconds())
+			recordLatency(ctx, m.DistSQLExecLatency, runLatRaw)
+			recordLatency(ctx, m.DistSQLServiceLatency, svcLatRaw)
 		}
-		m.SQLExecLatency.RecordValue(runLatRaw.Nanoseconds())
-		m.SQLServiceLatency.RecordValue(svcLatRaw.Nanoseconds())
+		recordLatency(ctx, m.SQLExecLatency, runLatRaw)
+		recordLatency(ctx, m.SQLServiceLatency, svcLatRaw)
 	}
 
 	stmtID := ex.statsCollector.recordStatement(
@@ -251,3 +252,13 @@ func (ex *connExecutor) updateOptCounters(planFlags planFlags) {
 		m.SQLOptPlanCacheMisses.Inc(1)
 	}
 }
+
+// recordLatency records the latency in the histogram. If the statement was
+// traced by an exported trace, the trace is recorded as an exemplar.
+func recordLatency(ctx context.Context, h *metric.Histogram, d time.Duration) {
+	if traceID, ok := tracing.ExportedTraceID(ctx); ok {
+		h.RecordValueWithExemplar(d.Nanoseconds(), traceID)
+	} else {
+		h.RecordValue(d.Nanoseconds())
+	}
+}
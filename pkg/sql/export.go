@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -37,9 +38,17 @@ type exportNode struct {
 	// fileNamePattern represents the file naming pattern for the
 	// export, typically to be appended to the destination URI
 	fileNamePattern string
+	// fileFormat is the format of the exported files, either CSV or PARQUET.
+	fileFormat      string
 	csvOpts         roachpb.CSVOptions
 	chunkRows       int
 	fileCompression execinfrapb.FileCompression
+	// parquetCompression is the compression codec used for the column chunks
+	// of the exported Parquet files.
+	parquetCompression execinfrapb.ParquetWriterSpec_Compression
+	// rowGroupSize is the approximate size, in bytes, of the row groups of the
+	// exported Parquet files.
+	rowGroupSize int64
 }
 
 func (e *exportNode) startExec(params runParams) error {
@@ -59,26 +68,50 @@ func (e *exportNode) Close(ctx context.Context) {
 }
 
 const (
-	exportOptionDelimiter   = "delimiter"
-	exportOptionNullAs      = "nullas"
-	exportOptionChunkRows   = "chunk_rows"
-	exportOptionFileName    = "filename"
-	exportOptionCompression = "compression"
+	exportOptionDelimiter    = "delimiter"
+	exportOptionNullAs       = "nullas"
+	exportOptionChunkRows    = "chunk_rows"
+	exportOptionFileName     = "filename"
+	exportOptionCompression  = "compression"
+	exportOptionRowGroupSize = "row_group_size"
 )
 
 var exportOptionExpectValues = map[string]KVStringOptValidate{
-	exportOptionChunkRows:   KVStringOptRequireValue,
-	exportOptionDelimiter:   KVStringOptRequireValue,
-	exportOptionFileName:    KVStringOptRequireValue,
-	exportOptionNullAs:      KVStringOptRequireValue,
-	exportOptionCompression: KVStringOptRequireValue,
+	exportOptionChunkRows:    KVStringOptRequireValue,
+	exportOptionDelimiter:    KVStringOptRequireValue,
+	exportOptionFileName:     KVStringOptRequireValue,
+	exportOptionNullAs:       KVStringOptRequireValue,
+	exportOptionCompression:  KVStringOptRequireValue,
+	exportOptionRowGroupSize: KVStringOptRequireValue,
+}
+
+const (
+	exportFormatCSV     = "CSV"
+	exportFormatParquet = "PARQUET"
+)
+
+// exportFormatOptions lists, for each export format, the options which are
+// specific to that format.
+var exportFormatOptions = map[string][]string{
+	exportFormatCSV:     {exportOptionDelimiter, exportOptionNullAs},
+	exportFormatParquet: {exportOptionRowGroupSize},
 }
 
 const exportChunkRowsDefault = 100000
+const exportRowGroupSizeDefault = 128 << 20
 const exportFilePatternPart = "%part%"
-const exportFilePatternDefault = exportFilePatternPart + ".csv"
+const exportFilePatternCSV = exportFilePatternPart + ".csv"
+const exportFilePatternParquet = exportFilePatternPart + ".parquet"
 const exportCompressionCodec = "gzip"
 
+// exportParquetCompressionCodecs maps the compression codecs supported for
+// Parquet exports to their spec representation.
+var exportParquetCompressionCodecs = map[string]execinfrapb.ParquetWriterSpec_Compression{
+	"none":   execinfrapb.ParquetWriterSpec_NONE,
+	"gzip":   execinfrapb.ParquetWriterSpec_GZIP,
+	"snappy": execinfrapb.ParquetWriterSpec_SNAPPY,
+}
+
 // ConstructExport is part of the exec.Factory interface.
 func (ef *execFactory) ConstructExport(
 	input exec.Node, fileName tree.TypedExpr, fileFormat string, options []exec.KVOption,
@@ -87,7 +120,7 @@ func (ef *execFactory) ConstructExport(
 		return nil, errors.Errorf("EXPORT cannot be used inside a transaction")
 	}
 
-	if fileFormat != "CSV" {
+	if _, ok := exportFormatOptions[fileFormat]; !ok {
 		return nil, errors.Errorf("unsupported export format: %q", fileFormat)
 	}
 
@@ -105,6 +138,17 @@ func (ef *execFactory) ConstructExport(
 	if err != nil {
 		return nil, err
 	}
+	for format, formatOpts := range exportFormatOptions {
+		if format == fileFormat {
+			continue
+		}
+		for _, opt := range formatOpts {
+			if _, ok := optVals[opt]; ok {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s option is only supported for %s exports", opt, format)
+			}
+		}
+	}
 
 	csvOpts := roachpb.CSVOptions{}
 
@@ -133,24 +177,50 @@ func (ef *execFactory) ConstructExport(
 	// Check whenever compression is expected and extract compression codec name in case
 	// of positive result
 	var codec execinfrapb.FileCompression
+	var parquetCodec execinfrapb.ParquetWriterSpec_Compression
 	if name, ok := optVals[exportOptionCompression]; ok && len(name) != 0 {
-		if strings.EqualFold(name, exportCompressionCodec) {
+		switch fileFormat {
+		case exportFormatCSV:
+			if !strings.EqualFold(name, exportCompressionCodec) {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"unsupported compression codec %s", name)
+			}
 			codec = execinfrapb.FileCompression_Gzip
-		} else {
-			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
-				"unsupported compression codec %s", name)
+		case exportFormatParquet:
+			if parquetCodec, ok = exportParquetCompressionCodecs[strings.ToLower(name)]; !ok {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"unsupported compression codec %s", name)
+			}
+		}
+	}
+
+	rowGroupSize := int64(exportRowGroupSizeDefault)
+	if override, ok := optVals[exportOptionRowGroupSize]; ok {
+		rowGroupSize, err = humanizeutil.ParseBytes(override)
+		if err != nil {
+			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+		}
+		if rowGroupSize < 1 {
+			return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid parquet row group size")
 		}
 	}
 
+	filePattern := exportFilePatternCSV
+	if fileFormat == exportFormatParquet {
+		filePattern = exportFilePatternParquet
+	}
 	exportID := ef.planner.stmt.queryID.String()
-	namePattern := fmt.Sprintf("export%s-%s", exportID, exportFilePatternDefault)
+	namePattern := fmt.Sprintf("export%s-%s", exportID, filePattern)
 
 	return &exportNode{
-		source:          input.(planNode),
-		destination:     string(*destination),
-		fileNamePattern: namePattern,
-		csvOpts:         csvOpts,
-		chunkRows:       chunkRows,
-		fileCompression: codec,
+		source:             input.(planNode),
+		destination:        string(*destination),
+		fileNamePattern:    namePattern,
+		fileFormat:         fileFormat,
+		csvOpts:            csvOpts,
+		chunkRows:          chunkRows,
+		fileCompression:    codec,
+		parquetCompression: parquetCodec,
+		rowGroupSize:       rowGroupSize,
 	}, nil
 }
@@ -130,6 +130,14 @@ func (r schemaChangeGCResumer) Resume(
 			return ctx.Err()
 		}
 
+		// Delete the data of newly dropped tables with MVCC range tombstones
+		// rather than waiting for their GC TTL to expire.
+		if details.Tables != nil {
+			if err := deleteDroppedTablesData(ctx, execCfg, r.jobID, progress); err != nil {
+				return err
+			}
+		}
+
 		// Refresh the status of all tables in case any GC TTLs have changed.
 		remainingTables := getAllTablesWaitingForGC(details, progress)
 		expired, earliestDeadline := refreshTables(ctx, execCfg, remainingTables, tableDropTimes, indexDropTimes, r.jobID, progress)
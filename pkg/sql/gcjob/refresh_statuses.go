@@ -165,6 +165,20 @@ func updateTableStatus(
 		}
 
 		lifetime := timeutil.Until(deadline)
+		if lifetime < 0 && !droppedTable.RangeTombstoneTimestamp.IsEmpty() {
+			// The table data was deleted with MVCC range tombstones, so the table
+			// can be deleted once MVCC GC has removed the data.
+			gced, err := isTableDataGarbageCollected(ctx, execCfg, table, droppedTable.RangeTombstoneTimestamp)
+			if err != nil {
+				log.Warningf(ctx, "error while checking whether the data of table %d was GC'd: %+v", t.ID, err)
+			}
+			if !gced {
+				if log.V(2) {
+					log.Infof(ctx, "table %d is waiting for MVCC GC", t.ID)
+				}
+				return timeutil.Now().Add(MaxSQLGCInterval)
+			}
+		}
 		if lifetime < 0 {
 			if log.V(2) {
 				log.Infof(ctx, "detected expired table %d", t.ID)
@@ -14,15 +14,18 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
@@ -65,9 +68,15 @@ func gcTables(
 			continue
 		}
 
-		// First, delete all the table data.
-		if err := ClearTableData(ctx, execCfg.DB, execCfg.DistSender, execCfg.Codec, table); err != nil {
-			return errors.Wrapf(err, "clearing data for table %d", table.ID)
+		// First, delete all the table data, unless it was deleted with MVCC
+		// range tombstones when the table was dropped. MVCC GC has removed that
+		// data by now (see updateTableStatus).
+		if droppedTable.RangeTombstoneTimestamp.IsEmpty() {
+			if err := ClearTableData(
+				ctx, execCfg.DB, execCfg.DistSender, execCfg.Codec, execCfg.Settings, table,
+			); err != nil {
+				return errors.Wrapf(err, "clearing data for table %d", table.ID)
+			}
 		}
 
 		// Finished deleting all the table data, now delete the table meta data.
@@ -81,12 +90,109 @@ func gcTables(
 	return nil
 }
 
+// deleteDroppedTablesData deletes the data of the dropped tables which are
+// waiting for their GC TTL to expire by writing MVCC range tombstones, once
+// VersionMVCCRangeTombstones is active. The deleted data remains visible to AS
+// OF SYSTEM TIME queries, rangefeeds and incremental backups, and is removed
+// by MVCC GC once the TTL has expired, rather than being cleared by the job
+// after it has.
+func deleteDroppedTablesData(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	jobID int64,
+	progress *jobspb.SchemaChangeGCProgress,
+) error {
+	if !execCfg.Settings.Version.IsActive(ctx, clusterversion.VersionMVCCRangeTombstones) {
+		return nil
+	}
+	for i := range progress.Tables {
+		droppedTable := &progress.Tables[i]
+		if droppedTable.Status != jobspb.SchemaChangeGCProgress_WAITING_FOR_GC ||
+			!droppedTable.RangeTombstoneTimestamp.IsEmpty() {
+			continue
+		}
+
+		var table *tabledesc.Immutable
+		if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			var err error
+			table, err = catalogkv.MustGetTableDescByID(ctx, txn, execCfg.Codec, droppedTable.ID)
+			return err
+		}); err != nil {
+			if errors.Is(err, catalog.ErrDescriptorNotFound) {
+				// The table will be marked as GC'd when its status is refreshed.
+				continue
+			}
+			return errors.Wrapf(err, "fetching table %d", droppedTable.ID)
+		}
+		// Interleaved tables and tables dropped by a version 1.1 server are
+		// cleared in chunks by the legacy code in ClearTableData.
+		if !table.Dropped() || table.DropTime == 0 || table.IsInterleaved() {
+			continue
+		}
+
+		// The table descriptor was read in a transaction which observed the
+		// drop, so the clock is above the timestamps of all of the table data.
+		ts := execCfg.Clock.Now()
+		log.Infof(ctx, "deleting data for table %d with MVCC range tombstones at %s", table.ID, ts)
+		if err := clearTableData(ctx, execCfg.DB, execCfg.DistSender, execCfg.Codec, table, ts); err != nil {
+			return errors.Wrapf(err, "deleting data for table %d", table.ID)
+		}
+		droppedTable.RangeTombstoneTimestamp = ts
+		persistProgress(ctx, execCfg, jobID, progress)
+	}
+	return nil
+}
+
+// isTableDataGarbageCollected returns whether MVCC GC has removed the data of
+// the table which was deleted by MVCC range tombstones at or above the given
+// timestamp. It reads each of the table's ranges just below the timestamp,
+// which fails once the range's GC threshold has passed the range tombstones
+// and finds no keys if the range holds no table data.
+func isTableDataGarbageCollected(
+	ctx context.Context, execCfg *sql.ExecutorConfig, table *tabledesc.Immutable, ts hlc.Timestamp,
+) (bool, error) {
+	tableKey := roachpb.RKey(execCfg.Codec.TablePrefix(uint32(table.ID)))
+	tableSpan := roachpb.RSpan{Key: tableKey, EndKey: tableKey.PrefixEnd()}
+	ri := kvcoord.NewRangeIterator(execCfg.DistSender)
+	for ri.Seek(ctx, tableSpan.Key, kvcoord.Ascending); ; ri.Next(ctx) {
+		if !ri.Valid() {
+			return false, ri.Error()
+		}
+		span, err := tableSpan.Intersect(ri.Desc())
+		if err != nil {
+			return false, err
+		}
+		var b kv.Batch
+		b.Header.Timestamp = ts.Prev()
+		b.Header.MaxSpanRequestKeys = 1
+		b.AddRawRequest(&roachpb.ScanRequest{
+			RequestHeader: roachpb.RequestHeader{Key: span.Key.AsRawKey(), EndKey: span.EndKey.AsRawKey()},
+		})
+		if err := execCfg.DB.Run(ctx, &b); err != nil {
+			if !errors.HasType(err, (*roachpb.BatchTimestampBeforeGCError)(nil)) {
+				return false, err
+			}
+		} else if len(b.RawResponse().Responses[0].GetScan().Rows) > 0 {
+			return false, nil
+		}
+		if !ri.NeedAnother(tableSpan) {
+			return true, nil
+		}
+	}
+}
+
 // ClearTableData deletes all of the data in the specified table.
+//
+// Once VersionMVCCRangeTombstones is active, the data is deleted by writing
+// MVCC range tombstones rather than by clearing it, so the deletion remains
+// visible to AS OF SYSTEM TIME queries, rangefeeds and incremental backups
+// until the range tombstones are garbage collected.
 func ClearTableData(
 	ctx context.Context,
 	db *kv.DB,
 	distSender *kvcoord.DistSender,
 	codec keys.SQLCodec,
+	settings *cluster.Settings,
 	table *tabledesc.Immutable,
 ) error {
 	// If DropTime isn't set, assume this drop request is from a version
@@ -100,6 +206,25 @@ func ClearTableData(
 	}
 	log.Infof(ctx, "clearing data for table %d", table.ID)
 
+	var rangeTombstoneTS hlc.Timestamp
+	if settings.Version.IsActive(ctx, clusterversion.VersionMVCCRangeTombstones) {
+		rangeTombstoneTS = db.Clock().Now()
+	}
+	return clearTableData(ctx, db, distSender, codec, table, rangeTombstoneTS)
+}
+
+// clearTableData deletes all of the data in the specified table, which must
+// not be interleaved. If rangeTombstoneTS is set, the data is deleted by
+// writing MVCC range tombstones at or above it, otherwise it is cleared.
+func clearTableData(
+	ctx context.Context,
+	db *kv.DB,
+	distSender *kvcoord.DistSender,
+	codec keys.SQLCodec,
+	table *tabledesc.Immutable,
+	rangeTombstoneTS hlc.Timestamp,
+) error {
+
 	tableKey := roachpb.RKey(codec.TablePrefix(uint32(table.ID)))
 	tableSpan := roachpb.RSpan{Key: tableKey, EndKey: tableKey.PrefixEnd()}
 
@@ -143,13 +268,21 @@ func ClearTableData(
 				endKey = tableSpan.EndKey
 			}
 			var b kv.Batch
-			b.AddRawRequest(&roachpb.ClearRangeRequest{
-				RequestHeader: roachpb.RequestHeader{
-					Key:    lastKey.AsRawKey(),
-					EndKey: endKey.AsRawKey(),
-				},
-			})
-			log.VEventf(ctx, 2, "ClearRange %s - %s", lastKey, endKey)
+			header := roachpb.RequestHeader{
+				Key:    lastKey.AsRawKey(),
+				EndKey: endKey.AsRawKey(),
+			}
+			if !rangeTombstoneTS.IsEmpty() {
+				b.Header.Timestamp = rangeTombstoneTS
+				b.AddRawRequest(&roachpb.DeleteRangeRequest{
+					RequestHeader:     header,
+					UseRangeTombstone: true,
+				})
+				log.VEventf(ctx, 2, "DeleteRange %s - %s", lastKey, endKey)
+			} else {
+				b.AddRawRequest(&roachpb.ClearRangeRequest{RequestHeader: header})
+				log.VEventf(ctx, 2, "ClearRange %s - %s", lastKey, endKey)
+			}
 			if err := db.Run(ctx, &b); err != nil {
 				return errors.Wrapf(err, "clear range %s - %s", lastKey, endKey)
 			}
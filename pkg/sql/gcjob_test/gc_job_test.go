@@ -22,10 +22,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/gcjob"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
@@ -307,3 +311,124 @@ SELECT job_id, status
 		return nil
 	})
 }
+
+// TestSchemaChangeGCJobWritesRangeTombstones ensures that once MVCC range
+// tombstones are enabled, the data of a dropped table is deleted as soon as
+// the table is dropped, remains visible to AS OF SYSTEM TIME queries and to the
+// catch-up scan of a rangefeed, and that the GC job waits for MVCC GC to remove
+// it before completing.
+func TestSchemaChangeGCJobWritesRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer jobs.TestingSetAdoptAndCancelIntervals(100*time.Millisecond, 100*time.Millisecond)()
+	defer gcjob.SetSmallMaxGCIntervalForTest()()
+
+	s, db, kvDB := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			// Prevent the MVCC GC queue from removing the deleted data until the
+			// test runs it.
+			Store: &kvserver.StoreTestingKnobs{DisableGCQueue: true},
+		},
+	})
+	ctx := context.Background()
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, "SET CLUSTER SETTING kv.range_merge.queue_enabled = false")
+	sqlDB.Exec(t, "SET CLUSTER SETTING kv.rangefeed.enabled = true")
+	sqlDB.Exec(t, "CREATE DATABASE db")
+	sqlDB.Exec(t, "CREATE TABLE db.foo (k INT PRIMARY KEY)")
+	sqlDB.Exec(t, "INSERT INTO db.foo SELECT generate_series(1, 10)")
+	sqlDB.Exec(t, "ALTER TABLE db.foo CONFIGURE ZONE USING gc.ttlseconds = 1")
+
+	var tableID descpb.ID
+	sqlDB.QueryRow(t, `
+SELECT table_id
+  FROM crdb_internal.tables
+ WHERE database_name = $1 AND name = $2;
+`, "db", "foo").Scan(&tableID)
+	tableSpan := roachpb.Span{
+		Key:    keys.SystemSQLCodec.TablePrefix(uint32(tableID)),
+		EndKey: keys.SystemSQLCodec.TablePrefix(uint32(tableID)).PrefixEnd(),
+	}
+
+	beforeDrop := s.Clock().Now()
+	sqlDB.Exec(t, "DROP TABLE db.foo")
+
+	var jobID int64
+	sqlDB.QueryRow(t, `
+SELECT job_id
+  FROM crdb_internal.jobs
+ WHERE description LIKE 'GC for DROP TABLE db.public.foo';
+`).Scan(&jobID)
+
+	// The table data is deleted without waiting for the GC TTL to expire.
+	testutils.SucceedsSoon(t, func() error {
+		kvs, err := kvDB.Scan(ctx, tableSpan.Key, tableSpan.EndKey, 0 /* maxRows */)
+		if err != nil {
+			return err
+		}
+		if len(kvs) > 0 {
+			return errors.Errorf("table data not yet deleted: %d keys remain", len(kvs))
+		}
+		return nil
+	})
+	afterDelete := s.Clock().Now()
+
+	// The table data is still visible before the drop.
+	sqlDB.CheckQueryResults(t,
+		fmt.Sprintf("SELECT count(*) FROM db.foo AS OF SYSTEM TIME %s", beforeDrop.AsOfSystemTime()),
+		[][]string{{"10"}},
+	)
+
+	// A rangefeed started before the drop sees the deletion of every row in its
+	// catch-up scan.
+	ds := s.DistSenderI().(*kvcoord.DistSender)
+	evChan := make(chan *roachpb.RangeFeedEvent)
+	rangefeedErrChan := make(chan error, 1)
+	ctxToCancel, cancel := context.WithCancel(ctx)
+	go func() {
+		rangefeedErrChan <- ds.RangeFeed(ctxToCancel, tableSpan, beforeDrop, false /* withDiff */, evChan)
+	}()
+	deleted := make(map[string]struct{})
+	for done := false; !done; {
+		select {
+		case ev := <-evChan:
+			if ev.Val != nil && !ev.Val.Value.IsPresent() {
+				deleted[string(ev.Val.Key)] = struct{}{}
+			}
+			if ev.Checkpoint != nil && afterDelete.Less(ev.Checkpoint.ResolvedTS) {
+				done = true
+			}
+		case err := <-rangefeedErrChan:
+			t.Fatalf("rangefeed failed: %v", err)
+		}
+	}
+	cancel()
+	require.Error(t, <-rangefeedErrChan)
+	require.Len(t, deleted, 10)
+
+	// The GC TTL has expired by now, but the job waits for MVCC GC to remove
+	// the data before deleting the table.
+	var status jobs.Status
+	sqlDB.QueryRow(t, "SELECT status FROM [SHOW JOB $1]", jobID).Scan(&status)
+	require.Equal(t, jobs.StatusRunning, status)
+
+	store, err := s.GetStores().(*kvserver.Stores).GetStore(s.GetFirstStoreID())
+	require.NoError(t, err)
+	testutils.SucceedsSoon(t, func() error {
+		repl := store.LookupReplica(roachpb.RKey(tableSpan.Key))
+		if _, processErr, err := store.ManuallyEnqueue(
+			ctx, "gc", repl, true, /* skipShouldQueue */
+		); err != nil {
+			return err
+		} else if processErr != nil {
+			return processErr
+		}
+		sqlDB.QueryRow(t, "SELECT status FROM [SHOW JOB $1]", jobID).Scan(&status)
+		if status != jobs.StatusSucceeded {
+			return errors.Errorf("job status %v != %v", status, jobs.StatusSucceeded)
+		}
+		return nil
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 )
 
@@ -65,8 +66,10 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 		changePrivilege: func(privDesc *descpb.PrivilegeDescriptor, grantee string) {
 			privDesc.Grant(grantee, n.Privileges)
 		},
-		grantOn:      grantOn,
-		eventLogType: EventLogGrantPrivilege,
+		grantOn: grantOn,
+		makeEvent: func(targets, grantees, privileges string) eventpb.EventPayload {
+			return &eventpb.GrantPrivilege{Target: targets, Grantees: grantees, Privileges: privileges}
+		},
 	}, nil
 }
 
@@ -107,8 +110,10 @@ func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error)
 		changePrivilege: func(privDesc *descpb.PrivilegeDescriptor, grantee string) {
 			privDesc.Revoke(grantee, n.Privileges, grantOn)
 		},
-		grantOn:      grantOn,
-		eventLogType: EventLogRevokePrivilege,
+		grantOn: grantOn,
+		makeEvent: func(targets, grantees, privileges string) eventpb.EventPayload {
+			return &eventpb.RevokePrivilege{Target: targets, Grantees: grantees, Privileges: privileges}
+		},
 	}, nil
 }
 
@@ -118,7 +123,7 @@ type changePrivilegesNode struct {
 	desiredprivs    privilege.List
 	changePrivilege func(*descpb.PrivilegeDescriptor, string)
 	grantOn         privilege.ObjectType
-	eventLogType    EventLogType
+	makeEvent       func(targets, grantees, privileges string) eventpb.EventPayload
 }
 
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
@@ -235,19 +240,9 @@ func (n *changePrivilegesNode) startExec(params runParams) error {
 	fmtCtx := tree.NewFmtCtx(tree.FmtSimple)
 	n.targets.Format(fmtCtx)
 	targets := fmtCtx.CloseAndGetString()
-	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
-		params.ctx,
-		params.p.txn,
-		n.eventLogType,
+	return p.logEvent(params.ctx,
 		0, /* no target */
-		int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
-		struct {
-			Target     string
-			User       string
-			Grantees   string
-			Privileges string
-		}{targets, p.SessionData().User, strings.Join(n.grantees.ToStrings(), ","), n.desiredprivs.String()},
-	)
+		n.makeEvent(targets, strings.Join(n.grantees.ToStrings(), ","), n.desiredprivs.String()))
 }
 
 func (*changePrivilegesNode) Next(runParams) (bool, error) { return false, nil }
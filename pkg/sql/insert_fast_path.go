@@ -14,6 +14,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -101,6 +102,7 @@ type insertFastPathFKCheck struct {
 	keyPrefix   []byte
 	colMap      map[descpb.ColumnID]int
 	spanBuilder *span.Builder
+	keyLocking  lock.Strength
 }
 
 func (c *insertFastPathFKCheck) init(params runParams) error {
@@ -111,6 +113,9 @@ func (c *insertFastPathFKCheck) init(params runParams) error {
 	codec := params.ExecCfg().Codec
 	c.keyPrefix = rowenc.MakeIndexKeyPrefix(codec, c.tabDesc, c.idxDesc.ID)
 	c.spanBuilder = span.MakeBuilder(codec, c.tabDesc, c.idxDesc)
+	if c.Locking != nil {
+		c.keyLocking = row.GetKeyLockingStrength(descpb.ToScanLockingStrength(c.Locking.Strength))
+	}
 
 	if len(c.InsertCols) > idx.numLaxKeyCols {
 		return errors.AssertionFailedf(
@@ -196,6 +201,7 @@ func (r *insertFastPathRun) addFKChecks(
 		r.fkBatch.Requests = append(r.fkBatch.Requests, roachpb.RequestUnion{})
 		r.fkBatch.Requests[reqIdx].MustSetInner(&roachpb.ScanRequest{
 			RequestHeader: roachpb.RequestHeaderFromSpan(span),
+			KeyLocking:    c.keyLocking,
 		})
 		r.fkSpanInfo = append(r.fkSpanInfo, insertFastPathFKSpanInfo{
 			check:  c,
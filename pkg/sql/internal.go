@@ -109,17 +109,22 @@ func (ie *InternalExecutor) SetSessionData(sessionData *sessiondata.SessionData)
 // If txn is not nil, the statement will be executed in the respective txn.
 //
 // sd will constitute the executor's session state.
+//
+// If rw is not nil, the rows of the statements are passed to it instead of
+// being buffered.
 func (ie *InternalExecutor) initConnEx(
 	ctx context.Context,
 	txn *kv.Txn,
 	sd *sessiondata.SessionData,
 	syncCallback func([]resWithPos),
 	errCallback func(error),
+	rw internalRowsWriter,
 ) (*StmtBuf, *sync.WaitGroup, error) {
 	clientComm := &internalClientComm{
 		sync: syncCallback,
 		// init lastDelivered below the position of the first result (0).
 		lastDelivered: -1,
+		rowsWriter:    rw,
 	}
 
 	// When the connEx is serving an internal executor, it can inherit the
@@ -361,6 +366,14 @@ func (ie *InternalExecutor) maybeRootSessionDataOverride(
 	return o
 }
 
+// internalRowsWriter receives the result columns, rows and error of a
+// statement run by the InternalExecutor as they are produced.
+type internalRowsWriter interface {
+	SetColumns(ctx context.Context, cols colinfo.ResultColumns)
+	AddRow(ctx context.Context, row tree.Datums) error
+	SetError(err error)
+}
+
 // execInternal executes a statement.
 //
 // sessionDataOverride can be used to control select fields in the executor's
@@ -373,6 +386,22 @@ func (ie *InternalExecutor) execInternal(
 	sessionDataOverride sessiondata.InternalExecutorOverride,
 	stmt string,
 	qargs ...interface{},
+) (retRes result, retErr error) {
+	return ie.execInternalWithRowsWriter(ctx, opName, txn, sessionDataOverride, nil /* rw */, stmt, qargs...)
+}
+
+// execInternalWithRowsWriter is like execInternal, but if rw is not nil, the
+// result columns, rows and error of the statement are passed to it as they
+// are produced instead of being buffered in the returned result. rw can
+// block in AddRow to suspend the execution of the statement.
+func (ie *InternalExecutor) execInternalWithRowsWriter(
+	ctx context.Context,
+	opName string,
+	txn *kv.Txn,
+	sessionDataOverride sessiondata.InternalExecutorOverride,
+	rw internalRowsWriter,
+	stmt string,
+	qargs ...interface{},
 ) (retRes result, retErr error) {
 	ctx = logtags.AddTag(ctx, "intExec", opName)
 
@@ -446,7 +475,7 @@ func (ie *InternalExecutor) execInternal(
 		}
 		resCh <- result{err: err}
 	}
-	stmtBuf, wg, err := ie.initConnEx(ctx, txn, sd, syncCallback, errCallback)
+	stmtBuf, wg, err := ie.initConnEx(ctx, txn, sd, syncCallback, errCallback, rw)
 	if err != nil {
 		return result{}, err
 	}
@@ -518,6 +547,10 @@ type internalClientComm struct {
 	// sync, if set, is called whenever a Sync is executed. It returns all the
 	// results since the previous Sync.
 	sync func([]resWithPos)
+
+	// rowsWriter, if set, receives the columns, rows and error of the statement
+	// results instead of them being buffered.
+	rowsWriter internalRowsWriter
 }
 
 type resWithPos struct {
@@ -536,7 +569,9 @@ func (icc *internalClientComm) CreateStatementResult(
 	_ string,
 	_ bool,
 ) CommandResult {
-	return icc.createRes(pos, nil /* onClose */)
+	res := icc.createRes(pos, nil /* onClose */)
+	res.rowsWriter = icc.rowsWriter
+	return res
 }
 
 // createRes creates a result. onClose, if not nil, is called when the result is
@@ -619,6 +654,13 @@ func (icc *internalClientComm) CreateCopyInResult(pos CmdPos) CopyInResult {
 	panic("unimplemented")
 }
 
+// CreateCopyOutResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateCopyOutResult(
+	pos CmdPos, conv sessiondata.DataConversionConfig,
+) CopyOutResult {
+	panic("unimplemented")
+}
+
 // CreateDrainResult is part of the ClientComm interface.
 func (icc *internalClientComm) CreateDrainResult(pos CmdPos) DrainResult {
 	panic("unimplemented")
@@ -452,6 +452,9 @@ type testClusterConfig struct {
 	// isCCLConfig should be true for any config that can only be run with a CCL
 	// binary.
 	isCCLConfig bool
+	// localities is set if nodes should be set to a particular locality.
+	// Nodes are 1-indexed.
+	localities map[int]roachpb.Locality
 }
 
 const threeNodeTenantConfigName = "3node-tenant"
@@ -634,6 +637,16 @@ var logicTestConfigs = []testClusterConfig{
 		useTenant:         true,
 		isCCLConfig:       true,
 	},
+	{
+		name:              "multiregion-3node-3region",
+		numNodes:          3,
+		overrideAutoStats: "false",
+		localities: map[int]roachpb.Locality{
+			1: {Tiers: []roachpb.Tier{{Key: "region", Value: "ap-southeast-2"}}},
+			2: {Tiers: []roachpb.Tier{{Key: "region", Value: "ca-central-1"}}},
+			3: {Tiers: []roachpb.Tier{{Key: "region", Value: "us-east-1"}}},
+		},
+	},
 }
 
 // An index in the above slice.
@@ -1317,6 +1330,20 @@ func (t *logicTest) setup(cfg testClusterConfig, serverArgs TestServerArgs) {
 		params.ServerArgsPerNode = paramsPerNode
 	}
 
+	if cfg.localities != nil {
+		if params.ServerArgsPerNode == nil {
+			params.ServerArgsPerNode = map[int]base.TestServerArgs{}
+		}
+		for i := 0; i < cfg.numNodes; i++ {
+			nodeParams, ok := params.ServerArgsPerNode[i]
+			if !ok {
+				nodeParams = params.ServerArgs
+			}
+			nodeParams.Locality = cfg.localities[i+1]
+			params.ServerArgsPerNode[i] = nodeParams
+		}
+	}
+
 	// Update the defaults for automatic statistics to avoid delays in testing.
 	// Avoid making the DefaultAsOfTime too small to avoid interacting with
 	// schema changes and causing transaction retries.
@@ -73,6 +73,8 @@ func buildOpaque(
 		plan, err = p.AlterSequence(ctx, n)
 	case *tree.Analyze:
 		plan, err = p.Analyze(ctx, n)
+	case *tree.CloseCursor:
+		plan, err = p.CloseCursor(ctx, n)
 	case *tree.CommentOnColumn:
 		plan, err = p.CommentOnColumn(ctx, n)
 	case *tree.CommentOnDatabase:
@@ -117,10 +119,14 @@ func buildOpaque(
 		plan, err = p.DropView(ctx, n)
 	case *tree.DropSequence:
 		plan, err = p.DropSequence(ctx, n)
+	case *tree.FetchCursor:
+		plan, err = p.FetchCursor(ctx, n)
 	case *tree.Grant:
 		plan, err = p.Grant(ctx, n)
 	case *tree.GrantRole:
 		plan, err = p.GrantRole(ctx, n)
+	case *tree.MoveCursor:
+		plan, err = p.MoveCursor(ctx, n)
 	case *tree.ReassignOwnedBy:
 		plan, err = p.ReassignOwnedBy(ctx, n)
 	case *tree.RefreshMaterializedView:
@@ -203,6 +209,7 @@ func init() {
 		&tree.AlterSequence{},
 		&tree.AlterRole{},
 		&tree.Analyze{},
+		&tree.CloseCursor{},
 		&tree.CommentOnColumn{},
 		&tree.CommentOnDatabase{},
 		&tree.CommentOnIndex{},
@@ -225,8 +232,10 @@ func init() {
 		&tree.DropView{},
 		&tree.DropRole{},
 		&tree.DropSequence{},
+		&tree.FetchCursor{},
 		&tree.Grant{},
 		&tree.GrantRole{},
+		&tree.MoveCursor{},
 		&tree.ReassignOwnedBy{},
 		&tree.RefreshMaterializedView{},
 		&tree.RenameColumn{},
@@ -72,11 +72,12 @@ type Builder struct {
 
 	allowInsertFastPath bool
 
-	// forceForUpdateLocking is conditionally passed through to factory methods
-	// for scan operators that serve as the input for mutation operators. When
-	// set to true, it ensures that a FOR UPDATE row-level locking mode is used
-	// by scans. See forUpdateLocking.
-	forceForUpdateLocking bool
+	// forceLocking is conditionally passed through to factory methods for scan
+	// and lookup join operators. When set, it ensures that the row-level
+	// locking mode is used by them. It is set to forUpdateLocking for scans
+	// that serve as the input for mutation operators, and to forShareLocking
+	// for the lookups of foreign key checks into the referenced table.
+	forceLocking *tree.LockingItem
 
 	// -- output --
 
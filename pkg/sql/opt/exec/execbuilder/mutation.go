@@ -15,6 +15,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -34,8 +35,8 @@ func (b *Builder) buildMutationInput(
 ) (execPlan, error) {
 	if b.shouldApplyImplicitLockingToMutationInput(mutExpr) {
 		// Re-entrance is not possible because mutations are never nested.
-		b.forceForUpdateLocking = true
-		defer func() { b.forceForUpdateLocking = false }()
+		b.forceLocking = forUpdateLocking
+		defer func() { b.forceLocking = nil }()
 	}
 
 	input, err := b.buildRelational(inputExpr)
@@ -194,6 +195,9 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		out.ReferencedTable = md.Table(lookupJoin.Table)
 		out.ReferencedIndex = out.ReferencedTable.Index(lookupJoin.Index)
 		out.MatchMethod = fk.MatchMethod()
+		if b.shouldApplySharedLockingToFKCheck(c) {
+			out.Locking = forShareLocking
+		}
 		out.MkErr = func(values tree.Datums) error {
 			if len(values) != len(out.InsertCols) {
 				return errors.AssertionFailedf("invalid FK violation values")
@@ -758,8 +762,13 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	md := b.mem.Metadata()
 	for i := range checks {
 		c := &checks[i]
+		if b.shouldApplySharedLockingToFKCheck(c) {
+			// Re-entrance is not possible because FK checks are never nested.
+			b.forceLocking = forShareLocking
+		}
 		// Construct the query that returns FK violations.
 		query, err := b.buildRelational(c.Check)
+		b.forceLocking = nil
 		if err != nil {
 			return err
 		}
@@ -941,6 +950,40 @@ func (b *Builder) canAutoCommit(rel memo.RelExpr) bool {
 // equivalent that used by a SELECT ... FOR UPDATE statement.
 var forUpdateLocking = &tree.LockingItem{Strength: tree.ForUpdate}
 
+// forShareLocking is the row-level locking mode used by foreign key checks
+// when looking up the referenced rows, when such locking is deemed desirable.
+// The locking mode is equivalent to that used by a SELECT ... FOR SHARE
+// statement.
+var forShareLocking = &tree.LockingItem{Strength: tree.ForShare}
+
+// shouldApplySharedLockingToFKCheck determines whether or not the builder
+// should apply a FOR SHARE row-level locking mode to the lookup of the
+// referenced row performed by a foreign key check. The Shared lock prevents
+// the referenced row from being deleted or updated by a concurrent transaction
+// until the checking transaction commits. FK checks are correct without it
+// under serializable isolation, but such a concurrent write would otherwise
+// force the checking transaction to retry.
+//
+// The locking mode is only applied to checks of outbound foreign keys (i.e.
+// for rows inserted or updated in the origin table) which are performed using
+// a lookup anti-join into the referenced table.
+func (b *Builder) shouldApplySharedLockingToFKCheck(c *memo.FKChecksItem) bool {
+	if !b.evalCtx.SessionData.SharedLockingForFKChecks || !c.FKOutbound ||
+		!b.sharedLockingEnabled() {
+		return false
+	}
+	lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
+	return isLookupJoin && lookupJoin.JoinType == opt.AntiJoinOp &&
+		lookupJoin.Table == c.ReferencedTable
+}
+
+// sharedLockingEnabled returns whether the cluster version permits the
+// acquisition of Shared locks. The check is performed here rather than in the
+// optbuilder because memos are cached across cluster version upgrades.
+func (b *Builder) sharedLockingEnabled() bool {
+	return b.evalCtx.Settings.Version.IsActive(b.evalCtx.Ctx(), clusterversion.VersionSharedLocks)
+}
+
 // shouldApplyImplicitLockingToMutationInput determines whether or not the
 // builder should apply a FOR UPDATE row-level locking mode to the initial row
 // scan of a mutation expression.
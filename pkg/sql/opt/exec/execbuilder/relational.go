@@ -499,8 +499,8 @@ func (b *Builder) scanParams(
 	}
 
 	locking := scan.Locking
-	if b.forceForUpdateLocking {
-		locking = forUpdateLocking
+	if b.forceLocking != nil {
+		locking = b.forceLocking
 	}
 
 	// Raise error if row-level locking is part of a read-only transaction.
@@ -509,6 +509,12 @@ func (b *Builder) scanParams(
 			"cannot execute %s in a read-only transaction", locking.Strength.String())
 	}
 
+	// FOR SHARE and FOR KEY SHARE remain no-ops until all nodes are able to
+	// acquire Shared locks.
+	if locking != nil && locking.Strength <= tree.ForShare && !b.sharedLockingEnabled() {
+		locking = nil
+	}
+
 	needed, outputMap := b.getColumns(scan.Cols, scan.Table)
 
 	// Get the estimated row count from the statistics.
@@ -1460,10 +1466,7 @@ func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
 	tab := md.Table(join.Table)
 	idx := tab.Index(join.Index)
 
-	var locking *tree.LockingItem
-	if b.forceForUpdateLocking {
-		locking = forUpdateLocking
-	}
+	locking := b.forceLocking
 
 	res.root, err = b.factory.ConstructLookupJoin(
 		joinOpToJoinType(join.JoinType),
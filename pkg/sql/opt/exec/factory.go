@@ -252,6 +252,10 @@ type InsertFastPathFKCheck struct {
 
 	MatchMethod tree.CompositeKeyMatchMethod
 
+	// Locking is the row-level locking mode used when looking up the referenced
+	// rows, or nil if no locking is used.
+	Locking *tree.LockingItem
+
 	// MkErr is called when a violation is detected (i.e. the index has no entries
 	// for a given inserted row). The values passed correspond to InsertCols
 	// above.
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -100,6 +101,12 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets contains the grouping columns of each grouping set if the
+	// GROUP BY clause contains GROUPING SETS, ROLLUP or CUBE. It is nil
+	// otherwise, in which case there is a single grouping set that contains all
+	// the grouping columns.
+	groupingSets []opt.ColSet
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -154,6 +161,17 @@ func (g *groupby) hasAggregates() bool {
 	return len(g.aggs) > 0
 }
 
+// hasGroupingFuncs returns true if any of the aggregates is a call to the
+// GROUPING function.
+func (g *groupby) hasGroupingFuncs() bool {
+	for i := range g.aggs {
+		if g.aggs[i].isGroupingFunc() {
+			return true
+		}
+	}
+	return false
+}
+
 // findAggregate finds the given aggregate among the bound variables
 // in this scope. Returns nil if the aggregate is not found.
 func (g *groupby) findAggregate(agg aggregateInfo) *scopeColumn {
@@ -259,6 +277,13 @@ func (a aggregateInfo) isOrderingSensitive() bool {
 	}
 }
 
+// isGroupingFunc returns true if the aggregate is a call to the GROUPING
+// function. GROUPING is not computed by the GroupBy operator; instead, it is
+// replaced by a constant for each grouping set (see buildGroupingSets).
+func (a aggregateInfo) isGroupingFunc() bool {
+	return a.def.Name == "grouping"
+}
+
 // isCommutative checks whether the aggregate is commutative. That is, if it is
 // ordering insensitive or if no ordering is specified.
 func (a aggregateInfo) isCommutative() bool {
@@ -350,9 +375,15 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		groupingColSet.Add(groupingCols[i].id)
 	}
 
+	hasGroupingSets := g.groupingSets != nil || g.hasGroupingFuncs()
+
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if hasGroupingSets {
+			panic(unimplemented.NewWithIssue(46280,
+				"ordered aggregates are not supported with GROUPING SETS, ROLLUP, CUBE or GROUPING"))
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -368,6 +399,13 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		fromCols = fromScope.colSet()
 	}
 	for i, agg := range aggInfos {
+		if agg.isGroupingFunc() {
+			// The GROUPING function is replaced by a constant in each grouping set
+			// by buildGroupingSets, so its arguments are not needed.
+			argCols = argCols[len(agg.args):]
+			continue
+		}
+
 		// First accumulate the arguments to the aggregate function. These are
 		// always variables referencing columns in the GroupBy input expression,
 		// except in the case of string_agg, where the second argument must be
@@ -429,12 +467,16 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// aggregate arguments, as well as any additional order by columns.
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
-	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr.(memo.RelExpr),
-		groupingColSet,
-		aggCols,
-		g.aggInScope.ordering,
-	)
+	if hasGroupingSets {
+		g.aggOutScope.expr = b.buildGroupingSets(g, aggCols)
+	} else {
+		g.aggOutScope.expr = b.constructGroupBy(
+			g.aggInScope.expr.(memo.RelExpr),
+			groupingColSet,
+			aggCols,
+			g.aggInScope.ordering,
+		)
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -446,6 +488,226 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	return g.aggOutScope
 }
 
+// maxGroupingFuncArgs is the maximum number of arguments of the GROUPING
+// function, since its result is a bit mask with one bit per argument.
+const maxGroupingFuncArgs = 31
+
+// buildGroupingSets constructs the aggregation for a GROUP BY clause with
+// GROUPING SETS, ROLLUP or CUBE, or for an aggregation that uses the GROUPING
+// function. Each grouping set is built as a separate GroupBy operator, which
+// projects NULL for the grouping columns that are not part of the set and a
+// constant for each GROUPING function. The results are combined with UNION
+// ALL into the columns of aggOutScope. For example:
+//
+//   SELECT a, b, sum(c), grouping(a, b) FROM t GROUP BY ROLLUP (a, b)
+//
+// is built as:
+//
+//   with &1 (pre-projection)
+//    └── union-all
+//         ├── union-all
+//         │    ├── project: grouping=0
+//         │    │    └── group-by (a, b)
+//         │    │         └── with-scan &1
+//         │    └── project: b=NULL, grouping=1
+//         │         └── group-by (a)
+//         │              └── with-scan &1
+//         └── project: a=NULL, b=NULL, grouping=3
+//              └── scalar-group-by
+//                   └── with-scan &1
+//
+// The With operator buffers the pre-projection, so that the input of the
+// aggregation is computed only once regardless of the number of grouping sets.
+func (b *Builder) buildGroupingSets(g *groupby, aggCols []scopeColumn) memo.RelExpr {
+	input := g.aggInScope.expr.(memo.RelExpr)
+
+	// Determine the grouping columns that are the arguments of each GROUPING
+	// function.
+	groupingFuncArgs := make([]opt.ColList, len(g.aggs))
+	for i := range g.aggs {
+		agg := &g.aggs[i]
+		if !agg.isGroupingFunc() {
+			continue
+		}
+		if len(agg.Exprs) > maxGroupingFuncArgs {
+			panic(pgerror.Newf(pgcode.TooManyArguments,
+				"GROUPING must have fewer than %d arguments", maxGroupingFuncArgs+1))
+		}
+		groupingFuncArgs[i] = make(opt.ColList, len(agg.Exprs))
+		for j, e := range agg.Exprs {
+			col, ok := g.groupStrs[symbolicExprStr(e)]
+			if !ok {
+				panic(pgerror.Newf(pgcode.Grouping,
+					"arguments to GROUPING must be grouping expressions of the associated query level"))
+			}
+			groupingFuncArgs[i][j] = col.id
+		}
+	}
+
+	sets := g.groupingSets
+	if len(sets) <= 1 {
+		// There is a single grouping set with all the grouping columns; it can be
+		// built directly on top of the input.
+		groupingCols := g.groupingCols()
+		var set opt.ColSet
+		for i := range groupingCols {
+			set.Add(groupingCols[i].id)
+		}
+		expr, _ := b.buildGroupingSet(
+			g, input, set, opt.ColMap{}, aggCols, groupingFuncArgs, g.aggInScope.ordering,
+		)
+		return expr
+	}
+
+	// Buffer the input so that it can be referenced once per grouping set. The
+	// With is hoisted to the top of the statement, so the input cannot refer to
+	// outer columns.
+	if !input.Relational().OuterCols.Empty() {
+		panic(unimplemented.NewWithIssue(46280,
+			"correlated GROUPING SETS, ROLLUP and CUBE are not supported"))
+	}
+	md := b.factory.Metadata()
+	withID := b.factory.Memo().NextWithID()
+	md.AddWithBinding(withID, input)
+	b.cteStack[len(b.cteStack)-1] = append(b.cteStack[len(b.cteStack)-1], cteSource{
+		id:   withID,
+		expr: input,
+		// The binding is referenced by multiple grouping sets, and inlining it
+		// would duplicate column IDs, so it is always materialized.
+		mtr: tree.MaterializeClause{Set: true, Materialize: true},
+	})
+	inCols := opt.ColSetToList(input.Relational().OutputCols)
+
+	var result memo.RelExpr
+	var resultCols opt.ColList
+	for i, set := range sets {
+		outCols := make(opt.ColList, len(inCols))
+		var colMap opt.ColMap
+		for j, col := range inCols {
+			colMeta := md.ColumnMeta(col)
+			outCols[j] = md.AddColumn(colMeta.Alias, colMeta.Type)
+			colMap.Set(int(col), int(outCols[j]))
+		}
+		scan := b.factory.ConstructWithScan(&memo.WithScanPrivate{
+			With:    withID,
+			InCols:  inCols,
+			OutCols: outCols,
+			ID:      md.NextUniqueID(),
+		})
+		expr, cols := b.buildGroupingSet(
+			g, scan, set, colMap, aggCols, groupingFuncArgs, nil, /* ordering */
+		)
+		if i == 0 {
+			result, resultCols = expr, cols
+			continue
+		}
+
+		// The last UNION ALL produces the columns of aggOutScope.
+		unionCols := make(opt.ColList, len(g.aggOutScope.cols))
+		for j := range g.aggOutScope.cols {
+			col := &g.aggOutScope.cols[j]
+			if i == len(sets)-1 {
+				unionCols[j] = col.id
+			} else {
+				unionCols[j] = md.AddColumn(string(col.name), col.typ)
+			}
+		}
+		result = b.factory.ConstructUnionAll(result, expr, &memo.SetPrivate{
+			LeftCols:  resultCols,
+			RightCols: cols,
+			OutCols:   unionCols,
+		})
+		resultCols = unionCols
+	}
+	return result
+}
+
+// buildGroupingSet builds the aggregation for a single grouping set on top of
+// the given input. colMap maps the columns of the pre-projection to the columns
+// of input; if it is empty, input is the pre-projection itself and the result
+// produces the columns of aggOutScope. Otherwise, new columns are synthesized.
+// Returns the expression along with its output columns, in the same order as
+// the columns of aggOutScope.
+func (b *Builder) buildGroupingSet(
+	g *groupby,
+	input memo.RelExpr,
+	set opt.ColSet,
+	colMap opt.ColMap,
+	aggCols []scopeColumn,
+	groupingFuncArgs []opt.ColList,
+	ordering opt.Ordering,
+) (memo.RelExpr, opt.ColList) {
+	md := b.factory.Metadata()
+	mapCol := func(col opt.ColumnID) opt.ColumnID {
+		if colMap.Empty() {
+			return col
+		}
+		to, ok := colMap.Get(int(col))
+		if !ok {
+			panic(errors.AssertionFailedf("column %d is not part of the grouping input", col))
+		}
+		return opt.ColumnID(to)
+	}
+	newCol := func(col *scopeColumn) opt.ColumnID {
+		if colMap.Empty() {
+			return col.id
+		}
+		return md.AddColumn(string(col.name), col.typ)
+	}
+
+	var groupingColSet opt.ColSet
+	for col, ok := set.Next(0); ok; col, ok = set.Next(col + 1) {
+		groupingColSet.Add(mapCol(col))
+	}
+
+	outCols := make(opt.ColList, len(g.aggOutScope.cols))
+	var groupByAggCols []scopeColumn
+	var projections memo.ProjectionsExpr
+	for i := range g.aggOutScope.cols {
+		col := &g.aggOutScope.cols[i]
+		switch {
+		case i < len(g.aggs) && g.aggs[i].isGroupingFunc():
+			// Bit j of the result (counting from the least significant bit) is set
+			// if the j-th to last argument is not part of the grouping set.
+			args := groupingFuncArgs[i]
+			var mask int64
+			for j, arg := range args {
+				if !set.Contains(arg) {
+					mask |= 1 << (len(args) - 1 - j)
+				}
+			}
+			outCols[i] = newCol(col)
+			projections = append(projections, b.factory.ConstructProjectionsItem(
+				b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(mask)), types.Int), outCols[i],
+			))
+
+		case i < len(g.aggs):
+			scalar := aggCols[i].scalar
+			if !colMap.Empty() {
+				scalar = b.factory.CustomFuncs().RemapCols(scalar, colMap)
+			}
+			outCols[i] = newCol(col)
+			groupByAggCols = append(groupByAggCols, scopeColumn{id: outCols[i], scalar: scalar})
+
+		case set.Contains(col.id):
+			outCols[i] = mapCol(col.id)
+
+		default:
+			// Grouping columns that are not part of this grouping set are NULL.
+			outCols[i] = newCol(col)
+			projections = append(projections, b.factory.ConstructProjectionsItem(
+				b.factory.ConstructNull(col.typ), outCols[i],
+			))
+		}
+	}
+
+	expr := b.constructGroupBy(input, groupingColSet, groupByAggCols, ordering)
+	if len(projections) > 0 {
+		expr = b.factory.ConstructProject(expr, projections, expr.Relational().OutputCols)
+	}
+	return expr, outCols
+}
+
 // analyzeHaving analyzes the having clause and returns it as a typed
 // expression. fromScope contains the name bindings that are visible for this
 // HAVING clause (e.g., passed in from an enclosing statement).
@@ -506,15 +768,129 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if !hasGroupingSets(groupBy) {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
+	} else {
+		// Each grouping set is built as the set of grouping columns for its
+		// expressions. Expressions that appear in multiple grouping sets are only
+		// built once.
+		sets := expandGroupingSets(groupBy)
+		g.groupingSets = make([]opt.ColSet, len(sets))
+		for i, set := range sets {
+			for _, e := range set {
+				cols := b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+				g.groupingSets[i].UnionWith(cols)
+			}
+		}
 	}
 	g.buildingGroupingCols = false
 }
 
+// maxGroupingSets is the maximum number of grouping sets that a GROUP BY
+// clause can expand to. maxCubeElements is the maximum number of elements in a
+// CUBE. These are the same limits that Postgres uses.
+const (
+	maxGroupingSets = 4096
+	maxCubeElements = 12
+)
+
+// hasGroupingSets returns true if the GROUP BY clause contains GROUPING SETS,
+// ROLLUP or CUBE.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := tree.StripParens(e).(*tree.GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandGroupingSets returns the grouping sets of a GROUP BY clause, each one
+// as a list of GROUP BY expressions. The grouping sets of the clause are the
+// cartesian product of the grouping sets of each of its elements. For example:
+//
+//   GROUP BY a, ROLLUP (b, c)
+//
+// expands to the grouping sets (a, b, c), (a, b) and (a).
+func expandGroupingSets(groupBy tree.GroupBy) []tree.Exprs {
+	sets := []tree.Exprs{nil}
+	for _, e := range groupBy {
+		elemSets := expandGroupingSetElem(e)
+		if len(sets)*len(elemSets) > maxGroupingSets {
+			panic(pgerror.Newf(pgcode.StatementTooComplex,
+				"too many grouping sets present (maximum %d)", maxGroupingSets))
+		}
+		product := make([]tree.Exprs, 0, len(sets)*len(elemSets))
+		for _, set := range sets {
+			for _, elemSet := range elemSets {
+				newSet := make(tree.Exprs, 0, len(set)+len(elemSet))
+				newSet = append(newSet, set...)
+				newSet = append(newSet, elemSet...)
+				product = append(product, newSet)
+			}
+		}
+		sets = product
+	}
+	return sets
+}
+
+// expandGroupingSetElem returns the grouping sets of a single element of a
+// GROUP BY clause. An element that is a parenthesized list of expressions is
+// returned as a single tuple, which buildGrouping flattens.
+func expandGroupingSetElem(e tree.Expr) []tree.Exprs {
+	gs, ok := tree.StripParens(e).(*tree.GroupingSet)
+	if !ok {
+		return []tree.Exprs{{e}}
+	}
+	var sets []tree.Exprs
+	switch gs.Type {
+	case tree.RollupType:
+		// ROLLUP (a, b, c) is equivalent to
+		// GROUPING SETS ((a, b, c), (a, b), (a), ()).
+		sets = make([]tree.Exprs, 0, len(gs.Exprs)+1)
+		for i := len(gs.Exprs); i >= 0; i-- {
+			sets = append(sets, gs.Exprs[:i:i])
+		}
+
+	case tree.CubeType:
+		// CUBE (a, b) is equivalent to GROUPING SETS ((a, b), (a), (b), ()).
+		if len(gs.Exprs) > maxCubeElements {
+			panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"CUBE is limited to %d elements", maxCubeElements))
+		}
+		n := len(gs.Exprs)
+		sets = make([]tree.Exprs, 0, 1<<n)
+		for mask := (1 << n) - 1; mask >= 0; mask-- {
+			var set tree.Exprs
+			for i := 0; i < n; i++ {
+				if mask&(1<<(n-1-i)) != 0 {
+					set = append(set, gs.Exprs[i])
+				}
+			}
+			sets = append(sets, set)
+		}
+
+	case tree.GroupingSetsType:
+		for _, elem := range gs.Exprs {
+			sets = append(sets, expandGroupingSetElem(elem)...)
+			if len(sets) > maxGroupingSets {
+				panic(pgerror.Newf(pgcode.StatementTooComplex,
+					"too many grouping sets present (maximum %d)", maxGroupingSets))
+			}
+		}
+
+	default:
+		panic(errors.AssertionFailedf("unknown grouping set type %s", gs.Type))
+	}
+	return sets
+}
+
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the set of grouping columns for the
+// expression.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -528,7 +904,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (cols opt.ColSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -626,7 +1002,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(col.id)
 			continue
 		}
 
@@ -636,7 +1013,9 @@ func (b *Builder) buildGrouping(
 		col := b.addColumn(aggInScope, alias, e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		cols.Add(col.id)
 	}
+	return cols
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -880,6 +1259,10 @@ func newGroupingError(name *tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The PK columns might not be part of all the grouping sets.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
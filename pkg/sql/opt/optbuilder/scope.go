@@ -1173,6 +1173,12 @@ func (s *scope) replaceWindowFn(f *tree.FuncExpr, def *tree.FunctionDefinition)
 	if err := tree.CheckIsWindowOrAgg(def); err != nil {
 		panic(err)
 	}
+	if def.Name == "grouping" {
+		// GROUPING is replaced with a constant for each grouping set, so it has
+		// no meaning over a window frame.
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"grouping() cannot be used as a window function"))
+	}
 
 	// We need to save and restore the previous value of the field in
 	// semaCtx in case we are recursively called within a subquery
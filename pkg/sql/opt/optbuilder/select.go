@@ -1294,7 +1294,8 @@ func (b *Builder) buildFromWithLateral(
 // validateAsOf ensures that any AS OF SYSTEM TIME timestamp is consistent with
 // that of the root statement.
 func (b *Builder) validateAsOf(asOf tree.AsOfClause) {
-	ts, err := tree.EvalAsOfTimestamp(b.ctx, asOf, b.semaCtx, b.evalCtx)
+	allowBoundedStaleness := b.semaCtx.AsOfBoundedStaleness != nil
+	asOfSystemTime, err := tree.EvalAsOf(b.ctx, asOf, b.semaCtx, b.evalCtx, allowBoundedStaleness)
 	if err != nil {
 		panic(err)
 	}
@@ -1304,7 +1305,17 @@ func (b *Builder) validateAsOf(asOf tree.AsOfClause) {
 			"AS OF SYSTEM TIME must be provided on a top-level statement"))
 	}
 
-	if *b.semaCtx.AsOfTimestamp != ts {
+	// The timestamp of a bounded staleness read is negotiated by the executor,
+	// so compare the clause against the one of the root statement instead.
+	if bs := b.semaCtx.AsOfBoundedStaleness; bs != nil {
+		if asOfSystemTime != *bs {
+			panic(unimplementedWithIssueDetailf(35712, "",
+				"cannot specify AS OF SYSTEM TIME with different timestamps"))
+		}
+		return
+	}
+
+	if *b.semaCtx.AsOfTimestamp != asOfSystemTime.Timestamp {
 		panic(unimplementedWithIssueDetailf(35712, "",
 			"cannot specify AS OF SYSTEM TIME with different timestamps"))
 	}
@@ -1348,11 +1359,12 @@ func (b *Builder) validateLockingInFrom(
 			// AST nodes should not be created with this locking strength.
 			panic(errors.AssertionFailedf("locking item without strength"))
 		case tree.ForUpdate, tree.ForNoKeyUpdate, tree.ForShare, tree.ForKeyShare:
-			// CockroachDB treats all of the FOR LOCKED modes as no-ops. Since all
-			// transactions are serializable in CockroachDB, clients can't observe
-			// whether or not FOR UPDATE (or any of the other weaker modes) actually
-			// created a lock. This behavior may improve as the transaction model gains
-			// more capabilities.
+			// FOR UPDATE and FOR NO KEY UPDATE acquire Exclusive locks on the
+			// scanned keys, while FOR SHARE and FOR KEY SHARE acquire Shared locks
+			// once all nodes are able to (see VersionSharedLocks).
+			// Since all transactions are serializable in CockroachDB, these locks
+			// are not needed for correctness, but they avoid transaction retries
+			// under contention.
 		default:
 			panic(errors.AssertionFailedf("unknown locking strength: %s", li.Strength))
 		}
@@ -12,14 +12,19 @@ package paramparse
 
 import (
 	"context"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
+	"github.com/gorhill/cronexpr"
 )
 
 // ApplyStorageParameters applies given storage parameters with the
@@ -68,7 +73,15 @@ type StorageParamObserver interface {
 }
 
 // TableStorageParamObserver observes storage parameters for tables.
-type TableStorageParamObserver struct{}
+type TableStorageParamObserver struct {
+	tableDesc *descpb.TableDescriptor
+}
+
+// NewTableStorageParamObserver returns a new TableStorageParamObserver which
+// applies storage parameters to the given table descriptor.
+func NewTableStorageParamObserver(tableDesc *descpb.TableDescriptor) *TableStorageParamObserver {
+	return &TableStorageParamObserver{tableDesc: tableDesc}
+}
 
 var _ StorageParamObserver = (*TableStorageParamObserver)(nil)
 
@@ -91,6 +104,90 @@ func applyFillFactorStorageParam(evalCtx *tree.EvalContext, key string, datum tr
 
 // RunPostChecks implements the StorageParamObserver interface.
 func (a *TableStorageParamObserver) RunPostChecks() error {
+	ttl := a.tableDesc.RowLevelTTL
+	if ttl == nil {
+		return nil
+	}
+	if ttl.ExpireAfter == "" && ttl.ExpirationExpr == "" {
+		return pgerror.New(pgcode.InvalidParameterValue,
+			`"ttl_expire_after" or "ttl_expiration_expression" must be set`)
+	}
+	if ttl.ExpireAfter != "" && ttl.ExpirationExpr != "" {
+		return pgerror.New(pgcode.InvalidParameterValue,
+			`"ttl_expire_after" and "ttl_expiration_expression" cannot both be set`)
+	}
+	return nil
+}
+
+func (a *TableStorageParamObserver) rowLevelTTL() *descpb.TableDescriptor_RowLevelTTL {
+	if a.tableDesc.RowLevelTTL == nil {
+		a.tableDesc.RowLevelTTL = &descpb.TableDescriptor_RowLevelTTL{}
+	}
+	return a.tableDesc.RowLevelTTL
+}
+
+func (a *TableStorageParamObserver) applyRowLevelTTLStorageParam(
+	evalCtx *tree.EvalContext, key string, datum tree.Datum,
+) error {
+	switch key {
+	case `ttl_expire_after`:
+		var d *tree.DInterval
+		if s, ok := tree.AsDString(datum); ok {
+			var err error
+			if d, err = tree.ParseDInterval(string(s)); err != nil {
+				return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid value for %q", key)
+			}
+		} else if d, ok = datum.(*tree.DInterval); !ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"parameter %q requires an interval value", key)
+		}
+		if d.Duration.Compare(duration.Duration{}) <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "%q must be positive", key)
+		}
+		a.rowLevelTTL().ExpireAfter = d.Duration.String()
+	case `ttl_expiration_expression`:
+		s, err := DatumAsString(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(s) == "" {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "%q must not be empty", key)
+		}
+		a.rowLevelTTL().ExpirationExpr = s
+	case `ttl_job_cron`:
+		s, err := DatumAsString(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if _, err := cronexpr.Parse(s); err != nil {
+			return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid value for %q", key)
+		}
+		a.rowLevelTTL().JobCron = s
+	case `ttl_select_batch_size`,
+		`ttl_delete_batch_size`,
+		`ttl_range_concurrency`,
+		`ttl_delete_rate_limit`:
+		val, err := DatumAsInt(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if val <= 0 {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "%q must be positive", key)
+		}
+		ttl := a.rowLevelTTL()
+		switch key {
+		case `ttl_select_batch_size`:
+			ttl.SelectBatchSize = val
+		case `ttl_delete_batch_size`:
+			ttl.DeleteBatchSize = val
+		case `ttl_range_concurrency`:
+			ttl.RangeConcurrency = val
+		case `ttl_delete_rate_limit`:
+			ttl.DeleteRateLimit = val
+		}
+	default:
+		return errors.Errorf("invalid storage parameter %q", key)
+	}
 	return nil
 }
 
@@ -122,6 +219,14 @@ func (a *TableStorageParamObserver) Apply(
 			)
 		}
 		return nil
+	case `ttl_expire_after`,
+		`ttl_expiration_expression`,
+		`ttl_job_cron`,
+		`ttl_select_batch_size`,
+		`ttl_delete_batch_size`,
+		`ttl_range_concurrency`,
+		`ttl_delete_rate_limit`:
+		return a.applyRowLevelTTLStorageParam(evalCtx, key, datum)
 	case `toast_tuple_target`,
 		`parallel_workers`,
 		`toast.autovacuum_enabled`,
@@ -210,10 +210,21 @@ func TestContextualHelp(t *testing.T) {
 		{`EXECUTE foo ??`, `EXECUTE`},
 		{`EXECUTE foo (??`, `EXECUTE`},
 
+		{`CLOSE ??`, `CLOSE`},
+		{`CLOSE foo ??`, `CLOSE`},
+
 		{`DEALLOCATE foo ??`, `DEALLOCATE`},
 		{`DEALLOCATE ALL ??`, `DEALLOCATE`},
 		{`DEALLOCATE PREPARE ??`, `DEALLOCATE`},
 
+		{`DECLARE ??`, `DECLARE`},
+		{`DECLARE foo CURSOR ??`, `DECLARE`},
+
+		{`FETCH ??`, `FETCH`},
+		{`FETCH NEXT FROM ??`, `FETCH`},
+
+		{`MOVE ??`, `MOVE`},
+
 		{`INSERT INTO ??`, `INSERT`},
 		{`INSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`INSERT INTO blah VALUES (1) RETURNING ??`, `INSERT`},
@@ -45,6 +45,7 @@ func TestParse(t *testing.T) {
 		{`BEGIN TRANSACTION READ ONLY`},
 		{`BEGIN TRANSACTION READ WRITE`},
 		{`BEGIN TRANSACTION ISOLATION LEVEL SERIALIZABLE`},
+		{`BEGIN TRANSACTION ISOLATION LEVEL READ COMMITTED`},
 		{`BEGIN TRANSACTION PRIORITY LOW`},
 		{`BEGIN TRANSACTION PRIORITY NORMAL`},
 		{`BEGIN TRANSACTION PRIORITY HIGH`},
@@ -722,6 +723,22 @@ func TestParse(t *testing.T) {
 		{`DEALLOCATE a`},
 		{`DEALLOCATE ALL`},
 
+		{`DECLARE a CURSOR FOR SELECT 1`},
+		{`DECLARE a BINARY NO SCROLL CURSOR WITH HOLD FOR SELECT * FROM t`},
+		{`DECLARE a SCROLL CURSOR FOR VALUES (1), (2)`},
+		{`FETCH 1 a`},
+		{`FETCH -3 a`},
+		{`FETCH ALL a`},
+		{`FETCH BACKWARD ALL a`},
+		{`FETCH FIRST a`},
+		{`FETCH LAST a`},
+		{`FETCH ABSOLUTE -2 a`},
+		{`FETCH RELATIVE 0 a`},
+		{`MOVE 5 a`},
+		{`MOVE ALL a`},
+		{`CLOSE a`},
+		{`CLOSE ALL`},
+
 		// Tables are the default, but can also be specified with
 		// GRANT x ON TABLE y. However, the stringer does not output TABLE.
 		{`GRANT SELECT ON TABLE foo TO root`},
@@ -1084,6 +1101,13 @@ func TestParse(t *testing.T) {
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
 		{`SELECT 1 FROM t GROUP BY ()`},
+		{`SELECT 1 FROM t GROUP BY ROLLUP (b)`},
+		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b, c)`},
+		{`SELECT 1 FROM t GROUP BY CUBE (b, c)`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (b)`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS ((a, b), (a), ())`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (ROLLUP (a), CUBE (b, c))`},
+		{`SELECT grouping(a, b) FROM t GROUP BY ROLLUP (a, b)`},
 		{`SELECT sum(x ORDER BY y) FROM t`},
 		{`SELECT sum(x ORDER BY y, z) FROM t`},
 
@@ -1233,6 +1257,7 @@ func TestParse(t *testing.T) {
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE`},
+		{`SET TRANSACTION ISOLATION LEVEL READ COMMITTED`},
 		{`SET TRANSACTION PRIORITY LOW`},
 		{`SET TRANSACTION PRIORITY NORMAL`},
 		{`SET TRANSACTION PRIORITY HIGH`},
@@ -1426,6 +1451,17 @@ func TestParse(t *testing.T) {
 		{`COPY crdb_internal.file_upload FROM STDIN WITH destination = 'filename'`},
 		{`COPY t (a, b, c) FROM STDIN WITH BINARY`},
 		{`COPY crdb_internal.file_upload FROM STDIN WITH BINARY destination = 'filename'`},
+		{`COPY t FROM STDIN WITH CSV`},
+		{`COPY t (a, b) FROM STDIN WITH CSV HEADER DELIMITER ';' NULL 'n' QUOTE '|'`},
+		{`COPY t TO STDOUT`},
+		{`COPY t (a, b, c) TO STDOUT`},
+		{`COPY t TO STDOUT WITH BINARY`},
+		{`COPY t TO STDOUT WITH CSV HEADER NULL ''`},
+		{`COPY t TO STDOUT WITH DELIMITER ','`},
+		{`COPY (SELECT a FROM t ORDER BY a) TO STDOUT`},
+		{`COPY (VALUES (1)) TO STDOUT WITH CSV`},
+		{`COPY (INSERT INTO t VALUES (1) RETURNING a) TO STDOUT`},
+		{`COPY (DELETE FROM t RETURNING a) TO STDOUT`},
 
 		{`ALTER TABLE a SPLIT AT VALUES (1)`},
 		{`EXPLAIN ALTER TABLE a SPLIT AT VALUES (1)`},
@@ -1671,6 +1707,8 @@ func TestParse(t *testing.T) {
 		{`EXPORT INTO CSV 'a' FROM SELECT * FROM a`},
 		{`EXPORT INTO CSV 's3://my/path/%part%.csv' WITH delimiter = '|' FROM TABLE a`},
 		{`EXPORT INTO CSV 's3://my/path/%part%.csv' WITH delimiter = '|' FROM SELECT a, sum(b) FROM c WHERE d = 1 ORDER BY sum(b) DESC LIMIT 10`},
+		{`EXPORT INTO PARQUET 'a' FROM TABLE a`},
+		{`EXPORT INTO PARQUET 's3://my/path/%part%.parquet' WITH compression = 'snappy', row_group_size = '64MiB' FROM SELECT * FROM a`},
 
 		{`SET ROW (1, true, NULL)`},
 
@@ -1683,6 +1721,10 @@ func TestParse(t *testing.T) {
 		// {`CREATE CHANGEFEED FOR TABLE foo PARTITION bar, baz INTO 'sink'`},
 		// {`CREATE CHANGEFEED FOR DATABASE foo INTO 'sink'`},
 		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink' WITH bar = 'baz'`},
+		{`CREATE CHANGEFEED FOR TABLE foo (a, b) INTO 'sink'`},
+		{`CREATE CHANGEFEED FOR TABLE foo WHERE status = 'shipped' INTO 'sink' WITH updated`},
+		{`CREATE CHANGEFEED FOR TABLE foo (a) WHERE b > 1 INTO 'sink'`},
+		{`EXPERIMENTAL CHANGEFEED FOR TABLE foo (a) WHERE b > 1 WITH updated`},
 
 		// Regression for #15926
 		{`SELECT * FROM ((t1 NATURAL JOIN t2 WITH ORDINALITY AS o1)) WITH ORDINALITY AS o2`},
@@ -2196,6 +2238,8 @@ $function$`,
 			`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT READ ONLY`,
 			`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, READ ONLY`},
+		{`BEGIN TRANSACTION ISOLATION LEVEL READ UNCOMMITTED`,
+			`BEGIN TRANSACTION ISOLATION LEVEL READ COMMITTED`},
 		{"SET CLUSTER SETTING a TO 1", "SET CLUSTER SETTING a = 1"},
 		{"SET TRACING TO off", "SET TRACING = off"},
 		{"RELEASE foo", "RELEASE SAVEPOINT foo"},
@@ -2214,6 +2258,20 @@ $function$`,
 		{`DEALLOCATE PREPARE ALL`,
 			`DEALLOCATE ALL`},
 
+		{`DECLARE a INSENSITIVE CURSOR WITHOUT HOLD FOR SELECT 1`,
+			`DECLARE a CURSOR FOR SELECT 1`},
+		{`FETCH a`, `FETCH 1 a`},
+		{`FETCH FROM a`, `FETCH 1 a`},
+		{`FETCH NEXT IN a`, `FETCH 1 a`},
+		{`FETCH PRIOR a`, `FETCH -1 a`},
+		{`FETCH FORWARD a`, `FETCH 1 a`},
+		{`FETCH FORWARD 5 FROM a`, `FETCH 5 a`},
+		{`FETCH FORWARD ALL a`, `FETCH ALL a`},
+		{`FETCH BACKWARD a`, `FETCH -1 a`},
+		{`FETCH BACKWARD 5 a`, `FETCH -5 a`},
+		{`FETCH ALL IN a`, `FETCH ALL a`},
+		{`MOVE PRIOR IN a`, `MOVE -1 a`},
+
 		{`CANCEL JOB a`, `CANCEL JOBS VALUES (a)`},
 		{`EXPLAIN CANCEL JOB a`, `EXPLAIN CANCEL JOBS VALUES (a)`},
 		{`CANCEL JOBS FOR SCHEDULE a`, `CANCEL JOBS FOR SCHEDULES VALUES (a)`},
@@ -2316,6 +2374,10 @@ $function$`,
 			`COPY t (a, b, c) FROM STDIN WITH BINARY`},
 		{`COPY t (a, b, c) FROM STDIN destination = 'filename' BINARY`,
 			`COPY t (a, b, c) FROM STDIN WITH BINARY destination = 'filename'`},
+		{`COPY t FROM STDIN DELIMITER AS ',' HEADER CSV`,
+			`COPY t FROM STDIN WITH CSV HEADER DELIMITER ','`},
+		{`COPY t TO STDOUT QUOTE AS '"' NULL AS 'x' CSV`,
+			`COPY t TO STDOUT WITH CSV NULL 'x' QUOTE '"'`},
 
 		// Identifier handling for zone configs.
 
@@ -3153,16 +3215,10 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`, ``},
 		{`SELECT (a,b) OVERLAPS (c,d)`, 0, `overlaps`, ``},
 		{`SELECT UNIQUE (SELECT b)`, 0, `UNIQUE predicate`, ``},
-		{`SELECT GROUPING (a,b,c)`, 0, `d_expr grouping`, ``},
 		{`SELECT a(VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`, ``},
 
-		{`SELECT 1 FROM t GROUP BY ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY CUBE (b)`, 46280, `cube`, ``},
-		{`SELECT 1 FROM t GROUP BY GROUPING SETS (b)`, 46280, `grouping sets`, ``},
-
 		{`SELECT a FROM t ORDER BY a NULLS LAST`, 6224, ``, ``},
 		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`, 6224, ``, ``},
 		{`SELECT a FROM t ORDER BY a DESC NULLS FIRST`, 6224, ``, ``},
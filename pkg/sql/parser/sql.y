@@ -276,6 +276,12 @@ func (u *sqlSymUnion) int32() int32 {
 func (u *sqlSymUnion) int64() int64 {
     return u.val.(int64)
 }
+func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
+    return u.val.(tree.CursorStmt)
+}
+func (u *sqlSymUnion) cursorScrollOption() tree.CursorScrollOption {
+    return u.val.(tree.CursorScrollOption)
+}
 func (u *sqlSymUnion) seqOpt() tree.SequenceOption {
     return u.val.(tree.SequenceOption)
 }
@@ -577,11 +583,11 @@ func (u *sqlSymUnion) survive() tree.Survive {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACCESS ACTION ADD ADMIN AFTER AGGREGATE
+%token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str> ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
 
-%token <str> BACKUP BACKUPS BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
+%token <str> BACKUP BACKUPS BACKWARD BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
@@ -591,12 +597,12 @@ func (u *sqlSymUnion) survive() tree.Survive {
 %token <str> COMMITTED COMPACT COMPLETE CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
 %token <str> CONFLICT CONNECTION CONSTRAINT CONSTRAINTS CONTAINS CONTROLCHANGEFEED CONTROLJOB
 %token <str> CONVERSION CONVERT COPY COVERING CREATE CREATEDB CREATELOGIN CREATEROLE
-%token <str> CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
+%token <str> CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
-%token <str> CURRENT_USER CYCLE
+%token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT DEFAULTS
-%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DESC DESTINATION DETACHED
+%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DESC DESTINATION DETACHED
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
 %token <str> ELSE ENCODING ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
@@ -607,19 +613,19 @@ func (u *sqlSymUnion) survive() tree.Survive {
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
-%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FROM FULL FUNCTION
+%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FORWARD FROM FULL FUNCTION
 
 %token <str> GENERATED GEOGRAPHY GEOMETRY GEOMETRYM GEOMETRYZ GEOMETRYZM
 %token <str> GEOMETRYCOLLECTION GEOMETRYCOLLECTIONM GEOMETRYCOLLECTIONZ GEOMETRYCOLLECTIONZM
 %token <str> GLOBAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HIGH HISTOGRAM HOUR
+%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
 
 %token <str> IDENTITY
 %token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INTERLEAVE INITIALLY
-%token <str> INNER INSERT INT INTEGER
+%token <str> INNER INSENSITIVE INSERT INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
@@ -631,7 +637,7 @@ func (u *sqlSymUnion) survive() tree.Survive {
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
 %token <str> LIST LOCAL LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH
+%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
@@ -645,23 +651,23 @@ func (u *sqlSymUnion) survive() tree.Survive {
 
 %token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACING
 %token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
-%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIORITY PRIVILEGES
+%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PUBLIC PUBLICATION
 
-%token <str> QUERIES QUERY
+%token <str> QUERIES QUERY QUOTE
 
 %token <str> RANGE RANGES READ REAL REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE REINDEX
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
-%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETRY REVISION_HISTORY REVOKE RIGHT
+%token <str> RELATIVE RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETRY REVISION_HISTORY REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE RUNNING
 
-%token <str> SAVEPOINT SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCROLL SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> START STATISTICS STATUS STDIN STDOUT STRICT STRING STORAGE STORE STORED STORING SUBSTRING
 %token <str> SURVIVE SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -780,6 +786,8 @@ func (u *sqlSymUnion) survive() tree.Survive {
 %type <tree.Statement> comment_stmt
 %type <tree.Statement> commit_stmt
 %type <tree.Statement> copy_from_stmt
+%type <tree.Statement> copy_to_stmt
+%type <tree.Statement> copy_query
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt
@@ -899,6 +907,11 @@ func (u *sqlSymUnion) survive() tree.Survive {
 
 %type <tree.Statement> close_cursor_stmt
 %type <tree.Statement> declare_cursor_stmt
+%type <tree.Statement> fetch_cursor_stmt
+%type <tree.Statement> move_cursor_stmt
+%type <tree.CursorStmt> cursor_movement_specifier
+%type <tree.CursorScrollOption> opt_scroll
+%type <bool> opt_binary opt_hold
 %type <tree.Statement> reindex_stmt
 
 %type <[]string> opt_incremental
@@ -1139,6 +1152,7 @@ func (u *sqlSymUnion) survive() tree.Survive {
 %type <tree.Expr> func_application func_expr_common_subexpr special_function
 %type <tree.Expr> func_expr func_expr_windowless
 %type <empty> opt_with
+%type <empty> opt_as
 %type <*tree.With> with_clause opt_with_clause
 %type <[]*tree.CTE> cte_list
 %type <*tree.CTE> common_table_expr
@@ -1257,6 +1271,7 @@ stmt:
 | preparable_stmt   // help texts in sub-rule
 | analyze_stmt      // EXTEND WITH HELP: ANALYZE
 | copy_from_stmt
+| copy_to_stmt
 | comment_stmt
 | execute_stmt      // EXTEND WITH HELP: EXECUTE
 | deallocate_stmt   // EXTEND WITH HELP: DEALLOCATE
@@ -1270,8 +1285,10 @@ stmt:
 | refresh_stmt      // EXTEND WITH HELP: REFRESH
 | nonpreparable_set_stmt // help texts in sub-rule
 | transaction_stmt  // help texts in sub-rule
-| close_cursor_stmt
-| declare_cursor_stmt
+| close_cursor_stmt // EXTEND WITH HELP: CLOSE
+| declare_cursor_stmt // EXTEND WITH HELP: DECLARE
+| fetch_cursor_stmt // EXTEND WITH HELP: FETCH
+| move_cursor_stmt // EXTEND WITH HELP: MOVE
 | reindex_stmt
 | /* EMPTY */
   {
@@ -2699,9 +2716,14 @@ import_stmt:
 //
 // Formats:
 //    CSV
+//    PARQUET
 //
 // Options:
-//    delimiter = '...'   [CSV-specific]
+//    delimiter = '...'        [CSV-specific]
+//    nullas = '...'           [CSV-specific]
+//    row_group_size = '...'   [PARQUET-specific]
+//    compression = '...'
+//    chunk_rows = '...'
 //
 // %SeeAlso: SELECT
 export_stmt:
@@ -2807,6 +2829,36 @@ copy_from_stmt:
     }
   }
 
+copy_to_stmt:
+  COPY table_name opt_column_list TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    name := $2.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CopyTo{
+       Table: name,
+       Columns: $3.nameList(),
+       Options: *$6.copyOptions(),
+    }
+  }
+| COPY '(' copy_query ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{
+       Statement: $3.stmt(),
+       Options: *$7.copyOptions(),
+    }
+  }
+
+copy_query:
+  select_stmt
+  {
+    $$.val = $1.slct()
+  }
+| insert_stmt
+| update_stmt
+| upsert_stmt
+| delete_stmt
+
 opt_with_copy_options:
   opt_with copy_options_list
   {
@@ -2838,6 +2890,26 @@ copy_options:
   {
     $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatBinary}
   }
+| CSV
+  {
+    $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatCSV}
+  }
+| HEADER
+  {
+    $$.val = &tree.CopyOptions{Header: true}
+  }
+| DELIMITER opt_as string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Delimiter: $3.expr()}
+  }
+| NULL opt_as string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Null: $3.expr()}
+  }
+| QUOTE opt_as string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Quote: $3.expr()}
+  }
 
 // %Help: CANCEL
 // %Category: Group
@@ -3177,20 +3249,24 @@ create_stats_option:
   }
 
 create_changefeed_stmt:
-  CREATE CHANGEFEED FOR changefeed_targets opt_changefeed_sink opt_with_options
+  CREATE CHANGEFEED FOR changefeed_targets opt_column_list opt_where_clause opt_changefeed_sink opt_with_options
   {
     $$.val = &tree.CreateChangefeed{
       Targets: $4.targetList(),
-      SinkURI: $5.expr(),
-      Options: $6.kvOptions(),
+      Columns: $5.nameList(),
+      Where:   tree.NewWhere(tree.AstWhere, $6.expr()),
+      SinkURI: $7.expr(),
+      Options: $8.kvOptions(),
     }
   }
-| EXPERIMENTAL CHANGEFEED FOR changefeed_targets opt_with_options
+| EXPERIMENTAL CHANGEFEED FOR changefeed_targets opt_column_list opt_where_clause opt_with_options
   {
     /* SKIP DOC */
     $$.val = &tree.CreateChangefeed{
       Targets: $4.targetList(),
-      Options: $5.kvOptions(),
+      Columns: $5.nameList(),
+      Where:   tree.NewWhere(tree.AstWhere, $6.expr()),
+      Options: $7.kvOptions(),
     }
   }
 
@@ -4203,11 +4279,11 @@ var_list:
 iso_level:
   READ UNCOMMITTED
   {
-    $$.val = tree.SerializableIsolation
+    $$.val = tree.ReadCommittedIsolation
   }
 | READ COMMITTED
   {
-    $$.val = tree.SerializableIsolation
+    $$.val = tree.ReadCommittedIsolation
   }
 | SNAPSHOT
   {
@@ -4310,14 +4386,183 @@ show_stmt:
 | SHOW error                // SHOW HELP: SHOW
 | show_last_query_stats_stmt // EXTEND WITH HELP: SHOW LAST QUERY STATISTICS
 
-// Cursors are not yet supported by CockroachDB. CLOSE ALL is safe to no-op
-// since there will be no open cursors.
+// %Help: CLOSE - close a cursor
+// %Category: Misc
+// %Text: CLOSE { <name> | ALL }
+// %SeeAlso: DECLARE, FETCH, MOVE
 close_cursor_stmt:
-	CLOSE ALL { }
-| CLOSE cursor_name { return unimplementedWithIssue(sqllex, 41412) }
+  CLOSE ALL
+  {
+    $$.val = &tree.CloseCursor{All: true}
+  }
+| CLOSE cursor_name
+  {
+    $$.val = &tree.CloseCursor{Name: tree.Name($2)}
+  }
+| CLOSE error // SHOW HELP: CLOSE
 
+// %Help: DECLARE - define a cursor
+// %Category: Misc
+// %Text:
+// DECLARE <name> [BINARY] [INSENSITIVE] [[NO] SCROLL]
+//   CURSOR [{WITH | WITHOUT} HOLD] FOR <selectclause>
+// %SeeAlso: FETCH, MOVE, CLOSE, SELECT
 declare_cursor_stmt:
-	DECLARE { return unimplementedWithIssue(sqllex, 41412) }
+  DECLARE cursor_name opt_binary opt_insensitive opt_scroll CURSOR opt_hold FOR select_stmt
+  {
+    $$.val = &tree.DeclareCursor{
+      Name: tree.Name($2),
+      Select: $9.slct(),
+      Binary: $3.bool(),
+      Scroll: $5.cursorScrollOption(),
+      Hold: $7.bool(),
+    }
+  }
+| DECLARE error // SHOW HELP: DECLARE
+
+opt_binary:
+  BINARY
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// All cursors are insensitive, so INSENSITIVE is accepted and ignored.
+opt_insensitive:
+  INSENSITIVE {}
+| /* EMPTY */ {}
+
+opt_scroll:
+  SCROLL
+  {
+    $$.val = tree.Scroll
+  }
+| NO SCROLL
+  {
+    $$.val = tree.NoScroll
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedScroll
+  }
+
+opt_hold:
+  WITH HOLD
+  {
+    $$.val = true
+  }
+| WITHOUT HOLD
+  {
+    $$.val = false
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// %Help: FETCH - retrieve rows from a cursor
+// %Category: Misc
+// %Text:
+// FETCH [<direction> [FROM | IN]] <name>
+//
+// Directions:
+//   NEXT | PRIOR | FIRST | LAST | ABSOLUTE <count> | RELATIVE <count> | <count> | ALL
+//   FORWARD [<count> | ALL] | BACKWARD [<count> | ALL]
+// %SeeAlso: DECLARE, MOVE, CLOSE
+fetch_cursor_stmt:
+  FETCH cursor_movement_specifier
+  {
+    $$.val = &tree.FetchCursor{CursorStmt: $2.cursorStmt()}
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - reposition a cursor without retrieving rows
+// %Category: Misc
+// %Text: MOVE [<direction> [FROM | IN]] <name>
+// %SeeAlso: FETCH, DECLARE, CLOSE
+move_cursor_stmt:
+  MOVE cursor_movement_specifier
+  {
+    $$.val = &tree.MoveCursor{CursorStmt: $2.cursorStmt()}
+  }
+| MOVE error // SHOW HELP: MOVE
+
+cursor_movement_specifier:
+  cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($1), FetchType: tree.FetchNormal, Count: 1}
+  }
+| from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($2), FetchType: tree.FetchNormal, Count: 1}
+  }
+| NEXT opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchNormal, Count: 1}
+  }
+| PRIOR opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchNormal, Count: -1}
+  }
+| FIRST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchFirst}
+  }
+| LAST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchLast}
+  }
+| ABSOLUTE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchAbsolute, Count: $2.int64()}
+  }
+| RELATIVE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchRelative, Count: $2.int64()}
+  }
+| signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchNormal, Count: $1.int64()}
+  }
+| ALL opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchAll}
+  }
+| FORWARD opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchNormal, Count: 1}
+  }
+| FORWARD signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchNormal, Count: $2.int64()}
+  }
+| FORWARD ALL opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchAll}
+  }
+| BACKWARD opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($3), FetchType: tree.FetchNormal, Count: -1}
+  }
+| BACKWARD signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchNormal, Count: -$2.int64()}
+  }
+| BACKWARD ALL opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{Name: tree.Name($4), FetchType: tree.FetchBackwardAll}
+  }
+
+from_or_in:
+  FROM {}
+| IN {}
+
+opt_from_or_in:
+  from_or_in {}
+| /* EMPTY */ {}
 
 reindex_stmt:
   REINDEX TABLE error
@@ -8075,6 +8320,10 @@ opt_with:
   WITH {}
 | /* EMPTY */ {}
 
+opt_as:
+  AS {}
+| /* EMPTY */ {}
+
 opt_with_clause:
   with_clause
   {
@@ -8344,9 +8593,18 @@ group_by_list:
 // rather than reducing the conflicting unreserved_keyword rule.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.RollupType, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.CubeType, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.GroupingSetsType, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -10181,7 +10439,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction($1), Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -11616,6 +11877,7 @@ unrestricted_name:
 // "Unreserved" keywords --- available for use as any kind of name.
 unreserved_keyword:
   ABORT
+| ABSOLUTE
 | ACTION
 | ACCESS
 | ADD
@@ -11630,6 +11892,7 @@ unreserved_keyword:
 | AVAILABILITY
 | BACKUP
 | BACKUPS
+| BACKWARD
 | BEFORE
 | BEGIN
 | BINARY
@@ -11665,8 +11928,10 @@ unreserved_keyword:
 | CREATEDB
 | CREATELOGIN
 | CREATEROLE
+| CSV
 | CUBE
 | CURRENT
+| CURSOR
 | CYCLE
 | DATA
 | DATABASE
@@ -11677,6 +11942,7 @@ unreserved_keyword:
 | DELETE
 | DEFAULTS
 | DEFERRED
+| DELIMITER
 | DESTINATION
 | DETACHED
 | DISCARD
@@ -11706,6 +11972,7 @@ unreserved_keyword:
 | FILTER
 | FIRST
 | FOLLOWING
+| FORWARD
 | FORCE_INDEX
 | FUNCTION
 | GENERATED
@@ -11720,8 +11987,10 @@ unreserved_keyword:
 | GRANTS
 | GROUPS
 | HASH
+| HEADER
 | HIGH
 | HISTOGRAM
+| HOLD
 | HOUR
 | IDENTITY
 | IMMEDIATE
@@ -11733,6 +12002,7 @@ unreserved_keyword:
 | INDEXES
 | INHERITS
 | INJECT
+| INSENSITIVE
 | INSERT
 | INTERLEAVE
 | INTO_DB
@@ -11781,6 +12051,7 @@ unreserved_keyword:
 | MULTIPOLYGONZ
 | MULTIPOLYGONZM
 | MONTH
+| MOVE
 | NAMES
 | NAN
 | NEVER
@@ -11831,12 +12102,14 @@ unreserved_keyword:
 | PRECEDING
 | PREPARE
 | PRESERVE
+| PRIOR
 | PRIORITY
 | PRIVILEGES
 | PUBLIC
 | PUBLICATION
 | QUERIES
 | QUERY
+| QUOTE
 | RANGE
 | RANGES
 | READ
@@ -11848,6 +12121,7 @@ unreserved_keyword:
 | REGION
 | REGIONS
 | REINDEX
+| RELATIVE
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -11875,6 +12149,7 @@ unreserved_keyword:
 | SCATTER
 | SCHEMA
 | SCHEMAS
+| SCROLL
 | SCRUB
 | SEARCH
 | SECOND
@@ -11900,6 +12175,7 @@ unreserved_keyword:
 | START
 | STATISTICS
 | STDIN
+| STDOUT
 | STORAGE
 | STORE
 | STORED
@@ -216,6 +216,7 @@ var pgCatalog = virtualSchema{
 		catconstants.PgCatalogCollationTableID:           pgCatalogCollationTable,
 		catconstants.PgCatalogConstraintTableID:          pgCatalogConstraintTable,
 		catconstants.PgCatalogConversionTableID:          pgCatalogConversionTable,
+		catconstants.PgCatalogCursorsTableID:             pgCatalogCursorsTable,
 		catconstants.PgCatalogDatabaseTableID:            pgCatalogDatabaseTable,
 		catconstants.PgCatalogDefaultACLTableID:          pgCatalogDefaultACLTable,
 		catconstants.PgCatalogDependTableID:              pgCatalogDependTable,
@@ -1218,6 +1219,41 @@ CREATE TABLE pg_catalog.pg_conversion (
 	},
 }
 
+// pgCatalogCursorsTable implements the pg_cursors table.
+// The is_binary field is always false as binary cursors are not supported.
+var pgCatalogCursorsTable = virtualSchemaTable{
+	comment: `open cursors
+https://www.postgresql.org/docs/current/view-pg-cursors.html`,
+	schema: `
+CREATE TABLE pg_catalog.pg_cursors (
+	name TEXT,
+	statement TEXT,
+	is_holdable BOOL,
+	is_binary BOOL,
+	is_scrollable BOOL,
+	creation_time TIMESTAMPTZ
+)`,
+	populate: func(ctx context.Context, p *planner, dbContext *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
+		for _, c := range p.sqlCursors.List() {
+			ts, err := tree.MakeDTimestampTZ(c.createdAt, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.NewDString(c.name),
+				tree.NewDString(c.sql),
+				tree.MakeDBool(tree.DBool(c.hold)),
+				tree.DBoolFalse,
+				tree.MakeDBool(tree.DBool(c.scroll != tree.NoScroll)),
+				ts,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 var pgCatalogDatabaseTable = virtualSchemaTable{
 	comment: `available databases (incomplete)
 https://www.postgresql.org/docs/9.5/catalog-pg-database.html`,
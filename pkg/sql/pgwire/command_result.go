@@ -83,6 +83,11 @@ type commandResult struct {
 	// (except types must always be set).
 	types []*types.T
 
+	// copyOpts is set for the results of COPY TO statements, once the Copy-out
+	// subprotocol has begun. The rows are then sent as CopyData messages,
+	// encoded according to these options.
+	copyOpts *sql.CopyFormatOptions
+
 	// bufferingDisabled is conditionally set during planning of certain
 	// statements.
 	bufferingDisabled bool
@@ -138,6 +143,9 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
 	case commandComplete:
+		if r.copyOpts != nil {
+			r.conn.bufferCopyDone(r.copyOpts.Format)
+		}
 		tag := cookTag(
 			r.cmdCompleteTag, r.conn.writerState.tagBuf[:0], r.stmtType, r.rowsAffected,
 		)
@@ -201,7 +209,11 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	}
 	r.rowsAffected++
 
-	r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.types)
+	if r.copyOpts != nil {
+		r.conn.bufferCopyDataRow(ctx, row, r.copyOpts, r.conv, r.types)
+	} else {
+		r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.types)
+	}
 	var err error
 	if r.bufferingDisabled {
 		err = r.conn.Flush(r.pos)
@@ -211,6 +223,33 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	return err
 }
 
+// BeginCopyOut is part of the sql.CopyOutResult interface.
+func (r *commandResult) BeginCopyOut(
+	ctx context.Context, cols colinfo.ResultColumns, opts sql.CopyFormatOptions,
+) error {
+	r.assertNotReleased()
+	r.conn.writerState.fi.registerCmd(r.pos)
+	if err := r.conn.GetErr(); err != nil {
+		return err
+	}
+	r.copyOpts = &opts
+	r.types = make([]*types.T, len(cols))
+	for i := range cols {
+		r.types[i] = cols[i].Typ
+	}
+	r.conn.bufferCopyOutResponse(len(cols), opts.Format)
+	switch opts.Format {
+	case tree.CopyFormatBinary:
+		r.conn.bufferCopyData([]byte(copyBinarySignature))
+	case tree.CopyFormatCSV:
+		if opts.Header {
+			r.conn.bufferCopyCSVHeader(cols, &opts)
+		}
+	}
+	_ /* flushed */, err := r.conn.maybeFlush(r.pos)
+	return err
+}
+
 // DisableBuffering is part of the CommandResult interface.
 func (r *commandResult) DisableBuffering() {
 	r.assertNotReleased()
@@ -17,7 +17,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -88,6 +90,10 @@ type conn struct {
 
 	readBuf    pgwirebase.ReadBuffer
 	msgBuilder writeBuffer
+	// copyBuf is used to serialize the fields of the rows sent through the
+	// Copy-out subprotocol, which need to be escaped before being added to
+	// msgBuilder.
+	copyBuf writeBuffer
 
 	sv *settings.Values
 
@@ -172,6 +178,9 @@ func newConn(
 	c.writerState.fi.lastFlushed = -1
 	c.writerState.fi.cmdStarts = make(map[sql.CmdPos]int)
 	c.msgBuilder.init(metrics.BytesOutCount)
+	// The copyBuf's contents are copied to msgBuilder, so there's no need to
+	// count its bytes.
+	c.copyBuf.init(nil /* bytecount */)
 
 	return c
 }
@@ -698,6 +707,25 @@ func (c *conn) sendInitialConnData(
 	return connHandler, nil
 }
 
+// traceParentCommentRE matches a W3C traceparent passed by a client in a
+// comment of a query, in the format used by sqlcommenter. For example:
+//
+//   SELECT 1 /*traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'*/
+var traceParentCommentRE = regexp.MustCompile(`(?s)/\*.*?\btraceparent\s*=\s*'([^']*)'.*?\*/`)
+
+// traceParentFromComment returns the traceparent passed in a comment of the
+// query, if any. The parser discards comments, so this needs to look at the
+// query string as sent by the client.
+func traceParentFromComment(query string) string {
+	if !strings.Contains(query, "traceparent") {
+		return ""
+	}
+	if m := traceParentCommentRE.FindStringSubmatch(query); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
 // An error is returned iff the statement buffer has been closed. In that case,
 // the connection should be considered toast.
 func (c *conn) handleSimpleQuery(
@@ -719,6 +747,7 @@ func (c *conn) handleSimpleQuery(
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
 	}
 	endParse := timeutil.Now()
+	traceParent := traceParentFromComment(query)
 
 	if len(stmts) == 0 {
 		return c.stmtBuf.Push(
@@ -755,6 +784,15 @@ func (c *conn) handleSimpleQuery(
 			copyDone.Wait()
 			return nil
 		}
+		// The CopyTo statement is executed through a special command as well, but
+		// it doesn't need control of the connection: its data is sent to the
+		// client through the command's result.
+		if cp, ok := stmts[i].AST.(*tree.CopyTo); ok {
+			if err := c.stmtBuf.Push(ctx, sql.CopyOut{Stmt: cp}); err != nil {
+				return err
+			}
+			continue
+		}
 
 		if err := c.stmtBuf.Push(
 			ctx,
@@ -763,6 +801,7 @@ func (c *conn) handleSimpleQuery(
 				TimeReceived: timeReceived,
 				ParseStart:   startParse,
 				ParseEnd:     endParse,
+				TraceParent:  traceParent,
 			}); err != nil {
 			return err
 		}
@@ -855,6 +894,11 @@ func (c *conn) handleParse(
 		// https://www.postgresql.org/message-id/flat/CAMsr%2BYGvp2wRx9pPSxaKFdaObxX8DzWse%2BOkWk2xpXSvT0rq-g%40mail.gmail.com#CAMsr+YGvp2wRx9pPSxaKFdaObxX8DzWse+OkWk2xpXSvT0rq-g@mail.gmail.com
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyFrom not supported in extended protocol mode")})
 	}
+	if _, ok := stmt.AST.(*tree.CopyTo); ok {
+		// Similarly, COPY TO would have to be executed through the CopyOut
+		// command when the portal is executed.
+		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyTo not supported in extended protocol mode")})
+	}
 
 	return c.stmtBuf.Push(
 		ctx,
@@ -865,6 +909,7 @@ func (c *conn) handleParse(
 			RawTypeHints: inTypeHints,
 			ParseStart:   startParse,
 			ParseEnd:     endParse,
+			TraceParent:  traceParentFromComment(query),
 		})
 }
 
@@ -1128,6 +1173,10 @@ func cookTag(tagStr string, buf []byte, stmtType tree.StatementType, rowsAffecte
 			tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
 		}
 
+	case tree.CopyOut:
+		tag = append(tag, ' ')
+		tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
+
 	case tree.CopyIn:
 		// Nothing to do. The CommandComplete message has been sent elsewhere.
 		panic(errors.AssertionFailedf("CopyIn statements should have been handled elsewhere " +
@@ -1201,6 +1250,172 @@ func (c *conn) bufferCloseComplete() {
 	}
 }
 
+// bufferCopyOutResponse adds the message initiating the Copy-out subprotocol
+// to the buffer. All the columns are sent in the same format.
+func (c *conn) bufferCopyOutResponse(numCols int, format tree.CopyFormat) {
+	fmtCode := pgwirebase.FormatText
+	if format == tree.CopyFormatBinary {
+		fmtCode = pgwirebase.FormatBinary
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	c.msgBuilder.writeByte(byte(fmtCode))
+	c.msgBuilder.putInt16(int16(numCols))
+	for i := 0; i < numCols; i++ {
+		c.msgBuilder.putInt16(int16(fmtCode))
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyData adds a CopyData message with the given contents to the
+// buffer.
+func (c *conn) bufferCopyData(data []byte) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	c.msgBuilder.write(data)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyDataRow serializes a row in the given COPY format and adds it to
+// the buffer as a CopyData message.
+func (c *conn) bufferCopyDataRow(
+	ctx context.Context,
+	row tree.Datums,
+	opts *sql.CopyFormatOptions,
+	conv sessiondata.DataConversionConfig,
+	types []*types.T,
+) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	if opts.Format == tree.CopyFormatBinary {
+		c.msgBuilder.putInt16(int16(len(row)))
+		for i, col := range row {
+			c.msgBuilder.writeBinaryDatum(ctx, col, conv.Location, types[i])
+		}
+	} else {
+		for i, col := range row {
+			if i > 0 {
+				c.msgBuilder.writeByte(opts.Delimiter)
+			}
+			if col == tree.DNull {
+				c.msgBuilder.writeString(opts.Null)
+				continue
+			}
+			c.copyBuf.wrapped.Reset()
+			c.copyBuf.writeTextDatum(ctx, col, conv, types[i])
+			// Skip the length prefix written by writeTextDatum.
+			field := c.copyBuf.wrapped.Bytes()[4:]
+			if opts.Format == tree.CopyFormatCSV {
+				writeCopyCSVField(&c.msgBuilder, field, opts)
+			} else {
+				writeCopyTextField(&c.msgBuilder, field, opts.Delimiter)
+			}
+		}
+		c.msgBuilder.writeByte('\n')
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyCSVHeader adds a CopyData message containing the header line of
+// the CSV format, listing the names of the columns, to the buffer.
+func (c *conn) bufferCopyCSVHeader(cols colinfo.ResultColumns, opts *sql.CopyFormatOptions) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	for i := range cols {
+		if i > 0 {
+			c.msgBuilder.writeByte(opts.Delimiter)
+		}
+		writeCopyCSVField(&c.msgBuilder, []byte(cols[i].Name), opts)
+	}
+	c.msgBuilder.writeByte('\n')
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// copyBinarySignature is the header of the binary COPY format: the 11-byte
+// signature followed by the flags field and the length of the header extension
+// area, both of which are zero.
+const copyBinarySignature = "PGCOPY\n\377\r\n\000" + "\x00\x00\x00\x00" + "\x00\x00\x00\x00"
+
+// writeCopyTextField writes a field of the text COPY format, escaping the
+// backslashes, the delimiter and the control characters it contains.
+func writeCopyTextField(b *writeBuffer, field []byte, delim byte) {
+	start := 0
+	for i, ch := range field {
+		var esc byte
+		switch ch {
+		case '\\':
+			esc = '\\'
+		case '\b':
+			esc = 'b'
+		case '\f':
+			esc = 'f'
+		case '\n':
+			esc = 'n'
+		case '\r':
+			esc = 'r'
+		case '\t':
+			esc = 't'
+		case '\v':
+			esc = 'v'
+		default:
+			if ch != delim {
+				continue
+			}
+			esc = ch
+		}
+		b.write(field[start:i])
+		b.writeByte('\\')
+		b.writeByte(esc)
+		start = i + 1
+	}
+	b.write(field[start:])
+}
+
+// writeCopyCSVField writes a field of the CSV COPY format. The field is quoted
+// if it contains the delimiter, a quote or a newline, and also if it matches
+// the null string, so that it isn't mistaken for a NULL. Quotes within quoted
+// fields are escaped by doubling them.
+func writeCopyCSVField(b *writeBuffer, field []byte, opts *sql.CopyFormatOptions) {
+	needsQuote := string(field) == opts.Null || string(field) == `\.`
+	for i := 0; i < len(field) && !needsQuote; i++ {
+		switch field[i] {
+		case opts.Delimiter, opts.Quote, '\n', '\r':
+			needsQuote = true
+		}
+	}
+	if !needsQuote {
+		b.write(field)
+		return
+	}
+	b.writeByte(opts.Quote)
+	start := 0
+	for i, ch := range field {
+		if ch == opts.Quote {
+			b.write(field[start : i+1])
+			start = i
+		}
+	}
+	b.write(field[start:])
+	b.writeByte(opts.Quote)
+}
+
+// bufferCopyDone adds the messages terminating the Copy-out subprotocol to the
+// buffer.
+func (c *conn) bufferCopyDone(format tree.CopyFormat) {
+	if format == tree.CopyFormatBinary {
+		// The binary format ends with a trailer consisting of a 16-bit -1.
+		c.bufferCopyData([]byte{0xff, 0xff})
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
 func (c *conn) bufferCommandComplete(tag []byte) {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgCommandComplete)
 	c.msgBuilder.write(tag)
@@ -1493,6 +1708,17 @@ func (c *conn) CreateCopyInResult(pos sql.CmdPos) sql.CopyInResult {
 	return c.newMiscResult(pos, noCompletionMsg)
 }
 
+// CreateCopyOutResult is part of the sql.ClientComm interface.
+func (c *conn) CreateCopyOutResult(
+	pos sql.CmdPos, conv sessiondata.DataConversionConfig,
+) sql.CopyOutResult {
+	res := c.newMiscResult(pos, commandComplete)
+	res.conv = conv
+	res.cmdCompleteTag = "COPY"
+	res.stmtType = tree.CopyOut
+	return res
+}
+
 // pgwireReader is an io.Reader that wraps a conn, maintaining its metrics as
 // it is consumed.
 type pgwireReader struct {
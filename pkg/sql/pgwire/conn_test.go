@@ -1252,3 +1252,29 @@ func TestConnCloseCancelsAuth(t *testing.T) {
 	// Check that the auth process indeed noticed the cancelation.
 	<-authBlocked
 }
+
+func TestTraceParentFromComment(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const traceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{query: "SELECT 1", expected: ""},
+		{query: "SELECT 'traceparent'", expected: ""},
+		{query: "SELECT 1 -- traceparent='" + traceParent + "'", expected: ""},
+		{query: "SELECT 1 /*traceparent='" + traceParent + "'*/", expected: traceParent},
+		{
+			query:    "/* app='x', traceparent = '" + traceParent + "',\n tracestate='a=b' */ SELECT 1",
+			expected: traceParent,
+		},
+		{query: "SELECT 1; /* traceparent='" + traceParent + "' */ SELECT 2", expected: traceParent},
+	}
+	for _, tc := range testCases {
+		if actual := traceParentFromComment(tc.query); actual != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.query, tc.expected, actual)
+		}
+	}
+}
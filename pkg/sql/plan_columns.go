@@ -95,6 +95,8 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 		return n.resultColumns
 	case *invertedJoinNode:
 		return n.columns
+	case *fetchNode:
+		return n.columns
 
 	// Nodes with a fixed schema.
 	case *scrubNode:
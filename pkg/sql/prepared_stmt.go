@@ -50,6 +50,11 @@ type PreparedStatement struct {
 	// if it is used by the optimizer as a starting point.
 	Memo *memo.Memo
 
+	// TraceParent is the W3C traceparent passed by the client in a comment of
+	// the prepared query, if any. Executions of the statement are part of the
+	// client's trace.
+	TraceParent string
+
 	// refCount keeps track of the number of references to this PreparedStatement.
 	// New references are registered through incRef().
 	// Once refCount hits 0 (through calls to decRef()), the following memAcc is
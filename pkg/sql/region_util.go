@@ -0,0 +1,268 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/proto"
+)
+
+// regionLocalityTierKey is the locality tier key used to determine which
+// region a node is in.
+const regionLocalityTierKey = "region"
+
+// minNumRegionsForSurviveRegionFailure is the minimum number of regions a
+// database must have to survive the failure of an entire region.
+const minNumRegionsForSurviveRegionFailure = 3
+
+// liveClusterRegions is the set of regions reported by the localities of the
+// nodes in the cluster.
+type liveClusterRegions map[string]struct{}
+
+func (s liveClusterRegions) isActive(region string) bool {
+	_, ok := s[region]
+	return ok
+}
+
+func (s liveClusterRegions) toStrings() []string {
+	ret := make([]string, 0, len(s))
+	for region := range s {
+		ret = append(ret, region)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// getLiveClusterRegionsFromNodes returns the set of regions reported by the
+// nodes in the cluster.
+func getLiveClusterRegionsFromNodes(
+	ctx context.Context, getNodes nodeGetter,
+) (liveClusterRegions, error) {
+	nodes, err := getNodes(ctx, &serverpb.NodesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(liveClusterRegions)
+	for _, node := range nodes.Nodes {
+		if region, ok := node.Desc.Locality.Find(regionLocalityTierKey); ok {
+			ret[region] = struct{}{}
+		}
+	}
+	return ret, nil
+}
+
+// checkLiveClusterRegion returns an error if the given region is not
+// reported by any node in the cluster.
+func checkLiveClusterRegion(liveRegions liveClusterRegions, region string) error {
+	if liveRegions.isActive(region) {
+		return nil
+	}
+	err := pgerror.Newf(pgcode.InvalidName, "region %q does not exist", region)
+	if len(liveRegions) == 0 {
+		return errors.WithHint(err,
+			`no regions are available; nodes must be started with a locality containing a "region" tier`)
+	}
+	return errors.WithHintf(err, "valid regions: %s", strings.Join(liveRegions.toStrings(), ", "))
+}
+
+// getLiveClusterRegions returns the set of regions reported by the nodes in
+// the cluster, as seen by the status server available to the planner.
+func (p *planner) getLiveClusterRegions(ctx context.Context) (liveClusterRegions, error) {
+	ss, err := p.ExecCfg().NodesStatusServer.OptionalNodesStatusServer(multitenancyZoneCfgIssueNo)
+	if err != nil {
+		return nil, err
+	}
+	return getLiveClusterRegionsFromNodes(ctx, ss.Nodes)
+}
+
+// regionConstraint returns the constraint which pins a replica or lease to
+// the given region.
+func regionConstraint(region string) zonepb.Constraint {
+	return zonepb.Constraint{
+		Type:  zonepb.Constraint_REQUIRED,
+		Key:   regionLocalityTierKey,
+		Value: region,
+	}
+}
+
+// numVotersForRegionConfig returns the number of voting replicas a range in a
+// multi-region database should have. Surviving a zone failure only requires a
+// quorum of three voters, which are all placed in the primary region. Surviving
+// a region failure requires that no single region holds a majority of the
+// voters, so five voters are used in that case.
+func numVotersForRegionConfig(regionConfig *descpb.DatabaseDescriptor_RegionConfig) int32 {
+	if regionConfig.SurvivalGoal == descpb.SurvivalGoal_REGION_FAILURE {
+		return 5
+	}
+	return 3
+}
+
+// numReplicasForRegionConfig returns the number of replicas a range in a
+// multi-region database should have. Every region gets at least one replica.
+// When surviving a zone failure, the voters are all in the primary region and
+// every other region is held by a non-voting replica. When surviving a region
+// failure, the voters are spread across regions and only regions in excess of
+// the voters get a non-voting replica.
+//
+// Without non-voting replicas, every replica is a voter and the replicas are
+// simply spread across the regions.
+func numReplicasForRegionConfig(
+	regionConfig *descpb.DatabaseDescriptor_RegionConfig, nonVotersEnabled bool,
+) int32 {
+	numRegions := int32(len(regionConfig.Regions))
+	numVoters := numVotersForRegionConfig(regionConfig)
+	if nonVotersEnabled && regionConfig.SurvivalGoal == descpb.SurvivalGoal_ZONE_FAILURE {
+		return numVoters + numRegions - 1
+	}
+	if numRegions < numVoters {
+		return numVoters
+	}
+	return numRegions
+}
+
+// zoneConfigFromRegionConfig generates the replication related fields of a
+// zone configuration for a multi-region database: the number of replicas, a
+// per-replica constraint placing one replica in each region and a lease
+// preference for the primary region.
+//
+// If nonVotersEnabled is set, the number of voters and voter constraints are
+// generated as well. Surviving a zone failure pins all the voters to the
+// primary region so that writes only need a quorum within that region, while
+// the non-voting replicas serve follower reads in the other regions.
+// Surviving a region failure places two of the five voters in the primary
+// region so that a quorum can usually be reached with a single round trip to
+// the nearest other region.
+func zoneConfigFromRegionConfig(
+	regionConfig *descpb.DatabaseDescriptor_RegionConfig, nonVotersEnabled bool,
+) *zonepb.ZoneConfig {
+	zone := zonepb.NewZoneConfig()
+	zone.NumReplicas = proto.Int32(numReplicasForRegionConfig(regionConfig, nonVotersEnabled))
+	zone.Constraints = make([]zonepb.ConstraintsConjunction, len(regionConfig.Regions))
+	for i, region := range regionConfig.Regions {
+		zone.Constraints[i] = zonepb.ConstraintsConjunction{
+			NumReplicas: 1,
+			Constraints: []zonepb.Constraint{regionConstraint(region)},
+		}
+	}
+	zone.InheritedConstraints = false
+	if nonVotersEnabled {
+		zone.NumVoters = proto.Int32(numVotersForRegionConfig(regionConfig))
+		primary := zonepb.ConstraintsConjunction{
+			Constraints: []zonepb.Constraint{regionConstraint(regionConfig.PrimaryRegion)},
+		}
+		if regionConfig.SurvivalGoal == descpb.SurvivalGoal_REGION_FAILURE {
+			primary.NumReplicas = 2
+		}
+		zone.VoterConstraints = []zonepb.ConstraintsConjunction{primary}
+	}
+	zone.LeasePreferences = []zonepb.LeasePreference{
+		{Constraints: []zonepb.Constraint{regionConstraint(regionConfig.PrimaryRegion)}},
+	}
+	zone.InheritedLeasePreferences = false
+	return zone
+}
+
+// applyRegionConfigToDatabaseZoneConfig updates the zone configuration of the
+// given database so that its replication fields match the given region
+// config. Fields of an existing zone configuration unrelated to replica
+// placement (e.g. the GC TTL or range sizes) are preserved. A nil region
+// config resets the replication fields so that they are inherited again.
+func applyRegionConfigToDatabaseZoneConfig(
+	ctx context.Context,
+	txn *kv.Txn,
+	dbID descpb.ID,
+	regionConfig *descpb.DatabaseDescriptor_RegionConfig,
+	execCfg *ExecutorConfig,
+) error {
+	zone, err := getZoneConfigRaw(ctx, txn, execCfg.Codec, dbID)
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		zone = zonepb.NewZoneConfig()
+	}
+	if regionConfig == nil {
+		zone.NumReplicas = nil
+		zone.NumVoters = nil
+		zone.Constraints = nil
+		zone.InheritedConstraints = true
+		zone.VoterConstraints = nil
+		zone.NullVoterConstraintsIsEmpty = false
+		zone.LeasePreferences = nil
+		zone.InheritedLeasePreferences = true
+	} else {
+		regionZone := zoneConfigFromRegionConfig(
+			regionConfig,
+			execCfg.Settings.Version.IsActive(ctx, clusterversion.VersionNonVotingReplicas),
+		)
+		zone.NumReplicas = regionZone.NumReplicas
+		zone.NumVoters = regionZone.NumVoters
+		zone.Constraints = regionZone.Constraints
+		zone.InheritedConstraints = regionZone.InheritedConstraints
+		zone.VoterConstraints = regionZone.VoterConstraints
+		zone.NullVoterConstraintsIsEmpty = regionZone.NullVoterConstraintsIsEmpty
+		zone.LeasePreferences = regionZone.LeasePreferences
+		zone.InheritedLeasePreferences = regionZone.InheritedLeasePreferences
+	}
+	if err := zone.Validate(); err != nil {
+		return pgerror.Wrap(err, pgcode.CheckViolation, "could not validate zone config")
+	}
+	if err := zone.ValidateTandemFields(); err != nil {
+		return pgerror.Wrap(err, pgcode.InvalidParameterValue, "could not validate zone config")
+	}
+	if zone.Equal(zonepb.NewZoneConfig()) {
+		_, err = execCfg.InternalExecutor.Exec(ctx, "delete-zone", txn,
+			"DELETE FROM system.zones WHERE id = $1", dbID)
+		return err
+	}
+	_, err = writeZoneConfig(ctx, txn, dbID, nil /* table */, zone, execCfg, false /* hasNewSubzones */)
+	return err
+}
+
+// survivalGoalFromSurvive converts a SURVIVE clause into the survival goal
+// stored on the database descriptor.
+func survivalGoalFromSurvive(survive tree.Survive) (descpb.SurvivalGoal, error) {
+	switch survive {
+	case tree.SurviveDefault, tree.SurviveAvailabilityZoneFailure:
+		return descpb.SurvivalGoal_ZONE_FAILURE, nil
+	case tree.SurviveRegionFailure:
+		return descpb.SurvivalGoal_REGION_FAILURE, nil
+	default:
+		return 0, errors.AssertionFailedf("unknown survival goal: %d", survive)
+	}
+}
+
+// validateSurvivalGoal returns an error if the given region config cannot
+// achieve its survival goal.
+func validateSurvivalGoal(regionConfig *descpb.DatabaseDescriptor_RegionConfig) error {
+	if regionConfig.SurvivalGoal == descpb.SurvivalGoal_REGION_FAILURE &&
+		len(regionConfig.Regions) < minNumRegionsForSurviveRegionFailure {
+		return errors.WithHintf(
+			pgerror.Newf(pgcode.InvalidParameterValue,
+				"at least %d regions are required for surviving a region failure",
+				minNumRegionsForSurviveRegionFailure),
+			"you must add additional regions to the database or change the survivability goal",
+		)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/server/status/statuspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneConfigFromRegionConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	regionConstraints := func(regions ...string) []zonepb.ConstraintsConjunction {
+		ret := make([]zonepb.ConstraintsConjunction, len(regions))
+		for i, region := range regions {
+			ret[i] = zonepb.ConstraintsConjunction{
+				NumReplicas: 1,
+				Constraints: []zonepb.Constraint{regionConstraint(region)},
+			}
+		}
+		return ret
+	}
+
+	voterConstraints := func(region string, numReplicas int32) []zonepb.ConstraintsConjunction {
+		return []zonepb.ConstraintsConjunction{{
+			NumReplicas: numReplicas,
+			Constraints: []zonepb.Constraint{regionConstraint(region)},
+		}}
+	}
+	leasePreferences := func(region string) []zonepb.LeasePreference {
+		return []zonepb.LeasePreference{
+			{Constraints: []zonepb.Constraint{regionConstraint(region)}},
+		}
+	}
+
+	testCases := []struct {
+		desc             string
+		regionConfig     descpb.DatabaseDescriptor_RegionConfig
+		nonVotersEnabled bool
+		expected         zonepb.ZoneConfig
+	}{
+		{
+			desc: "one region, zone survival",
+			regionConfig: descpb.DatabaseDescriptor_RegionConfig{
+				Regions:       []string{"region_a"},
+				SurvivalGoal:  descpb.SurvivalGoal_ZONE_FAILURE,
+				PrimaryRegion: "region_a",
+			},
+			nonVotersEnabled: true,
+			expected: zonepb.ZoneConfig{
+				NumReplicas:      proto.Int32(3),
+				NumVoters:        proto.Int32(3),
+				Constraints:      regionConstraints("region_a"),
+				VoterConstraints: voterConstraints("region_a", 0),
+				LeasePreferences: leasePreferences("region_a"),
+			},
+		},
+		{
+			desc: "four regions, zone survival",
+			regionConfig: descpb.DatabaseDescriptor_RegionConfig{
+				Regions:       []string{"region_a", "region_b", "region_c", "region_d"},
+				SurvivalGoal:  descpb.SurvivalGoal_ZONE_FAILURE,
+				PrimaryRegion: "region_b",
+			},
+			nonVotersEnabled: true,
+			expected: zonepb.ZoneConfig{
+				NumReplicas:      proto.Int32(6),
+				NumVoters:        proto.Int32(3),
+				Constraints:      regionConstraints("region_a", "region_b", "region_c", "region_d"),
+				VoterConstraints: voterConstraints("region_b", 0),
+				LeasePreferences: leasePreferences("region_b"),
+			},
+		},
+		{
+			desc: "three regions, region survival",
+			regionConfig: descpb.DatabaseDescriptor_RegionConfig{
+				Regions:       []string{"region_a", "region_b", "region_c"},
+				SurvivalGoal:  descpb.SurvivalGoal_REGION_FAILURE,
+				PrimaryRegion: "region_c",
+			},
+			nonVotersEnabled: true,
+			expected: zonepb.ZoneConfig{
+				NumReplicas:      proto.Int32(5),
+				NumVoters:        proto.Int32(5),
+				Constraints:      regionConstraints("region_a", "region_b", "region_c"),
+				VoterConstraints: voterConstraints("region_c", 2),
+				LeasePreferences: leasePreferences("region_c"),
+			},
+		},
+		{
+			desc: "six regions, region survival",
+			regionConfig: descpb.DatabaseDescriptor_RegionConfig{
+				Regions: []string{
+					"region_a", "region_b", "region_c", "region_d", "region_e", "region_f",
+				},
+				SurvivalGoal:  descpb.SurvivalGoal_REGION_FAILURE,
+				PrimaryRegion: "region_a",
+			},
+			nonVotersEnabled: true,
+			expected: zonepb.ZoneConfig{
+				NumReplicas: proto.Int32(6),
+				NumVoters:   proto.Int32(5),
+				Constraints: regionConstraints(
+					"region_a", "region_b", "region_c", "region_d", "region_e", "region_f",
+				),
+				VoterConstraints: voterConstraints("region_a", 2),
+				LeasePreferences: leasePreferences("region_a"),
+			},
+		},
+		{
+			desc: "four regions, zone survival, non-voters disabled",
+			regionConfig: descpb.DatabaseDescriptor_RegionConfig{
+				Regions:       []string{"region_a", "region_b", "region_c", "region_d"},
+				SurvivalGoal:  descpb.SurvivalGoal_ZONE_FAILURE,
+				PrimaryRegion: "region_b",
+			},
+			expected: zonepb.ZoneConfig{
+				NumReplicas:      proto.Int32(4),
+				Constraints:      regionConstraints("region_a", "region_b", "region_c", "region_d"),
+				LeasePreferences: leasePreferences("region_b"),
+			},
+		},
+		{
+			desc: "three regions, region survival, non-voters disabled",
+			regionConfig: descpb.DatabaseDescriptor_RegionConfig{
+				Regions:       []string{"region_a", "region_b", "region_c"},
+				SurvivalGoal:  descpb.SurvivalGoal_REGION_FAILURE,
+				PrimaryRegion: "region_c",
+			},
+			expected: zonepb.ZoneConfig{
+				NumReplicas:      proto.Int32(5),
+				Constraints:      regionConstraints("region_a", "region_b", "region_c"),
+				LeasePreferences: leasePreferences("region_c"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			zone := zoneConfigFromRegionConfig(&tc.regionConfig, tc.nonVotersEnabled)
+			require.Equal(t, tc.expected, *zone)
+			require.NoError(t, zone.Validate())
+			require.NoError(t, zone.ValidateTandemFields())
+		})
+	}
+}
+
+func TestGetLiveClusterRegions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	nodeWithLocality := func(tiers ...roachpb.Tier) statuspb.NodeStatus {
+		return statuspb.NodeStatus{
+			Desc: roachpb.NodeDescriptor{Locality: roachpb.Locality{Tiers: tiers}},
+		}
+	}
+	getNodes := func(context.Context, *serverpb.NodesRequest) (*serverpb.NodesResponse, error) {
+		return &serverpb.NodesResponse{
+			Nodes: []statuspb.NodeStatus{
+				nodeWithLocality(roachpb.Tier{Key: "region", Value: "us-east1"}, roachpb.Tier{Key: "az", Value: "a"}),
+				nodeWithLocality(roachpb.Tier{Key: "region", Value: "us-east1"}, roachpb.Tier{Key: "az", Value: "b"}),
+				nodeWithLocality(roachpb.Tier{Key: "region", Value: "europe-west1"}),
+				nodeWithLocality(roachpb.Tier{Key: "dc", Value: "dc1"}),
+			},
+		}, nil
+	}
+
+	regions, err := getLiveClusterRegionsFromNodes(context.Background(), getNodes)
+	require.NoError(t, err)
+	require.Equal(t, []string{"europe-west1", "us-east1"}, regions.toStrings())
+
+	require.NoError(t, checkLiveClusterRegion(regions, "us-east1"))
+	require.EqualError(t, checkLiveClusterRegion(regions, "us-west1"), `region "us-west1" does not exist`)
+	require.EqualError(t,
+		checkLiveClusterRegion(liveClusterRegions{}, "us-west1"), `region "us-west1" does not exist`)
+}
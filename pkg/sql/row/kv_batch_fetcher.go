@@ -132,7 +132,13 @@ func (f *txnKVFetcher) getBatchSizeForIdx(batchIdx int) int64 {
 // getKeyLockingStrength returns the configured per-key locking strength to use
 // for key-value scans.
 func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
-	switch f.lockStrength {
+	return GetKeyLockingStrength(f.lockStrength)
+}
+
+// GetKeyLockingStrength returns the per-key locking strength to use for
+// key-value scans performed with the given row-level locking strength.
+func GetKeyLockingStrength(lockStrength descpb.ScanLockingStrength) lock.Strength {
+	switch lockStrength {
 	case descpb.ScanLockingStrength_FOR_NONE:
 		return lock.None
 
@@ -140,9 +146,7 @@ func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
 		// Promote to FOR_SHARE.
 		fallthrough
 	case descpb.ScanLockingStrength_FOR_SHARE:
-		// We currently perform no per-key locking when FOR_SHARE is used
-		// because Shared locks have not yet been implemented.
-		return lock.None
+		return lock.Shared
 
 	case descpb.ScanLockingStrength_FOR_NO_KEY_UPDATE:
 		// Promote to FOR_UPDATE.
@@ -153,7 +157,7 @@ func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
 		return lock.Exclusive
 
 	default:
-		panic(errors.AssertionFailedf("unknown locking strength %s", f.lockStrength))
+		panic(errors.AssertionFailedf("unknown locking strength %s", lockStrength))
 	}
 }
 
@@ -0,0 +1,178 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// defaultRowLevelTTLCron is the schedule on which the row-level TTL job of a
+// table runs if the ttl_job_cron storage parameter is not set.
+const defaultRowLevelTTLCron = "@hourly"
+
+// checkRowLevelTTLSupported returns an error if row-level TTL cannot be used
+// for the table being created.
+func checkRowLevelTTLSupported(
+	ctx context.Context, st *cluster.Settings, n *tree.CreateTable,
+) error {
+	if st == nil {
+		return pgerror.New(pgcode.FeatureNotSupported, "row-level TTL is not supported in this context")
+	}
+	if version := st.Version.ActiveVersionOrEmpty(ctx); version == (clusterversion.ClusterVersion{}) ||
+		!version.IsActive(clusterversion.VersionRowLevelTTL) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"row-level TTL is not supported until version upgrade is finalized")
+	}
+	if n.Interleave != nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"interleaved tables do not support row-level TTL")
+	}
+	return nil
+}
+
+// addRowLevelTTLExpirationColumn adds the hidden crdb_internal_expiration
+// column to a table created with the ttl_expire_after storage parameter. The
+// column defaults to the insertion time plus the TTL interval; since it has no
+// ON UPDATE expression, rows expire relative to the time they were inserted
+// unless the column is updated explicitly.
+func addRowLevelTTLExpirationColumn(
+	ctx context.Context,
+	desc *tabledesc.Mutable,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+) error {
+	colName := tree.Name(descpb.RowLevelTTLExpirationColumnName)
+	if _, _, err := desc.FindColumnByName(colName); err == nil {
+		return pgerror.Newf(pgcode.DuplicateColumn,
+			"column name %q is reserved for row-level TTL", colName)
+	}
+	interval, err := tree.ParseDInterval(desc.RowLevelTTL.ExpireAfter)
+	if err != nil {
+		return err
+	}
+	def := &tree.ColumnTableDef{
+		Name: colName,
+		Type: types.TimestampTZ,
+	}
+	def.Nullable.Nullability = tree.NotNull
+	def.DefaultExpr.Expr = &tree.BinaryExpr{
+		Operator: tree.Plus,
+		Left:     &tree.FuncExpr{Func: tree.WrapFunction("current_timestamp")},
+		Right:    interval,
+	}
+	col, _, _, err := tabledesc.MakeColumnDefDescs(ctx, def, semaCtx, evalCtx)
+	if err != nil {
+		return err
+	}
+	col.Hidden = true
+	desc.AddColumn(col)
+	return nil
+}
+
+// validateRowLevelTTL validates the row-level TTL configuration of a new table
+// once all of its columns and its primary key are in place.
+func validateRowLevelTTL(
+	ctx context.Context, desc *tabledesc.Mutable, semaCtx *tree.SemaContext, tn *tree.TableName,
+) error {
+	if exprStr := desc.RowLevelTTL.ExpirationExpr; exprStr != "" {
+		expr, err := parser.ParseExpr(exprStr)
+		if err != nil {
+			return pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+				"ttl_expiration_expression %q must be a valid expression", exprStr)
+		}
+		if _, _, err := schemaexpr.DequalifyAndValidateExpr(
+			ctx,
+			desc,
+			expr,
+			types.TimestampTZ,
+			"ttl_expiration_expression",
+			semaCtx,
+			tree.VolatilityStable,
+			tn,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createRowLevelTTLScheduledJob creates the schedule which runs the row-level
+// TTL job of the given table.
+func (p *planner) createRowLevelTTLScheduledJob(
+	ctx context.Context, tableDesc *tabledesc.Mutable,
+) (*jobs.ScheduledJob, error) {
+	sj := jobs.NewScheduledJob(jobSchedulerEnv(p.ExecCfg()))
+	sj.SetScheduleLabel(fmt.Sprintf("row-level-ttl-%d", tableDesc.ID))
+	sj.SetOwner(p.User())
+
+	cron := tableDesc.RowLevelTTL.JobCron
+	if cron == "" {
+		cron = defaultRowLevelTTLCron
+	}
+	if err := sj.SetSchedule(cron); err != nil {
+		return nil, err
+	}
+	// A TTL job which is still running when the next one is due just delays
+	// the deletion of the rows which expired in the meantime, so we skip
+	// rather than pile up runs.
+	sj.SetScheduleDetails(jobspb.ScheduleDetails{
+		Wait:    jobspb.ScheduleDetails_SKIP,
+		OnError: jobspb.ScheduleDetails_RETRY_SCHED,
+	})
+
+	args, err := pbtypes.MarshalAny(&jobspb.ScheduledRowLevelTTLArgs{TableID: tableDesc.ID})
+	if err != nil {
+		return nil, err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		jobspb.ExecutionArguments{Args: args},
+	)
+	if err := sj.Create(ctx, p.ExecCfg().InternalExecutor, p.txn); err != nil {
+		return nil, err
+	}
+	return sj, nil
+}
+
+// deleteRowLevelTTLScheduledJob deletes the schedule which runs the row-level
+// TTL job of a table.
+func (p *planner) deleteRowLevelTTLScheduledJob(ctx context.Context, scheduleID int64) error {
+	env := jobSchedulerEnv(p.ExecCfg())
+	_, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx,
+		"delete-row-level-ttl-schedule",
+		p.txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUser},
+		fmt.Sprintf(
+			"DELETE FROM %s WHERE schedule_id = $1",
+			env.ScheduledJobsTableName(),
+		),
+		scheduleID,
+	)
+	return err
+}
@@ -17,8 +17,6 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
-	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
-	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
@@ -26,7 +24,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
-	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -459,80 +456,11 @@ func (w *windower) processPartition(
 		}
 
 		if windowFn.frame != nil {
-			var err error
-			if frameRun.Frame, err = windowFn.frame.ConvertToAST(); err != nil {
+			if err := windowFn.frame.InitFrameRun(
+				frameRun, windowFn.ordering, w.inputTypes, &w.datumAlloc,
+			); err != nil {
 				return err
 			}
-			startBound, endBound := windowFn.frame.Bounds.Start, windowFn.frame.Bounds.End
-			if startBound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING ||
-				startBound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING {
-				switch windowFn.frame.Mode {
-				case execinfrapb.WindowerSpec_Frame_ROWS:
-					frameRun.StartBoundOffset = tree.NewDInt(tree.DInt(int(startBound.IntOffset)))
-				case execinfrapb.WindowerSpec_Frame_RANGE:
-					datum, rem, err := rowenc.DecodeTableValue(&w.datumAlloc, startBound.OffsetType.Type, startBound.TypedOffset)
-					if err != nil {
-						return errors.NewAssertionErrorWithWrappedErrf(err,
-							"error decoding %d bytes", errors.Safe(len(startBound.TypedOffset)))
-					}
-					if len(rem) != 0 {
-						return errors.AssertionFailedf(
-							"%d trailing bytes in encoded value", errors.Safe(len(rem)))
-					}
-					frameRun.StartBoundOffset = datum
-				case execinfrapb.WindowerSpec_Frame_GROUPS:
-					frameRun.StartBoundOffset = tree.NewDInt(tree.DInt(int(startBound.IntOffset)))
-				default:
-					return errors.AssertionFailedf(
-						"unexpected WindowFrameMode: %d", errors.Safe(windowFn.frame.Mode))
-				}
-			}
-			if endBound != nil {
-				if endBound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_PRECEDING ||
-					endBound.BoundType == execinfrapb.WindowerSpec_Frame_OFFSET_FOLLOWING {
-					switch windowFn.frame.Mode {
-					case execinfrapb.WindowerSpec_Frame_ROWS:
-						frameRun.EndBoundOffset = tree.NewDInt(tree.DInt(int(endBound.IntOffset)))
-					case execinfrapb.WindowerSpec_Frame_RANGE:
-						datum, rem, err := rowenc.DecodeTableValue(&w.datumAlloc, endBound.OffsetType.Type, endBound.TypedOffset)
-						if err != nil {
-							return errors.NewAssertionErrorWithWrappedErrf(err,
-								"error decoding %d bytes", errors.Safe(len(endBound.TypedOffset)))
-						}
-						if len(rem) != 0 {
-							return errors.AssertionFailedf(
-								"%d trailing bytes in encoded value", errors.Safe(len(rem)))
-						}
-						frameRun.EndBoundOffset = datum
-					case execinfrapb.WindowerSpec_Frame_GROUPS:
-						frameRun.EndBoundOffset = tree.NewDInt(tree.DInt(int(endBound.IntOffset)))
-					default:
-						return errors.AssertionFailedf("unexpected WindowFrameMode: %d",
-							errors.Safe(windowFn.frame.Mode))
-					}
-				}
-			}
-			if frameRun.RangeModeWithOffsets() {
-				ordCol := windowFn.ordering.Columns[0]
-				frameRun.OrdColIdx = int(ordCol.ColIdx)
-				// We need this +1 because encoding.Direction has extra value "_"
-				// as zeroth "entry" which its proto equivalent doesn't have.
-				frameRun.OrdDirection = encoding.Direction(ordCol.Direction + 1)
-
-				colTyp := w.inputTypes[ordCol.ColIdx]
-				// Type of offset depends on the ordering column's type.
-				offsetTyp := colTyp
-				if types.IsDateTimeType(colTyp) {
-					// For datetime related ordering columns, offset must be an Interval.
-					offsetTyp = types.Interval
-				}
-				plusOp, minusOp, found := tree.WindowFrameRangeOps{}.LookupImpl(colTyp, offsetTyp)
-				if !found {
-					return pgerror.Newf(pgcode.Windowing,
-						"given logical offset cannot be combined with ordering column")
-				}
-				frameRun.PlusOp, frameRun.MinusOp = plusOp, minusOp
-			}
 		}
 
 		builtin := w.builtins[windowFnIdx]
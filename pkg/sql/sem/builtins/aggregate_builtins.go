@@ -205,6 +205,28 @@ var aggregates = map[string]builtinDefinition{
 			"Calculates the boolean value of `AND`ing all selected values.", tree.VolatilityImmutable),
 	),
 
+	// grouping is replaced by the optimizer with a constant for each grouping
+	// set, so it is never evaluated as an aggregate.
+	"grouping": makeBuiltin(aggPropsNullableArgs(),
+		tree.Overload{
+			Types:         tree.VariadicType{VarType: types.Any},
+			ReturnType:    tree.FixedReturnType(types.Int),
+			AggregateFunc: builtinMustNotRun,
+			WindowFunc: func(params []*types.T, evalCtx *tree.EvalContext) tree.WindowFunc {
+				return newFramableAggregateWindow(
+					builtinMustNotRun(params, evalCtx, nil /* arguments */),
+					func(evalCtx *tree.EvalContext, arguments tree.Datums) tree.AggregateFunc {
+						return builtinMustNotRun(params, evalCtx, arguments)
+					},
+				)
+			},
+			Info: "Returns a bit mask indicating which of the given GROUP BY expressions are " +
+				"not included in the grouping set of the current row. The least significant bit " +
+				"corresponds to the last argument.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
 	"max": collectOverloads(aggProps(), allMaxMinAggregateTypes,
 		func(t *types.T) tree.Overload {
 			info := "Identifies the maximum selected value."
@@ -2183,6 +2183,56 @@ nearest replica.`, defaultFollowerReadDuration),
 		},
 	),
 
+	tree.WithMinTimestampFunctionName: makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"min_timestamp", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if err := checkBoundedStalenessEnabled(ctx, tree.WithMinTimestampFunctionName); err != nil {
+					return nil, err
+				}
+				return args[0], nil
+			},
+			Info: `When used in the AS OF SYSTEM TIME clause of a SELECT statement in an
+implicit transaction, performs a bounded staleness read at the newest timestamp,
+no older than min_timestamp, at which all the ranges touched by the statement can
+be read from the nearest replica. If no such timestamp exists, the statement
+reads the latest data from the leaseholders instead.
+
+Note that this function requires an enterprise license on a CCL distribution.`,
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
+	tree.WithMaxStalenessFunctionName: makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"max_staleness", types.Interval}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if err := checkBoundedStalenessEnabled(ctx, tree.WithMaxStalenessFunctionName); err != nil {
+					return nil, err
+				}
+				d := tree.MustBeDInterval(args[0]).Duration
+				if d.Compare(duration.Duration{}) <= 0 {
+					return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+						"%s: interval must be positive", tree.WithMaxStalenessFunctionName)
+				}
+				return tree.MakeDTimestampTZ(duration.Add(ctx.GetStmtTimestamp(), d.Mul(-1)), time.Microsecond)
+			},
+			Info: `When used in the AS OF SYSTEM TIME clause of a SELECT statement in an
+implicit transaction, performs a bounded staleness read at the newest timestamp,
+no older than max_staleness before the statement time, at which all the ranges
+touched by the statement can be read from the nearest replica. If no such
+timestamp exists, the statement reads the latest data from the leaseholders
+instead.
+
+Note that this function requires an enterprise license on a CCL distribution.`,
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	"cluster_logical_timestamp": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -6386,6 +6436,16 @@ func requireNonNull(d tree.Datum) error {
 	return nil
 }
 
+// checkBoundedStalenessEnabled returns an error if bounded staleness reads,
+// which are a follower reads feature, can't be used.
+func checkBoundedStalenessEnabled(ctx *tree.EvalContext, name string) error {
+	if EvalFollowerReadOffset == nil {
+		return pgerror.Newf(pgcode.CCLRequired, "%s requires a CCL distribution", name)
+	}
+	_, err := EvalFollowerReadOffset(ctx.ClusterID, ctx.Settings)
+	return err
+}
+
 func followerReadTimestamp(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
 	ts, err := recentTimestamp(ctx)
 	if err != nil {
@@ -34,13 +34,48 @@ const FollowerReadTimestampFunctionName = "follower_read_timestamp"
 // "experimental_" function, which we keep for backwards compatibility.
 const FollowerReadTimestampExperimentalFunctionName = "experimental_follower_read_timestamp"
 
-var errInvalidExprForAsOf = errors.Errorf("AS OF SYSTEM TIME: only constant expressions or " +
+// WithMinTimestampFunctionName is the name of the function which can be used
+// with AOST clauses to perform a bounded staleness read that reads data at a
+// timestamp no older than its argument.
+const WithMinTimestampFunctionName = "with_min_timestamp"
+
+// WithMaxStalenessFunctionName is the name of the function which can be used
+// with AOST clauses to perform a bounded staleness read that reads data no
+// staler than its argument.
+const WithMaxStalenessFunctionName = "with_max_staleness"
+
+var errInvalidExprForAsOf = errors.Errorf("AS OF SYSTEM TIME: only constant expressions, " +
+	WithMinTimestampFunctionName + ", " + WithMaxStalenessFunctionName + " or " +
 	FollowerReadTimestampFunctionName + " are allowed")
 
+// AsOfSystemTime is the result of evaluating an AS OF SYSTEM TIME clause.
+type AsOfSystemTime struct {
+	// Timestamp is the timestamp the query reads at. For bounded staleness
+	// reads, it is instead the minimum timestamp the query may read at.
+	Timestamp hlc.Timestamp
+	// BoundedStaleness is set if the clause uses one of the bounded staleness
+	// functions. Such a query reads at the newest timestamp, no older than
+	// Timestamp, at which all of its reads can be served locally.
+	BoundedStaleness bool
+}
+
 // EvalAsOfTimestamp evaluates the timestamp argument to an AS OF SYSTEM TIME query.
 func EvalAsOfTimestamp(
 	ctx context.Context, asOf AsOfClause, semaCtx *SemaContext, evalCtx *EvalContext,
-) (tsss hlc.Timestamp, err error) {
+) (hlc.Timestamp, error) {
+	asOfSystemTime, err := EvalAsOf(ctx, asOf, semaCtx, evalCtx, false /* allowBoundedStaleness */)
+	return asOfSystemTime.Timestamp, err
+}
+
+// EvalAsOf evaluates the argument to an AS OF SYSTEM TIME query. Bounded
+// staleness reads are only accepted if allowBoundedStaleness is set.
+func EvalAsOf(
+	ctx context.Context,
+	asOf AsOfClause,
+	semaCtx *SemaContext,
+	evalCtx *EvalContext,
+	allowBoundedStaleness bool,
+) (AsOfSystemTime, error) {
 	// We need to save and restore the previous value of the field in
 	// semaCtx in case we are recursively called within a subquery
 	// context.
@@ -49,42 +84,61 @@ func EvalAsOfTimestamp(
 	scalarProps.Require("AS OF SYSTEM TIME", RejectSpecial|RejectSubqueries)
 
 	// In order to support the follower reads feature we permit this expression
-	// to be a simple invocation of the `FollowerReadTimestampFunction`.
-	// Over time we could expand the set of allowed functions or expressions.
-	// All non-function expressions must be const and must TypeCheck into a
-	// string.
+	// to be a simple invocation of the `FollowerReadTimestampFunction`, and in
+	// order to support bounded staleness reads we permit invocations of the
+	// `WithMinTimestamp` and `WithMaxStaleness` functions with constant
+	// arguments. Over time we could expand the set of allowed functions or
+	// expressions. All non-function expressions must be const and must
+	// TypeCheck into a string.
 	var te TypedExpr
+	var boundedStaleness bool
 	if fe, ok := asOf.Expr.(*FuncExpr); ok {
 		def, err := fe.Func.Resolve(semaCtx.SearchPath)
 		if err != nil {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
-		if def.Name != FollowerReadTimestampFunctionName &&
-			def.Name != FollowerReadTimestampExperimentalFunctionName {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
+		switch def.Name {
+		case FollowerReadTimestampFunctionName, FollowerReadTimestampExperimentalFunctionName:
+		case WithMinTimestampFunctionName, WithMaxStalenessFunctionName:
+			if !allowBoundedStaleness {
+				return AsOfSystemTime{}, pgerror.Newf(pgcode.FeatureNotSupported,
+					"AS OF SYSTEM TIME: %s can only be used with a SELECT statement "+
+						"in an implicit transaction", def.Name)
+			}
+			boundedStaleness = true
+		default:
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
 		if te, err = fe.TypeCheck(ctx, semaCtx, types.TimestampTZ); err != nil {
-			return hlc.Timestamp{}, err
+			return AsOfSystemTime{}, err
+		}
+		for _, arg := range te.(*FuncExpr).Exprs {
+			if !IsConst(evalCtx, arg.(TypedExpr)) {
+				return AsOfSystemTime{}, errInvalidExprForAsOf
+			}
 		}
 	} else {
 		var err error
 		te, err = asOf.Expr.TypeCheck(ctx, semaCtx, types.String)
 		if err != nil {
-			return hlc.Timestamp{}, err
+			return AsOfSystemTime{}, err
 		}
 		if !IsConst(evalCtx, te) {
-			return hlc.Timestamp{}, errInvalidExprForAsOf
+			return AsOfSystemTime{}, errInvalidExprForAsOf
 		}
 	}
 
 	d, err := te.Eval(evalCtx)
 	if err != nil {
-		return hlc.Timestamp{}, err
+		return AsOfSystemTime{}, err
 	}
 
 	stmtTimestamp := evalCtx.GetStmtTimestamp()
 	ts, err := DatumToHLC(evalCtx, stmtTimestamp, d)
-	return ts, errors.Wrap(err, "AS OF SYSTEM TIME")
+	if err != nil {
+		return AsOfSystemTime{}, errors.Wrap(err, "AS OF SYSTEM TIME")
+	}
+	return AsOfSystemTime{Timestamp: ts, BoundedStaleness: boundedStaleness}, nil
 }
 
 // DatumToHLC performs the conversion from a Datum to an HLC timestamp.
@@ -13,6 +13,10 @@ package tree
 // CreateChangefeed represents a CREATE CHANGEFEED statement.
 type CreateChangefeed struct {
 	Targets TargetList
+	// Columns, if non-empty, restricts the columns which are emitted.
+	Columns NameList
+	// Where, if non-nil, restricts the rows which are emitted.
+	Where   *Where
 	SinkURI Expr
 	Options KVOptions
 }
@@ -30,6 +34,15 @@ func (node *CreateChangefeed) Format(ctx *FmtCtx) {
 	}
 	ctx.WriteString("CHANGEFEED FOR ")
 	ctx.FormatNode(&node.Targets)
+	if len(node.Columns) > 0 {
+		ctx.WriteString(" (")
+		ctx.FormatNode(&node.Columns)
+		ctx.WriteByte(')')
+	}
+	if node.Where != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(node.Where)
+	}
 	if node.SinkURI != nil {
 		ctx.WriteString(" INTO ")
 		ctx.FormatNode(node.SinkURI)
@@ -20,10 +20,24 @@ type CopyFrom struct {
 	Options CopyOptions
 }
 
+// CopyTo represents a COPY TO statement.
+type CopyTo struct {
+	Table   TableName
+	Columns NameList
+	// Statement, if set, is the query whose results are copied. Table and
+	// Columns are unset in that case.
+	Statement Statement
+	Options   CopyOptions
+}
+
 // CopyOptions describes options for COPY execution.
 type CopyOptions struct {
 	Destination Expr
 	CopyFormat  CopyFormat
+	Header      bool
+	Delimiter   Expr
+	Null        Expr
+	Quote       Expr
 }
 
 var _ NodeFormatter = &CopyOptions{}
@@ -47,6 +61,28 @@ func (node *CopyFrom) Format(ctx *FmtCtx) {
 	}
 }
 
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY ")
+	if node.Statement != nil {
+		ctx.WriteString("(")
+		ctx.FormatNode(node.Statement)
+		ctx.WriteString(")")
+	} else {
+		ctx.FormatNode(&node.Table)
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(")")
+		}
+	}
+	ctx.WriteString(" TO STDOUT")
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
 // Format implements the NodeFormatter interface
 func (o *CopyOptions) Format(ctx *FmtCtx) {
 	var addSep bool
@@ -61,8 +97,30 @@ func (o *CopyOptions) Format(ctx *FmtCtx) {
 		case CopyFormatBinary:
 			ctx.WriteString("BINARY")
 			addSep = true
+		case CopyFormatCSV:
+			ctx.WriteString("CSV")
+			addSep = true
 		}
 	}
+	if o.Header {
+		maybeAddSep()
+		ctx.WriteString("HEADER")
+	}
+	if o.Delimiter != nil {
+		maybeAddSep()
+		ctx.WriteString("DELIMITER ")
+		ctx.FormatNode(o.Delimiter)
+	}
+	if o.Null != nil {
+		maybeAddSep()
+		ctx.WriteString("NULL ")
+		ctx.FormatNode(o.Null)
+	}
+	if o.Quote != nil {
+		maybeAddSep()
+		ctx.WriteString("QUOTE ")
+		ctx.FormatNode(o.Quote)
+	}
 	if o.Destination != nil {
 		maybeAddSep()
 		// Lowercase because that's what has historically been produced
@@ -93,6 +151,30 @@ func (o *CopyOptions) CombineWith(other *CopyOptions) error {
 		}
 		o.CopyFormat = other.CopyFormat
 	}
+	if other.Header {
+		if o.Header {
+			return errors.New("header option specified multiple times")
+		}
+		o.Header = true
+	}
+	if other.Delimiter != nil {
+		if o.Delimiter != nil {
+			return errors.New("delimiter option specified multiple times")
+		}
+		o.Delimiter = other.Delimiter
+	}
+	if other.Null != nil {
+		if o.Null != nil {
+			return errors.New("null option specified multiple times")
+		}
+		o.Null = other.Null
+	}
+	if other.Quote != nil {
+		if o.Quote != nil {
+			return errors.New("quote option specified multiple times")
+		}
+		o.Quote = other.Quote
+	}
 	return nil
 }
 
@@ -103,4 +185,5 @@ type CopyFormat int
 const (
 	CopyFormatText CopyFormat = iota
 	CopyFormatBinary
+	CopyFormatCSV
 )
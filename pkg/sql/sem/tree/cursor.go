@@ -0,0 +1,176 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "strconv"
+
+// CursorScrollOption represents the scroll option, if one was given, for a
+// DECLARE statement.
+type CursorScrollOption int8
+
+const (
+	// UnspecifiedScroll represents no SCROLL option having been given. In
+	// CockroachDB, such cursors can be scrolled backwards.
+	UnspecifiedScroll CursorScrollOption = iota
+	// Scroll represents the SCROLL option.
+	Scroll
+	// NoScroll represents the NO SCROLL option.
+	NoScroll
+)
+
+func (o CursorScrollOption) String() string {
+	switch o {
+	case Scroll:
+		return "SCROLL"
+	case NoScroll:
+		return "NO SCROLL"
+	}
+	return ""
+}
+
+// DeclareCursor represents a DECLARE statement.
+type DeclareCursor struct {
+	Name   Name
+	Select *Select
+	Binary bool
+	Scroll CursorScrollOption
+	Hold   bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DeclareCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("DECLARE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ")
+	if node.Binary {
+		ctx.WriteString("BINARY ")
+	}
+	if node.Scroll != UnspecifiedScroll {
+		ctx.WriteString(node.Scroll.String())
+		ctx.WriteString(" ")
+	}
+	ctx.WriteString("CURSOR ")
+	if node.Hold {
+		ctx.WriteString("WITH HOLD ")
+	}
+	ctx.WriteString("FOR ")
+	ctx.FormatNode(node.Select)
+}
+
+// FetchType represents the direction of a FETCH or MOVE statement.
+type FetchType int8
+
+const (
+	// FetchNormal moves Count rows forward, or backward if Count is negative.
+	// It represents FETCH n, NEXT, PRIOR, FORWARD n and BACKWARD n.
+	FetchNormal FetchType = iota
+	// FetchRelative moves Count rows from the current position and fetches the
+	// row found there.
+	FetchRelative
+	// FetchAbsolute moves to the Count-th row, counting from the end if Count is
+	// negative, and fetches it.
+	FetchAbsolute
+	// FetchFirst is equivalent to FetchAbsolute with a Count of 1.
+	FetchFirst
+	// FetchLast is equivalent to FetchAbsolute with a Count of -1.
+	FetchLast
+	// FetchAll fetches all the remaining rows.
+	FetchAll
+	// FetchBackwardAll fetches all the prior rows, in reverse order.
+	FetchBackwardAll
+)
+
+// HasCount returns true if the fetch type is parameterized by a count.
+func (t FetchType) HasCount() bool {
+	switch t {
+	case FetchNormal, FetchRelative, FetchAbsolute:
+		return true
+	}
+	return false
+}
+
+func (t FetchType) String() string {
+	switch t {
+	case FetchNormal:
+		return ""
+	case FetchRelative:
+		return "RELATIVE"
+	case FetchAbsolute:
+		return "ABSOLUTE"
+	case FetchFirst:
+		return "FIRST"
+	case FetchLast:
+		return "LAST"
+	case FetchAll:
+		return "ALL"
+	case FetchBackwardAll:
+		return "BACKWARD ALL"
+	}
+	return "<unknown fetch type " + strconv.Itoa(int(t)) + ">"
+}
+
+// CursorStmt represents the shared structure of the FETCH and MOVE statements.
+type CursorStmt struct {
+	Name      Name
+	FetchType FetchType
+	Count     int64
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CursorStmt) Format(ctx *FmtCtx) {
+	if s := node.FetchType.String(); s != "" {
+		ctx.WriteString(s)
+		ctx.WriteString(" ")
+	}
+	if node.FetchType.HasCount() {
+		ctx.WriteString(strconv.FormatInt(node.Count, 10))
+		ctx.WriteString(" ")
+	}
+	ctx.FormatNode(&node.Name)
+}
+
+// FetchCursor represents a FETCH statement.
+type FetchCursor struct {
+	CursorStmt
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FetchCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("FETCH ")
+	ctx.FormatNode(&node.CursorStmt)
+}
+
+// MoveCursor represents a MOVE statement.
+type MoveCursor struct {
+	CursorStmt
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MoveCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("MOVE ")
+	ctx.FormatNode(&node.CursorStmt)
+}
+
+// CloseCursor represents a CLOSE statement.
+type CloseCursor struct {
+	Name Name
+	All  bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CloseCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("CLOSE ")
+	if node.All {
+		ctx.WriteString("ALL")
+	} else {
+		ctx.FormatNode(&node.Name)
+	}
+}
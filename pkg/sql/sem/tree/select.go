@@ -569,6 +569,45 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType is the type of a GroupingSet.
+type GroupingSetType int
+
+const (
+	// GroupingSetsType is used for GROUPING SETS (...).
+	GroupingSetsType GroupingSetType = iota
+	// RollupType is used for ROLLUP (...).
+	RollupType
+	// CubeType is used for CUBE (...).
+	CubeType
+)
+
+var groupingSetTypeName = [...]string{
+	GroupingSetsType: "GROUPING SETS",
+	RollupType:       "ROLLUP",
+	CubeType:         "CUBE",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a GROUPING SETS, ROLLUP or CUBE element of a GROUP BY
+// clause. Parenthesized lists of expressions (for example the (a, b) in
+// ROLLUP ((a, b), c)) are represented as tuples. The elements of GROUPING SETS
+// can themselves be GroupingSets.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
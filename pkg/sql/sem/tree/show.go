@@ -612,11 +612,16 @@ const (
 	// ScheduledBackupExecutor is an executor responsible for
 	// the execution of the scheduled backups.
 	ScheduledBackupExecutor
+
+	// ScheduledRowLevelTTLExecutor is an executor responsible for the
+	// execution of the row-level TTL jobs of tables.
+	ScheduledRowLevelTTLExecutor
 )
 
 var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
-	InvalidExecutor:         "unknown-executor",
-	ScheduledBackupExecutor: "scheduled-backup-executor",
+	InvalidExecutor:              "unknown-executor",
+	ScheduledBackupExecutor:      "scheduled-backup-executor",
+	ScheduledRowLevelTTLExecutor: "scheduled-row-level-ttl-executor",
 }
 
 // InternalName returns an internal executor name.
@@ -630,6 +635,8 @@ func (t ScheduledJobExecutorType) UserName() string {
 	switch t {
 	case ScheduledBackupExecutor:
 		return "BACKUP"
+	case ScheduledRowLevelTTLExecutor:
+		return "ROW LEVEL TTL"
 	}
 	return "unsupported-executor"
 }
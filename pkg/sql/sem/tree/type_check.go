@@ -58,6 +58,12 @@ type SemaContext struct {
 	// globally for the entire txn and this field would not be needed.
 	AsOfTimestamp *hlc.Timestamp
 
+	// AsOfBoundedStaleness is set if the query is a bounded staleness read.
+	// It holds the evaluated AS OF SYSTEM TIME clause of the query, whose
+	// Timestamp is the minimum timestamp bound. AsOfTimestamp is then the
+	// timestamp negotiated for the read.
+	AsOfBoundedStaleness *AsOfSystemTime
+
 	Properties SemaProperties
 }
 
@@ -1361,6 +1367,13 @@ func (expr DefaultVal) TypeCheck(
 	return nil, errInvalidDefaultUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, pgerror.Newf(pgcode.Syntax, "%s can only appear in a GROUP BY clause", expr.Type)
+}
+
 // TypeCheck implements the Expr interface.
 func (expr PartitionMinVal) TypeCheck(
 	_ context.Context, _ *SemaContext, desired *types.T,
@@ -35,6 +35,11 @@ type SessionData struct {
 	// NOTE: we'd prefer to use tree.UserPriority here, but doing so would
 	// introduce a package dependency cycle.
 	DefaultTxnPriority int
+	// DefaultTxnIsolationLevel indicates the default isolation level of newly
+	// created transactions.
+	// NOTE: we'd prefer to use tree.IsolationLevel here, but doing so would
+	// introduce a package dependency cycle.
+	DefaultTxnIsolationLevel int
 	// DefaultReadOnly indicates the default read-only status of newly created
 	// transactions.
 	DefaultReadOnly bool
@@ -77,6 +82,10 @@ type SessionData struct {
 	// idle in a transaction before the session is canceled.
 	// If set to 0, there is no timeout.
 	IdleInTransactionSessionTimeout time.Duration
+	// TraceParent is a W3C trace context traceparent header value set by the
+	// client. If set, the spans of the statements executed by the session are
+	// part of the client's trace.
+	TraceParent string
 	// User is the name of the user logged into the session.
 	User string
 	// SafeUpdates causes errors when the client
@@ -141,6 +150,9 @@ type SessionData struct {
 	// ImplicitSelectForUpdate is true if FOR UPDATE locking may be used during
 	// the row-fetch phase of mutation statements.
 	ImplicitSelectForUpdate bool
+	// SharedLockingForFKChecks is true if FOR SHARE locking may be used when
+	// looking up the referenced rows during foreign key checks.
+	SharedLockingForFKChecks bool
 	// InsertFastPath is true if the fast path for insert (with VALUES input) may
 	// be used.
 	InsertFastPath bool
@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -35,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
 	"github.com/gogo/protobuf/proto"
@@ -66,6 +68,7 @@ var supportedZoneConfigOptions = map[tree.Name]struct {
 	"range_min_bytes": {types.Int, func(c *zonepb.ZoneConfig, d tree.Datum) { c.RangeMinBytes = proto.Int64(int64(tree.MustBeDInt(d))) }},
 	"range_max_bytes": {types.Int, func(c *zonepb.ZoneConfig, d tree.Datum) { c.RangeMaxBytes = proto.Int64(int64(tree.MustBeDInt(d))) }},
 	"num_replicas":    {types.Int, func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumReplicas = proto.Int32(int32(tree.MustBeDInt(d))) }},
+	"num_voters":      {types.Int, func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumVoters = proto.Int32(int32(tree.MustBeDInt(d))) }},
 	"gc.ttlseconds": {types.Int, func(c *zonepb.ZoneConfig, d tree.Datum) {
 		c.GC = &zonepb.GCPolicy{TTLSeconds: int32(tree.MustBeDInt(d))}
 	}},
@@ -78,6 +81,15 @@ var supportedZoneConfigOptions = map[tree.Name]struct {
 		c.Constraints = constraintsList.Constraints
 		c.InheritedConstraints = false
 	}},
+	"voter_constraints": {types.String, func(c *zonepb.ZoneConfig, d tree.Datum) {
+		voterConstraintsList := zonepb.ConstraintsList{
+			Constraints: c.VoterConstraints,
+			Inherited:   c.InheritedVoterConstraints(),
+		}
+		loadYAML(&voterConstraintsList, string(tree.MustBeDString(d)))
+		c.VoterConstraints = voterConstraintsList.Constraints
+		c.NullVoterConstraintsIsEmpty = len(c.VoterConstraints) == 0
+	}},
 	"lease_preferences": {types.String, func(c *zonepb.ZoneConfig, d tree.Datum) {
 		loadYAML(&c.LeasePreferences, string(tree.MustBeDString(d)))
 		c.InheritedLeasePreferences = false
@@ -266,13 +278,13 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 		// We'll add back the missing newline below.
 		yamlConfig = strings.TrimSpace(yamlConfig)
 	}
-	var optionStr strings.Builder
+	var optionsStr []string
 	var copyFromParentList []tree.Name
 	if n.options != nil {
 		// Set from var = value attributes.
 		//
 		// We iterate over zoneOptionKeys instead of iterating over
-		// n.options directly so that the optionStr string constructed for
+		// n.options directly so that the optionsStr list constructed for
 		// the event log remains deterministic.
 		for i := range zoneOptionKeys {
 			name := (*tree.Name)(&zoneOptionKeys[i])
@@ -288,10 +300,7 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 			inheritVal, expr := val.inheritValue, val.explicitValue
 			if inheritVal {
 				copyFromParentList = append(copyFromParentList, *name)
-				if optionStr.Len() > 0 {
-					optionStr.WriteString(", ")
-				}
-				fmt.Fprintf(&optionStr, "%s = COPY FROM PARENT", name)
+				optionsStr = append(optionsStr, fmt.Sprintf("%s = COPY FROM PARENT", name))
 				continue
 			}
 			datum, err := expr.Eval(params.EvalContext())
@@ -304,10 +313,7 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 			}
 			setter := supportedZoneConfigOptions[*name].setter
 			setters = append(setters, func(c *zonepb.ZoneConfig) { setter(c, datum) })
-			if optionStr.Len() > 0 {
-				optionStr.WriteString(", ")
-			}
-			fmt.Fprintf(&optionStr, "%s = %s", name, datum)
+			optionsStr = append(optionsStr, fmt.Sprintf("%s = %s", name, datum))
 
 		}
 	}
@@ -635,6 +641,15 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 				})
 			}
 
+			// Non-voting replicas can't be configured until all nodes know about
+			// them.
+			if (finalZone.NumVoters != nil || !finalZone.InheritedVoterConstraints()) &&
+				!params.ExecCfg().Settings.Version.IsActive(
+					params.ctx, clusterversion.VersionNonVotingReplicas) {
+				return pgerror.New(pgcode.FeatureNotSupported,
+					"num_voters and voter_constraints are not supported until version upgrade is finalized")
+			}
+
 			// Finally revalidate everything. Validate only the completeZone config.
 			if err := completeZone.Validate(); err != nil {
 				return pgerror.Newf(pgcode.CheckViolation,
@@ -669,31 +684,19 @@ func (n *setZoneConfigNode) startExec(params runParams) error {
 		}
 
 		// Record that the change has occurred for auditing.
-		var eventLogType EventLogType
-		info := struct {
-			Target  string
-			Config  string `json:",omitempty"`
-			Options string `json:",omitempty"`
-			User    string
-		}{
-			Target:  tree.AsStringWithFQNames(&zs, params.Ann()),
-			Config:  strings.TrimSpace(yamlConfig),
-			Options: optionStr.String(),
-			User:    params.SessionData().User,
-		}
+		var info eventpb.EventPayload
 		if deleteZone {
-			eventLogType = EventLogRemoveZoneConfig
+			info = &eventpb.RemoveZoneConfig{
+				Target: tree.AsStringWithFQNames(&zs, params.Ann()),
+			}
 		} else {
-			eventLogType = EventLogSetZoneConfig
+			info = &eventpb.SetZoneConfig{
+				Target:  tree.AsStringWithFQNames(&zs, params.Ann()),
+				Config:  strings.TrimSpace(yamlConfig),
+				Options: optionsStr,
+			}
 		}
-		return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
-			params.ctx,
-			params.p.txn,
-			eventLogType,
-			int32(targetID),
-			int32(params.extendedEvalCtx.NodeID.SQLInstanceID()),
-			info,
-		)
+		return params.p.logEvent(params.ctx, targetID, info)
 	}
 	for _, zs := range specifiers {
 		// Note(solon): Currently the zone configurations are applied serially for
@@ -723,7 +726,14 @@ type nodeGetter func(context.Context, *serverpb.NodesRequest) (*serverpb.NodesRe
 // will be rejected. Additionally, invalid constraints such as
 // [+region=us-east1, -region=us-east1] will also be rejected.
 func validateNoRepeatKeysInZone(zone *zonepb.ZoneConfig) error {
-	for _, constraints := range zone.Constraints {
+	if err := validateNoRepeatKeysInConjunction(zone.Constraints); err != nil {
+		return err
+	}
+	return validateNoRepeatKeysInConjunction(zone.VoterConstraints)
+}
+
+func validateNoRepeatKeysInConjunction(conjunctions []zonepb.ConstraintsConjunction) error {
+	for _, constraints := range conjunctions {
 		// Because we expect to have a small number of constraints, a nested
 		// loop is probably better than allocating a map.
 		for i, curr := range constraints.Constraints {
@@ -771,7 +781,7 @@ func validateNoRepeatKeysInZone(zone *zonepb.ZoneConfig) error {
 func validateZoneAttrsAndLocalities(
 	ctx context.Context, getNodes nodeGetter, zone *zonepb.ZoneConfig,
 ) error {
-	if len(zone.Constraints) == 0 && len(zone.LeasePreferences) == 0 {
+	if len(zone.Constraints) == 0 && len(zone.VoterConstraints) == 0 && len(zone.LeasePreferences) == 0 {
 		return nil
 	}
 
@@ -801,6 +811,11 @@ func validateZoneAttrsAndLocalities(
 			addToValidate(constraint)
 		}
 	}
+	for _, constraints := range zone.VoterConstraints {
+		for _, constraint := range constraints.Constraints {
+			addToValidate(constraint)
+		}
+	}
 	for _, leasePreferences := range zone.LeasePreferences {
 		for _, constraint := range leasePreferences.Constraints {
 			addToValidate(constraint)
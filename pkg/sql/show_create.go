@@ -13,10 +13,13 @@ package sql
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -197,6 +200,9 @@ func ShowCreateTable(
 	); err != nil {
 		return "", err
 	}
+	if ttl := desc.TableDesc().RowLevelTTL; ttl != nil {
+		showRowLevelTTLStorageParams(ttl, &f.Buffer)
+	}
 
 	if !displayOptions.IgnoreComments {
 		if err := showComments(tn, desc, selectComment(ctx, p, desc.GetID()), &f.Buffer); err != nil {
@@ -207,6 +213,37 @@ func ShowCreateTable(
 	return f.CloseAndGetString(), nil
 }
 
+// showRowLevelTTLStorageParams writes the WITH clause containing the storage
+// parameters which configure the row-level TTL of a table.
+func showRowLevelTTLStorageParams(ttl *descpb.TableDescriptor_RowLevelTTL, buf *bytes.Buffer) {
+	var params []string
+	if ttl.ExpireAfter != "" {
+		params = append(params, "ttl_expire_after = "+lex.EscapeSQLString(ttl.ExpireAfter))
+	}
+	if ttl.ExpirationExpr != "" {
+		params = append(params, "ttl_expiration_expression = "+lex.EscapeSQLString(ttl.ExpirationExpr))
+	}
+	if ttl.JobCron != "" {
+		params = append(params, "ttl_job_cron = "+lex.EscapeSQLString(ttl.JobCron))
+	}
+	for _, p := range []struct {
+		key string
+		val int64
+	}{
+		{key: "ttl_select_batch_size", val: ttl.SelectBatchSize},
+		{key: "ttl_delete_batch_size", val: ttl.DeleteBatchSize},
+		{key: "ttl_range_concurrency", val: ttl.RangeConcurrency},
+		{key: "ttl_delete_rate_limit", val: ttl.DeleteRateLimit},
+	} {
+		if p.val != 0 {
+			params = append(params, fmt.Sprintf("%s = %d", p.key, p.val))
+		}
+	}
+	buf.WriteString(" WITH (")
+	buf.WriteString(strings.Join(params, ", "))
+	buf.WriteString(")")
+}
+
 // formatQuoteNames quotes and adds commas between names.
 func formatQuoteNames(buf *bytes.Buffer, names ...string) {
 	f := tree.NewFmtCtx(tree.FmtSimple)
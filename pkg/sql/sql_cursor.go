@@ -0,0 +1,800 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// cursorMaxBufferSizeSettingName is the name of the cluster setting that
+// limits the temporary storage used by a cursor.
+const cursorMaxBufferSizeSettingName = "sql.cursors.max_buffer_size"
+
+// cursorMaxBufferSize is the maximum amount of temporary storage that the
+// buffered rows of a single cursor can use.
+var cursorMaxBufferSize = settings.RegisterByteSizeSetting(
+	cursorMaxBufferSizeSettingName,
+	"maximum amount of temporary disk space in bytes that the rows buffered by a single "+
+		"SQL cursor can use; only cursors that can scroll backward or that are held "+
+		"after their transaction commits buffer their rows",
+	1<<30, /* 1 GiB */
+)
+
+// sqlCursor is a cursor declared with DECLARE.
+//
+// The cursor's query is not run to completion when the cursor is declared.
+// Instead, it runs on its own goroutine (see cursorQuery) and is suspended
+// between rows; FETCH and MOVE resume it to read as many rows as they need.
+// The query uses the transaction of the session, but only makes progress
+// while a statement of the session waits for its next row, so the two never
+// use the transaction concurrently. Like in Postgres, the rows of the cursor
+// don't reflect the writes made by the transaction after DECLARE: the query
+// reads at the transaction's read sequence number as of DECLARE, which is
+// restored whenever the query resumes.
+//
+// Cursors that can scroll backward buffer the rows they read, so that they
+// can be read again. The same goes for holdable cursors, whose remaining rows
+// are read into the buffer when their transaction commits since the query
+// cannot outlive the transaction. The buffer is a row container that spills
+// to temporary storage once it exceeds the workmem limit. Its memory is
+// limited by sql.distsql.temp_storage.workmem (and accounted against the
+// session's memory monitor) and its temporary storage by
+// sql.cursors.max_buffer_size. Other cursors only keep their current row.
+type sqlCursor struct {
+	name string
+	// sql is the text of the DECLARE statement.
+	sql       string
+	createdAt time.Time
+	hold      bool
+	scroll    tree.CursorScrollOption
+	// createdInTxn is set if the cursor was declared in the current transaction.
+	// Such cursors are closed if the transaction does not commit, even if they
+	// are holdable.
+	createdInTxn bool
+
+	cols colinfo.ResultColumns
+	// txn is the transaction the cursor's query runs in, and readSeqNum the
+	// read sequence number of that transaction as of DECLARE.
+	txn        *kv.Txn
+	readSeqNum enginepb.TxnSeq
+	// query is the cursor's query. It is nil once all its rows have been read.
+	query *cursorQuery
+	// queryErr is the error the query failed with, if any.
+	queryErr error
+	// numRows is the number of rows read from the query so far.
+	numRows int
+
+	// rows buffers the rows read from the query, if the cursor buffers them.
+	rows    *rowcontainer.DiskBackedIndexedRowContainer
+	scratch rowenc.EncDatumRow
+	// current is the last row read from the query, if the cursor doesn't
+	// buffer its rows.
+	current tree.Datums
+
+	memMon  *mon.BytesMonitor
+	diskMon *mon.BytesMonitor
+	// maxBufferSize is the limit of diskMon.
+	maxBufferSize int64
+
+	// pos is the current position of the cursor. 0 is before the first row, i
+	// is on the i-th row and numRows+1 is after the last row, once all the rows
+	// have been read.
+	pos int
+}
+
+// newSQLCursor creates a cursor for the given DECLARE statement. Its query
+// is started with startSQLCursor.
+func (ex *connExecutor) newSQLCursor(
+	ctx context.Context, s *tree.DeclareCursor, sql string, implicitTxn bool,
+) (*sqlCursor, error) {
+	name := string(s.Name)
+	if _, ok := ex.extraTxnState.sqlCursors[name]; ok {
+		return nil, pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", name)
+	}
+	if s.Binary {
+		return nil, unimplemented.NewWithIssue(41412, "binary cursors are not supported")
+	}
+	if !s.Hold && implicitTxn {
+		return nil, pgerror.New(pgcode.NoActiveSQLTransaction,
+			"DECLARE CURSOR can only be used in transaction blocks")
+	}
+	cfg := &ex.server.cfg.DistSQLSrv.ServerConfig
+	maxBufferSize := cursorMaxBufferSize.Get(&cfg.Settings.SV)
+	diskMon := mon.NewMonitorInheritWithLimit("cursor-disk", maxBufferSize, cfg.DiskMonitor)
+	diskMon.Start(ctx, cfg.DiskMonitor, mon.BoundAccount{})
+	return &sqlCursor{
+		name:          name,
+		sql:           sql,
+		createdAt:     timeutil.Now(),
+		hold:          s.Hold,
+		scroll:        s.Scroll,
+		createdInTxn:  true,
+		memMon:        execinfra.NewLimitedMonitor(ctx, ex.sessionMon, cfg, "cursor-mem"),
+		diskMon:       diskMon,
+		maxBufferSize: maxBufferSize,
+	}, nil
+}
+
+// startSQLCursor starts the query of the cursor declared by the given
+// statement in the session's transaction and waits for its result columns.
+// The query is then suspended until the cursor's rows are fetched.
+func (ex *connExecutor) startSQLCursor(
+	ctx context.Context, c *sqlCursor, s *tree.DeclareCursor, p *planner,
+) error {
+	qargs := make([]interface{}, len(p.semaCtx.Placeholders.Values))
+	for i, v := range p.semaCtx.Placeholders.Values {
+		d, err := v.Eval(p.EvalContext())
+		if err != nil {
+			return err
+		}
+		qargs[i] = d
+	}
+	// The query is planned locally: a distributed flow would keep reading
+	// ahead on other nodes while the query is suspended.
+	sd := *ex.sessionData
+	sd.DistSQLMode = sessiondata.DistSQLOff
+	ie := MakeInternalExecutor(ctx, ex.server, ex.memMetrics, ex.server.cfg.Settings)
+	ie.SetSessionData(&sd)
+	q, err := startCursorQuery(
+		ex.state.Ctx, ex.server.cfg.DistSQLSrv.Stopper, &ie, ex.state.mu.txn,
+		tree.AsStringWithFlags(s.Select, tree.FmtSerializable), qargs,
+	)
+	if err != nil {
+		return err
+	}
+	c.query = q
+	c.txn = ex.state.mu.txn
+	res, err := c.query.wait(ctx)
+	if err != nil {
+		return err
+	}
+	if res.done {
+		c.query = nil
+		if res.err != nil {
+			return res.err
+		}
+		return errors.AssertionFailedf("cursor query finished without result columns")
+	}
+	// The query has stepped the transaction when it started, so that it sees
+	// the writes made before DECLARE.
+	c.readSeqNum = c.txn.GetReadSeqNum()
+	c.cols = res.cols
+	if c.buffersRows() {
+		typs := make([]*types.T, len(c.cols))
+		for i := range c.cols {
+			typs[i] = c.cols[i].Typ
+		}
+		c.scratch = make(rowenc.EncDatumRow, len(c.cols))
+		c.rows = rowcontainer.NewDiskBackedIndexedRowContainer(
+			nil /* ordering */, typs, p.EvalContext(), ex.server.cfg.DistSQLSrv.TempStorage,
+			c.memMon, c.diskMon,
+		)
+	}
+	return nil
+}
+
+// buffersRows returns whether the cursor buffers the rows it reads.
+func (c *sqlCursor) buffersRows() bool {
+	return c.hold || c.scroll != tree.NoScroll
+}
+
+// close releases the resources held by the cursor and stops its query.
+func (c *sqlCursor) close(ctx context.Context) {
+	c.stopQuery()
+	if c.rows != nil {
+		c.rows.Close(ctx)
+		c.rows = nil
+	}
+	c.memMon.Stop(ctx)
+	c.diskMon.Stop(ctx)
+}
+
+// stopQuery stops the cursor's query, if it is still running. The cursor
+// then behaves as if the query returned no more rows.
+func (c *sqlCursor) stopQuery() {
+	if c.query != nil {
+		c.query.stop()
+		c.query = nil
+	}
+}
+
+// readUpTo reads rows from the query until the i-th row of the cursor's
+// result has been read. It returns false if the result has fewer rows.
+func (c *sqlCursor) readUpTo(ctx context.Context, i int) (bool, error) {
+	if c.numRows < i && c.query != nil {
+		// The statements run by the session since DECLARE have stepped the
+		// transaction, so the query must be brought back to its own snapshot
+		// while it reads. Reads only use that snapshot in stepping mode, which
+		// the statement reading the rows doesn't necessarily enable (e.g.
+		// COMMIT for holdable cursors).
+		prevSteppingMode := c.txn.ConfigureStepping(ctx, kv.SteppingEnabled)
+		prevSeqNum := c.txn.GetReadSeqNum()
+		if err := c.txn.SetReadSeqNum(c.readSeqNum); err != nil {
+			_ = c.txn.ConfigureStepping(ctx, prevSteppingMode)
+			return false, err
+		}
+		defer func() {
+			if err := c.txn.SetReadSeqNum(prevSeqNum); err != nil {
+				log.Warningf(ctx, "unable to restore the read sequence number: %v", err)
+			}
+			_ = c.txn.ConfigureStepping(ctx, prevSteppingMode)
+		}()
+	}
+	for c.numRows < i {
+		if c.queryErr != nil {
+			return false, c.queryErr
+		}
+		if c.query == nil {
+			return false, nil
+		}
+		res, err := c.query.next(ctx)
+		if err != nil {
+			c.query = nil
+			return false, err
+		}
+		if res.done {
+			c.query = nil
+			c.queryErr = res.err
+			continue
+		}
+		c.numRows++
+		if c.rows == nil {
+			c.current = res.row
+			continue
+		}
+		for j, d := range res.row {
+			c.scratch[j] = rowenc.DatumToEncDatum(c.cols[j].Typ, d)
+		}
+		if err := c.rows.AddRow(ctx, c.scratch); err != nil {
+			if pgerror.GetPGCode(err) == pgcode.DiskFull {
+				err = errors.WithHintf(err,
+					"The rows of a cursor are buffered in temporary storage, which is limited to %s "+
+						"per cursor by the %s cluster setting.",
+					humanizeutil.IBytes(c.maxBufferSize), cursorMaxBufferSizeSettingName,
+				)
+			}
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// readAll reads all the remaining rows of the query and returns the number of
+// rows of the cursor's result.
+func (c *sqlCursor) readAll(ctx context.Context) (int, error) {
+	if _, err := c.readUpTo(ctx, math.MaxInt64); err != nil {
+		return 0, err
+	}
+	return c.numRows, nil
+}
+
+// row returns the row of the cursor's result at the given position, which
+// must have been read already.
+func (c *sqlCursor) row(ctx context.Context, i int) (tree.Datums, error) {
+	if c.rows == nil {
+		if i != c.numRows {
+			return nil, errors.AssertionFailedf("row %d of cursor %q is not available", i, c.name)
+		}
+		return c.current, nil
+	}
+	row, err := c.rows.GetRow(ctx, i-1)
+	if err != nil {
+		return nil, err
+	}
+	return row.GetDatums(0, len(c.cols))
+}
+
+// moveTo moves the cursor to the given position. It returns whether the
+// cursor is on a row.
+func (c *sqlCursor) moveTo(ctx context.Context, target int64) (bool, error) {
+	if target < 0 {
+		target = 0
+	}
+	if target < int64(c.pos) || (c.rows == nil && target > 0 && target < int64(c.numRows)) {
+		if err := c.checkScroll(); err != nil {
+			return false, err
+		}
+	}
+	if target == 0 {
+		c.pos = 0
+		return false, nil
+	}
+	ok, err := c.readUpTo(ctx, int(target))
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		c.pos = c.numRows + 1
+		return false, nil
+	}
+	c.pos = int(target)
+	return true, nil
+}
+
+// step moves the cursor by one row in the given direction (1 or -1). It
+// returns whether the cursor is on a row.
+func (c *sqlCursor) step(ctx context.Context, dir int) (bool, error) {
+	if dir > 0 {
+		return c.moveTo(ctx, int64(c.pos)+1)
+	}
+	if c.pos <= 1 {
+		c.pos = 0
+		return false, nil
+	}
+	return c.moveTo(ctx, int64(c.pos)-1)
+}
+
+// checkScroll returns an error if the cursor cannot move backward.
+func (c *sqlCursor) checkScroll() error {
+	if c.scroll == tree.NoScroll {
+		return errors.WithHint(
+			pgerror.New(pgcode.ObjectNotInPrerequisiteState, "cursor can only scan forward"),
+			"Declare it with SCROLL option to enable backward scan.",
+		)
+	}
+	return nil
+}
+
+func clampInt64(v, lo, hi int64) int64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// cursorMovement moves a cursor as specified by a FETCH or MOVE statement,
+// one row at a time.
+type cursorMovement struct {
+	cursor *sqlCursor
+	stmt   *tree.CursorStmt
+
+	started bool
+	// dir is 1 or -1 if the statement moves the cursor by a number of rows, and
+	// 0 if it moves the cursor to a single position.
+	dir       int
+	remaining int64
+}
+
+// next moves the cursor to the next row that the statement reaches and
+// returns whether there is such a row.
+func (m *cursorMovement) next(ctx context.Context) (bool, error) {
+	c := m.cursor
+	if !m.started {
+		m.started = true
+		onRow, dir, count, err := m.start(ctx)
+		if err != nil || dir == 0 {
+			return onRow, err
+		}
+		m.dir, m.remaining = dir, count
+	}
+	if m.dir == 0 || m.remaining == 0 {
+		return false, nil
+	}
+	m.remaining--
+	ok, err := c.step(ctx, m.dir)
+	if !ok {
+		m.remaining = 0
+	}
+	return ok, err
+}
+
+// start interprets the statement. It either moves the cursor to a single
+// position, returning whether the cursor is on a row, or returns the
+// direction (1 or -1) and the number of rows by which the cursor moves.
+func (m *cursorMovement) start(ctx context.Context) (onRow bool, dir int, count int64, _ error) {
+	c, s := m.cursor, m.stmt
+	switch s.FetchType {
+	case tree.FetchNormal, tree.FetchAll, tree.FetchBackwardAll:
+		dir, count = 1, s.Count
+		switch s.FetchType {
+		case tree.FetchAll:
+			count = math.MaxInt64
+		case tree.FetchBackwardAll:
+			dir, count = -1, math.MaxInt64
+		default:
+			if count == 0 {
+				// FETCH 0 re-fetches the current row.
+				onRow, err := c.moveTo(ctx, int64(c.pos))
+				return onRow, 0, 0, err
+			}
+			if count < 0 {
+				dir, count = -1, -count
+				if count < 0 {
+					// The negation of math.MinInt64 overflowed.
+					count = math.MaxInt64
+				}
+			}
+		}
+		if dir < 0 {
+			if err := c.checkScroll(); err != nil {
+				return false, 0, 0, err
+			}
+		}
+		return false, dir, count, nil
+
+	case tree.FetchAbsolute, tree.FetchFirst, tree.FetchLast:
+		k := s.Count
+		switch s.FetchType {
+		case tree.FetchFirst:
+			k = 1
+		case tree.FetchLast:
+			k = -1
+		}
+		if k < 0 {
+			// Negative positions count from the end, so all the rows must be read.
+			n, err := c.readAll(ctx)
+			if err != nil {
+				return false, 0, 0, err
+			}
+			k = clampInt64(k, -int64(n+1), 0) + int64(n+1)
+		}
+		onRow, err := c.moveTo(ctx, k)
+		return onRow, 0, 0, err
+
+	case tree.FetchRelative:
+		pos := int64(c.pos)
+		onRow, err := c.moveTo(ctx, pos+clampInt64(s.Count, -pos, math.MaxInt64-pos))
+		return onRow, 0, 0, err
+	}
+	return false, 0, 0, errors.AssertionFailedf("unknown fetch type %d", s.FetchType)
+}
+
+// cursorQuery runs the query of a cursor on its own goroutine, through an
+// InternalExecutor bound to the session's transaction. The query is
+// suspended after it produces its result columns and each of its rows, and
+// only resumes when the cursor asks for the next row. Since the cursor waits
+// until the next row (or the end of the query) is produced, the query never
+// runs concurrently with the statements of the session.
+type cursorQuery struct {
+	// resume lets the suspended query produce its next result.
+	resume chan struct{}
+	// results receives the result columns of the query, then its rows and
+	// finally a result marking the end of the query.
+	results chan cursorQueryResult
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// cursorQueryResult is a result produced by a cursorQuery.
+type cursorQueryResult struct {
+	cols colinfo.ResultColumns
+	row  tree.Datums
+	// done is set once the query has finished, with err being its error if any.
+	done bool
+	err  error
+}
+
+// startCursorQuery starts running the given statement in an async task. The
+// query is canceled if the stopper quiesces.
+func startCursorQuery(
+	ctx context.Context,
+	stopper *stop.Stopper,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	stmt string,
+	qargs []interface{},
+) (*cursorQuery, error) {
+	ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+	q := &cursorQuery{
+		resume:  make(chan struct{}),
+		results: make(chan cursorQueryResult),
+		cancel:  cancel,
+	}
+	q.wg.Add(1)
+	if err := stopper.RunAsyncTask(ctx, "sql-cursor", func(ctx context.Context) {
+		defer q.wg.Done()
+		w := &cursorQueryWriter{ctx: ctx, q: q}
+		res, err := ie.execInternalWithRowsWriter(
+			ctx, "sql-cursor", txn, sessiondata.NoSessionDataOverride, w, stmt, qargs...,
+		)
+		// Prefer the error reported to the writer, which is not wrapped with
+		// the operation name.
+		if w.err != nil {
+			err = w.err
+		} else if err == nil {
+			err = res.err
+		}
+		select {
+		case q.results <- cursorQueryResult{done: true, err: err}:
+		case <-ctx.Done():
+		}
+	}); err != nil {
+		q.wg.Done()
+		cancel()
+		return nil, err
+	}
+	return q, nil
+}
+
+// wait waits for the next result of the query. If ctx is canceled, the query
+// is stopped.
+func (q *cursorQuery) wait(ctx context.Context) (cursorQueryResult, error) {
+	select {
+	case res := <-q.results:
+		return res, nil
+	case <-ctx.Done():
+		q.stop()
+		return cursorQueryResult{}, ctx.Err()
+	}
+}
+
+// next resumes the query and waits for its next result.
+func (q *cursorQuery) next(ctx context.Context) (cursorQueryResult, error) {
+	select {
+	case q.resume <- struct{}{}:
+	case <-ctx.Done():
+		q.stop()
+		return cursorQueryResult{}, ctx.Err()
+	}
+	return q.wait(ctx)
+}
+
+// stop cancels the query and waits for its goroutine to exit.
+func (q *cursorQuery) stop() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+// cursorQueryWriter is the internalRowsWriter of a cursorQuery. It hands the
+// results of the query over to the cursor and suspends the query until the
+// cursor asks for the next result.
+type cursorQueryWriter struct {
+	ctx context.Context
+	q   *cursorQuery
+	err error
+}
+
+var _ internalRowsWriter = &cursorQueryWriter{}
+
+// SetColumns is part of the internalRowsWriter interface.
+func (w *cursorQueryWriter) SetColumns(_ context.Context, cols colinfo.ResultColumns) {
+	// An error means that the query was canceled, which AddRow reports.
+	_ = w.yield(cursorQueryResult{cols: cols})
+}
+
+// AddRow is part of the internalRowsWriter interface.
+func (w *cursorQueryWriter) AddRow(_ context.Context, row tree.Datums) error {
+	rowCopy := make(tree.Datums, len(row))
+	copy(rowCopy, row)
+	return w.yield(cursorQueryResult{row: rowCopy})
+}
+
+// SetError is part of the internalRowsWriter interface.
+func (w *cursorQueryWriter) SetError(err error) {
+	w.err = err
+}
+
+// yield hands the given result over to the cursor and waits until the cursor
+// resumes the query.
+func (w *cursorQueryWriter) yield(res cursorQueryResult) error {
+	select {
+	case w.q.results <- res:
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+	select {
+	case <-w.q.resume:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// sqlCursors gives a planner access to a session's collection of cursors.
+type sqlCursors interface {
+	// List returns all the cursors, ordered by name.
+	List() []*sqlCursor
+	// Get returns the cursor with the given name, or an error if it doesn't
+	// exist.
+	Get(name string) (*sqlCursor, error)
+	// Close closes the cursor with the given name and removes it from the
+	// collection. An error is returned if the cursor doesn't exist.
+	Close(ctx context.Context, name string) error
+	// CloseAll closes all cursors.
+	CloseAll(ctx context.Context)
+}
+
+// connExCursorsAccessor is an implementation of sqlCursors that gives access
+// to a connExecutor's cursors.
+type connExCursorsAccessor struct {
+	ex *connExecutor
+}
+
+var _ sqlCursors = connExCursorsAccessor{}
+
+// List is part of the sqlCursors interface.
+func (cs connExCursorsAccessor) List() []*sqlCursor {
+	cursors := cs.ex.extraTxnState.sqlCursors
+	ret := make([]*sqlCursor, 0, len(cursors))
+	for _, c := range cursors {
+		ret = append(ret, c)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].name < ret[j].name })
+	return ret
+}
+
+// Get is part of the sqlCursors interface.
+func (cs connExCursorsAccessor) Get(name string) (*sqlCursor, error) {
+	c, ok := cs.ex.extraTxnState.sqlCursors[name]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", name)
+	}
+	return c, nil
+}
+
+// Close is part of the sqlCursors interface.
+func (cs connExCursorsAccessor) Close(ctx context.Context, name string) error {
+	c, err := cs.Get(name)
+	if err != nil {
+		return err
+	}
+	c.close(ctx)
+	delete(cs.ex.extraTxnState.sqlCursors, name)
+	return nil
+}
+
+// CloseAll is part of the sqlCursors interface.
+func (cs connExCursorsAccessor) CloseAll(ctx context.Context) {
+	cs.ex.closeCursors(ctx, func(*sqlCursor) bool { return true })
+}
+
+// closeCursors closes the cursors for which the given function returns true.
+func (ex *connExecutor) closeCursors(ctx context.Context, shouldClose func(*sqlCursor) bool) {
+	for name, c := range ex.extraTxnState.sqlCursors {
+		if shouldClose(c) {
+			c.close(ctx)
+			delete(ex.extraTxnState.sqlCursors, name)
+		}
+	}
+}
+
+// prepareCursorsForCommit is called before the session's transaction
+// commits. The queries of the cursors cannot outlive the transaction, so the
+// remaining rows of holdable cursors are read into their buffer and the
+// queries of the other cursors, which are closed with the transaction, are
+// stopped.
+func (ex *connExecutor) prepareCursorsForCommit(ctx context.Context) error {
+	for _, c := range ex.extraTxnState.sqlCursors {
+		if c.query == nil {
+			continue
+		}
+		if !c.hold {
+			c.stopQuery()
+			continue
+		}
+		if _, err := c.readAll(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchCursor implements the FETCH statement.
+// See https://www.postgresql.org/docs/current/sql-fetch.html for details.
+func (p *planner) FetchCursor(ctx context.Context, s *tree.FetchCursor) (planNode, error) {
+	c, err := p.sqlCursors.Get(string(s.Name))
+	if err != nil {
+		return nil, err
+	}
+	return &fetchNode{cursor: c, stmt: &s.CursorStmt, columns: c.cols}, nil
+}
+
+// MoveCursor implements the MOVE statement.
+// See https://www.postgresql.org/docs/current/sql-move.html for details.
+func (p *planner) MoveCursor(ctx context.Context, s *tree.MoveCursor) (planNode, error) {
+	c, err := p.sqlCursors.Get(string(s.Name))
+	if err != nil {
+		return nil, err
+	}
+	return &moveNode{cursor: c, stmt: &s.CursorStmt}, nil
+}
+
+// CloseCursor implements the CLOSE statement.
+// See https://www.postgresql.org/docs/current/sql-close.html for details.
+func (p *planner) CloseCursor(ctx context.Context, s *tree.CloseCursor) (planNode, error) {
+	if s.All {
+		p.sqlCursors.CloseAll(ctx)
+	} else if err := p.sqlCursors.Close(ctx, string(s.Name)); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// fetchNode returns the rows of a cursor for a FETCH statement.
+type fetchNode struct {
+	cursor  *sqlCursor
+	stmt    *tree.CursorStmt
+	columns colinfo.ResultColumns
+
+	movement cursorMovement
+	row      tree.Datums
+}
+
+func (n *fetchNode) startExec(params runParams) error {
+	n.movement = cursorMovement{cursor: n.cursor, stmt: n.stmt}
+	return nil
+}
+
+func (n *fetchNode) Next(params runParams) (bool, error) {
+	if err := params.p.cancelChecker.Check(); err != nil {
+		return false, err
+	}
+	ok, err := n.movement.next(params.ctx)
+	if !ok || err != nil {
+		return false, err
+	}
+	n.row, err = n.cursor.row(params.ctx, n.cursor.pos)
+	return err == nil, err
+}
+
+func (n *fetchNode) Values() tree.Datums     { return n.row }
+func (n *fetchNode) Close(_ context.Context) {}
+
+// moveNode moves a cursor for a MOVE statement.
+type moveNode struct {
+	cursor *sqlCursor
+	stmt   *tree.CursorStmt
+
+	rowCount int
+}
+
+func (n *moveNode) startExec(params runParams) error {
+	m := cursorMovement{cursor: n.cursor, stmt: n.stmt}
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return err
+		}
+		ok, err := m.next(params.ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		n.rowCount++
+	}
+}
+
+// FastPathResults implements the planNodeFastPath interface.
+func (n *moveNode) FastPathResults() (int, bool) {
+	return n.rowCount, true
+}
+
+func (n *moveNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *moveNode) Values() tree.Datums                 { return nil }
+func (n *moveNode) Close(_ context.Context)             {}
@@ -0,0 +1,300 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package ttljob implements the job which deletes the expired rows of tables
+// with row-level TTL, and the schedule executor which periodically creates it.
+package ttljob
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	jobEnabled = settings.RegisterPublicBoolSetting(
+		"sql.ttl.job.enabled",
+		"whether row-level TTL jobs are allowed to run",
+		true,
+	)
+	defaultSelectBatchSize = settings.RegisterPositiveIntSetting(
+		"sql.ttl.default_select_batch_size",
+		"default number of expired rows read by a single scan of a range, "+
+			"used if ttl_select_batch_size is not set on the table",
+		500,
+	)
+	defaultDeleteBatchSize = settings.RegisterPositiveIntSetting(
+		"sql.ttl.default_delete_batch_size",
+		"default number of expired rows deleted by a single transaction, "+
+			"used if ttl_delete_batch_size is not set on the table",
+		100,
+	)
+	defaultRangeConcurrency = settings.RegisterPositiveIntSetting(
+		"sql.ttl.default_range_concurrency",
+		"default number of ranges processed in parallel by a row-level TTL job, "+
+			"used if ttl_range_concurrency is not set on the table",
+		4,
+	)
+	defaultDeleteRateLimit = settings.RegisterNonNegativeIntSetting(
+		"sql.ttl.default_delete_rate_limit",
+		"default maximum number of rows deleted per second by a row-level TTL job, "+
+			"used if ttl_delete_rate_limit is not set on the table (0 means unlimited)",
+		0,
+	)
+)
+
+// aostDuration is how far in the past the scans for expired rows are
+// performed. Reading in the past avoids contending with foreground traffic;
+// rows which are updated in the meantime are rechecked by the deletes.
+const aostDuration = -30 * time.Second
+
+// progressUpdateInterval is the minimum interval between two updates of the
+// persisted progress of a job.
+const progressUpdateInterval = 15 * time.Second
+
+type rowLevelTTLResumer struct {
+	job *jobs.Job
+	st  *cluster.Settings
+}
+
+var _ jobs.Resumer = (*rowLevelTTLResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (t rowLevelTTLResumer) Resume(
+	ctx context.Context, phs interface{}, _ chan<- tree.Datums,
+) error {
+	p := phs.(sql.PlanHookState)
+	execCfg := p.ExecCfg()
+	if !jobEnabled.Get(&execCfg.Settings.SV) {
+		return errors.New(
+			"row-level TTL jobs are currently disabled by CLUSTER SETTING sql.ttl.job.enabled",
+		)
+	}
+	details := t.job.Details().(jobspb.RowLevelTTLDetails)
+
+	var desc *tabledesc.Immutable
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
+		desc, err = catalogkv.MustGetTableDescByID(ctx, txn, execCfg.Codec, details.TableID)
+		return err
+	}); err != nil {
+		return err
+	}
+	if desc.Dropped() {
+		log.Infof(ctx, "table %d was dropped, skipping row-level TTL", details.TableID)
+		return nil
+	}
+	ttl := desc.RowLevelTTL
+	if ttl == nil {
+		return errors.Newf("table %s (%d) does not have row-level TTL set", desc.Name, desc.ID)
+	}
+
+	pkTypes := make([]*types.T, len(desc.PrimaryIndex.ColumnIDs))
+	for i, id := range desc.PrimaryIndex.ColumnIDs {
+		col, err := desc.FindColumnByID(id)
+		if err != nil {
+			return err
+		}
+		pkTypes[i] = col.Type
+	}
+	cutoff, err := tree.MakeDTimestampTZ(details.Cutoff, time.Microsecond)
+	if err != nil {
+		return err
+	}
+	aost := aostDuration
+	knobs := execCfg.TTLTestingKnobs
+	if knobs != nil && knobs.AOSTDuration != 0 {
+		aost = knobs.AOSTDuration
+	}
+	qb := queryBuilder{
+		tableID:        desc.ID,
+		pkColumns:      desc.PrimaryIndex.ColumnNames,
+		pkDirs:         desc.PrimaryIndex.ColumnDirections,
+		expirationExpr: ttl.EffectiveExpirationExpr(),
+		aost:           execCfg.Clock.Now().Add(aost.Nanoseconds(), 0),
+		cutoff:         cutoff,
+	}
+
+	sv := &execCfg.Settings.SV
+	selectBatchSize := ttlOrDefault(ttl.SelectBatchSize, defaultSelectBatchSize.Get(sv))
+	deleteBatchSize := ttlOrDefault(ttl.DeleteBatchSize, defaultDeleteBatchSize.Get(sv))
+	rangeConcurrency := ttlOrDefault(ttl.RangeConcurrency, defaultRangeConcurrency.Get(sv))
+	var limiter *quotapool.RateLimiter
+	if rate := ttlOrDefault(ttl.DeleteRateLimit, defaultDeleteRateLimit.Get(sv)); rate > 0 {
+		limiter = quotapool.NewRateLimiter("ttl-delete", quotapool.Limit(rate), rate)
+	}
+
+	spans, err := rangeSpans(ctx, execCfg.DistSender, desc.PrimaryIndexSpan(execCfg.Codec))
+	if err != nil {
+		return err
+	}
+	metrics := execCfg.JobRegistry.MetricsStruct().RowLevelTTL.(*Metrics)
+	prog := progressTracker{job: t.job, rangesTotal: int64(len(spans))}
+
+	r := rangeProcessor{
+		ie:              execCfg.InternalExecutor,
+		db:              execCfg.DB,
+		codec:           execCfg.Codec,
+		desc:            desc,
+		pkTypes:         pkTypes,
+		qb:              &qb,
+		selectBatchSize: selectBatchSize,
+		deleteBatchSize: deleteBatchSize,
+		limiter:         limiter,
+		metrics:         metrics,
+		knobs:           knobs,
+	}
+	spanCh := make(chan roachpb.Span)
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(spanCh)
+		for _, sp := range spans {
+			select {
+			case spanCh <- sp:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	for i := int64(0); i < rangeConcurrency; i++ {
+		g.GoCtx(func(ctx context.Context) error {
+			for sp := range spanCh {
+				rowsDeleted, err := r.processRange(ctx, sp)
+				if err != nil {
+					return errors.Wrapf(err, "deleting expired rows in %s", sp)
+				}
+				if err := prog.rangeDone(ctx, rowsDeleted); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return prog.flush(ctx)
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (t rowLevelTTLResumer) OnFailOrCancel(context.Context, interface{}) error {
+	return nil
+}
+
+// ttlOrDefault returns the value of a storage parameter if it is set and the
+// value of the cluster setting which provides its default otherwise.
+func ttlOrDefault(val int64, def int64) int64 {
+	if val != 0 {
+		return val
+	}
+	return def
+}
+
+// rangeSpans returns the intersection of the given span with each of the
+// ranges it overlaps.
+func rangeSpans(
+	ctx context.Context, distSender *kvcoord.DistSender, span roachpb.Span,
+) ([]roachpb.Span, error) {
+	rSpan, err := keys.SpanAddr(span)
+	if err != nil {
+		return nil, err
+	}
+	var spans []roachpb.Span
+	ri := kvcoord.NewRangeIterator(distSender)
+	for ri.Seek(ctx, rSpan.Key, kvcoord.Ascending); ; ri.Next(ctx) {
+		if !ri.Valid() {
+			return nil, ri.Error()
+		}
+		s, err := rSpan.Intersect(ri.Desc())
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, s.AsRawSpanWithNoLocals())
+		if !ri.NeedAnother(rSpan) {
+			return spans, nil
+		}
+	}
+}
+
+// progressTracker accumulates the progress of the workers of a job and
+// periodically persists it.
+type progressTracker struct {
+	job         *jobs.Job
+	rangesTotal int64
+
+	mu struct {
+		syncutil.Mutex
+		rowsDeleted     int64
+		rangesProcessed int64
+		lastUpdate      time.Time
+	}
+}
+
+func (pt *progressTracker) rangeDone(ctx context.Context, rowsDeleted int64) error {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.mu.rowsDeleted += rowsDeleted
+	pt.mu.rangesProcessed++
+	if timeutil.Since(pt.mu.lastUpdate) < progressUpdateInterval {
+		return nil
+	}
+	return pt.updateLocked(ctx)
+}
+
+func (pt *progressTracker) flush(ctx context.Context) error {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	return pt.updateLocked(ctx)
+}
+
+func (pt *progressTracker) updateLocked(ctx context.Context) error {
+	pt.mu.lastUpdate = timeutil.Now()
+	return pt.job.FractionProgressed(ctx,
+		func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+			prog := details.(*jobspb.Progress_RowLevelTTL).RowLevelTTL
+			prog.RowsDeleted = pt.mu.rowsDeleted
+			prog.RangesProcessed = pt.mu.rangesProcessed
+			prog.RangesTotal = pt.rangesTotal
+			if pt.rangesTotal == 0 {
+				return 1
+			}
+			return float32(pt.mu.rangesProcessed) / float32(pt.rangesTotal)
+		},
+	)
+}
+
+func init() {
+	createResumerFn := func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+		return &rowLevelTTLResumer{
+			job: job,
+			st:  settings,
+		}
+	}
+	jobs.RegisterConstructor(jobspb.TypeRowLevelTTL, createResumerFn)
+}
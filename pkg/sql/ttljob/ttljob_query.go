@@ -0,0 +1,312 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// queryBuilder generates the statements used by a row-level TTL job to find
+// and delete the expired rows of a table.
+type queryBuilder struct {
+	tableID   descpb.ID
+	pkColumns []string
+	// pkDirs are the directions of the primary key columns in the primary
+	// index, which determine the order in which the index is scanned.
+	pkDirs         []descpb.IndexDescriptor_Direction
+	expirationExpr string
+	// aost is the timestamp at which expired rows are looked up.
+	aost hlc.Timestamp
+	// cutoff is the time up to which rows are considered expired.
+	cutoff *tree.DTimestampTZ
+}
+
+// selectQuery returns the statement and placeholder values which select the
+// primary keys of up to limit expired rows in the given bounds, in primary
+// index order. Either bound may be a prefix of the primary key, and is omitted
+// if empty. The start bound is exclusive if startExclusive is set, the end
+// bound is always exclusive. The bounds are positions in the primary index, so
+// they are compared in the direction of each column.
+func (qb *queryBuilder) selectQuery(
+	start, end tree.Datums, startExclusive bool, limit int64,
+) (string, []interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString("SELECT ")
+	qb.writePKColumns(&buf, len(qb.pkColumns))
+	fmt.Fprintf(&buf, " FROM [%d AS tbl] AS OF SYSTEM TIME %s WHERE (%s) <= $1",
+		qb.tableID, qb.aost.AsOfSystemTime(), qb.expirationExpr)
+	args := []interface{}{qb.cutoff}
+	if len(start) > 0 {
+		args = qb.writeBound(&buf, true /* after */, !startExclusive, start, args)
+	}
+	if len(end) > 0 {
+		args = qb.writeBound(&buf, false /* after */, false /* inclusive */, end, args)
+	}
+	buf.WriteString(" ORDER BY ")
+	for i := range qb.pkColumns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(qb.pkColumns[i]))
+		if qb.pkDirs[i] == descpb.IndexDescriptor_DESC {
+			buf.WriteString(" DESC")
+		}
+	}
+	fmt.Fprintf(&buf, " LIMIT %d", limit)
+	return buf.String(), args
+}
+
+// deleteQuery returns the statement and placeholder values which delete the
+// rows with the given primary keys. The expiration expression is evaluated
+// again so that rows which were updated since they were selected are kept.
+func (qb *queryBuilder) deleteQuery(rows []tree.Datums) (string, []interface{}) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "DELETE FROM [%d AS tbl] WHERE (%s) <= $1 AND (",
+		qb.tableID, qb.expirationExpr)
+	qb.writePKColumns(&buf, len(qb.pkColumns))
+	buf.WriteString(") IN (")
+	args := make([]interface{}, 0, 1+len(rows)*len(qb.pkColumns))
+	args = append(args, qb.cutoff)
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('(')
+		args = writePlaceholders(&buf, row, args)
+		buf.WriteByte(')')
+	}
+	buf.WriteByte(')')
+	return buf.String(), args
+}
+
+func (qb *queryBuilder) writePKColumns(buf *bytes.Buffer, n int) {
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.NameString(qb.pkColumns[i]))
+	}
+}
+
+// writeBound writes the condition restricting the primary keys to those after
+// (or before) the given bound in primary index order, including keys prefixed
+// by the bound if inclusive is set. If the bound's columns all have the same
+// direction, this is a single tuple comparison. Otherwise, it is the
+// disjunction of the comparisons of each column, for keys equal to the bound
+// in the preceding columns.
+func (qb *queryBuilder) writeBound(
+	buf *bytes.Buffer, after, inclusive bool, bound tree.Datums, args []interface{},
+) []interface{} {
+	op := func(i int) string {
+		greater := after == (qb.pkDirs[i] != descpb.IndexDescriptor_DESC)
+		switch {
+		case greater && inclusive && i == len(bound)-1:
+			return ">="
+		case greater:
+			return ">"
+		case inclusive && i == len(bound)-1:
+			return "<="
+		default:
+			return "<"
+		}
+	}
+
+	uniform := true
+	for i := 1; i < len(bound); i++ {
+		uniform = uniform && qb.pkDirs[i] == qb.pkDirs[0]
+	}
+	if uniform {
+		buf.WriteString(" AND (")
+		qb.writePKColumns(buf, len(bound))
+		fmt.Fprintf(buf, ") %s (", op(len(bound)-1))
+		args = writePlaceholders(buf, bound, args)
+		buf.WriteByte(')')
+		return args
+	}
+
+	first := len(args) + 1
+	for _, d := range bound {
+		args = append(args, d)
+	}
+	buf.WriteString(" AND (")
+	for i := range bound {
+		if i > 0 {
+			buf.WriteString(" OR ")
+		}
+		buf.WriteByte('(')
+		for j := 0; j < i; j++ {
+			fmt.Fprintf(buf, "%s = $%d AND ", tree.NameString(qb.pkColumns[j]), first+j)
+		}
+		fmt.Fprintf(buf, "%s %s $%d)", tree.NameString(qb.pkColumns[i]), op(i), first+i)
+	}
+	buf.WriteByte(')')
+	return args
+}
+
+func writePlaceholders(buf *bytes.Buffer, datums tree.Datums, args []interface{}) []interface{} {
+	for i, d := range datums {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		args = append(args, d)
+		fmt.Fprintf(buf, "$%d", len(args))
+	}
+	return args
+}
+
+// rangeProcessor deletes the expired rows of a table one range at a time.
+type rangeProcessor struct {
+	ie      *sql.InternalExecutor
+	db      *kv.DB
+	codec   keys.SQLCodec
+	desc    *tabledesc.Immutable
+	pkTypes []*types.T
+	qb      *queryBuilder
+
+	selectBatchSize int64
+	deleteBatchSize int64
+	// limiter bounds the rate at which rows are deleted; nil if unlimited.
+	limiter *quotapool.RateLimiter
+	metrics *Metrics
+	knobs   *sql.TTLTestingKnobs
+}
+
+// processRange deletes the expired rows in the given span of the primary
+// index, returning the number of rows deleted. Expired rows are found by
+// paginated scans at the AOST timestamp of the job, and deleted in batches by
+// low priority transactions.
+func (r *rangeProcessor) processRange(ctx context.Context, sp roachpb.Span) (int64, error) {
+	start, err := r.decodeBound(sp.Key)
+	if err != nil {
+		return 0, err
+	}
+	end, err := r.decodeBound(sp.EndKey)
+	if err != nil {
+		return 0, err
+	}
+	override := sessiondata.InternalExecutorOverride{User: security.RootUser}
+	var rowsDeleted int64
+	startExclusive := false
+	for {
+		query, args := r.qb.selectQuery(start, end, startExclusive, r.selectBatchSize)
+		before := timeutil.Now()
+		rows, err := r.ie.QueryEx(ctx, "ttl-select", nil /* txn */, override, query, args...)
+		if err != nil {
+			return rowsDeleted, err
+		}
+		r.metrics.SelectDuration.RecordValue(timeutil.Since(before).Nanoseconds())
+		r.metrics.RowsSelected.Inc(int64(len(rows)))
+
+		for i := 0; i < len(rows); i += int(r.deleteBatchSize) {
+			batch := rows[i:]
+			if len(batch) > int(r.deleteBatchSize) {
+				batch = batch[:r.deleteBatchSize]
+			}
+			n, err := r.deleteBatch(ctx, batch)
+			if err != nil {
+				return rowsDeleted, err
+			}
+			rowsDeleted += n
+		}
+
+		if int64(len(rows)) < r.selectBatchSize {
+			return rowsDeleted, nil
+		}
+		start, startExclusive = rows[len(rows)-1], true
+	}
+}
+
+func (r *rangeProcessor) deleteBatch(ctx context.Context, rows []tree.Datums) (int64, error) {
+	if r.limiter != nil {
+		if err := r.limiter.WaitN(ctx, int64(len(rows))); err != nil {
+			return 0, err
+		}
+	}
+	if r.knobs != nil && r.knobs.BeforeDeleteBatch != nil {
+		r.knobs.BeforeDeleteBatch(rows)
+	}
+	query, args := r.qb.deleteQuery(rows)
+	before := timeutil.Now()
+	var n int
+	if err := r.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) (err error) {
+		// The deletions run alongside foreground traffic and should lose any
+		// conflict with it.
+		if err := txn.SetUserPriority(roachpb.MinUserPriority); err != nil {
+			return err
+		}
+		n, err = r.ie.ExecEx(
+			ctx,
+			"ttl-delete",
+			txn,
+			sessiondata.InternalExecutorOverride{User: security.RootUser},
+			query,
+			args...,
+		)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	r.metrics.DeleteDuration.RecordValue(timeutil.Since(before).Nanoseconds())
+	r.metrics.RowsDeleted.Inc(int64(n))
+	return int64(n), nil
+}
+
+// decodeBound decodes the primary key datums which prefix the given key. It
+// returns no datums if the key does not belong to the primary index of the
+// table, which is the case for the bounds of the primary index span.
+func (r *rangeProcessor) decodeBound(key roachpb.Key) (tree.Datums, error) {
+	key, err := r.codec.StripTenantPrefix(key)
+	if err != nil {
+		return nil, err
+	}
+	key, tableID, indexID, err := rowenc.DecodePartialTableIDIndexID(key)
+	if err != nil || tableID != r.desc.ID || indexID != r.desc.PrimaryIndex.ID {
+		return nil, nil //nolint:returnerrcheck
+	}
+	var alloc rowenc.DatumAlloc
+	var datums tree.Datums
+	for i, typ := range r.pkTypes {
+		if len(key) == 0 {
+			break
+		}
+		dir, err := r.desc.PrimaryIndex.ColumnDirections[i].ToEncodingDirection()
+		if err != nil {
+			return nil, err
+		}
+		var ed rowenc.EncDatum
+		ed, key, err = rowenc.EncDatumFromBuffer(typ, rowenc.EncodingDirToDatumEncoding(dir), key)
+		if err != nil {
+			return nil, err
+		}
+		if err := ed.EnsureDecoded(typ, &alloc); err != nil {
+			return nil, err
+		}
+		datums = append(datums, ed.Datum)
+	}
+	return datums, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cutoff, err := tree.MakeDTimestampTZ(time.Unix(1600000000, 0), time.Microsecond)
+	require.NoError(t, err)
+	qb := queryBuilder{
+		tableID:        53,
+		pkColumns:      []string{"a", "select"},
+		pkDirs:         []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_ASC},
+		expirationExpr: "crdb_internal_expiration",
+		aost:           hlc.Timestamp{WallTime: 1599999970000000000},
+		cutoff:         cutoff,
+	}
+	one, two := tree.NewDInt(1), tree.NewDInt(2)
+
+	testCases := []struct {
+		name           string
+		start, end     tree.Datums
+		startExclusive bool
+		expected       string
+		numArgs        int
+	}{
+		{
+			name: "unbounded",
+			expected: `SELECT a, "select" FROM [53 AS tbl] AS OF SYSTEM TIME 1599999970000000000.0000000000 ` +
+				`WHERE (crdb_internal_expiration) <= $1 ORDER BY a, "select" LIMIT 10`,
+			numArgs: 1,
+		},
+		{
+			name:  "prefix bounds",
+			start: tree.Datums{one},
+			end:   tree.Datums{two},
+			expected: `SELECT a, "select" FROM [53 AS tbl] AS OF SYSTEM TIME 1599999970000000000.0000000000 ` +
+				`WHERE (crdb_internal_expiration) <= $1 AND (a) >= ($2) AND (a) < ($3) ORDER BY a, "select" LIMIT 10`,
+			numArgs: 3,
+		},
+		{
+			name:           "exclusive start",
+			start:          tree.Datums{one, two},
+			startExclusive: true,
+			expected: `SELECT a, "select" FROM [53 AS tbl] AS OF SYSTEM TIME 1599999970000000000.0000000000 ` +
+				`WHERE (crdb_internal_expiration) <= $1 AND (a, "select") > ($2, $3) ORDER BY a, "select" LIMIT 10`,
+			numArgs: 3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, args := qb.selectQuery(tc.start, tc.end, tc.startExclusive, 10)
+			require.Equal(t, tc.expected, query)
+			require.Len(t, args, tc.numArgs)
+		})
+	}
+
+	t.Run("descending", func(t *testing.T) {
+		qb := qb
+		qb.pkDirs = []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_DESC, descpb.IndexDescriptor_DESC}
+		query, args := qb.selectQuery(tree.Datums{two, one}, tree.Datums{one}, true /* startExclusive */, 10)
+		require.Equal(t,
+			`SELECT a, "select" FROM [53 AS tbl] AS OF SYSTEM TIME 1599999970000000000.0000000000 `+
+				`WHERE (crdb_internal_expiration) <= $1 AND (a, "select") < ($2, $3) AND (a) > ($4) `+
+				`ORDER BY a DESC, "select" DESC LIMIT 10`,
+			query,
+		)
+		require.Equal(t, []interface{}{cutoff, two, one, one}, args)
+	})
+
+	t.Run("mixed directions", func(t *testing.T) {
+		qb := qb
+		qb.pkDirs = []descpb.IndexDescriptor_Direction{descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_DESC}
+		query, args := qb.selectQuery(tree.Datums{one, two}, tree.Datums{two, one}, false /* startExclusive */, 10)
+		require.Equal(t,
+			`SELECT a, "select" FROM [53 AS tbl] AS OF SYSTEM TIME 1599999970000000000.0000000000 `+
+				`WHERE (crdb_internal_expiration) <= $1 `+
+				`AND ((a > $2) OR (a = $2 AND "select" <= $3)) `+
+				`AND ((a < $4) OR (a = $4 AND "select" > $5)) `+
+				`ORDER BY a, "select" DESC LIMIT 10`,
+			query,
+		)
+		require.Equal(t, []interface{}{cutoff, one, two, two, one}, args)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		query, args := qb.deleteQuery([]tree.Datums{{one, two}, {two, one}})
+		require.Equal(t,
+			`DELETE FROM [53 AS tbl] WHERE (crdb_internal_expiration) <= $1 `+
+				`AND (a, "select") IN (($2, $3), ($4, $5))`,
+			query,
+		)
+		require.Equal(t, []interface{}{cutoff, one, two, two, one}, args)
+	})
+}
+
+func TestDecodeBound(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	desc := tabledesc.NewImmutable(descpb.TableDescriptor{
+		ID: 53,
+		PrimaryIndex: descpb.IndexDescriptor{
+			ID: 1,
+			ColumnDirections: []descpb.IndexDescriptor_Direction{
+				descpb.IndexDescriptor_ASC, descpb.IndexDescriptor_DESC,
+			},
+		},
+	})
+	r := rangeProcessor{
+		codec:   keys.SystemSQLCodec,
+		desc:    desc,
+		pkTypes: []*types.T{types.Int, types.Int},
+	}
+	prefix := keys.SystemSQLCodec.IndexPrefix(53, 1)
+
+	// The primary key columns are decoded in their direction.
+	key := encoding.EncodeVarintAscending(append(roachpb.Key(nil), prefix...), 1)
+	datums, err := r.decodeBound(key)
+	require.NoError(t, err)
+	require.Equal(t, tree.Datums{tree.NewDInt(1)}, datums)
+	key = encoding.EncodeVarintDescending(key, 2)
+	datums, err = r.decodeBound(key)
+	require.NoError(t, err)
+	require.Equal(t, tree.Datums{tree.NewDInt(1), tree.NewDInt(2)}, datums)
+
+	// Keys outside of the primary index have no bound.
+	datums, err = r.decodeBound(keys.SystemSQLCodec.IndexPrefix(53, 2))
+	require.NoError(t, err)
+	require.Empty(t, datums)
+}
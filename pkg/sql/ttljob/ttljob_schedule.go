@@ -0,0 +1,148 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+type rowLevelTTLExecutor struct {
+	metrics rowLevelTTLMetrics
+}
+
+type rowLevelTTLMetrics struct {
+	jobs.ExecutorMetrics
+}
+
+var _ metric.Struct = &rowLevelTTLMetrics{}
+
+// MetricStruct implements metric.Struct interface
+func (m *rowLevelTTLMetrics) MetricStruct() {}
+
+var _ jobs.ScheduledJobExecutor = &rowLevelTTLExecutor{}
+
+// ExecuteJob implements jobs.ScheduledJobExecutor interface.
+func (e *rowLevelTTLExecutor) ExecuteJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	if err := e.createRowLevelTTLJob(ctx, cfg, env, sj, txn); err != nil {
+		e.metrics.NumFailed.Inc(1)
+		return err
+	}
+	e.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+func (e *rowLevelTTLExecutor) createRowLevelTTLJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	args := &jobspb.ScheduledRowLevelTTLArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+
+	hook, cleanup := cfg.PlanHookMaker("invoke-row-level-ttl", txn, sj.Owner())
+	defer cleanup()
+	execCfg := hook.(sql.PlanHookState).ExecCfg()
+
+	desc, err := catalogkv.MustGetTableDescByID(ctx, txn, execCfg.Codec, args.TableID)
+	if err != nil {
+		return err
+	}
+	record := jobs.Record{
+		Description:   fmt.Sprintf("ttl for %s", tree.NameString(desc.Name)),
+		Username:      sj.Owner(),
+		DescriptorIDs: descpb.IDs{args.TableID},
+		Details: jobspb.RowLevelTTLDetails{
+			TableID: args.TableID,
+			Cutoff:  env.Now(),
+		},
+		Progress: jobspb.RowLevelTTLProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			Name: jobs.CreatedByScheduledJobs,
+			ID:   sj.ScheduleID(),
+		},
+	}
+	job, err := execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, record, txn)
+	if err != nil {
+		return err
+	}
+	log.Infof(ctx, "created row-level TTL job %d for table %d by schedule %d",
+		*job.ID(), args.TableID, sj.ScheduleID())
+	return nil
+}
+
+// NotifyJobTermination implements jobs.ScheduledJobExecutor interface.
+func (e *rowLevelTTLExecutor) NotifyJobTermination(
+	ctx context.Context,
+	jobID int64,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	if jobStatus == jobs.StatusSucceeded {
+		e.metrics.NumSucceeded.Inc(1)
+		return nil
+	}
+
+	e.metrics.NumFailed.Inc(1)
+	err := errors.Errorf(
+		"row-level TTL job %d scheduled by %d failed with status %s",
+		jobID, schedule.ScheduleID(), jobStatus)
+	log.Errorf(ctx, "row-level TTL error: %v", err)
+	jobs.DefaultHandleFailedRun(schedule, "row-level TTL job %d failed with err=%v", jobID, err)
+	return nil
+}
+
+// Metrics implements ScheduledJobExecutor interface
+func (e *rowLevelTTLExecutor) Metrics() metric.Struct {
+	return &e.metrics
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			return &rowLevelTTLExecutor{
+				metrics: rowLevelTTLMetrics{
+					// The user name of the executor contains spaces, which are not
+					// valid in metric names.
+					ExecutorMetrics: jobs.MakeExecutorMetrics("ROW_LEVEL_TTL"),
+				},
+			}, nil
+		})
+}
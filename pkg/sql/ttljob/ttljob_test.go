@@ -0,0 +1,250 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob_test
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobstest"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+type execSchedulesFn = func(ctx context.Context, maxSchedules int64, txn *kv.Txn) error
+
+// testHelper starts a server whose job scheduling daemon uses a
+// jobstest.JobSchedulerTestEnv, and whose schedules only run when
+// executeSchedules is called.
+type testHelper struct {
+	server           serverutils.TestServerInterface
+	env              *jobstest.JobSchedulerTestEnv
+	cfg              *scheduledjobs.JobExecutionConfig
+	db               *gosql.DB
+	sqlDB            *sqlutils.SQLRunner
+	executeSchedules func() error
+}
+
+func newTestHelper(t *testing.T, ttlKnobs *sql.TTLTestingKnobs) (*testHelper, func()) {
+	th := &testHelper{
+		env: jobstest.NewJobSchedulerTestEnv(jobstest.UseSystemTables, timeutil.Now()),
+	}
+	// The tables are created and filled right before their TTL job runs, so
+	// the job must not scan them in the past.
+	ttlKnobs.AOSTDuration = -time.Nanosecond
+
+	args := base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			JobsTestingKnobs: &jobs.TestingKnobs{
+				JobSchedulerEnv: th.env,
+				TakeOverJobsScheduling: func(fn execSchedulesFn) {
+					th.executeSchedules = func() error {
+						defer th.server.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+						return th.cfg.DB.Txn(context.Background(), func(ctx context.Context, txn *kv.Txn) error {
+							return fn(ctx, 0 /* maxSchedules */, txn)
+						})
+					}
+				},
+				CaptureJobExecutionConfig: func(config *scheduledjobs.JobExecutionConfig) {
+					th.cfg = config
+				},
+			},
+			TTL: ttlKnobs,
+		},
+	}
+	s, db, _ := serverutils.StartServer(t, args)
+	require.NotNil(t, th.cfg)
+	th.server = s
+	th.db = db
+	th.sqlDB = sqlutils.MakeSQLRunner(db)
+	return th, func() { s.Stopper().Stop(context.Background()) }
+}
+
+// scheduleID returns the ID of the row-level TTL schedule of the given table.
+func (h *testHelper) scheduleID(t *testing.T, table string) int64 {
+	var tableID, scheduleID int64
+	h.sqlDB.QueryRow(t,
+		`SELECT table_id FROM crdb_internal.tables WHERE name = $1`, table,
+	).Scan(&tableID)
+	h.sqlDB.QueryRow(t,
+		fmt.Sprintf(`SELECT schedule_id FROM %s WHERE schedule_name = $1`,
+			h.env.ScheduledJobsTableName()),
+		fmt.Sprintf("row-level-ttl-%d", tableID),
+	).Scan(&scheduleID)
+	return scheduleID
+}
+
+// runTTLJob makes the schedule of the given table due, runs it, and waits for
+// the TTL job it creates to succeed.
+func (h *testHelper) runTTLJob(t *testing.T, table string) {
+	scheduleID := h.scheduleID(t, table)
+	// The schedules run at most hourly, so two hours from now they are all due.
+	h.env.AdvanceTime(2 * time.Hour)
+	require.NoError(t, h.executeSchedules())
+
+	query := "SELECT id FROM " + h.env.SystemJobsTableName() +
+		" WHERE status=$1 AND created_by_type=$2 AND created_by_id=$3"
+	testutils.SucceedsSoon(t, func() error {
+		h.server.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+		var unused int64
+		return h.db.QueryRow(
+			query, jobs.StatusSucceeded, jobs.CreatedByScheduledJobs, scheduleID,
+		).Scan(&unused)
+	})
+}
+
+// createTable creates a table with row-level TTL whose rows with an even id
+// expired an hour ago, and whose rows with an odd id expire in ten days.
+func (h *testHelper) createTable(t *testing.T, pk string, params string) {
+	h.sqlDB.Exec(t, fmt.Sprintf(`CREATE TABLE t (
+  id INT,
+  expire_at TIMESTAMPTZ,
+  PRIMARY KEY (%s)
+) WITH (ttl_expiration_expression = 'expire_at'%s)`, pk, params))
+	h.sqlDB.Exec(t, `INSERT INTO t SELECT i, IF(i % 2 = 0, now() - '1 hour'::INTERVAL, now() + '10 days'::INTERVAL)
+FROM generate_series(1, 20) AS g(i)`)
+}
+
+func TestRowLevelTTLJob(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	odd := [][]string{
+		{"1"}, {"3"}, {"5"}, {"7"}, {"9"}, {"11"}, {"13"}, {"15"}, {"17"}, {"19"},
+	}
+
+	t.Run("expiration expression", func(t *testing.T) {
+		th, cleanup := newTestHelper(t, &sql.TTLTestingKnobs{})
+		defer cleanup()
+
+		th.createTable(t, "id", ", ttl_select_batch_size = 3, ttl_delete_batch_size = 2")
+		th.runTTLJob(t, "t")
+		th.sqlDB.CheckQueryResults(t, `SELECT id FROM t ORDER BY id`, odd)
+	})
+
+	t.Run("expire after", func(t *testing.T) {
+		th, cleanup := newTestHelper(t, &sql.TTLTestingKnobs{})
+		defer cleanup()
+
+		th.sqlDB.Exec(t, `CREATE TABLE t (id INT PRIMARY KEY) WITH (ttl_expire_after = '1 hour')`)
+		th.sqlDB.Exec(t, `INSERT INTO t VALUES (1), (2)`)
+		th.sqlDB.Exec(t, `INSERT INTO t (id, crdb_internal_expiration) VALUES (3, now() + '10 days')`)
+		th.runTTLJob(t, "t")
+		th.sqlDB.CheckQueryResults(t, `SELECT id FROM t`, [][]string{{"3"}})
+	})
+
+	// The bounds of each range are positions in the primary index, so they must
+	// be decoded and compared in the direction of each primary key column.
+	t.Run("mixed direction primary key over several ranges", func(t *testing.T) {
+		th, cleanup := newTestHelper(t, &sql.TTLTestingKnobs{})
+		defer cleanup()
+
+		th.createTable(t, "expire_at ASC, id DESC", ", ttl_select_batch_size = 2")
+		th.sqlDB.Exec(t, `ALTER TABLE t SPLIT AT VALUES (now()), (now() + '5 days'::INTERVAL, 7)`)
+		th.runTTLJob(t, "t")
+		th.sqlDB.CheckQueryResults(t, `SELECT id FROM t ORDER BY id`, odd)
+	})
+
+	// The expired rows are found at an AOST timestamp; a row which is updated
+	// in the meantime must be kept.
+	t.Run("rows updated after the scan are kept", func(t *testing.T) {
+		var th *testHelper
+		var mu struct {
+			syncutil.Mutex
+			updated int64
+			err     error
+		}
+		knobs := &sql.TTLTestingKnobs{
+			BeforeDeleteBatch: func(rows []tree.Datums) {
+				mu.Lock()
+				defer mu.Unlock()
+				if mu.updated != 0 {
+					return
+				}
+				mu.updated = int64(tree.MustBeDInt(rows[0][0]))
+				_, mu.err = th.db.Exec(
+					`UPDATE t SET expire_at = now() + '10 days' WHERE id = $1`, mu.updated,
+				)
+			},
+		}
+		th, cleanup := newTestHelper(t, knobs)
+		defer cleanup()
+
+		th.createTable(t, "id", ", ttl_delete_batch_size = 5")
+		th.runTTLJob(t, "t")
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.NoError(t, mu.err)
+		require.NotZero(t, mu.updated)
+		th.sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM t WHERE id % 2 = 0`, [][]string{{"1"}})
+		th.sqlDB.CheckQueryResults(t,
+			fmt.Sprintf(`SELECT count(*) FROM t WHERE id = %d`, mu.updated), [][]string{{"1"}})
+	})
+
+	t.Run("delete rate limit", func(t *testing.T) {
+		var mu struct {
+			syncutil.Mutex
+			deletes []time.Time
+		}
+		knobs := &sql.TTLTestingKnobs{
+			BeforeDeleteBatch: func(rows []tree.Datums) {
+				mu.Lock()
+				defer mu.Unlock()
+				mu.deletes = append(mu.deletes, timeutil.Now())
+			},
+		}
+		th, cleanup := newTestHelper(t, knobs)
+		defer cleanup()
+
+		th.sqlDB.Exec(t, `CREATE TABLE t (id INT PRIMARY KEY, expire_at TIMESTAMPTZ)
+WITH (ttl_expiration_expression = 'expire_at', ttl_delete_batch_size = 1, ttl_delete_rate_limit = 1)`)
+		th.sqlDB.Exec(t, `INSERT INTO t VALUES (1, now() - '1 hour'), (2, now() - '1 hour'), (3, now() - '1 hour')`)
+		th.runTTLJob(t, "t")
+		th.sqlDB.CheckQueryResults(t, `SELECT count(*) FROM t`, [][]string{{"0"}})
+
+		// The limiter lets one row through immediately, and one per second
+		// afterwards.
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, mu.deletes, 3)
+		require.GreaterOrEqual(t, int64(mu.deletes[2].Sub(mu.deletes[0])), int64(time.Second))
+	})
+
+	t.Run("drop table deletes the schedule", func(t *testing.T) {
+		th, cleanup := newTestHelper(t, &sql.TTLTestingKnobs{})
+		defer cleanup()
+
+		th.createTable(t, "id", "")
+		scheduleID := th.scheduleID(t, "t")
+		query := fmt.Sprintf(`SELECT count(*) FROM %s WHERE schedule_id = %d`,
+			th.env.ScheduledJobsTableName(), scheduleID)
+		th.sqlDB.CheckQueryResults(t, query, [][]string{{"1"}})
+		th.sqlDB.Exec(t, `DROP TABLE t`)
+		th.sqlDB.CheckQueryResults(t, query, [][]string{{"0"}})
+	})
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -87,6 +89,9 @@ type txnState struct {
 	// The transaction's priority.
 	priority roachpb.UserPriority
 
+	// The transaction's isolation level.
+	isoLevel enginepb.IsolationLevel
+
 	// The transaction's read only state.
 	readOnly bool
 
@@ -127,18 +132,30 @@ const (
 // It creates a new client.Txn and initializes it using the session defaults.
 //
 // connCtx: The context in which the new transaction is started (usually a
-// 	 connection's context). ts.Ctx will be set to a child context and should be
-// 	 used for everything that happens within this SQL transaction.
+//
+//	connection's context). ts.Ctx will be set to a child context and should be
+//	used for everything that happens within this SQL transaction.
+//
 // txnType: The type of the starting txn.
 // sqlTimestamp: The timestamp to report for current_timestamp(), now() etc.
 // historicalTimestamp: If non-nil indicates that the transaction is historical
-//   and should be fixed to this timestamp.
+//
+//	and should be fixed to this timestamp.
+//
 // priority: The transaction's priority. Pass roachpb.UnspecifiedUserPriority if the txn arg is
-//   not nil.
+//
+//	not nil.
+//
+// isoLevel: The transaction's isolation level. Ignored if the txn arg is not
+//
+//	nil.
+//
 // readOnly: The read-only character of the new txn.
 // txn: If not nil, this txn will be used instead of creating a new txn. If so,
-//   all the other arguments need to correspond to the attributes of this txn
-//   (unless otherwise specified).
+//
+//	all the other arguments need to correspond to the attributes of this txn
+//	(unless otherwise specified).
+//
 // tranCtx: A bag of extra execution context.
 func (ts *txnState) resetForNewSQLTxn(
 	connCtx context.Context,
@@ -146,6 +163,7 @@ func (ts *txnState) resetForNewSQLTxn(
 	sqlTimestamp time.Time,
 	historicalTimestamp *hlc.Timestamp,
 	priority roachpb.UserPriority,
+	isoLevel enginepb.IsolationLevel,
 	readOnly tree.ReadWriteMode,
 	txn *kv.Txn,
 	tranCtx transitionCtx,
@@ -206,16 +224,21 @@ func (ts *txnState) resetForNewSQLTxn(
 	ts.mu.Lock()
 	ts.mu.stmtCount = 0
 	if txn == nil {
-		ts.mu.txn = kv.NewTxnWithSteppingEnabled(ts.Ctx, tranCtx.db, tranCtx.nodeIDOrZero)
+		ts.mu.txn = kv.NewTxnWithAdmissionControl(
+			ts.Ctx, tranCtx.db, tranCtx.nodeIDOrZero, roachpb.AdmissionHeader_FROM_SQL, admission.NormalPri)
 		ts.mu.txn.SetDebugName(opName)
 		if err := ts.setPriorityLocked(priority); err != nil {
 			panic(err)
 		}
+		if err := ts.setIsolationLevelLocked(isoLevel); err != nil {
+			panic(err)
+		}
 	} else {
 		if priority != roachpb.UnspecifiedUserPriority {
 			panic(errors.AssertionFailedf("unexpected priority when using an existing txn: %s", priority))
 		}
 		ts.mu.txn = txn
+		ts.isoLevel = txn.IsoLevel()
 	}
 	ts.mu.txnStart = timeutil.Now()
 	ts.mu.Unlock()
@@ -295,6 +318,13 @@ func (ts *txnState) setHistoricalTimestamp(ctx context.Context, historicalTimest
 	ts.isHistorical = true
 }
 
+// setRoutingPolicy sets the policy used to route the transaction's requests.
+func (ts *txnState) setRoutingPolicy(policy roachpb.RoutingPolicy) {
+	ts.mu.Lock()
+	ts.mu.txn.SetRoutingPolicy(policy)
+	ts.mu.Unlock()
+}
+
 // getReadTimestamp returns the transaction's current read timestamp.
 func (ts *txnState) getReadTimestamp() hlc.Timestamp {
 	ts.mu.RLock()
@@ -316,6 +346,20 @@ func (ts *txnState) setPriorityLocked(userPriority roachpb.UserPriority) error {
 	return nil
 }
 
+func (ts *txnState) setIsolationLevel(level enginepb.IsolationLevel) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.setIsolationLevelLocked(level)
+}
+
+func (ts *txnState) setIsolationLevelLocked(level enginepb.IsolationLevel) error {
+	if err := ts.mu.txn.SetIsoLevel(level); err != nil {
+		return err
+	}
+	ts.isoLevel = level
+	return nil
+}
+
 func (ts *txnState) setReadOnlyMode(mode tree.ReadWriteMode) error {
 	switch mode {
 	case tree.UnspecifiedReadWriteMode:
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -271,8 +272,8 @@ func TestTransitions(t *testing.T) {
 				return s, ts, nil
 			},
 			ev: eventTxnStart{ImplicitTxn: fsm.True},
-			evPayload: makeEventTxnStartPayload(pri, tree.ReadWrite, timeutil.Now(),
-				nil /* historicalTimestamp */, tranCtx),
+			evPayload: makeEventTxnStartPayload(pri, enginepb.Serializable, tree.ReadWrite,
+				timeutil.Now(), nil /* historicalTimestamp */, tranCtx),
 			expState: stateOpen{ImplicitTxn: fsm.True},
 			expAdv: expAdvance{
 				// We expect to stayInPlace; upon starting a txn the statement is
@@ -296,8 +297,8 @@ func TestTransitions(t *testing.T) {
 				return s, ts, nil
 			},
 			ev: eventTxnStart{ImplicitTxn: fsm.False},
-			evPayload: makeEventTxnStartPayload(pri, tree.ReadWrite, timeutil.Now(),
-				nil /* historicalTimestamp */, tranCtx),
+			evPayload: makeEventTxnStartPayload(pri, enginepb.Serializable, tree.ReadWrite,
+				timeutil.Now(), nil /* historicalTimestamp */, tranCtx),
 			expState: stateOpen{ImplicitTxn: fsm.False},
 			expAdv: expAdvance{
 				expCode: advanceOne,
@@ -32,7 +32,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
@@ -316,15 +318,22 @@ var varGen = map[string]sessionVar{
 	`default_transaction_isolation`: {
 		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
 			switch strings.ToUpper(s) {
-			case `READ UNCOMMITTED`, `READ COMMITTED`, `SNAPSHOT`, `REPEATABLE READ`, `SERIALIZABLE`, `DEFAULT`:
-				// Do nothing. All transactions execute with serializable isolation.
+			case `READ UNCOMMITTED`, `READ COMMITTED`:
+				m.SetDefaultTransactionIsolationLevel(tree.ReadCommittedIsolation)
+			case `SNAPSHOT`, `REPEATABLE READ`, `SERIALIZABLE`:
+				m.SetDefaultTransactionIsolationLevel(tree.SerializableIsolation)
+			case `DEFAULT`:
+				m.SetDefaultTransactionIsolationLevel(tree.UnspecifiedIsolation)
 			default:
-				return newVarValueError(`default_transaction_isolation`, s, "serializable")
+				return newVarValueError(`default_transaction_isolation`, s, "serializable", "read committed")
 			}
 
 			return nil
 		},
 		Get: func(evalCtx *extendedEvalContext) string {
+			if tree.IsolationLevel(evalCtx.SessionData.DefaultTxnIsolationLevel) == tree.ReadCommittedIsolation {
+				return "read committed"
+			}
 			return "serializable"
 		},
 		GlobalDefault: func(sv *settings.Values) string { return "default" },
@@ -646,6 +655,25 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`enable_shared_locking_for_fk_checks`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`enable_shared_locking_for_fk_checks`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar("enable_shared_locking_for_fk_checks", s)
+			if err != nil {
+				return err
+			}
+			m.SetSharedLockingForFKChecks(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData.SharedLockingForFKChecks)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(sharedLockingForFKChecksClusterMode.Get(sv))
+		},
+	},
+
 	// CockroachDB extension.
 	`enable_insert_fast_path`: {
 		GetStringVal: makePostgresBoolGetStringValFn(`enable_insert_fast_path`),
@@ -963,14 +991,18 @@ var varGen = map[string]sessionVar{
 	// See https://github.com/postgres/postgres/blob/REL_10_STABLE/src/backend/utils/misc/guc.c#L3401-L3409
 	`transaction_isolation`: {
 		Get: func(evalCtx *extendedEvalContext) string {
+			if evalCtx.Txn.IsoLevel() == enginepb.ReadCommitted {
+				return "read committed"
+			}
 			return "serializable"
 		},
-		RuntimeSet: func(_ context.Context, evalCtx *extendedEvalContext, s string) error {
-			_, ok := tree.IsolationLevelMap[s]
+		RuntimeSet: func(ctx context.Context, evalCtx *extendedEvalContext, s string) error {
+			level, ok := tree.IsolationLevelMap[s]
 			if !ok {
-				return newVarValueError(`transaction_isolation`, s, "serializable")
+				return newVarValueError(`transaction_isolation`, s, "serializable", "read committed")
 			}
-			return nil
+			return evalCtx.TxnModesSetter.setTransactionModes(
+				ctx, tree.TransactionModes{Isolation: level}, hlc.Timestamp{} /* asOfTs */)
 		},
 		GlobalDefault: func(_ *settings.Values) string { return "serializable" },
 	},
@@ -1006,6 +1038,26 @@ var varGen = map[string]sessionVar{
 		GlobalDefault: globalFalse,
 	},
 
+	// CockroachDB extension. Set by clients to propagate their W3C trace
+	// context; see https://www.w3.org/TR/trace-context/#traceparent-header.
+	`traceparent`: {
+		Set: func(
+			_ context.Context, m *sessionDataMutator, s string,
+		) error {
+			if s != "" {
+				if err := tracing.ValidateTraceParent(s); err != nil {
+					return pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
+				}
+			}
+			m.SetTraceParent(s)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return evalCtx.SessionData.TraceParent
+		},
+		GlobalDefault: func(_ *settings.Values) string { return "" },
+	},
+
 	// CockroachDB extension.
 	`tracing`: {
 		Get: func(evalCtx *extendedEvalContext) string {
@@ -334,6 +334,9 @@ func nodeName(plan planNode) string {
 // be changed without changing the output of "EXPLAIN".
 var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterDatabaseOwnerNode{}):      "alter database owner",
+	reflect.TypeOf(&alterDatabaseAddRegionNode{}):  "alter database add region",
+	reflect.TypeOf(&alterDatabaseDropRegionNode{}): "alter database drop region",
+	reflect.TypeOf(&alterDatabaseSurviveNode{}):    "alter database survive",
 	reflect.TypeOf(&alterIndexNode{}):              "alter index",
 	reflect.TypeOf(&alterSequenceNode{}):           "alter sequence",
 	reflect.TypeOf(&alterSchemaNode{}):             "alter schema",
@@ -379,6 +382,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&explainPlanNode{}):             "explain plan",
 	reflect.TypeOf(&explainVecNode{}):              "explain vectorized",
 	reflect.TypeOf(&exportNode{}):                  "export",
+	reflect.TypeOf(&fetchNode{}):                   "fetch",
 	reflect.TypeOf(&filterNode{}):                  "filter",
 	reflect.TypeOf(&GrantRoleNode{}):               "grant role",
 	reflect.TypeOf(&groupNode{}):                   "group",
@@ -392,6 +396,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&limitNode{}):                   "limit",
 	reflect.TypeOf(&lookupJoinNode{}):              "lookup join",
 	reflect.TypeOf(&max1RowNode{}):                 "max1row",
+	reflect.TypeOf(&moveNode{}):                    "move",
 	reflect.TypeOf(&ordinalityNode{}):              "ordinality",
 	reflect.TypeOf(&projectSetNode{}):              "project set",
 	reflect.TypeOf(&reassignOwnedByNode{}):         "reassign owned by",
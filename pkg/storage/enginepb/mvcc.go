@@ -169,6 +169,7 @@ func (ms *MVCCStats) Add(oms MVCCStats) {
 	ms.SysBytes += oms.SysBytes
 	ms.SysCount += oms.SysCount
 	ms.AbortSpanBytes += oms.AbortSpanBytes
+	ms.RangeTombstoneCount += oms.RangeTombstoneCount
 }
 
 // Subtract removes oms from ms. The ages will be moved forward to the larger of
@@ -195,6 +196,7 @@ func (ms *MVCCStats) Subtract(oms MVCCStats) {
 	ms.SysBytes -= oms.SysBytes
 	ms.SysCount -= oms.SysCount
 	ms.AbortSpanBytes -= oms.AbortSpanBytes
+	ms.RangeTombstoneCount -= oms.RangeTombstoneCount
 }
 
 // IsInline returns true if the value is inlined in the metadata.
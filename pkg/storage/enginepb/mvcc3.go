@@ -46,3 +46,19 @@ func (op *MVCCLogicalOp) MustSetValue(value interface{}) {
 		panic(errors.AssertionFailedf("%T excludes %T", op, value))
 	}
 }
+
+// SafeValue implements the redact.SafeValue interface.
+func (IsolationLevel) SafeValue() {}
+
+// ToleratesWriteSkew returns whether transactions running under the isolation
+// level are allowed to commit at a timestamp above the one at which they read,
+// without first verifying that their reads are still valid.
+func (l IsolationLevel) ToleratesWriteSkew() bool {
+	return l == ReadCommitted
+}
+
+// PerStatementReadSnapshot returns whether each statement of transactions
+// running under the isolation level reads from its own, newer snapshot.
+func (l IsolationLevel) PerStatementReadSnapshot() bool {
+	return l == ReadCommitted
+}
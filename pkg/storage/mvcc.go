@@ -769,6 +769,9 @@ type MVCCGetOptions struct {
 	Tombstones       bool
 	FailOnMoreRecent bool
 	Txn              *roachpb.Transaction
+	// rangeTombstones are the MVCC range tombstones covering the key, loaded
+	// by MVCCGet.
+	rangeTombstones MVCCRangeTombstones
 }
 
 func (opts *MVCCGetOptions) validate() error {
@@ -808,6 +811,12 @@ func (opts *MVCCGetOptions) validate() error {
 func MVCCGet(
 	ctx context.Context, reader Reader, key roachpb.Key, timestamp hlc.Timestamp, opts MVCCGetOptions,
 ) (*roachpb.Value, *roachpb.Intent, error) {
+	if !isSysLocal(key) {
+		var err error
+		if opts.rangeTombstones, err = ReadMVCCRangeTombstones(reader, key, key.Next()); err != nil {
+			return nil, nil, err
+		}
+	}
 	iter := reader.NewIterator(IterOptions{Prefix: true})
 	defer iter.Close()
 	return mvccGet(ctx, iter, key, timestamp, opts)
@@ -826,8 +835,10 @@ func mvccGet(
 		return nil, nil, err
 	}
 
-	// If the iterator has a specialized implementation, defer to that.
-	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() {
+	// If the iterator has a specialized implementation, defer to that. The
+	// specialized implementations are unaware of MVCC range tombstones.
+	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() &&
+		len(opts.rangeTombstones) == 0 {
 		return mvccIter.MVCCGet(key, timestamp, opts)
 	}
 
@@ -845,6 +856,7 @@ func mvccGet(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		rangeTombstones:  opts.rangeTombstones,
 		keyBuf:           mvccScanner.keyBuf,
 	}
 
@@ -1153,6 +1165,9 @@ type putBuffer struct {
 	newMeta enginepb.MVCCMetadata
 	ts      hlc.LegacyTimestamp
 	tmpbuf  []byte
+	// rangeTombstones is the MVCC range tombstone stack covering the key
+	// being written, if any.
+	rangeTombstones *MVCCRangeTombstoneStack
 }
 
 var putBufferPool = sync.Pool{
@@ -1345,6 +1360,9 @@ func maybeGetValue(
 			ctx, iter, metaKey, readTS, true /* consistent */, safeValue, txn, getBuf); err != nil {
 			return nil, err
 		}
+		if exVal != nil && buf.rangeTombstones.hides(exVal.Timestamp, readTS) {
+			exVal = nil
+		}
 	}
 	return valueFn(exVal)
 }
@@ -1538,6 +1556,15 @@ func mvccPutInternal(
 		return err
 	}
 
+	// Load the MVCC range tombstones covering the key. Inline values can't be
+	// covered by them, and blind puts don't read existing data.
+	buf.rangeTombstones = nil
+	if reader, isReader := writer.(Reader); isReader && iter != nil && timestamp != (hlc.Timestamp{}) {
+		if buf.rangeTombstones, err = readMVCCRangeTombstoneStack(reader, key); err != nil {
+			return err
+		}
+	}
+
 	// Verify we're not mixing inline and non-inline values.
 	putIsInline := timestamp == (hlc.Timestamp{})
 	if ok && putIsInline != buf.meta.IsInline() {
@@ -1604,6 +1631,11 @@ func mvccPutInternal(
 		IntentHistory: buf.meta.IntentHistory,
 	}
 
+	// A range tombstone covering the key deletes it at each of the
+	// tombstone's timestamps, so a write at or below the most recent one is
+	// too old, just like a write at or below a committed value.
+	rangeTombstoneTS := buf.rangeTombstones.Newest()
+
 	var maybeTooOldErr error
 	var prevValSize int64
 	if ok {
@@ -1693,6 +1725,9 @@ func mvccPutInternal(
 				if err != nil {
 					return err
 				}
+				if existingVal != nil && buf.rangeTombstones.hides(existingVal.Timestamp, readTimestamp) {
+					existingVal = nil
+				}
 			}
 
 			// Make sure we process valueFn before clearing any earlier
@@ -1717,12 +1752,17 @@ func mvccPutInternal(
 					// move the intent above it. A similar phenomenon occurs in
 					// MVCCResolveWriteIntent.
 					latestKey := MVCCKey{Key: key, Timestamp: metaTimestamp}
-					_, prevUnsafeVal, haveNextVersion, err := unsafeNextVersion(iter, latestKey)
+					prevKey, prevUnsafeVal, haveNextVersion, err := unsafeNextVersion(iter, latestKey)
 					if err != nil {
 						return err
 					}
 					if haveNextVersion {
 						prevValSize = int64(len(prevUnsafeVal))
+						// A version deleted by a range tombstone accrues GCBytesAge
+						// from the tombstone's timestamp regardless of the intent.
+						if _, shadowed := buf.rangeTombstones.ShadowedAt(prevKey.Timestamp); shadowed {
+							prevValSize = 0
+						}
 					}
 					iter = nil // prevent accidental use below
 				}
@@ -1767,9 +1807,9 @@ func mvccPutInternal(
 			} else {
 				buf.newMeta.IntentHistory = nil
 			}
-		} else if readTimestamp.LessEq(metaTimestamp) {
+		} else if readTimestamp.LessEq(metaTimestamp) || readTimestamp.LessEq(rangeTombstoneTS) {
 			// This is the case where we're trying to write under a committed
-			// value. Obviously we can't do that, but we can increment our
+			// value (or range tombstone). Obviously we can't do that, but we can increment our
 			// timestamp to one logical tick past the existing value and go on
 			// to write, but then also return a write-too-old error indicating
 			// what the timestamp ended up being. This timestamp can then be
@@ -1790,6 +1830,9 @@ func mvccPutInternal(
 			// instead of allowing their transactions to continue and be retried
 			// before committing.
 			writeTimestamp.Forward(metaTimestamp.Next())
+			if !rangeTombstoneTS.IsEmpty() {
+				writeTimestamp.Forward(rangeTombstoneTS.Next())
+			}
 			maybeTooOldErr = roachpb.NewWriteTooOldError(readTimestamp, writeTimestamp)
 			// If we're in a transaction, always get the value at the orig
 			// timestamp.
@@ -1819,6 +1862,11 @@ func mvccPutInternal(
 	} else {
 		// There is no existing value for this key. Even if the new value is
 		// nil write a deletion tombstone for the key.
+		if !rangeTombstoneTS.IsEmpty() && readTimestamp.LessEq(rangeTombstoneTS) {
+			// Writing under a range tombstone is too old, as above.
+			writeTimestamp.Forward(rangeTombstoneTS.Next())
+			maybeTooOldErr = roachpb.NewWriteTooOldError(readTimestamp, writeTimestamp)
+		}
 		if valueFn != nil {
 			value, err = valueFn(nil)
 			if err != nil {
@@ -1881,8 +1929,19 @@ func mvccPutInternal(
 
 	// Update MVCC stats.
 	if ms != nil {
+		// A committed value deleted by a range tombstone is accounted for as
+		// a deletion at the tombstone's timestamp.
+		orig := meta
+		if meta != nil && meta.Txn == nil && !meta.Deleted {
+			if shadowTS, shadowed := buf.rangeTombstones.ShadowedAt(hlc.Timestamp(meta.Timestamp)); shadowed {
+				shadowedMeta := *meta
+				shadowedMeta.Deleted = true
+				shadowedMeta.Timestamp = hlc.LegacyTimestamp(shadowTS)
+				orig = &shadowedMeta
+			}
+		}
 		ms.Add(updateStatsOnPut(key, prevValSize, origMetaKeySize, origMetaValSize,
-			metaKeySize, metaValSize, meta, newMeta))
+			metaKeySize, metaValSize, orig, newMeta))
 	}
 
 	// Log the logical MVCC operation.
@@ -2196,6 +2255,9 @@ func MVCCMerge(
 // incremental deltas of clearing these keys (and correctly determining if it
 // does or not not change the live and gc keys) so the caller is responsible for
 // recomputing stats over the resulting span if needed.
+//
+// MVCC range tombstones are not cleared, so the span must not contain any
+// with timestamps above startTime.
 func MVCCClearTimeRange(
 	_ context.Context,
 	rw ReadWriter,
@@ -2207,6 +2269,19 @@ func MVCCClearTimeRange(
 	var batchSize int64
 	var resume *roachpb.Span
 
+	tombstones, err := ReadMVCCRangeTombstones(rw, key, endKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range tombstones {
+		for _, t := range s.Timestamps {
+			if startTime.Less(t) {
+				return nil, errors.Errorf("cannot clear MVCC range tombstone %s at %s",
+					roachpb.Span{Key: s.StartKey, EndKey: s.EndKey}, t)
+			}
+		}
+	}
+
 	// When iterating, instead of immediately clearing a matching key we can
 	// accumulate it in buf until either a) useRangeClearThreshold is reached and
 	// we discard the buffer, instead just keeping track of where the span of keys
@@ -2296,9 +2371,17 @@ func MVCCClearTimeRange(
 				restoredMeta.Deleted = valueSize == 0
 				restoredMeta.ValBytes = valueSize
 				restoredMeta.Timestamp = hlc.LegacyTimestamp(k.Timestamp)
+				restoredNanos := k.Timestamp.WallTime
+				// A restored version deleted by a range tombstone is restored as a
+				// deletion at the tombstone's timestamp. The tombstone is below
+				// startTime and thus below the cleared version.
+				if shadowTS, ok := tombstones.Covering(k.Key).ShadowedAt(k.Timestamp); ok && !restoredMeta.Deleted {
+					restoredMeta.Deleted = true
+					restoredNanos = shadowTS.WallTime
+				}
 
 				ms.Add(updateStatsOnClear(
-					clearedMetaKey.Key, metaKeySize, 0, metaKeySize, 0, &clearedMeta, &restoredMeta, k.Timestamp.WallTime,
+					clearedMetaKey.Key, metaKeySize, 0, metaKeySize, 0, &clearedMeta, &restoredMeta, restoredNanos,
 				))
 			} else {
 				// We cleared a revision of a different key, so nothing was "restored".
@@ -2438,8 +2521,10 @@ func mvccScanToBytes(
 		return MVCCScanResult{ResumeSpan: resumeSpan}, nil
 	}
 
-	// If the iterator has a specialized implementation, defer to that.
-	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() {
+	// If the iterator has a specialized implementation, defer to that. The
+	// specialized implementations are unaware of MVCC range tombstones.
+	if mvccIter, ok := iter.(MVCCIterator); ok && mvccIter.MVCCOpsSpecialized() &&
+		len(opts.rangeTombstones) == 0 {
 		return mvccIter.MVCCScan(key, endKey, timestamp, opts)
 	}
 
@@ -2457,6 +2542,7 @@ func mvccScanToBytes(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		rangeTombstones:  opts.rangeTombstones,
 		keyBuf:           mvccScanner.keyBuf,
 	}
 
@@ -2570,6 +2656,9 @@ type MVCCScanOptions struct {
 	//
 	// The zero value indicates no limit.
 	TargetBytes int64
+	// rangeTombstones are the MVCC range tombstones overlapping the scan,
+	// loaded by the exported MVCCScan family of functions.
+	rangeTombstones MVCCRangeTombstones
 }
 
 func (opts *MVCCScanOptions) validate() error {
@@ -2637,6 +2726,10 @@ func MVCCScan(
 	timestamp hlc.Timestamp,
 	opts MVCCScanOptions,
 ) (MVCCScanResult, error) {
+	var err error
+	if opts.rangeTombstones, err = ReadMVCCRangeTombstones(reader, key, endKey); err != nil {
+		return MVCCScanResult{}, err
+	}
 	iter := reader.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
 	return mvccScanToKvs(ctx, iter, key, endKey, timestamp, opts)
@@ -2650,6 +2743,10 @@ func MVCCScanToBytes(
 	timestamp hlc.Timestamp,
 	opts MVCCScanOptions,
 ) (MVCCScanResult, error) {
+	var err error
+	if opts.rangeTombstones, err = ReadMVCCRangeTombstones(reader, key, endKey); err != nil {
+		return MVCCScanResult{}, err
+	}
 	iter := reader.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
 	return mvccScanToBytes(ctx, iter, key, endKey, timestamp, opts)
@@ -2692,6 +2789,10 @@ func MVCCIterate(
 	opts MVCCScanOptions,
 	f func(roachpb.KeyValue) error,
 ) ([]roachpb.Intent, error) {
+	var err error
+	if opts.rangeTombstones, err = ReadMVCCRangeTombstones(reader, key, endKey); err != nil {
+		return nil, err
+	}
 	iter := reader.NewIterator(IterOptions{LowerBound: key, UpperBound: endKey})
 	defer iter.Close()
 
@@ -2992,6 +3093,18 @@ func mvccResolveWriteIntent(
 				return false, err
 			} else if valid && iter.UnsafeKey().Key.Equal(oldKey.Key) {
 				prevValSize = int64(len(iter.UnsafeValue()))
+				// A version deleted by a range tombstone accrues GCBytesAge from
+				// the tombstone's timestamp regardless of the intent.
+				if prevValSize > 0 {
+					prevTS := iter.UnsafeKey().Timestamp
+					stack, err := readMVCCRangeTombstoneStack(rw, intent.Key)
+					if err != nil {
+						return false, err
+					}
+					if _, shadowed := stack.ShadowedAt(prevTS); shadowed {
+						prevValSize = 0
+					}
+				}
 			}
 		}
 
@@ -3064,6 +3177,20 @@ func mvccResolveWriteIntent(
 		KeyBytes: MVCCVersionTimestampSize,
 		ValBytes: valueSize,
 	}
+	restoredNanos := unsafeNextKey.Timestamp.WallTime
+	if ms != nil && valueSize > 0 {
+		// If the next version was deleted by a range tombstone, it is restored
+		// as a deletion at the tombstone's timestamp.
+		nextTS := unsafeNextKey.Timestamp
+		stack, err := readMVCCRangeTombstoneStack(rw, intent.Key)
+		if err != nil {
+			return false, err
+		}
+		if shadowTS, shadowed := stack.ShadowedAt(nextTS); shadowed {
+			buf.newMeta.Deleted = true
+			restoredNanos = shadowTS.WallTime
+		}
+	}
 	if err := rw.Clear(metaKey); err != nil {
 		return false, err
 	}
@@ -3073,7 +3200,7 @@ func mvccResolveWriteIntent(
 	// Update stat counters with older version.
 	if ms != nil {
 		ms.Add(updateStatsOnClear(intent.Key, origMetaKeySize, origMetaValSize,
-			metaKeySize, metaValSize, meta, &buf.newMeta, unsafeNextKey.Timestamp.WallTime))
+			metaKeySize, metaValSize, meta, &buf.newMeta, restoredNanos))
 	}
 
 	return true, nil
@@ -3246,6 +3373,12 @@ func MVCCResolveWriteIntentRangeUsingIter(
 // key, clearing all values with timestamps <= to expiration. The
 // timestamp parameter is used to compute the intent age on GC.
 //
+// Keys of MVCC range tombstone fragments may be included, in which case the
+// range tombstones of the fragment with timestamps <= to expiration are
+// removed. This happens after all other keys have been processed, since the
+// versions covered by the range tombstones must be garbage collected before
+// the range tombstones themselves.
+//
 // Note that this method will be sorting the keys.
 func MVCCGarbageCollect(
 	ctx context.Context,
@@ -3253,7 +3386,7 @@ func MVCCGarbageCollect(
 	ms *enginepb.MVCCStats,
 	keys []roachpb.GCRequest_GCKey,
 	timestamp hlc.Timestamp,
-) error {
+) (err error) {
 
 	var count int64
 	defer func(begin time.Time) {
@@ -3274,6 +3407,37 @@ func MVCCGarbageCollect(
 		return iKey.Less(jKey)
 	})
 
+	// Set aside the range tombstone fragments, and load the range tombstones
+	// covering the remaining keys.
+	var rangeTombstoneKeys []roachpb.GCRequest_GCKey
+	{
+		var pointKeys []roachpb.GCRequest_GCKey
+		for _, gcKey := range keys {
+			if isMVCCRangeTombstoneKey(gcKey.Key) {
+				rangeTombstoneKeys = append(rangeTombstoneKeys, gcKey)
+			} else {
+				pointKeys = append(pointKeys, gcKey)
+			}
+		}
+		if len(rangeTombstoneKeys) > 0 {
+			keys = pointKeys
+		}
+	}
+	defer func() {
+		for _, gcKey := range rangeTombstoneKeys {
+			if err == nil {
+				err = mvccGarbageCollectRangeTombstone(ctx, rw, ms, gcKey.Key, gcKey.Timestamp)
+			}
+		}
+	}()
+	if len(keys) == 0 {
+		return nil
+	}
+	tombstones, err := ReadMVCCRangeTombstones(rw, keys[0].Key, keys[len(keys)-1].Key.Next())
+	if err != nil {
+		return err
+	}
+
 	// Bound the iterator appropriately for the set of keys we'll be garbage
 	// collecting.
 	iter := rw.NewIterator(IterOptions{
@@ -3296,6 +3460,7 @@ func MVCCGarbageCollect(
 		}
 		inlinedValue := meta.IsInline()
 		implicitMeta := iter.UnsafeKey().IsValue()
+		stack := tombstones.Covering(gcKey.Key)
 		// First, check whether all values of the key are being deleted.
 		//
 		// Note that we naively can't terminate GC'ing keys loop early if we
@@ -3306,11 +3471,17 @@ func MVCCGarbageCollect(
 		// sure each individual GCRequest does bounded work.
 		if hlc.Timestamp(meta.Timestamp).LessEq(gcKey.Timestamp) {
 			// For version keys, don't allow GC'ing the meta key if it's
-			// not marked deleted. However, for inline values we allow it;
+			// not marked deleted (or deleted by a range tombstone that is
+			// itself being GC'ed). However, for inline values we allow it;
 			// they are internal and GCing them directly saves the extra
 			// deletion step.
+			nonLiveNanos := meta.Timestamp.WallTime
 			if !meta.Deleted && !inlinedValue {
-				return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				shadowTS, shadowed := stack.ShadowedAt(hlc.Timestamp(meta.Timestamp))
+				if !shadowed || gcKey.Timestamp.Less(shadowTS) || meta.Txn != nil {
+					return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+				}
+				nonLiveNanos = shadowTS.WallTime
 			}
 			if meta.Txn != nil {
 				return errors.Errorf("request to GC intent at %q", gcKey.Key)
@@ -3320,7 +3491,7 @@ func MVCCGarbageCollect(
 					updateStatsForInline(ms, gcKey.Key, metaKeySize, metaValSize, 0, 0)
 					ms.AgeTo(timestamp.WallTime)
 				} else {
-					ms.Add(updateStatsOnGC(gcKey.Key, metaKeySize, metaValSize, meta, meta.Timestamp.WallTime))
+					ms.Add(updateStatsOnGC(gcKey.Key, metaKeySize, metaValSize, meta, nonLiveNanos))
 				}
 			}
 			if !implicitMeta {
@@ -3432,11 +3603,14 @@ func MVCCGarbageCollect(
 				// when it's a deletion.
 				valSize := int64(len(iter.UnsafeValue()))
 
-				// A non-deletion becomes non-live when its newer neighbor shows up.
+				// A non-deletion becomes non-live when its newer neighbor shows up,
+				// or when a range tombstone deletes it if that happened earlier.
 				// A deletion tombstone becomes non-live right when it is created.
 				fromNS := prevNanos
 				if valSize == 0 {
 					fromNS = unsafeIterKey.Timestamp.WallTime
+				} else if shadowTS, ok := stack.ShadowedAt(unsafeIterKey.Timestamp); ok && shadowTS.WallTime < fromNS {
+					fromNS = shadowTS.WallTime
 				}
 
 				ms.Add(updateStatsOnGC(gcKey.Key, MVCCVersionTimestampSize,
@@ -3587,12 +3761,27 @@ func ComputeStatsGo(
 	start, end roachpb.Key,
 	nowNanos int64,
 	callbacks ...func(MVCCKey, []byte) error,
+) (enginepb.MVCCStats, error) {
+	return computeStatsGo(iter, start, end, nowNanos, nil /* tombstones */, callbacks...)
+}
+
+// computeStatsGo is like ComputeStatsGo, but additionally accounts for the
+// given MVCC range tombstones, which must include all range tombstones
+// overlapping the span.
+func computeStatsGo(
+	iter SimpleIterator,
+	start, end roachpb.Key,
+	nowNanos int64,
+	tombstones MVCCRangeTombstones,
+	callbacks ...func(MVCCKey, []byte) error,
 ) (enginepb.MVCCStats, error) {
 	var ms enginepb.MVCCStats
 
 	var meta enginepb.MVCCMetadata
 	var prevKey []byte
 	first := false
+	// The MVCC range tombstones covering the current key, if any.
+	var stack *MVCCRangeTombstoneStack
 
 	// Values start accruing GCBytesAge at the timestamp at which they
 	// are shadowed (i.e. overwritten) whereas deletion tombstones
@@ -3667,11 +3856,23 @@ func ComputeStatsGo(
 				}
 			}
 
+			// A value shadowed by a range tombstone is deleted at the
+			// tombstone's timestamp.
+			stack = tombstones.Covering(unsafeKey.Key)
+			if !isSys && !meta.Deleted && meta.Txn == nil && !meta.IsInline() {
+				if shadowTS, ok := stack.ShadowedAt(hlc.Timestamp(meta.Timestamp)); ok {
+					meta.Deleted = true
+					meta.Timestamp = hlc.LegacyTimestamp(shadowTS)
+				}
+			}
+
 			if isSys {
 				ms.SysBytes += totalBytes
 				ms.SysCount++
 				if isAbortSpanKey(unsafeKey.Key) {
 					ms.AbortSpanBytes += totalBytes
+				} else if isMVCCRangeTombstoneKey(unsafeKey.Key) {
+					ms.RangeTombstoneCount++
 				}
 			} else {
 				if !meta.Deleted {
@@ -3718,7 +3919,7 @@ func ComputeStatsGo(
 					return ms, errors.Errorf("expected mvcc metadata val bytes to equal %d; got %d "+
 						"(meta: %s)", len(unsafeValue), meta.ValBytes, &meta)
 				}
-				accrueGCAgeNanos = meta.Timestamp.WallTime
+				accrueGCAgeNanos = unsafeKey.Timestamp.WallTime
 			} else {
 				// Overwritten value. Is it a deletion tombstone?
 				isTombstone := len(unsafeValue) == 0
@@ -3727,8 +3928,13 @@ func ComputeStatsGo(
 					ms.GCBytesAge += totalBytes * (nowNanos/1e9 - unsafeKey.Timestamp.WallTime/1e9)
 				} else {
 					// The kv pair is an overwritten value, so it became non-live when the closest more
-					// recent value was written.
-					ms.GCBytesAge += totalBytes * (nowNanos/1e9 - accrueGCAgeNanos/1e9)
+					// recent value was written, or when a range tombstone deleted it if that was
+					// earlier.
+					nonLiveNanos := accrueGCAgeNanos
+					if shadowTS, ok := stack.ShadowedAt(unsafeKey.Timestamp); ok && shadowTS.WallTime < nonLiveNanos {
+						nonLiveNanos = shadowTS.WallTime
+					}
+					ms.GCBytesAge += totalBytes * (nowNanos/1e9 - nonLiveNanos/1e9)
 				}
 				// Update for the next version we may end up looking at.
 				accrueGCAgeNanos = unsafeKey.Timestamp.WallTime
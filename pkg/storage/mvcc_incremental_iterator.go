@@ -115,12 +115,18 @@ func NewMVCCIncrementalIterator(
 	if !opts.IterOptions.MinTimestampHint.IsEmpty() && !opts.IterOptions.MaxTimestampHint.IsEmpty() {
 		// An iterator without the timestamp hints is created to ensure that the
 		// iterator visits every required version of every key that has changed.
-		iter = reader.NewIterator(IterOptions{
+		iter = NewIteratorWithRangeTombstones(reader, IterOptions{
+			LowerBound: opts.IterOptions.LowerBound,
 			UpperBound: opts.IterOptions.UpperBound,
 		})
-		timeBoundIter = reader.NewIterator(opts.IterOptions)
+		// MVCC range tombstones are presented as deletions of keys that may not
+		// have any versions within the time bounds, so the time-bound iterator
+		// cannot be used to skip keys in their presence.
+		if _, ok := iter.(*rangeTombstoneIterator); !ok {
+			timeBoundIter = reader.NewIterator(opts.IterOptions)
+		}
 	} else {
-		iter = reader.NewIterator(opts.IterOptions)
+		iter = NewIteratorWithRangeTombstones(reader, opts.IterOptions)
 	}
 
 	return &MVCCIncrementalIterator{
@@ -0,0 +1,757 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// An MVCC range tombstone deletes all keys in a span at a timestamp, in the
+// same way that a point deletion tombstone deletes a single key. Versions
+// below the tombstone's timestamp are hidden from reads at or above it, but
+// remain visible to reads below it until they are garbage collected.
+//
+// Range tombstones are stored as a set of non-overlapping fragments, each of
+// which is an inline value at keys.MVCCRangeTombstoneKey(start). A fragment
+// records its end key and the (descending) timestamps of all the range
+// tombstones covering its span. Writing a range tombstone that partially
+// overlaps existing fragments splits them at the tombstone's bounds, so that
+// all keys in a fragment share the same tombstone history. Since fragments are
+// addressed by their start key, a fragment straddling a range split is split
+// along with the range (see MVCCSplitRangeTombstones).
+//
+// For the purposes of MVCC stats, a key whose most recent version is a value
+// shadowed by a range tombstone is considered deleted at the (lowest) shadowing
+// tombstone's timestamp, and an overwritten value accrues GCBytesAge from the
+// earlier of the next version's timestamp and the timestamp of the oldest range
+// tombstone above it. The fragments themselves are accounted for as system
+// keys, and are additionally counted in MVCCStats.RangeTombstoneCount. Looking
+// up the range tombstones covering a key requires an additional seek, which
+// callers evaluating requests on a range whose stats count no fragments avoid
+// by way of DisableMVCCRangeTombstoneLookups.
+
+// MVCCRangeTombstoneStack is a fragment of the MVCC range tombstone keyspace
+// along with the timestamps of all range tombstones covering it.
+type MVCCRangeTombstoneStack struct {
+	StartKey, EndKey roachpb.Key
+	// Timestamps are the timestamps of the range tombstones covering the
+	// fragment, in descending order.
+	Timestamps []hlc.Timestamp
+}
+
+// Newest returns the timestamp of the most recent range tombstone in the
+// stack, or an empty timestamp if the stack is nil.
+func (s *MVCCRangeTombstoneStack) Newest() hlc.Timestamp {
+	if s == nil || len(s.Timestamps) == 0 {
+		return hlc.Timestamp{}
+	}
+	return s.Timestamps[0]
+}
+
+// VisibleAt returns the timestamp of the most recent range tombstone in the
+// stack that is visible to a read at the given timestamp, if any.
+func (s *MVCCRangeTombstoneStack) VisibleAt(ts hlc.Timestamp) (hlc.Timestamp, bool) {
+	if s == nil {
+		return hlc.Timestamp{}, false
+	}
+	for _, t := range s.Timestamps {
+		if t.LessEq(ts) {
+			return t, true
+		}
+	}
+	return hlc.Timestamp{}, false
+}
+
+// ShadowedAt returns the timestamp of the oldest range tombstone in the stack
+// above the given timestamp, i.e. the timestamp at which a version written at
+// ts became deleted by a range tombstone, if any.
+func (s *MVCCRangeTombstoneStack) ShadowedAt(ts hlc.Timestamp) (hlc.Timestamp, bool) {
+	if s == nil {
+		return hlc.Timestamp{}, false
+	}
+	for i := len(s.Timestamps) - 1; i >= 0; i-- {
+		if ts.Less(s.Timestamps[i]) {
+			return s.Timestamps[i], true
+		}
+	}
+	return hlc.Timestamp{}, false
+}
+
+// hides returns whether a version at versionTS is deleted by a range
+// tombstone in the stack as far as a read at readTS is concerned.
+func (s *MVCCRangeTombstoneStack) hides(versionTS, readTS hlc.Timestamp) bool {
+	t, ok := s.VisibleAt(readTS)
+	return ok && versionTS.Less(t)
+}
+
+// MVCCRangeTombstones is a sorted set of non-overlapping MVCC range tombstone
+// stacks.
+type MVCCRangeTombstones []MVCCRangeTombstoneStack
+
+// Covering returns the stack covering the given key, or nil if there is none.
+func (ts MVCCRangeTombstones) Covering(key roachpb.Key) *MVCCRangeTombstoneStack {
+	i := sort.Search(len(ts), func(i int) bool {
+		return key.Compare(ts[i].EndKey) < 0
+	})
+	if i < len(ts) && ts[i].StartKey.Compare(key) <= 0 {
+		return &ts[i]
+	}
+	return nil
+}
+
+// isMVCCRangeTombstoneKey returns whether the key is the key of an MVCC range
+// tombstone fragment.
+func isMVCCRangeTombstoneKey(key roachpb.Key) bool {
+	return bytes.HasPrefix(key, keys.LocalMVCCRangeTombstonePrefix)
+}
+
+func encodeMVCCRangeTombstoneValue(s MVCCRangeTombstoneStack) roachpb.Value {
+	b := encoding.EncodeBytesAscending(nil, s.EndKey)
+	for _, t := range s.Timestamps {
+		b = encoding.EncodeUvarintAscending(b, uint64(t.WallTime))
+		b = encoding.EncodeUvarintAscending(b, uint64(t.Logical))
+	}
+	return roachpb.MakeValueFromBytes(b)
+}
+
+func decodeMVCCRangeTombstone(key roachpb.Key, rawMeta []byte) (MVCCRangeTombstoneStack, error) {
+	var s MVCCRangeTombstoneStack
+	start, err := keys.DecodeMVCCRangeTombstoneKey(key)
+	if err != nil {
+		return s, err
+	}
+	s.StartKey = append(roachpb.Key(nil), start...)
+
+	var meta enginepb.MVCCMetadata
+	if err := protoutil.Unmarshal(rawMeta, &meta); err != nil {
+		return s, errors.Wrapf(err, "unable to decode MVCC range tombstone at %s", key)
+	}
+	v := roachpb.Value{RawBytes: meta.RawBytes}
+	b, err := v.GetBytes()
+	if err != nil {
+		return s, errors.Wrapf(err, "unable to decode MVCC range tombstone at %s", key)
+	}
+	var end []byte
+	if b, end, err = encoding.DecodeBytesAscending(b, nil); err != nil {
+		return s, errors.Wrapf(err, "unable to decode MVCC range tombstone at %s", key)
+	}
+	s.EndKey = append(roachpb.Key(nil), end...)
+	for len(b) > 0 {
+		var wall, logical uint64
+		if b, wall, err = encoding.DecodeUvarintAscending(b); err != nil {
+			return s, errors.Wrapf(err, "unable to decode MVCC range tombstone at %s", key)
+		}
+		if b, logical, err = encoding.DecodeUvarintAscending(b); err != nil {
+			return s, errors.Wrapf(err, "unable to decode MVCC range tombstone at %s", key)
+		}
+		s.Timestamps = append(s.Timestamps, hlc.Timestamp{WallTime: int64(wall), Logical: int32(logical)})
+	}
+	if len(s.Timestamps) == 0 || s.EndKey.Compare(s.StartKey) <= 0 {
+		return s, errors.AssertionFailedf("invalid MVCC range tombstone %s-%s at %s",
+			s.StartKey, s.EndKey, key)
+	}
+	return s, nil
+}
+
+// mvccRangeTombstoneLookupsDisabled is implemented by the readers returned
+// from DisableMVCCRangeTombstoneLookups.
+type mvccRangeTombstoneLookupsDisabled interface {
+	mvccRangeTombstoneLookupsDisabled()
+}
+
+type noRangeTombstonesReadWriter struct {
+	ReadWriter
+}
+
+func (noRangeTombstonesReadWriter) mvccRangeTombstoneLookupsDisabled() {}
+
+type noRangeTombstonesBatch struct {
+	Batch
+}
+
+func (noRangeTombstonesBatch) mvccRangeTombstoneLookupsDisabled() {}
+
+// DisableMVCCRangeTombstoneLookups returns a ReadWriter which reads from and
+// writes to the given one, but which MVCC operations treat as containing no
+// MVCC range tombstones, without seeking to the range tombstones covering the
+// keys they access. The caller must ensure that this is the case, typically
+// because the MVCC stats of the range on which it is evaluating requests count
+// no range tombstone fragments. If the given ReadWriter is a Batch, so is the
+// returned one.
+func DisableMVCCRangeTombstoneLookups(rw ReadWriter) ReadWriter {
+	if b, ok := rw.(Batch); ok {
+		return noRangeTombstonesBatch{Batch: b}
+	}
+	return noRangeTombstonesReadWriter{ReadWriter: rw}
+}
+
+// ReadMVCCRangeTombstones returns the MVCC range tombstone fragments
+// overlapping the span [start, end), ordered by start key. Fragments are
+// returned in full, i.e. they are not truncated to the span. Local keys cannot
+// be covered by range tombstones, so the part of the span below keys.LocalMax
+// is ignored.
+func ReadMVCCRangeTombstones(reader Reader, start, end roachpb.Key) (MVCCRangeTombstones, error) {
+	if _, ok := reader.(mvccRangeTombstoneLookupsDisabled); ok {
+		return nil, nil
+	}
+	if start.Compare(keys.LocalMax) < 0 {
+		start = keys.LocalMax
+	}
+	if end.Compare(start) <= 0 {
+		return nil, nil
+	}
+	iter := reader.NewIterator(IterOptions{
+		LowerBound: keys.LocalMVCCRangeTombstonePrefix,
+		UpperBound: keys.MVCCRangeTombstoneKey(roachpb.RKey(end)),
+	})
+	defer iter.Close()
+
+	var res MVCCRangeTombstones
+	// The fragment covering start, if any, is the last one starting at or
+	// before it.
+	startKey := keys.MVCCRangeTombstoneKey(roachpb.RKey(start))
+	iter.SeekLT(MakeMVCCMetadataKey(startKey.Next()))
+	ok, err := iter.Valid()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		s, err := decodeMVCCRangeTombstone(iter.UnsafeKey().Key, iter.UnsafeValue())
+		if err != nil {
+			return nil, err
+		}
+		if s.EndKey.Compare(start) > 0 {
+			res = append(res, s)
+		}
+		iter.Next()
+	} else {
+		iter.SeekGE(MakeMVCCMetadataKey(startKey))
+	}
+	for ; ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		s, err := decodeMVCCRangeTombstone(iter.UnsafeKey().Key, iter.UnsafeValue())
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+// readMVCCRangeTombstoneStack returns the MVCC range tombstone stack covering
+// the given key, if any.
+func readMVCCRangeTombstoneStack(
+	reader Reader, key roachpb.Key,
+) (*MVCCRangeTombstoneStack, error) {
+	if isSysLocal(key) {
+		return nil, nil
+	}
+	ts, err := ReadMVCCRangeTombstones(reader, key, key.Next())
+	if err != nil {
+		return nil, err
+	}
+	return ts.Covering(key), nil
+}
+
+func putMVCCRangeTombstoneStack(
+	ctx context.Context, rw ReadWriter, ms *enginepb.MVCCStats, s MVCCRangeTombstoneStack,
+) error {
+	key := keys.MVCCRangeTombstoneKey(roachpb.RKey(s.StartKey))
+	value := encodeMVCCRangeTombstoneValue(s)
+	value.InitChecksum(key)
+	var delta enginepb.MVCCStats
+	if err := MVCCPut(ctx, rw, &delta, key, hlc.Timestamp{}, value, nil /* txn */); err != nil {
+		return err
+	}
+	addMVCCRangeTombstoneStats(ms, delta)
+	return nil
+}
+
+func clearMVCCRangeTombstoneStack(
+	ctx context.Context, rw ReadWriter, ms *enginepb.MVCCStats, s MVCCRangeTombstoneStack,
+) error {
+	key := keys.MVCCRangeTombstoneKey(roachpb.RKey(s.StartKey))
+	var delta enginepb.MVCCStats
+	if err := MVCCDelete(ctx, rw, &delta, key, hlc.Timestamp{}, nil /* txn */); err != nil {
+		return err
+	}
+	addMVCCRangeTombstoneStats(ms, delta)
+	return nil
+}
+
+// addMVCCRangeTombstoneStats adds the stats delta resulting from writing or
+// removing a single fragment to ms, if non-nil. Since fragments are system
+// keys, the change in the number of fragments is the change in SysCount.
+func addMVCCRangeTombstoneStats(ms *enginepb.MVCCStats, delta enginepb.MVCCStats) {
+	if ms == nil {
+		return
+	}
+	delta.RangeTombstoneCount = delta.SysCount
+	ms.Add(delta)
+}
+
+// MVCCDeleteRangeUsingTombstone deletes all keys in [start, end) at the given
+// timestamp by writing an MVCC range tombstone. Unlike MVCCDeleteRange, the
+// cost of the write does not depend on the number of keys deleted, though the
+// keys are still scanned to check for conflicts and to update the stats.
+//
+// The operation is non-transactional. It fails with a WriteIntentError if the
+// span contains intents, and with a WriteTooOldError if the span contains a
+// version or range tombstone at or above the timestamp. Inline values cannot
+// be deleted by range tombstones.
+func MVCCDeleteRangeUsingTombstone(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	start, end roachpb.Key,
+	timestamp hlc.Timestamp,
+) error {
+	if timestamp.IsEmpty() {
+		return errors.Errorf("cannot write MVCC range tombstone without timestamp")
+	}
+	if start.Compare(keys.LocalMax) < 0 || end.Compare(start) <= 0 {
+		return errors.Errorf("invalid MVCC range tombstone span %s", roachpb.Span{Key: start, EndKey: end})
+	}
+
+	existing, err := ReadMVCCRangeTombstones(rw, start, end)
+	if err != nil {
+		return err
+	}
+	var tooOldTS hlc.Timestamp
+	for _, s := range existing {
+		if timestamp.LessEq(s.Newest()) {
+			tooOldTS.Forward(s.Newest())
+		}
+	}
+
+	// Scan the point keys in the span, checking for conflicts and accumulating
+	// the contributions of the keys that the tombstone deletes.
+	iter := rw.NewIterator(IterOptions{LowerBound: start, UpperBound: end})
+	defer iter.Close()
+
+	var intents []roachpb.Intent
+	var delta enginepb.MVCCStats
+	delta.AgeTo(timestamp.WallTime)
+	var meta enginepb.MVCCMetadata
+	for iter.SeekGE(MakeMVCCMetadataKey(start)); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.IsValue() {
+			if err := protoutil.Unmarshal(iter.UnsafeValue(), &meta); err != nil {
+				return errors.Wrap(err, "unable to decode MVCCMetadata")
+			}
+			if meta.IsInline() {
+				return errors.Errorf("cannot delete inline value %s with an MVCC range tombstone",
+					unsafeKey.Key)
+			}
+			intents = append(intents, roachpb.MakeIntent(meta.Txn, iter.Key().Key))
+			continue
+		}
+		if timestamp.LessEq(unsafeKey.Timestamp) {
+			tooOldTS.Forward(unsafeKey.Timestamp)
+			continue
+		}
+		unsafeValue := iter.UnsafeValue()
+		if len(unsafeValue) == 0 {
+			// Already deleted by a point tombstone.
+			continue
+		}
+		if _, ok := existing.Covering(unsafeKey.Key).ShadowedAt(unsafeKey.Timestamp); ok {
+			// Already deleted by an older range tombstone.
+			continue
+		}
+		metaKeySize := int64(len(unsafeKey.Key)) + 1
+		delta.LiveBytes -= metaKeySize + MVCCVersionTimestampSize + int64(len(unsafeValue))
+		delta.LiveCount--
+		rw.LogLogicalOp(MVCCWriteValueOpType, MVCCLogicalOpDetails{
+			Key:       unsafeKey.Key,
+			Timestamp: timestamp,
+		})
+	}
+	if len(intents) > 0 {
+		return &roachpb.WriteIntentError{Intents: intents}
+	}
+	if !tooOldTS.IsEmpty() {
+		return roachpb.NewWriteTooOldError(timestamp, tooOldTS.Next())
+	}
+
+	// Replace the existing fragments overlapping the span with the fragments
+	// resulting from adding the new tombstone to them.
+	for _, s := range existing {
+		if err := clearMVCCRangeTombstoneStack(ctx, rw, ms, s); err != nil {
+			return err
+		}
+	}
+	for _, s := range fragmentMVCCRangeTombstone(existing, start, end, timestamp) {
+		if err := putMVCCRangeTombstoneStack(ctx, rw, ms, s); err != nil {
+			return err
+		}
+	}
+	if ms != nil {
+		ms.Add(delta)
+	}
+	return nil
+}
+
+// fragmentMVCCRangeTombstone returns the fragments resulting from adding a
+// range tombstone over [start, end) at the given timestamp to the existing
+// fragments overlapping the span. The parts of the existing fragments outside
+// of the span are retained as separate fragments.
+func fragmentMVCCRangeTombstone(
+	existing MVCCRangeTombstones, start, end roachpb.Key, timestamp hlc.Timestamp,
+) MVCCRangeTombstones {
+	var res MVCCRangeTombstones
+	cur := start
+	for _, s := range existing {
+		if s.StartKey.Compare(start) < 0 {
+			res = append(res, MVCCRangeTombstoneStack{
+				StartKey: s.StartKey, EndKey: start, Timestamps: s.Timestamps,
+			})
+			s.StartKey = start
+		}
+		if cur.Compare(s.StartKey) < 0 {
+			res = append(res, MVCCRangeTombstoneStack{
+				StartKey: cur, EndKey: s.StartKey, Timestamps: []hlc.Timestamp{timestamp},
+			})
+		}
+		overlapEnd := s.EndKey
+		if end.Compare(overlapEnd) < 0 {
+			overlapEnd = end
+		}
+		res = append(res, MVCCRangeTombstoneStack{
+			StartKey:   s.StartKey,
+			EndKey:     overlapEnd,
+			Timestamps: append([]hlc.Timestamp{timestamp}, s.Timestamps...),
+		})
+		cur = overlapEnd
+		if end.Compare(s.EndKey) < 0 {
+			res = append(res, MVCCRangeTombstoneStack{
+				StartKey: end, EndKey: s.EndKey, Timestamps: s.Timestamps,
+			})
+		}
+	}
+	if cur.Compare(end) < 0 {
+		res = append(res, MVCCRangeTombstoneStack{
+			StartKey: cur, EndKey: end, Timestamps: []hlc.Timestamp{timestamp},
+		})
+	}
+	return res
+}
+
+// MVCCSplitRangeTombstones splits the MVCC range tombstone fragment straddling
+// the given key, if any, into two fragments at the key. This is required when
+// splitting a range at the key, since fragments are addressed by their start
+// key and must not extend beyond the range containing them.
+func MVCCSplitRangeTombstones(
+	ctx context.Context, rw ReadWriter, ms *enginepb.MVCCStats, splitKey roachpb.Key,
+) error {
+	existing, err := ReadMVCCRangeTombstones(rw, splitKey, splitKey.Next())
+	if err != nil {
+		return err
+	}
+	for _, s := range existing {
+		if splitKey.Compare(s.StartKey) <= 0 {
+			continue
+		}
+		left, right := s, s
+		left.EndKey, right.StartKey = splitKey, splitKey
+		if err := putMVCCRangeTombstoneStack(ctx, rw, ms, left); err != nil {
+			return err
+		}
+		if err := putMVCCRangeTombstoneStack(ctx, rw, ms, right); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mvccGarbageCollectRangeTombstone removes the range tombstones at or below
+// the given timestamp from the fragment with the given key, removing the
+// fragment altogether if no range tombstones remain. The caller must have
+// garbage collected all versions covered by the fragment at or below the
+// timestamp, since these would otherwise be revealed.
+func mvccGarbageCollectRangeTombstone(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	key roachpb.Key,
+	timestamp hlc.Timestamp,
+) error {
+	start, err := keys.DecodeMVCCRangeTombstoneKey(key)
+	if err != nil {
+		return err
+	}
+	existing, err := ReadMVCCRangeTombstones(rw, start.AsRawKey(), start.AsRawKey().Next())
+	if err != nil {
+		return err
+	}
+	for _, s := range existing {
+		if !s.StartKey.Equal(start.AsRawKey()) {
+			continue
+		}
+		n := sort.Search(len(s.Timestamps), func(i int) bool {
+			return s.Timestamps[i].LessEq(timestamp)
+		})
+		if n == len(s.Timestamps) {
+			return nil
+		}
+		if n == 0 {
+			return clearMVCCRangeTombstoneStack(ctx, rw, ms, s)
+		}
+		s.Timestamps = s.Timestamps[:n]
+		return putMVCCRangeTombstoneStack(ctx, rw, ms, s)
+	}
+	return nil
+}
+
+// ComputeStatsWithRangeTombstones is like iter.ComputeStats, but accounts for
+// MVCC range tombstones read from the given reader, and counts the range
+// tombstone fragments in the span. If there are no range tombstones in the
+// span, the span does not overlap the fragments' keyspace and no callbacks are
+// specified, it defers to iter.ComputeStats. See ComputeStatsGo for a
+// description of the callbacks.
+func ComputeStatsWithRangeTombstones(
+	reader Reader,
+	iter Iterator,
+	start, end roachpb.Key,
+	nowNanos int64,
+	callbacks ...func(MVCCKey, []byte) error,
+) (enginepb.MVCCStats, error) {
+	tombstones, err := ReadMVCCRangeTombstones(reader, start, end)
+	if err != nil {
+		return enginepb.MVCCStats{}, err
+	}
+	fragments := start.Compare(keys.LocalMVCCRangeTombstoneMax) < 0 &&
+		end.Compare(keys.LocalMVCCRangeTombstonePrefix) > 0
+	if len(tombstones) == 0 && !fragments && len(callbacks) == 0 {
+		return iter.ComputeStats(start, end, nowNanos)
+	}
+	return computeStatsGo(iter, start, end, nowNanos, tombstones, callbacks...)
+}
+
+// rangeTombstoneIterator is an Iterator which presents MVCC range tombstones
+// as point deletion tombstones. For each key covered by a range tombstone
+// that deleted a live version of the key, a deletion tombstone is synthesized
+// at the range tombstone's timestamp. It only supports forward iteration.
+type rangeTombstoneIterator struct {
+	Iterator
+	tombstones MVCCRangeTombstones
+	err        error
+	// buf holds the versions of the current key merged with the synthesized
+	// deletion tombstones, when the current key is covered by a range
+	// tombstone. The underlying iterator is then positioned after the key.
+	buf      []MVCCKeyValue
+	bufIdx   int
+	buffered bool
+}
+
+var _ Iterator = &rangeTombstoneIterator{}
+
+// NewIteratorWithRangeTombstones returns an iterator over the given reader
+// which presents MVCC range tombstones in the iterator's bounds as point
+// deletion tombstones on the keys they deleted. This allows consumers of
+// historical MVCC data, such as exports and rangefeed catch-up scans, to
+// observe range tombstones without being aware of them. The returned iterator
+// only supports forward iteration.
+func NewIteratorWithRangeTombstones(reader Reader, opts IterOptions) Iterator {
+	iter := reader.NewIterator(opts)
+	start, end := opts.LowerBound, opts.UpperBound
+	if len(end) == 0 {
+		end = roachpb.KeyMax
+	}
+	tombstones, err := ReadMVCCRangeTombstones(reader, start, end)
+	if err == nil && len(tombstones) == 0 {
+		return iter
+	}
+	return &rangeTombstoneIterator{Iterator: iter, tombstones: tombstones, err: err}
+}
+
+// SeekGE implements the SimpleIterator interface.
+func (i *rangeTombstoneIterator) SeekGE(key MVCCKey) {
+	if i.err != nil {
+		return
+	}
+	i.Iterator.SeekGE(key)
+	i.fill(key)
+}
+
+// Next implements the SimpleIterator interface.
+func (i *rangeTombstoneIterator) Next() {
+	if i.err != nil {
+		return
+	}
+	if !i.buffered {
+		// The current key isn't covered by a range tombstone, so if the
+		// iterator moves onto a covered key, it must be a new one.
+		i.Iterator.Next()
+		i.fill(MVCCKey{})
+		return
+	}
+	i.bufIdx++
+	if i.bufIdx == len(i.buf) {
+		i.fill(MVCCKey{})
+	}
+}
+
+// NextKey implements the SimpleIterator interface.
+func (i *rangeTombstoneIterator) NextKey() {
+	if i.err != nil {
+		return
+	}
+	if !i.buffered {
+		i.Iterator.NextKey()
+	}
+	i.fill(MVCCKey{})
+}
+
+// Valid implements the SimpleIterator interface.
+func (i *rangeTombstoneIterator) Valid() (bool, error) {
+	if i.err != nil {
+		return false, i.err
+	}
+	if i.buffered {
+		return true, nil
+	}
+	return i.Iterator.Valid()
+}
+
+// Key implements the Iterator interface.
+func (i *rangeTombstoneIterator) Key() MVCCKey {
+	if i.buffered {
+		return i.buf[i.bufIdx].Key
+	}
+	return i.Iterator.Key()
+}
+
+// UnsafeKey implements the SimpleIterator interface.
+func (i *rangeTombstoneIterator) UnsafeKey() MVCCKey {
+	if i.buffered {
+		return i.buf[i.bufIdx].Key
+	}
+	return i.Iterator.UnsafeKey()
+}
+
+// Value implements the Iterator interface.
+func (i *rangeTombstoneIterator) Value() []byte {
+	if i.buffered {
+		return i.buf[i.bufIdx].Value
+	}
+	return i.Iterator.Value()
+}
+
+// UnsafeValue implements the SimpleIterator interface.
+func (i *rangeTombstoneIterator) UnsafeValue() []byte {
+	if i.buffered {
+		return i.buf[i.bufIdx].Value
+	}
+	return i.Iterator.UnsafeValue()
+}
+
+// UnsafeRawKey implements the Iterator interface.
+func (i *rangeTombstoneIterator) UnsafeRawKey() []byte {
+	if i.buffered {
+		return EncodeKey(i.buf[i.bufIdx].Key)
+	}
+	return i.Iterator.UnsafeRawKey()
+}
+
+// ValueProto implements the Iterator interface.
+func (i *rangeTombstoneIterator) ValueProto(msg protoutil.Message) error {
+	return protoutil.Unmarshal(i.UnsafeValue(), msg)
+}
+
+// SupportsPrev implements the Iterator interface.
+func (i *rangeTombstoneIterator) SupportsPrev() bool {
+	return false
+}
+
+// fill buffers the versions of the key at the underlying iterator's position
+// if it is covered by a range tombstone, merging them with the deletion
+// tombstones synthesized for the range tombstones. If the iterator was
+// positioned by a seek to a specific version of the key, only tombstones at
+// or below that version are synthesized.
+func (i *rangeTombstoneIterator) fill(seekKey MVCCKey) {
+	i.buf, i.bufIdx, i.buffered = nil, 0, false
+	if ok, err := i.Iterator.Valid(); err != nil || !ok {
+		return
+	}
+	unsafeKey := i.Iterator.UnsafeKey()
+	stack := i.tombstones.Covering(unsafeKey.Key)
+	if stack == nil {
+		return
+	}
+	var seekTS hlc.Timestamp
+	if unsafeKey.Key.Equal(seekKey.Key) {
+		seekTS = seekKey.Timestamp
+	}
+
+	key := append(roachpb.Key(nil), unsafeKey.Key...)
+	var versions []MVCCKeyValue
+	for {
+		versions = append(versions, MVCCKeyValue{
+			Key:   MVCCKey{Key: key, Timestamp: i.Iterator.UnsafeKey().Timestamp},
+			Value: append([]byte(nil), i.Iterator.UnsafeValue()...),
+		})
+		i.Iterator.Next()
+		if ok, err := i.Iterator.Valid(); err != nil {
+			i.err = err
+			return
+		} else if !ok || !i.Iterator.UnsafeKey().Key.Equal(key) {
+			break
+		}
+	}
+
+	// Merge the versions, which are in descending timestamp order after the
+	// metadata key if there is one, with the synthesized tombstones.
+	buf := make([]MVCCKeyValue, 0, len(versions)+len(stack.Timestamps))
+	v := 0
+	if !versions[0].Key.IsValue() {
+		buf = append(buf, versions[0])
+		v++
+	}
+	for _, t := range stack.Timestamps {
+		for ; v < len(versions) && t.LessEq(versions[v].Key.Timestamp); v++ {
+			buf = append(buf, versions[v])
+		}
+		if len(buf) > 0 && buf[len(buf)-1].Key.Timestamp == t {
+			// There can't be two versions at the same timestamp.
+			continue
+		}
+		// The range tombstone deleted the key if the version below it is live.
+		if v == len(versions) || len(versions[v].Value) == 0 {
+			continue
+		}
+		if !seekTS.IsEmpty() && seekTS.Less(t) {
+			continue
+		}
+		buf = append(buf, MVCCKeyValue{Key: MVCCKey{Key: key, Timestamp: t}})
+	}
+	buf = append(buf, versions[v:]...)
+	i.buf, i.buffered = buf, true
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/kr/pretty"
+	"github.com/stretchr/testify/require"
+)
+
+// assertRangeTombstoneStats verifies that the given stats match those computed
+// over the whole engine, taking MVCC range tombstones into account.
+func assertRangeTombstoneStats(
+	t *testing.T, reader Reader, debug string, ms *enginepb.MVCCStats, nowNanos int64,
+) {
+	t.Helper()
+	msCpy := *ms
+	msCpy.AgeTo(nowNanos)
+	iter := reader.NewIterator(IterOptions{UpperBound: roachpb.KeyMax})
+	defer iter.Close()
+	expMS, err := ComputeStatsWithRangeTombstones(reader, iter, roachpb.KeyMin, roachpb.KeyMax, nowNanos)
+	require.NoError(t, err)
+	if !msCpy.Equal(expMS) {
+		t.Errorf("%s: diff(ms, computed) = %s", debug, pretty.Diff(msCpy, expMS))
+	}
+}
+
+func TestMVCCRangeTombstoneReads(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(wall int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wall} }
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ms := &enginepb.MVCCStats{}
+			for _, k := range []roachpb.Key{testKey1, testKey2, testKey3, testKey4} {
+				require.NoError(t, MVCCPut(ctx, engine, ms, k, ts(1), value1, nil))
+			}
+			require.NoError(t, MVCCPut(ctx, engine, ms, testKey3, ts(3), value2, nil))
+			assertRangeTombstoneStats(t, engine, "before delete", ms, 3)
+
+			// Writing below an existing version fails.
+			err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey2, testKey4, ts(2))
+			require.True(t, errors.HasType(err, (*roachpb.WriteTooOldError)(nil)), "%v", err)
+
+			require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey2, testKey4, ts(5)))
+			assertRangeTombstoneStats(t, engine, "after delete", ms, 5)
+			require.EqualValues(t, 1, ms.RangeTombstoneCount)
+
+			tombstones, err := ReadMVCCRangeTombstones(engine, roachpb.KeyMin, roachpb.KeyMax)
+			require.NoError(t, err)
+			require.Equal(t, MVCCRangeTombstones{{
+				StartKey: testKey2, EndKey: testKey4, Timestamps: []hlc.Timestamp{ts(5)},
+			}}, tombstones)
+
+			// Readers with range tombstone lookups disabled don't observe them.
+			noLookups := DisableMVCCRangeTombstoneLookups(engine)
+			tombstones, err = ReadMVCCRangeTombstones(noLookups, roachpb.KeyMin, roachpb.KeyMax)
+			require.NoError(t, err)
+			require.Empty(t, tombstones)
+			val, _, err := MVCCGet(ctx, noLookups, testKey2, ts(5), MVCCGetOptions{})
+			require.NoError(t, err)
+			require.NotNil(t, val)
+
+			// Reads below the range tombstone observe the deleted keys, reads at or
+			// above it don't.
+			for _, k := range []roachpb.Key{testKey2, testKey3} {
+				val, _, err := MVCCGet(ctx, engine, k, ts(4), MVCCGetOptions{})
+				require.NoError(t, err)
+				require.NotNil(t, val)
+				val, _, err = MVCCGet(ctx, engine, k, ts(5), MVCCGetOptions{})
+				require.NoError(t, err)
+				require.Nil(t, val)
+			}
+			res, err := MVCCScan(ctx, engine, testKey1, keyMax, ts(6), MVCCScanOptions{})
+			require.NoError(t, err)
+			require.Len(t, res.KVs, 2)
+			require.Equal(t, testKey1, res.KVs[0].Key)
+			require.Equal(t, testKey4, res.KVs[1].Key)
+			res, err = MVCCScan(ctx, engine, testKey1, keyMax, ts(4), MVCCScanOptions{})
+			require.NoError(t, err)
+			require.Len(t, res.KVs, 4)
+
+			// Tombstones reveal the deletion to reads which also return them.
+			res, err = MVCCScan(ctx, engine, testKey1, keyMax, ts(6), MVCCScanOptions{Tombstones: true})
+			require.NoError(t, err)
+			require.Len(t, res.KVs, 4)
+			require.Equal(t, ts(5), res.KVs[1].Value.Timestamp)
+			require.Empty(t, res.KVs[1].Value.RawBytes)
+
+			// Writes below the range tombstone fail, and writes above it
+			// resurrect the key.
+			err = MVCCPut(ctx, engine, ms, testKey2, ts(4), value3, nil)
+			require.True(t, errors.HasType(err, (*roachpb.WriteTooOldError)(nil)), "%v", err)
+			require.NoError(t, MVCCPut(ctx, engine, ms, testKey2, ts(7), value3, nil))
+			val, _, err = MVCCGet(ctx, engine, testKey2, ts(7), MVCCGetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, value3.RawBytes, val.RawBytes)
+			assertRangeTombstoneStats(t, engine, "after put", ms, 7)
+		})
+	}
+}
+
+func TestMVCCRangeTombstoneGarbageCollect(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(wall int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wall} }
+	for _, engineImpl := range mvccEngineImpls {
+		t.Run(engineImpl.name, func(t *testing.T) {
+			engine := engineImpl.create()
+			defer engine.Close()
+
+			ms := &enginepb.MVCCStats{}
+			require.NoError(t, MVCCPut(ctx, engine, ms, testKey1, ts(1), value1, nil))
+			require.NoError(t, MVCCPut(ctx, engine, ms, testKey2, ts(1), value1, nil))
+			require.NoError(t, MVCCPut(ctx, engine, ms, testKey2, ts(2), value2, nil))
+			require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey1, testKey3, ts(3)))
+
+			// The newest versions can't be removed below the range tombstone.
+			err := MVCCGarbageCollect(ctx, engine, ms, []roachpb.GCRequest_GCKey{
+				{Key: testKey1, Timestamp: ts(1)},
+			}, ts(10))
+			require.Error(t, err)
+
+			require.NoError(t, MVCCGarbageCollect(ctx, engine, ms, []roachpb.GCRequest_GCKey{
+				{Key: testKey1, Timestamp: ts(3)},
+				{Key: testKey2, Timestamp: ts(3)},
+				{Key: keys.MVCCRangeTombstoneKey(roachpb.RKey(testKey1)), Timestamp: ts(3)},
+			}, ts(10)))
+			assertRangeTombstoneStats(t, engine, "after GC", ms, 10)
+			require.Zero(t, ms.KeyCount)
+			require.Zero(t, ms.SysCount)
+			require.Zero(t, ms.RangeTombstoneCount)
+
+			tombstones, err := ReadMVCCRangeTombstones(engine, roachpb.KeyMin, roachpb.KeyMax)
+			require.NoError(t, err)
+			require.Empty(t, tombstones)
+		})
+	}
+}
+
+func TestMVCCSplitRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(wall int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wall} }
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	ms := &enginepb.MVCCStats{}
+	require.NoError(t, MVCCPut(ctx, engine, ms, testKey2, ts(1), value1, nil))
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey1, testKey4, ts(2)))
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey2, testKey3, ts(3)))
+	require.NoError(t, MVCCSplitRangeTombstones(ctx, engine, ms, roachpb.Key("/db35")))
+	assertRangeTombstoneStats(t, engine, "after split", ms, 3)
+
+	tombstones, err := ReadMVCCRangeTombstones(engine, roachpb.KeyMin, roachpb.KeyMax)
+	require.NoError(t, err)
+	require.Equal(t, MVCCRangeTombstones{
+		{StartKey: testKey1, EndKey: testKey2, Timestamps: []hlc.Timestamp{ts(2)}},
+		{StartKey: testKey2, EndKey: testKey3, Timestamps: []hlc.Timestamp{ts(3), ts(2)}},
+		{StartKey: testKey3, EndKey: roachpb.Key("/db35"), Timestamps: []hlc.Timestamp{ts(2)}},
+		{StartKey: roachpb.Key("/db35"), EndKey: testKey4, Timestamps: []hlc.Timestamp{ts(2)}},
+	}, tombstones)
+
+	tombstones, err = ReadMVCCRangeTombstones(engine, roachpb.Key("/db35"), keyMax)
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+	require.Equal(t, roachpb.Key("/db35"), tombstones[0].StartKey)
+}
+
+func TestIteratorWithRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts := func(wall int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wall} }
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey1, ts(1), value1, nil))
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey2, ts(1), value1, nil))
+	require.NoError(t, MVCCPut(ctx, engine, nil, testKey3, ts(1), value1, nil))
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, nil, testKey2, testKey3, ts(2)))
+
+	iter := NewIteratorWithRangeTombstones(engine, IterOptions{
+		LowerBound: testKey1, UpperBound: keyMax,
+	})
+	defer iter.Close()
+	var kvs []MVCCKeyValue
+	for iter.SeekGE(MakeMVCCMetadataKey(testKey1)); ; iter.Next() {
+		ok, err := iter.Valid()
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+		kvs = append(kvs, MVCCKeyValue{Key: iter.Key(), Value: iter.Value()})
+	}
+	require.Len(t, kvs, 4)
+	require.Equal(t, MVCCKey{Key: testKey2, Timestamp: ts(2)}, kvs[1].Key)
+	require.Empty(t, kvs[1].Value)
+	require.Equal(t, MVCCKey{Key: testKey2, Timestamp: ts(1)}, kvs[2].Key)
+	require.Equal(t, MVCCKey{Key: testKey3, Timestamp: ts(1)}, kvs[3].Key)
+}
@@ -368,7 +368,7 @@ var pebbleLog *log.SecondaryLogger
 // CockroachDB log. The caller is responsible for ensuring the
 // Close() method is eventually called on the new logger.
 func InitPebbleLogger(ctx context.Context) *log.SecondaryLogger {
-	pebbleLog = log.NewSecondaryLogger(ctx, nil, "pebble",
+	pebbleLog = log.NewSecondaryLogger(ctx, nil, "pebble", log.ChannelStorage,
 		true /* enableGC */, false /* forceSyncWrites */, false /* enableMsgCount */)
 	return pebbleLog
 }
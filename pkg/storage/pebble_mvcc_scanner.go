@@ -127,6 +127,10 @@ type pebbleMVCCScanner struct {
 	curValue  []byte
 	results   pebbleResults
 	intents   pebble.Batch
+	// rangeTombstones are the MVCC range tombstones overlapping the scan, and
+	// curTombstones the stack covering the current key, if any.
+	rangeTombstones MVCCRangeTombstones
+	curTombstones   *MVCCRangeTombstoneStack
 	// mostRecentTS stores the largest timestamp observed that is above the scan
 	// timestamp. Only applicable if failOnMoreRecent is true. If set and no
 	// other error is hit, a WriteToOld error will be returned from the scan.
@@ -284,6 +288,23 @@ func (p *pebbleMVCCScanner) uncertaintyError(ts hlc.Timestamp) bool {
 // Emit a tuple and return true if we have reason to believe iteration can
 // continue.
 func (p *pebbleMVCCScanner) getAndAdvance() bool {
+	if len(p.rangeTombstones) > 0 {
+		// A range tombstone above the read timestamp is treated like a more
+		// recent version of the key.
+		p.curTombstones = p.rangeTombstones.Covering(p.curKey.Key)
+		if newest := p.curTombstones.Newest(); p.ts.Less(newest) {
+			if p.failOnMoreRecent {
+				p.mostRecentTS.Forward(newest)
+			} else if p.checkUncertainty {
+				for _, t := range p.curTombstones.Timestamps {
+					if p.ts.Less(t) && t.LessEq(p.txn.MaxTimestamp) {
+						return p.uncertaintyError(t)
+					}
+				}
+			}
+		}
+	}
+
 	if p.curKey.Timestamp != (hlc.Timestamp{}) {
 		if p.curKey.Timestamp.LessEq(p.ts) {
 			// 1. Fast path: there is no intent and our read timestamp is newer than
@@ -580,6 +601,13 @@ func (p *pebbleMVCCScanner) advanceKeyAtNewKey(key []byte) bool {
 // p.tombstones is true. Advances to the next key unless we've reached the max
 // results limit.
 func (p *pebbleMVCCScanner) addAndAdvance(rawKey []byte, val []byte) bool {
+	if !p.curKey.Timestamp.IsEmpty() && p.curTombstones.hides(p.curKey.Timestamp, p.ts) {
+		// The version is deleted by a range tombstone, which is presented as a
+		// deletion tombstone at the range tombstone's timestamp.
+		t, _ := p.curTombstones.VisibleAt(p.ts)
+		p.keyBuf = EncodeKeyToBuf(p.keyBuf[:0], MVCCKey{Key: p.curKey.Key, Timestamp: t})
+		rawKey, val = p.keyBuf, nil
+	}
 	// Don't include deleted versions len(val) == 0, unless we've been instructed
 	// to include tombstones in the results.
 	if len(val) > 0 || p.tombstones {
@@ -67,7 +67,7 @@ var rocksdbLogger *log.SecondaryLogger
 // CockroachDB log. The caller is responsible for ensuring the
 // Close() method is eventually called on the new logger.
 func InitRocksDBLogger(ctx context.Context) *log.SecondaryLogger {
-	rocksdbLogger = log.NewSecondaryLogger(ctx, nil, "rocksdb",
+	rocksdbLogger = log.NewSecondaryLogger(ctx, nil, "rocksdb", log.ChannelStorage,
 		true /* enableGC */, false /* forceSyncWrites */, false /* enableMsgCount */)
 	return rocksdbLogger
 }
@@ -463,6 +463,14 @@ func (r *RocksDB) ExportToSst(
 	targetSize, maxSize uint64,
 	io IterOptions,
 ) ([]byte, roachpb.BulkOpSummary, roachpb.Key, error) {
+	// The C++ implementation is unaware of MVCC range tombstones, so fall back
+	// to the Go implementation in their presence.
+	if tombstones, err := ReadMVCCRangeTombstones(r, startKey, endKey); err != nil {
+		return nil, roachpb.BulkOpSummary{}, nil, err
+	} else if len(tombstones) > 0 {
+		return pebbleExportToSst(r, startKey, endKey, startTS, endTS, exportAllRevisions, targetSize, maxSize, io)
+	}
+
 	start := MVCCKey{Key: startKey, Timestamp: startTS}
 	end := MVCCKey{Key: endKey, Timestamp: endTS}
 
@@ -772,6 +772,114 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{
+			{KVTransactionLayer, "Requests", "Admission Control"}},
+		Charts: []chartDescription{
+			{
+				Title:       "KV Work Requested",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.requested.kv"},
+			},
+			{
+				Title:       "KV Work Admitted",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.admitted.kv"},
+			},
+			{
+				Title:       "KV Work Errored",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.errored.kv"},
+			},
+			{
+				Title:       "KV Work Wait Durations",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: true,
+				Metrics:     []string{"admission.wait_durations.kv"},
+			},
+			{
+				Title:       "KV Work Wait Queue Length",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.wait_queue_length.kv"},
+			},
+			{
+				Title:       "KV Store Work Requested",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.requested.kv-stores"},
+			},
+			{
+				Title:       "KV Store Work Admitted",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.admitted.kv-stores"},
+			},
+			{
+				Title:       "KV Store Work Errored",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.errored.kv-stores"},
+			},
+			{
+				Title:       "KV Store Work Wait Durations",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: true,
+				Metrics:     []string{"admission.wait_durations.kv-stores"},
+			},
+			{
+				Title:       "KV Store Work Wait Queue Length",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.wait_queue_length.kv-stores"},
+			},
+			{
+				Title:       "SQL Work Requested",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.requested.sql"},
+			},
+			{
+				Title:       "SQL Work Admitted",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.admitted.sql"},
+			},
+			{
+				Title:       "SQL Work Errored",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.errored.sql"},
+			},
+			{
+				Title:       "SQL Work Wait Durations",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: true,
+				Metrics:     []string{"admission.wait_durations.sql"},
+			},
+			{
+				Title:       "SQL Work Wait Queue Length",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.wait_queue_length.sql"},
+			},
+			{
+				Title:       "KV Slots",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.granter.total_slots.kv", "admission.granter.used_slots.kv"},
+			},
+			{
+				Title:       "Stores with Overloaded Storage Engines",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"admission.granter.io_overloaded_stores.kv"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{
 			{KVTransactionLayer, "Requests", "Slow"},
@@ -1268,6 +1376,14 @@ var charts = []sectionDescription{
 				Title:   "Keys/Sec Avg.",
 				Metrics: []string{"rebalancing.writespersecond"},
 			},
+			{
+				Title:   "CPU Nanos/Sec Avg.",
+				Metrics: []string{"rebalancing.cpunanospersecond"},
+			},
+			{
+				Title:   "Write Bytes/Sec Avg.",
+				Metrics: []string{"rebalancing.writebytespersecond"},
+			},
 			{
 				Title:   "Leader Transfers",
 				Metrics: []string{"range.raftleadertransfers"},
@@ -2285,6 +2401,40 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{Jobs, "Schedules", "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Counts",
+				Metrics: []string{
+					"schedules.ROW_LEVEL_TTL.started",
+					"schedules.ROW_LEVEL_TTL.succeeded",
+					"schedules.ROW_LEVEL_TTL.failed",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Jobs, "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Rows",
+				Metrics: []string{
+					"jobs.row_level_ttl.rows_selected",
+					"jobs.row_level_ttl.rows_deleted",
+				},
+				AxisLabel: "Rows",
+			},
+			{
+				Title:   "Select Latency",
+				Metrics: []string{"jobs.row_level_ttl.select_duration"},
+			},
+			{
+				Title:   "Delete Latency",
+				Metrics: []string{"jobs.row_level_ttl.delete_duration"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Jobs, "Execution"}},
 		Charts: []chartDescription{
@@ -2298,6 +2448,7 @@ var charts = []sectionDescription{
 					"jobs.import.currently_running",
 					"jobs.restore.currently_running",
 					"jobs.schema_change.currently_running",
+					"jobs.row_level_ttl.currently_running",
 					"jobs.schema_change_gc.currently_running",
 					"jobs.typedesc_schema_change.currently_running",
 				},
@@ -2374,6 +2525,18 @@ var charts = []sectionDescription{
 				},
 				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
 			},
+			{
+				Title: "Row Level TTL",
+				Metrics: []string{
+					"jobs.row_level_ttl.fail_or_cancel_completed",
+					"jobs.row_level_ttl.fail_or_cancel_failed",
+					"jobs.row_level_ttl.fail_or_cancel_retry_error",
+					"jobs.row_level_ttl.resume_completed",
+					"jobs.row_level_ttl.resume_failed",
+					"jobs.row_level_ttl.resume_retry_error",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
 			{
 				Title: "Schema Change",
 				Metrics: []string{
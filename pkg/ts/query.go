@@ -459,6 +459,14 @@ func (db *DB) Query(
 	if err := verifyDownsampler(query.GetDownsampler()); err != nil {
 		return nil, nil, err
 	}
+	if query.Quantile != nil {
+		if err := verifyQuantileQuery(query); err != nil {
+			return nil, nil, err
+		}
+		// A quantile query reads all of the histogram's bucket series at once;
+		// the memory budget must accommodate the sources of every bucket.
+		mem.EstimatedSources *= tspb.HistogramBucketCount
+	}
 
 	// Adjust timespan based on the current time.
 	if err := timespan.adjustForCurrentTime(diskResolution); err != nil {
@@ -531,6 +539,37 @@ func (db *DB) Query(
 	return result, sources, nil
 }
 
+// histogramQuantile computes the given quantile of the distribution described
+// by the supplied per-bucket counts (see tspb.HistogramBucketCount), linearly
+// interpolating within the bucket containing the quantile. Returns false if
+// the distribution is empty.
+func histogramQuantile(quantile float64, counts []float64) (float64, bool) {
+	total := aggSum(counts)
+	if total <= 0 {
+		return 0, false
+	}
+	rank := quantile * total
+	var cumulative, lowerBound float64
+	last := 0
+	for bucket, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		last = bucket
+		if bucket > 0 {
+			lowerBound = tspb.HistogramBucketUpperBound(bucket - 1)
+		}
+		if cumulative+count >= rank {
+			upperBound := tspb.HistogramBucketUpperBound(bucket)
+			return lowerBound + (upperBound-lowerBound)*(rank-cumulative)/count, true
+		}
+		cumulative += count
+	}
+	// Floating point error may leave the rank slightly above the cumulative
+	// count of all buckets.
+	return tspb.HistogramBucketUpperBound(last), true
+}
+
 // queryChunk processes a chunk of a query; this will read the necessary data
 // from disk and apply the desired processing operations to generate a result.
 func (db *DB) queryChunk(
@@ -549,20 +588,66 @@ func (db *DB) queryChunk(
 	diskTimespan := timespan
 	diskTimespan.expand(mem.InterpolationLimitNanos)
 
-	var data []kv.KeyValue
+	// A quantile query reads all of the histogram's bucket series at once.
+	seriesNames := []string{query.Name}
+	if query.Quantile != nil {
+		seriesNames = make([]string, tspb.HistogramBucketCount)
+		for bucket := range seriesNames {
+			seriesNames[bucket] = tspb.HistogramBucketName(query.Name, bucket)
+		}
+	}
+
+	var data [][]kv.KeyValue
 	var err error
 	if len(query.Sources) == 0 {
-		data, err = db.readAllSourcesFromDatabase(ctx, query.Name, diskResolution, diskTimespan)
+		data, err = db.readAllSourcesFromDatabase(ctx, seriesNames, diskResolution, diskTimespan)
 	} else {
-		data, err = db.readFromDatabase(ctx, query.Name, diskResolution, diskTimespan, query.Sources)
+		data, err = db.readFromDatabase(ctx, seriesNames, diskResolution, diskTimespan, query.Sources)
 	}
 
 	if err != nil {
 		return err
 	}
 
+	// Aggregate the data, increasing our memory usage if the destination slice
+	// is expanded.
+	oldCap := cap(*dest)
+	if query.Quantile != nil {
+		err = aggregateQuantile(
+			ctx, data, query, diskResolution, timespan, mem.InterpolationLimitNanos, &acc, dest, sourceSet,
+		)
+	} else {
+		err = aggregateSeries(
+			ctx, data[0], query, diskResolution, timespan, mem.InterpolationLimitNanos, &acc, dest, sourceSet,
+		)
+	}
+	if err != nil {
+		return err
+	}
+	if oldCap > cap(*dest) {
+		if err := mem.resultAccount.Grow(ctx, sizeOfDataPoint*int64(cap(*dest)-oldCap)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggregateSeries processes the data of a single series read from disk,
+// appending the datapoints generated by the query to dest and the sources of
+// the data to sourceSet.
+func aggregateSeries(
+	ctx context.Context,
+	data []kv.KeyValue,
+	query tspb.Query,
+	diskResolution Resolution,
+	timespan QueryTimespan,
+	interpolationLimitNanos int64,
+	acc *mon.BoundAccount,
+	dest *[]tspb.TimeSeriesDatapoint,
+	sourceSet map[string]struct{},
+) error {
 	// Assemble data into an ordered timeSeriesSpan for each source.
-	sourceSpans, err := convertKeysToSpans(ctx, data, &acc)
+	sourceSpans, err := convertKeysToSpans(ctx, data, acc)
 	if err != nil {
 		return err
 	}
@@ -581,15 +666,7 @@ func (db *DB) queryChunk(
 		query.Downsampler = tspb.TimeSeriesQueryAggregator_SUM.Enum()
 	}
 
-	// Aggregate spans, increasing our memory usage if the destination slice is
-	// expanded.
-	oldCap := cap(*dest)
-	aggregateSpansToDatapoints(sourceSpans, query, timespan, mem.InterpolationLimitNanos, dest)
-	if oldCap > cap(*dest) {
-		if err := mem.resultAccount.Grow(ctx, sizeOfDataPoint*int64(cap(*dest)-oldCap)); err != nil {
-			return err
-		}
-	}
+	aggregateSpansToDatapoints(sourceSpans, query, timespan, interpolationLimitNanos, dest)
 
 	// Add unique sources to the supplied source set.
 	for k := range sourceSpans {
@@ -598,6 +675,71 @@ func (db *DB) queryChunk(
 	return nil
 }
 
+// aggregateQuantile processes the data of each of a histogram's bucket series,
+// read from disk and indexed by bucket, for a quantile query. Bucket series
+// store the number of values recorded into each bucket since the histogram was
+// created; the per-second rate of each bucket, summed across all sources,
+// describes the distribution of all values recorded during each sample
+// period. The quantile is computed for each sample period from the combined
+// bucket rates, yielding the exact (up to the bucket resolution) quantile of
+// all values recorded by the queried sources during that period.
+func aggregateQuantile(
+	ctx context.Context,
+	data [][]kv.KeyValue,
+	query tspb.Query,
+	diskResolution Resolution,
+	timespan QueryTimespan,
+	interpolationLimitNanos int64,
+	acc *mon.BoundAccount,
+	dest *[]tspb.TimeSeriesDatapoint,
+	sourceSet map[string]struct{},
+) error {
+	bucketQuery := tspb.Query{
+		Downsampler:      tspb.TimeSeriesQueryAggregator_MAX.Enum(),
+		SourceAggregator: tspb.TimeSeriesQueryAggregator_SUM.Enum(),
+		Derivative:       tspb.TimeSeriesQueryDerivative_NON_NEGATIVE_DERIVATIVE.Enum(),
+	}
+	bucketRates := make(map[int64][]float64)
+	var rates []tspb.TimeSeriesDatapoint
+	for bucket := range data {
+		rates = rates[:0]
+		if err := aggregateSeries(
+			ctx, data[bucket], bucketQuery, diskResolution, timespan, interpolationLimitNanos, acc, &rates, sourceSet,
+		); err != nil {
+			return err
+		}
+		for _, dp := range rates {
+			r, ok := bucketRates[dp.TimestampNanos]
+			if !ok {
+				r = make([]float64, len(data))
+				bucketRates[dp.TimestampNanos] = r
+			}
+			r[bucket] = dp.Value
+		}
+	}
+
+	timestamps := make([]int64, 0, len(bucketRates))
+	for timestamp := range bucketRates {
+		// Each bucket independently omits its most recent datapoint if not all
+		// sources have reported it yet. A quantile computed from only some of the
+		// buckets would be skewed, so such datapoints are omitted entirely.
+		if timestamp > timespan.NowNanos-timespan.SampleDurationNanos {
+			continue
+		}
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	for _, timestamp := range timestamps {
+		if value, ok := histogramQuantile(query.GetQuantile(), bucketRates[timestamp]); ok {
+			*dest = append(*dest, tspb.TimeSeriesDatapoint{
+				TimestampNanos: timestamp,
+				Value:          value,
+			})
+		}
+	}
+	return nil
+}
+
 // downsampleSpans downsamples the provided timeSeriesSpans in place, without
 // allocating additional memory. The output data from downsampleSpans is
 // single-valued, without rollups; unused rollup data will be discarded.
@@ -820,65 +962,78 @@ func aggregate(agg tspb.TimeSeriesQueryAggregator, values []float64) float64 {
 	panic(fmt.Sprintf("unknown aggregator option encountered: %v", agg))
 }
 
-// readFromDatabase retrieves data for the given series name, at the given disk
-// resolution, across the supplied time span, for only the given list of
-// sources.
+// readFromDatabase retrieves data for the given series names, at the given
+// disk resolution, across the supplied time span, for only the given list of
+// sources. The data of all series is read in a single batch and returned
+// separately for each series, in the order of seriesNames.
 func (db *DB) readFromDatabase(
 	ctx context.Context,
-	seriesName string,
+	seriesNames []string,
 	diskResolution Resolution,
 	timespan QueryTimespan,
 	sources []string,
-) ([]kv.KeyValue, error) {
+) ([][]kv.KeyValue, error) {
 	// Iterate over all key timestamps which may contain data for the given
 	// sources, based on the given start/end time and the resolution.
 	b := &kv.Batch{}
 	startTimestamp := diskResolution.normalizeToSlab(timespan.StartNanos)
 	kd := diskResolution.SlabDuration()
-	for currentTimestamp := startTimestamp; currentTimestamp <= timespan.EndNanos; currentTimestamp += kd {
-		for _, source := range sources {
-			key := MakeDataKey(seriesName, source, diskResolution, currentTimestamp)
-			b.Get(key)
+	keysPerSeries := 0
+	for _, seriesName := range seriesNames {
+		keysPerSeries = 0
+		for currentTimestamp := startTimestamp; currentTimestamp <= timespan.EndNanos; currentTimestamp += kd {
+			for _, source := range sources {
+				key := MakeDataKey(seriesName, source, diskResolution, currentTimestamp)
+				b.Get(key)
+				keysPerSeries++
+			}
 		}
 	}
 	if err := db.db.Run(ctx, b); err != nil {
 		return nil, err
 	}
-	var rows []kv.KeyValue
-	for _, result := range b.Results {
+	rows := make([][]kv.KeyValue, len(seriesNames))
+	for i, result := range b.Results {
 		row := result.Rows[0]
 		if row.Value == nil {
 			continue
 		}
-		rows = append(rows, row)
+		series := i / keysPerSeries
+		rows[series] = append(rows[series], row)
 	}
 	return rows, nil
 }
 
-// readAllSourcesFromDatabase retrieves data for the given series name, at the
+// readAllSourcesFromDatabase retrieves data for the given series names, at the
 // given disk resolution, across the supplied time span, for all sources. The
-// optional limit is used when memory usage is being limited by the number of
-// keys, rather than by timespan.
+// data of all series is read in a single batch and returned separately for
+// each series, in the order of seriesNames.
 func (db *DB) readAllSourcesFromDatabase(
-	ctx context.Context, seriesName string, diskResolution Resolution, timespan QueryTimespan,
-) ([]kv.KeyValue, error) {
+	ctx context.Context, seriesNames []string, diskResolution Resolution, timespan QueryTimespan,
+) ([][]kv.KeyValue, error) {
 	// Based on the supplied timestamps and resolution, construct start and
 	// end keys for a scan that will return every key with data relevant to
 	// the query. Query slightly before and after the actual queried range
 	// to allow interpolation of points at the start and end of the range.
-	startKey := MakeDataKey(
-		seriesName, "" /* source */, diskResolution, timespan.StartNanos,
-	)
-	endKey := MakeDataKey(
-		seriesName, "" /* source */, diskResolution, timespan.EndNanos,
-	).PrefixEnd()
 	b := &kv.Batch{}
-	b.Scan(startKey, endKey)
+	for _, seriesName := range seriesNames {
+		startKey := MakeDataKey(
+			seriesName, "" /* source */, diskResolution, timespan.StartNanos,
+		)
+		endKey := MakeDataKey(
+			seriesName, "" /* source */, diskResolution, timespan.EndNanos,
+		).PrefixEnd()
+		b.Scan(startKey, endKey)
+	}
 
 	if err := db.db.Run(ctx, b); err != nil {
 		return nil, err
 	}
-	return b.Results[0].Rows, nil
+	rows := make([][]kv.KeyValue, len(seriesNames))
+	for i := range b.Results {
+		rows[i] = b.Results[i].Rows
+	}
+	return rows, nil
 }
 
 // convertKeysToSpans converts a batch of KeyValues queried from disk into a
@@ -910,6 +1065,20 @@ func convertKeysToSpans(
 	return sourceSpans, nil
 }
 
+// verifyQuantileQuery verifies the parameters of a query for a quantile of a
+// histogram metric.
+func verifyQuantileQuery(query tspb.Query) error {
+	if q := query.GetQuantile(); !(q > 0 && q <= 1) {
+		return errors.Errorf("quantile %v is not in the range (0, 1]", q)
+	}
+	if query.Downsampler != nil || query.SourceAggregator != nil || query.Derivative != nil {
+		return errors.New(
+			"downsampler, source aggregator and derivative cannot be specified for a quantile query",
+		)
+	}
+	return nil
+}
+
 func verifySourceAggregator(agg tspb.TimeSeriesQueryAggregator) error {
 	switch agg {
 	case tspb.TimeSeriesQueryAggregator_AVG:
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ts/tspb"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/gogo/protobuf/proto"
 )
 
 // runTestCaseMultipleFormats runs the provided test case body against a
@@ -698,3 +699,94 @@ func TestQueryRollup(t *testing.T) {
 		query.assertSuccess(13, 2)
 	}
 }
+
+func TestQueryQuantile(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	runTestCaseMultipleFormats(t, func(t *testing.T, tm testModelRunner) {
+		bucket := func(n int) string {
+			return tspb.HistogramBucketName("test.hist", n)
+		}
+		// Bucket 3 holds values in (4, 8], bucket 4 values in (8, 16] and bucket
+		// 6 values in (32, 64]. Bucket series store cumulative counts.
+		tm.storeTimeSeriesData(resolution1ns, []tspb.TimeSeriesData{
+			tsd(bucket(3), "source1", tsdp(5, 10), tsdp(15, 30), tsdp(25, 30)),
+			tsd(bucket(4), "source1", tsdp(5, 0), tsdp(15, 0), tsdp(25, 10)),
+			tsd(bucket(4), "source2", tsdp(5, 0), tsdp(15, 10), tsdp(25, 10)),
+			tsd(bucket(6), "source2", tsdp(5, 5), tsdp(15, 5), tsdp(25, 45)),
+		})
+
+		assertQuantile := func(
+			query modelQuery, quantile float64, expected []tspb.TimeSeriesDatapoint,
+		) {
+			t.Helper()
+			query.Quantile = &quantile
+			actual, _, err := query.queryDB()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(actual) != len(expected) {
+				t.Fatalf("quantile %v: got datapoints %v, wanted %v", quantile, actual, expected)
+			}
+			for i := range actual {
+				if actual[i].TimestampNanos != expected[i].TimestampNanos ||
+					math.Abs(actual[i].Value-expected[i].Value) > 1e-9 {
+					t.Fatalf("quantile %v: got datapoints %v, wanted %v", quantile, actual, expected)
+				}
+			}
+		}
+
+		// During the first sample period, 20 values were recorded into bucket 3
+		// and 10 into bucket 4; during the second, 10 values were recorded into
+		// bucket 4 and 40 into bucket 6.
+		{
+			query := tm.makeQuery("test.hist", resolution1ns, 0, 30)
+			query.SampleDurationNanos = 10
+			assertQuantile(query, 0.5, []tspb.TimeSeriesDatapoint{
+				tsdp(10, 7),
+				tsdp(20, 44),
+			})
+			assertQuantile(query, 0.9, []tspb.TimeSeriesDatapoint{
+				tsdp(10, 13.6),
+				tsdp(20, 60),
+			})
+			assertQuantile(query, 1, []tspb.TimeSeriesDatapoint{
+				tsdp(10, 16),
+				tsdp(20, 64),
+			})
+		}
+
+		// Restrict the query to a single source.
+		{
+			query := tm.makeQuery("test.hist", resolution1ns, 0, 30)
+			query.SampleDurationNanos = 10
+			query.Sources = []string{"source2"}
+			assertQuantile(query, 0.5, []tspb.TimeSeriesDatapoint{
+				tsdp(10, 12),
+				tsdp(20, 48),
+			})
+		}
+
+		// Datapoints for the most recent sample period are omitted.
+		{
+			query := tm.makeQuery("test.hist", resolution1ns, 0, 30)
+			query.SampleDurationNanos = 10
+			query.NowNanos = 25
+			assertQuantile(query, 0.5, []tspb.TimeSeriesDatapoint{
+				tsdp(10, 7),
+			})
+		}
+
+		// Invalid quantile queries.
+		{
+			query := tm.makeQuery("test.hist", resolution1ns, 0, 30)
+			query.SampleDurationNanos = 10
+			query.Quantile = proto.Float64(0)
+			query.assertError("not in the range")
+			query.Quantile = proto.Float64(1.5)
+			query.assertError("not in the range")
+			query.Quantile = proto.Float64(0.5)
+			query.setDownsampler(tspb.TimeSeriesQueryAggregator_MAX)
+			query.assertError("cannot be specified for a quantile query")
+		}
+	})
+}
@@ -12,6 +12,8 @@ package tspb
 
 import (
 	"fmt"
+	"math"
+	"math/bits"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 )
@@ -106,3 +108,32 @@ func VerifySlabAndSampleDuration(slabDuration, sampleDuration int64) error {
 
 	return nil
 }
+
+// HistogramBucketCount is the number of buckets into which the values recorded
+// by a histogram are sorted when the histogram is persisted as time series.
+// Bucket zero holds all values less than or equal to one; bucket i > 0 holds
+// the values in the range (2^(i-1), 2^i]. The bucket boundaries are fixed so
+// that buckets recorded by different sources, and by histograms with
+// different precisions, can be summed together.
+const HistogramBucketCount = 64
+
+// HistogramBucketName returns the name of the time series which stores the
+// number of values recorded into the given bucket of the named histogram.
+func HistogramBucketName(name string, bucket int) string {
+	return fmt.Sprintf("%s-bucket-%d", name, bucket)
+}
+
+// HistogramBucketForValue returns the bucket into which the supplied
+// histogram value falls.
+func HistogramBucketForValue(v int64) int {
+	if v <= 1 {
+		return 0
+	}
+	return bits.Len64(uint64(v - 1))
+}
+
+// HistogramBucketUpperBound returns the (inclusive) upper bound of the values
+// which fall into the given bucket.
+func HistogramBucketUpperBound(bucket int) float64 {
+	return math.Ldexp(1, bucket)
+}
@@ -22,6 +22,12 @@ export const CREATE_DATABASE = "create_database";
 export const DROP_DATABASE = "drop_database";
 // Recorded when a database is renamed.
 export const RENAME_DATABASE = "rename_database";
+// Recorded when a region is added to a database.
+export const ALTER_DATABASE_ADD_REGION = "alter_database_add_region";
+// Recorded when a region is dropped from a database.
+export const ALTER_DATABASE_DROP_REGION = "alter_database_drop_region";
+// Recorded when the survival goal of a database is changed.
+export const ALTER_DATABASE_SURVIVE = "alter_database_survive";
 // Recorded when a table is created.
 export const CREATE_TABLE = "create_table";
 // Recorded when a table is dropped.
@@ -77,10 +83,19 @@ export const CREATE_STATISTICS = "create_statistics";
 export const GRANT_PRIVILEGE = "grant_privilege";
 // Recorded when privileges are removed from a user(s).
 export const REVOKE_PRIVILEGE = "revoke_privilege";
+// Recorded when a role is created.
+export const CREATE_ROLE = "create_role";
+// Recorded when a role is dropped.
+export const DROP_ROLE = "drop_role";
+// Recorded when a role is altered.
+export const ALTER_ROLE = "alter_role";
 
 // Node Event Types
 export const nodeEvents = [NODE_JOIN, NODE_RESTART, NODE_DECOMMISSIONING, NODE_DECOMMISSIONED, NODE_RECOMMISSIONED];
-export const databaseEvents = [CREATE_DATABASE, DROP_DATABASE];
+export const databaseEvents = [
+  CREATE_DATABASE, DROP_DATABASE, ALTER_DATABASE_ADD_REGION, ALTER_DATABASE_DROP_REGION,
+  ALTER_DATABASE_SURVIVE,
+];
 export const tableEvents = [
   CREATE_TABLE, DROP_TABLE, TRUNCATE_TABLE, ALTER_TABLE, CREATE_INDEX,
   ALTER_INDEX, DROP_INDEX, CREATE_VIEW, DROP_VIEW, REVERSE_SCHEMA_CHANGE,
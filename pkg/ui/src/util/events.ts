@@ -30,6 +30,12 @@ export function getEventDescription(e: Event$Properties): string {
       return `Database Dropped: User ${info.User} dropped database ${info.DatabaseName}. ${tableDropText}`;
     case eventTypes.RENAME_DATABASE:
       return `Database Renamed: User ${info.User} renamed database ${info.DatabaseName} to ${info.NewDatabaseName}`;
+    case eventTypes.ALTER_DATABASE_ADD_REGION:
+      return `Database Altered: User ${info.User} added a region to database ${info.DatabaseName}`;
+    case eventTypes.ALTER_DATABASE_DROP_REGION:
+      return `Database Altered: User ${info.User} dropped a region from database ${info.DatabaseName}`;
+    case eventTypes.ALTER_DATABASE_SURVIVE:
+      return `Database Altered: User ${info.User} changed the survival goal of database ${info.DatabaseName}`;
     case eventTypes.CREATE_TABLE:
       return `Table Created: User ${info.User} created table ${info.TableName}`;
     case eventTypes.DROP_TABLE:
@@ -87,6 +93,12 @@ export function getEventDescription(e: Event$Properties): string {
       return `Privileges granted: User ${info.User} granted ${info.Privileges} to ${info.Grantees} on ${info.Target}`;
     case eventTypes.REVOKE_PRIVILEGE:
       return `Privileges revoked: User ${info.User} revoked ${info.Privileges} from ${info.Grantees} on ${info.Target}`;
+    case eventTypes.CREATE_ROLE:
+      return `Role Created: User ${info.User} created role ${info.RoleName}`;
+    case eventTypes.DROP_ROLE:
+      return `Role Dropped: User ${info.User} dropped role ${info.RoleName}`;
+    case eventTypes.ALTER_ROLE:
+      return `Role Altered: User ${info.User} altered role ${info.RoleName}`;
     default:
       return `Unknown Event Type: ${e.event_type}, content: ${JSON.stringify(info, null, 2)}`;
   }
@@ -111,6 +123,7 @@ export interface EventInfo {
   Statement?: string;
   Grantees?: string;
   Privileges?: string;
+  RoleName?: string;
   // The following are three names for the same key (it was renamed twice).
   // All ar included for backwards compatibility.
   DroppedTables?: string[];
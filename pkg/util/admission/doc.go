@@ -0,0 +1,34 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package admission contains the admission control subsystem, which protects
+// a node from overload by queueing work before it starts executing instead of
+// letting it pile up as goroutines competing for CPU and storage.
+//
+// Work waits in a WorkQueue, ordered by priority and then by creation time,
+// until the queue's granter admits it:
+//
+// - KV work needs one of a limited number of slots, which it holds while it
+//   executes, but not while it waits for latches, locks or replication. The
+//   GrantCoordinator adjusts the number of slots based on the CPU
+//   utilization of the process, so that only as much KV work runs as the CPUs
+//   can keep up with.
+// - SQL work, i.e. the start of a SQL flow, is only admitted while KV slots
+//   are available, but doesn't hold on to one.
+// - Writes to a store additionally need a token from the store's queue. While
+//   the store's storage engine reports too many L0 files or sublevels, the
+//   number of tokens handed out every interval is reduced in proportion to the
+//   overload, so that compactions can catch up.
+//
+// Work that must never wait, like node liveness heartbeats, bypasses the
+// queues, and work on the system keyspace is admitted at a high priority. The
+// net effect is that overload increases the latency of user work instead of
+// causing the node to miss its liveness heartbeats.
+package admission
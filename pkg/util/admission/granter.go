@@ -0,0 +1,442 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"math"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/elastic/gosigar"
+)
+
+var cpuOverloadThreshold = settings.RegisterValidatedFloatSetting(
+	"admission.kv.cpu_overload_threshold",
+	"the fraction of the node's CPU capacity above which the number of KV work items "+
+		"that can execute concurrently is reduced",
+	0.9,
+	func(v float64) error {
+		if v <= 0 || v > 1 {
+			return errors.Errorf("%v is not in (0, 1]", v)
+		}
+		return nil
+	})
+
+var l0SubLevelCountOverloadThreshold = settings.RegisterPositiveIntSetting(
+	"admission.l0_sub_level_count_overload_threshold",
+	"when the number of L0 sublevels of a store exceeds this value, writes to it are throttled",
+	20)
+
+var l0FileCountOverloadThreshold = settings.RegisterPositiveIntSetting(
+	"admission.l0_file_count_overload_threshold",
+	"when the number of L0 files of a store exceeds this value, writes to it are throttled",
+	1000)
+
+const (
+	// cpuLoadSampleInterval is the interval at which the CPU utilization of the
+	// process is sampled to adjust the number of KV slots.
+	cpuLoadSampleInterval = 50 * time.Millisecond
+	// ioTokenInterval is the interval at which the storage engine metrics of
+	// the stores are sampled, and for which the tokens of their queues are
+	// handed out.
+	ioTokenInterval = time.Second
+	// minSlots and maxSlots bound the number of KV slots.
+	minSlots = 1
+	maxSlots = 1 << 14
+	// unlimitedTokens is the number of tokens of a store that isn't overloaded.
+	unlimitedTokens = math.MaxInt64 / 2
+)
+
+// GrantCoordinator admits KV and SQL work based on the CPU utilization of the
+// process.
+//
+// KV work needs a slot, which it holds while it executes; see
+// PauseAdmittedWork. When the process uses more than the
+// admission.kv.cpu_overload_threshold fraction of its CPU capacity, the number
+// of slots decreases by one every sample. Otherwise, if
+// all the slots were used since the previous sample, so that more concurrent
+// work could have run, the number of slots grows by a quarter. SQL work is
+// admitted whenever no KV work is waiting and a slot is available, without
+// holding on to it. Since SQL flows issue KV work, this gives KV work
+// priority without SQL flows on different nodes waiting on each other.
+//
+// The best signal that more KV work is running than the CPUs can keep up with
+// is the number of runnable goroutines waiting for a processor. The Go
+// runtime doesn't expose it, short of parsing scheduler traces, so the CPU
+// utilization of the process is used instead. It reacts more slowly: a
+// sample only shows overload once the CPUs have been saturated for part of
+// it, and utilization can't tell a saturated node from one that is just
+// about to be.
+type GrantCoordinator struct {
+	settings *cluster.Settings
+	// mu is shared with the WorkQueues.
+	mu         syncutil.Mutex
+	totalSlots int
+	usedSlots  int
+	// slotsExhausted is set if all the slots were used since the previous call
+	// to CPULoad.
+	slotsExhausted bool
+
+	kvQueue  *WorkQueue
+	sqlQueue *WorkQueue
+
+	metrics    GranterMetrics
+	kvMetrics  WorkQueueMetrics
+	sqlMetrics WorkQueueMetrics
+}
+
+// NewGrantCoordinator returns a GrantCoordinator. Its slots are only adjusted
+// once Start is called.
+func NewGrantCoordinator(st *cluster.Settings, histogramWindow time.Duration) *GrantCoordinator {
+	c := &GrantCoordinator{
+		settings:   st,
+		totalSlots: runtime.GOMAXPROCS(0),
+		metrics: GranterMetrics{
+			TotalSlots: metric.NewGauge(metaTotalSlots),
+			UsedSlots:  metric.NewGauge(metaUsedSlots),
+		},
+		kvMetrics:  makeWorkQueueMetrics(KVWork, histogramWindow),
+		sqlMetrics: makeWorkQueueMetrics(SQLWork, histogramWindow),
+	}
+	c.metrics.TotalSlots.Update(int64(c.totalSlots))
+	c.kvQueue = makeWorkQueue(
+		KVWork, kvSlotGranter{c}, &c.mu, st, KVAdmissionControlEnabled, &c.kvMetrics)
+	c.sqlQueue = makeWorkQueue(
+		SQLWork, sqlGranter{c}, &c.mu, st, SQLAdmissionControlEnabled, &c.sqlMetrics)
+	return c
+}
+
+// KVWorkQueue returns the WorkQueue for KV work.
+func (c *GrantCoordinator) KVWorkQueue() *WorkQueue {
+	return c.kvQueue
+}
+
+// SQLWorkQueue returns the WorkQueue for SQL work.
+func (c *GrantCoordinator) SQLWorkQueue() *WorkQueue {
+	return c.sqlQueue
+}
+
+// MetricStructs returns the metric.Structs of the GrantCoordinator and its
+// WorkQueues.
+func (c *GrantCoordinator) MetricStructs() []metric.Struct {
+	return []metric.Struct{c.metrics, c.kvMetrics, c.sqlMetrics}
+}
+
+// Start samples the CPU utilization of the process until the stopper quiesces,
+// and adjusts the number of KV slots based on it.
+func (c *GrantCoordinator) Start(ctx context.Context, stopper *stop.Stopper) error {
+	return stopper.RunAsyncTask(ctx, "admission-cpu-load", func(ctx context.Context) {
+		pid := os.Getpid()
+		var lastCPU gosigar.ProcTime
+		if err := lastCPU.Get(pid); err != nil {
+			log.Warningf(ctx, "unable to get cpu usage, KV slots won't be adjusted: %v", err)
+			return
+		}
+		lastSample := timeutil.Now()
+		ticker := time.NewTicker(cpuLoadSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				var cpu gosigar.ProcTime
+				if err := cpu.Get(pid); err != nil {
+					log.Warningf(ctx, "unable to get cpu usage: %v", err)
+					continue
+				}
+				now := timeutil.Now()
+				// ProcTime reports milliseconds.
+				cpuTime := time.Duration(cpu.User+cpu.Sys-lastCPU.User-lastCPU.Sys) * time.Millisecond
+				capacity := now.Sub(lastSample) * time.Duration(runtime.GOMAXPROCS(0))
+				if capacity > 0 {
+					c.CPULoad(float64(cpuTime) / float64(capacity))
+				}
+				lastCPU, lastSample = cpu, now
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+// CPULoad adjusts the number of KV slots given the fraction of the CPU
+// capacity of the process that was used since the previous call.
+func (c *GrantCoordinator) CPULoad(utilization float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if utilization > cpuOverloadThreshold.Get(&c.settings.SV) {
+		if c.totalSlots > minSlots {
+			c.totalSlots--
+		}
+	} else if c.slotsExhausted {
+		c.totalSlots += (c.totalSlots + 3) / 4
+		if c.totalSlots > maxSlots {
+			c.totalSlots = maxSlots
+		}
+	}
+	c.slotsExhausted = c.usedSlots >= c.totalSlots
+	c.metrics.TotalSlots.Update(int64(c.totalSlots))
+	c.grantLocked()
+}
+
+// grantLocked admits as much waiting work as the available slots allow, KV
+// work first.
+func (c *GrantCoordinator) grantLocked() {
+	kv := kvSlotGranter{c}
+	for c.kvQueue.hasWaitingRequestsLocked() && kv.tryGetLocked() {
+		c.kvQueue.grantLocked()
+	}
+	sql := sqlGranter{c}
+	for c.sqlQueue.hasWaitingRequestsLocked() && sql.tryGetLocked() {
+		c.sqlQueue.grantLocked()
+	}
+}
+
+// kvSlotGranter is the granter of the KV WorkQueue.
+type kvSlotGranter struct {
+	c *GrantCoordinator
+}
+
+var _ granter = kvSlotGranter{}
+
+func (g kvSlotGranter) tryGetLocked() bool {
+	if g.c.usedSlots >= g.c.totalSlots {
+		g.c.slotsExhausted = true
+		return false
+	}
+	g.tookWithoutPermissionLocked()
+	return true
+}
+
+func (g kvSlotGranter) returnGrantLocked() {
+	g.workDoneLocked()
+}
+
+func (g kvSlotGranter) tookWithoutPermissionLocked() {
+	g.c.usedSlots++
+	if g.c.usedSlots >= g.c.totalSlots {
+		g.c.slotsExhausted = true
+	}
+	g.c.metrics.UsedSlots.Update(int64(g.c.usedSlots))
+}
+
+func (g kvSlotGranter) workDoneLocked() {
+	g.c.usedSlots--
+	g.c.metrics.UsedSlots.Update(int64(g.c.usedSlots))
+	g.c.grantLocked()
+}
+
+// sqlGranter is the granter of the SQL WorkQueue.
+type sqlGranter struct {
+	c *GrantCoordinator
+}
+
+var _ granter = sqlGranter{}
+
+func (g sqlGranter) tryGetLocked() bool {
+	return !g.c.kvQueue.hasWaitingRequestsLocked() && g.c.usedSlots < g.c.totalSlots
+}
+
+func (g sqlGranter) returnGrantLocked()           {}
+func (g sqlGranter) tookWithoutPermissionLocked() {}
+func (g sqlGranter) workDoneLocked()              {}
+
+// IOMetrics are the storage engine metrics used to detect that a store is
+// overloaded.
+type IOMetrics struct {
+	L0FileCount     int64
+	L0SublevelCount int64
+}
+
+// StoreGrantCoordinators admits writes to the stores of a node based on the
+// health of their storage engines, using a WorkQueue per store.
+//
+// Every ioTokenInterval, each store is handed out tokens for the writes it
+// can admit during the next interval. A store that isn't overloaded gets an
+// unlimited number of them. Once a store has more L0 sublevels or files than
+// allowed, its tokens are the number of writes it admitted during the
+// previous interval scaled down by the ratio of the threshold to the actual
+// value, so that writes slow down until compactions catch up.
+type StoreGrantCoordinators struct {
+	settings *cluster.Settings
+
+	mu struct {
+		syncutil.RWMutex
+		granters map[roachpb.StoreID]*storeTokenGranter
+	}
+
+	metrics      StoreGranterMetrics
+	queueMetrics WorkQueueMetrics
+}
+
+// NewStoreGrantCoordinators returns a StoreGrantCoordinators. Writes aren't
+// throttled until Start is called.
+func NewStoreGrantCoordinators(
+	st *cluster.Settings, histogramWindow time.Duration,
+) *StoreGrantCoordinators {
+	sgc := &StoreGrantCoordinators{
+		settings: st,
+		metrics: StoreGranterMetrics{
+			OverloadedStores: metric.NewGauge(metaOverloadedStores),
+		},
+		queueMetrics: makeWorkQueueMetrics(StoreWork, histogramWindow),
+	}
+	sgc.mu.granters = make(map[roachpb.StoreID]*storeTokenGranter)
+	return sgc
+}
+
+// MetricStructs returns the metric.Structs of the StoreGrantCoordinators and
+// their WorkQueues.
+func (sgc *StoreGrantCoordinators) MetricStructs() []metric.Struct {
+	return []metric.Struct{sgc.metrics, sgc.queueMetrics}
+}
+
+// GetQueue returns the WorkQueue for writes to the given store.
+func (sgc *StoreGrantCoordinators) GetQueue(storeID roachpb.StoreID) *WorkQueue {
+	return sgc.getGranter(storeID).queue
+}
+
+func (sgc *StoreGrantCoordinators) getGranter(storeID roachpb.StoreID) *storeTokenGranter {
+	sgc.mu.RLock()
+	g, ok := sgc.mu.granters[storeID]
+	sgc.mu.RUnlock()
+	if ok {
+		return g
+	}
+	sgc.mu.Lock()
+	defer sgc.mu.Unlock()
+	if g, ok = sgc.mu.granters[storeID]; !ok {
+		g = &storeTokenGranter{availableTokens: unlimitedTokens}
+		g.queue = makeWorkQueue(
+			StoreWork, g, &g.mu, sgc.settings, KVAdmissionControlEnabled, &sgc.queueMetrics)
+		sgc.mu.granters[storeID] = g
+	}
+	return g
+}
+
+// Start periodically retrieves the IOMetrics of the stores from the given
+// function until the stopper quiesces, and hands out the tokens of their
+// queues based on them.
+func (sgc *StoreGrantCoordinators) Start(
+	ctx context.Context,
+	stopper *stop.Stopper,
+	getIOMetrics func() (map[roachpb.StoreID]IOMetrics, error),
+) error {
+	return stopper.RunAsyncTask(ctx, "admission-io-load", func(ctx context.Context) {
+		ticker := time.NewTicker(ioTokenInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ioMetrics, err := getIOMetrics()
+				if err != nil {
+					log.Warningf(ctx, "unable to get storage engine metrics: %v", err)
+					continue
+				}
+				sgc.setIOMetrics(ioMetrics)
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+func (sgc *StoreGrantCoordinators) setIOMetrics(ioMetrics map[roachpb.StoreID]IOMetrics) {
+	sublevelThreshold := l0SubLevelCountOverloadThreshold.Get(&sgc.settings.SV)
+	fileThreshold := l0FileCountOverloadThreshold.Get(&sgc.settings.SV)
+	var overloaded int64
+	for storeID, m := range ioMetrics {
+		if sgc.getGranter(storeID).setIOMetrics(m, sublevelThreshold, fileThreshold) {
+			overloaded++
+		}
+	}
+	sgc.metrics.OverloadedStores.Update(overloaded)
+}
+
+// storeTokenGranter is the granter of the WorkQueue of a store.
+type storeTokenGranter struct {
+	// mu is shared with the WorkQueue.
+	mu syncutil.Mutex
+	// availableTokens is the number of writes that can still be admitted during
+	// the current interval. It can become negative because of writes that
+	// bypass admission.
+	availableTokens int64
+	// admitted is the number of writes admitted during the current interval.
+	admitted int64
+	queue    *WorkQueue
+}
+
+var _ granter = &storeTokenGranter{}
+
+func (g *storeTokenGranter) tryGetLocked() bool {
+	if g.availableTokens <= 0 {
+		return false
+	}
+	g.tookWithoutPermissionLocked()
+	return true
+}
+
+func (g *storeTokenGranter) returnGrantLocked() {
+	g.availableTokens++
+	g.admitted--
+	g.grantLocked()
+}
+
+func (g *storeTokenGranter) tookWithoutPermissionLocked() {
+	g.availableTokens--
+	g.admitted++
+}
+
+func (g *storeTokenGranter) workDoneLocked() {}
+
+// setIOMetrics hands out the tokens for the next interval, and returns
+// whether the store is overloaded.
+func (g *storeTokenGranter) setIOMetrics(
+	m IOMetrics, sublevelThreshold, fileThreshold int64,
+) (overloaded bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	scale := 1.0
+	if m.L0SublevelCount > sublevelThreshold {
+		scale = float64(sublevelThreshold) / float64(m.L0SublevelCount)
+	}
+	if m.L0FileCount > fileThreshold {
+		scale = math.Min(scale, float64(fileThreshold)/float64(m.L0FileCount))
+	}
+	if scale == 1 {
+		g.availableTokens = unlimitedTokens
+	} else {
+		overloaded = true
+		g.availableTokens = int64(float64(g.admitted) * scale)
+		if g.availableTokens < 1 {
+			g.availableTokens = 1
+		}
+	}
+	g.admitted = 0
+	g.grantLocked()
+	return overloaded
+}
+
+func (g *storeTokenGranter) grantLocked() {
+	for g.queue.hasWaitingRequestsLocked() && g.tryGetLocked() {
+		g.queue.grantLocked()
+	}
+}
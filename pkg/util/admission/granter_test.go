@@ -0,0 +1,120 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantCoordinatorSlots(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	c := NewGrantCoordinator(makeEnabledSettings(), time.Minute)
+	c.mu.Lock()
+	c.totalSlots = 2
+	c.mu.Unlock()
+
+	kvQ, sqlQ := c.KVWorkQueue(), c.SQLWorkQueue()
+	for i := 0; i < 2; i++ {
+		_, err := kvQ.Admit(ctx, WorkInfo{})
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(2), c.metrics.UsedSlots.Value())
+
+	// All the slots are in use, so both KV and SQL work wait.
+	kvAdmitted := make(chan struct{})
+	go func() {
+		if _, err := kvQ.Admit(ctx, WorkInfo{}); err != nil {
+			t.Error(err)
+		}
+		close(kvAdmitted)
+	}()
+	waitForQueueLength(t, kvQ, 1)
+	sqlAdmitted := make(chan struct{})
+	go func() {
+		if _, err := sqlQ.Admit(ctx, WorkInfo{}); err != nil {
+			t.Error(err)
+		}
+		close(sqlAdmitted)
+	}()
+	waitForQueueLength(t, sqlQ, 1)
+
+	// Overload reduces the number of slots, but not below the minimum.
+	c.CPULoad(1)
+	c.CPULoad(1)
+	require.Equal(t, int64(1), c.metrics.TotalSlots.Value())
+
+	// Without overload, the slots grow since they were all in use. The waiting
+	// KV work is admitted, and takes the slot the SQL work was waiting for.
+	c.CPULoad(0.5)
+	require.Equal(t, int64(2), c.metrics.TotalSlots.Value())
+	c.CPULoad(0.5)
+	require.Equal(t, int64(3), c.metrics.TotalSlots.Value())
+	<-kvAdmitted
+	waitForQueueLength(t, sqlQ, 1)
+
+	// Once KV work is done, the SQL work is admitted without taking a slot.
+	kvQ.AdmittedWorkDone()
+	<-sqlAdmitted
+	require.Equal(t, int64(2), c.metrics.UsedSlots.Value())
+	kvQ.AdmittedWorkDone()
+	kvQ.AdmittedWorkDone()
+	require.Equal(t, int64(0), c.metrics.UsedSlots.Value())
+
+	// The slots grow once more since they were exhausted when the KV work was
+	// admitted, but not further unless they're exhausted again.
+	c.CPULoad(0.5)
+	require.Equal(t, int64(4), c.metrics.TotalSlots.Value())
+	c.CPULoad(0.5)
+	require.Equal(t, int64(4), c.metrics.TotalSlots.Value())
+}
+
+func TestStoreGrantCoordinatorsTokens(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	sgc := NewStoreGrantCoordinators(makeEnabledSettings(), time.Minute)
+	q := sgc.GetQueue(roachpb.StoreID(1))
+	require.Same(t, q, sgc.GetQueue(roachpb.StoreID(1)))
+
+	// Writes to a store that isn't overloaded are admitted.
+	for i := 0; i < 100; i++ {
+		_, err := q.Admit(ctx, WorkInfo{})
+		require.NoError(t, err)
+	}
+
+	// With twice as many sublevels as allowed, the store admits half as many
+	// writes as during the previous interval.
+	sgc.setIOMetrics(map[roachpb.StoreID]IOMetrics{1: {L0FileCount: 10, L0SublevelCount: 40}})
+	require.Equal(t, int64(1), sgc.metrics.OverloadedStores.Value())
+	for i := 0; i < 50; i++ {
+		_, err := q.Admit(ctx, WorkInfo{})
+		require.NoError(t, err)
+	}
+	admitted := make(chan struct{})
+	go func() {
+		if _, err := q.Admit(ctx, WorkInfo{}); err != nil {
+			t.Error(err)
+		}
+		close(admitted)
+	}()
+	waitForQueueLength(t, q, 1)
+
+	// Once the store is no longer overloaded, the waiting write is admitted.
+	sgc.setIOMetrics(map[roachpb.StoreID]IOMetrics{1: {L0FileCount: 10, L0SublevelCount: 5}})
+	<-admitted
+	require.Equal(t, int64(0), sgc.metrics.OverloadedStores.Value())
+}
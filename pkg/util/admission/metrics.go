@@ -0,0 +1,110 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// WorkQueueMetrics is a metric.Struct for a WorkQueue. The queues of all the
+// stores of a node share one.
+type WorkQueueMetrics struct {
+	Requested       *metric.Counter
+	Admitted        *metric.Counter
+	Errored         *metric.Counter
+	WaitDurations   *metric.Histogram
+	WaitQueueLength *metric.Gauge
+}
+
+var _ metric.Struct = (*WorkQueueMetrics)(nil)
+
+// MetricStruct implements the metric.Struct interface.
+func (WorkQueueMetrics) MetricStruct() {}
+
+func makeWorkQueueMetrics(kind WorkKind, histogramWindow time.Duration) WorkQueueMetrics {
+	return WorkQueueMetrics{
+		Requested: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.requested.%s", kind),
+			Help:        fmt.Sprintf("Number of %s work items subject to admission control", kind),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		Admitted: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.admitted.%s", kind),
+			Help:        fmt.Sprintf("Number of %s work items admitted", kind),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		Errored: metric.NewCounter(metric.Metadata{
+			Name:        fmt.Sprintf("admission.errored.%s", kind),
+			Help:        fmt.Sprintf("Number of %s work items that gave up waiting for admission", kind),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+		WaitDurations: metric.NewLatency(metric.Metadata{
+			Name:        fmt.Sprintf("admission.wait_durations.%s", kind),
+			Help:        fmt.Sprintf("Wait time of %s work items that waited for admission", kind),
+			Measurement: "Wait time",
+			Unit:        metric.Unit_NANOSECONDS,
+		}, histogramWindow),
+		WaitQueueLength: metric.NewGauge(metric.Metadata{
+			Name:        fmt.Sprintf("admission.wait_queue_length.%s", kind),
+			Help:        fmt.Sprintf("Number of %s work items waiting for admission", kind),
+			Measurement: "Requests",
+			Unit:        metric.Unit_COUNT,
+		}),
+	}
+}
+
+// GranterMetrics is a metric.Struct for the GrantCoordinator.
+type GranterMetrics struct {
+	TotalSlots *metric.Gauge
+	UsedSlots  *metric.Gauge
+}
+
+var _ metric.Struct = (*GranterMetrics)(nil)
+
+// MetricStruct implements the metric.Struct interface.
+func (GranterMetrics) MetricStruct() {}
+
+// StoreGranterMetrics is a metric.Struct for the StoreGrantCoordinators.
+type StoreGranterMetrics struct {
+	OverloadedStores *metric.Gauge
+}
+
+var _ metric.Struct = (*StoreGranterMetrics)(nil)
+
+// MetricStruct implements the metric.Struct interface.
+func (StoreGranterMetrics) MetricStruct() {}
+
+var (
+	metaTotalSlots = metric.Metadata{
+		Name:        "admission.granter.total_slots.kv",
+		Help:        "Number of KV work items that can be executed concurrently",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaUsedSlots = metric.Metadata{
+		Name:        "admission.granter.used_slots.kv",
+		Help:        "Number of KV work items currently executing",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaOverloadedStores = metric.Metadata{
+		Name:        "admission.granter.io_overloaded_stores.kv",
+		Help:        "Number of stores whose writes are throttled because of too many L0 files or sublevels",
+		Measurement: "Stores",
+		Unit:        metric.Unit_COUNT,
+	}
+)
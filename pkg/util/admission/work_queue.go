@@ -0,0 +1,331 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// KVAdmissionControlEnabled controls whether KV work, including writes to
+// overloaded stores, is subject to admission control.
+var KVAdmissionControlEnabled = settings.RegisterBoolSetting(
+	"admission.kv.enabled",
+	"when true, work performed by the KV layer is subject to admission control",
+	false)
+
+// SQLAdmissionControlEnabled controls whether the start of SQL flows is
+// subject to admission control.
+var SQLAdmissionControlEnabled = settings.RegisterBoolSetting(
+	"admission.sql.enabled",
+	"when true, the start of SQL flows is subject to admission control",
+	false)
+
+// WorkPriority represents the priority of work. Waiting work of a higher
+// priority is admitted before waiting work of a lower priority.
+type WorkPriority int8
+
+const (
+	// LowPri is the priority of background work.
+	LowPri WorkPriority = math.MinInt8
+	// NormalPri is the priority of user work.
+	NormalPri WorkPriority = 0
+	// HighPri is the priority of work that the rest of the system depends on,
+	// like work on the system keyspace.
+	HighPri WorkPriority = math.MaxInt8
+)
+
+// WorkKind is the kind of work admitted by a WorkQueue.
+type WorkKind int8
+
+const (
+	// KVWork is the evaluation of a KV batch. It holds a slot from the
+	// GrantCoordinator while it executes, but not while it waits on other
+	// work.
+	KVWork WorkKind = iota
+	// SQLWork is the start of a SQL flow. It's only admitted while KV slots are
+	// available, but doesn't hold one.
+	SQLWork
+	// StoreWork is a KV batch writing to a store. It takes a token from the
+	// store's queue before it's subject to KVWork admission.
+	StoreWork
+)
+
+// String implements the fmt.Stringer interface. It's used in metric names.
+func (k WorkKind) String() string {
+	switch k {
+	case KVWork:
+		return "kv"
+	case SQLWork:
+		return "sql"
+	case StoreWork:
+		return "kv-stores"
+	default:
+		panic(errors.AssertionFailedf("unknown WorkKind %d", k))
+	}
+}
+
+// WorkInfo describes work that is subject to admission control.
+type WorkInfo struct {
+	// Priority is the priority of the work.
+	Priority WorkPriority
+	// CreateTime is the time at which the work was created, in nanoseconds
+	// since the epoch. Waiting work of the same priority is admitted in
+	// CreateTime order, so that work of an old transaction doesn't keep waiting
+	// behind work of newer ones.
+	CreateTime int64
+	// BypassAdmission is set for work that must not wait. It's admitted
+	// immediately, but still takes the resources it uses from other work.
+	BypassAdmission bool
+}
+
+// granter grants the right to perform work to the WorkQueue it's paired with.
+// All its methods are called with the mutex the granter shares with its
+// WorkQueue held.
+type granter interface {
+	// tryGetLocked returns true if work can be admitted, in which case the
+	// resource it needs is taken.
+	tryGetLocked() bool
+	// returnGrantLocked gives back a resource taken by tryGetLocked for work
+	// that didn't end up executing.
+	returnGrantLocked()
+	// tookWithoutPermissionLocked takes a resource for work that bypasses
+	// admission.
+	tookWithoutPermissionLocked()
+	// workDoneLocked is called once admitted work is done.
+	workDoneLocked()
+}
+
+// WorkQueue queues work until its granter admits it.
+type WorkQueue struct {
+	workKind WorkKind
+	granter  granter
+	settings *cluster.Settings
+	enabled  *settings.BoolSetting
+	// mu is shared with the granter, which can then admit waiting work as soon
+	// as the resource it needs is available without races with new work.
+	mu *syncutil.Mutex
+	// waiting is the work waiting for admission, protected by mu.
+	waiting waitingWorkHeap
+	metrics *WorkQueueMetrics
+}
+
+func makeWorkQueue(
+	workKind WorkKind,
+	granter granter,
+	mu *syncutil.Mutex,
+	st *cluster.Settings,
+	enabled *settings.BoolSetting,
+	metrics *WorkQueueMetrics,
+) *WorkQueue {
+	return &WorkQueue{
+		workKind: workKind,
+		granter:  granter,
+		settings: st,
+		enabled:  enabled,
+		mu:       mu,
+		metrics:  metrics,
+	}
+}
+
+// Admit blocks until the work is admitted or the context is canceled. It
+// returns false if admission control is disabled. Otherwise, if no error is
+// returned, the caller must call AdmittedWorkDone once the work is done.
+func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err error) {
+	if !q.enabled.Get(&q.settings.SV) {
+		return false, nil
+	}
+	q.metrics.Requested.Inc(1)
+	q.mu.Lock()
+	if info.BypassAdmission {
+		q.granter.tookWithoutPermissionLocked()
+		q.mu.Unlock()
+		q.metrics.Admitted.Inc(1)
+		return true, nil
+	}
+	// Work can only skip the queue if nothing is waiting in it.
+	if len(q.waiting) == 0 && q.granter.tryGetLocked() {
+		q.mu.Unlock()
+		q.metrics.Admitted.Inc(1)
+		return true, nil
+	}
+	w := &waitingWork{
+		priority:    info.Priority,
+		createTime:  info.CreateTime,
+		enqueueTime: timeutil.Now(),
+		grantCh:     make(chan struct{}, 1),
+	}
+	heap.Push(&q.waiting, w)
+	q.metrics.WaitQueueLength.Inc(1)
+	q.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		q.mu.Lock()
+		if w.heapIndex == -1 {
+			// The work was admitted concurrently with the cancellation.
+			q.granter.returnGrantLocked()
+		} else {
+			heap.Remove(&q.waiting, w.heapIndex)
+			q.metrics.WaitQueueLength.Dec(1)
+		}
+		q.mu.Unlock()
+		q.metrics.Errored.Inc(1)
+		return true, errors.Wrapf(ctx.Err(),
+			"context canceled while waiting in %s admission queue after %s",
+			q.workKind, timeutil.Since(w.enqueueTime))
+	case <-w.grantCh:
+		q.metrics.Admitted.Inc(1)
+		q.metrics.WaitDurations.RecordValue(timeutil.Since(w.enqueueTime).Nanoseconds())
+		return true, nil
+	}
+}
+
+// AdmittedWorkDone is called once work admitted by Admit is done.
+func (q *WorkQueue) AdmittedWorkDone() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.granter.workDoneLocked()
+}
+
+// ContextWithAdmittedWork is called instead of AdmittedWorkDone for work
+// admitted by Admit that can block on other work. The resource held by the
+// work is released whenever the work calls PauseAdmittedWork with the
+// returned context, so that the work it waits on can be admitted. The
+// returned function must be called once the work is done.
+func (q *WorkQueue) ContextWithAdmittedWork(ctx context.Context) (context.Context, func()) {
+	w := &admittedWork{q: q, holding: true}
+	return context.WithValue(ctx, admittedWorkKey{}, w), w.done
+}
+
+// PauseAdmittedWork releases the resource held by the admitted work running
+// in the context, if any, before the work blocks on other work, like latches,
+// locks or replication. It returns a function to call once the work resumes,
+// which takes the resource back without waiting for admission again: the work
+// was already admitted, and making it queue behind newer work could deadlock.
+func PauseAdmittedWork(ctx context.Context) (resume func()) {
+	w, _ := ctx.Value(admittedWorkKey{}).(*admittedWork)
+	if w == nil {
+		return func() {}
+	}
+	w.q.mu.Lock()
+	defer w.q.mu.Unlock()
+	if w.finished || !w.holding {
+		return func() {}
+	}
+	w.holding = false
+	w.q.granter.workDoneLocked()
+	return w.resume
+}
+
+type admittedWorkKey struct{}
+
+// admittedWork tracks whether admitted work currently holds the resource it
+// was admitted with. It's protected by the mutex of its WorkQueue.
+type admittedWork struct {
+	q        *WorkQueue
+	holding  bool
+	finished bool
+}
+
+func (w *admittedWork) resume() {
+	w.q.mu.Lock()
+	defer w.q.mu.Unlock()
+	if w.finished || w.holding {
+		return
+	}
+	w.holding = true
+	w.q.granter.tookWithoutPermissionLocked()
+}
+
+func (w *admittedWork) done() {
+	w.q.mu.Lock()
+	defer w.q.mu.Unlock()
+	if w.holding {
+		w.holding = false
+		w.q.granter.workDoneLocked()
+	}
+	w.finished = true
+}
+
+// hasWaitingRequestsLocked returns whether any work is waiting for admission.
+func (q *WorkQueue) hasWaitingRequestsLocked() bool {
+	return len(q.waiting) > 0
+}
+
+// grantLocked admits the waiting work of the highest priority, whose resource
+// the granter has already taken. It returns false if no work is waiting.
+func (q *WorkQueue) grantLocked() bool {
+	if len(q.waiting) == 0 {
+		return false
+	}
+	w := heap.Pop(&q.waiting).(*waitingWork)
+	q.metrics.WaitQueueLength.Dec(1)
+	w.grantCh <- struct{}{}
+	return true
+}
+
+// waitingWork is work waiting for admission in a WorkQueue.
+type waitingWork struct {
+	priority    WorkPriority
+	createTime  int64
+	enqueueTime time.Time
+	// grantCh is signaled when the work is admitted.
+	grantCh chan struct{}
+	// heapIndex is the index of the work in the waitingWorkHeap, or -1 once it
+	// has been removed from it.
+	heapIndex int
+}
+
+// waitingWorkHeap is a heap of waitingWork, ordered by decreasing priority
+// and then by increasing creation time.
+type waitingWorkHeap []*waitingWork
+
+var _ heap.Interface = (*waitingWorkHeap)(nil)
+
+func (h waitingWorkHeap) Len() int { return len(h) }
+
+func (h waitingWorkHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].createTime < h[j].createTime
+}
+
+func (h waitingWorkHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *waitingWorkHeap) Push(x interface{}) {
+	w := x.(*waitingWork)
+	w.heapIndex = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waitingWorkHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	w.heapIndex = -1
+	return w
+}
@@ -0,0 +1,197 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testGranter is a granter with a fixed number of resources.
+type testGranter struct {
+	available int
+	q         *WorkQueue
+}
+
+func (g *testGranter) tryGetLocked() bool {
+	if g.available <= 0 {
+		return false
+	}
+	g.available--
+	return true
+}
+
+func (g *testGranter) returnGrantLocked() {
+	g.workDoneLocked()
+}
+
+func (g *testGranter) tookWithoutPermissionLocked() {
+	g.available--
+}
+
+func (g *testGranter) workDoneLocked() {
+	g.available++
+	for g.q.hasWaitingRequestsLocked() && g.tryGetLocked() {
+		g.q.grantLocked()
+	}
+}
+
+// makeEnabledSettings returns cluster settings with admission control enabled,
+// which it isn't by default.
+func makeEnabledSettings() *cluster.Settings {
+	st := cluster.MakeTestingClusterSettings()
+	KVAdmissionControlEnabled.Override(&st.SV, true)
+	SQLAdmissionControlEnabled.Override(&st.SV, true)
+	return st
+}
+
+func makeTestWorkQueue(available int) (*WorkQueue, *testGranter) {
+	var mu syncutil.Mutex
+	g := &testGranter{available: available}
+	metrics := makeWorkQueueMetrics(KVWork, time.Minute)
+	q := makeWorkQueue(
+		KVWork, g, &mu, makeEnabledSettings(), KVAdmissionControlEnabled, &metrics)
+	g.q = q
+	return q, g
+}
+
+func waitForQueueLength(t *testing.T, q *WorkQueue, n int) {
+	testutils.SucceedsSoon(t, func() error {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if len(q.waiting) != n {
+			return errors.Errorf("expected %d waiting work items, found %d", n, len(q.waiting))
+		}
+		return nil
+	})
+}
+
+func TestWorkQueueOrdering(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	q, _ := makeTestWorkQueue(1)
+
+	enabled, err := q.Admit(ctx, WorkInfo{Priority: NormalPri})
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	infos := []WorkInfo{
+		{Priority: LowPri, CreateTime: 1},
+		{Priority: NormalPri, CreateTime: 3},
+		{Priority: NormalPri, CreateTime: 2},
+		{Priority: HighPri, CreateTime: 4},
+	}
+	admitted := make(chan WorkInfo, len(infos))
+	for i, info := range infos {
+		info := info
+		go func() {
+			if _, err := q.Admit(ctx, info); err != nil {
+				t.Error(err)
+			}
+			admitted <- info
+		}()
+		waitForQueueLength(t, q, i+1)
+	}
+
+	// Work that bypasses admission doesn't wait, even though no resources are
+	// available.
+	enabled, err = q.Admit(ctx, WorkInfo{Priority: LowPri, BypassAdmission: true})
+	require.NoError(t, err)
+	require.True(t, enabled)
+	q.AdmittedWorkDone()
+
+	expected := []WorkInfo{infos[3], infos[2], infos[1], infos[0]}
+	for _, e := range expected {
+		q.AdmittedWorkDone()
+		require.Equal(t, e, <-admitted)
+	}
+	q.AdmittedWorkDone()
+	require.Equal(t, int64(6), q.metrics.Admitted.Count())
+	require.Equal(t, int64(0), q.metrics.WaitQueueLength.Value())
+}
+
+func TestWorkQueueCancellation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	q, g := makeTestWorkQueue(1)
+
+	_, err := q.Admit(ctx, WorkInfo{})
+	require.NoError(t, err)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := q.Admit(cancelCtx, WorkInfo{})
+		errCh <- err
+	}()
+	waitForQueueLength(t, q, 1)
+	cancel()
+	require.True(t, errors.Is(<-errCh, context.Canceled))
+	waitForQueueLength(t, q, 0)
+	require.Equal(t, int64(1), q.metrics.Errored.Count())
+
+	q.AdmittedWorkDone()
+	require.Equal(t, 1, g.available)
+}
+
+func TestPauseAdmittedWork(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	q, g := makeTestWorkQueue(1)
+
+	_, err := q.Admit(ctx, WorkInfo{})
+	require.NoError(t, err)
+	workCtx, done := q.ContextWithAdmittedWork(ctx)
+
+	// While the admitted work waits, its resource lets other work in.
+	resume := PauseAdmittedWork(workCtx)
+	require.Equal(t, 1, g.available)
+	_, err = q.Admit(ctx, WorkInfo{})
+	require.NoError(t, err)
+
+	// Pausing work that already released its resource is a no-op.
+	PauseAdmittedWork(workCtx)()
+	require.Equal(t, 0, g.available)
+
+	// The work takes its resource back without waiting for admission.
+	resume()
+	require.Equal(t, -1, g.available)
+	done()
+	q.AdmittedWorkDone()
+	require.Equal(t, 1, g.available)
+
+	// Once the work is done, pausing it doesn't affect the resources.
+	PauseAdmittedWork(workCtx)()
+	require.Equal(t, 1, g.available)
+	// Work running outside of admission control isn't affected either.
+	PauseAdmittedWork(ctx)()
+	require.Equal(t, 1, g.available)
+}
+
+func TestWorkQueueDisabled(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	q, _ := makeTestWorkQueue(0)
+	KVAdmissionControlEnabled.Override(&q.settings.SV, false)
+
+	enabled, err := q.Admit(ctx, WorkInfo{})
+	require.NoError(t, err)
+	require.False(t, enabled)
+	require.Equal(t, int64(0), q.metrics.Requested.Count())
+}
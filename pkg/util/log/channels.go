@@ -0,0 +1,178 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Channel identifies a logging channel. Channels group log events by
+// purpose, so that the logging configuration can route them to
+// different sinks (see Config).
+//
+// Every log event belongs to exactly one channel. The events emitted
+// via the package-level functions (Infof, Warningf, etc.) belong to
+// ChannelDev; those emitted via a SecondaryLogger belong to the
+// channel the logger was created with; and those emitted via a
+// ChannelLogger belong to its channel.
+type Channel int32
+
+const (
+	// ChannelDev is the channel of uncategorized log events, which are
+	// mainly of interest to developers.
+	ChannelDev Channel = iota
+	// ChannelOps is the channel of events about the operation of the
+	// process, such as start-up, shutdown and changes to its
+	// configuration or cluster membership.
+	ChannelOps
+	// ChannelHealth is the channel of reports about the health of the
+	// process and of the cluster.
+	ChannelHealth
+	// ChannelStorage is the channel of the events reported by the
+	// storage engines.
+	ChannelStorage
+	// ChannelSessions is the channel of client connection and
+	// authentication events.
+	ChannelSessions
+	// ChannelSQLSchema is the channel of changes to the SQL schema.
+	ChannelSQLSchema
+	// ChannelUserAdmin is the channel of changes to SQL users and roles.
+	ChannelUserAdmin
+	// ChannelPrivileges is the channel of changes to SQL privileges.
+	ChannelPrivileges
+	// ChannelSensitiveAccess is the channel of accesses to audited
+	// tables and of the use of administrative privileges.
+	ChannelSensitiveAccess
+	// ChannelSQLExec is the channel of the SQL statement execution log.
+	ChannelSQLExec
+	// ChannelSQLPerf is the channel of the slow query log.
+	ChannelSQLPerf
+	// ChannelSQLInternalPerf is the channel of the slow query log for
+	// internal queries.
+	ChannelSQLInternalPerf
+
+	numChannels
+)
+
+// channelNames are the names of the channels used in the logging
+// configuration, indexed by Channel.
+var channelNames = [numChannels]string{
+	ChannelDev:             "dev",
+	ChannelOps:             "ops",
+	ChannelHealth:          "health",
+	ChannelStorage:         "storage",
+	ChannelSessions:        "sessions",
+	ChannelSQLSchema:       "sql-schema",
+	ChannelUserAdmin:       "user-admin",
+	ChannelPrivileges:      "privileges",
+	ChannelSensitiveAccess: "sensitive-access",
+	ChannelSQLExec:         "sql-exec",
+	ChannelSQLPerf:         "sql-perf",
+	ChannelSQLInternalPerf: "sql-internal-perf",
+}
+
+// String implements the fmt.Stringer interface.
+func (c Channel) String() string {
+	if c < 0 || c >= numChannels {
+		return "unknown"
+	}
+	return channelNames[c]
+}
+
+// ParseChannel returns the channel with the given name.
+func ParseChannel(s string) (Channel, error) {
+	for c, name := range channelNames {
+		if name == s {
+			return Channel(c), nil
+		}
+	}
+	return 0, errors.Newf("unknown logging channel: %q", s)
+}
+
+// ChannelLogger emits log events on a specific channel. The events
+// go to the main log like those emitted via the package-level
+// functions, and additionally to the sinks which the logging
+// configuration routes the channel to.
+type ChannelLogger struct {
+	ch Channel
+}
+
+// The ChannelLoggers of the channels which are not served by a
+// SecondaryLogger.
+var (
+	Ops        = ChannelLogger{ch: ChannelOps}
+	Health     = ChannelLogger{ch: ChannelHealth}
+	SQLSchema  = ChannelLogger{ch: ChannelSQLSchema}
+	UserAdmin  = ChannelLogger{ch: ChannelUserAdmin}
+	Privileges = ChannelLogger{ch: ChannelPrivileges}
+)
+
+// Channel returns the logger's channel.
+func (l ChannelLogger) Channel() Channel {
+	return l.ch
+}
+
+// Infof logs to the INFO log on the logger's channel.
+// Arguments are handled in the manner of fmt.Printf.
+func (l ChannelLogger) Infof(ctx context.Context, format string, args ...interface{}) {
+	addStructured(ctx, l.ch, Severity_INFO, 1, format, args)
+}
+
+// InfofDepth logs to the INFO log on the logger's channel, offsetting
+// the caller's stack frame by 'depth'.
+func (l ChannelLogger) InfofDepth(
+	ctx context.Context, depth int, format string, args ...interface{},
+) {
+	addStructured(ctx, l.ch, Severity_INFO, depth+1, format, args)
+}
+
+// Warningf logs to the WARNING and INFO logs on the logger's channel.
+// Arguments are handled in the manner of fmt.Printf.
+func (l ChannelLogger) Warningf(ctx context.Context, format string, args ...interface{}) {
+	addStructured(ctx, l.ch, Severity_WARNING, 1, format, args)
+}
+
+// Errorf logs to the ERROR, WARNING, and INFO logs on the logger's
+// channel. Arguments are handled in the manner of fmt.Printf.
+func (l ChannelLogger) Errorf(ctx context.Context, format string, args ...interface{}) {
+	addStructured(ctx, l.ch, Severity_ERROR, 1, format, args)
+}
+
+// Shoutf is like the package-level Shoutf, but logs on the logger's
+// channel.
+func (l ChannelLogger) Shoutf(
+	ctx context.Context, sev Severity, format string, args ...interface{},
+) {
+	shoutfDepth(ctx, l.ch, 1, sev, format, args)
+}
+
+// EventPayload is implemented by the structured events defined in
+// package eventpb.
+type EventPayload interface {
+	// LoggingChannel returns the channel the event is emitted on.
+	LoggingChannel() Channel
+	// AppendJSONFields appends the JSON representation of the event's
+	// fields, without the enclosing braces, to the given buffer. A
+	// comma is emitted before the first field if printComma is set.
+	// The returned boolean indicates whether any field was emitted.
+	AppendJSONFields(printComma bool, b []byte) (bool, []byte)
+}
+
+// StructuredEvent emits a structured event on its logging channel,
+// as a JSON object that can be processed by log collectors.
+func StructuredEvent(ctx context.Context, event EventPayload) {
+	b := append(make([]byte, 0, 256), '{')
+	_, b = event.AppendJSONFields(false, b)
+	b = append(b, '}')
+	addStructured(ctx, event.LoggingChannel(), Severity_INFO, 1, "Structured event: %s", []interface{}{b})
+}
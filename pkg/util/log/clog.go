@@ -46,6 +46,10 @@ type loggingT struct {
 	// facilities.
 	vmoduleConfig vmoduleConfig
 
+	// sinks routes log entries to the sinks configured for their
+	// channel. See ApplyConfig.
+	sinks sinkRouter
+
 	// mu protects the remaining elements of this structure and is
 	// used to synchronize logging.
 	// mu should be held only for short periods of time and
@@ -203,7 +207,7 @@ func SetClusterID(clusterID string) {
 	// new log files, even on the first log file. This ensures that grep
 	// will always find it.
 	ctx := logtags.AddTag(context.Background(), "config", nil)
-	addStructured(ctx, Severity_INFO, 1, "clusterID: %s", []interface{}{clusterID})
+	addStructured(ctx, ChannelOps, Severity_INFO, 1, "clusterID: %s", []interface{}{clusterID})
 
 	// Perform the change proper.
 	logging.mu.Lock()
@@ -316,7 +316,7 @@ func TestV(t *testing.T) {
 	_ = logging.vmoduleConfig.verbosity.Set("2")
 	defer func() { _ = logging.vmoduleConfig.verbosity.Set("0") }()
 	if V(2) {
-		addStructured(context.Background(), Severity_INFO, 1, "", []interface{}{"test"})
+		addStructured(context.Background(), ChannelDev, Severity_INFO, 1, "", []interface{}{"test"})
 	}
 	if !contains("I", t) {
 		t.Errorf("Info has wrong character: %q", contents())
@@ -344,7 +344,7 @@ func TestVmoduleOn(t *testing.T) {
 		t.Error("V enabled for 3")
 	}
 	if V(2) {
-		addStructured(context.Background(), Severity_INFO, 1, "", []interface{}{"test"})
+		addStructured(context.Background(), ChannelDev, Severity_INFO, 1, "", []interface{}{"test"})
 	}
 	if !contains("I", t) {
 		t.Errorf("Info has wrong character: %q", contents())
@@ -366,7 +366,7 @@ func TestVmoduleOff(t *testing.T) {
 		}
 	}
 	if V(2) {
-		addStructured(context.Background(), Severity_INFO, 1, "", []interface{}{"test"})
+		addStructured(context.Background(), ChannelDev, Severity_INFO, 1, "", []interface{}{"test"})
 	}
 	if contents() != "" {
 		t.Error("V logged incorrectly")
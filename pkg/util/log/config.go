@@ -0,0 +1,308 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Config describes the routing of logging channels to sinks. It
+// complements the log files set up by the command-line flags: every
+// log event still goes to the main log or to the files of its
+// secondary logger, and additionally to the sinks which the
+// configuration routes its channel to.
+//
+// A configuration is expressed in YAML, for example:
+//
+//	sinks:
+//	- type: syslog
+//	  channels: [sessions, sensitive-access]
+//	  address: siem.example.com:601
+//	  format: json
+//	- type: http
+//	  channels: [all]
+//	  filter: WARNING
+//	  url: https://collector.example.com/logs
+//	  redact: true
+type Config struct {
+	Sinks []SinkConfig `yaml:"sinks"`
+}
+
+// SinkConfig describes a sink and the channels routed to it.
+type SinkConfig struct {
+	// Type is the type of the sink: "file", "stderr", "syslog",
+	// "fluent" or "http".
+	Type string `yaml:"type"`
+	// Channels lists the names of the channels routed to the sink. The
+	// name "all" stands for all the channels.
+	Channels []string `yaml:"channels,flow"`
+	// Filter is the minimum severity of the entries emitted to the
+	// sink. It defaults to INFO.
+	Filter string `yaml:"filter,omitempty"`
+	// Format is the format of the entries emitted to the sink: "crdb-v1",
+	// the format of the log files, or "json". It defaults to crdb-v1.
+	Format string `yaml:"format,omitempty"`
+	// Redact, if set, removes the sensitive data from the entries
+	// emitted to the sink.
+	Redact bool `yaml:"redact,omitempty"`
+	// Redactable, if set, keeps the redaction markers in the entries
+	// emitted to the sink. Otherwise, they are stripped.
+	Redactable bool `yaml:"redactable,omitempty"`
+
+	// FileGroup is the name of the logging group of a file sink. Its
+	// files are written in the main log directory.
+	FileGroup string `yaml:"file-group,omitempty"`
+	// SyncWrites, if set, makes a file sink sync every write to disk.
+	SyncWrites bool `yaml:"sync-writes,omitempty"`
+
+	// Net is the network used to reach a syslog or Fluentd server: "tcp"
+	// or, for syslog only, "udp". It defaults to tcp.
+	Net string `yaml:"net,omitempty"`
+	// Address is the address of a syslog or Fluentd server.
+	Address string `yaml:"address,omitempty"`
+	// Tag is the application name of the messages emitted to a syslog
+	// server, or the prefix of the tags of the events emitted to a
+	// Fluentd server. It defaults to "cockroach".
+	Tag string `yaml:"tag,omitempty"`
+	// URL is the URL that an HTTP sink posts the entries to.
+	URL string `yaml:"url,omitempty"`
+	// Timeout bounds the network operations of a network sink. It
+	// defaults to 5s.
+	Timeout string `yaml:"timeout,omitempty"`
+	// BufferedEntries is the maximum number of entries queued for a
+	// network sink. Entries are dropped while the queue is full, so
+	// that a slow sink never blocks the logging calls. It defaults to
+	// 10000.
+	BufferedEntries int `yaml:"buffered-entries,omitempty"`
+}
+
+const (
+	defaultSinkTimeout         = 5 * time.Second
+	defaultSinkBufferedEntries = 10000
+	defaultSinkTag             = "cockroach"
+)
+
+// ParseConfig parses and validates a logging configuration expressed
+// in YAML.
+func ParseConfig(s string) (*Config, error) {
+	var c Config
+	if err := yaml.UnmarshalStrict([]byte(s), &c); err != nil {
+		return nil, errors.Wrap(err, "parsing logging configuration")
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Validate checks the configuration.
+func (c *Config) Validate() error {
+	fileGroups := make(map[string]struct{})
+	for i := range c.Sinks {
+		sc := &c.Sinks[i]
+		if err := sc.validate(); err != nil {
+			return errors.Wrapf(err, "sink %d (%s)", i, sc.Type)
+		}
+		if sc.Type == "file" {
+			if _, ok := fileGroups[sc.FileGroup]; ok {
+				return errors.Newf("sink %d (file): duplicate file group %q", i, sc.FileGroup)
+			}
+			fileGroups[sc.FileGroup] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (sc *SinkConfig) validate() error {
+	if len(sc.Channels) == 0 {
+		return errors.New("no channels specified")
+	}
+	if _, err := sc.channels(); err != nil {
+		return err
+	}
+	if sc.Filter != "" {
+		if _, ok := SeverityByName(sc.Filter); !ok {
+			return errors.Newf("unknown severity: %q", sc.Filter)
+		}
+	}
+	if _, ok := logFormats[sc.format()]; !ok {
+		return errors.Newf("unknown format: %q", sc.Format)
+	}
+	if sc.Timeout != "" {
+		if _, err := time.ParseDuration(sc.Timeout); err != nil {
+			return err
+		}
+	}
+	if sc.BufferedEntries < 0 {
+		return errors.Newf("invalid number of buffered entries: %d", sc.BufferedEntries)
+	}
+
+	switch sc.Type {
+	case "file":
+		if sc.FileGroup == "" {
+			return errors.New("file-group is required")
+		}
+		if strings.ContainsAny(sc.FileGroup, `/\.`) {
+			return errors.Newf("invalid file group: %q", sc.FileGroup)
+		}
+	case "stderr":
+		// See SetupRedactionAndStderrRedirects for why redaction markers
+		// cannot be emitted on stderr.
+		if sc.Redactable {
+			return errors.New("redaction markers cannot be emitted on stderr")
+		}
+	case "syslog", "fluent":
+		if sc.Address == "" {
+			return errors.New("address is required")
+		}
+		switch sc.Net {
+		case "", "tcp":
+		case "udp":
+			if sc.Type != "syslog" {
+				return errors.New("UDP is only supported for syslog")
+			}
+		default:
+			return errors.Newf("unknown network: %q", sc.Net)
+		}
+	case "http":
+		if !strings.HasPrefix(sc.URL, "http://") && !strings.HasPrefix(sc.URL, "https://") {
+			return errors.Newf("invalid URL: %q", sc.URL)
+		}
+	default:
+		return errors.Newf("unknown sink type: %q", sc.Type)
+	}
+	return nil
+}
+
+// channels returns the channels routed to the sink.
+func (sc *SinkConfig) channels() ([]Channel, error) {
+	var chans []Channel
+	for _, name := range sc.Channels {
+		if name == "all" {
+			chans = chans[:0]
+			for ch := Channel(0); ch < numChannels; ch++ {
+				chans = append(chans, ch)
+			}
+			return chans, nil
+		}
+		ch, err := ParseChannel(name)
+		if err != nil {
+			return nil, err
+		}
+		chans = append(chans, ch)
+	}
+	return chans, nil
+}
+
+func (sc *SinkConfig) format() string {
+	if sc.Format == "" {
+		return "crdb-v1"
+	}
+	return sc.Format
+}
+
+// newSink creates the sink described by the configuration, which must
+// have been validated.
+func (sc *SinkConfig) newSink() (logSink, error) {
+	timeout := defaultSinkTimeout
+	if sc.Timeout != "" {
+		timeout, _ = time.ParseDuration(sc.Timeout)
+	}
+	maxEntries := sc.BufferedEntries
+	if maxEntries == 0 {
+		maxEntries = defaultSinkBufferedEntries
+	}
+	network := sc.Net
+	if network == "" {
+		network = "tcp"
+	}
+	tag := sc.Tag
+	if tag == "" {
+		tag = defaultSinkTag
+	}
+
+	switch sc.Type {
+	case "file":
+		if !mainLog.logDir.IsSet() {
+			return nil, errors.New("file sinks require a log directory")
+		}
+		return newFileSink(sc.FileGroup, sc.SyncWrites), nil
+	case "stderr":
+		return stderrSink{}, nil
+	case "syslog":
+		return newBufferedSink(newSyslogSink(network, sc.Address, tag, timeout), maxEntries), nil
+	case "fluent":
+		return newBufferedSink(newFluentSink(network, sc.Address, tag, timeout), maxEntries), nil
+	case "http":
+		contentType := "text/plain"
+		if sc.format() == "json" {
+			contentType = "application/json"
+		}
+		return newBufferedSink(newHTTPSink(sc.URL, contentType, timeout), maxEntries), nil
+	default:
+		return nil, errors.AssertionFailedf("unknown sink type: %q", sc.Type)
+	}
+}
+
+// ApplyConfig creates the sinks described by the configuration and
+// starts routing the channels to them. The returned cleanup function
+// stops the routing and closes the sinks; a long-running server
+// process should not need to call it.
+func ApplyConfig(c *Config) (cleanup func(), err error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	var sinks []logSink
+	defer func() {
+		if err != nil {
+			for _, s := range sinks {
+				s.close()
+			}
+		}
+	}()
+
+	routes := &[numChannels][]*sinkInfo{}
+	for i := range c.Sinks {
+		sc := &c.Sinks[i]
+		s, err := sc.newSink()
+		if err != nil {
+			return nil, errors.Wrapf(err, "sink %d (%s)", i, sc.Type)
+		}
+		sinks = append(sinks, s)
+
+		threshold := Severity_INFO
+		if sc.Filter != "" {
+			threshold, _ = SeverityByName(sc.Filter)
+		}
+		si := &sinkInfo{
+			sink:      s,
+			threshold: threshold,
+			format:    logFormats[sc.format()],
+			editor:    getEditor(SelectEditMode(sc.Redact, sc.Redactable)),
+		}
+		chans, _ := sc.channels()
+		for _, ch := range chans {
+			routes[ch] = append(routes[ch], si)
+		}
+	}
+
+	prev := logging.sinks.setRoutes(routes)
+	return func() {
+		logging.sinks.setRoutes(prev)
+		for _, s := range sinks {
+			s.close()
+		}
+	}, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package eventpb defines the catalog of the structured events which
+// are recorded in system.eventlog and emitted to the logging
+// channels. Each event type is a protobuf message embedding
+// CommonEventDetails; the JSON encoders, the logging channels of the
+// events and their reference documentation in
+// docs/generated/eventlog.md are generated from the .proto files by
+// gen.go.
+package eventpb
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+//go:generate go run gen.go
+
+// EventPayload is implemented by all the event types.
+type EventPayload interface {
+	log.EventPayload
+	// CommonDetails gives access to the fields common to all events.
+	CommonDetails() *CommonEventDetails
+}
+
+// CommonDetails implements the EventPayload interface.
+func (m *CommonEventDetails) CommonDetails() *CommonEventDetails { return m }
+
+// EventWithCommonSQLPayload is implemented by the events emitted by
+// SQL statements.
+type EventWithCommonSQLPayload interface {
+	EventPayload
+	// CommonSQLDetails gives access to the fields common to all SQL
+	// events.
+	CommonSQLDetails() *CommonSQLEventDetails
+}
+
+// CommonSQLDetails implements the EventWithCommonSQLPayload interface.
+func (m *CommonSQLEventDetails) CommonSQLDetails() *CommonSQLEventDetails { return m }
+
+// EventWithCommonSchemaChangePayload is implemented by the events
+// emitted by the asynchronous schema changer.
+type EventWithCommonSchemaChangePayload interface {
+	EventPayload
+	// CommonSchemaChangeDetails gives access to the fields common to
+	// all schema changer events.
+	CommonSchemaChangeDetails() *CommonSchemaChangeEventDetails
+}
+
+// CommonSchemaChangeDetails implements the
+// EventWithCommonSchemaChangePayload interface.
+func (m *CommonSchemaChangeEventDetails) CommonSchemaChangeDetails() *CommonSchemaChangeEventDetails {
+	return m
+}
+
+var _ EventWithCommonSQLPayload = (*CreateTable)(nil)
+var _ EventWithCommonSchemaChangePayload = (*FinishSchemaChange)(nil)
+var _ EventPayload = (*NodeJoin)(nil)
+
+// GetEventTypeName returns the name of the type of the given event,
+// as recorded in the eventType column of system.eventlog: the name of
+// the event's message in snake case, for example "create_table".
+func GetEventTypeName(event EventPayload) string {
+	return eventTypeName(reflect.TypeOf(event).Elem().Name())
+}
+
+// eventTypeName converts the name of an event's message to snake case.
+func eventTypeName(msgName string) string {
+	var b strings.Builder
+	for i, r := range msgName {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends the given string to the buffer, escaped
+// for inclusion in a JSON string literal. The quotes are not included.
+func appendJSONString(b []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c < 0x20:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			default:
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			// Invalid UTF-8 is replaced, as encoding/json does.
+			b = append(b, `�`...)
+		} else {
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+	return b
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package eventpb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+func TestEventTypeName(t *testing.T) {
+	testData := []struct {
+		event EventPayload
+		exp   string
+	}{
+		{&CreateTable{}, "create_table"},
+		{&AlterDatabaseAddRegion{}, "alter_database_add_region"},
+		{&FinishSchemaChangeRollback{}, "finish_schema_change_rollback"},
+		{&NodeJoin{}, "node_join"},
+	}
+	for _, tc := range testData {
+		if name := GetEventTypeName(tc.event); name != tc.exp {
+			t.Errorf("expected %q, got %q", tc.exp, name)
+		}
+	}
+}
+
+func TestLoggingChannel(t *testing.T) {
+	testData := []struct {
+		event EventPayload
+		exp   log.Channel
+	}{
+		{&CreateTable{}, log.ChannelSQLSchema},
+		{&GrantPrivilege{}, log.ChannelPrivileges},
+		{&AlterRole{}, log.ChannelUserAdmin},
+		{&NodeDecommissioned{}, log.ChannelOps},
+		{&SetClusterSetting{}, log.ChannelOps},
+	}
+	for _, tc := range testData {
+		if ch := tc.event.LoggingChannel(); ch != tc.exp {
+			t.Errorf("%T: expected %s, got %s", tc.event, tc.exp, ch)
+		}
+	}
+}
+
+// TestAppendJSONFields checks that the generated JSON encoders agree
+// with encoding/json, which the jsontag options of the .proto files
+// configure to use the same keys.
+func TestAppendJSONFields(t *testing.T) {
+	testData := []EventPayload{
+		&DropTable{},
+		&DropTable{
+			CommonEventDetails: CommonEventDetails{Timestamp: 123, EventType: "drop_table"},
+			CommonSQLEventDetails: CommonSQLEventDetails{
+				Statement:    `DROP TABLE "té"`,
+				User:         "root",
+				DescriptorID: 52,
+			},
+			TableName:           "db.public.t\n\"é\"\x01",
+			CascadeDroppedViews: []string{"v1", "v2"},
+		},
+		&CommentOnTable{TableName: "t", NullComment: true},
+		&ReverseSchemaChange{
+			CommonSchemaChangeEventDetails: CommonSchemaChangeEventDetails{
+				InstanceID: 1, DescriptorID: 53, MutationID: 2,
+			},
+			Error:    "boom",
+			SQLSTATE: "XXUUU",
+		},
+		&NodeJoin{CommonNodeEventDetails: CommonNodeEventDetails{NodeID: 3, StartedAt: -1}},
+	}
+	for _, ev := range testData {
+		_, b := ev.AppendJSONFields(false, []byte{'{'})
+		b = append(b, '}')
+
+		var actual, expected map[string]interface{}
+		if err := json.Unmarshal(b, &actual); err != nil {
+			t.Fatalf("%T: invalid JSON %s: %v", ev, b, err)
+		}
+		expectedJSON, err := json.Marshal(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(expectedJSON, &expected); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%T: expected %s, got %s", ev, expectedJSON, b)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build ignore
+
+// gen.go generates, from the .proto files of package eventpb:
+//
+// - json_encode_generated.go, the JSON encoders of the events;
+// - eventlog_channels_generated.go, the logging channels of the events;
+// - docs/generated/eventlog.md, the reference documentation of the
+//   events.
+//
+// It must be run in the package's directory, which "go generate" does.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const docPath = "../../../../docs/generated/eventlog.md"
+
+type fieldInfo struct {
+	// GoName is the name of the Go field, which is also its JSON key.
+	GoName  string
+	Type    string
+	Comment []string
+	// Repeated is set for the repeated fields.
+	Repeated bool
+	// Embedded is set for the embedded common payloads.
+	Embedded bool
+}
+
+type msgInfo struct {
+	GoType    string
+	EventType string
+	Comment   []string
+	Fields    []fieldInfo
+}
+
+type categoryInfo struct {
+	Title   string
+	Channel string
+	// ChannelConst is the name of the log.Channel constant.
+	ChannelConst string
+	Comment      []string
+	Events       []*msgInfo
+}
+
+var (
+	messageRe = regexp.MustCompile(`^message (\w+) \{$`)
+	fieldRe   = regexp.MustCompile(`^(repeated )?(\w+) (\w+) = \d+( \[(.*)\])?;$`)
+	customRe  = regexp.MustCompile(`\(gogoproto\.customname\) = "(\w+)"`)
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "ERROR:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	files, err := filepath.Glob("*.proto")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	msgs := make(map[string]*msgInfo)
+	var allMsgs []*msgInfo
+	var cats []*categoryInfo
+	for _, f := range files {
+		cat, fileMsgs, err := parseFile(f)
+		if err != nil {
+			return err
+		}
+		for _, m := range fileMsgs {
+			msgs[m.GoType] = m
+			allMsgs = append(allMsgs, m)
+		}
+		if cat != nil {
+			cat.Events = fileMsgs
+			cats = append(cats, cat)
+		}
+	}
+	sort.Slice(allMsgs, func(i, j int) bool { return allMsgs[i].GoType < allMsgs[j].GoType })
+	sort.Slice(cats, func(i, j int) bool { return cats[i].Title < cats[j].Title })
+	for _, cat := range cats {
+		sort.Slice(cat.Events, func(i, j int) bool { return cat.Events[i].EventType < cat.Events[j].EventType })
+	}
+
+	if err := writeGo("json_encode_generated.go", jsonTmpl, allMsgs); err != nil {
+		return err
+	}
+	if err := writeGo("eventlog_channels_generated.go", channelsTmpl, cats); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(docPath, genDoc(cats, msgs), 0644)
+}
+
+// parseFile extracts the messages of a .proto file. The category of
+// the file is defined by a detached comment which starts with
+// "Category:" and "Channel:" lines; files without a category, like
+// events.proto, only define the common payloads.
+func parseFile(path string) (*categoryInfo, []*msgInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var cat *categoryInfo
+	var msgs []*msgInfo
+	var curMsg *msgInfo
+	var comment []string
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "//"):
+			comment = append(comment, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+
+		case line == "":
+			if len(comment) > 0 && strings.HasPrefix(comment[0], "Category:") {
+				if len(comment) < 2 || !strings.HasPrefix(comment[1], "Channel:") {
+					return nil, nil, fmt.Errorf("%s:%d: missing channel", path, lineNum)
+				}
+				ch := strings.TrimSpace(strings.TrimPrefix(comment[1], "Channel:"))
+				cat = &categoryInfo{
+					Title:        strings.TrimSpace(strings.TrimPrefix(comment[0], "Category:")),
+					Channel:      ch,
+					ChannelConst: channelConst(ch),
+					Comment:      trimEmpty(comment[2:]),
+				}
+			}
+			comment = nil
+
+		case messageRe.MatchString(line):
+			name := messageRe.FindStringSubmatch(line)[1]
+			curMsg = &msgInfo{GoType: name, EventType: snakeCase(name), Comment: comment}
+			msgs = append(msgs, curMsg)
+			comment = nil
+
+		case line == "}":
+			curMsg = nil
+
+		case curMsg != nil && fieldRe.MatchString(line):
+			m := fieldRe.FindStringSubmatch(line)
+			fi := fieldInfo{
+				GoName:   camelCase(m[3]),
+				Type:     m[2],
+				Comment:  comment,
+				Repeated: m[1] != "",
+				Embedded: strings.Contains(m[5], "(gogoproto.embed) = true"),
+			}
+			if c := customRe.FindStringSubmatch(m[5]); c != nil {
+				fi.GoName = c[1]
+			}
+			if fi.Embedded {
+				fi.GoName = fi.Type
+			}
+			curMsg.Fields = append(curMsg.Fields, fi)
+			comment = nil
+
+		default:
+			comment = nil
+		}
+	}
+	return cat, msgs, scanner.Err()
+}
+
+func trimEmpty(lines []string) []string {
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// snakeCase converts a message name to an event type name. It must
+// agree with eventTypeName in events.go.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// camelCase converts a field name to the name of the Go field
+// generated by protoc-gen-gogo.
+func camelCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+// channelConst converts a channel name, such as "sql-schema", to the
+// name of its log.Channel constant, such as "ChannelSQLSchema".
+func channelConst(name string) string {
+	s := "Channel"
+	for _, part := range strings.Split(name, "-") {
+		if part == "sql" {
+			s += "SQL"
+		} else {
+			s += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return s
+}
+
+func writeGo(path string, tmpl *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting %s: %v\n%s", path, err, buf.Bytes())
+	}
+	return ioutil.WriteFile(path, src, 0644)
+}
+
+var jsonTmpl = template.Must(template.New("json").Parse(`// Code generated by gen.go. DO NOT EDIT.
+
+package eventpb
+
+import "strconv"
+
+{{range .}}
+// AppendJSONFields implements the EventPayload interface.
+func (m *{{.GoType}}) AppendJSONFields(printComma bool, b []byte) (bool, []byte) {
+{{- range .Fields}}
+{{- if .Embedded}}
+	printComma, b = m.{{.GoName}}.AppendJSONFields(printComma, b)
+{{- else if .Repeated}}
+	if len(m.{{.GoName}}) > 0 {
+		if printComma {
+			b = append(b, ',')
+		}
+		printComma = true
+		b = append(b, "\"{{.GoName}}\":["...)
+		for i, v := range m.{{.GoName}} {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, '"')
+			b = appendJSONString(b, v)
+			b = append(b, '"')
+		}
+		b = append(b, ']')
+	}
+{{- else if eq .Type "string"}}
+	if m.{{.GoName}} != "" {
+		if printComma {
+			b = append(b, ',')
+		}
+		printComma = true
+		b = append(b, "\"{{.GoName}}\":\""...)
+		b = appendJSONString(b, m.{{.GoName}})
+		b = append(b, '"')
+	}
+{{- else if eq .Type "bool"}}
+	if m.{{.GoName}} {
+		if printComma {
+			b = append(b, ',')
+		}
+		printComma = true
+		b = append(b, "\"{{.GoName}}\":true"...)
+	}
+{{- else if or (eq .Type "int32") (eq .Type "int64")}}
+	if m.{{.GoName}} != 0 {
+		if printComma {
+			b = append(b, ',')
+		}
+		printComma = true
+		b = append(b, "\"{{.GoName}}\":"...)
+		b = strconv.AppendInt(b, int64(m.{{.GoName}}), 10)
+	}
+{{- else if or (eq .Type "uint32") (eq .Type "uint64")}}
+	if m.{{.GoName}} != 0 {
+		if printComma {
+			b = append(b, ',')
+		}
+		printComma = true
+		b = append(b, "\"{{.GoName}}\":"...)
+		b = strconv.AppendUint(b, uint64(m.{{.GoName}}), 10)
+	}
+{{- else}}
+	{{.GoName}} has unsupported type {{.Type}}
+{{- end}}
+{{- end}}
+	return printComma, b
+}
+{{end}}
+`))
+
+var channelsTmpl = template.Must(template.New("channels").Parse(`// Code generated by gen.go. DO NOT EDIT.
+
+package eventpb
+
+import "github.com/cockroachdb/cockroach/pkg/util/log"
+
+{{range .}}{{$cat := .}}{{range .Events}}
+// LoggingChannel implements the EventPayload interface.
+func (m *{{.GoType}}) LoggingChannel() log.Channel { return log.{{$cat.ChannelConst}} }
+{{end}}{{end}}
+`))
+
+const docHeader = `Certain notable events are reported using a structured format.
+Commonly, these notable events are also copied to the table
+` + "`system.eventlog`" + `.
+
+Additionally, notable events are copied to specific external logging
+channels in log messages, where they can be collected for further
+processing.
+
+The sections below document the possible notable event types
+in this version of CockroachDB. For each event type, a table
+documents the possible fields. A field may be omitted from
+an event if its value is empty or zero.
+
+A field is also documented as "common" if it is shared by
+all the events of its type through a common payload.
+`
+
+// genDoc renders the reference documentation of the events.
+func genDoc(cats []*categoryInfo, msgs map[string]*msgInfo) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(docHeader)
+	fieldTable := func(title string, fields []fieldInfo) {
+		if len(fields) == 0 {
+			return
+		}
+		fmt.Fprintf(&buf, "\n%s| Field | Description |\n|--|--|\n", title)
+		for _, f := range fields {
+			fmt.Fprintf(&buf, "| `%s` | %s |\n", f.GoName, strings.Join(f.Comment, " "))
+		}
+	}
+	for _, cat := range cats {
+		fmt.Fprintf(&buf, "\n## %s\n\n%s\n\nEvents in this category are logged to channel `%s`.\n",
+			cat.Title, paragraphs(cat.Comment), cat.Channel)
+		for _, ev := range cat.Events {
+			fmt.Fprintf(&buf, "\n### `%s`\n\n%s\n", ev.EventType, paragraphs(ev.Comment))
+			var own, common []fieldInfo
+			for _, f := range ev.Fields {
+				if f.Embedded {
+					common = append(common, msgs[f.Type].Fields...)
+				} else {
+					own = append(own, f)
+				}
+			}
+			fieldTable("", own)
+			fieldTable("#### Common fields\n\n", common)
+		}
+	}
+	return buf.Bytes()
+}
+
+// paragraphs joins comment lines into paragraphs separated by blank
+// lines.
+func paragraphs(lines []string) string {
+	var buf strings.Builder
+	for i, l := range lines {
+		switch {
+		case l == "":
+			buf.WriteString("\n\n")
+		case i > 0 && lines[i-1] != "":
+			buf.WriteString(" " + l)
+		default:
+			buf.WriteString(l)
+		}
+	}
+	return buf.String()
+}
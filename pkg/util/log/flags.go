@@ -93,7 +93,7 @@ func SetupRedactionAndStderrRedirects() (cleanupForTestingOnly func(), err error
 		// logger when the remainder of the process stops. See the
 		// discussion on cancel at the top of the function.
 		ctx, cancel := context.WithCancel(context.Background())
-		secLogger := NewSecondaryLogger(ctx, &mainLog.logDir, "stderr",
+		secLogger := NewSecondaryLogger(ctx, &mainLog.logDir, "stderr", ChannelDev,
 			true /* enableGC */, true /* forceSyncWrites */, false /* enableMsgCount */)
 
 		// Stderr capture produces unsafe strings. This logger
@@ -41,7 +41,7 @@ func FatalOnPanic() {
 // dictionary for separate binary-log output.
 func logDepth(ctx context.Context, depth int, sev Severity, format string, args []interface{}) {
 	// TODO(tschottdorf): logging hooks should have their entry point here.
-	addStructured(ctx, sev, depth+1, format, args)
+	addStructured(ctx, ChannelDev, sev, depth+1, format, args)
 }
 
 // Shout logs to the specified severity's log, and also to the real
@@ -52,6 +52,14 @@ func Shout(ctx context.Context, sev Severity, msg string) {
 
 // Shoutf is like Shout but uses formatting.
 func Shoutf(ctx context.Context, sev Severity, format string, args ...interface{}) {
+	shoutfDepth(ctx, ChannelDev, 1, sev, format, args)
+}
+
+// shoutfDepth implements Shoutf for the given channel, offsetting the
+// caller's stack frame by 'depth'.
+func shoutfDepth(
+	ctx context.Context, ch Channel, depth int, sev Severity, format string, args []interface{},
+) {
 	if sev == Severity_FATAL {
 		// Fatal error handling later already tries to exit even if I/O should
 		// block, but crash reporting might also be in the way.
@@ -69,7 +77,7 @@ func Shoutf(ctx context.Context, sev Severity, format string, args ...interface{
 				FormatWithContextTags(ctx, format, args...),
 				"\n", "\n* ", -1))
 	}
-	logDepth(ctx, 1, sev, format, args)
+	addStructured(ctx, ch, sev, depth+1, format, args)
 }
 
 // Infof logs to the INFO log.
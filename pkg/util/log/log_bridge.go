@@ -94,6 +94,7 @@ func (lb logBridge) Write(b []byte) (n int, err error) {
 			entry.Line = 1
 		}
 	}
+	logging.sinks.output(ChannelDev, entry)
 	mainLog.outputLogEntry(entry)
 	return len(b), nil
 }
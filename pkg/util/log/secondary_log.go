@@ -21,6 +21,7 @@ import (
 // facility.
 type SecondaryLogger struct {
 	logger          loggerT
+	channel         Channel
 	forceSyncWrites bool
 }
 
@@ -37,6 +38,10 @@ var secondaryLogRegistry struct {
 // the global logger's own dirName is used; or non-nil and non-empty,
 // in which case it specifies the directory for that new logger.
 //
+// The logger's events belong to the given channel, which the logging
+// configuration can route to other sinks in addition to the logger's
+// files.
+//
 // The logger's GC daemon stops when the provided context is canceled.
 //
 // The caller is responsible for ensuring the Close() method is
@@ -45,6 +50,7 @@ func NewSecondaryLogger(
 	ctx context.Context,
 	dirName *DirName,
 	fileNamePrefix string,
+	channel Channel,
 	enableGc bool,
 	forceSyncWrites bool,
 	enableMsgCount bool,
@@ -73,6 +79,7 @@ func NewSecondaryLogger(
 			logCounter:      EntryCounter{EnableMsgCount: enableMsgCount},
 			gcNotify:        make(chan struct{}, 1),
 		},
+		channel:         channel,
 		forceSyncWrites: forceSyncWrites,
 	}
 	l.logger.redactableLogs.Set(mainLog.redactableLogs.Get())
@@ -112,6 +119,7 @@ func (l *SecondaryLogger) output(
 ) {
 	entry := MakeEntry(
 		ctx, sev, &l.logger.logCounter, depth+1, l.logger.redactableLogs.Get(), format, args...)
+	logging.sinks.output(l.channel, entry)
 	l.logger.outputLogEntry(entry)
 }
 
@@ -34,7 +34,7 @@ func TestSecondaryLog(t *testing.T) {
 	defer cancel()
 
 	// Make a new logger, in the same directory.
-	l := NewSecondaryLogger(ctx, &mainLog.logDir, "woo", true, false, true)
+	l := NewSecondaryLogger(ctx, &mainLog.logDir, "woo", ChannelDev, true, false, true)
 	defer l.Close()
 
 	// Interleave some messages.
@@ -92,7 +92,7 @@ func TestRedirectStderrWithSecondaryLoggersActive(t *testing.T) {
 	// Now create a secondary logger in the same directory.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	l := NewSecondaryLogger(ctx, &mainLog.logDir, "woo", true, false, true)
+	l := NewSecondaryLogger(ctx, &mainLog.logDir, "woo", ChannelDev, true, false, true)
 	defer l.Close()
 
 	// Log something on the secondary logger.
@@ -130,7 +130,7 @@ func TestListLogFilesIncludeSecondaryLogs(t *testing.T) {
 	defer cancel()
 
 	// Make a new logger, in the same directory.
-	l := NewSecondaryLogger(ctx, &mainLog.logDir, "woo", true, false, true)
+	l := NewSecondaryLogger(ctx, &mainLog.logDir, "woo", ChannelDev, true, false, true)
 	defer l.Close()
 
 	// Emit some logging and ensure the files gets created.
@@ -0,0 +1,115 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// bufferedSink wraps a sink which may be slow or unavailable, such as
+// a network sink, so that logging calls never block on it. Entries are
+// queued for a background goroutine that emits them to the wrapped
+// sink. When the queue is full, new entries are dropped rather than
+// waited for; the number of dropped entries is reported as an error
+// once the queue has room again.
+type bufferedSink struct {
+	child logSink
+	queue chan bufferedItem
+	// dropped counts the entries dropped since the last report.
+	dropped int64
+	// stop is closed to stop the background goroutine, which closes
+	// stopped when it has.
+	stop, stopped chan struct{}
+}
+
+// bufferedItem is an entry queued in a bufferedSink. Items without
+// data are flush requests, whose done channel is closed when all the
+// items queued before them have been emitted.
+type bufferedItem struct {
+	e    sinkEntry
+	done chan struct{}
+}
+
+var _ logSink = (*bufferedSink)(nil)
+
+// newBufferedSink wraps the given sink in a bufferedSink which queues
+// up to maxEntries entries.
+func newBufferedSink(child logSink, maxEntries int) *bufferedSink {
+	s := &bufferedSink{
+		child:   child,
+		queue:   make(chan bufferedItem, maxEntries),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// output queues the entry. It never blocks and never fails.
+func (s *bufferedSink) output(e sinkEntry) error {
+	e.data = append([]byte(nil), e.data...)
+	select {
+	case s.queue <- bufferedItem{e: e}:
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+	return nil
+}
+
+// flush waits until the entries queued so far have been emitted, or
+// until the timeout expires.
+func (s *bufferedSink) flush(timeout time.Duration) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	done := make(chan struct{})
+	select {
+	case s.queue <- bufferedItem{done: done}:
+	case <-t.C:
+		return
+	}
+	select {
+	case <-done:
+	case <-t.C:
+	}
+}
+
+// close stops the background goroutine, discarding the entries still
+// queued, and closes the wrapped sink.
+func (s *bufferedSink) close() {
+	close(s.stop)
+	<-s.stopped
+	s.child.close()
+}
+
+func (s *bufferedSink) run() {
+	defer close(s.stopped)
+	for {
+		select {
+		case item := <-s.queue:
+			if item.done != nil {
+				s.child.flush(0)
+				close(item.done)
+				continue
+			}
+			if err := s.child.output(item.e); err != nil {
+				reportSinkError(err)
+			}
+			if n := atomic.SwapInt64(&s.dropped, 0); n > 0 {
+				reportSinkError(errors.Newf("%d log entries dropped while the sink was falling behind", n))
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The network sinks below are not safe for concurrent use. They are
+// always wrapped in a bufferedSink, whose background goroutine is
+// their only user.
+
+// netConn is a connection to a network sink's server which is
+// (re-)established on demand.
+type netConn struct {
+	network, addr string
+	timeout       time.Duration
+	conn          net.Conn
+}
+
+// write writes the given data, connecting first if necessary. The
+// connection is dropped on error, so that the next write reconnects.
+func (c *netConn) write(data []byte) error {
+	if c.conn == nil {
+		conn, err := net.DialTimeout(c.network, c.addr, c.timeout)
+		if err != nil {
+			return err
+		}
+		c.conn = conn
+	}
+	if err := c.conn.SetWriteDeadline(timeutil.Now().Add(c.timeout)); err != nil {
+		c.close()
+		return err
+	}
+	if _, err := c.conn.Write(data); err != nil {
+		c.close()
+		return err
+	}
+	return nil
+}
+
+func (c *netConn) close() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// syslogSink emits log entries to a syslog server, in the format
+// described by RFC 5424. Over TCP, messages are framed by octet
+// counting as described by RFC 6587.
+type syslogSink struct {
+	conn     netConn
+	hostname string
+	appName  string
+	procID   int
+}
+
+var _ logSink = (*syslogSink)(nil)
+
+// syslogFacility is the facility of the messages emitted to syslog
+// servers: local0.
+const syslogFacility = 16
+
+func newSyslogSink(network, addr, appName string, timeout time.Duration) *syslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &syslogSink{
+		conn:     netConn{network: network, addr: addr, timeout: timeout},
+		hostname: hostname,
+		appName:  appName,
+		procID:   os.Getpid(),
+	}
+}
+
+// syslogSeverity maps the severities to syslog severities.
+func syslogSeverity(sev Severity) int {
+	switch sev {
+	case Severity_WARNING:
+		return 4
+	case Severity_ERROR:
+		return 3
+	case Severity_FATAL:
+		return 2
+	default:
+		return 6
+	}
+}
+
+func (s *syslogSink) output(e sinkEntry) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "<%d>1 %s %s %s %d %s - ",
+		syslogFacility*8+syslogSeverity(e.sev),
+		timeutil.Unix(0, e.time).UTC().Format(time.RFC3339Nano),
+		s.hostname, s.appName, s.procID, e.ch)
+	msg.Write(bytes.TrimRight(e.data, "\n"))
+	if s.conn.network == "udp" {
+		return s.conn.write(msg.Bytes())
+	}
+	framed := make([]byte, 0, msg.Len()+8)
+	framed = append(framed, fmt.Sprintf("%d ", msg.Len())...)
+	framed = append(framed, msg.Bytes()...)
+	return s.conn.write(framed)
+}
+
+func (s *syslogSink) flush(time.Duration) {}
+
+func (s *syslogSink) close() { s.conn.close() }
+
+// fluentSink emits log entries to a Fluentd server using the Message
+// mode of its Forward protocol. The tag of each event is the
+// configured tag prefix followed by the entry's channel, and its
+// record holds the channel, the severity and the formatted entry.
+type fluentSink struct {
+	conn      netConn
+	tagPrefix string
+	buf       []byte
+}
+
+var _ logSink = (*fluentSink)(nil)
+
+func newFluentSink(network, addr, tagPrefix string, timeout time.Duration) *fluentSink {
+	return &fluentSink{
+		conn:      netConn{network: network, addr: addr, timeout: timeout},
+		tagPrefix: tagPrefix,
+	}
+}
+
+func (s *fluentSink) output(e sinkEntry) error {
+	b := s.buf[:0]
+	b = append(b, 0x93) // [tag, time, record]
+	b = appendMsgpackString(b, s.tagPrefix+"."+e.ch.String())
+	b = appendMsgpackUint(b, uint64(e.time/1e9))
+	b = append(b, 0x83) // {channel, severity, message}
+	b = appendMsgpackString(b, "channel")
+	b = appendMsgpackString(b, e.ch.String())
+	b = appendMsgpackString(b, "severity")
+	b = appendMsgpackString(b, e.sev.String())
+	b = appendMsgpackString(b, "message")
+	b = appendMsgpackString(b, string(bytes.TrimRight(e.data, "\n")))
+	s.buf = b
+	return s.conn.write(b)
+}
+
+func (s *fluentSink) flush(time.Duration) {}
+
+func (s *fluentSink) close() { s.conn.close() }
+
+// appendMsgpackString appends the MessagePack encoding of a string.
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n < 1<<8:
+		b = append(b, 0xd9, byte(n))
+	case n < 1<<16:
+		b = append(b, 0xda, 0, 0)
+		binary.BigEndian.PutUint16(b[len(b)-2:], uint16(n))
+	default:
+		b = append(b, 0xdb, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(b[len(b)-4:], uint32(n))
+	}
+	return append(b, s...)
+}
+
+// appendMsgpackUint appends the MessagePack encoding of an unsigned
+// integer.
+func appendMsgpackUint(b []byte, v uint64) []byte {
+	if v < 128 {
+		return append(b, byte(v))
+	}
+	b = append(b, 0xcf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(b[len(b)-8:], v)
+	return b
+}
+
+// httpSink emits each log entry as the body of an HTTP POST request.
+type httpSink struct {
+	client      http.Client
+	url         string
+	contentType string
+}
+
+var _ logSink = (*httpSink)(nil)
+
+func newHTTPSink(url, contentType string, timeout time.Duration) *httpSink {
+	return &httpSink{
+		client:      http.Client{Timeout: timeout},
+		url:         url,
+		contentType: contentType,
+	}
+}
+
+func (s *httpSink) output(e sinkEntry) error {
+	resp, err := s.client.Post(s.url, s.contentType, bytes.NewReader(e.data))
+	if err != nil {
+		return err
+	}
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Newf("POST %s: %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+func (s *httpSink) flush(time.Duration) {}
+
+func (s *httpSink) close() { s.client.CloseIdleConnections() }
@@ -0,0 +1,237 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// logSink is the interface implemented by the outputs which the
+// logging configuration can route channels to, in addition to the
+// main and secondary log files.
+type logSink interface {
+	// output emits a log entry formatted for the sink.
+	output(e sinkEntry) error
+	// flush waits until the entries emitted so far have reached their
+	// destination, or until the timeout expires.
+	flush(timeout time.Duration)
+	// close releases the sink's resources.
+	close()
+}
+
+// sinkEntry is a log entry formatted for a sink. Sinks which need to
+// retain the data after output returns must copy it.
+type sinkEntry struct {
+	ch   Channel
+	sev  Severity
+	time int64
+	data []byte
+}
+
+// sinkInfo describes a sink and the processing applied to the log
+// entries of the channels routed to it.
+type sinkInfo struct {
+	sink logSink
+	// threshold is the minimum severity of the entries passed to the
+	// sink.
+	threshold Severity
+	// format renders the entries for the sink.
+	format logFormat
+	// editor strips or redacts the sensitive data of the entries.
+	editor redactEditor
+}
+
+// sinkRouter routes log entries to the sinks configured for their
+// channel.
+type sinkRouter struct {
+	// routes holds a *[numChannels][]*sinkInfo. It is replaced
+	// wholesale when the configuration changes, so that logging calls
+	// need not synchronize with each other to read it.
+	routes atomic.Value
+	// errEvery limits the rate at which sink errors are reported to
+	// the main log.
+	errEvery EveryN
+}
+
+// fatalFlushTimeout bounds the time that a fatal error waits for the
+// sinks to emit the entries that preceded it.
+const fatalFlushTimeout = 5 * time.Second
+
+// output emits the given entry to the sinks configured for the given
+// channel. Sink errors are reported to the main log but otherwise
+// ignored, as they shouldn't prevent the entry from reaching the log
+// files.
+func (r *sinkRouter) output(ch Channel, entry Entry) {
+	routes, _ := r.routes.Load().(*[numChannels][]*sinkInfo)
+	if routes == nil || ch < 0 || ch >= numChannels {
+		return
+	}
+	for _, si := range routes[ch] {
+		if entry.Severity < si.threshold {
+			continue
+		}
+		edited := entry
+		edited.Tags = string(si.editor(redactablePackage{
+			msg: []byte(entry.Tags), redactable: entry.Redactable}).msg)
+		msg := si.editor(redactablePackage{msg: []byte(entry.Message), redactable: entry.Redactable})
+		edited.Message, edited.Redactable = string(msg.msg), msg.redactable
+
+		buf := si.format(ch, edited)
+		err := si.sink.output(sinkEntry{ch: ch, sev: entry.Severity, time: entry.Time, data: buf.Bytes()})
+		putBuffer(buf)
+		if err != nil {
+			reportSinkError(err)
+		}
+		if entry.Severity == Severity_FATAL {
+			// The process is about to exit. Give the entries, including
+			// this one, a chance to reach their destination.
+			si.sink.flush(fatalFlushTimeout)
+		}
+	}
+}
+
+// setRoutes installs new routes, returning the previous ones.
+func (r *sinkRouter) setRoutes(routes *[numChannels][]*sinkInfo) *[numChannels][]*sinkInfo {
+	prev, _ := r.routes.Load().(*[numChannels][]*sinkInfo)
+	r.routes.Store(routes)
+	return prev
+}
+
+func init() {
+	logging.sinks.errEvery = Every(time.Minute)
+	logging.sinks.routes.Store((*[numChannels][]*sinkInfo)(nil))
+}
+
+// reportSinkError reports an error emitted by a sink to the main log.
+// It bypasses the sinks, which may well be the source of the error.
+func reportSinkError(err error) {
+	if !logging.sinks.errEvery.ShouldLog() {
+		return
+	}
+	entry := MakeEntry(context.Background(), Severity_WARNING, nil /* lc */, 1, /* depth */
+		false /* redactable */, "error emitting log entry to sink: %v", err)
+	mainLog.outputLogEntry(entry)
+}
+
+// logFormat renders a log entry for a sink into a newly allocated
+// *buffer. The caller is responsible for calling putBuffer()
+// afterwards.
+type logFormat func(ch Channel, entry Entry) *buffer
+
+// logFormats are the formats that sinks can be configured with, keyed
+// by their name in the configuration.
+var logFormats = map[string]logFormat{
+	"crdb-v1": formatCrdbV1,
+	"json":    formatJSON,
+}
+
+// formatCrdbV1 renders an entry in the format of the log files.
+func formatCrdbV1(_ Channel, entry Entry) *buffer {
+	return logging.formatLogEntry(entry, nil /* stacks */, nil /* cp */)
+}
+
+// jsonEntry is the JSON representation of a log entry.
+type jsonEntry struct {
+	Channel    string `json:"channel"`
+	Timestamp  string `json:"timestamp"`
+	Severity   string `json:"severity"`
+	Goroutine  int64  `json:"goroutine,omitempty"`
+	File       string `json:"file"`
+	Line       int64  `json:"line"`
+	Redactable int    `json:"redactable"`
+	Tags       string `json:"tags,omitempty"`
+	Counter    uint64 `json:"counter,omitempty"`
+	Message    string `json:"message"`
+}
+
+// formatJSON renders an entry as a single-line JSON object.
+func formatJSON(ch Channel, entry Entry) *buffer {
+	je := jsonEntry{
+		Channel:   ch.String(),
+		Timestamp: fmt.Sprintf("%d.%09d", entry.Time/1e9, entry.Time%1e9),
+		Severity:  entry.Severity.String(),
+		Goroutine: entry.Goroutine,
+		File:      entry.File,
+		Line:      entry.Line,
+		Tags:      entry.Tags,
+		Counter:   entry.Counter,
+		Message:   entry.Message,
+	}
+	if entry.Redactable {
+		je.Redactable = 1
+	}
+	buf := getBuffer()
+	b, err := json.Marshal(&je)
+	if err != nil {
+		// The entry consists of strings and numbers only.
+		panic(err)
+	}
+	buf.Write(b)
+	buf.WriteByte('\n')
+	return buf
+}
+
+// stderrSink emits log entries to the process' external standard
+// error stream (OrigStderr).
+type stderrSink struct{}
+
+var _ logSink = stderrSink{}
+
+func (stderrSink) output(e sinkEntry) error {
+	_, err := OrigStderr.Write(e.data)
+	return err
+}
+
+func (stderrSink) flush(time.Duration) {}
+
+func (stderrSink) close() {}
+
+// fileSink emits log entries to the files of a logging group of its
+// own, in the main log directory. The files are flushed and garbage
+// collected like those of the secondary loggers.
+type fileSink struct {
+	logger *SecondaryLogger
+	// stopGC stops the GC daemon of the logger's files.
+	stopGC func()
+}
+
+var _ logSink = (*fileSink)(nil)
+
+func newFileSink(group string, syncWrites bool) *fileSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := NewSecondaryLogger(ctx, nil /* dirName */, group, ChannelDev,
+		true /* enableGc */, syncWrites, false /* enableMsgCount */)
+	return &fileSink{logger: l, stopGC: cancel}
+}
+
+func (s *fileSink) output(e sinkEntry) error {
+	l := &s.logger.logger
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.ensureFileLocked(); err != nil {
+		return err
+	}
+	return l.writeToFileLocked(e.data)
+}
+
+func (s *fileSink) flush(time.Duration) {
+	s.logger.logger.lockAndFlushAndSync(true /* doSync */)
+}
+
+func (s *fileSink) close() {
+	s.flush(0)
+	s.stopGC()
+	s.logger.Close()
+}
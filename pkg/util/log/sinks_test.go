@@ -0,0 +1,235 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+func TestParseConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		config string
+		expErr string
+	}{
+		{`sinks: [{type: stderr, channels: [ops]}]`, ``},
+		{`sinks: [{type: http, channels: [all], url: "http://x/y", format: json}]`, ``},
+		{`sinks: [{type: syslog, channels: [sessions], address: "x:514", net: udp}]`, ``},
+		{`sinks: [{type: stderr, channels: []}]`, `no channels specified`},
+		{`sinks: [{type: stderr, channels: [foo]}]`, `unknown channel`},
+		{`sinks: [{type: stderr, channels: [ops], filter: LOUD}]`, `unknown severity`},
+		{`sinks: [{type: stderr, channels: [ops], format: xml}]`, `unknown format`},
+		{`sinks: [{type: stderr, channels: [ops], redactable: true}]`, `redaction markers`},
+		{`sinks: [{type: fluent, channels: [ops], address: "x:24224", net: udp}]`, `UDP is only supported`},
+		{`sinks: [{type: syslog, channels: [ops]}]`, `address is required`},
+		{`sinks: [{type: http, channels: [ops], url: "ftp://x"}]`, `invalid URL`},
+		{`sinks: [{type: file, channels: [ops], file-group: "../x"}]`, `invalid file group`},
+		{`sinks: [{type: file, channels: [ops], file-group: a}, {type: file, channels: [health], file-group: a}]`,
+			`duplicate file group`},
+		{`sinks: [{type: kafka, channels: [ops]}]`, `unknown sink type`},
+		{`sinks: [{type: stderr, channels: [ops], colour: red}]`, `not found`},
+	}
+	for _, tc := range testData {
+		t.Run(tc.config, func(t *testing.T) {
+			_, err := ParseConfig(tc.config)
+			if tc.expErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !regexp.MustCompile(tc.expErr).MatchString(err.Error()) {
+				t.Fatalf("expected error %q, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	entry := Entry{
+		Severity: Severity_WARNING,
+		Time:     1234567890123456789,
+		File:     "foo.go",
+		Line:     42,
+		Tags:     "n1",
+		Message:  "hello\nworld",
+	}
+	buf := formatJSON(ChannelHealth, entry)
+	defer putBuffer(buf)
+
+	if !strings.HasSuffix(buf.String(), "}\n") || strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("expected single-line JSON object, got %q", buf.String())
+	}
+	var je jsonEntry
+	if err := json.Unmarshal(buf.Bytes(), &je); err != nil {
+		t.Fatal(err)
+	}
+	exp := jsonEntry{
+		Channel:   "health",
+		Timestamp: "1234567890.123456789",
+		Severity:  "WARNING",
+		File:      "foo.go",
+		Line:      42,
+		Tags:      "n1",
+		Message:   "hello\nworld",
+	}
+	if je != exp {
+		t.Fatalf("expected %+v, got %+v", exp, je)
+	}
+}
+
+func TestAppendMsgpackString(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		n      int
+		header []byte
+	}{
+		{0, []byte{0xa0}},
+		{31, []byte{0xbf}},
+		{32, []byte{0xd9, 32}},
+		{255, []byte{0xd9, 255}},
+		{256, []byte{0xda, 1, 0}},
+		{70000, []byte{0xdb, 0, 1, 0x11, 0x70}},
+	}
+	for _, tc := range testData {
+		s := strings.Repeat("x", tc.n)
+		b := appendMsgpackString(nil, s)
+		if string(b[:len(tc.header)]) != string(tc.header) || string(b[len(tc.header):]) != s {
+			t.Errorf("%d: unexpected encoding, header % x", tc.n, b[:len(tc.header)])
+		}
+	}
+}
+
+// blockingSink is a logSink whose output blocks until it is released.
+type blockingSink struct {
+	release chan struct{}
+	mu      struct {
+		syncutil.Mutex
+		entries []string
+	}
+}
+
+func (s *blockingSink) output(e sinkEntry) error {
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.entries = append(s.mu.entries, string(e.data))
+	return nil
+}
+
+func (s *blockingSink) flush(time.Duration) {}
+
+func (s *blockingSink) close() {}
+
+// TestBufferedSinkDoesNotBlock checks that a stuck sink does not block
+// the logging calls, and that the entries it falls behind on are dropped.
+func TestBufferedSinkDoesNotBlock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	child := &blockingSink{release: make(chan struct{})}
+	s := newBufferedSink(child, 2 /* maxEntries */)
+	defer s.close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = s.output(sinkEntry{data: []byte("x")})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("output blocked on a stuck sink")
+	}
+
+	close(child.release)
+	s.flush(10 * time.Second)
+	child.mu.Lock()
+	defer child.mu.Unlock()
+	// At most one entry in flight plus the two queued ones.
+	if n := len(child.mu.entries); n == 0 || n > 3 {
+		t.Fatalf("expected between 1 and 3 entries emitted, got %d", n)
+	}
+}
+
+func TestHTTPSinkRouting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s := ScopeWithoutShowLogs(t)
+	defer s.Close(t)
+	defer TestingSetRedactable(true)()
+
+	var mu syncutil.Mutex
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(b))
+	}))
+	defer ts.Close()
+
+	c, err := ParseConfig(`
+sinks:
+- type: http
+  channels: [health]
+  filter: WARNING
+  format: json
+  redact: true
+  url: ` + ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup, err := ApplyConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	Health.Infof(ctx, "below the filter")
+	Ops.Warningf(ctx, "not routed")
+	Health.Warningf(ctx, "disk %s is slow", "secret")
+	routes, _ := logging.sinks.routes.Load().(*[numChannels][]*sinkInfo)
+	routes[ChannelHealth][0].sink.flush(10 * time.Second)
+	cleanup()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %q", len(bodies), bodies)
+	}
+	var je jsonEntry
+	if err := json.Unmarshal([]byte(bodies[0]), &je); err != nil {
+		t.Fatal(err)
+	}
+	if je.Channel != "health" || je.Severity != "WARNING" {
+		t.Errorf("unexpected entry: %+v", je)
+	}
+	if strings.Contains(je.Message, "secret") || !strings.Contains(je.Message, "disk") {
+		t.Errorf("expected redacted message, got %q", je.Message)
+	}
+}
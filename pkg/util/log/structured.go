@@ -33,7 +33,7 @@ func FormatWithContextTags(ctx context.Context, format string, args ...interface
 // addStructured creates a structured log entry to be written to the
 // specified facility of the logger.
 func addStructured(
-	ctx context.Context, sev Severity, depth int, format string, args []interface{},
+	ctx context.Context, ch Channel, sev Severity, depth int, format string, args []interface{},
 ) {
 	if sev == Severity_FATAL {
 		// We load the ReportingSettings from the a global singleton in this
@@ -49,5 +49,6 @@ func addStructured(
 	if sp, el, ok := getSpanOrEventLog(ctx); ok {
 		eventInternal(sp, el, (sev >= Severity_ERROR), entry)
 	}
+	logging.sinks.output(ch, entry)
 	mainLog.outputLogEntry(entry)
 }
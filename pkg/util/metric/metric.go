@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/bits"
 	"sync/atomic"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/codahale/hdrhistogram"
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	prometheusgo "github.com/prometheus/client_model/go"
 	metrics "github.com/rcrowley/go-metrics"
 )
@@ -193,9 +195,19 @@ type Histogram struct {
 		syncutil.Mutex
 		cumulative *hdrhistogram.Histogram
 		sliding    *slidingHistogram
+		// exemplars holds the most recently recorded exemplar for values of
+		// each power-of-two magnitude, indexed by bits.Len64 of the value. The
+		// bucket boundaries of the underlying histograms never straddle a
+		// power of two, so each exemplar falls into exactly one exported
+		// bucket.
+		exemplars [64]*prometheusgo.Exemplar
 	}
 }
 
+// exemplarTraceIDLabel is the name of the exemplar label holding the ID of
+// the trace that an exemplar refers to.
+const exemplarTraceIDLabel = "trace_id"
+
 // NewHistogram initializes a given Histogram. The contained windowed histogram
 // rotates every 'duration'; both the windowed and the cumulative histogram
 // track nonnegative values up to 'maxVal' with 'sigFigs' decimal points of
@@ -246,13 +258,39 @@ func (h *Histogram) Snapshot() *hdrhistogram.Histogram {
 func (h *Histogram) RecordValue(v int64) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.recordValueLocked(v)
+}
+
+// RecordValueWithExemplar is like RecordValue, but additionally remembers the
+// value as an exemplar referring to the given trace. Exemplars are attached
+// to the buckets of the histogram when it is exported in the OpenMetrics
+// format, linking the metric to traces of the operations it measures.
+func (h *Histogram) RecordValueWithExemplar(v int64, traceID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	v = h.recordValueLocked(v)
+	t := now()
+	h.mu.exemplars[bits.Len64(uint64(v))] = &prometheusgo.Exemplar{
+		Label: []*prometheusgo.LabelPair{{
+			Name:  proto.String(exemplarTraceIDLabel),
+			Value: proto.String(traceID),
+		}},
+		Value:     proto.Float64(float64(v)),
+		Timestamp: &timestamp.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())},
+	}
+}
 
+// recordValueLocked records the value in both the cumulative and the windowed
+// histogram, returning the value that was actually recorded.
+func (h *Histogram) recordValueLocked(v int64) int64 {
 	if h.mu.sliding.RecordValue(v) != nil {
 		_ = h.mu.sliding.RecordValue(h.maxVal)
 	}
 	if h.mu.cumulative.RecordValue(v) != nil {
 		_ = h.mu.cumulative.RecordValue(h.maxVal)
+		v = h.maxVal
 	}
+	return v
 }
 
 // TotalCount returns the (cumulative) number of samples.
@@ -304,10 +342,15 @@ func (h *Histogram) ToPrometheusMetric() *prometheusgo.Metric {
 		cumCount += uint64(bar.Count)
 		curCumCount := cumCount // need a new alloc thanks to bad proto code
 
-		hist.Bucket = append(hist.Bucket, &prometheusgo.Bucket{
+		bucket := &prometheusgo.Bucket{
 			CumulativeCount: &curCumCount,
 			UpperBound:      &upperBound,
-		})
+		}
+		if e := h.mu.exemplars[bits.Len64(uint64(bar.To))]; e != nil &&
+			int64(e.GetValue()) >= bar.From && int64(e.GetValue()) <= bar.To {
+			bucket.Exemplar = e
+		}
+		hist.Bucket = append(hist.Bucket, bucket)
 	}
 	hist.SampleCount = &cumCount
 	hist.SampleSum = &sum // can do better here; we approximate in the loop
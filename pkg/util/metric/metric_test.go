@@ -132,6 +132,42 @@ func TestHistogramPrometheus(t *testing.T) {
 	}
 }
 
+func TestHistogramExemplars(t *testing.T) {
+	h := NewHistogram(Metadata{}, time.Hour, 10, 1)
+	h.RecordValue(1)
+	h.RecordValueWithExemplar(5, "0af7651916cd43dd8448eb211c80319c")
+	h.RecordValueWithExemplar(15000, "b7ad6b7169203331b7ad6b7169203331") // counts as 10
+
+	exp := map[float64]string{
+		5:  "0af7651916cd43dd8448eb211c80319c",
+		10: "b7ad6b7169203331b7ad6b7169203331",
+	}
+	buckets := h.ToPrometheusMetric().Histogram.Bucket
+	if len(buckets) != 3 {
+		t.Fatalf("expected 3 buckets, got %d", len(buckets))
+	}
+	for _, b := range buckets {
+		e := b.GetExemplar()
+		traceID, ok := exp[b.GetUpperBound()]
+		if !ok {
+			if e != nil {
+				t.Errorf("unexpected exemplar %s for bucket %v", e, b.GetUpperBound())
+			}
+			continue
+		}
+		if e == nil {
+			t.Errorf("missing exemplar for bucket %v", b.GetUpperBound())
+			continue
+		}
+		if len(e.Label) != 1 || e.Label[0].GetName() != "trace_id" || e.Label[0].GetValue() != traceID {
+			t.Errorf("unexpected exemplar labels for bucket %v: %v", b.GetUpperBound(), e.Label)
+		}
+		if e.GetValue() != b.GetUpperBound() {
+			t.Errorf("expected exemplar value %v, got %v", b.GetUpperBound(), e.GetValue())
+		}
+	}
+}
+
 func TestHistogramRotate(t *testing.T) {
 	defer TestingSetNow(nil)()
 	setNow(0)
@@ -102,6 +102,22 @@ func (pm *PrometheusExporter) PrintAsText(w io.Writer) error {
 	return nil
 }
 
+// PrintAsOpenMetrics is like PrintAsText, but writes the metrics in the
+// OpenMetrics text format. Unlike the prometheus text format, OpenMetrics
+// supports exemplars, which are attached to the buckets of histograms.
+func (pm *PrometheusExporter) PrintAsOpenMetrics(w io.Writer) error {
+	for _, family := range pm.families {
+		if _, err := expfmt.MetricFamilyToOpenMetrics(w, family); err != nil {
+			return err
+		}
+	}
+	if _, err := expfmt.FinalizeOpenMetrics(w); err != nil {
+		return err
+	}
+	pm.clearMetrics()
+	return nil
+}
+
 // Verify GraphiteExporter implements Gatherer interface.
 var _ prometheus.Gatherer = (*PrometheusExporter)(nil)
 
@@ -10,7 +10,12 @@
 
 package metric
 
-import "testing"
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestPrometheusExporter(t *testing.T) {
 	r1, r2 := NewRegistry(), NewRegistry()
@@ -113,3 +118,35 @@ func TestPrometheusExporter(t *testing.T) {
 		}
 	}
 }
+
+func TestPrometheusExporterOpenMetrics(t *testing.T) {
+	r := NewRegistry()
+	h := NewHistogram(Metadata{Name: "latency"}, time.Hour, 10, 1)
+	r.AddMetric(h)
+	h.RecordValue(1)
+	h.RecordValueWithExemplar(5, "0af7651916cd43dd8448eb211c80319c")
+
+	pe := MakePrometheusExporter()
+	pe.ScrapeRegistry(r, false /* includeChildMetrics */)
+	var buf bytes.Buffer
+	if err := pe.PrintAsOpenMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, exp := range []string{
+		"# TYPE latency histogram\n",
+		`latency_bucket{le="1.0"} 1` + "\n",
+		`latency_bucket{le="5.0"} 2 # {trace_id="0af7651916cd43dd8448eb211c80319c"} 5.0 `,
+		`latency_bucket{le="+Inf"} 2` + "\n",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected output to contain %q, got:\n%s", exp, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("expected output to end with EOF marker, got:\n%s", out)
+	}
+	if len(pe.families["latency"].Metric) != 0 {
+		t.Errorf("expected metrics to be cleared after printing")
+	}
+}
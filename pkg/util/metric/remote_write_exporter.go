@@ -0,0 +1,226 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package metric
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/prompb"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+	prometheusgo "github.com/prometheus/client_model/go"
+)
+
+const (
+	// remoteWriteVersion is the version of the remote write protocol
+	// implemented by RemoteWriteExporter.
+	remoteWriteVersion = "0.1.0"
+	// remoteWriteTimeout bounds the time taken by a single push.
+	remoteWriteTimeout = 10 * time.Second
+	// remoteWriteMaxErrorBody is the maximum number of bytes of an error
+	// response that are included in the returned error.
+	remoteWriteMaxErrorBody = 512
+)
+
+var errNoRemoteWriteEndpoint = errors.New("external.prometheus.remote_write.endpoint is not set")
+
+// remoteWriteLabelNameRE matches valid prometheus label names.
+var remoteWriteLabelNameRE = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ParseRemoteWriteLabels parses a comma-separated list of name=value pairs
+// into a set of labels which can be passed to RemoteWriteExporter.Push.
+func ParseRemoteWriteLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		eq := strings.IndexByte(pair, '=')
+		if eq < 0 {
+			return nil, errors.Newf("invalid label %q: expected name=value", pair)
+		}
+		name, value := strings.TrimSpace(pair[:eq]), strings.TrimSpace(pair[eq+1:])
+		if !remoteWriteLabelNameRE.MatchString(name) || strings.HasPrefix(name, "__") {
+			return nil, errors.Newf("invalid label name %q", name)
+		}
+		if _, ok := labels[name]; ok {
+			return nil, errors.Newf("duplicate label name %q", name)
+		}
+		labels[name] = value
+	}
+	return labels, nil
+}
+
+// RemoteWriteExporter scrapes PrometheusExporter for metrics and pushes them
+// to an endpoint implementing the Prometheus remote write protocol. This is
+// useful when the nodes of a cluster cannot be scraped directly.
+type RemoteWriteExporter struct {
+	pm     *PrometheusExporter
+	client *httputil.Client
+}
+
+// MakeRemoteWriteExporter returns an initialized remote write exporter.
+func MakeRemoteWriteExporter(pm *PrometheusExporter) RemoteWriteExporter {
+	return RemoteWriteExporter{
+		pm:     pm,
+		client: httputil.NewClientWithTimeout(remoteWriteTimeout),
+	}
+}
+
+// Push sends the metrics scraped into the PrometheusExporter to the remote
+// write endpoint at the given URL. The supplied labels are added to every
+// series which doesn't already have a label of the same name. Series are
+// named as they are in the prometheus text format, and histograms are sent
+// as their buckets (along with _sum and _count series) rather than as
+// precomputed quantiles, so that they can be aggregated by the receiver.
+func (re *RemoteWriteExporter) Push(
+	ctx context.Context, endpoint string, labels map[string]string,
+) error {
+	if endpoint == "" {
+		return errNoRemoteWriteEndpoint
+	}
+	// As for the graphite exporter, clear the metrics regardless of whether
+	// the push succeeds. Only the latest metrics are pushed.
+	defer re.pm.clearMetrics()
+
+	data, err := protoutil.Marshal(makeRemoteWriteRequest(re.pm, labels, now()))
+	if err != nil {
+		return err
+	}
+	body := snappy.Encode(nil, data)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(httputil.ContentTypeHeader, httputil.ProtoContentType)
+	req.Header.Set(httputil.ContentEncodingHeader, "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", remoteWriteVersion)
+	resp, err := re.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(&io.LimitedReader{R: resp.Body, N: remoteWriteMaxErrorBody})
+		return errors.Newf("remote write to %s failed: %s: %s",
+			endpoint, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// makeRemoteWriteRequest converts the metrics in the exporter into a remote
+// write request, with all samples taken at the given time.
+func makeRemoteWriteRequest(
+	pm *PrometheusExporter, extraLabels map[string]string, t time.Time,
+) *prompb.WriteRequest {
+	names := make([]string, 0, len(pm.families))
+	for name := range pm.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	extraNames := make([]string, 0, len(extraLabels))
+	for name := range extraLabels {
+		extraNames = append(extraNames, name)
+	}
+	sort.Strings(extraNames)
+
+	timestampMs := t.UnixNano() / int64(time.Millisecond)
+	req := &prompb.WriteRequest{}
+	for _, name := range names {
+		family := pm.families[name]
+		for _, m := range family.Metric {
+			for _, ts := range remoteWriteSeriesForMetric(name, family.GetType(), m) {
+				for _, l := range m.Label {
+					ts.Labels = append(ts.Labels, prompb.Label{Name: l.GetName(), Value: l.GetValue()})
+				}
+				for _, extraName := range extraNames {
+					if !hasLabel(ts.Labels, extraName) {
+						ts.Labels = append(ts.Labels, prompb.Label{
+							Name:  extraName,
+							Value: extraLabels[extraName],
+						})
+					}
+				}
+				// The remote write protocol requires labels to be sorted by name.
+				sort.Slice(ts.Labels, func(i, j int) bool {
+					return ts.Labels[i].Name < ts.Labels[j].Name
+				})
+				ts.Samples[0].Timestamp = timestampMs
+				req.Timeseries = append(req.Timeseries, ts)
+			}
+		}
+	}
+	return req
+}
+
+// remoteWriteSeriesForMetric returns the series representing the given
+// metric, labeled with their name and (for histogram buckets) upper bound.
+// Each series has a single sample, without a timestamp.
+func remoteWriteSeriesForMetric(
+	name string, typ prometheusgo.MetricType, m *prometheusgo.Metric,
+) []prompb.TimeSeries {
+	series := func(name string, value float64, labels ...prompb.Label) prompb.TimeSeries {
+		return prompb.TimeSeries{
+			Labels:  append([]prompb.Label{{Name: "__name__", Value: name}}, labels...),
+			Samples: []prompb.Sample{{Value: value}},
+		}
+	}
+	switch typ {
+	case prometheusgo.MetricType_COUNTER:
+		return []prompb.TimeSeries{series(name, m.GetCounter().GetValue())}
+	case prometheusgo.MetricType_GAUGE:
+		return []prompb.TimeSeries{series(name, m.GetGauge().GetValue())}
+	case prometheusgo.MetricType_UNTYPED:
+		return []prompb.TimeSeries{series(name, m.GetUntyped().GetValue())}
+	case prometheusgo.MetricType_HISTOGRAM:
+		h := m.GetHistogram()
+		result := make([]prompb.TimeSeries, 0, len(h.Bucket)+3)
+		le := func(upperBound float64) prompb.Label {
+			return prompb.Label{Name: "le", Value: strconv.FormatFloat(upperBound, 'g', -1, 64)}
+		}
+		infSeen := false
+		for _, b := range h.Bucket {
+			result = append(result, series(name+"_bucket", float64(b.GetCumulativeCount()), le(b.GetUpperBound())))
+			infSeen = infSeen || math.IsInf(b.GetUpperBound(), +1)
+		}
+		if !infSeen {
+			result = append(result, series(name+"_bucket", float64(h.GetSampleCount()), le(math.Inf(+1))))
+		}
+		return append(result,
+			series(name+"_sum", h.GetSampleSum()),
+			series(name+"_count", float64(h.GetSampleCount())),
+		)
+	}
+	return nil
+}
+
+func hasLabel(labels []prompb.Label, name string) bool {
+	for _, l := range labels {
+		if l.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package metric
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/prompb"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/golang/snappy"
+	"github.com/kr/pretty"
+)
+
+// decodeRemoteWriteRequest decodes a prometheus.WriteRequest into a list of
+// series, each formatted as `name{label="value",...} value`.
+func decodeRemoteWriteRequest(t *testing.T, b []byte) []string {
+	t.Helper()
+	var req prompb.WriteRequest
+	if err := protoutil.Unmarshal(b, &req); err != nil {
+		t.Fatal(err)
+	}
+	var result []string
+	for _, ts := range req.Timeseries {
+		var name string
+		var labels []string
+		for _, l := range ts.Labels {
+			if l.Name == "__name__" {
+				name = l.Value
+			} else {
+				labels = append(labels, fmt.Sprintf("%s=%q", l.Name, l.Value))
+			}
+		}
+		if !sort.StringsAreSorted(labels) {
+			t.Errorf("labels of %s are not sorted: %v", name, labels)
+		}
+		var samples []string
+		for _, s := range ts.Samples {
+			if s.Timestamp != 1000 {
+				t.Errorf("unexpected sample timestamp %d", s.Timestamp)
+			}
+			samples = append(samples, fmt.Sprint(s.Value))
+		}
+		result = append(result, fmt.Sprintf("%s{%s} %s",
+			name, strings.Join(labels, ","), strings.Join(samples, ",")))
+	}
+	return result
+}
+
+func TestRemoteWriteExporter(t *testing.T) {
+	defer TestingSetNow(func() time.Time { return time.Unix(1, 0) })()
+
+	r := NewRegistry()
+	r.AddLabel("registry", "one")
+	g := NewGauge(Metadata{Name: "a.gauge"})
+	r.AddMetric(g)
+	g.Update(7)
+	c := NewCounter(Metadata{Name: "a.counter"})
+	r.AddMetric(c)
+	c.Inc(3)
+	h := NewHistogram(Metadata{Name: "latency"}, time.Hour, 10, 1)
+	r.AddMetric(h)
+	h.RecordValue(1)
+	h.RecordValue(5)
+
+	var received []string
+	var status int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if ct := req.Header.Get(httputil.ContentTypeHeader); ct != httputil.ProtoContentType {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		if ce := req.Header.Get(httputil.ContentEncodingHeader); ce != "snappy" {
+			t.Errorf("unexpected content encoding %q", ce)
+		}
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := snappy.Decode(nil, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		received = decodeRemoteWriteRequest(t, decoded)
+		if status != http.StatusOK {
+			http.Error(w, "out of order sample", status)
+		}
+	}))
+	defer srv.Close()
+
+	pm := MakePrometheusExporter()
+	re := MakeRemoteWriteExporter(&pm)
+	labels := map[string]string{"instance": "host:8080", "registry": "ignored"}
+
+	status = http.StatusOK
+	pm.ScrapeRegistry(r, false /* includeChildMetrics */)
+	if err := re.Push(context.Background(), srv.URL, labels); err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{
+		`a_counter{instance="host:8080",registry="one"} 3`,
+		`a_gauge{instance="host:8080",registry="one"} 7`,
+		`latency_bucket{instance="host:8080",le="1",registry="one"} 1`,
+		`latency_bucket{instance="host:8080",le="5",registry="one"} 2`,
+		`latency_bucket{instance="host:8080",le="+Inf",registry="one"} 2`,
+		`latency_sum{instance="host:8080",registry="one"} 6`,
+		`latency_count{instance="host:8080",registry="one"} 2`,
+	}
+	if !reflect.DeepEqual(exp, received) {
+		t.Fatalf("expected differs from actual: %s", pretty.Diff(exp, received))
+	}
+	for name, fam := range pm.families {
+		if len(fam.Metric) != 0 {
+			t.Errorf("expected %s to be cleared after push", name)
+		}
+	}
+
+	status = http.StatusBadRequest
+	pm.ScrapeRegistry(r, false /* includeChildMetrics */)
+	if err := re.Push(context.Background(), srv.URL, nil); err == nil ||
+		!strings.Contains(err.Error(), "400 Bad Request: out of order sample") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := re.Push(context.Background(), "", nil); err != errNoRemoteWriteEndpoint {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseRemoteWriteLabels(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		exp    map[string]string
+		expErr string
+	}{
+		{in: "", exp: map[string]string{}},
+		{in: "cluster=prod", exp: map[string]string{"cluster": "prod"}},
+		{in: " cluster = prod , region=us-east1,", exp: map[string]string{"cluster": "prod", "region": "us-east1"}},
+		{in: "empty=", exp: map[string]string{"empty": ""}},
+		{in: "cluster", expErr: `invalid label "cluster": expected name=value`},
+		{in: "1abc=x", expErr: `invalid label name "1abc"`},
+		{in: "a-b=x", expErr: `invalid label name "a-b"`},
+		{in: "__name__=x", expErr: `invalid label name "__name__"`},
+		{in: "a=x,a=y", expErr: `duplicate label name "a"`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			labels, err := ParseRemoteWriteLabels(tc.in)
+			if tc.expErr != "" {
+				if err == nil || err.Error() != tc.expErr {
+					t.Fatalf("expected error %q, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.exp, labels) {
+				t.Fatalf("expected %v, got %v", tc.exp, labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,450 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing/otlppb"
+	"github.com/cockroachdb/errors"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+)
+
+// The OpenTelemetry shadow tracer records spans using the OpenTelemetry data
+// model (128-bit trace IDs, W3C trace context propagation) and exports them in
+// batches to an OTLP or Jaeger collector (see otel_export.go).
+
+// traceParentHeader is the name of the W3C trace context header, used both
+// when injecting the shadow context into a carrier and when accepting a
+// context propagated by a client.
+const traceParentHeader = "traceparent"
+
+// traceParentVersion is the only version of the traceparent format that we
+// produce and understand.
+const traceParentVersion = "00"
+
+// traceFlagSampled is the W3C trace-flags bit indicating that the caller may
+// have recorded trace data.
+const traceFlagSampled = 0x01
+
+// otelSpanContext is the span context of the OpenTelemetry shadow tracer.
+type otelSpanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+	sampled bool
+}
+
+var _ opentracing.SpanContext = otelSpanContext{}
+
+// ForeachBaggageItem is part of the opentracing.SpanContext interface. Baggage
+// is propagated by our own tracer, so the shadow context never carries any.
+func (otelSpanContext) ForeachBaggageItem(func(k, v string) bool) {}
+
+func (sc otelSpanContext) traceParent() string {
+	var flags byte
+	if sc.sampled {
+		flags |= traceFlagSampled
+	}
+	return fmt.Sprintf("%s-%x-%x-%02x", traceParentVersion, sc.traceID[:], sc.spanID[:], flags)
+}
+
+// parseTraceParent parses the value of a W3C traceparent header, of the form
+// "00-<32 hex digits trace ID>-<16 hex digits parent ID>-<2 hex digits flags>".
+func parseTraceParent(s string) (otelSpanContext, error) {
+	var sc otelSpanContext
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 {
+		return sc, errors.Newf("invalid traceparent %q: expected 4 fields", s)
+	}
+	if parts[0] != traceParentVersion {
+		return sc, errors.Newf("invalid traceparent %q: unsupported version %q", s, parts[0])
+	}
+	if err := decodeHexField(sc.traceID[:], parts[1]); err != nil {
+		return sc, errors.Wrapf(err, "invalid traceparent %q: trace ID", s)
+	}
+	if err := decodeHexField(sc.spanID[:], parts[2]); err != nil {
+		return sc, errors.Wrapf(err, "invalid traceparent %q: parent ID", s)
+	}
+	var flags [1]byte
+	if err := decodeHexField(flags[:], parts[3]); err != nil {
+		return sc, errors.Wrapf(err, "invalid traceparent %q: trace flags", s)
+	}
+	if sc.traceID == ([16]byte{}) || sc.spanID == ([8]byte{}) {
+		return sc, errors.Newf("invalid traceparent %q: all-zero trace or parent ID", s)
+	}
+	sc.sampled = flags[0]&traceFlagSampled != 0
+	return sc, nil
+}
+
+// decodeHexField decodes a lowercase hex string of exactly 2*len(dst) digits.
+func decodeHexField(dst []byte, s string) error {
+	if len(s) != 2*len(dst) || strings.ToLower(s) != s {
+		return errors.Newf("expected %d lowercase hex digits, got %q", 2*len(dst), s)
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// ValidateTraceParent returns an error if s is not a valid W3C traceparent
+// header value.
+func ValidateTraceParent(s string) error {
+	_, err := parseTraceParent(s)
+	return err
+}
+
+// ExportedTraceID returns the hex-encoded ID of the trace that the span in the
+// context belongs to, as exported to an OpenTelemetry or Jaeger collector. It
+// returns false if there is no span or if the span is not sampled for export,
+// in which case there is no trace that the ID could refer to.
+func ExportedTraceID(ctx context.Context) (string, bool) {
+	sp, ok := opentracing.SpanFromContext(ctx).(*span)
+	if !ok {
+		return "", false
+	}
+	otelSp, ok := sp.shadowSpan.(*otelSpan)
+	if !ok || !otelSp.ctx.sampled {
+		return "", false
+	}
+	return hex.EncodeToString(otelSp.ctx.traceID[:]), true
+}
+
+// otelAttribute is a key/value pair attached to a span, an event or the
+// exported resource. Values are strings, bools, int64s or float64s; anything
+// else is converted to a string when the attribute is created.
+type otelAttribute struct {
+	key   string
+	value interface{}
+}
+
+func makeOtelAttribute(key string, value interface{}) otelAttribute {
+	switch v := value.(type) {
+	case string, bool, int64, float64:
+		return otelAttribute{key: key, value: v}
+	case int:
+		return otelAttribute{key: key, value: int64(v)}
+	case int32:
+		return otelAttribute{key: key, value: int64(v)}
+	case uint32:
+		return otelAttribute{key: key, value: int64(v)}
+	case float32:
+		return otelAttribute{key: key, value: float64(v)}
+	default:
+		return otelAttribute{key: key, value: fmt.Sprint(v)}
+	}
+}
+
+// otelEvent is a timestamped annotation of a span (the equivalent of an
+// opentracing log record).
+type otelEvent struct {
+	time  time.Time
+	name  string
+	attrs []otelAttribute
+}
+
+// otelSpan is the span type of the OpenTelemetry shadow tracer. Spans that
+// were not sampled are still created (so that their context can be propagated)
+// but don't accumulate tags or events and are not exported.
+type otelSpan struct {
+	tracer       *otelTracer
+	ctx          otelSpanContext
+	parentSpanID [8]byte
+	startTime    time.Time
+
+	mu struct {
+		syncutil.Mutex
+		operation string
+		kind      otlppb.Span_SpanKind
+		endTime   time.Time
+		finished  bool
+		attrs     []otelAttribute
+		events    []otelEvent
+		isError   bool
+	}
+}
+
+var _ opentracing.Span = &otelSpan{}
+
+// Finish is part of the opentracing.Span interface.
+func (s *otelSpan) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+// FinishWithOptions is part of the opentracing.Span interface.
+func (s *otelSpan) FinishWithOptions(opts opentracing.FinishOptions) {
+	finishTime := opts.FinishTime
+	if finishTime.IsZero() {
+		finishTime = time.Now()
+	}
+	s.mu.Lock()
+	if s.mu.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.mu.finished = true
+	s.mu.endTime = finishTime
+	s.mu.Unlock()
+	if s.ctx.sampled {
+		s.tracer.batcher.add(s)
+	}
+}
+
+// Context is part of the opentracing.Span interface.
+func (s *otelSpan) Context() opentracing.SpanContext {
+	return s.ctx
+}
+
+// SetOperationName is part of the opentracing.Span interface.
+func (s *otelSpan) SetOperationName(operationName string) opentracing.Span {
+	s.mu.Lock()
+	s.mu.operation = operationName
+	s.mu.Unlock()
+	return s
+}
+
+// SetTag is part of the opentracing.Span interface.
+func (s *otelSpan) SetTag(key string, value interface{}) opentracing.Span {
+	if !s.ctx.sampled {
+		return s
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setTagLocked(key, value)
+	return s
+}
+
+func (s *otelSpan) setTagLocked(key string, value interface{}) {
+	switch key {
+	case string(ext.SpanKind):
+		switch fmt.Sprint(value) {
+		case string(ext.SpanKindRPCServerEnum):
+			s.mu.kind = otlppb.Span_SPAN_KIND_SERVER
+		case string(ext.SpanKindRPCClientEnum):
+			s.mu.kind = otlppb.Span_SPAN_KIND_CLIENT
+		}
+	case string(ext.Error):
+		if b, ok := value.(bool); ok {
+			s.mu.isError = b
+		}
+	}
+	for i := range s.mu.attrs {
+		if s.mu.attrs[i].key == key {
+			s.mu.attrs[i] = makeOtelAttribute(key, value)
+			return
+		}
+	}
+	s.mu.attrs = append(s.mu.attrs, makeOtelAttribute(key, value))
+}
+
+// LogFields is part of the opentracing.Span interface.
+func (s *otelSpan) LogFields(fields ...otlog.Field) {
+	if !s.ctx.sampled {
+		return
+	}
+	ev := otelEvent{time: time.Now(), name: "log"}
+	for _, f := range fields {
+		if f.Key() == LogMessageField && len(fields) == 1 {
+			ev.name = fmt.Sprint(f.Value())
+			continue
+		}
+		ev.attrs = append(ev.attrs, makeOtelAttribute(f.Key(), f.Value()))
+	}
+	s.mu.Lock()
+	if len(s.mu.events) < maxLogsPerSpan {
+		s.mu.events = append(s.mu.events, ev)
+	}
+	s.mu.Unlock()
+}
+
+// LogKV is part of the opentracing.Span interface.
+func (s *otelSpan) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := otlog.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		s.LogFields(otlog.Error(err), otlog.String("function", "LogKV"))
+		return
+	}
+	s.LogFields(fields...)
+}
+
+// SetBaggageItem is part of the opentracing.Span interface. Baggage is handled
+// by our tracer (which also copies baggage items into tags).
+func (s *otelSpan) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	return s
+}
+
+// BaggageItem is part of the opentracing.Span interface.
+func (s *otelSpan) BaggageItem(restrictedKey string) string {
+	return ""
+}
+
+// Tracer is part of the opentracing.Span interface.
+func (s *otelSpan) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+// LogEvent is part of the opentracing.Span interface. Deprecated.
+func (s *otelSpan) LogEvent(event string) {
+	s.LogFields(otlog.String(LogMessageField, event))
+}
+
+// LogEventWithPayload is part of the opentracing.Span interface. Deprecated.
+func (s *otelSpan) LogEventWithPayload(event string, payload interface{}) {
+	s.LogFields(otlog.String(LogMessageField, event), otlog.Object("payload", payload))
+}
+
+// Log is part of the opentracing.Span interface. Deprecated.
+func (s *otelSpan) Log(data opentracing.LogData) {
+	panic("unimplemented")
+}
+
+// otelTracer is an opentracing.Tracer producing OpenTelemetry spans. It is
+// used as a shadow tracer; finished, sampled spans are handed to a batcher
+// which exports them to a collector.
+type otelTracer struct {
+	// sampleRate is the probability with which a new trace (i.e. a root span
+	// without a sampled remote parent) is sampled. Child spans follow the
+	// sampling decision of their parent.
+	sampleRate float64
+	batcher    *otelBatcher
+}
+
+var _ opentracing.Tracer = &otelTracer{}
+
+func newOtelTracer(sampleRate float64, exporter otelExporter, resource func() []otelAttribute) *otelTracer {
+	return &otelTracer{
+		sampleRate: sampleRate,
+		batcher:    newOtelBatcher(exporter, resource),
+	}
+}
+
+// shouldSample implements a trace ID ratio based sampler: a trace is sampled
+// if the low 63 bits of its ID fall below sampleRate * 2^63. This makes the
+// decision deterministic for a given trace ID.
+func (t *otelTracer) shouldSample(traceID [16]byte) bool {
+	if t.sampleRate >= 1 {
+		return true
+	}
+	if t.sampleRate <= 0 {
+		return false
+	}
+	bound := uint64(t.sampleRate * math.MaxInt64)
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < bound
+}
+
+// StartSpan is part of the opentracing.Tracer interface.
+func (t *otelTracer) StartSpan(
+	operationName string, opts ...opentracing.StartSpanOption,
+) opentracing.Span {
+	var sso opentracing.StartSpanOptions
+	for _, o := range opts {
+		o.Apply(&sso)
+	}
+
+	s := &otelSpan{
+		tracer:    t,
+		startTime: sso.StartTime,
+	}
+	if s.startTime.IsZero() {
+		s.startTime = time.Now()
+	}
+	s.mu.operation = operationName
+	s.mu.kind = otlppb.Span_SPAN_KIND_INTERNAL
+
+	var hasParent bool
+	for _, r := range sso.References {
+		if r.Type != opentracing.ChildOfRef && r.Type != opentracing.FollowsFromRef {
+			continue
+		}
+		if parentCtx, ok := r.ReferencedContext.(otelSpanContext); ok {
+			hasParent = true
+			s.ctx.traceID = parentCtx.traceID
+			s.ctx.sampled = parentCtx.sampled
+			s.parentSpanID = parentCtx.spanID
+			break
+		}
+	}
+	if !hasParent {
+		binary.BigEndian.PutUint64(s.ctx.traceID[:8], uint64(rand.Int63()))
+		binary.BigEndian.PutUint64(s.ctx.traceID[8:], uint64(rand.Int63())|1)
+		s.ctx.sampled = t.shouldSample(s.ctx.traceID)
+	}
+	binary.BigEndian.PutUint64(s.ctx.spanID[:], uint64(rand.Int63())|1)
+
+	if s.ctx.sampled {
+		for k, v := range sso.Tags {
+			s.setTagLocked(k, v)
+		}
+	}
+	return s
+}
+
+// Inject is part of the opentracing.Tracer interface. The context is injected
+// as a W3C traceparent header.
+func (t *otelTracer) Inject(
+	osc opentracing.SpanContext, format interface{}, carrier interface{},
+) error {
+	if format != opentracing.HTTPHeaders && format != opentracing.TextMap {
+		return opentracing.ErrUnsupportedFormat
+	}
+	mapWriter, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+	sc, ok := osc.(otelSpanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+	mapWriter.Set(traceParentHeader, sc.traceParent())
+	return nil
+}
+
+// Extract is part of the opentracing.Tracer interface.
+func (t *otelTracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	if format != opentracing.HTTPHeaders && format != opentracing.TextMap {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+	mapReader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, opentracing.ErrInvalidCarrier
+	}
+	var sc opentracing.SpanContext
+	err := mapReader.ForeachKey(func(k, v string) error {
+		if strings.ToLower(k) != traceParentHeader {
+			return nil
+		}
+		otelCtx, err := parseTraceParent(v)
+		if err != nil {
+			return opentracing.ErrSpanContextCorrupted
+		}
+		sc = otelCtx
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sc == nil {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+	return sc, nil
+}
+
+func (t *otelTracer) close() {
+	t.batcher.close()
+}
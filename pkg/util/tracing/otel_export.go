@@ -0,0 +1,424 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing/otlppb"
+	"github.com/cockroachdb/errors"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"google.golang.org/grpc"
+)
+
+// The exporters below send spans to the respective collector APIs:
+//
+//  - OTLP: opentelemetry.proto.collector.trace.v1.ExportTraceServiceRequest,
+//    sent over gRPC (TraceService/Export) or HTTP (POST /v1/traces). The
+//    messages are generated from the OTLP protos vendored in otlppb.
+//  - Jaeger: jaeger.api_v2.PostSpansRequest, sent over gRPC
+//    (CollectorService/PostSpans).
+
+const (
+	otlpHTTPTracesPath     = "/v1/traces"
+	otelInstrumentationLib = "github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// otelExporter sends batches of finished spans to a collector.
+type otelExporter interface {
+	export(ctx context.Context, resource []otelAttribute, spans []*otelSpan) error
+	close()
+}
+
+var (
+	// otelBatchSize is the maximum number of spans exported in one request.
+	otelBatchSize = 512
+	// otelMaxQueuedSpans is the maximum number of finished spans waiting to be
+	// exported; spans finished while the queue is full are dropped.
+	otelMaxQueuedSpans = 10000
+	// otelFlushInterval is the maximum time a finished span waits before being
+	// exported.
+	otelFlushInterval = time.Second
+	// otelExportTimeout bounds each export request.
+	otelExportTimeout = 10 * time.Second
+)
+
+var otelLogEveryN = util.Every(5 * time.Second)
+
+// otelBatcher accumulates finished spans and exports them asynchronously, in
+// batches of up to otelBatchSize spans or every otelFlushInterval.
+type otelBatcher struct {
+	exporter otelExporter
+	resource func() []otelAttribute
+	spans    chan *otelSpan
+	stopC    chan struct{}
+	doneC    chan struct{}
+}
+
+func newOtelBatcher(exporter otelExporter, resource func() []otelAttribute) *otelBatcher {
+	b := &otelBatcher{
+		exporter: exporter,
+		resource: resource,
+		spans:    make(chan *otelSpan, otelMaxQueuedSpans),
+		stopC:    make(chan struct{}),
+		doneC:    make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *otelBatcher) add(s *otelSpan) {
+	select {
+	case b.spans <- s:
+	default:
+		// The queue is full; drop the span rather than blocking the traced
+		// operation.
+	}
+}
+
+func (b *otelBatcher) run() {
+	defer close(b.doneC)
+	ticker := time.NewTicker(otelFlushInterval)
+	defer ticker.Stop()
+	batch := make([]*otelSpan, 0, otelBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), otelExportTimeout)
+		defer cancel()
+		if err := b.exporter.export(ctx, b.resource(), batch); err != nil {
+			if otelLogEveryN.ShouldProcess(timeutil.Now()) {
+				// We can't use `log` from this package so print errors to stderr.
+				fmt.Fprintf(os.Stderr, "OpenTelemetry exporter: dropping %d spans: %v\n", len(batch), err)
+			}
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case s := <-b.spans:
+			batch = append(batch, s)
+			if len(batch) >= otelBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-b.stopC:
+			// Drain the spans finished before close() and export them.
+			for {
+				select {
+				case s := <-b.spans:
+					batch = append(batch, s)
+					if len(batch) >= otelBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// close exports any pending spans and releases the exporter.
+func (b *otelBatcher) close() {
+	close(b.stopC)
+	<-b.doneC
+	b.exporter.close()
+}
+
+// otlpGRPCExporter exports spans to an OTLP collector's gRPC endpoint.
+type otlpGRPCExporter struct {
+	conn   *grpc.ClientConn
+	client otlppb.TraceServiceClient
+}
+
+// dialCollector dials a collector's gRPC endpoint. The connection is
+// established lazily, so a collector that is down when the exporter is
+// configured doesn't prevent the node from starting.
+func dialCollector(addr string) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr, grpc.WithInsecure())
+}
+
+func newOTLPGRPCExporter(addr string) (*otlpGRPCExporter, error) {
+	conn, err := dialCollector(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &otlpGRPCExporter{conn: conn, client: otlppb.NewTraceServiceClient(conn)}, nil
+}
+
+func (e *otlpGRPCExporter) export(
+	ctx context.Context, resource []otelAttribute, spans []*otelSpan,
+) error {
+	_, err := e.client.Export(ctx, makeOTLPRequest(resource, spans))
+	return err
+}
+
+func (e *otlpGRPCExporter) close() {
+	_ = e.conn.Close()
+}
+
+// otlpHTTPExporter posts OTLP protobuf requests to a collector's HTTP
+// endpoint.
+type otlpHTTPExporter struct {
+	url    string
+	client *http.Client
+}
+
+func newOTLPHTTPExporter(addr string) *otlpHTTPExporter {
+	url := addr
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = "http://" + url
+	}
+	if i := strings.Index(url, "://"); !strings.Contains(url[i+len("://"):], "/") {
+		url += otlpHTTPTracesPath
+	}
+	return &otlpHTTPExporter{
+		url:    url,
+		client: &http.Client{Timeout: otelExportTimeout},
+	}
+}
+
+func (e *otlpHTTPExporter) export(
+	ctx context.Context, resource []otelAttribute, spans []*otelSpan,
+) error {
+	body, err := protoutil.Marshal(makeOTLPRequest(resource, spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return errors.Newf("collector at %s returned %s", e.url, resp.Status)
+	}
+	return nil
+}
+
+func (e *otlpHTTPExporter) close() {
+	e.client.CloseIdleConnections()
+}
+
+// jaegerExporter exports spans to a Jaeger collector's gRPC endpoint.
+type jaegerExporter struct {
+	conn   *grpc.ClientConn
+	client api_v2.CollectorServiceClient
+}
+
+func newJaegerExporter(addr string) (*jaegerExporter, error) {
+	conn, err := dialCollector(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &jaegerExporter{conn: conn, client: api_v2.NewCollectorServiceClient(conn)}, nil
+}
+
+func (e *jaegerExporter) export(
+	ctx context.Context, resource []otelAttribute, spans []*otelSpan,
+) error {
+	_, err := e.client.PostSpans(ctx, makeJaegerRequest(resource, spans))
+	return err
+}
+
+func (e *jaegerExporter) close() {
+	_ = e.conn.Close()
+}
+
+// makeOTLPRequest returns an ExportTraceServiceRequest holding a single
+// ResourceSpans.
+func makeOTLPRequest(
+	resource []otelAttribute, spans []*otelSpan,
+) *otlppb.ExportTraceServiceRequest {
+	ils := &otlppb.InstrumentationLibrarySpans{
+		InstrumentationLibrary: &otlppb.InstrumentationLibrary{Name: otelInstrumentationLib},
+		Spans:                  make([]*otlppb.Span, len(spans)),
+	}
+	for i, s := range spans {
+		ils.Spans[i] = s.toOTLP()
+	}
+	return &otlppb.ExportTraceServiceRequest{
+		ResourceSpans: []*otlppb.ResourceSpans{{
+			Resource:                    &otlppb.Resource{Attributes: makeOTLPAttributes(resource)},
+			InstrumentationLibrarySpans: []*otlppb.InstrumentationLibrarySpans{ils},
+		}},
+	}
+}
+
+func makeOTLPAttributes(attrs []otelAttribute) []*otlppb.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]*otlppb.KeyValue, len(attrs))
+	for i, a := range attrs {
+		var v otlppb.AnyValue
+		switch t := a.value.(type) {
+		case string:
+			v.Value = &otlppb.AnyValue_StringValue{StringValue: t}
+		case bool:
+			v.Value = &otlppb.AnyValue_BoolValue{BoolValue: t}
+		case int64:
+			v.Value = &otlppb.AnyValue_IntValue{IntValue: t}
+		case float64:
+			v.Value = &otlppb.AnyValue_DoubleValue{DoubleValue: t}
+		}
+		kvs[i] = &otlppb.KeyValue{Key: a.key, Value: &v}
+	}
+	return kvs
+}
+
+func (s *otelSpan) toOTLP() *otlppb.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sp := &otlppb.Span{
+		TraceId:           append([]byte(nil), s.ctx.traceID[:]...),
+		SpanId:            append([]byte(nil), s.ctx.spanID[:]...),
+		Name:              s.mu.operation,
+		Kind:              s.mu.kind,
+		StartTimeUnixNano: uint64(s.startTime.UnixNano()),
+		EndTimeUnixNano:   uint64(s.mu.endTime.UnixNano()),
+		Attributes:        makeOTLPAttributes(s.mu.attrs),
+	}
+	if s.parentSpanID != ([8]byte{}) {
+		sp.ParentSpanId = append([]byte(nil), s.parentSpanID[:]...)
+	}
+	for i := range s.mu.events {
+		ev := &s.mu.events[i]
+		sp.Events = append(sp.Events, &otlppb.Span_Event{
+			TimeUnixNano: uint64(ev.time.UnixNano()),
+			Name:         ev.name,
+			Attributes:   makeOTLPAttributes(ev.attrs),
+		})
+	}
+	if s.mu.isError {
+		sp.Status = &otlppb.Status{Code: otlppb.Status_STATUS_CODE_ERROR}
+	}
+	return sp
+}
+
+// makeJaegerRequest returns a PostSpansRequest. The resource attributes become
+// the process tags, except for service.name which is the process' service
+// name.
+func makeJaegerRequest(resource []otelAttribute, spans []*otelSpan) *api_v2.PostSpansRequest {
+	process := &model.Process{}
+	var tags []otelAttribute
+	for _, a := range resource {
+		if a.key == serviceNameAttribute {
+			process.ServiceName = fmt.Sprint(a.value)
+			continue
+		}
+		tags = append(tags, a)
+	}
+	process.Tags = makeJaegerKeyValues(tags)
+	req := &api_v2.PostSpansRequest{
+		Batch: model.Batch{
+			Spans:   make([]*model.Span, len(spans)),
+			Process: process,
+		},
+	}
+	for i, s := range spans {
+		req.Batch.Spans[i] = s.toJaeger()
+	}
+	return req
+}
+
+func makeJaegerKeyValues(attrs []otelAttribute) []model.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]model.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.value.(type) {
+		case string:
+			kvs = append(kvs, model.String(a.key, v))
+		case bool:
+			kvs = append(kvs, model.Bool(a.key, v))
+		case int64:
+			kvs = append(kvs, model.Int64(a.key, v))
+		case float64:
+			kvs = append(kvs, model.Float64(a.key, v))
+		}
+	}
+	return kvs
+}
+
+// jaegerTraceID converts a W3C trace ID to a Jaeger trace ID, which encodes
+// the same 16 bytes as two big-endian integers.
+func jaegerTraceID(traceID [16]byte) model.TraceID {
+	return model.NewTraceID(
+		binary.BigEndian.Uint64(traceID[:8]), binary.BigEndian.Uint64(traceID[8:]),
+	)
+}
+
+func jaegerSpanID(spanID [8]byte) model.SpanID {
+	return model.NewSpanID(binary.BigEndian.Uint64(spanID[:]))
+}
+
+func (s *otelSpan) toJaeger() *model.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	traceID := jaegerTraceID(s.ctx.traceID)
+	sp := &model.Span{
+		TraceID:       traceID,
+		SpanID:        jaegerSpanID(s.ctx.spanID),
+		OperationName: s.mu.operation,
+		Flags:         traceFlagSampled,
+		StartTime:     s.startTime,
+		Duration:      s.mu.endTime.Sub(s.startTime),
+	}
+	if s.parentSpanID != ([8]byte{}) {
+		sp.References = []model.SpanRef{
+			model.NewChildOfRef(traceID, jaegerSpanID(s.parentSpanID)),
+		}
+	}
+	tags := s.mu.attrs
+	switch s.mu.kind {
+	case otlppb.Span_SPAN_KIND_SERVER, otlppb.Span_SPAN_KIND_CLIENT:
+		// span.kind is already among the tags.
+	default:
+		tags = append(tags[:len(tags):len(tags)], otelAttribute{key: "span.kind", value: "internal"})
+	}
+	sp.Tags = makeJaegerKeyValues(tags)
+	for i := range s.mu.events {
+		ev := &s.mu.events[i]
+		fields := append([]otelAttribute{{key: "event", value: ev.name}}, ev.attrs...)
+		sp.Logs = append(sp.Logs, model.Log{
+			Timestamp: ev.time,
+			Fields:    makeJaegerKeyValues(fields),
+		})
+	}
+	return sp
+}
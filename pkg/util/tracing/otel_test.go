@@ -0,0 +1,363 @@
+// Copyright 2020 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing/otlppb"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	const valid = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	sc, err := parseTraceParent(valid)
+	require.NoError(t, err)
+	require.True(t, sc.sampled)
+	require.Equal(t, "0af7651916cd43dd8448eb211c80319c", hex.EncodeToString(sc.traceID[:]))
+	require.Equal(t, "b7ad6b7169203331", hex.EncodeToString(sc.spanID[:]))
+	require.Equal(t, valid, sc.traceParent())
+
+	sc, err = parseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+	require.NoError(t, err)
+	require.False(t, sc.sampled)
+
+	for _, invalid := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c8031-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b71692033zz-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+	} {
+		require.Error(t, ValidateTraceParent(invalid), invalid)
+	}
+}
+
+// testExporter is an otelExporter keeping the exported spans in memory.
+type testExporter struct {
+	syncutil.Mutex
+	spans []*otelSpan
+}
+
+func (e *testExporter) export(_ context.Context, _ []otelAttribute, spans []*otelSpan) error {
+	e.Lock()
+	defer e.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *testExporter) close() {}
+
+func (e *testExporter) operations() []string {
+	e.Lock()
+	defer e.Unlock()
+	var ops []string
+	for _, s := range e.spans {
+		ops = append(ops, s.mu.operation)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+func TestOtelSampling(t *testing.T) {
+	tr := NewTracer()
+	exporter := &testExporter{}
+	tr.setShadowTracer(&otelManager{name: "test"}, newOtelTracer(0 /* sampleRate */, exporter, tr.getResourceAttributes))
+
+	// With a sample rate of 0, new traces are not exported...
+	root := tr.StartSpan("root")
+	StartChildSpan("root-child", root, nil /* logTags */, false /* separateRecording */).Finish()
+	root.Finish()
+
+	// ... but traces continuing a sampled client trace are.
+	remote, err := tr.ExtractTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.NoError(t, err)
+	sampled := tr.StartSpan("sampled", opentracing.ChildOf(remote))
+	StartChildSpan("sampled-child", sampled, nil /* logTags */, false /* separateRecording */).Finish()
+	sampled.Finish()
+
+	remote, err = tr.ExtractTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+	require.NoError(t, err)
+	tr.StartSpan("unsampled", opentracing.ChildOf(remote)).Finish()
+
+	tr.Close()
+	require.Equal(t, []string{"sampled", "sampled-child"}, exporter.operations())
+}
+
+func TestOtelInjectExtract(t *testing.T) {
+	tr := NewTracer()
+	tr.setShadowTracer(&otelManager{name: "test"}, newOtelTracer(1 /* sampleRate */, &testExporter{}, tr.getResourceAttributes))
+	defer tr.Close()
+
+	const traceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	remote, err := tr.ExtractTraceParent(traceParent)
+	require.NoError(t, err)
+	s := tr.StartSpan("test", opentracing.ChildOf(remote))
+	defer s.Finish()
+
+	// Our trace ID is derived from the W3C trace ID.
+	require.Equal(t, uint64(0x8448eb211c80319c), s.(*span).TraceID)
+
+	// The exported trace ID is the W3C trace ID.
+	traceID, ok := ExportedTraceID(opentracing.ContextWithSpan(context.Background(), s))
+	require.True(t, ok)
+	require.Equal(t, "0af7651916cd43dd8448eb211c80319c", traceID)
+	_, ok = ExportedTraceID(context.Background())
+	require.False(t, ok)
+
+	carrier := make(opentracing.HTTPHeadersCarrier)
+	require.NoError(t, tr.Inject(s.Context(), opentracing.HTTPHeaders, carrier))
+	wireContext, err := tr.Extract(opentracing.HTTPHeaders, carrier)
+	require.NoError(t, err)
+
+	s2 := tr.StartSpan("child", opentracing.ChildOf(wireContext))
+	defer s2.Finish()
+	parentCtx := s.(*span).shadowSpan.Context().(otelSpanContext)
+	childSpan := s2.(*span).shadowSpan.(*otelSpan)
+	require.Equal(t, parentCtx.traceID, childSpan.ctx.traceID)
+	require.Equal(t, parentCtx.spanID, childSpan.parentSpanID)
+	require.True(t, childSpan.ctx.sampled)
+}
+
+// testCollector is an in-process stand-in for an OTLP or Jaeger collector. It
+// records the requests it receives.
+type testCollector struct {
+	syncutil.Mutex
+	methods  []string
+	requests []protoutil.Message
+}
+
+var _ otlppb.TraceServiceServer = &testCollector{}
+var _ api_v2.CollectorServiceServer = &testCollector{}
+
+func (c *testCollector) record(method string, req protoutil.Message) {
+	c.Lock()
+	defer c.Unlock()
+	c.methods = append(c.methods, method)
+	c.requests = append(c.requests, req)
+}
+
+// Export is part of the otlppb.TraceServiceServer interface.
+func (c *testCollector) Export(
+	_ context.Context, req *otlppb.ExportTraceServiceRequest,
+) (*otlppb.ExportTraceServiceResponse, error) {
+	c.record("Export", req)
+	return &otlppb.ExportTraceServiceResponse{}, nil
+}
+
+// PostSpans is part of the api_v2.CollectorServiceServer interface.
+func (c *testCollector) PostSpans(
+	_ context.Context, req *api_v2.PostSpansRequest,
+) (*api_v2.PostSpansResponse, error) {
+	c.record("PostSpans", req)
+	return &api_v2.PostSpansResponse{}, nil
+}
+
+// startGRPC starts a gRPC server implementing the OTLP and Jaeger collector
+// services.
+func (c *testCollector) startGRPC(t *testing.T) (addr string, stop func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	otlppb.RegisterTraceServiceServer(s, c)
+	api_v2.RegisterCollectorServiceServer(s, c)
+	go func() { _ = s.Serve(ln) }()
+	return ln.Addr().String(), s.Stop
+}
+
+// startHTTP starts an HTTP server accepting OTLP requests.
+func (c *testCollector) startHTTP(t *testing.T) (addr string, stop func()) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-protobuf" {
+			http.Error(w, "unexpected content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var req otlppb.ExportTraceServiceRequest
+		if err := protoutil.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.record(r.URL.Path, &req)
+	}))
+	return s.Listener.Addr().String(), s.Close
+}
+
+// collectedSpan is the subset of an exported span checked by the tests.
+type collectedSpan struct {
+	traceID, spanID, parentID string
+	attrs                     map[string]string
+}
+
+// otlpStringAttrs returns the string-valued attributes in kvs.
+func otlpStringAttrs(kvs []*otlppb.KeyValue) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range kvs {
+		if v, ok := kv.Value.Value.(*otlppb.AnyValue_StringValue); ok {
+			attrs[kv.Key] = v.StringValue
+		}
+	}
+	return attrs
+}
+
+func decodeOTLPRequest(
+	t *testing.T, msg protoutil.Message,
+) (resource map[string]string, spans map[string]collectedSpan) {
+	req, ok := msg.(*otlppb.ExportTraceServiceRequest)
+	require.True(t, ok, "unexpected request %T", msg)
+	require.Len(t, req.ResourceSpans, 1)
+	rs := req.ResourceSpans[0]
+	resource = otlpStringAttrs(rs.Resource.Attributes)
+	spans = make(map[string]collectedSpan)
+	for _, ils := range rs.InstrumentationLibrarySpans {
+		for _, sp := range ils.Spans {
+			spans[sp.Name] = collectedSpan{
+				traceID:  hex.EncodeToString(sp.TraceId),
+				spanID:   hex.EncodeToString(sp.SpanId),
+				parentID: hex.EncodeToString(sp.ParentSpanId),
+				attrs:    otlpStringAttrs(sp.Attributes),
+			}
+		}
+	}
+	return resource, spans
+}
+
+// jaegerStringAttrs returns the string-valued key/values in kvs.
+func jaegerStringAttrs(kvs []model.KeyValue) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range kvs {
+		if kv.VType == model.StringType {
+			attrs[kv.Key] = kv.VStr
+		}
+	}
+	return attrs
+}
+
+func decodeJaegerRequest(
+	t *testing.T, msg protoutil.Message,
+) (resource map[string]string, spans map[string]collectedSpan) {
+	req, ok := msg.(*api_v2.PostSpansRequest)
+	require.True(t, ok, "unexpected request %T", msg)
+	resource = jaegerStringAttrs(req.Batch.Process.Tags)
+	resource[serviceNameAttribute] = req.Batch.Process.ServiceName
+	spans = make(map[string]collectedSpan)
+	for _, sp := range req.Batch.Spans {
+		s := collectedSpan{
+			traceID: fmt.Sprintf("%016x%016x", sp.TraceID.High, sp.TraceID.Low),
+			spanID:  fmt.Sprintf("%016x", uint64(sp.SpanID)),
+			attrs:   jaegerStringAttrs(sp.Tags),
+		}
+		if len(sp.References) > 0 {
+			s.parentID = fmt.Sprintf("%016x", uint64(sp.References[0].SpanID))
+		}
+		spans[sp.OperationName] = s
+	}
+	return resource, spans
+}
+
+func TestOtelExport(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		start          func(*testCollector, *testing.T) (string, func())
+		createTracer   func(addr string, resource func() []otelAttribute) (shadowTracerManager, opentracing.Tracer)
+		expectedMethod string
+		decode         func(*testing.T, protoutil.Message) (map[string]string, map[string]collectedSpan)
+	}{
+		{
+			name:  "otlp-grpc",
+			start: (*testCollector).startGRPC,
+			createTracer: func(addr string, resource func() []otelAttribute) (shadowTracerManager, opentracing.Tracer) {
+				return createOTLPTracer(addr, otlpProtocolGRPC, 1 /* sampleRate */, resource)
+			},
+			expectedMethod: "Export",
+			decode:         decodeOTLPRequest,
+		},
+		{
+			name:  "otlp-http",
+			start: (*testCollector).startHTTP,
+			createTracer: func(addr string, resource func() []otelAttribute) (shadowTracerManager, opentracing.Tracer) {
+				return createOTLPTracer(addr, otlpProtocolHTTP, 1 /* sampleRate */, resource)
+			},
+			expectedMethod: otlpHTTPTracesPath,
+			decode:         decodeOTLPRequest,
+		},
+		{
+			name:  "jaeger",
+			start: (*testCollector).startGRPC,
+			createTracer: func(addr string, resource func() []otelAttribute) (shadowTracerManager, opentracing.Tracer) {
+				return createJaegerTracer(addr, 1 /* sampleRate */, resource)
+			},
+			expectedMethod: "PostSpans",
+			decode:         decodeJaegerRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &testCollector{}
+			addr, stop := tc.start(c, t)
+			defer stop()
+
+			tr := NewTracer()
+			tr.SetResourceAttribute("cockroach.node_id", "1")
+			tr.SetResourceAttribute("cockroach.locality", "region=us-east1")
+			tr.setShadowTracer(tc.createTracer(addr, tr.getResourceAttributes))
+
+			remote, err := tr.ExtractTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+			require.NoError(t, err)
+			root := tr.StartSpan("root", opentracing.ChildOf(remote))
+			root.SetTag("statement", "SELECT 1")
+			child := StartChildSpan("child", root, nil /* logTags */, false /* separateRecording */)
+			child.LogKV("event", "hello")
+			child.Finish()
+			root.Finish()
+
+			// Closing the tracer flushes the pending spans.
+			tr.Close()
+
+			c.Lock()
+			defer c.Unlock()
+			require.Equal(t, []string{tc.expectedMethod}, c.methods)
+			resource, spans := tc.decode(t, c.requests[0])
+			require.Equal(t, map[string]string{
+				serviceNameAttribute: "cockroach",
+				"cockroach.node_id":  "1",
+				"cockroach.locality": "region=us-east1",
+			}, resource)
+			require.Len(t, spans, 2)
+			const traceID = "0af7651916cd43dd8448eb211c80319c"
+			require.Equal(t, traceID, spans["root"].traceID)
+			require.Equal(t, "b7ad6b7169203331", spans["root"].parentID)
+			require.Equal(t, "SELECT 1", spans["root"].attrs["statement"])
+			require.Equal(t, traceID, spans["child"].traceID)
+			require.Equal(t, spans["root"].spanID, spans["child"].parentID)
+		})
+	}
+}
@@ -142,3 +142,47 @@ func createZipkinTracer(collectorAddr string) (shadowTracerManager, opentracing.
 	}
 	return &zipkinManager{collector: collector}, zipkinTr
 }
+
+// otelManager is the shadowTracerManager for the OpenTelemetry tracer, which
+// is used for all the collectors speaking OpenTelemetry (or Jaeger) protocols.
+type otelManager struct {
+	name string
+}
+
+func (m *otelManager) Name() string {
+	return m.name
+}
+
+func (m *otelManager) Close(tr opentracing.Tracer) {
+	if st, ok := tr.(*shadowTracer); ok {
+		tr = st.Tracer
+	}
+	tr.(*otelTracer).close()
+}
+
+func createOTLPTracer(
+	collectorAddr string, protocol otlpProtocol, sampleRate float64, resource func() []otelAttribute,
+) (shadowTracerManager, opentracing.Tracer) {
+	var exporter otelExporter
+	switch protocol {
+	case otlpProtocolHTTP:
+		exporter = newOTLPHTTPExporter(collectorAddr)
+	default:
+		grpcExporter, err := newOTLPGRPCExporter(collectorAddr)
+		if err != nil {
+			panic(err)
+		}
+		exporter = grpcExporter
+	}
+	return &otelManager{name: "opentelemetry"}, newOtelTracer(sampleRate, exporter, resource)
+}
+
+func createJaegerTracer(
+	collectorAddr string, sampleRate float64, resource func() []otelAttribute,
+) (shadowTracerManager, opentracing.Tracer) {
+	exporter, err := newJaegerExporter(collectorAddr)
+	if err != nil {
+		panic(err)
+	}
+	return &otelManager{name: "jaeger"}, newOtelTracer(sampleRate, exporter, resource)
+}
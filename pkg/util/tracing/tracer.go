@@ -12,6 +12,7 @@ package tracing
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"regexp"
@@ -25,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/caller"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -70,6 +72,56 @@ var zipkinCollector = settings.RegisterPublicStringSetting(
 	envutil.EnvOrDefaultString("COCKROACH_TEST_ZIPKIN_COLLECTOR", ""),
 )
 
+var otlpCollector = settings.RegisterPublicStringSetting(
+	"trace.opentelemetry.collector",
+	"if set, traces go to the given OpenTelemetry collector (example: '127.0.0.1:4317'); ignored if trace.lightstep.token or trace.zipkin.collector is set",
+	envutil.EnvOrDefaultString("COCKROACH_TEST_OTLP_COLLECTOR", ""),
+)
+
+// otlpProtocol is the transport used to send spans to an OpenTelemetry
+// collector.
+type otlpProtocol int64
+
+const (
+	otlpProtocolGRPC otlpProtocol = iota
+	otlpProtocolHTTP
+)
+
+var otlpCollectorProtocol = settings.RegisterPublicEnumSetting(
+	"trace.opentelemetry.protocol",
+	"the OTLP transport used to send traces to trace.opentelemetry.collector",
+	"grpc",
+	map[int64]string{
+		int64(otlpProtocolGRPC): "grpc",
+		int64(otlpProtocolHTTP): "http",
+	},
+)
+
+var jaegerCollector = settings.RegisterPublicStringSetting(
+	"trace.jaeger.collector",
+	"if set, traces go to the gRPC endpoint of the given Jaeger collector (example: '127.0.0.1:14250'); ignored if trace.lightstep.token, trace.zipkin.collector or trace.opentelemetry.collector is set",
+	envutil.EnvOrDefaultString("COCKROACH_TEST_JAEGER_COLLECTOR", ""),
+)
+
+var otelSampleRate = func() *settings.FloatSetting {
+	s := settings.RegisterValidatedFloatSetting(
+		"trace.opentelemetry.sample_rate",
+		"the fraction of new traces sent to trace.opentelemetry.collector or trace.jaeger.collector; traces continuing a sampled client trace are always sent",
+		1,
+		func(v float64) error {
+			if v < 0 || v > 1 {
+				return errors.Errorf("sample rate must be between 0 and 1, got %f", v)
+			}
+			return nil
+		})
+	s.SetVisibility(settings.Public)
+	return s
+}()
+
+// serviceNameAttribute is the resource attribute holding the name of the
+// service producing the spans exported to OpenTelemetry and Jaeger collectors.
+const serviceNameAttribute = "service.name"
+
 // Tracer is our own custom implementation of opentracing.Tracer. It supports:
 //
 //  - forwarding events to x/net/trace instances
@@ -81,6 +133,9 @@ var zipkinCollector = settings.RegisterPublicStringSetting(
 //  - lightstep traces. This is implemented by maintaining a "shadow" lightstep
 //    span inside each of our spans.
 //
+//  - zipkin, OpenTelemetry (OTLP) and Jaeger traces, implemented the same way
+//    as lightstep traces.
+//
 // Even when tracing is disabled, we still use this Tracer (with x/net/trace and
 // lightstep disabled) because of its recording capability (snowball
 // tracing needs to work in all cases).
@@ -104,6 +159,13 @@ type Tracer struct {
 
 	// Pointer to shadowTracer, if using one.
 	shadowTracer unsafe.Pointer
+
+	// resourceAttrs describe this process to OpenTelemetry and Jaeger
+	// collectors. See SetResourceAttribute.
+	resourceAttrs struct {
+		syncutil.Mutex
+		attrs []otelAttribute
+	}
 }
 
 var _ opentracing.Tracer = &Tracer{}
@@ -114,9 +176,34 @@ var _ opentracing.Tracer = &Tracer{}
 func NewTracer() *Tracer {
 	t := &Tracer{}
 	t.noopSpan.tracer = t
+	t.resourceAttrs.attrs = []otelAttribute{{key: serviceNameAttribute, value: "cockroach"}}
 	return t
 }
 
+// SetResourceAttribute sets an attribute describing this process (for
+// example its node ID or locality), attached to all the spans exported to
+// OpenTelemetry and Jaeger collectors. Setting an existing key overwrites its
+// value.
+func (t *Tracer) SetResourceAttribute(key, value string) {
+	t.resourceAttrs.Lock()
+	defer t.resourceAttrs.Unlock()
+	// The slice is copied on write since getResourceAttributes returns it
+	// without holding the lock.
+	attrs := make([]otelAttribute, 0, len(t.resourceAttrs.attrs)+1)
+	for _, a := range t.resourceAttrs.attrs {
+		if a.key != key {
+			attrs = append(attrs, a)
+		}
+	}
+	t.resourceAttrs.attrs = append(attrs, otelAttribute{key: key, value: value})
+}
+
+func (t *Tracer) getResourceAttributes() []otelAttribute {
+	t.resourceAttrs.Lock()
+	defer t.resourceAttrs.Unlock()
+	return t.resourceAttrs.attrs
+}
+
 // Configure sets up the Tracer according to the cluster settings (and keeps
 // it updated if they change).
 func (t *Tracer) Configure(sv *settings.Values) {
@@ -125,6 +212,13 @@ func (t *Tracer) Configure(sv *settings.Values) {
 			t.setShadowTracer(createLightStepTracer(lsToken))
 		} else if zipkinAddr := zipkinCollector.Get(sv); zipkinAddr != "" {
 			t.setShadowTracer(createZipkinTracer(zipkinAddr))
+		} else if otlpAddr := otlpCollector.Get(sv); otlpAddr != "" {
+			t.setShadowTracer(createOTLPTracer(
+				otlpAddr, otlpProtocol(otlpCollectorProtocol.Get(sv)), otelSampleRate.Get(sv),
+				t.getResourceAttributes,
+			))
+		} else if jaegerAddr := jaegerCollector.Get(sv); jaegerAddr != "" {
+			t.setShadowTracer(createJaegerTracer(jaegerAddr, otelSampleRate.Get(sv), t.getResourceAttributes))
 		} else {
 			t.setShadowTracer(nil, nil)
 		}
@@ -140,6 +234,10 @@ func (t *Tracer) Configure(sv *settings.Values) {
 	enableNetTrace.SetOnChange(sv, reconfigure)
 	lightstepToken.SetOnChange(sv, reconfigure)
 	zipkinCollector.SetOnChange(sv, reconfigure)
+	otlpCollector.SetOnChange(sv, reconfigure)
+	otlpCollectorProtocol.SetOnChange(sv, reconfigure)
+	jaegerCollector.SetOnChange(sv, reconfigure)
+	otelSampleRate.SetOnChange(sv, reconfigure)
 }
 
 func (t *Tracer) useNetTrace() bool {
@@ -621,6 +719,31 @@ func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.S
 	return &sc, nil
 }
 
+// ExtractTraceParent returns a span context for the remote parent identified
+// by a W3C traceparent header value, as propagated by a client. When spans are
+// exported to an OpenTelemetry or Jaeger collector, children of the returned
+// context are part of the client's trace. Our own trace ID is derived from the
+// low 64 bits of the W3C trace ID.
+func (t *Tracer) ExtractTraceParent(traceParent string) (opentracing.SpanContext, error) {
+	otelCtx, err := parseTraceParent(traceParent)
+	if err != nil {
+		return noopSpanContext{}, err
+	}
+	sc := &spanContext{
+		spanMeta: spanMeta{
+			TraceID: binary.BigEndian.Uint64(otelCtx.traceID[8:]),
+			SpanID:  binary.BigEndian.Uint64(otelCtx.spanID[:]),
+		},
+	}
+	if shadowTr := t.getShadowTracer(); shadowTr != nil {
+		if _, ok := shadowTr.Tracer.(*otelTracer); ok {
+			sc.shadowTr = shadowTr
+			sc.shadowCtx = otelCtx
+		}
+	}
+	return sc, nil
+}
+
 // FinishSpan closes the given span (if not nil). It is a convenience wrapper
 // for span.Finish() which tolerates nil spans.
 func FinishSpan(span opentracing.Span) {